@@ -90,6 +90,10 @@ func main() {
 
 	reconciling.Configure(log)
 
+	if runSnapshotCommand(log, os.Args[1:]) {
+		return
+	}
+
 	// normal workflow, we don't do migrations anymore
 	e := &etcdCluster{}
 	err := e.parseConfigFlags()
@@ -723,13 +727,17 @@ func (e *etcdCluster) restoreDatadirFromBackupIfNeeded(ctx context.Context, log
 		return fmt.Errorf("failed to get s3 client: %w", err)
 	}
 
-	objectName := fmt.Sprintf("%s-%s", cluster.GetName(), activeRestore.Spec.BackupName)
+	objectName := activeRestore.BackupObjectName()
 	downloadedSnapshotFile := fmt.Sprintf("/tmp/%s", objectName)
 
 	if err := s3Client.FGetObject(ctx, bucketName, objectName, downloadedSnapshotFile, minio.GetObjectOptions{}); err != nil {
 		return fmt.Errorf("failed to download backup (%s/%s): %w", bucketName, objectName, err)
 	}
 
+	if err := decryptSnapshotIfNeeded(ctx, log, seedClient, activeRestore, cluster, downloadedSnapshotFile); err != nil {
+		return err
+	}
+
 	if err := os.RemoveAll(e.dataDir); err != nil {
 		return fmt.Errorf("error deleting data directory before restore (%s): %w", e.dataDir, err)
 	}
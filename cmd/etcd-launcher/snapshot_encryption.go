@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/util/backupencryption"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// encryptSnapshotCommand and decryptSnapshotCommand are used by the etcd backup
+	// jobs, which reuse the etcd-launcher image to (de)crypt snapshots in place.
+	encryptSnapshotCommand = "encrypt-snapshot"
+	decryptSnapshotCommand = "decrypt-snapshot"
+
+	// encryptionKeyEnvVar contains the encryption key for the snapshot commands.
+	encryptionKeyEnvVar = "ENCRYPTION_KEY"
+)
+
+// runSnapshotCommand handles the snapshot encryption commands. It returns false if
+// args do not contain any of them, in which case the launcher should start normally.
+func runSnapshotCommand(log *zap.SugaredLogger, args []string) bool {
+	if len(args) == 0 || (args[0] != encryptSnapshotCommand && args[0] != decryptSnapshotCommand) {
+		return false
+	}
+
+	if err := transformSnapshot(args[0], args[1:]); err != nil {
+		log.Fatalw("failed to process snapshot", "command", args[0], zap.Error(err))
+	}
+
+	return true
+}
+
+func transformSnapshot(command string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s <snapshot file>", command)
+	}
+
+	rawKey := os.Getenv(encryptionKeyEnvVar)
+	if rawKey == "" {
+		return errors.New("no encryption key given in $" + encryptionKeyEnvVar)
+	}
+
+	key, err := backupencryption.ParseKey([]byte(rawKey))
+	if err != nil {
+		return err
+	}
+
+	if command == encryptSnapshotCommand {
+		return backupencryption.EncryptFile(args[0], key)
+	}
+
+	return backupencryption.DecryptFile(args[0], key)
+}
+
+// decryptSnapshotIfNeeded decrypts a downloaded snapshot in place, if it has been
+// encrypted by the backup job. Plaintext snapshots are left untouched.
+func decryptSnapshotIfNeeded(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster, snapshotFile string) error {
+	encrypted, err := backupencryption.IsEncryptedFile(snapshotFile)
+	if err != nil {
+		return fmt.Errorf("failed to read downloaded backup: %w", err)
+	}
+	if !encrypted {
+		return nil
+	}
+
+	key, err := resources.GetEtcdRestoreEncryptionKey(ctx, restore, seedClient, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get backup encryption key: %w", err)
+	}
+	if key == nil {
+		return errors.New("backup is encrypted, but no encryption key is configured for its destination")
+	}
+
+	log.Info("decrypting backup")
+
+	if err := backupencryption.DecryptFile(snapshotFile, key); err != nil {
+		return fmt.Errorf("failed to decrypt backup: %w", err)
+	}
+
+	return nil
+}
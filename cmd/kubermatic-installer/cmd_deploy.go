@@ -31,6 +31,7 @@ import (
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/install/helm"
+	"k8c.io/kubermatic/v2/pkg/install/plan"
 	"k8c.io/kubermatic/v2/pkg/install/stack"
 	"k8c.io/kubermatic/v2/pkg/install/stack/common"
 	kubermaticmaster "k8c.io/kubermatic/v2/pkg/install/stack/kubermatic-master"
@@ -53,6 +54,13 @@ var (
 	UserClusterMinHelmTimeout = 15 * time.Minute
 )
 
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var supportedOutputs = sets.New(outputText, outputJSON)
+
 type DeployOptions struct {
 	Options
 
@@ -82,6 +90,9 @@ type DeployOptions struct {
 	MLAIncludeIap            bool
 
 	SkipCharts []string
+
+	DryRun bool
+	Output string
 }
 
 func DeployCommand(logger *logrus.Logger, versions kubermaticversion.Versions) *cobra.Command {
@@ -89,6 +100,7 @@ func DeployCommand(logger *logrus.Logger, versions kubermaticversion.Versions) *
 		HelmTimeout:        5 * time.Minute,
 		HelmBinary:         "helm",
 		SkipSeedValidation: sets.New[string](),
+		Output:             outputText,
 	}
 
 	cmd := &cobra.Command{
@@ -144,6 +156,9 @@ func DeployCommand(logger *logrus.Logger, versions kubermaticversion.Versions) *
 
 	cmd.PersistentFlags().StringSliceVar(&opt.SkipCharts, "skip-charts", nil, "skip helm chart deployment (some of cert-manager, nginx-ingress-controller, dex)")
 
+	cmd.PersistentFlags().BoolVar(&opt.DryRun, "dry-run", false, "do not change the cluster, but show the Helm releases, CRDs and migrations that would be changed/run")
+	cmd.PersistentFlags().StringVar(&opt.Output, "output", opt.Output, fmt.Sprintf("output format for the --dry-run plan (one of %v)", sets.List(supportedOutputs)))
+
 	return cmd
 }
 
@@ -166,6 +181,10 @@ func DeployFunc(logger *logrus.Logger, versions kubermaticversion.Versions, opt
 			opt.HelmTimeout = UserClusterMinHelmTimeout
 		}
 
+		if !supportedOutputs.Has(opt.Output) {
+			return fmt.Errorf("invalid --output %q given", opt.Output)
+		}
+
 		// error out early if there is no useful Helm binary
 		helmClient, err := helm.NewCLI(opt.HelmBinary, opt.Kubeconfig, opt.KubeContext, opt.HelmTimeout, logger)
 		if err != nil {
@@ -238,6 +257,14 @@ func DeployFunc(logger *logrus.Logger, versions kubermaticversion.Versions, opt
 			SkipCharts:                         opt.SkipCharts,
 		}
 
+		if opt.DryRun {
+			deployOptions.Plan = plan.New(kubermaticStack.Name())
+			deployOptions.HelmClient = plan.NewHelmClient(helmClient, deployOptions.Plan)
+
+			// render and diff every chart, even if its version and values did not change
+			deployOptions.ForceHelmReleaseUpgrade = true
+		}
+
 		// prepapre Kubernetes and Helm clients
 		ctrlConfig, err := ctrlruntimeconfig.GetConfigWithContext(opt.KubeContext)
 		if err != nil {
@@ -323,6 +350,9 @@ func DeployFunc(logger *logrus.Logger, versions kubermaticversion.Versions, opt
 		deployOptions.KubermaticConfiguration = kubermaticConfig
 		deployOptions.HelmValues = helmValues
 		deployOptions.KubeClient = kubeClient
+		if opt.DryRun {
+			deployOptions.KubeClient = plan.NewKubeClient(kubeClient, deployOptions.Plan)
+		}
 		deployOptions.Logger = subLogger
 		deployOptions.SeedsGetter = seedsGetter
 		deployOptions.SeedClientGetter = kubernetesprovider.SeedClientGetterFactory(seedKubeconfigGetter)
@@ -341,6 +371,22 @@ func DeployFunc(logger *logrus.Logger, versions kubermaticversion.Versions, opt
 
 		logger.Info("✅ Existing installation is valid.")
 
+		if opt.DryRun {
+			logger.Infof("🔍 Planning deployment of %s…", kubermaticStack.Name())
+
+			if err := kubermaticStack.Deploy(appContext, deployOptions); err != nil {
+				return err
+			}
+
+			logger.Info("✅ Plan completed, no changes were made to the cluster.")
+
+			if opt.Output == outputJSON {
+				return deployOptions.Plan.WriteJSON(cmd.OutOrStdout())
+			}
+
+			return deployOptions.Plan.WriteText(cmd.OutOrStdout())
+		}
+
 		logger.Infof("🛫 Deploying %s…", kubermaticStack.Name())
 
 		if err := kubermaticStack.Deploy(appContext, deployOptions); err != nil {
@@ -34,11 +34,13 @@ import (
 	"k8c.io/kubermatic/v2/pkg/util/cli"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 	addonmutation "k8c.io/kubermatic/v2/pkg/webhook/addon/mutation"
+	addonvalidation "k8c.io/kubermatic/v2/pkg/webhook/addon/validation"
 	applicationdefinitionmutation "k8c.io/kubermatic/v2/pkg/webhook/application/applicationdefinition/mutation"
 	applicationdefinitionvalidation "k8c.io/kubermatic/v2/pkg/webhook/application/applicationdefinition/validation"
 	clustermutation "k8c.io/kubermatic/v2/pkg/webhook/cluster/mutation"
 	clustervalidation "k8c.io/kubermatic/v2/pkg/webhook/cluster/validation"
 	clustertemplatevalidation "k8c.io/kubermatic/v2/pkg/webhook/clustertemplate/validation"
+	etcdrestorevalidation "k8c.io/kubermatic/v2/pkg/webhook/etcdrestore/validation"
 	externalclustermutation "k8c.io/kubermatic/v2/pkg/webhook/externalcluster/mutation"
 	groupprojectbinding "k8c.io/kubermatic/v2/pkg/webhook/groupprojectbinding/validation"
 	ipampoolvalidation "k8c.io/kubermatic/v2/pkg/webhook/ipampool/validation"
@@ -180,6 +182,19 @@ func main() {
 
 	addonmutation.NewAdmissionHandler(seedGetter, seedClientGetter).SetupWebhookWithManager(mgr)
 
+	addonValidator := addonvalidation.NewValidator(seedGetter, seedClientGetter)
+	if err := builder.WebhookManagedBy(mgr).For(&kubermaticv1.Addon{}).WithValidator(addonValidator).Complete(); err != nil {
+		log.Fatalw("Failed to setup addon validation webhook", zap.Error(err))
+	}
+
+	// /////////////////////////////////////////
+	// setup EtcdRestore webhook
+
+	etcdRestoreValidator := etcdrestorevalidation.NewValidator(seedGetter, seedClientGetter)
+	if err := builder.WebhookManagedBy(mgr).For(&kubermaticv1.EtcdRestore{}).WithValidator(etcdRestoreValidator).Complete(); err != nil {
+		log.Fatalw("Failed to setup EtcdRestore validation webhook", zap.Error(err))
+	}
+
 	// /////////////////////////////////////////
 	// setup MLAAdminSetting webhooks
 
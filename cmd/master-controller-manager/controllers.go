@@ -23,6 +23,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	addonconfigsynchronizer "k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/addon-config-synchronizer"
 	applicationdefinitionsynchronizer "k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/application-definition-synchronizer"
 	applicationsecretsynchronizer "k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/application-secret-synchronizer"
 	clustertemplatesynchronizer "k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/cluster-template-synchronizer"
@@ -73,6 +74,7 @@ func createAllControllers(ctrlCtx *controllerContext) error {
 	projectSynchronizerFactory := projectSynchronizerFactoryCreator(ctrlCtx)
 	applicationdefinitionsynchronizerFactory := applicationDefinitionSynchronizerFactoryCreator(ctrlCtx)
 	applicationSecretSynchronizerFactor := applicationSecretSynchronizerFactoryCreator(ctrlCtx)
+	addonConfigSynchronizerFactory := addonConfigSynchronizerFactoryCreator(ctrlCtx)
 	presetSynchronizerFactory := presetSynchronizerFactoryCreator(ctrlCtx)
 	resourceQuotaSynchronizerFactory := resourceQuotaSynchronizerFactoryCreator(ctrlCtx)
 	resourceQuotaControllerFactory := resourceQuotaControllerFactoryCreator(ctrlCtx)
@@ -94,6 +96,7 @@ func createAllControllers(ctrlCtx *controllerContext) error {
 		projectSynchronizerFactory,
 		applicationdefinitionsynchronizerFactory,
 		applicationSecretSynchronizerFactor,
+		addonConfigSynchronizerFactory,
 		presetSynchronizerFactory,
 		resourceQuotaSynchronizerFactory,
 		resourceQuotaControllerFactory,
@@ -264,6 +267,17 @@ func applicationDefinitionSynchronizerFactoryCreator(ctrlCtx *controllerContext)
 	}
 }
 
+func addonConfigSynchronizerFactoryCreator(ctrlCtx *controllerContext) seedcontrollerlifecycle.ControllerFactory {
+	return func(ctx context.Context, masterMgr manager.Manager, seedManagerMap map[string]manager.Manager) (string, error) {
+		return addonconfigsynchronizer.ControllerName, addonconfigsynchronizer.Add(
+			masterMgr,
+			seedManagerMap,
+			ctrlCtx.log,
+			ctrlCtx.workerCount,
+		)
+	}
+}
+
 func applicationSecretSynchronizerFactoryCreator(ctrlCtx *controllerContext) seedcontrollerlifecycle.ControllerFactory {
 	return func(ctx context.Context, masterMgr manager.Manager, seedManagerMap map[string]manager.Manager) (string, error) {
 		return applicationsecretsynchronizer.ControllerName, applicationsecretsynchronizer.Add(
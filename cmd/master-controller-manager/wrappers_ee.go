@@ -32,6 +32,7 @@ import (
 	resourcequotalabelownercontroller "k8c.io/kubermatic/v2/pkg/ee/resource-quota/label-owner-controller"
 	resourcequotamastercontroller "k8c.io/kubermatic/v2/pkg/ee/resource-quota/master-controller"
 	resourcequotasynchronizer "k8c.io/kubermatic/v2/pkg/ee/resource-quota/resource-quota-synchronizer"
+	resourcequotathresholdcontroller "k8c.io/kubermatic/v2/pkg/ee/resource-quota/threshold-controller"
 	"k8c.io/kubermatic/v2/pkg/provider"
 
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -63,6 +64,10 @@ func setupControllers(ctrlCtx *controllerContext) error {
 		return fmt.Errorf("failed to create default project resource quota controller: %w", err)
 	}
 
+	if err := resourcequotathresholdcontroller.Add(ctrlCtx.mgr, ctrlCtx.log, 1); err != nil {
+		return fmt.Errorf("failed to create resource quota threshold controller: %w", err)
+	}
+
 	return nil
 }
 
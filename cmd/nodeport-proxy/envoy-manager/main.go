@@ -37,6 +37,7 @@ func main() {
 
 	srv := Server{}
 	ctrlOpts := envoymanager.Options{}
+	var maxConnections, maxPendingConnections, connectionsPerSecond, connectionBurst uint
 	flag.StringVar(&srv.ListenAddress, "listen-address", ":8001", "Address to serve on")
 	flag.StringVar(&ctrlOpts.EnvoyNodeName, "envoy-node-name", "kube", "Name of the envoy nodes to apply the config to via xds.")
 	flag.IntVar(&ctrlOpts.EnvoyAdminPort, "envoy-admin-port", 9001, "Envoys admin port")
@@ -45,8 +46,19 @@ func main() {
 	flag.IntVar(&ctrlOpts.EnvoyTunnelingListenerPort, "envoy-tunneling-port", 0, "Port used for HTTP/2 CONNECT termination.")
 	flag.StringVar(&ctrlOpts.Namespace, "namespace", "", "The namespace we should use for pods and services. Leave empty for all namespaces.")
 	flag.StringVar(&ctrlOpts.ExposeAnnotationKey, "expose-annotation-key", nodeportproxy.DefaultExposeAnnotationKey, "The annotation key used to determine if a service should be exposed")
+	flag.UintVar(&maxConnections, "max-connections", 0, "Default maximum number of concurrent connections per exposed service port. 0 keeps Envoy's default of 1024.")
+	flag.UintVar(&maxPendingConnections, "max-pending-connections", 0, "Default maximum number of pending upstream connections per exposed service port. 0 keeps Envoy's default of 1024.")
+	flag.UintVar(&connectionsPerSecond, "connections-per-second", 0, "Default number of new connections per second accepted per exposed service port. 0 means no limit.")
+	flag.UintVar(&connectionBurst, "connection-burst", 0, "Default number of new connections accepted at once on top of -connections-per-second. Defaults to -connections-per-second.")
 	flag.Parse()
 
+	ctrlOpts.DefaultRateLimits = envoymanager.RateLimits{
+		MaxConnections:        uint32(maxConnections),
+		MaxPendingConnections: uint32(maxPendingConnections),
+		ConnectionsPerSecond:  uint32(connectionsPerSecond),
+		ConnectionBurst:       uint32(connectionBurst),
+	}
+
 	// setup signal handler
 	ctx := signals.SetupSignalHandler()
 
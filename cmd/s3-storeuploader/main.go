@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
 	"k8c.io/kubermatic/v2/pkg/storeuploader"
@@ -43,6 +45,7 @@ type options struct {
 	Prefix       string
 	File         string
 	MaxRevisions int
+	Retention    kubermaticv1.EtcdBackupRetention
 
 	LogOptions log.Options
 }
@@ -65,7 +68,7 @@ func main() {
 		Short:         "Helper tool to backup files to S3 and maintain a given number of revisions",
 		Version:       "v0.2.0",
 		SilenceErrors: true,
-		PersistentPreRunE: func(_ *cobra.Command, _ []string) (err error) {
+		PersistentPreRunE: func(c *cobra.Command, _ []string) (err error) {
 			if opt.AccessKeyID == "" {
 				opt.AccessKeyID = os.Getenv("ACCESS_KEY_ID")
 			}
@@ -74,6 +77,10 @@ func main() {
 				opt.SecretAccessKey = os.Getenv("SECRET_ACCESS_KEY")
 			}
 
+			if err := retentionFromEnv(c, &opt.Retention); err != nil {
+				return err
+			}
+
 			logger = log.New(opt.LogOptions.Debug, opt.LogOptions.Format).Sugar()
 			uploader, err = getUploaderFromCtx(logger, opt)
 			return
@@ -90,6 +97,10 @@ func main() {
 	pFlags.BoolVar(&opt.Secure, "secure", opt.Secure, "Enable TLS validation")
 	pFlags.BoolVar(&opt.CreateBucket, "create-bucket", opt.CreateBucket, "Create the bucket if it does not exist yet")
 	pFlags.IntVar(&opt.MaxRevisions, "max-revisions", opt.MaxRevisions, "Maximum number of revisions of the file to keep in S3. Older ones will be deleted")
+	pFlags.IntVar(&opt.Retention.Hourly, "keep-hourly", 0, "Number of hours for which the newest revision is kept ($BACKUP_KEEP_HOURLY). If any --keep-* flag is set, --max-revisions is ignored")
+	pFlags.IntVar(&opt.Retention.Daily, "keep-daily", 0, "Number of days for which the newest revision is kept ($BACKUP_KEEP_DAILY)")
+	pFlags.IntVar(&opt.Retention.Weekly, "keep-weekly", 0, "Number of weeks for which the newest revision is kept ($BACKUP_KEEP_WEEKLY)")
+	pFlags.IntVar(&opt.Retention.Monthly, "keep-monthly", 0, "Number of months for which the newest revision is kept ($BACKUP_KEEP_MONTHLY)")
 	pFlags.StringVar(&opt.CABundle, "ca-bundle", opt.CABundle, "Filename of the CA bundle to use (if not given, default system certificates are used)")
 	opt.LogOptions.AddPFlags(pFlags)
 
@@ -104,8 +115,11 @@ func main() {
 
 		&cobra.Command{
 			Use:   "delete-old-revisions",
-			Short: "Deletes backups which are older than max-revisions",
+			Short: "Deletes backups which are older than max-revisions or not retained by any --keep-* tier",
 			RunE: func(c *cobra.Command, args []string) error {
+				if opt.Retention.Total() > 0 {
+					return uploader.DeleteExpiredBackups(c.Context(), opt.Bucket, opt.Prefix, opt.Retention)
+				}
 				return uploader.DeleteOldBackups(c.Context(), opt.Bucket, opt.Prefix, opt.MaxRevisions)
 			},
 		},
@@ -124,6 +138,38 @@ func main() {
 	}
 }
 
+// retentionFromEnv sets the retention tiers that were not given as flags from the environment
+// variables the etcd backup controller sets for EtcdBackupConfigs with a retention policy, so that
+// the default store container honours the policy without any changes to its command.
+func retentionFromEnv(c *cobra.Command, retention *kubermaticv1.EtcdBackupRetention) error {
+	tiers := []struct {
+		flag  string
+		env   string
+		value *int
+	}{
+		{flag: "keep-hourly", env: "BACKUP_KEEP_HOURLY", value: &retention.Hourly},
+		{flag: "keep-daily", env: "BACKUP_KEEP_DAILY", value: &retention.Daily},
+		{flag: "keep-weekly", env: "BACKUP_KEEP_WEEKLY", value: &retention.Weekly},
+		{flag: "keep-monthly", env: "BACKUP_KEEP_MONTHLY", value: &retention.Monthly},
+	}
+
+	for _, tier := range tiers {
+		env := os.Getenv(tier.env)
+		if env == "" || c.Flags().Changed(tier.flag) {
+			continue
+		}
+
+		value, err := strconv.Atoi(env)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for $%s: %w", env, tier.env, err)
+		}
+
+		*tier.value = value
+	}
+
+	return nil
+}
+
 func getUploaderFromCtx(log *zap.SugaredLogger, opt options) (*storeuploader.StoreUploader, error) {
 	var rootCAs *x509.CertPool
 
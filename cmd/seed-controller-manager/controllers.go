@@ -50,6 +50,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/pvwatcher"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/seedresourcesuptodatecondition"
 	updatecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/update-controller"
+	upgradeplancontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/upgrade-plan-controller"
 	"k8c.io/kubermatic/v2/pkg/features"
 )
 
@@ -60,6 +61,7 @@ var AllControllers = map[string]controllerCreator{
 	kubernetescontroller.ControllerName:                     createKubernetesController,
 	autoupdatecontroller.ControllerName:                     createAutoUpdateController,
 	updatecontroller.ControllerName:                         createUpdateController,
+	upgradeplancontroller.ControllerName:                    createUpgradePlanController,
 	addon.ControllerName:                                    createAddonController,
 	addoninstaller.ControllerName:                           createAddonInstallerController,
 	etcdbackupcontroller.ControllerName:                     createEtcdBackupController,
@@ -181,12 +183,15 @@ func createProjectController(ctrlCtx *controllerContext) error {
 }
 
 func createEtcdBackupController(ctrlCtx *controllerContext) error {
+	etcdbackupcontroller.MustRegisterMetrics(prometheus.DefaultRegisterer)
+
 	return etcdbackupcontroller.Add(
 		ctrlCtx.mgr,
 		ctrlCtx.log,
 		ctrlCtx.runOptions.workerCount,
 		ctrlCtx.runOptions.workerName,
 		ctrlCtx.runOptions.backupContainerImage,
+		ctrlCtx.runOptions.etcdLauncherImage,
 		ctrlCtx.versions,
 		ctrlCtx.runOptions.caBundle,
 		ctrlCtx.seedGetter,
@@ -221,6 +226,7 @@ func createEtcdRestoreController(ctrlCtx *controllerContext) error {
 		ctrlCtx.runOptions.workerName,
 		ctrlCtx.versions,
 		ctrlCtx.seedGetter,
+		ctrlCtx.clientProvider,
 	)
 }
 
@@ -267,6 +273,15 @@ func createUpdateController(ctrlCtx *controllerContext) error {
 	)
 }
 
+func createUpgradePlanController(ctrlCtx *controllerContext) error {
+	return upgradeplancontroller.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.log,
+	)
+}
+
 func createClusterPhaseController(ctrlCtx *controllerContext) error {
 	return clusterphasecontroller.Add(
 		ctrlCtx.mgr,
@@ -377,6 +392,7 @@ func createMLAController(ctrlCtx *controllerContext) error {
 		ctrlCtx.runOptions.workerCount,
 		ctrlCtx.runOptions.workerName,
 		ctrlCtx.versions,
+		ctrlCtx.seedGetter,
 		ctrlCtx.runOptions.mlaNamespace,
 		ctrlCtx.runOptions.grafanaURL,
 		ctrlCtx.runOptions.grafanaHeaderName,
@@ -438,6 +454,9 @@ func createEncryptionAtRestController(ctrlCtx *controllerContext) error {
 }
 
 func createIPAMController(ctrlCtx *controllerContext) error {
+	// Use the API reader, as the cache-backed reader only contains data while being the leader.
+	ipam.MustRegisterMetrics(prometheus.DefaultRegisterer, ctrlCtx.mgr.GetAPIReader())
+
 	return ipam.Add(
 		ctrlCtx.mgr,
 		ctrlCtx.log,
@@ -56,6 +56,8 @@ import (
 	ctrlruntimelog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 const (
@@ -155,6 +157,12 @@ func main() {
 	if err := appskubermaticv1.AddToScheme(mgr.GetScheme()); err != nil {
 		log.Fatalw("Failed to register scheme", zap.Stringer("api", appskubermaticv1.SchemeGroupVersion), zap.Error(err))
 	}
+	if err := gatewayapiv1alpha2.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Fatalw("Failed to register scheme", zap.Stringer("api", gatewayapiv1alpha2.SchemeGroupVersion), zap.Error(err))
+	}
+	if err := gatewayapiv1beta1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Fatalw("Failed to register scheme", zap.Stringer("api", gatewayapiv1beta1.SchemeGroupVersion), zap.Error(err))
+	}
 
 	// Check if the CRD for the VerticalPodAutoscaler is registered by allocating an informer
 	if err := mgr.GetAPIReader().List(rootCtx, &autoscalingv1.VerticalPodAutoscalerList{}); err != nil {
@@ -233,6 +241,8 @@ Please install the VerticalPodAutoscaler according to the documentation: https:/
 	collectors.MustRegisterClusterCollector(prometheus.DefaultRegisterer, ctrlCtx.mgr.GetAPIReader())
 	log.Debug("Starting addons collector")
 	collectors.MustRegisterAddonCollector(prometheus.DefaultRegisterer, ctrlCtx.mgr.GetAPIReader())
+	log.Debug("Starting compliance reports collector")
+	collectors.MustRegisterComplianceReportCollector(prometheus.DefaultRegisterer, ctrlCtx.mgr.GetAPIReader())
 	// The canonical source of projects is the master cluster, but since they are replicated onto
 	// seeds, we start the project collctor on seed clusters as well, just for convenience for the admin.
 	log.Debug("Starting projects collector")
@@ -31,10 +31,13 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/applications"
 	userclustercontrollermanager "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager"
+	applicationdriftcontroller "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/application-drift-controller"
 	applicationinstallationcontroller "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/application-installation-controller"
 	ccmcsimigrator "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/ccm-csi-migrator"
 	clusterrolelabeler "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/cluster-role-labeler"
+	compliancecontroller "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/compliance-controller"
 	constraintsyncer "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/constraint-syncer"
+	deprecatedapicontroller "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/deprecated-api-controller"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/flatcar"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/ipam"
 	kvvmieviction "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/kubevirt-vmi-eviction"
@@ -378,6 +381,16 @@ func main() {
 	}
 	log.Info("Registered node-version controller")
 
+	if err := deprecatedapicontroller.Add(rootCtx, log, seedMgr, mgr, runOp.clusterName, isPausedChecker); err != nil {
+		log.Fatalw("Failed to register deprecated-api controller", zap.Error(err))
+	}
+	log.Info("Registered deprecated-api controller")
+
+	if err := compliancecontroller.Add(rootCtx, log, seedMgr, mgr, runOp.clusterName, runOp.overwriteRegistry, isPausedChecker); err != nil {
+		log.Fatalw("Failed to register compliance controller", zap.Error(err))
+	}
+	log.Info("Registered compliance controller")
+
 	if err := clusterrolelabeler.Add(rootCtx, log, mgr, isPausedChecker); err != nil {
 		log.Fatalw("Failed to register clusterrolelabeler controller", zap.Error(err))
 	}
@@ -411,11 +424,17 @@ func main() {
 		log.Info("Registered constraintsyncer controller")
 	}
 
-	if err := applicationinstallationcontroller.Add(rootCtx, log, seedMgr, mgr, isPausedChecker, &applications.ApplicationManager{ApplicationCache: runOp.applicationCache, Kubeconfig: kubeconfigFlag.Value.String(), SecretNamespace: runOp.namespace}); err != nil {
+	appManager := &applications.ApplicationManager{ApplicationCache: runOp.applicationCache, Kubeconfig: kubeconfigFlag.Value.String(), SecretNamespace: runOp.namespace}
+	if err := applicationinstallationcontroller.Add(rootCtx, log, seedMgr, mgr, isPausedChecker, appManager); err != nil {
 		log.Fatalw("Failed to add user Application Installation controller to mgr", zap.Error(err))
 	}
 	log.Info("Registered Application Installation controller")
 
+	if err := applicationdriftcontroller.Add(rootCtx, log, seedMgr, mgr, isPausedChecker, appManager); err != nil {
+		log.Fatalw("Failed to add user Application Drift controller to mgr", zap.Error(err))
+	}
+	log.Info("Registered Application Drift controller")
+
 	if err := addResourceUsageController(log, seedMgr, mgr, runOp.clusterName, caBundle, isPausedChecker); err != nil {
 		log.Fatalw("Failed to add user Resource Usage controller to mgr", zap.Error(err))
 	}
@@ -34,7 +34,10 @@ import (
 	applicationinstallationmutation "k8c.io/kubermatic/v2/pkg/webhook/application/applicationinstallation/mutation"
 	applicationinstallationvalidation "k8c.io/kubermatic/v2/pkg/webhook/application/applicationinstallation/validation"
 	machinevalidation "k8c.io/kubermatic/v2/pkg/webhook/machine/validation"
+	persistentvolumevalidation "k8c.io/kubermatic/v2/pkg/webhook/persistentvolume/validation"
+	servicevalidation "k8c.io/kubermatic/v2/pkg/webhook/service/validation"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	ctrlruntime "sigs.k8s.io/controller-runtime"
@@ -127,7 +130,7 @@ func main() {
 	applicationinstallationmutation.NewAdmissionHandler().SetupWebhookWithManager(seedMgr)
 
 	// Setup the validation admission handler for ApplicationInstallation CRDs in seed manager.
-	applicationinstallationvalidation.NewAdmissionHandler(seedMgr.GetClient()).SetupWebhookWithManager(seedMgr)
+	applicationinstallationvalidation.NewAdmissionHandler(seedMgr.GetClient(), userMgr.GetAPIReader()).SetupWebhookWithManager(seedMgr)
 
 	// Setup Machine Webhook in user manager.
 	machineValidator, err := machinevalidation.NewValidator(seedMgr.GetClient(), userMgr.GetClient(), log, options.caBundle, options.projectID)
@@ -138,6 +141,27 @@ func main() {
 		log.Fatalw("Failed to setup Machine validation webhook", zap.Error(err))
 	}
 
+	// Setup MachineDeployment Webhook in user manager.
+	machineDeploymentValidator, err := machinevalidation.NewMachineDeploymentValidator(seedMgr.GetClient(), log, options.projectID)
+	if err != nil {
+		log.Fatalw("Failed to setup MachineDeployment validator", zap.Error(err))
+	}
+	if err := builder.WebhookManagedBy(userMgr).For(&clusterv1alpha1.MachineDeployment{}).WithValidator(machineDeploymentValidator).Complete(); err != nil {
+		log.Fatalw("Failed to setup MachineDeployment validation webhook", zap.Error(err))
+	}
+
+	// Setup Service and PersistentVolume Webhooks in user manager. Both count the existing
+	// objects live, so they use uncached readers to not watch all of them.
+	serviceValidator := servicevalidation.NewValidator(seedMgr.GetAPIReader(), userMgr.GetAPIReader(), log, options.clusterName)
+	if err := builder.WebhookManagedBy(userMgr).For(&corev1.Service{}).WithValidator(serviceValidator).Complete(); err != nil {
+		log.Fatalw("Failed to setup Service validation webhook", zap.Error(err))
+	}
+
+	persistentVolumeValidator := persistentvolumevalidation.NewValidator(seedMgr.GetAPIReader(), userMgr.GetAPIReader(), log, options.clusterName)
+	if err := builder.WebhookManagedBy(userMgr).For(&corev1.PersistentVolume{}).WithValidator(persistentVolumeValidator).Complete(); err != nil {
+		log.Fatalw("Failed to setup PersistentVolume validation webhook", zap.Error(err))
+	}
+
 	// /////////////////////////////////////////
 	// Start managers
 
@@ -35,6 +35,7 @@ type appOptions struct {
 	log         kubermaticlog.Options
 	caBundle    *certificates.CABundle
 	projectID   string
+	clusterName string
 }
 
 func initApplicationOptions() (appOptions, error) {
@@ -52,9 +53,11 @@ func initApplicationOptions() (appOptions, error) {
 
 	var caBundleFile string
 	var projectID string
+	var clusterName string
 
 	flag.StringVar(&caBundleFile, "ca-bundle", "", "File containing the PEM-encoded CA bundle for all userclusters")
 	flag.StringVar(&projectID, "project-id", "", "Project ID in which cluster the webhook is running in")
+	flag.StringVar(&clusterName, "cluster-name", "", "Name of the cluster the webhook is running for")
 
 	flag.Parse()
 
@@ -64,6 +67,7 @@ func initApplicationOptions() (appOptions, error) {
 	}
 	c.caBundle = caBundle
 	c.projectID = projectID
+	c.clusterName = clusterName
 
 	if err := c.userWebhook.Validate(); err != nil {
 		return c, fmt.Errorf("invalid user cluster webhook configuration: %w", err)
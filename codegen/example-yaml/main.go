@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -234,6 +235,7 @@ func createExampleSeed(config *kubermaticv1.KubermaticConfiguration) *kubermatic
 						SourceRanges: []kubermaticv1.CIDR{},
 					},
 				},
+				RateLimits: &kubermaticv1.NodeportProxyRateLimits{},
 			},
 			Metering: &kubermaticv1.MeteringConfiguration{
 				Enabled:          false,
@@ -282,6 +284,7 @@ func createExampleKubermaticConfiguration() *kubermaticv1.KubermaticConfiguratio
 				BackupStoreContainer:   defaulting.DefaultBackupStoreContainer,
 				BackupCleanupContainer: defaulting.DefaultBackupCleanupContainer,
 				BackupDeleteContainer:  defaulting.DefaultNewBackupDeleteContainer,
+				BackupVerifyContainer:  defaulting.DefaultBackupVerifyContainer,
 			},
 		},
 	}
@@ -380,8 +383,29 @@ func createExampleApplicationDefinition() *appskubermaticv1.ApplicationDefinitio
 									},
 								},
 							},
+							OCI: &appskubermaticv1.OCISource{
+								URL:    "oci://localhost:5000/myrepo/my-app",
+								Tag:    "v1.2.3",
+								Digest: "sha256:8061ceb738db42fe82b4c305b7aa5459d926d03e8061ceb738db42fe82b4c305",
+								Path:   "manifests",
+								Credentials: &appskubermaticv1.HelmCredentials{
+									RegistryConfigFile: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "<<secret-name>>"},
+										Key:                  ".dockerconfigjson",
+										Optional:             pointer.Bool(false),
+									},
+								},
+								Verify: &appskubermaticv1.OCIVerification{
+									PublicKey: corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "<<secret-name>>"},
+										Key:                  "cosign.pub",
+										Optional:             pointer.Bool(false),
+									},
+								},
+							},
 						},
 					},
+					ValuesSchema: &runtime.RawExtension{Raw: []byte(`{"type": "object", "properties": {"commonLabels": {"type": "object", "additionalProperties": {"type": "string"}}}}`)},
 				},
 			},
 		},
@@ -408,7 +432,10 @@ func createExampleApplicationInstallation() *appskubermaticv1.ApplicationInstall
 				Name:    "apache",
 				Version: "1.2.3",
 			},
-			Values: runtime.RawExtension{Raw: []byte(`{ "commonLabels": {"owner": "somebody"}}`)},
+			Values:         runtime.RawExtension{Raw: []byte(`{ "commonLabels": {"owner": "somebody"}}`)},
+			DependsOn:      []string{"cert-manager"},
+			AutoRollback:   &appskubermaticv1.AutoRollbackPolicy{AfterFailures: 3},
+			DriftDetection: &appskubermaticv1.DriftDetection{Policy: appskubermaticv1.DriftPolicyReport, Interval: metav1.Duration{Duration: 10 * time.Minute}},
 		},
 	}
 }
@@ -45,12 +45,13 @@ annotation="kubermatic.k8c.io/location"
 locationMap='{
   "applicationdefinitions.apps.kubermatic.k8c.io": "master,seed",
   "applicationinstallations.apps.kubermatic.k8c.io": "usercluster",
-  "addonconfigs.kubermatic.k8c.io": "master",
+  "addonconfigs.kubermatic.k8c.io": "master,seed",
   "addons.kubermatic.k8c.io": "master,seed",
   "admissionplugins.kubermatic.k8c.io": "master",
   "alertmanagers.kubermatic.k8c.io": "master,seed",
   "allowedregistries.kubermatic.k8c.io": "master",
   "clusters.kubermatic.k8c.io": "master,seed",
+  "compliancereports.kubermatic.k8c.io": "master,seed",
   "clustertemplateinstances.kubermatic.k8c.io": "master,seed",
   "clustertemplates.kubermatic.k8c.io": "master,seed",
   "constraints.kubermatic.k8c.io": "master,seed",
@@ -70,6 +71,7 @@ locationMap='{
   "resourcequotas.kubermatic.k8c.io": "master,seed",
   "rulegroups.kubermatic.k8c.io": "master,seed",
   "seeds.kubermatic.k8c.io": "master,seed",
+  "upgradeplans.kubermatic.k8c.io": "master,seed",
   "userprojectbindings.kubermatic.k8c.io": "master,seed",
   "usersshkeys.kubermatic.k8c.io": "master,seed",
   "users.kubermatic.k8c.io": "master,seed"
@@ -63,7 +63,10 @@ type TemplateData struct {
 	DatacenterName string
 	Cluster        ClusterData
 	Credentials    Credentials
-	Variables      map[string]interface{}
+	// Variables of the Addon. If the AddonConfig of the addon declares a variables schema, missing
+	// variables are defaulted and the variables are validated and typed according to the schema,
+	// i.e. integers are int64 instead of float64.
+	Variables map[string]interface{}
 }
 
 func NewTemplateData(
@@ -120,11 +123,17 @@ func NewTemplateData(
 	if ipamAllocations != nil {
 		ipamAllocationsData = make(map[string]IPAMAllocation, len(ipamAllocations.Items))
 		for _, ipamAllocation := range ipamAllocations.Items {
-			ipamAllocationsData[ipamAllocation.Name] = IPAMAllocation{
-				Type:      ipamAllocation.Spec.Type,
-				CIDR:      ipamAllocation.Spec.CIDR,
-				Addresses: ipamAllocation.Spec.Addresses,
+			ipamAllocationData := IPAMAllocation{
+				Type:         ipamAllocation.Spec.Type,
+				CIDR:         ipamAllocation.Spec.CIDR,
+				Addresses:    ipamAllocation.Spec.Addresses,
+				Reservations: ipamAllocation.Spec.Reservations,
 			}
+			if ipamAllocation.Spec.DualStack != nil {
+				ipamAllocationData.DualStackCIDR = ipamAllocation.Spec.DualStack.CIDR
+				ipamAllocationData.DualStackAddresses = ipamAllocation.Spec.DualStack.Addresses
+			}
+			ipamAllocationsData[ipamAllocation.Name] = ipamAllocationData
 		}
 	}
 
@@ -256,6 +265,13 @@ type IPAMAllocation struct {
 	Type      kubermaticv1.IPAMPoolAllocationType
 	CIDR      kubermaticv1.SubnetCIDR
 	Addresses []string
+	// DualStackCIDR and DualStackAddresses are the allocation
+	// from the second IP family of a dual-stack IPAM pool.
+	DualStackCIDR      kubermaticv1.SubnetCIDR
+	DualStackAddresses []string
+	// Reservations are the static addresses reserved for the cluster, keyed by
+	// reservation name. They are not part of CIDR or Addresses.
+	Reservations map[string]string
 }
 
 type CNIPlugin struct {
@@ -76,8 +76,21 @@ func TestNewTemplateData(t *testing.T) {
 					Name: "ipam-pool-2",
 				},
 				Spec: kubermaticv1.IPAMAllocationSpec{
-					Type:      "range",
-					Addresses: []string{"192.168.0.1-192.168.0.8", "192.168.0.10-192.168.0.17"},
+					Type:         "range",
+					Addresses:    []string{"192.168.0.1-192.168.0.8", "192.168.0.10-192.168.0.17"},
+					Reservations: map[string]string{"ingress-vip": "192.168.0.9"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ipam-pool-3",
+				},
+				Spec: kubermaticv1.IPAMAllocationSpec{
+					Type: "prefix",
+					CIDR: "192.168.1.0/28",
+					DualStack: &kubermaticv1.IPAMAllocationDualStack{
+						CIDR: "fd00::/120",
+					},
 				},
 			},
 		},
@@ -100,8 +113,14 @@ func TestNewTemplateData(t *testing.T) {
 			CIDR: "192.168.0.1/28",
 		},
 		"ipam-pool-2": {
-			Type:      "range",
-			Addresses: []string{"192.168.0.1-192.168.0.8", "192.168.0.10-192.168.0.17"},
+			Type:         "range",
+			Addresses:    []string{"192.168.0.1-192.168.0.8", "192.168.0.10-192.168.0.17"},
+			Reservations: map[string]string{"ingress-vip": "192.168.0.9"},
+		},
+		"ipam-pool-3": {
+			Type:          "prefix",
+			CIDR:          "192.168.1.0/28",
+			DualStackCIDR: "fd00::/120",
 		},
 	}, templateData.Cluster.Network.IPAMAllocations)
 }
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"k8c.io/kubermatic/v2/pkg/validation/openapi"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ParseVariables decodes the variables of an Addon. Empty variables result in an empty map.
+func ParseVariables(raw *runtime.RawExtension) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	if raw != nil && len(raw.Raw) > 0 {
+		if err := json.Unmarshal(raw.Raw, &variables); err != nil {
+			return nil, fmt.Errorf("failed to decode variables: %w", err)
+		}
+	}
+
+	return variables, nil
+}
+
+func parseVariablesSchema(schema *runtime.RawExtension) (*apiextensionsv1.JSONSchemaProps, error) {
+	props := &apiextensionsv1.JSONSchemaProps{}
+	if err := json.Unmarshal(schema.Raw, props); err != nil {
+		return nil, fmt.Errorf("failed to decode variables schema: %w", err)
+	}
+
+	return props, nil
+}
+
+// DefaultVariables sets all variables that are missing but have a default value in the given
+// OpenAPI v3 schema. Defaults are applied recursively to nested objects and to the items of arrays.
+func DefaultVariables(schema *runtime.RawExtension, variables map[string]interface{}) error {
+	if schema == nil || len(schema.Raw) == 0 {
+		return nil
+	}
+
+	props, err := parseVariablesSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	return applyDefaults(props, variables)
+}
+
+func applyDefaults(schema *apiextensionsv1.JSONSchemaProps, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, prop := range schema.Properties {
+			prop := prop
+
+			if _, exists := v[name]; !exists {
+				if prop.Default == nil {
+					continue
+				}
+
+				var defaultValue interface{}
+				if err := json.Unmarshal(prop.Default.Raw, &defaultValue); err != nil {
+					return fmt.Errorf("failed to decode default value of %q: %w", name, err)
+				}
+				v[name] = defaultValue
+			}
+
+			if err := applyDefaults(&prop, v[name]); err != nil {
+				return err
+			}
+		}
+
+	case []interface{}:
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return nil
+		}
+
+		for _, item := range v {
+			if err := applyDefaults(schema.Items.Schema, item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateVariables validates the variables against the given OpenAPI v3 schema.
+func ValidateVariables(schema *runtime.RawExtension, variables map[string]interface{}, f *field.Path) field.ErrorList {
+	if schema == nil || len(schema.Raw) == 0 {
+		return nil
+	}
+
+	validator, err := openapi.NewValidatorForSchema(schema.Raw)
+	if err != nil {
+		return field.ErrorList{field.InternalError(f, fmt.Errorf("invalid variables schema in AddonConfig: %w", err))}
+	}
+
+	// the validator does not return errors in a stable order.
+	errs := validation.ValidateCustomResource(f, variables, validator)
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+
+	return errs
+}
+
+// ProcessVariables defaults and validates the variables according to the given OpenAPI v3 schema
+// and converts them to the types declared in the schema. As JSON does not distinguish between
+// integers and floats, variables of type integer are converted to int64 so that templates do not
+// render them as floats. Without a schema, the variables are returned unchanged.
+func ProcessVariables(schema *runtime.RawExtension, variables map[string]interface{}) (map[string]interface{}, error) {
+	if schema == nil || len(schema.Raw) == 0 {
+		return variables, nil
+	}
+
+	if err := DefaultVariables(schema, variables); err != nil {
+		return nil, err
+	}
+
+	if errs := ValidateVariables(schema, variables, field.NewPath("spec", "variables")); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+
+	props, err := parseVariablesSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTypes(props, variables).(map[string]interface{}), nil
+}
+
+func convertTypes(schema *apiextensionsv1.JSONSchemaProps, value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if schema.Type == "integer" && v == math.Trunc(v) {
+			return int64(v)
+		}
+
+	case map[string]interface{}:
+		for name, item := range v {
+			if prop, ok := schema.Properties[name]; ok {
+				v[name] = convertTypes(&prop, item)
+			} else if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+				v[name] = convertTypes(schema.AdditionalProperties.Schema, item)
+			}
+		}
+
+	case []interface{}:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for i := range v {
+				v[i] = convertTypes(schema.Items.Schema, v[i])
+			}
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var testVariablesSchema = &runtime.RawExtension{Raw: []byte(`{
+	"type": "object",
+	"properties": {
+		"replicas": {"type": "integer", "minimum": 1, "default": 2},
+		"image": {"type": "string"},
+		"resources": {
+			"type": "object",
+			"default": {},
+			"properties": {
+				"cpu": {"type": "string", "default": "100m"}
+			}
+		},
+		"ports": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"port": {"type": "integer"},
+					"protocol": {"type": "string", "default": "TCP"}
+				}
+			}
+		}
+	},
+	"additionalProperties": false
+}`)}
+
+func TestDefaultVariables(t *testing.T) {
+	testcases := []struct {
+		name      string
+		variables map[string]interface{}
+		expected  map[string]interface{}
+	}{
+		{
+			name:      "empty variables are defaulted",
+			variables: map[string]interface{}{},
+			expected: map[string]interface{}{
+				"replicas":  float64(2),
+				"resources": map[string]interface{}{"cpu": "100m"},
+			},
+		},
+		{
+			name: "set variables are not overwritten",
+			variables: map[string]interface{}{
+				"replicas":  float64(5),
+				"resources": map[string]interface{}{"cpu": "1"},
+			},
+			expected: map[string]interface{}{
+				"replicas":  float64(5),
+				"resources": map[string]interface{}{"cpu": "1"},
+			},
+		},
+		{
+			name: "array items are defaulted",
+			variables: map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"port": float64(53)},
+					map[string]interface{}{"port": float64(53), "protocol": "UDP"},
+				},
+			},
+			expected: map[string]interface{}{
+				"replicas":  float64(2),
+				"resources": map[string]interface{}{"cpu": "100m"},
+				"ports": []interface{}{
+					map[string]interface{}{"port": float64(53), "protocol": "TCP"},
+					map[string]interface{}{"port": float64(53), "protocol": "UDP"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := DefaultVariables(testVariablesSchema, tc.variables); err != nil {
+				t.Fatalf("Failed to default variables: %v", err)
+			}
+
+			if !equality.Semantic.DeepEqual(tc.variables, tc.expected) {
+				t.Fatalf("Expected %v, got %v.", tc.expected, tc.variables)
+			}
+		})
+	}
+}
+
+func TestValidateVariables(t *testing.T) {
+	testcases := []struct {
+		name           string
+		variables      map[string]interface{}
+		expectedFields []string
+	}{
+		{
+			name: "valid variables",
+			variables: map[string]interface{}{
+				"replicas": float64(3),
+				"image":    "nginx",
+			},
+		},
+		{
+			name: "invalid type",
+			variables: map[string]interface{}{
+				"replicas": "three",
+			},
+			expectedFields: []string{"spec.variables.replicas"},
+		},
+		{
+			name: "unknown variable",
+			variables: map[string]interface{}{
+				"replicsa": float64(3),
+			},
+			expectedFields: []string{"spec.variables"},
+		},
+		{
+			name: "multiple errors are sorted",
+			variables: map[string]interface{}{
+				"replicas": float64(0),
+				"image":    float64(1),
+			},
+			expectedFields: []string{"spec.variables.image", "spec.variables.replicas"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateVariables(testVariablesSchema, tc.variables, field.NewPath("spec", "variables"))
+			if len(errs) != len(tc.expectedFields) {
+				t.Fatalf("Expected %d errors, got %d: %v", len(tc.expectedFields), len(errs), errs)
+			}
+
+			for i, err := range errs {
+				if err.Field != tc.expectedFields[i] {
+					t.Errorf("Expected error %d to be for field %q, got %q.", i, tc.expectedFields[i], err.Field)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessVariables(t *testing.T) {
+	variables, err := ParseVariables(&runtime.RawExtension{Raw: []byte(`{"image":"nginx","ports":[{"port":53}]}`)})
+	if err != nil {
+		t.Fatalf("Failed to parse variables: %v", err)
+	}
+
+	processed, err := ProcessVariables(testVariablesSchema, variables)
+	if err != nil {
+		t.Fatalf("Failed to process variables: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"image":     "nginx",
+		"replicas":  int64(2),
+		"resources": map[string]interface{}{"cpu": "100m"},
+		"ports": []interface{}{
+			map[string]interface{}{"port": int64(53), "protocol": "TCP"},
+		},
+	}
+
+	if !equality.Semantic.DeepEqual(processed, expected) {
+		t.Fatalf("Expected %v, got %v.", expected, processed)
+	}
+
+	if _, err := ProcessVariables(testVariablesSchema, map[string]interface{}{"replicas": float64(1.5)}); err == nil {
+		t.Fatal("Expected non-integer replicas to be rejected, but got no error.")
+	}
+
+	unchanged := map[string]interface{}{"replicas": float64(3)}
+	processed, err = ProcessVariables(nil, unchanged)
+	if err != nil {
+		t.Fatalf("Failed to process variables without schema: %v", err)
+	}
+
+	if !equality.Semantic.DeepEqual(processed, unchanged) {
+		t.Fatalf("Expected variables without schema to be unchanged, got %v.", processed)
+	}
+}
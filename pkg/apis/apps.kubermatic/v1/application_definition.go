@@ -140,19 +140,63 @@ type GitSource struct {
 	Credentials *GitCredentials `json:"credentials,omitempty"`
 }
 
+type OCISource struct {
+	// URL of the OCI repository holding the artifact, without tag or digest (e.g. oci://localhost:5000/myrepo/manifests).
+	// The oci:// prefix is optional.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Tag of the artifact to pull.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// Digest of the artifact to pull (e.g. sha256:0123...). If tag is also defined, the tag must resolve to this digest.
+	// At least a tag or a digest must be defined.
+	// +kubebuilder:validation:Pattern:=`^sha256:[a-f0-9]{64}$`
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Path of the "source" in the artifact. default is the artifact root. It must not point outside of the artifact.
+	Path string `json:"path,omitempty"`
+
+	// PlainHTTP allows to pull the artifact over HTTP instead of HTTPS.
+	PlainHTTP bool `json:"plainHTTP,omitempty"`
+
+	// Credentials are optional and hold the ref to the secret with registry credentials.
+	// Either username / Password or registryConfigFile can be defined.
+	Credentials *HelmCredentials `json:"credentials,omitempty"`
+
+	// Verify holds the settings to verify the cosign signature of the artifact before it is used.
+	// If not set, the signature is not verified.
+	Verify *OCIVerification `json:"verify,omitempty"`
+}
+
+type OCIVerification struct {
+	// PublicKey holds the ref and key in the secret for the PEM encoded cosign public key used to verify the signature.
+	// The Secret must exist in the namespace where KKP is installed (default is "kubermatic").
+	// The Secret must be annotated with `apps.kubermatic.k8c.io/secret-type:` set to oci
+	PublicKey corev1.SecretKeySelector `json:"publicKey"`
+}
+
 type ApplicationSource struct {
 	// Install Application from a Helm repository
 	Helm *HelmSource `json:"helm,omitempty"`
 
 	// Install application from a Git repository
 	Git *GitSource `json:"git,omitempty"`
+
+	// Install application from an OCI artifact (e.g. a bundle of manifests or a Helm chart).
+	OCI *OCISource `json:"oci,omitempty"`
 }
 
 const (
-	HelmTemplateMethod TemplateMethod = "helm"
+	HelmTemplateMethod      TemplateMethod = "helm"
+	KustomizeTemplateMethod TemplateMethod = "kustomize"
+	ManifestTemplateMethod  TemplateMethod = "manifest"
 )
 
-// +kubebuilder:validation:Enum=helm
+// +kubebuilder:validation:Enum=helm;kustomize;manifest
 type TemplateMethod string
 
 type ApplicationTemplate struct {
@@ -181,6 +225,13 @@ type ApplicationVersion struct {
 
 	// Template defines how application is installed (source provenance, Method...)
 	Template ApplicationTemplate `json:"template"`
+
+	// ValuesSchema is an OpenAPI v3 / JSON schema (e.g. the values.schema.json of a Helm chart) the values of the
+	// ApplicationInstallations using this version must match. Values are validated when an ApplicationInstallation is
+	// created or updated.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	ValuesSchema *runtime.RawExtension `json:"valuesSchema,omitempty"`
 }
 
 // ApplicationDefinitionSpec defines the desired state of ApplicationDefinition.
@@ -188,7 +239,10 @@ type ApplicationDefinitionSpec struct {
 	// Description of the application. what is its purpose
 	Description string `json:"description"`
 
-	// Method used to install the application
+	// Method used to install the application.
+	// helm renders and installs the source as a Helm chart.
+	// kustomize builds the source directory with Kustomize and applies the result with server-side apply.
+	// manifest applies all YAML / JSON files found in the source directory with server-side apply.
 	Method TemplateMethod `json:"method"`
 
 	// DefaultValues describe overrides for manifest-rendering in UI when creating an application.
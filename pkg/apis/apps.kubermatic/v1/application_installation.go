@@ -33,6 +33,9 @@ const (
 
 	// ApplicationInstallationsFQDNName represents "FQDN" defined in Kubernetes.
 	ApplicationInstallationsFQDNName = ApplicationInstallationResourceName + "." + GroupName
+
+	// ApplicationInstallationMaxHistory is the maximum number of revisions kept in the history of an ApplicationInstallation.
+	ApplicationInstallationMaxHistory = 10
 )
 
 // +kubebuilder:object:root=true
@@ -81,6 +84,63 @@ type ApplicationInstallationSpec struct {
 
 	// DeployOptions holds the settings specific to the templating method used to deploy the application.
 	DeployOptions *DeployOptions `json:"deployOptions,omitempty"`
+
+	// DependsOn is the list of names of the ApplicationInstallations (in the same namespace) this application depends on.
+	// The application is only installed or upgraded once all its dependencies are ready. On deletion, the application is
+	// only uninstalled once all ApplicationInstallations depending on it have been removed.
+	// Cyclic dependencies are not allowed.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// RollbackTo is the name of a revision listed in status.history to roll back to. While set, the application is pinned
+	// to this revision: changes to the applicationRef or the values are not deployed. Unset it to resume normal reconciliation.
+	// Only supported if template method is 'helm'.
+	// +optional
+	RollbackTo string `json:"rollbackTo,omitempty"`
+
+	// AutoRollback, if set, rolls back the application to the last successfully deployed revision after a number of
+	// consecutive failed installations or upgrades. The application is not reconciled anymore until its spec changes.
+	// Only supported if template method is 'helm'.
+	// +optional
+	AutoRollback *AutoRollbackPolicy `json:"autoRollback,omitempty"`
+
+	// DriftDetection, if set, periodically compares the objects deployed by the application with the live objects of the
+	// user cluster. Differences are reported in the Drifted condition.
+	// +optional
+	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
+}
+
+// AutoRollbackPolicy defines when an application is automatically rolled back.
+type AutoRollbackPolicy struct {
+	// AfterFailures is the number of consecutive failed installations or upgrades after which the application is rolled
+	// back to the last successfully deployed revision. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=3
+	AfterFailures int `json:"afterFailures,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=report;correct
+
+// DriftPolicy defines what to do when the live objects of an application have drifted from the deployed ones.
+type DriftPolicy string
+
+const (
+	// DriftPolicyReport only reports the drift in the Drifted condition.
+	DriftPolicyReport DriftPolicy = "report"
+
+	// DriftPolicyCorrect reports the drift and restores the drifted objects to their deployed state, overwriting
+	// fields that have been changed by other field managers.
+	DriftPolicyCorrect DriftPolicy = "correct"
+)
+
+// DriftDetection configures the detection of changes made to the objects deployed by an application.
+type DriftDetection struct {
+	// Policy defines what to do when a drift is detected. Defaults to 'report'.
+	// +kubebuilder:default:=report
+	Policy DriftPolicy `json:"policy,omitempty"`
+
+	// Interval at which the drift is checked. Defaults to 10 minutes. Must be at least 1 minute.
+	Interval metav1.Duration `json:"interval,omitempty"`
 }
 
 // DeployOptions holds the settings specific to the templating method used to deploy the application.
@@ -106,6 +166,15 @@ type HelmDeployOptions struct {
 	// enable DNS lookups when rendering templates.
 	// if you enable this flag, you have to verify that helm template function 'getHostByName' is not being used in a chart to disclose any information you do not want to be passed to DNS servers.(c.f. CVE-2023-25165)
 	EnableDNS bool `json:"enableDNS,omitempty"`
+
+	// MaxHistory corresponds to the --history-max flag on Helm cli.
+	// maximum number of revisions (including the current one) Helm keeps for the release. Older revisions are purged and
+	// can not be rolled back to anymore. Helm loads every kept revision into memory, so this should be kept low.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	MaxHistory int `json:"maxHistory,omitempty"`
 }
 
 // AppNamespaceSpec describe the desired state of the namespace where application will be created.
@@ -168,8 +237,36 @@ type ApplicationInstallationStatus struct {
 	// HelmRelease holds the information about the helm release installed by this application. This field is only filled if template method is 'helm'.
 	HelmRelease *HelmRelease `json:"helmRelease,omitempty"`
 
+	// AppliedResources lists the objects that have been applied into the user cluster by this application. This field is only filled
+	// if template method is 'kustomize' or 'manifest'. Objects that are listed here but not part of the application anymore are pruned.
+	AppliedResources []AppliedResource `json:"appliedResources,omitempty"`
+
 	// Failures counts the number of failed installation or updagrade. it is reset on successful reconciliation.
 	Failures int `json:"failures,omitempty"`
+
+	// History lists the revisions successfully deployed by this application, the most recent first. It holds at most 10
+	// revisions.
+	History []ApplicationInstallationRevision `json:"history,omitempty"`
+}
+
+// ApplicationInstallationRevision describes a revision of the application that has been successfully deployed.
+type ApplicationInstallationRevision struct {
+	// Name of the revision. It's built from the application version and the values hash (<version>-<hash>), so
+	// deploying the same version with the same values again results in the same revision.
+	Name string `json:"name"`
+
+	// ApplicationVersion is the version of the application deployed.
+	ApplicationVersion string `json:"applicationVersion"`
+
+	// ValuesHash is the sha256 hash of the values used to deploy the application.
+	ValuesHash string `json:"valuesHash"`
+
+	// HelmReleaseRevision is the revision of the helm release created by this deployment. This field is only filled
+	// if template method is 'helm'.
+	HelmReleaseRevision int `json:"helmReleaseRevision,omitempty"`
+
+	// DeployedAt is when the revision was last deployed.
+	DeployedAt metav1.Time `json:"deployedAt"`
 }
 
 type HelmRelease struct {
@@ -183,6 +280,24 @@ type HelmRelease struct {
 	Info *HelmReleaseInfo `json:"info,omitempty"`
 }
 
+// AppliedResource identifies an object applied into the user cluster by an application.
+type AppliedResource struct {
+	// Group of the object. Empty for the core API group.
+	Group string `json:"group,omitempty"`
+
+	// Version of the object.
+	Version string `json:"version"`
+
+	// Kind of the object.
+	Kind string `json:"kind"`
+
+	// Namespace of the object. Empty for cluster-scoped objects.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object.
+	Name string `json:"name"`
+}
+
 // HelmReleaseInfo describes release information.
 // tech note: we can not use release.Info from Helm because the underlying type used for time has no json tag.
 type HelmReleaseInfo struct {
@@ -226,7 +341,7 @@ type ApplicationInstallationCondition struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=ManifestsRetrieved;Ready
+// +kubebuilder:validation:Enum=ManifestsRetrieved;Ready;Drifted
 
 // swagger:enum ApplicationInstallationConditionType
 // All condition types must be registered within the `AllApplicationInstallationConditionTypes` variable.
@@ -238,11 +353,16 @@ const (
 
 	// Ready describes all components have been successfully rolled out and are ready.
 	Ready ApplicationInstallationConditionType = "Ready"
+
+	// Drifted indicates that the live objects of the application differ from the deployed ones. It's only set if drift
+	// detection is enabled.
+	Drifted ApplicationInstallationConditionType = "Drifted"
 )
 
 var AllApplicationInstallationConditionTypes = []ApplicationInstallationConditionType{
 	ManifestsRetrieved,
 	Ready,
+	Drifted,
 }
 
 // SetCondition of the applicationInstallation. It take care of update LastHeartbeatTime and LastTransitionTime if needed.
@@ -266,6 +386,28 @@ func (appInstallation *ApplicationInstallation) SetCondition(conditionType Appli
 	appInstallation.Status.Conditions[conditionType] = condition
 }
 
+// AddRevision records revision as the most recent revision in the history. If a revision with the same name already
+// exists, it's moved to the top of the history. The history is truncated to ApplicationInstallationMaxHistory revisions.
+func (appInstallation *ApplicationInstallation) AddRevision(revision ApplicationInstallationRevision) {
+	history := []ApplicationInstallationRevision{revision}
+	for _, rev := range appInstallation.Status.History {
+		if rev.Name != revision.Name && len(history) < ApplicationInstallationMaxHistory {
+			history = append(history, rev)
+		}
+	}
+	appInstallation.Status.History = history
+}
+
+// GetRevision returns the revision named name from the history or nil if it does not exist.
+func (appInstallation *ApplicationInstallation) GetRevision(name string) *ApplicationInstallationRevision {
+	for i := range appInstallation.Status.History {
+		if appInstallation.Status.History[i].Name == name {
+			return &appInstallation.Status.History[i]
+		}
+	}
+	return nil
+}
+
 // SetReadyCondition sets the ReadyCondition and appInstallation.Status.Failures counter according to the installError.
 func (appInstallation *ApplicationInstallation) SetReadyCondition(installErr error, hasLimitedRetries bool) {
 	if installErr != nil {
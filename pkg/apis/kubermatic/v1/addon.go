@@ -30,6 +30,16 @@ const (
 	AddonKindName = "Addon"
 
 	AddonResourcesCreated AddonConditionType = "AddonResourcesCreatedSuccessfully"
+	// AddonResourcesApplied reports the result of the last apply of the addon manifests. If applying
+	// any object failed, the condition is false and its message lists the failed objects.
+	AddonResourcesApplied AddonConditionType = "AddonResourcesApplied"
+	// AddonHealthy is true if all Deployments, DaemonSets and StatefulSets of the addon are ready
+	// and all health probes declared in the matching AddonConfig succeed.
+	AddonHealthy AddonConditionType = "Healthy"
+	// AddonDegraded is true if the addon is actively failing instead of just not being ready yet,
+	// e.g. because one of its workloads is crash-looping, exceeded its progress deadline or a health
+	// probe fails although all workloads are ready.
+	AddonDegraded AddonConditionType = "Degraded"
 )
 
 // +kubebuilder:object:generate=true
@@ -95,9 +105,31 @@ type AddonList struct {
 // AddonStatus contains information about the reconciliation status.
 type AddonStatus struct {
 	Conditions map[AddonConditionType]AddonCondition `json:"conditions,omitempty"`
+
+	// AppliedResources lists the objects that have been applied into the user cluster for this addon.
+	// Objects that are not part of the addon manifests anymore are pruned based on this list.
+	AppliedResources []AddonAppliedResource `json:"appliedResources,omitempty"`
+}
+
+// AddonAppliedResource identifies an object applied into the user cluster by an addon.
+type AddonAppliedResource struct {
+	// Group of the object. Empty for the core API group.
+	Group string `json:"group,omitempty"`
+
+	// Version of the object.
+	Version string `json:"version"`
+
+	// Kind of the object.
+	Kind string `json:"kind"`
+
+	// Namespace of the object. Empty for cluster-scoped objects.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object.
+	Name string `json:"name"`
 }
 
-// +kubebuilder:validation:Enum=AddonResourcesCreatedSuccessfully
+// +kubebuilder:validation:Enum=AddonResourcesCreatedSuccessfully;AddonResourcesApplied;Healthy;Degraded
 
 type AddonConditionType string
 
@@ -109,4 +141,10 @@ type AddonCondition struct {
 	// Last time the condition transitioned from one status to another.
 	// +optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
@@ -17,7 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +kubebuilder:resource:scope=Cluster
@@ -49,6 +52,34 @@ type AddonConfigSpec struct {
 	LogoFormat string `json:"logoFormat,omitempty"`
 	// Controls that can be set for configured addon
 	Controls []AddonFormControl `json:"formSpec,omitempty"`
+	// HealthProbes are checked in addition to the readiness of the Deployments, DaemonSets and
+	// StatefulSets of the addon to determine whether the addon is healthy.
+	HealthProbes []AddonHealthProbe `json:"healthProbes,omitempty"`
+	// VariablesSchema is an OpenAPI v3 / JSON schema the variables of Addons using this config must match.
+	// Invalid variables are rejected when an Addon is created or updated. Variables that are neither set on
+	// the Addon nor in the globally configured addon variables are defaulted from the `default` keywords of
+	// the schema when the addon is rendered; the defaults are not stored in the Addon.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	VariablesSchema *runtime.RawExtension `json:"variablesSchema,omitempty"`
+}
+
+// AddonHealthProbe is an HTTP GET request that is sent to a Service in the user cluster through
+// the Kubernetes API server proxy. The probe succeeds if the response has a 2xx status code.
+type AddonHealthProbe struct {
+	// Name of the probe, used to report failures.
+	Name string `json:"name"`
+	// Namespace of the Service.
+	Namespace string `json:"namespace"`
+	// Service is the name of the Service to probe.
+	Service string `json:"service"`
+	// Port is the name or number of the Service port.
+	Port intstr.IntOrString `json:"port"`
+	// Scheme to use for the request. Defaults to HTTP.
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	Scheme corev1.URIScheme `json:"scheme,omitempty"`
+	// Path to request. Defaults to "/".
+	Path string `json:"path,omitempty"`
 }
 
 // AddonFormControl specifies addon form control.
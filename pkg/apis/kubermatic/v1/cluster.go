@@ -50,6 +50,15 @@ const (
 
 	// PresetInvalidatedAnnotation is key of the annotation used to indicate why the preset was invalidated.
 	PresetInvalidatedAnnotation = "presetInvalidated"
+
+	// CloneInProgressAnnotation is set on clusters into which the etcd backup of another cluster is being
+	// cloned and holds the name of the source cluster. As long as it is set, the machine-controller is not
+	// started, so that the restored Machines of the source cluster are not reconciled.
+	CloneInProgressAnnotation = "kubermatic.k8c.io/clone-in-progress"
+
+	// SkipUpgradeCompatibilityCheckAnnotation can be set to "true" on a cluster to allow upgrades of
+	// the control plane even though APIs that are removed in the new Kubernetes release are still in use.
+	SkipUpgradeCompatibilityCheckAnnotation = "kubermatic.k8c.io/skip-upgrade-compatibility-check"
 )
 
 const (
@@ -225,6 +234,11 @@ type ClusterSpec struct {
 	// By default it is disabled.
 	OPAIntegration *OPAIntegrationSettings `json:"opaIntegration,omitempty"`
 
+	// Optional: ComplianceScanning periodically checks the control plane and the nodes of the cluster
+	// against the CIS Kubernetes benchmark and stores the results in a ComplianceReport in the cluster
+	// namespace. By default it is disabled.
+	ComplianceScanning *ComplianceScanningSettings `json:"complianceScanning,omitempty"`
+
 	// Optional: ServiceAccount contains service account related settings for the user cluster's kube-apiserver.
 	ServiceAccount *ServiceAccountSettings `json:"serviceAccount,omitempty"`
 
@@ -247,6 +261,21 @@ type ClusterSpec struct {
 
 	// Enables more verbose logging in KKP's user-cluster-controller-manager.
 	DebugLog bool `json:"debugLog,omitempty"`
+
+	// Optional: ForceDeletion allows KKP to skip the cleanup of applications and cloud resources inside
+	// the user cluster (LoadBalancers, volumes and machines) if the cluster deletion takes too long. Skipped
+	// resources are recorded in the deletion status and must be cleaned up manually.
+	// The cleanup of the cloud provider infrastructure of the cluster (e.g. networks, security
+	// groups or roles) is never skipped; a deletion stuck in this cleanup has to be resolved
+	// manually.
+	ForceDeletion *ForceDeletionPolicy `json:"forceDeletion,omitempty"`
+}
+
+// ForceDeletionPolicy configures when cloud-side cleanup steps are skipped during cluster deletion.
+type ForceDeletionPolicy struct {
+	// After is the duration, counted from the deletion timestamp of the cluster, after which
+	// pending application, LoadBalancer, volume and machine cleanups are skipped.
+	After metav1.Duration `json:"after"`
 }
 
 func (c ClusterSpec) IsOperatingSystemManagerEnabled() bool {
@@ -264,6 +293,20 @@ func (c ClusterSpec) IsKubernetesDashboardEnabled() bool {
 	return c.KubernetesDashboard == nil || c.KubernetesDashboard.Enabled
 }
 
+// ComplianceScanningSettings configures the periodic CIS benchmark scans of a cluster.
+type ComplianceScanningSettings struct {
+	// Enabled controls whether the cluster is scanned.
+	Enabled bool `json:"enabled,omitempty"`
+	// Schedule is a cron expression (e.g. "0 3 * * *" or "@weekly") that determines when the
+	// cluster is scanned. Defaults to "@daily".
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+}
+
+func (c ClusterSpec) IsComplianceScanningEnabled() bool {
+	return c.ComplianceScanning != nil && c.ComplianceScanning.Enabled
+}
+
 // GetVersionConditions returns a kubermaticv1.ConditionType list that should be used when checking
 // for available versions in a VersionManager instance.
 func (c ClusterSpec) GetVersionConditions() []ConditionType {
@@ -433,6 +476,10 @@ const (
 
 	ClusterConditionUpdateProgress ClusterConditionType = "UpdateProgress"
 
+	// ClusterConditionUpgradeCompatible is false if the cluster still uses APIs that are removed
+	// in the next Kubernetes minor release, which blocks upgrades of the control plane.
+	ClusterConditionUpgradeCompatible ClusterConditionType = "UpgradeCompatible"
+
 	// ClusterConditionNone is a special value indicating that no cluster condition should be set.
 	ClusterConditionNone ClusterConditionType = ""
 	// This condition is met when a CSI migration is ongoing and the CSI
@@ -549,6 +596,99 @@ type ClusterStatus struct {
 
 	// ResourceUsage shows the current usage of resources for the cluster.
 	ResourceUsage *ResourceDetails `json:"resourceUsage,omitempty"`
+
+	// DeprecatedAPIs lists the deprecated APIs that are still in use inside the user cluster and
+	// that are going to be removed in a future Kubernetes release. This is periodically updated by
+	// the usercluster-controller-manager.
+	// +optional
+	DeprecatedAPIs *DeprecatedAPIsStatus `json:"deprecatedAPIs,omitempty"`
+
+	// Deletion shows the progress of the cluster deletion. It is only set once the cluster is
+	// being deleted.
+	// +optional
+	Deletion *ClusterDeletionStatus `json:"deletion,omitempty"`
+}
+
+// ClusterDeletionStatus describes the progress of a cluster deletion.
+type ClusterDeletionStatus struct {
+	// PendingFinalizers lists the finalizers that still block the deletion of the cluster.
+	// +optional
+	PendingFinalizers []PendingFinalizer `json:"pendingFinalizers,omitempty"`
+	// OrphanedResources lists the resources that were not cleaned up because the
+	// force deletion policy skipped their cleanup.
+	// +optional
+	OrphanedResources []OrphanedResource `json:"orphanedResources,omitempty"`
+}
+
+// PendingFinalizer describes a single finalizer blocking the cluster deletion.
+type PendingFinalizer struct {
+	// Name is the name of the finalizer.
+	Name string `json:"name"`
+	// WaitingFor describes what the cleanup step behind this finalizer is waiting on.
+	// +optional
+	WaitingFor string `json:"waitingFor,omitempty"`
+	// Since is the time since when the deletion is waiting for this finalizer.
+	Since metav1.Time `json:"since"`
+}
+
+// OrphanedResource describes a resource that was left behind by a forced cluster deletion.
+type OrphanedResource struct {
+	// Finalizer is the finalizer whose cleanup step was skipped.
+	Finalizer string `json:"finalizer"`
+	// Kind is the kind of the resource inside the user cluster, e.g. "Service" or "Machine".
+	Kind string `json:"kind"`
+	// Name is the name of the resource, prefixed with its namespace for namespaced resources.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Message contains additional information, e.g. why the resources could not be listed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DeprecatedAPIsStatus is the result of scanning a user cluster for deprecated API usage.
+type DeprecatedAPIsStatus struct {
+	// LastScanTime is the time when the user cluster was last scanned.
+	LastScanTime metav1.Time `json:"lastScanTime"`
+	// Usages are the deprecated APIs that are in use.
+	// +optional
+	Usages []DeprecatedAPIUsage `json:"usages,omitempty"`
+}
+
+// DeprecatedAPIUsage describes a single deprecated API that is still in use.
+type DeprecatedAPIUsage struct {
+	// Group is the API group, empty for the core group.
+	// +optional
+	Group    string `json:"group,omitempty"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	// RemovedInVersion is the Kubernetes release that no longer serves this API, e.g. "1.25".
+	RemovedInVersion string `json:"removedInVersion"`
+	// Replacement is the group/version that should be used instead, if there is any.
+	// +optional
+	Replacement string `json:"replacement,omitempty"`
+	// Objects lists (a sample of) the objects that still use this API, either because they can
+	// only be represented in it or because they are part of a Helm release whose manifest still
+	// uses it.
+	// +optional
+	Objects []string `json:"objects,omitempty"`
+	// ObjectCount is the total number of objects found, which can be larger than the
+	// number of listed objects.
+	// +optional
+	ObjectCount int `json:"objectCount,omitempty"`
+	// Requested is true if the apiserver metrics show that clients requested this API since
+	// the apiserver was last started. This is informational only and does not block upgrades
+	// on its own, as the deprecated API scan itself requests APIs to find their objects.
+	// +optional
+	Requested bool `json:"requested,omitempty"`
+}
+
+// GroupVersion returns the API's group/version in the same format as the apiVersion field.
+func (u *DeprecatedAPIUsage) GroupVersion() string {
+	if u.Group == "" {
+		return u.Version
+	}
+
+	return u.Group + "/" + u.Version
 }
 
 // ClusterVersionsStatus contains information regarding the current and desired versions
@@ -1385,6 +1525,9 @@ type ExtendedClusterHealth struct {
 	MLAGateway                   *HealthStatus `json:"mlaGateway,omitempty"`
 	OperatingSystemManager       *HealthStatus `json:"operatingSystemManager,omitempty"`
 	KubernetesDashboard          *HealthStatus `json:"kubernetesDashboard,omitempty"`
+	// Addons is the combined health of all addons installed into the cluster. It is down if
+	// any addon is degraded and provisioning if any addon is not healthy yet.
+	Addons *HealthStatus `json:"addons,omitempty"`
 }
 
 // ControlPlaneHealthy returns if all Kubernetes control plane components are healthy.
@@ -16,10 +16,10 @@ limitations under the License.
 
 package v1
 
-// +kubebuilder:validation:Enum=NodePort;LoadBalancer;Tunneling
+// +kubebuilder:validation:Enum=NodePort;LoadBalancer;Tunneling;GatewayAPI
 
 // ExposeStrategy is the strategy used to expose a cluster control plane.
-// Possible values are `NodePort`, `LoadBalancer` or `Tunneling` (requires a feature gate).
+// Possible values are `NodePort`, `LoadBalancer`, `Tunneling` (requires a feature gate) or `GatewayAPI`.
 type ExposeStrategy string
 
 const (
@@ -43,6 +43,12 @@ const (
 	// (e.g. Service of type LoadBalancer) without consuming one or more ports
 	// for each user cluster.
 	ExposeStrategyTunneling ExposeStrategy = "Tunneling"
+	// ExposeStrategyGatewayAPI exposes the control plane components via
+	// TLSRoutes attached to a Gateway that is provided by a Gateway API
+	// implementation running on the Seed Cluster. The routes use SNI to
+	// pass TLS traffic through to the right user cluster, so clusters must
+	// use Konnectivity. The Gateway is configured in the Seed.
+	ExposeStrategyGatewayAPI ExposeStrategy = "GatewayAPI"
 )
 
 // Finalizers should be kept to their controllers. Only if a finalizer is
@@ -72,6 +78,8 @@ const (
 	GatekeeperConstraintCleanupFinalizer = "kubermatic.k8c.io/cleanup-gatekeeper-constraints"
 	// KubermaticConstraintCleanupFinalizer indicates that Kubermatic constraints for the cluster need cleanup.
 	KubermaticConstraintCleanupFinalizer = "kubermatic.k8c.io/cleanup-kubermatic-constraints"
+	// InClusterApplicationCleanupFinalizer indicates that the ApplicationInstallations in the user cluster still need cleanup.
+	InClusterApplicationCleanupFinalizer = "kubermatic.k8c.io/cleanup-in-cluster-applications"
 )
 
 const (
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ComplianceReportResourceName represents "Resource" defined in Kubernetes.
+	ComplianceReportResourceName = "compliancereports"
+
+	// ComplianceReportKindName represents "Kind" defined in Kubernetes.
+	ComplianceReportKindName = "ComplianceReport"
+
+	// CISBenchmarkComplianceReportName is the name of the ComplianceReport that holds the
+	// results of the CIS benchmark scans in a cluster namespace.
+	CISBenchmarkComplianceReportName = "cis-benchmark"
+
+	// DefaultComplianceScanSchedule is used if no schedule is configured for the compliance scanning.
+	DefaultComplianceScanSchedule = "@daily"
+)
+
+// +kubebuilder:validation:Enum=Pass;Fail;Warn;Info
+
+// ComplianceCheckState is the outcome of a single compliance check.
+type ComplianceCheckState string
+
+const (
+	// ComplianceCheckPass means that the check was successful.
+	ComplianceCheckPass ComplianceCheckState = "Pass"
+	// ComplianceCheckFail means that the benchmark recommendation is not met.
+	ComplianceCheckFail ComplianceCheckState = "Fail"
+	// ComplianceCheckWarn means that the check could not be evaluated automatically
+	// and needs to be verified manually.
+	ComplianceCheckWarn ComplianceCheckState = "Warn"
+	// ComplianceCheckInfo is used for checks that are informational only.
+	ComplianceCheckInfo ComplianceCheckState = "Info"
+)
+
+// +kubebuilder:validation:Enum=ControlPlane;Node
+
+// ComplianceCheckTarget is the part of the cluster a compliance check was run against.
+type ComplianceCheckTarget string
+
+const (
+	// ComplianceCheckTargetControlPlane checks are evaluated against the control plane
+	// components running in the seed cluster.
+	ComplianceCheckTargetControlPlane ComplianceCheckTarget = "ControlPlane"
+	// ComplianceCheckTargetNode checks are run on every node of the user cluster.
+	ComplianceCheckTargetNode ComplianceCheckTarget = "Node"
+)
+
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".spec.cluster",name="Cluster",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.benchmark",name="Benchmark",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.summary.pass",name="Pass",type="integer"
+// +kubebuilder:printcolumn:JSONPath=".status.summary.fail",name="Fail",type="integer"
+// +kubebuilder:printcolumn:JSONPath=".status.summary.warn",name="Warn",type="integer"
+// +kubebuilder:printcolumn:JSONPath=".status.lastScanTime",name="Last Scan",type="date"
+
+// ComplianceReport contains the results of the latest CIS benchmark scan of a user cluster.
+// It is created in the cluster namespace by the user-cluster-controller-manager if compliance
+// scanning is enabled for the cluster and is updated after every scan.
+type ComplianceReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ComplianceReportSpec   `json:"spec,omitempty"`
+	Status ComplianceReportStatus `json:"status,omitempty"`
+}
+
+// ComplianceReportSpec specifies the scanned cluster.
+type ComplianceReportSpec struct {
+	// Cluster is the name of the scanned cluster.
+	Cluster string `json:"cluster"`
+}
+
+// ComplianceReportStatus contains the results of the latest scan.
+type ComplianceReportStatus struct {
+	// Benchmark is the version of the CIS Kubernetes benchmark the cluster was scanned against.
+	Benchmark string `json:"benchmark,omitempty"`
+	// LastScanTime is the time the latest scan was completed.
+	LastScanTime metav1.Time `json:"lastScanTime,omitempty"`
+	// Summary counts the checks by their state.
+	Summary ComplianceReportSummary `json:"summary,omitempty"`
+	// Checks contains the result of every check. Node checks are run on every node and
+	// reported once, with the least favourable state of all nodes.
+	Checks []ComplianceCheckResult `json:"checks,omitempty"`
+	// Errors lists problems that prevented parts of the cluster from being scanned,
+	// for example nodes on which the scan did not complete.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ComplianceReportSummary counts the checks of a ComplianceReport by their state.
+type ComplianceReportSummary struct {
+	Pass int `json:"pass"`
+	Fail int `json:"fail"`
+	Warn int `json:"warn"`
+	Info int `json:"info"`
+}
+
+// ComplianceCheckResult is the result of a single benchmark check.
+type ComplianceCheckResult struct {
+	// ID is the number of the recommendation in the CIS benchmark, e.g. "1.2.18".
+	ID string `json:"id"`
+	// Description of the recommendation.
+	Description string `json:"description"`
+	// Target is the part of the cluster the check was run against.
+	Target ComplianceCheckTarget `json:"target"`
+	// State is the outcome of the check.
+	State ComplianceCheckState `json:"state"`
+	// Nodes lists the nodes on which a node check had the reported state. At most 10 nodes are listed.
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+	// Remediation describes how to fix a failed check.
+	// +optional
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+
+// ComplianceReportList is a list of compliance reports.
+type ComplianceReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of compliance reports.
+	Items []ComplianceReport `json:"items"`
+}
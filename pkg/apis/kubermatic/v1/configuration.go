@@ -179,6 +179,10 @@ type KubermaticSeedControllerConfiguration struct {
 	// BackupDeleteContainer is the container used for deleting etcd snapshots from a backup location.
 	// This container is only relevant when the new backup/restore controllers are enabled.
 	BackupDeleteContainer string `json:"backupDeleteContainer,omitempty"`
+	// BackupVerifyContainer is the container used for downloading etcd snapshots from a backup location
+	// to verify them. It is run as an init container and must write the snapshot to /backup/snapshot.db.
+	// This container is only relevant when the new backup/restore controllers are enabled.
+	BackupVerifyContainer string `json:"backupVerifyContainer,omitempty"`
 	// BackupCleanupContainer is the container used for removing expired backups from the storage location.
 	// This container is only relevant when the old, deprecated backup controllers are enabled.
 	BackupCleanupContainer string `json:"backupCleanupContainer,omitempty"`
@@ -279,6 +279,9 @@ type SeedSpec struct {
 	// NodeportProxy can be used to configure the NodePort proxy service that is
 	// responsible for making user-cluster control planes accessible from the outside.
 	NodeportProxy NodeportProxyConfig `json:"nodeportProxy,omitempty"`
+	// Optional: GatewayAPI configures the Gateway that the control planes of user
+	// clusters using the `GatewayAPI` expose strategy are attached to.
+	GatewayAPI *SeedGatewayAPIConfiguration `json:"gatewayAPI,omitempty"`
 	// Optional: ProxySettings can be used to configure HTTP proxy settings on the
 	// worker nodes in user clusters. However, proxy settings on nodes take precedence.
 	ProxySettings *ProxySettings `json:"proxySettings,omitempty"`
@@ -325,6 +328,15 @@ type BackupDestination struct {
 	BucketName string `json:"bucketName"`
 	// Credentials hold the ref to the secret with backup credentials
 	Credentials *corev1.SecretReference `json:"credentials,omitempty"`
+	// EncryptionKey references the key used to encrypt backups before they are uploaded to this
+	// destination. The key must be 32 bytes long (optionally base64-encoded) and be stored in a
+	// Secret in the same namespace as the credentials Secret. Backups are encrypted with AES-256-GCM
+	// and decrypted transparently when restoring them. If not set, backups are stored unencrypted.
+	// The Secret must be in kube-system, where the backup jobs run, otherwise the EtcdBackupConfigs
+	// using this destination are rejected. EtcdRestores that do not reference a destination only
+	// decrypt backups if their BackupDownloadCredentialsSecret contains the key as ENCRYPTION_KEY.
+	// +optional
+	EncryptionKey *corev1.SecretKeySelector `json:"encryptionKey,omitempty"`
 }
 
 type NodeportProxyConfig struct {
@@ -343,6 +355,55 @@ type NodeportProxyConfig struct {
 	// Updater configures the component responsible for updating the LoadBalancer
 	// service.
 	Updater NodeportProxyComponent `json:"updater,omitempty"`
+	// RateLimits configures the default connection limits that are applied to
+	// every Service exposed by the nodeport-proxy, e.g. the kube-apiservers of
+	// the user clusters. Single Services can override them using the
+	// "nodeport-proxy.k8s.io/*" annotations.
+	RateLimits *NodeportProxyRateLimits `json:"rateLimits,omitempty"`
+}
+
+// NodeportProxyRateLimits configures the connection limits of a Service exposed
+// by the nodeport-proxy.
+type NodeportProxyRateLimits struct {
+	// MaxConnections is the maximum number of concurrent connections to a single
+	// exposed Service. New connections are closed once the limit is reached.
+	// If not set, Envoy's default of 1024 connections applies.
+	// +kubebuilder:validation:Minimum:=0
+	MaxConnections int32 `json:"maxConnections,omitempty"`
+	// MaxPendingConnections is the maximum number of connections that are waiting
+	// for an upstream connection to a single exposed Service. If not set, Envoy's
+	// default of 1024 pending connections applies.
+	// +kubebuilder:validation:Minimum:=0
+	MaxPendingConnections int32 `json:"maxPendingConnections,omitempty"`
+	// ConnectionsPerSecond is the number of new connections per second that are
+	// accepted for a single exposed Service. Only applies to the SNI and NodePort
+	// expose types. If not set, the rate of new connections is not limited.
+	// +kubebuilder:validation:Minimum:=0
+	ConnectionsPerSecond int32 `json:"connectionsPerSecond,omitempty"`
+	// ConnectionBurst is the number of new connections that can be accepted at
+	// once on top of ConnectionsPerSecond. Defaults to ConnectionsPerSecond.
+	// +kubebuilder:validation:Minimum:=0
+	ConnectionBurst int32 `json:"connectionBurst,omitempty"`
+}
+
+// SeedGatewayAPIConfiguration configures the Gateway used by the `GatewayAPI` expose strategy.
+type SeedGatewayAPIConfiguration struct {
+	// Gateway references the Gateway the TLSRoutes of the user clusters are
+	// attached to. The Gateway must have a TLS listener in passthrough mode that
+	// allows routes from the cluster namespaces.
+	Gateway GatewayReference `json:"gateway"`
+	// Optional: ListenerName is the name of the Gateway listener the TLSRoutes are
+	// attached to. If not set, the routes attach to all compatible listeners and the
+	// port of the first TLS listener is used for the cluster address.
+	ListenerName string `json:"listenerName,omitempty"`
+}
+
+// GatewayReference references a Gateway object.
+type GatewayReference struct {
+	// Name is the name of the Gateway.
+	Name string `json:"name"`
+	// Namespace is the namespace of the Gateway.
+	Namespace string `json:"namespace"`
 }
 
 type EnvoyLoadBalancerService struct {
@@ -70,9 +70,69 @@ type EtcdBackupConfigSpec struct {
 	// Keep is the number of backups to keep around before deleting the oldest one
 	// If not set, defaults to DefaultKeptBackupsCount. Only used if Schedule is set.
 	Keep *int `json:"keep,omitempty"`
+	// Retention is a tiered (grandfather-father-son) retention policy. If any of its tiers is set, it
+	// replaces Keep: a completed backup is kept for as long as at least one tier retains it. Only used
+	// if Schedule is set.
+	// +optional
+	Retention *EtcdBackupRetention `json:"retention,omitempty"`
 	// Destination indicates where the backup will be stored. The destination name must correspond to a destination in
 	// the cluster's Seed.Spec.EtcdBackupRestore.
 	Destination string `json:"destination"`
+	// Verification configures whether completed backups are verified by downloading them again
+	// and restoring them into a scratch data directory.
+	// +optional
+	Verification *EtcdBackupVerification `json:"verification,omitempty"`
+}
+
+// EtcdBackupVerification configures the verification of uploaded etcd backups.
+type EtcdBackupVerification struct {
+	// Enabled controls whether each completed backup is downloaded from the destination and
+	// restored into a scratch data directory to prove that it can actually be restored. The
+	// revision, key count and hash of the snapshot are recorded in the backup status.
+	Enabled bool `json:"enabled"`
+}
+
+// EtcdBackupRetention configures how many hourly, daily, weekly and monthly backups are kept. Each
+// tier retains the newest backup of each of its most recent periods that contain a backup. Periods
+// are computed in UTC, weeks start on Monday.
+type EtcdBackupRetention struct {
+	// Hourly is the number of hours for which the newest backup is retained.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Hourly int `json:"hourly,omitempty"`
+	// Daily is the number of days for which the newest backup is retained.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Daily int `json:"daily,omitempty"`
+	// Weekly is the number of weeks for which the newest backup is retained.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Weekly int `json:"weekly,omitempty"`
+	// Monthly is the number of months for which the newest backup is retained.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Monthly int `json:"monthly,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Hourly;Daily;Weekly;Monthly
+
+// EtcdBackupRetentionTier is a tier of an EtcdBackupRetention policy.
+type EtcdBackupRetentionTier string
+
+const (
+	EtcdBackupRetentionTierHourly  EtcdBackupRetentionTier = "Hourly"
+	EtcdBackupRetentionTierDaily   EtcdBackupRetentionTier = "Daily"
+	EtcdBackupRetentionTierWeekly  EtcdBackupRetentionTier = "Weekly"
+	EtcdBackupRetentionTierMonthly EtcdBackupRetentionTier = "Monthly"
+)
+
+// Total returns the maximum number of backups retained by the policy.
+func (r *EtcdBackupRetention) Total() int {
+	if r == nil {
+		return 0
+	}
+
+	return r.Hourly + r.Daily + r.Weekly + r.Monthly
 }
 
 // +kubebuilder:object:generate=true
@@ -116,6 +176,20 @@ type BackupStatus struct {
 	DeleteFinishedTime metav1.Time       `json:"deleteFinishedTime,omitempty"`
 	DeletePhase        BackupStatusPhase `json:"deletePhase,omitempty"`
 	DeleteMessage      string            `json:"deleteMessage,omitempty"`
+	VerifyJobName      string            `json:"verifyJobName,omitempty"`
+	// +optional
+	VerifyFinishedTime metav1.Time       `json:"verifyFinishedTime,omitempty"`
+	VerifyPhase        BackupStatusPhase `json:"verifyPhase,omitempty"`
+	VerifyMessage      string            `json:"verifyMessage,omitempty"`
+	// SnapshotRevision is the etcd revision of the snapshot, as reported by its verification.
+	SnapshotRevision int64 `json:"snapshotRevision,omitempty"`
+	// SnapshotKeyCount is the total number of keys in the snapshot, as reported by its verification.
+	SnapshotKeyCount int64 `json:"snapshotKeyCount,omitempty"`
+	// SnapshotHash is the hash of the snapshot, as reported by its verification.
+	SnapshotHash int64 `json:"snapshotHash,omitempty"`
+	// RetainedBy lists the tiers of the retention policy that currently retain this backup.
+	// +optional
+	RetainedBy []EtcdBackupRetentionTier `json:"retainedBy,omitempty"`
 }
 
 type EtcdBackupConfigCondition struct {
@@ -134,7 +208,7 @@ type EtcdBackupConfigCondition struct {
 	Message string `json:"message,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=SchedulingActive
+// +kubebuilder:validation:Enum=SchedulingActive;BackupVerified
 
 // EtcdBackupConfigConditionType is used to indicate the type of a EtcdBackupConfig condition. For all condition
 // types, the `true` value must indicate success. All condition types must be registered within
@@ -145,9 +219,21 @@ const (
 	// EtcdBackupConfigConditionSchedulingActive indicates that the EtcdBackupConfig is active, i.e.
 	// new backups are being scheduled according to the config's schedule.
 	EtcdBackupConfigConditionSchedulingActive EtcdBackupConfigConditionType = "SchedulingActive"
+
+	// EtcdBackupConfigConditionBackupVerified indicates whether the most recently verified backup
+	// could be downloaded and restored successfully. It is only set if verification is enabled.
+	EtcdBackupConfigConditionBackupVerified EtcdBackupConfigConditionType = "BackupVerified"
 )
 
+// IsVerificationEnabled returns true if completed backups should be verified.
+func (bc *EtcdBackupConfig) IsVerificationEnabled() bool {
+	return bc.Spec.Verification != nil && bc.Spec.Verification.Enabled
+}
+
 func (bc *EtcdBackupConfig) GetKeptBackupsCount() int {
+	if bc.HasRetentionPolicy() {
+		return bc.Spec.Retention.Total()
+	}
 	if bc.Spec.Keep == nil {
 		return DefaultKeptBackupsCount
 	}
@@ -159,3 +245,9 @@ func (bc *EtcdBackupConfig) GetKeptBackupsCount() int {
 	}
 	return *bc.Spec.Keep
 }
+
+// HasRetentionPolicy returns true if backups are kept according to the tiered retention
+// policy instead of the plain Keep count.
+func (bc *EtcdBackupConfig) HasRetentionPolicy() bool {
+	return bc.Spec.Retention.Total() > 0
+}
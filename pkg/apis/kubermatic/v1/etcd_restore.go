@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -71,6 +73,14 @@ type EtcdRestoreSpec struct {
 	Cluster corev1.ObjectReference `json:"cluster"`
 	// BackupName is the name of the backup to restore from
 	BackupName string `json:"backupName"`
+	// SourceCluster is the name of the cluster the backup was taken from. If set to a cluster other than
+	// Cluster, the backup is cloned into Cluster: the restored etcd keeps the certificates and namespace of
+	// Cluster, its MachineDeployments are scaled to zero and the Machines and Nodes of the source cluster are
+	// removed without touching the underlying instances. Cluster should be a new cluster. The source cluster
+	// must still exist, belong to the same project as Cluster and use the same cloud provider and minor
+	// Kubernetes version.
+	// +optional
+	SourceCluster string `json:"sourceCluster,omitempty"`
 	// BackupDownloadCredentialsSecret is the name of a secret in the cluster-xxx namespace containing
 	// credentials needed to download the backup
 	BackupDownloadCredentialsSecret string `json:"backupDownloadCredentialsSecret,omitempty"`
@@ -95,3 +105,19 @@ type EtcdRestoreStatus struct {
 	// +optional
 	RestoreTime metav1.Time `json:"restoreTime,omitempty"`
 }
+
+// IsClone returns true if the restore materializes the backup of another cluster.
+func (r *EtcdRestore) IsClone() bool {
+	return r.Spec.SourceCluster != "" && r.Spec.SourceCluster != r.Spec.Cluster.Name
+}
+
+// BackupObjectName returns the name of the object in the backup destination
+// that holds the backup to restore.
+func (r *EtcdRestore) BackupObjectName() string {
+	cluster := r.Spec.Cluster.Name
+	if r.Spec.SourceCluster != "" {
+		cluster = r.Spec.SourceCluster
+	}
+
+	return fmt.Sprintf("%s-%s", cluster, r.Spec.BackupName)
+}
@@ -328,6 +328,41 @@ func SetSeedCondition(seed *kubermaticv1.Seed, conditionType kubermaticv1.SeedCo
 	seed.Status.Conditions[conditionType] = newCondition
 }
 
+// SetResourceQuotaCondition sets a condition on the given resource quota using the provided
+// type, status, reason and message.
+func SetResourceQuotaCondition(rq *kubermaticv1.ResourceQuota, conditionType kubermaticv1.ResourceQuotaConditionType, status corev1.ConditionStatus, reason string, message string) {
+	newCondition := kubermaticv1.ResourceQuotaCondition{
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+
+	oldCondition, hadCondition := rq.Status.Conditions[conditionType]
+	if hadCondition {
+		conditionCopy := oldCondition.DeepCopy()
+
+		// Reset the times before comparing
+		conditionCopy.LastHeartbeatTime.Reset()
+		conditionCopy.LastTransitionTime.Reset()
+
+		if apiequality.Semantic.DeepEqual(*conditionCopy, newCondition) {
+			return
+		}
+	}
+
+	now := metav1.Now()
+	newCondition.LastHeartbeatTime = now
+	newCondition.LastTransitionTime = oldCondition.LastTransitionTime
+	if hadCondition && oldCondition.Status != status {
+		newCondition.LastTransitionTime = now
+	}
+
+	if rq.Status.Conditions == nil {
+		rq.Status.Conditions = map[kubermaticv1.ResourceQuotaConditionType]kubermaticv1.ResourceQuotaCondition{}
+	}
+	rq.Status.Conditions[conditionType] = newCondition
+}
+
 type ResourceQuotaPatchFunc func(resourceQuota *kubermaticv1.ResourceQuota)
 
 // UpdateResourceQuotaStatus will attempt to patch the resource quota status
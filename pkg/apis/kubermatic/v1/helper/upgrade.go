@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"fmt"
+	"strings"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
+)
+
+// maxListedObjects is the number of objects per API that are mentioned in messages.
+const maxListedObjects = 5
+
+// RemovedAPIsInUse returns the deprecated APIs that are still in use in the cluster and that are
+// removed by a Kubernetes release after from, up to and including to. This is empty if the cluster
+// has not been scanned for deprecated APIs yet.
+//
+// Only APIs with stored objects are considered. APIs that were merely requested according to the
+// apiserver metrics are ignored, as the metrics cannot tell apart actual clients from the scan
+// itself, which has to request the APIs in order to find their objects.
+func RemovedAPIsInUse(cluster *kubermaticv1.Cluster, from, to *semver.Semver) []kubermaticv1.DeprecatedAPIUsage {
+	if cluster.Status.DeprecatedAPIs == nil || from == nil || to == nil {
+		return nil
+	}
+
+	// compare against the minor releases, so that 1.25 is considered to be removed in 1.25.3
+	fromMinor, err := semver.NewSemver(from.MajorMinor())
+	if err != nil {
+		return nil
+	}
+
+	toMinor, err := semver.NewSemver(to.MajorMinor())
+	if err != nil {
+		return nil
+	}
+
+	var result []kubermaticv1.DeprecatedAPIUsage
+	for _, usage := range cluster.Status.DeprecatedAPIs.Usages {
+		if usage.ObjectCount == 0 && len(usage.Objects) == 0 {
+			continue
+		}
+
+		removedIn, err := semver.NewSemver(usage.RemovedInVersion)
+		if err != nil {
+			continue
+		}
+
+		if removedIn.GreaterThan(fromMinor) && !removedIn.GreaterThan(toMinor) {
+			result = append(result, usage)
+		}
+	}
+
+	return result
+}
+
+// RemovedAPIsMessage returns a human readable description of the given removed APIs,
+// suitable for a condition message.
+func RemovedAPIsMessage(usages []kubermaticv1.DeprecatedAPIUsage, target *semver.Semver) string {
+	descriptions := make([]string, 0, len(usages))
+
+	for _, usage := range usages {
+		details := []string{fmt.Sprintf("removed in %s", usage.RemovedInVersion)}
+
+		objects := usage.Objects
+		if len(objects) > maxListedObjects {
+			objects = objects[:maxListedObjects]
+		}
+
+		if len(objects) > 0 {
+			listed := strings.Join(objects, ", ")
+
+			total := usage.ObjectCount
+			if total < len(usage.Objects) {
+				total = len(usage.Objects)
+			}
+			if more := total - len(objects); more > 0 {
+				listed = fmt.Sprintf("%s and %d more", listed, more)
+			}
+
+			details = append(details, listed)
+		}
+
+		if usage.Requested {
+			details = append(details, "requested by clients")
+		}
+
+		descriptions = append(descriptions, fmt.Sprintf("%s %s (%s)", usage.GroupVersion(), usage.Resource, strings.Join(details, "; ")))
+	}
+
+	return fmt.Sprintf("APIs that are no longer served by Kubernetes %s are still in use: %s.", target.MajorMinor(), strings.Join(descriptions, ", "))
+}
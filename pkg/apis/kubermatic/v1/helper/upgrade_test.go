@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
+)
+
+func TestRemovedAPIsInUse(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{
+		Status: kubermaticv1.ClusterStatus{
+			DeprecatedAPIs: &kubermaticv1.DeprecatedAPIsStatus{
+				Usages: []kubermaticv1.DeprecatedAPIUsage{
+					{Group: "policy", Version: "v1beta1", Resource: "podsecuritypolicies", RemovedInVersion: "1.25", Objects: []string{"a", "b"}, ObjectCount: 12},
+					{Group: "autoscaling", Version: "v2beta2", Resource: "horizontalpodautoscalers", RemovedInVersion: "1.26", Objects: []string{"app (Helm release apps/app)"}, ObjectCount: 1, Requested: true},
+					// requests alone do not block upgrades
+					{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Resource: "flowschemas", RemovedInVersion: "1.26", Requested: true},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		expected []string
+	}{
+		{
+			name:     "patch release",
+			from:     "1.24.3",
+			to:       "1.24.10",
+			expected: nil,
+		},
+		{
+			name:     "next minor release",
+			from:     "1.24.10",
+			to:       "1.25.0",
+			expected: []string{"podsecuritypolicies"},
+		},
+		{
+			name:     "multiple minor releases",
+			from:     "1.24.10",
+			to:       "1.26.4",
+			expected: []string{"podsecuritypolicies", "horizontalpodautoscalers"},
+		},
+		{
+			name:     "already removed",
+			from:     "1.25.2",
+			to:       "1.26.0",
+			expected: []string{"horizontalpodautoscalers"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			removed := RemovedAPIsInUse(cluster, semver.NewSemverOrDie(tc.from), semver.NewSemverOrDie(tc.to))
+			if len(removed) != len(tc.expected) {
+				t.Fatalf("Expected %v, but got %+v", tc.expected, removed)
+			}
+
+			for i, usage := range removed {
+				if usage.Resource != tc.expected[i] {
+					t.Fatalf("Expected %v, but got %+v", tc.expected, removed)
+				}
+			}
+		})
+	}
+
+	removed := RemovedAPIsInUse(cluster, semver.NewSemverOrDie("1.24.0"), semver.NewSemverOrDie("1.26.0"))
+	expected := "APIs that are no longer served by Kubernetes 1.26 are still in use: policy/v1beta1 podsecuritypolicies (removed in 1.25; a, b and 10 more), autoscaling/v2beta2 horizontalpodautoscalers (removed in 1.26; app (Helm release apps/app); requested by clients)."
+
+	if message := RemovedAPIsMessage(removed, semver.NewSemverOrDie("1.26.0")); message != expected {
+		t.Fatalf("Expected message\n%s\nbut got\n%s", expected, message)
+	}
+}
@@ -54,6 +54,21 @@ type IPAMAllocationSpec struct {
 	// Addresses are the IP address ranges that are being used for the allocation.
 	// Set when "type=range".
 	Addresses []string `json:"addresses,omitempty"`
+	// Reservations are the static addresses that are reserved for the cluster
+	// in the IPAMPool, keyed by reservation name.
+	Reservations map[string]string `json:"reservations,omitempty"`
+	// DualStack is the allocation from the second pool CIDR of a dual-stack IPAMPool.
+	DualStack *IPAMAllocationDualStack `json:"dualStack,omitempty"`
+}
+
+// IPAMAllocationDualStack is the allocation for the second IP family of a dual-stack IPAMPool.
+type IPAMAllocationDualStack struct {
+	// CIDR is the CIDR that is being used for the allocation.
+	// Set when "type=prefix".
+	CIDR SubnetCIDR `json:"cidr,omitempty"`
+	// Addresses are the IP address ranges that are being used for the allocation.
+	// Set when "type=range".
+	Addresses []string `json:"addresses,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
@@ -47,6 +47,30 @@ type IPAMPool struct {
 type IPAMPoolSpec struct {
 	// Datacenters contains a map of datacenters (DCs) for the allocation.
 	Datacenters map[string]IPAMPoolDatacenterSettings `json:"datacenters"`
+
+	// Optional: ProjectSelector restricts the pool to clusters of projects whose labels
+	// match the selector. If not set, clusters of all projects get an allocation.
+	// Existing allocations are not removed when the selector changes.
+	ProjectSelector *metav1.LabelSelector `json:"projectSelector,omitempty"`
+
+	// +kubebuilder:validation:Minimum:=0
+	// Optional: ProjectQuota is the maximum number of clusters of a single project that
+	// can get an allocation from this pool, across all datacenters. If not set, the
+	// number of allocations per project is not limited.
+	ProjectQuota *int `json:"projectQuota,omitempty"`
+
+	// Optional: ProjectQuotas overrides ProjectQuota for particular projects, keyed by project ID.
+	ProjectQuotas map[string]int `json:"projectQuotas,omitempty"`
+}
+
+// QuotaForProject returns the maximum number of allocations for the given project,
+// or nil if the project is not limited.
+func (s *IPAMPoolSpec) QuotaForProject(projectID string) *int {
+	if quota, ok := s.ProjectQuotas[projectID]; ok {
+		return &quota
+	}
+
+	return s.ProjectQuota
 }
 
 // IPAMPoolDatacenterSettings contains IPAM Pool configuration for a datacenter.
@@ -75,10 +99,62 @@ type IPAMPoolDatacenterSettings struct {
 
 	// Optional: ExcludeRanges is used to exclude particular IPs or IP ranges for the allocation.
 	// Examples: "192.168.1.100-192.168.1.110", "192.168.1.255".
-	// Can be used when "type=range".
+	// Can be used when "type=range". The ranges must be part of the pool CIDR.
+	ExcludeRanges []string `json:"excludeRanges,omitempty"`
+
+	// Optional: Reservations are named static addresses that are never handed out by the
+	// regular allocation. A reservation for a cluster is added to the allocation of that cluster.
+	Reservations []IPAMPoolReservation `json:"reservations,omitempty"`
+
+	// Optional: DualStack configures a second pool CIDR of the other IP family. Every cluster
+	// then gets a pair of allocations of the same type, one from each pool CIDR.
+	DualStack *IPAMPoolDualStackSettings `json:"dualStack,omitempty"`
+}
+
+// IPAMPoolDualStackSettings contains the configuration for the second IP family of a dual-stack IPAM pool.
+type IPAMPoolDualStackSettings struct {
+	// PoolCIDR is the pool CIDR to be used for the allocation. It must be of
+	// the other IP family than the pool CIDR of the datacenter.
+	PoolCIDR SubnetCIDR `json:"poolCidr"`
+
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=128
+	// AllocationPrefix is the prefix for the allocation.
+	// Used when "type=prefix".
+	AllocationPrefix int `json:"allocationPrefix,omitempty"`
+
+	// Optional: ExcludePrefixes is used to exclude particular subnets for the allocation.
+	// NOTE: must be the same length as allocationPrefix.
+	// Can be used when "type=prefix".
+	ExcludePrefixes []SubnetCIDR `json:"excludePrefixes,omitempty"`
+
+	// +kubebuilder:validation:Minimum:=1
+	// AllocationRange is the range for the allocation.
+	// Used when "type=range".
+	AllocationRange int `json:"allocationRange,omitempty"`
+
+	// Optional: ExcludeRanges is used to exclude particular IPs or IP ranges for the allocation.
+	// Examples: "fd00::100-fd00::110", "fd00::ff".
+	// Can be used when "type=range". The ranges must be part of the pool CIDR.
 	ExcludeRanges []string `json:"excludeRanges,omitempty"`
 }
 
+// IPAMPoolReservation is a named static address in an IPAM pool.
+type IPAMPoolReservation struct {
+	// Name identifies the reservation within the datacenter, e.g. "ingress-vip".
+	// It must be a DNS-1123 label, as addons create objects named after it.
+	Name string `json:"name"`
+
+	// Address is the reserved IP or IP range (e.g. "192.168.1.10" or "192.168.1.10-192.168.1.12")
+	// when "type=range", or the reserved subnet CIDR when "type=prefix". For dual-stack pools,
+	// the address can be of either IP family.
+	// NOTE: a reserved subnet must be the same length as the allocationPrefix of its IP family.
+	Address string `json:"address"`
+
+	// Optional: Cluster is the name of the cluster the address is reserved for.
+	Cluster string `json:"cluster,omitempty"`
+}
+
 // +kubebuilder:validation:Pattern="((^((([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\\.){3}([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5]))/([0-9]|[1-2][0-9]|3[0-2])$)|(^(([0-9a-fA-F]{1,4}:){7,7}[0-9a-fA-F]{1,4}|([0-9a-fA-F]{1,4}:){1,7}:|([0-9a-fA-F]{1,4}:){1,6}:[0-9a-fA-F]{1,4}|([0-9a-fA-F]{1,4}:){1,5}(:[0-9a-fA-F]{1,4}){1,2}|([0-9a-fA-F]{1,4}:){1,4}(:[0-9a-fA-F]{1,4}){1,3}|([0-9a-fA-F]{1,4}:){1,3}(:[0-9a-fA-F]{1,4}){1,4}|([0-9a-fA-F]{1,4}:){1,2}(:[0-9a-fA-F]{1,4}){1,5}|[0-9a-fA-F]{1,4}:((:[0-9a-fA-F]{1,4}){1,6})|:((:[0-9a-fA-F]{1,4}){1,7}|:))/([0-9]|[0-9][0-9]|1[0-1][0-9]|12[0-8])$))"
 // SubnetCIDR is used to store IPv4/IPv6 CIDR.
 type SubnetCIDR string
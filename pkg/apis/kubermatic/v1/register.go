@@ -105,6 +105,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ResourceQuotaList{},
 		&GroupProjectBinding{},
 		&GroupProjectBindingList{},
+		&UpgradePlan{},
+		&UpgradePlanList{},
+		&ComplianceReport{},
+		&ComplianceReportList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -54,6 +55,27 @@ type ResourceQuotaSpec struct {
 	Subject Subject `json:"subject"`
 	// Quota specifies the current maximum allowed usage of resources.
 	Quota ResourceDetails `json:"quota"`
+	// WarningThresholds are percentages of the quota (e.g. 80 and 95). Whenever the global
+	// usage of a resource crosses one of them, KKP emits an event on the subject, sets the
+	// ThresholdReached condition and calls the notification webhook, if configured.
+	// +optional
+	WarningThresholds []ResourceQuotaThreshold `json:"warningThresholds,omitempty"`
+	// Notification configures how teams are notified when a warning threshold is crossed.
+	// +optional
+	Notification *ResourceQuotaNotification `json:"notification,omitempty"`
+}
+
+// +kubebuilder:validation:Minimum=1
+// +kubebuilder:validation:Maximum=100
+
+// ResourceQuotaThreshold is a percentage of a quota.
+type ResourceQuotaThreshold int
+
+// ResourceQuotaNotification configures notifications for crossed warning thresholds.
+type ResourceQuotaNotification struct {
+	// WebhookURL is called with a HTTP POST request containing a JSON document that
+	// describes the crossed threshold.
+	WebhookURL string `json:"webhookURL"`
 }
 
 // ResourceQuotaStatus describes the current state of a resource quota.
@@ -62,6 +84,40 @@ type ResourceQuotaStatus struct {
 	GlobalUsage ResourceDetails `json:"globalUsage,omitempty"`
 	// LocalUsage is holds the current usage of resources for the local seed.
 	LocalUsage ResourceDetails `json:"localUsage,omitempty"`
+	// ReachedThresholds maps resource names (e.g. "cpu" or "clusters") to the
+	// highest warning threshold their global usage has reached.
+	ReachedThresholds map[string]ResourceQuotaThreshold `json:"reachedThresholds,omitempty"`
+	// NotifiedThresholds maps resource names to the highest reached warning threshold
+	// that has been successfully delivered to the notification webhook.
+	NotifiedThresholds map[string]ResourceQuotaThreshold `json:"notifiedThresholds,omitempty"`
+	// Conditions contains conditions about the usage of the quota.
+	Conditions map[ResourceQuotaConditionType]ResourceQuotaCondition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=ThresholdReached
+
+type ResourceQuotaConditionType string
+
+const (
+	// ResourceQuotaConditionThresholdReached is true if the global usage of at
+	// least one resource has reached one of the warning thresholds.
+	ResourceQuotaConditionThresholdReached ResourceQuotaConditionType = "ThresholdReached"
+)
+
+type ResourceQuotaCondition struct {
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time we got an update on a given condition.
+	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime"`
+	// Last time the condition transit from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // Subject describes the entity to which the quota applies to.
@@ -76,7 +132,8 @@ type Subject struct {
 	Kind string `json:"kind"`
 }
 
-// ResourceDetails holds the CPU, Memory and Storage quantities.
+// ResourceDetails holds the CPU, Memory and Storage quantities as well as
+// the number of countable objects like clusters or load balancers.
 type ResourceDetails struct {
 	// CPU holds the quantity of CPU. For the format, please check k8s.io/apimachinery/pkg/api/resource.Quantity.
 	CPU *resource.Quantity `json:"cpu,omitempty"`
@@ -84,10 +141,77 @@ type ResourceDetails struct {
 	Memory *resource.Quantity `json:"memory,omitempty"`
 	// Storage represents the disk size. For the format, please check k8s.io/apimachinery/pkg/api/resource.Quantity.
 	Storage *resource.Quantity `json:"storage,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// Clusters is the number of user clusters.
+	Clusters *int64 `json:"clusters,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// MachineDeployments is the number of MachineDeployments in all user clusters.
+	MachineDeployments *int64 `json:"machineDeployments,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// Nodes is the number of Machines (and thereby nodes) in all user clusters.
+	Nodes *int64 `json:"nodes,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// LoadBalancers is the number of Services of type LoadBalancer in all user clusters.
+	LoadBalancers *int64 `json:"loadBalancers,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+
+	// PersistentVolumes is the number of PersistentVolumes in all user clusters.
+	PersistentVolumes *int64 `json:"persistentVolumes,omitempty"`
 }
 
 func (r ResourceDetails) IsEmpty() bool {
-	return (r.CPU == nil || r.CPU.IsZero()) && (r.Memory == nil || r.Memory.IsZero()) && (r.Storage == nil || r.Storage.IsZero())
+	return (r.CPU == nil || r.CPU.IsZero()) && (r.Memory == nil || r.Memory.IsZero()) && (r.Storage == nil || r.Storage.IsZero()) &&
+		isZeroCount(r.Clusters) && isZeroCount(r.MachineDeployments) && isZeroCount(r.Nodes) &&
+		isZeroCount(r.LoadBalancers) && isZeroCount(r.PersistentVolumes)
+}
+
+// Add adds the quantities and counts of other to r. Fields that are nil in
+// both r and other are left untouched.
+func (r *ResourceDetails) Add(other ResourceDetails) {
+	r.CPU = addQuantity(r.CPU, other.CPU)
+	r.Memory = addQuantity(r.Memory, other.Memory)
+	r.Storage = addQuantity(r.Storage, other.Storage)
+	r.Clusters = addCount(r.Clusters, other.Clusters)
+	r.MachineDeployments = addCount(r.MachineDeployments, other.MachineDeployments)
+	r.Nodes = addCount(r.Nodes, other.Nodes)
+	r.LoadBalancers = addCount(r.LoadBalancers, other.LoadBalancers)
+	r.PersistentVolumes = addCount(r.PersistentVolumes, other.PersistentVolumes)
+}
+
+func isZeroCount(c *int64) bool {
+	return c == nil || *c == 0
+}
+
+func addQuantity(a, b *resource.Quantity) *resource.Quantity {
+	if b == nil {
+		return a
+	}
+	if a == nil {
+		sum := b.DeepCopy()
+		return &sum
+	}
+	a.Add(*b)
+	return a
+}
+
+func addCount(a, b *int64) *int64 {
+	if b == nil {
+		return a
+	}
+	sum := *b
+	if a != nil {
+		sum += *a
+	}
+	return &sum
 }
 
 // +kubebuilder:object:generate=true
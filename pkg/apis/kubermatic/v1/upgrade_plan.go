@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	"k8c.io/kubermatic/v2/pkg/semver"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// UpgradePlanResourceName represents "Resource" defined in Kubernetes.
+	UpgradePlanResourceName = "upgradeplans"
+
+	// UpgradePlanKindName represents "Kind" defined in Kubernetes.
+	UpgradePlanKindName = "UpgradePlan"
+)
+
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".spec.version",name="Version",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name="Phase",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.currentWave",name="Wave",type="integer"
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type="date"
+
+// UpgradePlan rolls out a Kubernetes version to a set of user clusters in
+// consecutive waves (for example canary, early adopters and the rest), waiting
+// for each wave to be healthy for a while before continuing with the next one.
+// Clusters selected by an UpgradePlan are not upgraded by the automatic update
+// rules of the KubermaticConfiguration until the plan has completed.
+type UpgradePlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UpgradePlanSpec   `json:"spec,omitempty"`
+	Status UpgradePlanStatus `json:"status,omitempty"`
+}
+
+// UpgradePlanSpec specifies which clusters to upgrade and how.
+type UpgradePlanSpec struct {
+	// ClusterSelector selects the clusters that are upgraded by this plan.
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+	// Version is the Kubernetes version the selected clusters are upgraded to. Clusters
+	// that already run this or a newer version are skipped. The version must be
+	// reachable according to the update rules in the KubermaticConfiguration.
+	Version semver.Semver `json:"version"`
+	// Waves are rolled out one after another. Clusters are assigned to waves in
+	// alphabetical order; clusters that do not fit into any wave are upgraded
+	// together with the last wave.
+	// +kubebuilder:validation:MinItems=1
+	Waves []UpgradePlanWave `json:"waves"`
+	// HealthTimeout is the time a cluster has to reach the target version and be
+	// healthy after its upgrade has been started. Clusters that take longer are
+	// considered failed. Defaults to 30m.
+	// +optional
+	HealthTimeout *metav1.Duration `json:"healthTimeout,omitempty"`
+	// Paused stops the plan from starting any further cluster upgrades. Upgrades
+	// that are already in progress are not affected.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// UpgradePlanWave is a group of clusters that are upgraded at the same time.
+type UpgradePlanWave struct {
+	// Name is a human readable name for the wave, like "canary".
+	Name string `json:"name"`
+	// Size is the number of clusters in this wave. A size of 0 puts all
+	// remaining clusters into this wave.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Size int `json:"size,omitempty"`
+	// SoakTime is the time to wait after all clusters of this wave have been
+	// upgraded and before the next wave is started. If any cluster of the wave
+	// becomes unhealthy during this time, the plan is paused.
+	// +optional
+	SoakTime metav1.Duration `json:"soakTime,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Progressing;Soaking;Paused;Completed
+
+// UpgradePlanPhase is the phase of the whole rollout.
+type UpgradePlanPhase string
+
+const (
+	// UpgradePlanPhaseProgressing means that clusters of the current wave are being upgraded.
+	UpgradePlanPhaseProgressing UpgradePlanPhase = "Progressing"
+	// UpgradePlanPhaseSoaking means that the current wave has been upgraded and
+	// the plan is waiting for its soak time to pass.
+	UpgradePlanPhaseSoaking UpgradePlanPhase = "Soaking"
+	// UpgradePlanPhasePaused means that no further upgrades are started, either
+	// because the plan was paused manually or because a cluster failed.
+	UpgradePlanPhasePaused UpgradePlanPhase = "Paused"
+	// UpgradePlanPhaseCompleted means that all waves have been rolled out.
+	UpgradePlanPhaseCompleted UpgradePlanPhase = "Completed"
+)
+
+// +kubebuilder:validation:Enum=Pending;Upgrading;Upgraded;Failed;Skipped
+
+// UpgradePlanClusterPhase is the phase of a single cluster within an UpgradePlan.
+type UpgradePlanClusterPhase string
+
+const (
+	// UpgradePlanClusterPhasePending means that the cluster's wave has not been started yet,
+	// or that the cluster is waiting for its update window.
+	UpgradePlanClusterPhasePending UpgradePlanClusterPhase = "Pending"
+	// UpgradePlanClusterPhaseUpgrading means that the cluster's version has been changed
+	// and the plan waits for the control plane to be updated and healthy.
+	UpgradePlanClusterPhaseUpgrading UpgradePlanClusterPhase = "Upgrading"
+	// UpgradePlanClusterPhaseUpgraded means that the cluster runs the target version and is healthy.
+	UpgradePlanClusterPhaseUpgraded UpgradePlanClusterPhase = "Upgraded"
+	// UpgradePlanClusterPhaseFailed means that the cluster did not become healthy in time, its
+	// update failed or it became unhealthy while its wave was soaking. A failed cluster pauses
+	// the plan until it reaches the target version and is healthy again.
+	UpgradePlanClusterPhaseFailed UpgradePlanClusterPhase = "Failed"
+	// UpgradePlanClusterPhaseSkipped means that the cluster already ran the target version
+	// (or a newer one) when it was selected by the plan.
+	UpgradePlanClusterPhaseSkipped UpgradePlanClusterPhase = "Skipped"
+)
+
+// UpgradePlanStatus records the progress of the rollout.
+type UpgradePlanStatus struct {
+	// +optional
+	Phase UpgradePlanPhase `json:"phase,omitempty"`
+	// CurrentWave is the index of the wave that is currently rolled out.
+	// +optional
+	CurrentWave int `json:"currentWave"`
+	// WaveCompletionTime is the time when all clusters of the current wave were upgraded.
+	// +optional
+	WaveCompletionTime *metav1.Time `json:"waveCompletionTime,omitempty"`
+	// Message is a human readable explanation of the current phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Clusters contains the progress of every selected cluster, keyed by the cluster name.
+	// +optional
+	Clusters map[string]UpgradePlanClusterStatus `json:"clusters,omitempty"`
+}
+
+// UpgradePlanClusterStatus is the progress of a single cluster.
+type UpgradePlanClusterStatus struct {
+	// Wave is the index of the wave the cluster belongs to.
+	Wave  int                     `json:"wave"`
+	Phase UpgradePlanClusterPhase `json:"phase"`
+	// FromVersion is the version the cluster was running before the upgrade.
+	// +optional
+	FromVersion *semver.Semver `json:"fromVersion,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SelectsCluster returns true if the cluster is selected by the plan's cluster selector.
+func (p *UpgradePlan) SelectsCluster(cluster *Cluster) (bool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&p.Spec.ClusterSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid cluster selector: %w", err)
+	}
+
+	return selector.Matches(labels.Set(cluster.Labels)), nil
+}
+
+// +kubebuilder:object:generate=true
+// +kubebuilder:object:root=true
+
+// UpgradePlanList is a list of upgrade plans.
+type UpgradePlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []UpgradePlan `json:"items"`
+}
@@ -25,7 +25,7 @@ import (
 )
 
 // AllExposeStrategies is a set containing all the ExposeStrategy.
-var AllExposeStrategies = NewExposeStrategiesSet(ExposeStrategyNodePort, ExposeStrategyLoadBalancer, ExposeStrategyTunneling)
+var AllExposeStrategies = NewExposeStrategiesSet(ExposeStrategyNodePort, ExposeStrategyLoadBalancer, ExposeStrategyTunneling, ExposeStrategyGatewayAPI)
 
 // ExposeStrategyFromString returns the expose strategy which String
 // representation corresponds to the input string, and a bool saying whether a
@@ -136,6 +136,21 @@ func (in *Addon) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonAppliedResource) DeepCopyInto(out *AddonAppliedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonAppliedResource.
+func (in *AddonAppliedResource) DeepCopy() *AddonAppliedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonAppliedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonCondition) DeepCopyInto(out *AddonCondition) {
 	*out = *in
@@ -219,6 +234,16 @@ func (in *AddonConfigSpec) DeepCopyInto(out *AddonConfigSpec) {
 		*out = make([]AddonFormControl, len(*in))
 		copy(*out, *in)
 	}
+	if in.HealthProbes != nil {
+		in, out := &in.HealthProbes, &out.HealthProbes
+		*out = make([]AddonHealthProbe, len(*in))
+		copy(*out, *in)
+	}
+	if in.VariablesSchema != nil {
+		in, out := &in.VariablesSchema, &out.VariablesSchema
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonConfigSpec.
@@ -246,6 +271,22 @@ func (in *AddonFormControl) DeepCopy() *AddonFormControl {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonHealthProbe) DeepCopyInto(out *AddonHealthProbe) {
+	*out = *in
+	out.Port = in.Port
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonHealthProbe.
+func (in *AddonHealthProbe) DeepCopy() *AddonHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonList) DeepCopyInto(out *AddonList) {
 	*out = *in
@@ -314,6 +355,11 @@ func (in *AddonStatus) DeepCopyInto(out *AddonStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.AppliedResources != nil {
+		in, out := &in.AppliedResources, &out.AppliedResources
+		*out = make([]AddonAppliedResource, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonStatus.
@@ -823,6 +869,11 @@ func (in *BackupDestination) DeepCopyInto(out *BackupDestination) {
 		*out = new(corev1.SecretReference)
 		**out = **in
 	}
+	if in.EncryptionKey != nil {
+		in, out := &in.EncryptionKey, &out.EncryptionKey
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDestination.
@@ -843,6 +894,12 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 	in.BackupFinishedTime.DeepCopyInto(&out.BackupFinishedTime)
 	in.DeleteStartTime.DeepCopyInto(&out.DeleteStartTime)
 	in.DeleteFinishedTime.DeepCopyInto(&out.DeleteFinishedTime)
+	in.VerifyFinishedTime.DeepCopyInto(&out.VerifyFinishedTime)
+	if in.RetainedBy != nil {
+		in, out := &in.RetainedBy, &out.RetainedBy
+		*out = make([]EtcdBackupRetentionTier, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -1068,6 +1125,33 @@ func (in *ClusterCondition) DeepCopy() *ClusterCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeletionStatus) DeepCopyInto(out *ClusterDeletionStatus) {
+	*out = *in
+	if in.PendingFinalizers != nil {
+		in, out := &in.PendingFinalizers, &out.PendingFinalizers
+		*out = make([]PendingFinalizer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OrphanedResources != nil {
+		in, out := &in.OrphanedResources, &out.OrphanedResources
+		*out = make([]OrphanedResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeletionStatus.
+func (in *ClusterDeletionStatus) DeepCopy() *ClusterDeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterEncryptionStatus) DeepCopyInto(out *ClusterEncryptionStatus) {
 	*out = *in
@@ -1251,6 +1335,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(OPAIntegrationSettings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ComplianceScanning != nil {
+		in, out := &in.ComplianceScanning, &out.ComplianceScanning
+		*out = new(ComplianceScanningSettings)
+		**out = **in
+	}
 	if in.ServiceAccount != nil {
 		in, out := &in.ServiceAccount, &out.ServiceAccount
 		*out = new(ServiceAccountSettings)
@@ -1271,6 +1360,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(EncryptionConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ForceDeletion != nil {
+		in, out := &in.ForceDeletion, &out.ForceDeletion
+		*out = new(ForceDeletionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -1325,6 +1419,16 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = new(ResourceDetails)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeprecatedAPIs != nil {
+		in, out := &in.DeprecatedAPIs, &out.DeprecatedAPIs
+		*out = new(DeprecatedAPIsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(ClusterDeletionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -1526,6 +1630,159 @@ func (in *ClusterVersionsStatus) DeepCopy() *ClusterVersionsStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceCheckResult) DeepCopyInto(out *ComplianceCheckResult) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceCheckResult.
+func (in *ComplianceCheckResult) DeepCopy() *ComplianceCheckResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceCheckResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceReport) DeepCopyInto(out *ComplianceReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceReport.
+func (in *ComplianceReport) DeepCopy() *ComplianceReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceReportList) DeepCopyInto(out *ComplianceReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ComplianceReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceReportList.
+func (in *ComplianceReportList) DeepCopy() *ComplianceReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComplianceReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceReportSpec) DeepCopyInto(out *ComplianceReportSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceReportSpec.
+func (in *ComplianceReportSpec) DeepCopy() *ComplianceReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceReportStatus) DeepCopyInto(out *ComplianceReportStatus) {
+	*out = *in
+	in.LastScanTime.DeepCopyInto(&out.LastScanTime)
+	out.Summary = in.Summary
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ComplianceCheckResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceReportStatus.
+func (in *ComplianceReportStatus) DeepCopy() *ComplianceReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceReportSummary) DeepCopyInto(out *ComplianceReportSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceReportSummary.
+func (in *ComplianceReportSummary) DeepCopy() *ComplianceReportSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceReportSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceScanningSettings) DeepCopyInto(out *ComplianceScanningSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceScanningSettings.
+func (in *ComplianceScanningSettings) DeepCopy() *ComplianceScanningSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceScanningSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentSettings) DeepCopyInto(out *ComponentSettings) {
 	*out = *in
@@ -2406,6 +2663,49 @@ func (in *DeploymentSettings) DeepCopy() *DeploymentSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeprecatedAPIUsage) DeepCopyInto(out *DeprecatedAPIUsage) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeprecatedAPIUsage.
+func (in *DeprecatedAPIUsage) DeepCopy() *DeprecatedAPIUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(DeprecatedAPIUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeprecatedAPIsStatus) DeepCopyInto(out *DeprecatedAPIsStatus) {
+	*out = *in
+	in.LastScanTime.DeepCopyInto(&out.LastScanTime)
+	if in.Usages != nil {
+		in, out := &in.Usages, &out.Usages
+		*out = make([]DeprecatedAPIUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeprecatedAPIsStatus.
+func (in *DeprecatedAPIsStatus) DeepCopy() *DeprecatedAPIsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeprecatedAPIsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Digitalocean) DeepCopyInto(out *Digitalocean) {
 	*out = *in
@@ -2595,6 +2895,16 @@ func (in *EtcdBackupConfigSpec) DeepCopyInto(out *EtcdBackupConfigSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(EtcdBackupRetention)
+		**out = **in
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(EtcdBackupVerification)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupConfigSpec.
@@ -2666,6 +2976,36 @@ func (in *EtcdBackupRestore) DeepCopy() *EtcdBackupRestore {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupRetention) DeepCopyInto(out *EtcdBackupRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupRetention.
+func (in *EtcdBackupRetention) DeepCopy() *EtcdBackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupVerification) DeepCopyInto(out *EtcdBackupVerification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupVerification.
+func (in *EtcdBackupVerification) DeepCopy() *EtcdBackupVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdRestore) DeepCopyInto(out *EtcdRestore) {
 	*out = *in
@@ -2908,6 +3248,11 @@ func (in *ExtendedClusterHealth) DeepCopyInto(out *ExtendedClusterHealth) {
 		*out = new(HealthStatus)
 		**out = **in
 	}
+	if in.Addons != nil {
+		in, out := &in.Addons, &out.Addons
+		*out = new(HealthStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedClusterHealth.
@@ -3290,6 +3635,22 @@ func (in *FakeCloudSpec) DeepCopy() *FakeCloudSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForceDeletionPolicy) DeepCopyInto(out *ForceDeletionPolicy) {
+	*out = *in
+	out.After = in.After
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForceDeletionPolicy.
+func (in *ForceDeletionPolicy) DeepCopy() *ForceDeletionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ForceDeletionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GCP) DeepCopyInto(out *GCP) {
 	*out = *in
@@ -3347,6 +3708,21 @@ func (in *GKE) DeepCopy() *GKE {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupProjectBinding) DeepCopyInto(out *GroupProjectBinding) {
 	*out = *in
@@ -3498,9 +3874,29 @@ func (in *IPAMAllocation) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPAMAllocationList) DeepCopyInto(out *IPAMAllocationList) {
+func (in *IPAMAllocationDualStack) DeepCopyInto(out *IPAMAllocationDualStack) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMAllocationDualStack.
+func (in *IPAMAllocationDualStack) DeepCopy() *IPAMAllocationDualStack {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAMAllocationDualStack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAMAllocationList) DeepCopyInto(out *IPAMAllocationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
@@ -3537,6 +3933,18 @@ func (in *IPAMAllocationSpec) DeepCopyInto(out *IPAMAllocationSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Reservations != nil {
+		in, out := &in.Reservations, &out.Reservations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DualStack != nil {
+		in, out := &in.DualStack, &out.DualStack
+		*out = new(IPAMAllocationDualStack)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMAllocationSpec.
@@ -3588,6 +3996,16 @@ func (in *IPAMPoolDatacenterSettings) DeepCopyInto(out *IPAMPoolDatacenterSettin
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Reservations != nil {
+		in, out := &in.Reservations, &out.Reservations
+		*out = make([]IPAMPoolReservation, len(*in))
+		copy(*out, *in)
+	}
+	if in.DualStack != nil {
+		in, out := &in.DualStack, &out.DualStack
+		*out = new(IPAMPoolDualStackSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMPoolDatacenterSettings.
@@ -3600,6 +4018,31 @@ func (in *IPAMPoolDatacenterSettings) DeepCopy() *IPAMPoolDatacenterSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAMPoolDualStackSettings) DeepCopyInto(out *IPAMPoolDualStackSettings) {
+	*out = *in
+	if in.ExcludePrefixes != nil {
+		in, out := &in.ExcludePrefixes, &out.ExcludePrefixes
+		*out = make([]SubnetCIDR, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeRanges != nil {
+		in, out := &in.ExcludeRanges, &out.ExcludeRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMPoolDualStackSettings.
+func (in *IPAMPoolDualStackSettings) DeepCopy() *IPAMPoolDualStackSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAMPoolDualStackSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAMPoolList) DeepCopyInto(out *IPAMPoolList) {
 	*out = *in
@@ -3632,6 +4075,21 @@ func (in *IPAMPoolList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAMPoolReservation) DeepCopyInto(out *IPAMPoolReservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMPoolReservation.
+func (in *IPAMPoolReservation) DeepCopy() *IPAMPoolReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAMPoolReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAMPoolSpec) DeepCopyInto(out *IPAMPoolSpec) {
 	*out = *in
@@ -3642,6 +4100,23 @@ func (in *IPAMPoolSpec) DeepCopyInto(out *IPAMPoolSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ProjectSelector != nil {
+		in, out := &in.ProjectSelector, &out.ProjectSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProjectQuota != nil {
+		in, out := &in.ProjectQuota, &out.ProjectQuota
+		*out = new(int)
+		**out = **in
+	}
+	if in.ProjectQuotas != nil {
+		in, out := &in.ProjectQuotas, &out.ProjectQuotas
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAMPoolSpec.
@@ -4879,6 +5354,11 @@ func (in *NodeportProxyConfig) DeepCopyInto(out *NodeportProxyConfig) {
 	in.Envoy.DeepCopyInto(&out.Envoy)
 	in.EnvoyManager.DeepCopyInto(&out.EnvoyManager)
 	in.Updater.DeepCopyInto(&out.Updater)
+	if in.RateLimits != nil {
+		in, out := &in.RateLimits, &out.RateLimits
+		*out = new(NodeportProxyRateLimits)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeportProxyConfig.
@@ -4891,6 +5371,21 @@ func (in *NodeportProxyConfig) DeepCopy() *NodeportProxyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeportProxyRateLimits) DeepCopyInto(out *NodeportProxyRateLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeportProxyRateLimits.
+func (in *NodeportProxyRateLimits) DeepCopy() *NodeportProxyRateLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeportProxyRateLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotificationsOptions) DeepCopyInto(out *NotificationsOptions) {
 	*out = *in
@@ -5215,6 +5710,21 @@ func (in OperatingSystemProfileList) DeepCopy() OperatingSystemProfileList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanedResource) DeepCopyInto(out *OrphanedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedResource.
+func (in *OrphanedResource) DeepCopy() *OrphanedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Packet) DeepCopyInto(out *Packet) {
 	*out = *in
@@ -5280,6 +5790,22 @@ func (in Parameters) DeepCopy() Parameters {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingFinalizer) DeepCopyInto(out *PendingFinalizer) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingFinalizer.
+func (in *PendingFinalizer) DeepCopy() *PendingFinalizer {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingFinalizer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreAllocatedDataVolume) DeepCopyInto(out *PreAllocatedDataVolume) {
 	*out = *in
@@ -5658,6 +6184,31 @@ func (in *ResourceDetails) DeepCopyInto(out *ResourceDetails) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MachineDeployments != nil {
+		in, out := &in.MachineDeployments, &out.MachineDeployments
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LoadBalancers != nil {
+		in, out := &in.LoadBalancers, &out.LoadBalancers
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PersistentVolumes != nil {
+		in, out := &in.PersistentVolumes, &out.PersistentVolumes
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDetails.
@@ -5697,6 +6248,23 @@ func (in *ResourceQuota) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaCondition) DeepCopyInto(out *ResourceQuotaCondition) {
+	*out = *in
+	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaCondition.
+func (in *ResourceQuotaCondition) DeepCopy() *ResourceQuotaCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceQuotaList) DeepCopyInto(out *ResourceQuotaList) {
 	*out = *in
@@ -5729,11 +6297,36 @@ func (in *ResourceQuotaList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaNotification) DeepCopyInto(out *ResourceQuotaNotification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaNotification.
+func (in *ResourceQuotaNotification) DeepCopy() *ResourceQuotaNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceQuotaSpec) DeepCopyInto(out *ResourceQuotaSpec) {
 	*out = *in
 	out.Subject = in.Subject
 	in.Quota.DeepCopyInto(&out.Quota)
+	if in.WarningThresholds != nil {
+		in, out := &in.WarningThresholds, &out.WarningThresholds
+		*out = make([]ResourceQuotaThreshold, len(*in))
+		copy(*out, *in)
+	}
+	if in.Notification != nil {
+		in, out := &in.Notification, &out.Notification
+		*out = new(ResourceQuotaNotification)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaSpec.
@@ -5751,6 +6344,27 @@ func (in *ResourceQuotaStatus) DeepCopyInto(out *ResourceQuotaStatus) {
 	*out = *in
 	in.GlobalUsage.DeepCopyInto(&out.GlobalUsage)
 	in.LocalUsage.DeepCopyInto(&out.LocalUsage)
+	if in.ReachedThresholds != nil {
+		in, out := &in.ReachedThresholds, &out.ReachedThresholds
+		*out = make(map[string]ResourceQuotaThreshold, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NotifiedThresholds != nil {
+		in, out := &in.NotifiedThresholds, &out.NotifiedThresholds
+		*out = make(map[string]ResourceQuotaThreshold, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(map[ResourceQuotaConditionType]ResourceQuotaCondition, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaStatus.
@@ -5948,6 +6562,22 @@ func (in *SeedCondition) DeepCopy() *SeedCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedGatewayAPIConfiguration) DeepCopyInto(out *SeedGatewayAPIConfiguration) {
+	*out = *in
+	out.Gateway = in.Gateway
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedGatewayAPIConfiguration.
+func (in *SeedGatewayAPIConfiguration) DeepCopy() *SeedGatewayAPIConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedGatewayAPIConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SeedList) DeepCopyInto(out *SeedList) {
 	*out = *in
@@ -6007,6 +6637,11 @@ func (in *SeedSpec) DeepCopyInto(out *SeedSpec) {
 		}
 	}
 	in.NodeportProxy.DeepCopyInto(&out.NodeportProxy)
+	if in.GatewayAPI != nil {
+		in, out := &in.GatewayAPI, &out.GatewayAPI
+		*out = new(SeedGatewayAPIConfiguration)
+		**out = **in
+	}
 	if in.ProxySettings != nil {
 		in, out := &in.ProxySettings, &out.ProxySettings
 		*out = new(ProxySettings)
@@ -6234,6 +6869,162 @@ func (in *UpdateWindow) DeepCopy() *UpdateWindow {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePlan) DeepCopyInto(out *UpgradePlan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePlan.
+func (in *UpgradePlan) DeepCopy() *UpgradePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpgradePlan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePlanClusterStatus) DeepCopyInto(out *UpgradePlanClusterStatus) {
+	*out = *in
+	if in.FromVersion != nil {
+		in, out := &in.FromVersion, &out.FromVersion
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePlanClusterStatus.
+func (in *UpgradePlanClusterStatus) DeepCopy() *UpgradePlanClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePlanClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePlanList) DeepCopyInto(out *UpgradePlanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]UpgradePlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePlanList.
+func (in *UpgradePlanList) DeepCopy() *UpgradePlanList {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePlanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpgradePlanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePlanSpec) DeepCopyInto(out *UpgradePlanSpec) {
+	*out = *in
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	out.Version = in.Version.DeepCopy()
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]UpgradePlanWave, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthTimeout != nil {
+		in, out := &in.HealthTimeout, &out.HealthTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePlanSpec.
+func (in *UpgradePlanSpec) DeepCopy() *UpgradePlanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePlanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePlanStatus) DeepCopyInto(out *UpgradePlanStatus) {
+	*out = *in
+	if in.WaveCompletionTime != nil {
+		in, out := &in.WaveCompletionTime, &out.WaveCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make(map[string]UpgradePlanClusterStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePlanStatus.
+func (in *UpgradePlanStatus) DeepCopy() *UpgradePlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePlanWave) DeepCopyInto(out *UpgradePlanWave) {
+	*out = *in
+	out.SoakTime = in.SoakTime
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePlanWave.
+func (in *UpgradePlanWave) DeepCopy() *UpgradePlanWave {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePlanWave)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
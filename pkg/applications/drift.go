@@ -0,0 +1,282 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applications
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8c.io/kubermatic/v2/pkg/applications/providers/template"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxSummarizedObjects is the maximum number of drifted objects listed in a drift summary.
+	maxSummarizedObjects = 5
+
+	// maxSummarizedFields is the maximum number of drifted fields listed per object in a drift summary.
+	maxSummarizedFields = 5
+)
+
+// ObjectDrift describes how a live object differs from the object deployed by the application.
+type ObjectDrift struct {
+	// Object is the object as deployed by the application.
+	Object *unstructured.Unstructured
+
+	// Missing is true if the object does not exist anymore in the user cluster.
+	Missing bool
+
+	// Fields are the paths of the fields whose live value differs from the deployed one (e.g. spec.replicas).
+	Fields []string
+}
+
+// DetectDrift compares the desired objects with the live objects of the user cluster and returns the objects that have
+// drifted. Only the fields set in the desired objects are compared, so fields defaulted by the API server or set by
+// other controllers are not considered as drift. The status and the metadata, except labels and annotations, are ignored.
+func DetectDrift(ctx context.Context, userClient ctrlruntimeclient.Reader, desired []*unstructured.Unstructured) ([]ObjectDrift, error) {
+	var drifts []ObjectDrift
+	for _, obj := range desired {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := userClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), live); err != nil {
+			if apierrors.IsNotFound(err) {
+				drifts = append(drifts, ObjectDrift{Object: obj, Missing: true})
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s %s: %w", obj.GetKind(), ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+		}
+
+		var fields []string
+		diffFields(comparableContent(obj), live.Object, "", &fields)
+		if len(fields) > 0 {
+			drifts = append(drifts, ObjectDrift{Object: obj, Fields: fields})
+		}
+	}
+	return drifts, nil
+}
+
+// CorrectDrift restores the drifted objects to their desired state with server-side apply. Ownership is forced,
+// so fields that have been changed by another field manager, e.g. with kubectl edit, are overwritten as well.
+func CorrectDrift(ctx context.Context, userClient ctrlruntimeclient.Client, drifts []ObjectDrift) error {
+	var errs []error
+	for _, drift := range drifts {
+		obj := drift.Object.DeepCopy()
+		obj.SetResourceVersion("")
+		if err := userClient.Patch(ctx, obj, ctrlruntimeclient.Apply, ctrlruntimeclient.FieldOwner(template.FieldManager), ctrlruntimeclient.ForceOwnership); err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply %s %s: %w", obj.GetKind(), ctrlruntimeclient.ObjectKeyFromObject(obj), err))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// SummarizeDrift returns a human-readable summary of the drifts suitable for a condition message. Only the paths of the
+// drifted fields are listed, never their values, as they may hold secrets.
+func SummarizeDrift(drifts []ObjectDrift) string {
+	if len(drifts) == 0 {
+		return "no drift detected"
+	}
+
+	var objects []string
+	for i, drift := range drifts {
+		if i == maxSummarizedObjects {
+			objects = append(objects, fmt.Sprintf("and %d more", len(drifts)-maxSummarizedObjects))
+			break
+		}
+
+		id := objectID(drift.Object)
+		if drift.Missing {
+			objects = append(objects, id+" (missing)")
+			continue
+		}
+
+		fields := drift.Fields
+		if len(fields) > maxSummarizedFields {
+			fields = append(append([]string{}, fields[:maxSummarizedFields]...), fmt.Sprintf("and %d more", len(drift.Fields)-maxSummarizedFields))
+		}
+		objects = append(objects, fmt.Sprintf("%s (%s)", id, strings.Join(fields, ", ")))
+	}
+
+	return fmt.Sprintf("%d object(s) drifted: %s", len(drifts), strings.Join(objects, "; "))
+}
+
+// comparableContent returns the content of obj that is compared with the live object: metadata is restricted to labels
+// and annotations, status is dropped and the stringData of Secrets is converted to data as the API server does.
+func comparableContent(obj *unstructured.Unstructured) map[string]interface{} {
+	content := obj.DeepCopy().Object
+	delete(content, "status")
+
+	metadata := map[string]interface{}{}
+	if labels := obj.GetLabels(); len(labels) > 0 {
+		metadata["labels"] = toInterfaceMap(labels)
+	}
+	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+		metadata["annotations"] = toInterfaceMap(annotations)
+	}
+	content["metadata"] = metadata
+
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Secret" {
+		if stringData, ok := content["stringData"].(map[string]interface{}); ok {
+			data, _ := content["data"].(map[string]interface{})
+			if data == nil {
+				data = map[string]interface{}{}
+			}
+			for k, v := range stringData {
+				if s, ok := v.(string); ok {
+					data[k] = base64.StdEncoding.EncodeToString([]byte(s))
+				}
+			}
+			content["data"] = data
+			delete(content, "stringData")
+		}
+	}
+
+	return content
+}
+
+// diffFields appends to fields the paths of the fields set in desired whose value differs in live.
+func diffFields(desired interface{}, live interface{}, path string, fields *[]string) {
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			if live != nil || !isZero(desired) {
+				*fields = append(*fields, path)
+			}
+			return
+		}
+
+		keys := make([]string, 0, len(desiredValue))
+		for k := range desiredValue {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diffFields(desiredValue[k], liveValue[k], joinPath(path, k), fields)
+		}
+
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok && live == nil && isZero(desired) {
+			return
+		}
+		if !ok || len(liveValue) != len(desiredValue) {
+			*fields = append(*fields, path)
+			return
+		}
+		for i := range desiredValue {
+			diffFields(desiredValue[i], liveValue[i], fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+
+	default:
+		if live == nil && isZero(desired) {
+			// zero values are usually omitted by the API server.
+			return
+		}
+		if !scalarEqual(desired, live) {
+			*fields = append(*fields, path)
+		}
+	}
+}
+
+// scalarEqual returns true if a and b are equal. Numbers are compared independently of their type and strings that are
+// quantities (e.g. 1Gi and 1024Mi) are compared semantically.
+func scalarEqual(a interface{}, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			return fa == fb
+		}
+		// e.g. a cpu request of 1 is returned as "1" by the API server.
+		if sb, ok := b.(string); ok {
+			qb, err := resource.ParseQuantity(sb)
+			return err == nil && qb.AsApproximateFloat64() == fa
+		}
+		return false
+	}
+
+	sa, okA := a.(string)
+	sb, okB := b.(string)
+	if okA && okB {
+		qa, errA := resource.ParseQuantity(sa)
+		qb, errB := resource.ParseQuantity(sb)
+		return errA == nil && errB == nil && qa.Cmp(qb) == 0
+	}
+
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// isZero returns true if v is the zero value of its type or an empty map or list.
+func isZero(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(value) == 0
+	case []interface{}:
+		return len(value) == 0
+	default:
+		return reflect.ValueOf(v).IsZero()
+	}
+}
+
+// objectID returns the kind, namespace and name of obj (e.g. "Deployment default/nginx").
+func objectID(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
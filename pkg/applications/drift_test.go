@@ -0,0 +1,245 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applications
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDetectDrift(t *testing.T) {
+	testCases := []struct {
+		name           string
+		liveObjects    []ctrlruntimeclient.Object
+		desired        []*unstructured.Unstructured
+		expectedDrifts []ObjectDrift
+	}{
+		{
+			name:        "scenario 1: fields defaulted or set by the API server are not considered as drift",
+			liveObjects: []ctrlruntimeclient.Object{genDeployment(2, "nginx:1.23", "1Gi", map[string]string{"app": "nginx", "added": "by-user"})},
+			desired: []*unstructured.Unstructured{
+				genDesiredDeployment(2, "nginx:1.23", "1024Mi", map[string]interface{}{"app": "nginx"}),
+			},
+		},
+		{
+			name:        "scenario 2: modified fields are reported",
+			liveObjects: []ctrlruntimeclient.Object{genDeployment(5, "nginx:latest", "1Gi", map[string]string{"app": "other"})},
+			desired: []*unstructured.Unstructured{
+				genDesiredDeployment(2, "nginx:1.23", "1Gi", map[string]interface{}{"app": "nginx"}),
+			},
+			expectedDrifts: []ObjectDrift{
+				{Fields: []string{"metadata.labels.app", "spec.replicas", "spec.template.spec.containers[0].image"}},
+			},
+		},
+		{
+			name: "scenario 3: deleted objects are reported as missing",
+			desired: []*unstructured.Unstructured{
+				genDesiredDeployment(2, "nginx:1.23", "1Gi", nil),
+			},
+			expectedDrifts: []ObjectDrift{
+				{Missing: true},
+			},
+		},
+		{
+			name: "scenario 4: stringData of secrets is compared with data",
+			liveObjects: []ctrlruntimeclient.Object{
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "same", Namespace: defaultNamespace}, Data: map[string][]byte{"password": []byte("secret")}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "changed", Namespace: defaultNamespace}, Data: map[string][]byte{"password": []byte("changed")}},
+			},
+			desired: []*unstructured.Unstructured{
+				genDesiredSecret("same", "secret"),
+				genDesiredSecret("changed", "secret"),
+			},
+			expectedDrifts: []ObjectDrift{
+				{Fields: []string{"data.password"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.liveObjects...).Build()
+
+			drifts, err := DetectDrift(context.Background(), userClient, tc.desired)
+			if err != nil {
+				t.Fatalf("failed to detect drift: %v", err)
+			}
+
+			if len(drifts) != len(tc.expectedDrifts) {
+				t.Fatalf("expected %d drifted objects, got %d: %s", len(tc.expectedDrifts), len(drifts), SummarizeDrift(drifts))
+			}
+			for i, expected := range tc.expectedDrifts {
+				if drifts[i].Missing != expected.Missing || !reflect.DeepEqual(drifts[i].Fields, expected.Fields) {
+					t.Errorf("expected drift %+v, got missing=%v fields=%v", expected, drifts[i].Missing, drifts[i].Fields)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeDrift(t *testing.T) {
+	deployment := genDesiredDeployment(1, "nginx", "1Gi", nil)
+
+	testCases := []struct {
+		name     string
+		drifts   []ObjectDrift
+		expected string
+	}{
+		{
+			name:     "scenario 1: no drift",
+			expected: "no drift detected",
+		},
+		{
+			name: "scenario 2: missing and modified objects",
+			drifts: []ObjectDrift{
+				{Object: deployment, Fields: []string{"spec.replicas"}},
+				{Object: genDesiredSecret("creds", "secret"), Missing: true},
+			},
+			expected: "2 object(s) drifted: Deployment default/nginx (spec.replicas); Secret default/creds (missing)",
+		},
+		{
+			name: "scenario 3: objects and fields are truncated",
+			drifts: func() []ObjectDrift {
+				var drifts []ObjectDrift
+				for i := 0; i < 7; i++ {
+					drifts = append(drifts, ObjectDrift{Object: deployment, Fields: []string{"a", "b", "c", "d", "e", "f"}})
+				}
+				return drifts
+			}(),
+			expected: "7 object(s) drifted: " +
+				"Deployment default/nginx (a, b, c, d, e, and 1 more); Deployment default/nginx (a, b, c, d, e, and 1 more); " +
+				"Deployment default/nginx (a, b, c, d, e, and 1 more); Deployment default/nginx (a, b, c, d, e, and 1 more); " +
+				"Deployment default/nginx (a, b, c, d, e, and 1 more); and 2 more",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if summary := SummarizeDrift(tc.drifts); summary != tc.expected {
+				t.Errorf("expected summary %q, got %q", tc.expected, summary)
+			}
+		})
+	}
+}
+
+// applyRecordingClient records the options of server-side apply patches and fails them with a conflict if the
+// ownership is not forced, like the API server does for fields changed by another field manager.
+type applyRecordingClient struct {
+	ctrlruntimeclient.Client
+
+	applied []string
+}
+
+func (c *applyRecordingClient) Patch(_ context.Context, obj ctrlruntimeclient.Object, _ ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+	patchOpts := &ctrlruntimeclient.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+
+	if patchOpts.Force == nil || !*patchOpts.Force {
+		return apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, obj.GetName(), errors.New(`conflict with "kubectl-edit": .spec.replicas`))
+	}
+
+	c.applied = append(c.applied, obj.GetName())
+	return nil
+}
+
+func TestCorrectDrift(t *testing.T) {
+	drifts := []ObjectDrift{
+		{Object: genDesiredDeployment(1, "nginx", "1Gi", nil), Fields: []string{"spec.replicas"}},
+		{Object: genDesiredSecret("credentials", "secret"), Fields: []string{"data"}},
+	}
+
+	userClient := &applyRecordingClient{Client: fakectrlruntimeclient.NewClientBuilder().Build()}
+
+	if err := CorrectDrift(context.Background(), userClient, drifts); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if expected := []string{"nginx", "credentials"}; !reflect.DeepEqual(userClient.applied, expected) {
+		t.Errorf("expected %v to be applied with forced ownership, got %v", expected, userClient.applied)
+	}
+}
+
+func genDeployment(replicas int32, image string, memory string, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: defaultNamespace, Labels: labels, ResourceVersion: "1"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas:             pointer.Int32(replicas),
+			RevisionHistoryLimit: pointer.Int32(10),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:                     "nginx",
+						Image:                    image,
+						TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func genDesiredDeployment(replicas int64, image string, memory string, labels map[string]interface{}) *unstructured.Unstructured {
+	metadata := map[string]interface{}{"name": "nginx", "namespace": defaultNamespace}
+	if labels != nil {
+		metadata["labels"] = labels
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   metadata,
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"hostNetwork": false,
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":      "nginx",
+							"image":     image,
+							"resources": map[string]interface{}{"limits": map[string]interface{}{"memory": memory}},
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func genDesiredSecret(name string, password string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": name, "namespace": defaultNamespace},
+		"stringData": map[string]interface{}{"password": password},
+	}}
+}
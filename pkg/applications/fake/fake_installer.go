@@ -23,6 +23,7 @@ import (
 	"go.uber.org/zap"
 
 	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications"
 	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
 
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,6 +39,12 @@ type ApplicationInstallerRecorder struct {
 
 	// DeleteEvents stores the call to delete function. Key is the name of the applicationInstallation.
 	DeleteEvents sync.Map
+
+	// RollbackEvents stores the call to rollback function. Key is the name of the applicationInstallation.
+	RollbackEvents sync.Map
+
+	// DetectDriftEvents stores the call to detectDrift function. Key is the name of the applicationInstallation.
+	DetectDriftEvents sync.Map
 }
 
 func (a *ApplicationInstallerRecorder) GetAppCache() string {
@@ -59,6 +66,20 @@ func (a *ApplicationInstallerRecorder) Delete(ctx context.Context, log *zap.Suga
 	return util.NoStatusUpdate, nil
 }
 
+func (a *ApplicationInstallerRecorder) Rollback(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error) {
+	a.RollbackEvents.Store(applicationInstallation.Name, *applicationInstallation.DeepCopy())
+	return util.NoStatusUpdate, nil
+}
+
+func (a *ApplicationInstallerRecorder) DetectDrift(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]applications.ObjectDrift, error) {
+	a.DetectDriftEvents.Store(applicationInstallation.Name, *applicationInstallation.DeepCopy())
+	return nil, nil
+}
+
+func (a *ApplicationInstallerRecorder) CorrectDrift(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, drifts []applications.ObjectDrift) error {
+	return nil
+}
+
 // ApplicationInstallerLogger is a fake ApplicationInstaller that just logs actions. it's used for the development of the controller.
 type ApplicationInstallerLogger struct {
 }
@@ -81,6 +102,21 @@ func (a ApplicationInstallerLogger) Delete(ctx context.Context, log *zap.Sugared
 	return util.NoStatusUpdate, nil
 }
 
+func (a ApplicationInstallerLogger) Rollback(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error) {
+	log.Debugf("Rollback application %s to revision %d. applicationVersion=%v", applicationInstallation.Name, revision, applicationInstallation.Status.ApplicationVersion)
+	return util.NoStatusUpdate, nil
+}
+
+func (a ApplicationInstallerLogger) DetectDrift(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]applications.ObjectDrift, error) {
+	log.Debugf("Detect drift of application %s. applicationVersion=%v", applicationInstallation.Name, applicationInstallation.Status.ApplicationVersion)
+	return nil, nil
+}
+
+func (a ApplicationInstallerLogger) CorrectDrift(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, drifts []applications.ObjectDrift) error {
+	log.Debugf("Correct drift of %d objects", len(drifts))
+	return nil
+}
+
 // CustomApplicationInstaller is an applicationInstaller in which every function can be independently mocked.
 // If a function is not mocked, then default values are returned.
 type CustomApplicationInstaller struct {
@@ -88,6 +124,9 @@ type CustomApplicationInstaller struct {
 	DonwloadSourceFunc func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, applicationInstallation *appskubermaticv1.ApplicationInstallation, downloadDest string) (string, error)
 	ApplyFunc          func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, appSourcePath string) (util.StatusUpdater, error)
 	DeleteFunc         func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error)
+	RollbackFunc       func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error)
+	DetectDriftFunc    func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]applications.ObjectDrift, error)
+	CorrectDriftFunc   func(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, drifts []applications.ObjectDrift) error
 }
 
 func (c CustomApplicationInstaller) GetAppCache() string {
@@ -117,3 +156,24 @@ func (c CustomApplicationInstaller) Delete(ctx context.Context, log *zap.Sugared
 	}
 	return util.NoStatusUpdate, nil
 }
+
+func (c CustomApplicationInstaller) Rollback(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error) {
+	if c.RollbackFunc != nil {
+		return c.RollbackFunc(ctx, log, seedClient, userClient, appDefinition, applicationInstallation, revision)
+	}
+	return util.NoStatusUpdate, nil
+}
+
+func (c CustomApplicationInstaller) DetectDrift(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]applications.ObjectDrift, error) {
+	if c.DetectDriftFunc != nil {
+		return c.DetectDriftFunc(ctx, log, seedClient, userClient, appDefinition, applicationInstallation)
+	}
+	return nil, nil
+}
+
+func (c CustomApplicationInstaller) CorrectDrift(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, drifts []applications.ObjectDrift) error {
+	if c.CorrectDriftFunc != nil {
+		return c.CorrectDriftFunc(ctx, log, userClient, drifts)
+	}
+	return nil
+}
@@ -49,6 +49,11 @@ import (
 // More information at https://helm.sh/docs/topics/advanced/#storage-backends
 const secretStorageDriver = "secret"
 
+// DefaultMaxHistory is the default maximum number of revisions (including the current one) Helm keeps for a release.
+// Older revisions are purged on upgrade or rollback and can not be rolled back to anymore. It is kept low as Helm loads
+// every revision into memory, which can get the controller OOM killed.
+const DefaultMaxHistory = 1
+
 // HelmSettings holds the Helm configuration for caching repositories.
 type HelmSettings struct {
 	// RepositoryConfig is the path to the repositories file.
@@ -127,21 +132,32 @@ type DeployOpts struct {
 	// enable DNS lookups when rendering templates.
 	// if you enable this flag, you have to verify that helm template function 'getHostByName' is not being used in a chart to disclose any information you do not want to be passed to DNS servers.(c.f CVE-2023-25165)
 	enableDNS bool
+
+	// maxHistory corresponds to the --history-max flag on Helm cli.
+	// maximum number of revisions (including the current one) Helm keeps for the release. 0 means DefaultMaxHistory.
+	maxHistory int
 }
 
 // NewDeployOpts creates a new DeployOpts. It raises an error if the inputs are not valid.
-func NewDeployOpts(wait bool, timeout time.Duration, atomic bool, enableDNS bool) (*DeployOpts, error) {
+func NewDeployOpts(wait bool, timeout time.Duration, atomic bool, enableDNS bool, maxHistory int) (*DeployOpts, error) {
 	if atomic && !wait {
 		return nil, fmt.Errorf("invalid values: if atomic=true then wait must also be true")
 	}
 	if wait && timeout == 0 {
 		return nil, fmt.Errorf("invalid values: if wait = true then timeout must be greater than 0")
 	}
+	if maxHistory < 0 {
+		return nil, fmt.Errorf("invalid values: maxHistory must not be negative")
+	}
+	if maxHistory == 0 {
+		maxHistory = DefaultMaxHistory
+	}
 	return &DeployOpts{
-		wait:      wait,
-		timeout:   timeout,
-		atomic:    atomic,
-		enableDNS: enableDNS,
+		wait:       wait,
+		timeout:    timeout,
+		atomic:     atomic,
+		enableDNS:  enableDNS,
+		maxHistory: maxHistory,
 	}, nil
 }
 
@@ -286,9 +302,9 @@ func (h HelmClient) Upgrade(chartLoc, releaseName string, values map[string]inte
 	upgradeClient.Atomic = deployOpts.atomic
 	upgradeClient.EnableDNS = deployOpts.enableDNS
 
-	// Restrict history to avoid OOM kill
+	// Restrict history to avoid OOM kill while keeping enough revisions to roll back to.
 	// If upgrade fails, Helm always keep the last successful release.
-	// Example:
+	// Example with a history of 2:
 	// with the following actions:
 	// 	* revision 1: successful install
 	// 	* revision 2: fail upgrade
@@ -300,7 +316,7 @@ func (h HelmClient) Upgrade(chartLoc, releaseName string, values map[string]inte
 	// 3       	Fri Mar 24 11:17:14 2023	failed  	examplechart-0.2.0	           	Upgrade "testchart" failed: timed out waiting for the condition
 	//
 	// The revision 2 has been purged.
-	upgradeClient.MaxHistory = 1
+	upgradeClient.MaxHistory = deployOpts.maxHistory
 
 	// Don't reuse values from the previous release.
 	// By default, Helm will merge values with the ones of the last release. This behavior may be helpful to for CLI but
@@ -315,6 +331,34 @@ func (h HelmClient) Upgrade(chartLoc, releaseName string, values map[string]inte
 	return rel, nil
 }
 
+// Rollback the release in targetNamespace to the given revision. Helm creates a new revision of the release with the
+// chart and values of the target revision. The new revision is returned.
+func (h HelmClient) Rollback(releaseName string, revision int, deployOpts DeployOpts) (*release.Release, error) {
+	rollbackClient := action.NewRollback(h.actionConfig)
+	rollbackClient.Version = revision
+	rollbackClient.Wait = deployOpts.wait
+	rollbackClient.Timeout = deployOpts.timeout
+	rollbackClient.MaxHistory = deployOpts.maxHistory
+
+	rollbackErr := rollbackClient.Run(releaseName)
+
+	// Rollback does not return the release, so we get the last one, which is the release created by the rollback (even
+	// if it failed).
+	rel, err := h.actionConfig.Releases.Last(releaseName)
+	if err != nil {
+		if rollbackErr != nil {
+			return nil, rollbackErr
+		}
+		return nil, fmt.Errorf("failed to get release after rollback: %w", err)
+	}
+	return rel, rollbackErr
+}
+
+// GetDeployedRelease returns the currently deployed revision of the release in targetNamespace.
+func (h HelmClient) GetDeployedRelease(releaseName string) (*release.Release, error) {
+	return h.actionConfig.Releases.Deployed(releaseName)
+}
+
 // Uninstall the release in targetNamespace.
 func (h HelmClient) Uninstall(releaseName string) (*release.UninstallReleaseResponse, error) {
 	uninstallClient := action.NewUninstall(h.actionConfig)
@@ -21,9 +21,11 @@ package helmclient
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -345,7 +347,7 @@ func TestHelmClient(t *testing.T) {
 				ns := test.CreateNamespaceWithCleanup(t, ctx, client)
 				helmClient, chartFullPath := buildHelClient(t, ctx, ns, chartDirV2Path)
 
-				deployOpts, err := NewDeployOpts(true, 5*time.Second, false, false)
+				deployOpts, err := NewDeployOpts(true, 5*time.Second, false, false, 0)
 				if err != nil {
 					t.Fatalf("failed to build DeployOpts: %s", err)
 				}
@@ -371,7 +373,7 @@ func TestHelmClient(t *testing.T) {
 				ns := test.CreateNamespaceWithCleanup(t, ctx, client)
 				helmClient, chartFullPath := buildHelClient(t, ctx, ns, chartDirV2Path)
 
-				deployOpts, err := NewDeployOpts(true, 5*time.Second, true, false)
+				deployOpts, err := NewDeployOpts(true, 5*time.Second, true, false, 0)
 				if err != nil {
 					t.Fatalf("failed to build DeployOpts: %s", err)
 				}
@@ -393,7 +395,7 @@ func TestHelmClient(t *testing.T) {
 			testFunc: func(t *testing.T) {
 				ns := test.CreateNamespaceWithCleanup(t, ctx, client)
 				helmClient, chartFullPath := buildHelClient(t, ctx, ns, chartDirV2Path)
-				deployOpts, err := NewDeployOpts(true, 5*time.Second, false, false)
+				deployOpts, err := NewDeployOpts(true, 5*time.Second, false, false, 0)
 				if err != nil {
 					t.Fatalf("failed to build DeployOpts: %s", err)
 				}
@@ -421,7 +423,7 @@ func TestHelmClient(t *testing.T) {
 			testFunc: func(t *testing.T) {
 				ns := test.CreateNamespaceWithCleanup(t, ctx, client)
 				helmClient, chartFullPath := buildHelClient(t, ctx, ns, chartDirV2Path)
-				deployOpts, err := NewDeployOpts(true, 5*time.Second, true, false)
+				deployOpts, err := NewDeployOpts(true, 5*time.Second, true, false, 0)
 				if err != nil {
 					t.Fatalf("failed to build DeployOpts: %s", err)
 				}
@@ -452,13 +454,13 @@ func TestHelmClient(t *testing.T) {
 				ns := test.CreateNamespaceWithCleanup(t, ctx, client)
 				installTest(t, ctx, client, ns, chartDirV1Path, map[string]interface{}{}, test.DefaultData, test.DefaultVerionLabel, false)
 				upgradeTest(t, ctx, client, ns, chartDirV2Path, map[string]interface{}{}, test.DefaultDataV2, test.DefaultVerionLabelV2, false, 2)
-				checkExpectedReleases(t, ctx, client, ns, []test.ReleaseStorageInfo{{Name: "sh.helm.release.v1." + releaseName + ".v1", Version: "1"}, {Name: "sh.helm.release.v1." + releaseName + ".v2", Version: "2"}})
+				checkExpectedReleases(t, ctx, client, ns, expectedReleases(1, 2))
 
-				// history limit is 2 so these next upgrades should trigger a clean up of old helm release
+				// history limit is 1 (plus the deployed release Helm never purges) so these next upgrades should trigger a clean up of old helm release
 				upgradeTest(t, ctx, client, ns, chartDirV2Path, map[string]interface{}{}, test.DefaultDataV2, test.DefaultVerionLabelV2, false, 3)
 				upgradeTest(t, ctx, client, ns, chartDirV2Path, map[string]interface{}{}, test.DefaultDataV2, test.DefaultVerionLabelV2, false, 4)
 
-				checkExpectedReleases(t, ctx, client, ns, []test.ReleaseStorageInfo{{Name: "sh.helm.release.v1." + releaseName + ".v3", Version: "3"}, {Name: "sh.helm.release.v1." + releaseName + ".v4", Version: "4"}})
+				checkExpectedReleases(t, ctx, client, ns, expectedReleases(3, 4))
 			},
 		},
 
@@ -467,16 +469,60 @@ func TestHelmClient(t *testing.T) {
 			testFunc: func(t *testing.T) {
 				ns := test.CreateNamespaceWithCleanup(t, ctx, client)
 				installOrUpgradeTest(t, ctx, client, ns, chartDirV1Path, map[string]interface{}{}, test.DefaultData, test.DefaultVerionLabel, 1)
-				checkExpectedReleases(t, ctx, client, ns, []test.ReleaseStorageInfo{{Name: "sh.helm.release.v1." + releaseName + ".v1", Version: "1"}})
+				checkExpectedReleases(t, ctx, client, ns, expectedReleases(1, 1))
 
 				// upgrade
 				installOrUpgradeTest(t, ctx, client, ns, chartDirV1Path, map[string]interface{}{}, test.DefaultData, test.DefaultVerionLabel, 2)
-				checkExpectedReleases(t, ctx, client, ns, []test.ReleaseStorageInfo{{Name: "sh.helm.release.v1." + releaseName + ".v1", Version: "1"}, {Name: "sh.helm.release.v1." + releaseName + ".v2", Version: "2"}})
+				checkExpectedReleases(t, ctx, client, ns, expectedReleases(1, 2))
 
-				// history limit is 2 so these next upgrades should trigger a clean up of old helm release
+				// history limit is 1 (plus the deployed release Helm never purges) so these next upgrades should trigger a clean up of old helm release
 				installOrUpgradeTest(t, ctx, client, ns, chartDirV2Path, map[string]interface{}{}, test.DefaultDataV2, test.DefaultVerionLabelV2, 3)
 				installOrUpgradeTest(t, ctx, client, ns, chartDirV2Path, map[string]interface{}{}, test.DefaultDataV2, test.DefaultVerionLabelV2, 4)
-				checkExpectedReleases(t, ctx, client, ns, []test.ReleaseStorageInfo{{Name: "sh.helm.release.v1." + releaseName + ".v3", Version: "3"}, {Name: "sh.helm.release.v1." + releaseName + ".v4", Version: "4"}})
+				checkExpectedReleases(t, ctx, client, ns, expectedReleases(3, 4))
+			},
+		},
+		{
+			name: "release history should be kept up to the configured max history",
+			testFunc: func(t *testing.T) {
+				ns := test.CreateNamespaceWithCleanup(t, ctx, client)
+				installTest(t, ctx, client, ns, chartDirV1Path, map[string]interface{}{}, test.DefaultData, test.DefaultVerionLabel, false)
+
+				helmClient, chartFullPath := buildHelClient(t, ctx, ns, chartDirV2Path)
+				deployOpts, err := NewDeployOpts(false, 0, false, false, 3)
+				if err != nil {
+					t.Fatalf("failed to build DeployOpts: %s", err)
+				}
+				for version := 2; version <= 5; version++ {
+					if _, err := helmClient.Upgrade(chartFullPath, releaseName, map[string]interface{}{}, *deployOpts, AuthSettings{}); err != nil {
+						t.Fatalf("helm upgrade failed :%s", err)
+					}
+				}
+				checkExpectedReleases(t, ctx, client, ns, expectedReleases(3, 5))
+			},
+		},
+		{
+			name: "rollback should restore the chart and values of the previous revision",
+			testFunc: func(t *testing.T) {
+				ns := test.CreateNamespaceWithCleanup(t, ctx, client)
+				installTest(t, ctx, client, ns, chartDirV1Path, map[string]interface{}{}, test.DefaultData, test.DefaultVerionLabel, false)
+				upgradeTest(t, ctx, client, ns, chartDirV2Path, map[string]interface{}{}, test.DefaultDataV2, test.DefaultVerionLabelV2, false, 2)
+
+				rollbackTest(t, ctx, client, ns, 1, test.DefaultData, test.DefaultVerionLabel, 3)
+				// the rolled back revision is purged as only the deployed release is kept in addition to the new one.
+				checkExpectedReleases(t, ctx, client, ns, expectedReleases(2, 3))
+			},
+		},
+		{
+			name: "rollback should fail if revision does not exist",
+			testFunc: func(t *testing.T) {
+				ns := test.CreateNamespaceWithCleanup(t, ctx, client)
+				installTest(t, ctx, client, ns, chartDirV1Path, map[string]interface{}{}, test.DefaultData, test.DefaultVerionLabel, false)
+
+				helmClient, _ := buildHelClient(t, ctx, ns, chartDirV1Path)
+				if _, err := helmClient.Rollback(releaseName, 5, *defaultDeployOpts(t)); err == nil {
+					t.Fatal("helm rollback to a revision that does not exist should failed, but no error was raised")
+				}
+				checkExpectedReleases(t, ctx, client, ns, expectedReleases(1, 1))
 			},
 		},
 	}
@@ -489,7 +535,7 @@ func TestHelmClient(t *testing.T) {
 func installTest(t *testing.T, ctx context.Context, client ctrlruntimeclient.Client, ns *corev1.Namespace, chartPath string, values map[string]interface{}, expectedData map[string]string, expectedVersionLabel string, enableDNS bool) {
 	helmClient, chartFullPath := buildHelClient(t, ctx, ns, chartPath)
 
-	deployOpts, err := NewDeployOpts(false, 0, false, enableDNS)
+	deployOpts, err := NewDeployOpts(false, 0, false, enableDNS, 0)
 	if err != nil {
 		t.Fatalf("failed to build DeployOpts: %s", err)
 	}
@@ -520,7 +566,7 @@ func installShouldFailedIfAlreadyInstalledTest(t *testing.T, ctx context.Context
 
 func upgradeTest(t *testing.T, ctx context.Context, client ctrlruntimeclient.Client, ns *corev1.Namespace, chartPath string, values map[string]interface{}, expectedData map[string]string, expectedVersionLabel string, enableDNS bool, expectedReleaseVersion int) {
 	helmClient, chartFullPath := buildHelClient(t, ctx, ns, chartPath)
-	deployOpts, err := NewDeployOpts(false, 0, false, enableDNS)
+	deployOpts, err := NewDeployOpts(false, 0, false, enableDNS, 0)
 	if err != nil {
 		t.Fatalf("failed to build DeployOpts: %s", err)
 	}
@@ -563,6 +609,21 @@ func installOrUpgradeTest(t *testing.T, ctx context.Context, client ctrlruntimec
 	test.CheckConfigMap(t, ctx, client, ns, expectedData, expectedVersionLabel, false)
 }
 
+func rollbackTest(t *testing.T, ctx context.Context, client ctrlruntimeclient.Client, ns *corev1.Namespace, revision int, expectedData map[string]string, expectedVersionLabel string, expectedRelVersion int) {
+	helmClient, _ := buildHelClient(t, ctx, ns, "testdata/examplechart")
+
+	releaseInfo, err := helmClient.Rollback(releaseName, revision, *defaultDeployOpts(t))
+	if err != nil {
+		t.Fatalf("helm rollback failed :%s", err)
+	}
+
+	if releaseInfo.Version != expectedRelVersion {
+		t.Fatalf("invalid helm release version. expected %v, got %v", expectedRelVersion, releaseInfo.Version)
+	}
+
+	test.CheckConfigMap(t, ctx, client, ns, expectedData, expectedVersionLabel, false)
+}
+
 func uninstallTest(t *testing.T, ctx context.Context, client ctrlruntimeclient.Client, ns *corev1.Namespace) {
 	tempDir := t.TempDir()
 	settings := NewSettings(tempDir)
@@ -632,7 +693,7 @@ func buildHelClient(t *testing.T, ctx context.Context, ns *corev1.Namespace, cha
 
 // defaultDeployOpts creates DeployOpts with wait=false and atomic=false.
 func defaultDeployOpts(t *testing.T) *DeployOpts {
-	deployOpts, err := NewDeployOpts(false, 0, false, false)
+	deployOpts, err := NewDeployOpts(false, 0, false, false, 0)
 	if err != nil {
 		t.Fatalf("failed to build default deployOpts: %s", err)
 	}
@@ -675,6 +736,15 @@ func checkServiceDeployed(t *testing.T, ctx context.Context, client ctrlruntimec
 	}
 }
 
+// expectedReleases returns the storage info of the releases from revision first to revision last (included).
+func expectedReleases(first, last int) []test.ReleaseStorageInfo {
+	var releases []test.ReleaseStorageInfo
+	for version := first; version <= last; version++ {
+		releases = append(releases, test.ReleaseStorageInfo{Name: fmt.Sprintf("sh.helm.release.v1.%s.v%d", releaseName, version), Version: strconv.Itoa(version)})
+	}
+	return releases
+}
+
 // checkExpectedReleases checks that only expected releases are stored on the clusters.
 // Helm releases are stored in secrets (because we use the secret driver) labeled with name=<the name of the release>, version=<the version of the release> and owner=helm.
 func checkExpectedReleases(t *testing.T, ctx context.Context, client ctrlruntimeclient.Client, ns *corev1.Namespace, expectedReleases []test.ReleaseStorageInfo) {
@@ -55,13 +55,14 @@ func TestNewShouldFailWhenRESTClientGetterNamespaceIsDifferentThanTargetNamespac
 
 func TestNewDeploySettings(t *testing.T) {
 	tests := []struct {
-		name      string
-		wait      bool
-		timeout   time.Duration
-		atomic    bool
-		enableDns bool
-		want      *DeployOpts
-		wantErr   bool
+		name       string
+		wait       bool
+		timeout    time.Duration
+		atomic     bool
+		enableDns  bool
+		maxHistory int
+		want       *DeployOpts
+		wantErr    bool
 	}{
 		{
 			name:      "test valid: no wait, timeout, atomic and enableDNS",
@@ -70,10 +71,11 @@ func TestNewDeploySettings(t *testing.T) {
 			atomic:    false,
 			enableDns: false,
 			want: &DeployOpts{
-				wait:      false,
-				timeout:   0,
-				atomic:    false,
-				enableDNS: false,
+				wait:       false,
+				timeout:    0,
+				atomic:     false,
+				enableDNS:  false,
+				maxHistory: DefaultMaxHistory,
 			},
 			wantErr: false,
 		},
@@ -84,10 +86,11 @@ func TestNewDeploySettings(t *testing.T) {
 			atomic:    false,
 			enableDns: false,
 			want: &DeployOpts{
-				wait:      true,
-				timeout:   10 * time.Second,
-				atomic:    false,
-				enableDNS: false,
+				wait:       true,
+				timeout:    10 * time.Second,
+				atomic:     false,
+				enableDNS:  false,
+				maxHistory: DefaultMaxHistory,
 			},
 			wantErr: false,
 		},
@@ -98,10 +101,11 @@ func TestNewDeploySettings(t *testing.T) {
 			atomic:    true,
 			enableDns: false,
 			want: &DeployOpts{
-				wait:      true,
-				timeout:   10 * time.Second,
-				atomic:    true,
-				enableDNS: false,
+				wait:       true,
+				timeout:    10 * time.Second,
+				atomic:     true,
+				enableDNS:  false,
+				maxHistory: DefaultMaxHistory,
 			},
 			wantErr: false,
 		},
@@ -112,10 +116,11 @@ func TestNewDeploySettings(t *testing.T) {
 			atomic:    true,
 			enableDns: true,
 			want: &DeployOpts{
-				wait:      true,
-				timeout:   10 * time.Second,
-				atomic:    true,
-				enableDNS: true,
+				wait:       true,
+				timeout:    10 * time.Second,
+				atomic:     true,
+				enableDNS:  true,
+				maxHistory: DefaultMaxHistory,
 			},
 			wantErr: false,
 		},
@@ -126,10 +131,19 @@ func TestNewDeploySettings(t *testing.T) {
 			atomic:    false,
 			enableDns: true,
 			want: &DeployOpts{
-				wait:      false,
-				timeout:   0,
-				atomic:    false,
-				enableDNS: true,
+				wait:       false,
+				timeout:    0,
+				atomic:     false,
+				enableDNS:  true,
+				maxHistory: DefaultMaxHistory,
+			},
+			wantErr: false,
+		},
+		{
+			name:       "test valid: maxHistory=5",
+			maxHistory: 5,
+			want: &DeployOpts{
+				maxHistory: 5,
 			},
 			wantErr: false,
 		},
@@ -151,10 +165,16 @@ func TestNewDeploySettings(t *testing.T) {
 			want:      nil,
 			wantErr:   true,
 		},
+		{
+			name:       "test invalid: negative maxHistory",
+			maxHistory: -1,
+			want:       nil,
+			wantErr:    true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewDeployOpts(tt.wait, tt.timeout, tt.atomic, tt.enableDns)
+			got, err := NewDeployOpts(tt.wait, tt.timeout, tt.atomic, tt.enableDns, tt.maxHistory)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("NewDeployOpts() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -171,6 +191,9 @@ func TestNewDeploySettings(t *testing.T) {
 				if tt.want.enableDNS != got.enableDNS {
 					t.Errorf("want DeployOpts.enableDNS=%v, got %v", tt.want.enableDNS, got.enableDNS)
 				}
+				if tt.want.maxHistory != got.maxHistory {
+					t.Errorf("want DeployOpts.maxHistory=%v, got %v", tt.want.maxHistory, got.maxHistory)
+				}
 			}
 		})
 	}
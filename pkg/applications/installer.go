@@ -19,6 +19,7 @@ package applications
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"go.uber.org/zap"
 
@@ -44,6 +45,15 @@ type ApplicationInstaller interface {
 
 	// Delete function uninstalls the application on the user-cluster and returns an error if the uninstallation has failed. StatusUpdater is guaranteed to be non nil. This is idempotent.
 	Delete(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error)
+
+	// Rollback function rolls back the application on the user-cluster to the given revision of its release and returns an error if the rollback has failed. StatusUpdater is guaranteed to be non nil.
+	Rollback(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error)
+
+	// DetectDrift function compares the objects deployed by the application with the live objects of the user-cluster and returns the objects that have drifted.
+	DetectDrift(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]ObjectDrift, error)
+
+	// CorrectDrift function restores the drifted objects to their deployed state.
+	CorrectDrift(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, drifts []ObjectDrift) error
 }
 
 // ApplicationManager handles the installation / uninstallation of an Application on the user-cluster.
@@ -79,7 +89,7 @@ func (a *ApplicationManager) DonwloadSource(ctx context.Context, log *zap.Sugare
 
 // Apply creates the namespace where the application will be installed (if necessary) and installs the application.
 func (a *ApplicationManager) Apply(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, appSourcePath string) (util.StatusUpdater, error) {
-	templateProvider, err := providers.NewTemplateProvider(ctx, seedClient, a.Kubeconfig, a.ApplicationCache, log, applicationInstallation, a.SecretNamespace)
+	templateProvider, err := providers.NewTemplateProvider(ctx, seedClient, userClient, a.Kubeconfig, a.ApplicationCache, log, applicationInstallation, a.SecretNamespace)
 	if err != nil {
 		return util.NoStatusUpdate, fmt.Errorf("failed to initialize template provider: %w", err)
 	}
@@ -93,7 +103,7 @@ func (a *ApplicationManager) Apply(ctx context.Context, log *zap.SugaredLogger,
 
 // Delete uninstalls the application where the application was installed if necessary.
 func (a *ApplicationManager) Delete(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error) {
-	templateProvider, err := providers.NewTemplateProvider(ctx, seedClient, a.Kubeconfig, a.ApplicationCache, log, applicationInstallation, a.SecretNamespace)
+	templateProvider, err := providers.NewTemplateProvider(ctx, seedClient, userClient, a.Kubeconfig, a.ApplicationCache, log, applicationInstallation, a.SecretNamespace)
 	if err != nil {
 		return util.NoStatusUpdate, fmt.Errorf("failed to initialize template provider: %w", err)
 	}
@@ -101,6 +111,57 @@ func (a *ApplicationManager) Delete(ctx context.Context, log *zap.SugaredLogger,
 	return templateProvider.Uninstall(applicationInstallation)
 }
 
+// Rollback rolls back the application to the given revision of its release.
+func (a *ApplicationManager) Rollback(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error) {
+	templateProvider, err := providers.NewTemplateProvider(ctx, seedClient, userClient, a.Kubeconfig, a.ApplicationCache, log, applicationInstallation, a.SecretNamespace)
+	if err != nil {
+		return util.NoStatusUpdate, fmt.Errorf("failed to initialize template provider: %w", err)
+	}
+
+	return templateProvider.Rollback(appDefinition, applicationInstallation, revision)
+}
+
+// DetectDrift renders the objects deployed by the application and compares them with the live objects of the user-cluster.
+// Except for the helm template method, where objects are read from the deployed release, the application's source is
+// downloaded to render the objects.
+func (a *ApplicationManager) DetectDrift(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]ObjectDrift, error) {
+	templateProvider, err := providers.NewTemplateProvider(ctx, seedClient, userClient, a.Kubeconfig, a.ApplicationCache, log, applicationInstallation, a.SecretNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize template provider: %w", err)
+	}
+
+	var appSourcePath string
+	if applicationInstallation.Status.Method != appskubermaticv1.HelmTemplateMethod {
+		downloadDest, err := os.MkdirTemp(a.ApplicationCache, applicationInstallation.Namespace+"-"+applicationInstallation.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary directory where application source will be downloaded: %w", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(downloadDest); err != nil {
+				log.Errorw("failed to remove temporary directory where application source has been downloaded", zap.Error(err))
+			}
+		}()
+
+		appSourcePath, err = a.DonwloadSource(ctx, log, seedClient, applicationInstallation, downloadDest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	desired, err := templateProvider.Render(appSourcePath, appDefinition, applicationInstallation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render application: %w", err)
+	}
+
+	return DetectDrift(ctx, userClient, desired)
+}
+
+// CorrectDrift restores the drifted objects to their deployed state with server-side apply.
+func (a *ApplicationManager) CorrectDrift(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, drifts []ObjectDrift) error {
+	log.Infow("correcting drifted objects", "count", len(drifts))
+	return CorrectDrift(ctx, userClient, drifts)
+}
+
 // reconcileNamespace ensures namespace is created and has desired labels and annotations if applicationInstallation.Spec.Namespace.Create flag is set.
 func (a *ApplicationManager) reconcileNamespace(ctx context.Context, log *zap.SugaredLogger, applicationInstallation *appskubermaticv1.ApplicationInstallation, userClient ctrlruntimeclient.Client) error {
 	desiredNs := applicationInstallation.Spec.Namespace
@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+const (
+	// cosignSignatureAnnotation is the annotation of a signature layer holding the base64 encoded signature of the layer's payload.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	// cosignPayloadMaxSize is the maximum size of a signature payload.
+	cosignPayloadMaxSize = 128 * 1024
+)
+
+// cosignPayload is the "simple signing" payload signed by cosign.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// verifyCosignSignature verifies that the artifact identified by digest in repo has been signed by cosign with the private key
+// matching the PEM encoded publicKey. Signatures are looked up with the cosign naming convention
+// (<repo>:sha256-<hex>.sig). At least one signature must be valid.
+// Only key based verification is supported, the transparency log is not checked.
+func verifyCosignSignature(repo name.Repository, digest v1.Hash, publicKey []byte, opts ...remote.Option) error {
+	verifier, err := newSignatureVerifier(publicKey)
+	if err != nil {
+		return err
+	}
+
+	sigTag := repo.Tag(fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex))
+	sigImg, err := remote.Image(sigTag, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to get signature %s: %w", sigTag, err)
+	}
+
+	manifest, err := sigImg.Manifest()
+	if err != nil {
+		return fmt.Errorf("failed to read signature manifest: %w", err)
+	}
+
+	var errs []string
+	for _, layerDesc := range manifest.Layers {
+		if err := verifySignatureLayer(sigImg, layerDesc, digest, verifier); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		return nil
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("no signature found in %s", sigTag)
+	}
+	return fmt.Errorf("no valid signature found in %s: %s", sigTag, strings.Join(errs, "; "))
+}
+
+func verifySignatureLayer(sigImg v1.Image, layerDesc v1.Descriptor, digest v1.Hash, verifier signatureVerifier) error {
+	encodedSig, ok := layerDesc.Annotations[cosignSignatureAnnotation]
+	if !ok {
+		return fmt.Errorf("layer %s has no signature annotation", layerDesc.Digest)
+	}
+	signature, err := base64.StdEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature of layer %s: %w", layerDesc.Digest, err)
+	}
+
+	layer, err := sigImg.LayerByDigest(layerDesc.Digest)
+	if err != nil {
+		return fmt.Errorf("failed to get layer %s: %w", layerDesc.Digest, err)
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		return fmt.Errorf("failed to read layer %s: %w", layerDesc.Digest, err)
+	}
+	defer rc.Close()
+
+	payload, err := readAllLimited(rc, cosignPayloadMaxSize)
+	if err != nil {
+		return fmt.Errorf("failed to read payload of layer %s: %w", layerDesc.Digest, err)
+	}
+
+	if err := verifier(payload, signature); err != nil {
+		return fmt.Errorf("invalid signature in layer %s: %w", layerDesc.Digest, err)
+	}
+
+	// the signature is valid, check that it has been issued for this artifact.
+	p := cosignPayload{}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to decode payload of layer %s: %w", layerDesc.Digest, err)
+	}
+	if p.Critical.Image.DockerManifestDigest != digest.String() {
+		return fmt.Errorf("signature in layer %s has been issued for %s", layerDesc.Digest, p.Critical.Image.DockerManifestDigest)
+	}
+
+	return nil
+}
+
+// signatureVerifier verifies that signature is a valid signature of payload.
+type signatureVerifier func(payload, signature []byte) error
+
+// newSignatureVerifier returns a signatureVerifier for the PEM encoded public key. ECDSA, RSA and ed25519 keys are supported.
+func newSignatureVerifier(publicKey []byte) (signatureVerifier, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM public key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	switch pub := key.(type) {
+	case *ecdsa.PublicKey:
+		return func(payload, signature []byte) error {
+			hash := sha256.Sum256(payload)
+			if !ecdsa.VerifyASN1(pub, hash[:], signature) {
+				return errors.New("ecdsa signature verification failed")
+			}
+			return nil
+		}, nil
+	case *rsa.PublicKey:
+		return func(payload, signature []byte) error {
+			hash := sha256.Sum256(payload)
+			return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], signature)
+		}, nil
+	case ed25519.PublicKey:
+		return func(payload, signature []byte) error {
+			if !ed25519.Verify(pub, payload, signature) {
+				return errors.New("ed25519 signature verification failed")
+			}
+			return nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
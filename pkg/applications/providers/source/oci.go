@@ -0,0 +1,269 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ociTitleAnnotation is the annotation holding the file name of a layer (e.g. set by oras push).
+	ociTitleAnnotation = "org.opencontainers.image.title"
+
+	// orasUnpackAnnotation flags a layer pushed by oras as a tarball of a directory.
+	orasUnpackAnnotation = "io.deis.oras.content.unpack"
+
+	// maxLayerSize is the maximum size of a layer that is not a tarball.
+	maxLayerSize = 10 * 1024 * 1024
+)
+
+// OCISource downloads the application's source from an OCI artifact.
+type OCISource struct {
+	Ctx context.Context
+
+	// SeedClient to seed cluster.
+	SeedClient ctrlruntimeclient.Client
+
+	Source *appskubermaticv1.OCISource
+
+	// Namespace where credential secrets are stored.
+	SecretNamespace string
+}
+
+// DownloadSource pulls the artifact, verifies its signature if required and extracts its layers into destination.
+// Layers that are tarballs (e.g. Helm charts or manifest bundles) are extracted, other layers are written to the file
+// named by their "org.opencontainers.image.title" annotation.
+// It returns the full path to the application's source. If the artifact contains a single top level directory, this
+// directory is used as root.
+func (o OCISource) DownloadSource(destination string) (string, error) {
+	ref, err := o.reference()
+	if err != nil {
+		return "", err
+	}
+
+	auth, err := o.authenticator(ref.Context().RegistryStr())
+	if err != nil {
+		return "", err
+	}
+	opts := []remote.Option{remote.WithContext(o.Ctx), remote.WithAuth(auth)}
+
+	// the artifact is pulled by digest, the tag (if any) must point to the same artifact.
+	if o.Source.Digest != "" && o.Source.Tag != "" {
+		tag := ref.Context().Tag(o.Source.Tag)
+		tagDesc, err := remote.Head(tag, opts...)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve tag %s: %w", tag, err)
+		}
+		if tagDesc.Digest.String() != o.Source.Digest {
+			return "", fmt.Errorf("tag %s resolves to digest %s, expected %s", tag, tagDesc.Digest, o.Source.Digest)
+		}
+	}
+
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get artifact %s: %w", ref, err)
+	}
+	if desc.MediaType.IsIndex() {
+		return "", fmt.Errorf("artifact %s is an index, only single manifests are supported", ref)
+	}
+
+	if o.Source.Verify != nil {
+		publicKey, err := util.GetCredentialFromSecret(o.Ctx, o.SeedClient, o.SecretNamespace, o.Source.Verify.PublicKey.Name, o.Source.Verify.PublicKey.Key)
+		if err != nil {
+			return "", err
+		}
+		if err := verifyCosignSignature(ref.Context(), desc.Digest, []byte(publicKey), opts...); err != nil {
+			return "", fmt.Errorf("failed to verify signature of artifact %s: %w", ref, err)
+		}
+	}
+
+	img, err := desc.Image()
+	if err != nil {
+		return "", fmt.Errorf("failed to read artifact %s: %w", ref, err)
+	}
+	if err := extractLayers(img, destination); err != nil {
+		return "", fmt.Errorf("failed to extract artifact %s: %w", ref, err)
+	}
+
+	root, err := util.SingleDirOrRoot(destination)
+	if err != nil {
+		return "", err
+	}
+	// the path comes from the ApplicationDefinition and must not point outside of the artifact.
+	root = filepath.Clean(root)
+	sourcePath := filepath.Join(root, o.Source.Path)
+	if sourcePath != root && !strings.HasPrefix(sourcePath, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the artifact", o.Source.Path)
+	}
+	return sourcePath, nil
+}
+
+// reference returns the reference of the artifact to pull. If a digest is defined, it takes precedence over the tag.
+func (o OCISource) reference() (name.Reference, error) {
+	var opts []name.Option
+	if o.Source.PlainHTTP {
+		opts = append(opts, name.Insecure)
+	}
+
+	repo := strings.TrimPrefix(o.Source.URL, "oci://")
+	switch {
+	case o.Source.Digest != "":
+		return name.NewDigest(repo+"@"+o.Source.Digest, opts...)
+	case o.Source.Tag != "":
+		return name.NewTag(repo+":"+o.Source.Tag, opts...)
+	default: // This should not happen. The admission webhook prevents that.
+		return nil, errors.New("either tag or digest must be defined")
+	}
+}
+
+// authenticator returns the authn.Authenticator for the registry according to the credentials defined in the OCISource.
+// If no credentials are defined then anonymous authentication is used.
+func (o OCISource) authenticator(registry string) (authn.Authenticator, error) {
+	credentials := o.Source.Credentials
+	if credentials == nil {
+		return authn.Anonymous, nil
+	}
+
+	if credentials.RegistryConfigFile != nil {
+		registryConfigFile, err := util.GetCredentialFromSecret(o.Ctx, o.SeedClient, o.SecretNamespace, credentials.RegistryConfigFile.Name, credentials.RegistryConfigFile.Key)
+		if err != nil {
+			return nil, err
+		}
+		return authFromRegistryConfigFile([]byte(registryConfigFile), registry)
+	}
+
+	if credentials.Username != nil && credentials.Password != nil {
+		username, err := util.GetCredentialFromSecret(o.Ctx, o.SeedClient, o.SecretNamespace, credentials.Username.Name, credentials.Username.Key)
+		if err != nil {
+			return nil, err
+		}
+		password, err := util.GetCredentialFromSecret(o.Ctx, o.SeedClient, o.SecretNamespace, credentials.Password.Name, credentials.Password.Key)
+		if err != nil {
+			return nil, err
+		}
+		return &authn.Basic{Username: username, Password: password}, nil
+	}
+
+	return authn.Anonymous, nil
+}
+
+// authFromRegistryConfigFile returns the authn.Authenticator for registry from a dockercfg file (same format as
+// ~/.docker/config.json). If the file holds no credentials for the registry, anonymous authentication is used.
+func authFromRegistryConfigFile(registryConfigFile []byte, registry string) (authn.Authenticator, error) {
+	config := struct {
+		Auths map[string]authn.AuthConfig `json:"auths"`
+	}{}
+	if err := json.Unmarshal(registryConfigFile, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse registryConfigFile: %w", err)
+	}
+
+	for host, auth := range config.Auths {
+		host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+		host = strings.SplitN(host, "/", 2)[0]
+		if host == registry || (registry == name.DefaultRegistry && host == "docker.io") {
+			return authn.FromConfig(auth), nil
+		}
+	}
+
+	return authn.Anonymous, nil
+}
+
+// extractLayers extracts the layers of img into destination.
+func extractLayers(img v1.Image, destination string) error {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	for _, layerDesc := range manifest.Layers {
+		layer, err := img.LayerByDigest(layerDesc.Digest)
+		if err != nil {
+			return fmt.Errorf("failed to get layer %s: %w", layerDesc.Digest, err)
+		}
+
+		if err := extractLayer(layer, layerDesc, destination); err != nil {
+			return fmt.Errorf("failed to extract layer %s: %w", layerDesc.Digest, err)
+		}
+	}
+	return nil
+}
+
+func extractLayer(layer v1.Layer, desc v1.Descriptor, destination string) error {
+	content, err := layer.Compressed()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if isTarLayer(desc) {
+		return util.ExtractTar(content, destination)
+	}
+
+	title := desc.Annotations[ociTitleAnnotation]
+	if title == "" {
+		return fmt.Errorf("unsupported layer with media type %q and without %q annotation", desc.MediaType, ociTitleAnnotation)
+	}
+
+	target := filepath.Join(destination, filepath.Clean("/"+title))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	data, err := readAllLimited(content, maxLayerSize)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(target, data, 0644)
+}
+
+// isTarLayer returns true if the layer is a (possibly compressed) tarball, e.g. a Helm chart, a Flux artifact or an
+// image layer. oras stores files as-is with a tar media type, so layers with a title are only considered as tarballs if
+// they are flagged to be unpacked.
+func isTarLayer(desc v1.Descriptor) bool {
+	if _, hasTitle := desc.Annotations[ociTitleAnnotation]; hasTitle {
+		return desc.Annotations[orasUnpackAnnotation] == "true"
+	}
+	return strings.Contains(string(desc.MediaType), "tar")
+}
+
+// readAllLimited reads r until EOF and returns an error if more than limit bytes are read.
+func readAllLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("layer exceeds the maximum size of %d bytes", limit)
+	}
+	return data, nil
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const ociTestSecretNamespace = "kubermatic"
+
+func TestDownloadOCISource(t *testing.T) {
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	signingKey, publicKey := generateCosignKey(t)
+	_, otherPublicKey := generateCosignKey(t)
+
+	digest := pushArtifact(t, host+"/apps/manifests:1.0.0", map[string]string{
+		"manifests/cm.yaml":     "kind: ConfigMap",
+		"manifests/sub/cm.yaml": "kind: ConfigMap",
+	})
+	signArtifact(t, host+"/apps/manifests", digest, signingKey)
+
+	unsignedDigest := pushArtifact(t, host+"/apps/unsigned:1.0.0", map[string]string{"cm.yaml": "kind: ConfigMap"})
+
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cosign", Namespace: ociTestSecretNamespace},
+		Data: map[string][]byte{
+			"valid":   publicKey,
+			"invalid": otherPublicKey,
+		},
+	}
+	verify := func(key string) *appskubermaticv1.OCIVerification {
+		return &appskubermaticv1.OCIVerification{
+			PublicKey: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "cosign"}, Key: key},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		source        *appskubermaticv1.OCISource
+		expectedFiles []string
+		expectedErr   string
+	}{
+		{
+			name:          "scenario 1: pull by tag",
+			source:        &appskubermaticv1.OCISource{URL: "oci://" + host + "/apps/manifests", Tag: "1.0.0", PlainHTTP: true},
+			expectedFiles: []string{"cm.yaml", "sub/cm.yaml"},
+		},
+		{
+			name:          "scenario 2: pull by digest with path",
+			source:        &appskubermaticv1.OCISource{URL: "oci://" + host + "/apps/manifests", Digest: digest.String(), Path: "sub", PlainHTTP: true},
+			expectedFiles: []string{"cm.yaml"},
+		},
+		{
+			name:          "scenario 3: pull by tag and matching digest with valid signature",
+			source:        &appskubermaticv1.OCISource{URL: "oci://" + host + "/apps/manifests", Tag: "1.0.0", Digest: digest.String(), PlainHTTP: true, Verify: verify("valid")},
+			expectedFiles: []string{"cm.yaml", "sub/cm.yaml"},
+		},
+		{
+			name:        "scenario 4: tag does not match digest",
+			source:      &appskubermaticv1.OCISource{URL: "oci://" + host + "/apps/manifests", Tag: "1.0.0", Digest: unsignedDigest.String(), PlainHTTP: true},
+			expectedErr: "expected " + unsignedDigest.String(),
+		},
+		{
+			name:        "scenario 5: signature made with another key",
+			source:      &appskubermaticv1.OCISource{URL: "oci://" + host + "/apps/manifests", Tag: "1.0.0", PlainHTTP: true, Verify: verify("invalid")},
+			expectedErr: "no valid signature found",
+		},
+		{
+			name:        "scenario 6: artifact is not signed",
+			source:      &appskubermaticv1.OCISource{URL: "oci://" + host + "/apps/unsigned", Tag: "1.0.0", PlainHTTP: true, Verify: verify("valid")},
+			expectedErr: "failed to get signature",
+		},
+		{
+			name:        "scenario 7: tag does not exist",
+			source:      &appskubermaticv1.OCISource{URL: "oci://" + host + "/apps/manifests", Tag: "2.0.0", PlainHTTP: true},
+			expectedErr: "failed to get artifact",
+		},
+		{
+			name:        "scenario 8: path escapes the artifact",
+			source:      &appskubermaticv1.OCISource{URL: "oci://" + host + "/apps/manifests", Tag: "1.0.0", Path: "sub/../../..", PlainHTTP: true},
+			expectedErr: "escapes the artifact",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			destination := t.TempDir()
+			source := OCISource{
+				Ctx:             context.Background(),
+				SeedClient:      fakectrlruntimeclient.NewClientBuilder().WithObjects(keySecret).Build(),
+				Source:          tc.source,
+				SecretNamespace: ociTestSecretNamespace,
+			}
+
+			sourcePath, err := source.DownloadSource(destination)
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to download source: %v", err)
+			}
+
+			for _, file := range tc.expectedFiles {
+				if _, err := os.Stat(filepath.Join(sourcePath, file)); err != nil {
+					t.Errorf("expected file %s in source: %v", file, err)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractLayers(t *testing.T) {
+	tarLayer := static.NewLayer(tarGz(t, map[string]string{"chart/Chart.yaml": "name: chart"}), types.DockerLayer)
+	fileLayer := static.NewLayer([]byte("kind: ConfigMap"), "application/vnd.kubermatic.manifest.v1+yaml")
+	orasLayer := static.NewLayer(tarGz(t, map[string]string{"bundle/cm.yaml": "kind: ConfigMap"}), types.OCILayer)
+
+	img, err := mutate.Append(empty.Image,
+		mutate.Addendum{Layer: tarLayer},
+		mutate.Addendum{Layer: fileLayer, Annotations: map[string]string{ociTitleAnnotation: "../../escape/cm.yaml"}},
+		mutate.Addendum{Layer: orasLayer, Annotations: map[string]string{ociTitleAnnotation: "bundle", orasUnpackAnnotation: "true"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	destination := t.TempDir()
+	if err := extractLayers(img, destination); err != nil {
+		t.Fatalf("failed to extract layers: %v", err)
+	}
+
+	for _, file := range []string{"chart/Chart.yaml", "escape/cm.yaml", "bundle/cm.yaml"} {
+		if _, err := os.Stat(filepath.Join(destination, file)); err != nil {
+			t.Errorf("expected file %s to be extracted: %v", file, err)
+		}
+	}
+}
+
+func TestAuthFromRegistryConfigFile(t *testing.T) {
+	registryConfigFile := []byte(`{"auths": {
+		"https://registry.example.com/v1/": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("user:pass")) + `"},
+		"docker.io": {"username": "hub", "password": "secret"}
+	}}`)
+
+	testCases := []struct {
+		registry         string
+		expectedUsername string
+		expectedPassword string
+	}{
+		{registry: "registry.example.com", expectedUsername: "user", expectedPassword: "pass"},
+		{registry: name.DefaultRegistry, expectedUsername: "hub", expectedPassword: "secret"},
+		{registry: "other.example.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.registry, func(t *testing.T) {
+			auth, err := authFromRegistryConfigFile(registryConfigFile, tc.registry)
+			if err != nil {
+				t.Fatalf("failed to parse registry config file: %v", err)
+			}
+			config, err := auth.Authorization()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Username != tc.expectedUsername || config.Password != tc.expectedPassword {
+				t.Errorf("expected credentials %s:%s, got %s:%s", tc.expectedUsername, tc.expectedPassword, config.Username, config.Password)
+			}
+		})
+	}
+}
+
+// pushArtifact pushes an artifact made of a single tarball layer holding files and returns its digest.
+func pushArtifact(t *testing.T, reference string, files map[string]string) v1.Hash {
+	t.Helper()
+	ref, err := name.ParseReference(reference, name.Insecure)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := mutate.AppendLayers(empty.Image, static.NewLayer(tarGz(t, files), types.DockerLayer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatalf("failed to push artifact: %v", err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return digest
+}
+
+// signArtifact pushes a cosign signature of the artifact identified by digest.
+func signArtifact(t *testing.T, repository string, digest v1.Hash, key *ecdsa.PrivateKey) {
+	t.Helper()
+	repo, err := name.NewRepository(repository, name.Insecure)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, repo.String(), digest))
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sigImg, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       static.NewLayer(payload, "application/vnd.dev.cosign.simplesigning.v1+json"),
+		Annotations: map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(repo.Tag(fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex)), sigImg); err != nil {
+		t.Fatalf("failed to push signature: %v", err)
+	}
+}
+
+func generateCosignKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
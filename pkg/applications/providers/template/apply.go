@@ -0,0 +1,281 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
+	yamlutil "k8c.io/kubermatic/v2/pkg/util/yaml"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldManager is the field manager used for server-side apply of the objects rendered by the kustomize and manifest
+// template methods.
+const FieldManager = "kkp-application-installer"
+
+// applyOrder defines the kinds that must be applied before any other object. Objects of other kinds are applied
+// afterwards. Deletion happens in reverse order.
+var applyOrder = map[string]int{
+	"CustomResourceDefinition": 0,
+	"Namespace":                1,
+	"ServiceAccount":           2,
+	"ClusterRole":              2,
+	"ClusterRoleBinding":       2,
+	"Role":                     2,
+	"RoleBinding":              2,
+	"Secret":                   3,
+	"ConfigMap":                3,
+}
+
+const defaultApplyOrder = 4
+
+// decodeObjects decodes the multi-document YAML / JSON manifests into unstructured objects.
+func decodeObjects(manifests []byte) ([]*unstructured.Unstructured, error) {
+	docs, err := yamlutil.ParseMultipleDocuments(bytes.NewReader(manifests))
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]*unstructured.Unstructured, 0, len(docs))
+	for _, doc := range docs {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(doc.Raw); err != nil {
+			return nil, fmt.Errorf("failed to decode object: %w", err)
+		}
+
+		// a List is flattened into its items
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode list: %w", err)
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			continue
+		}
+
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("object %q has no apiVersion or kind", obj.GetName())
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// sortObjects sorts objects so that they can be applied in order (e.g. CRDs and namespaces first).
+func sortObjects(objects []*unstructured.Unstructured) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return kindOrder(objects[i].GetKind()) < kindOrder(objects[j].GetKind())
+	})
+}
+
+func kindOrder(kind string) int {
+	if order, ok := applyOrder[kind]; ok {
+		return order
+	}
+	return defaultApplyOrder
+}
+
+// toAppliedResource returns the AppliedResource identifying obj.
+func toAppliedResource(obj *unstructured.Unstructured) appskubermaticv1.AppliedResource {
+	gvk := obj.GroupVersionKind()
+	return appskubermaticv1.AppliedResource{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+// resourceKey identifies an applied resource independently of its API version, so that an object whose apiVersion has been
+// bumped in the manifests is not pruned.
+func resourceKey(res appskubermaticv1.AppliedResource) string {
+	return res.Group + "/" + res.Kind + "/" + res.Namespace + "/" + res.Name
+}
+
+// toObject returns an unstructured object that only holds the identity of res.
+func toObject(res appskubermaticv1.AppliedResource) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: res.Group, Version: res.Version, Kind: res.Kind})
+	obj.SetNamespace(res.Namespace)
+	obj.SetName(res.Name)
+	return obj
+}
+
+// prunableResources returns the resources from previous that are not part of current, in reverse apply order.
+func prunableResources(previous []appskubermaticv1.AppliedResource, current []appskubermaticv1.AppliedResource) []appskubermaticv1.AppliedResource {
+	keep := make(map[string]struct{}, len(current))
+	for _, res := range current {
+		keep[resourceKey(res)] = struct{}{}
+	}
+
+	var prune []appskubermaticv1.AppliedResource
+	for _, res := range previous {
+		if _, ok := keep[resourceKey(res)]; !ok {
+			prune = append(prune, res)
+		}
+	}
+	sortForDeletion(prune)
+
+	return prune
+}
+
+// sortForDeletion sorts resources in the reverse apply order (e.g. CRDs and namespaces last).
+func sortForDeletion(resources []appskubermaticv1.AppliedResource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return kindOrder(resources[i].Kind) > kindOrder(resources[j].Kind)
+	})
+}
+
+// setDefaultNamespace sets namespace on the namespaced objects that do not define one.
+func setDefaultNamespace(mapper meta.RESTMapper, objects []*unstructured.Unstructured, namespace string) error {
+	crdScopes := crdScopesFromObjects(objects)
+
+	for _, obj := range objects {
+		if obj.GetNamespace() != "" {
+			continue
+		}
+
+		gvk := obj.GroupVersionKind()
+		var namespaced bool
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		switch {
+		case err == nil:
+			namespaced = mapping.Scope.Name() == meta.RESTScopeNameNamespace
+		case meta.IsNoMatchError(err):
+			// The kind may be defined by a CRD that is part of the manifests and not yet installed.
+			scope, found := crdScopes[gvk.GroupKind()]
+			if !found {
+				return fmt.Errorf("no kind %s is registered and no CustomResourceDefinition in the manifests defines it", gvk)
+			}
+			namespaced = scope == "Namespaced"
+		default:
+			return fmt.Errorf("failed to get REST mapping for %s: %w", gvk, err)
+		}
+
+		if namespaced {
+			obj.SetNamespace(namespace)
+		}
+	}
+	return nil
+}
+
+// crdScopesFromObjects returns the scope (Namespaced or Cluster) of the kinds defined by the CustomResourceDefinitions
+// found in objects.
+func crdScopesFromObjects(objects []*unstructured.Unstructured) map[schema.GroupKind]string {
+	scopes := map[schema.GroupKind]string{}
+	for _, obj := range objects {
+		if obj.GroupVersionKind().GroupKind() != (schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}) {
+			continue
+		}
+
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		scope, _, _ := unstructured.NestedString(obj.Object, "spec", "scope")
+		scopes[schema.GroupKind{Group: group, Kind: kind}] = scope
+	}
+	return scopes
+}
+
+// applyObjects applies objects into the user cluster with server-side apply, then deletes the objects listed in
+// previouslyApplied that are not part of objects anymore.
+// It returns a StatusUpdater that records the applied objects into status.AppliedResources. If an error occurred,
+// previouslyApplied objects are kept in the status, so they can be pruned by a later reconciliation.
+func applyObjects(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, namespace string, objects []*unstructured.Unstructured, previouslyApplied []appskubermaticv1.AppliedResource) (util.StatusUpdater, error) {
+	if err := setDefaultNamespace(userClient.RESTMapper(), objects, namespace); err != nil {
+		return util.NoStatusUpdate, err
+	}
+	sortObjects(objects)
+
+	var applied []appskubermaticv1.AppliedResource
+	var errs []error
+	for _, obj := range objects {
+		res := toAppliedResource(obj)
+		if err := userClient.Patch(ctx, obj, ctrlruntimeclient.Apply, ctrlruntimeclient.FieldOwner(FieldManager), ctrlruntimeclient.ForceOwnership); err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply %s %s: %w", res.Kind, ctrlruntimeclient.ObjectKeyFromObject(obj), err))
+			continue
+		}
+		applied = append(applied, res)
+	}
+
+	if len(errs) > 0 {
+		return appliedResourcesUpdater(mergeResources(previouslyApplied, applied)), kerrors.NewAggregate(errs)
+	}
+
+	prune := prunableResources(previouslyApplied, applied)
+	notPruned, err := deleteResources(ctx, log, userClient, prune)
+	if err != nil {
+		return appliedResourcesUpdater(mergeResources(notPruned, applied)), err
+	}
+
+	return appliedResourcesUpdater(applied), nil
+}
+
+// deleteResources deletes the resources from the user cluster. It returns the resources that could not be deleted.
+func deleteResources(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, resources []appskubermaticv1.AppliedResource) ([]appskubermaticv1.AppliedResource, error) {
+	var remaining []appskubermaticv1.AppliedResource
+	var errs []error
+	for _, res := range resources {
+		log.Debugw("deleting object", "kind", res.Kind, "namespace", res.Namespace, "name", res.Name)
+		if err := userClient.Delete(ctx, toObject(res), ctrlruntimeclient.PropagationPolicy("Background")); err != nil && !isGoneError(err) {
+			remaining = append(remaining, res)
+			errs = append(errs, fmt.Errorf("failed to delete %s %s/%s: %w", res.Kind, res.Namespace, res.Name, err))
+		}
+	}
+	return remaining, kerrors.NewAggregate(errs)
+}
+
+// isGoneError returns true if the object or its kind does not exist anymore.
+func isGoneError(err error) bool {
+	return apierrors.IsNotFound(err) || meta.IsNoMatchError(err)
+}
+
+// mergeResources returns the union of a and b without duplicates.
+func mergeResources(a []appskubermaticv1.AppliedResource, b []appskubermaticv1.AppliedResource) []appskubermaticv1.AppliedResource {
+	seen := map[string]struct{}{}
+	var res []appskubermaticv1.AppliedResource
+	for _, r := range append(append([]appskubermaticv1.AppliedResource{}, b...), a...) {
+		if _, ok := seen[resourceKey(r)]; ok {
+			continue
+		}
+		seen[resourceKey(r)] = struct{}{}
+		res = append(res, r)
+	}
+	return res
+}
+
+func appliedResourcesUpdater(resources []appskubermaticv1.AppliedResource) util.StatusUpdater {
+	return func(status *appskubermaticv1.ApplicationInstallationStatus) {
+		status.AppliedResources = resources
+	}
+}
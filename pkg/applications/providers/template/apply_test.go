@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"reflect"
+	"testing"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const testManifests = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+---
+# only a comment
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.example.com
+spec:
+  group: example.com
+  scope: Namespaced
+  names:
+    kind: Foo
+---
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: foo
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: deploy
+    namespace: other
+`
+
+func TestDecodeAndSortObjects(t *testing.T) {
+	objects, err := decodeObjects([]byte(testManifests))
+	if err != nil {
+		t.Fatalf("failed to decode objects: %v", err)
+	}
+	sortObjects(objects)
+
+	var kinds []string
+	for _, obj := range objects {
+		kinds = append(kinds, obj.GetKind())
+	}
+
+	expected := []string{"CustomResourceDefinition", "Namespace", "ConfigMap", "Foo", "Deployment"}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("expected objects %v, got %v", expected, kinds)
+	}
+}
+
+func TestDecodeObjectsWithoutKind(t *testing.T) {
+	if _, err := decodeObjects([]byte("metadata:\n  name: foo\n")); err == nil {
+		t.Error("expected an error for an object without apiVersion and kind")
+	}
+}
+
+func TestSetDefaultNamespace(t *testing.T) {
+	objects, err := decodeObjects([]byte(testManifests))
+	if err != nil {
+		t.Fatalf("failed to decode objects: %v", err)
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+
+	if err := setDefaultNamespace(mapper, objects, "app-ns"); err != nil {
+		t.Fatalf("failed to set default namespace: %v", err)
+	}
+
+	namespaces := map[string]string{}
+	for _, obj := range objects {
+		namespaces[obj.GetKind()] = obj.GetNamespace()
+	}
+
+	expected := map[string]string{
+		"ConfigMap":                "app-ns",
+		"CustomResourceDefinition": "",
+		"Foo":                      "app-ns",
+		"Namespace":                "",
+		"Deployment":               "other",
+	}
+	if !reflect.DeepEqual(namespaces, expected) {
+		t.Errorf("expected namespaces %v, got %v", expected, namespaces)
+	}
+}
+
+func TestSetDefaultNamespaceUnknownKind(t *testing.T) {
+	objects, err := decodeObjects([]byte("apiVersion: example.com/v1\nkind: Bar\nmetadata:\n  name: bar\n"))
+	if err != nil {
+		t.Fatalf("failed to decode objects: %v", err)
+	}
+
+	if err := setDefaultNamespace(meta.NewDefaultRESTMapper(nil), objects, "app-ns"); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
+
+func TestPrunableResources(t *testing.T) {
+	cm := appskubermaticv1.AppliedResource{Version: "v1", Kind: "ConfigMap", Namespace: "default", Name: "cm"}
+	ns := appskubermaticv1.AppliedResource{Version: "v1", Kind: "Namespace", Name: "ns"}
+	deploy := appskubermaticv1.AppliedResource{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "default", Name: "deploy"}
+	deployV1beta1 := appskubermaticv1.AppliedResource{Group: "apps", Version: "v1beta1", Kind: "Deployment", Namespace: "default", Name: "deploy"}
+
+	tests := []struct {
+		name     string
+		previous []appskubermaticv1.AppliedResource
+		current  []appskubermaticv1.AppliedResource
+		expected []appskubermaticv1.AppliedResource
+	}{
+		{
+			name:     "nothing is pruned on first installation",
+			previous: nil,
+			current:  []appskubermaticv1.AppliedResource{cm, deploy},
+			expected: nil,
+		},
+		{
+			name:     "removed objects are pruned in reverse apply order",
+			previous: []appskubermaticv1.AppliedResource{ns, cm, deploy},
+			current:  []appskubermaticv1.AppliedResource{cm},
+			expected: []appskubermaticv1.AppliedResource{deploy, ns},
+		},
+		{
+			name:     "objects whose api version changed are not pruned",
+			previous: []appskubermaticv1.AppliedResource{deployV1beta1},
+			current:  []appskubermaticv1.AppliedResource{deploy},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if res := prunableResources(tt.previous, tt.current); !reflect.DeepEqual(res, tt.expected) {
+				t.Errorf("prunableResources() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+}
@@ -25,12 +25,14 @@ import (
 	"path"
 
 	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/release"
 
 	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/applications/helmclient"
 	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -52,6 +54,9 @@ type HelmTemplate struct {
 
 	// SeedClient to seed cluster.
 	SeedClient ctrlruntimeclient.Client
+
+	// UserClient to user cluster.
+	UserClient ctrlruntimeclient.Client
 }
 
 // InstallOrUpgrade the chart located at chartLoc with parameters (releaseName, values) defined applicationInstallation into cluster.
@@ -99,27 +104,83 @@ func (h HelmTemplate) InstallOrUpgrade(chartLoc string, appDefinition *appskuber
 	}
 
 	helmRelease, err := helmClient.InstallOrUpgrade(chartLoc, getReleaseName(applicationInstallation), values, *deployOpts, auth)
-	statusUpdater := util.NoStatusUpdate
 
 	// In some case, even if an error occurred, the helmRelease is updated.
-	if helmRelease != nil {
-		statusUpdater = func(status *appskubermaticv1.ApplicationInstallationStatus) {
-			status.HelmRelease = &appskubermaticv1.HelmRelease{
-				Name:    helmRelease.Name,
-				Version: helmRelease.Version,
-				Info: &appskubermaticv1.HelmReleaseInfo{
-					FirstDeployed: metav1.Time(helmRelease.Info.FirstDeployed),
-					LastDeployed:  metav1.Time(helmRelease.Info.LastDeployed),
-					Deleted:       metav1.Time(helmRelease.Info.Deleted),
-					Description:   helmRelease.Info.Description,
-					Status:        helmRelease.Info.Status,
-					Notes:         helmRelease.Info.Notes,
-				},
-			}
-		}
+	return helmReleaseStatusUpdater(helmRelease), err
+}
+
+// Rollback the helm release of the application to the given revision.
+func (h HelmTemplate) Rollback(appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error) {
+	helmCacheDir, err := util.CreateHelmTempDir(h.CacheDir)
+	if err != nil {
+		return util.NoStatusUpdate, err
 	}
+	defer util.CleanUpHelmTempDir(helmCacheDir, h.Log)
 
-	return statusUpdater, err
+	deployOpts, err := getDeployOpts(appDefinition, applicationInstallation)
+	if err != nil {
+		return util.NoStatusUpdate, err
+	}
+
+	restClientGetter := &genericclioptions.ConfigFlags{
+		KubeConfig: &h.Kubeconfig,
+		Namespace:  &applicationInstallation.Spec.Namespace.Name,
+	}
+
+	helmClient, err := helmclient.NewClient(
+		h.Ctx,
+		restClientGetter,
+		helmclient.NewSettings(helmCacheDir),
+		applicationInstallation.Spec.Namespace.Name,
+		h.Log)
+
+	if err != nil {
+		return util.NoStatusUpdate, err
+	}
+
+	helmRelease, err := helmClient.Rollback(getReleaseName(applicationInstallation), revision, *deployOpts)
+
+	return helmReleaseStatusUpdater(helmRelease), err
+}
+
+// Render returns the objects of the deployed helm release of the application. Hooks are not part of the returned objects.
+func (h HelmTemplate) Render(source string, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]*unstructured.Unstructured, error) {
+	helmCacheDir, err := util.CreateHelmTempDir(h.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+	defer util.CleanUpHelmTempDir(helmCacheDir, h.Log)
+
+	restClientGetter := &genericclioptions.ConfigFlags{
+		KubeConfig: &h.Kubeconfig,
+		Namespace:  &applicationInstallation.Spec.Namespace.Name,
+	}
+
+	helmClient, err := helmclient.NewClient(
+		h.Ctx,
+		restClientGetter,
+		helmclient.NewSettings(helmCacheDir),
+		applicationInstallation.Spec.Namespace.Name,
+		h.Log)
+
+	if err != nil {
+		return nil, err
+	}
+
+	helmRelease, err := helmClient.GetDeployedRelease(getReleaseName(applicationInstallation))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployed release: %w", err)
+	}
+
+	objects, err := decodeObjects([]byte(helmRelease.Manifest))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setDefaultNamespace(h.UserClient.RESTMapper(), objects, applicationInstallation.Spec.Namespace.Name); err != nil {
+		return nil, err
+	}
+	return objects, nil
 }
 
 // Uninstall the chart from the user cluster.
@@ -169,6 +230,28 @@ func (h HelmTemplate) Uninstall(applicationInstallation *appskubermaticv1.Applic
 	return statusUpdater, err
 }
 
+// helmReleaseStatusUpdater returns a StatusUpdater that sets status.HelmRelease from helmRelease. If helmRelease is nil,
+// the status is not updated.
+func helmReleaseStatusUpdater(helmRelease *release.Release) util.StatusUpdater {
+	if helmRelease == nil {
+		return util.NoStatusUpdate
+	}
+	return func(status *appskubermaticv1.ApplicationInstallationStatus) {
+		status.HelmRelease = &appskubermaticv1.HelmRelease{
+			Name:    helmRelease.Name,
+			Version: helmRelease.Version,
+			Info: &appskubermaticv1.HelmReleaseInfo{
+				FirstDeployed: metav1.Time(helmRelease.Info.FirstDeployed),
+				LastDeployed:  metav1.Time(helmRelease.Info.LastDeployed),
+				Deleted:       metav1.Time(helmRelease.Info.Deleted),
+				Description:   helmRelease.Info.Description,
+				Status:        helmRelease.Info.Status,
+				Notes:         helmRelease.Info.Notes,
+			},
+		}
+	}
+}
+
 // getReleaseName computes the release name from the applicationInstallation.
 // The releaseName length must be less or equal to 53. So we first start to compute this release Name:
 //
@@ -196,18 +279,18 @@ func getReleaseName(applicationInstallation *appskubermaticv1.ApplicationInstall
 }
 
 // getDeployOpts builds helmclient.DeployOpts from values provided by appInstall or fallback to the values of appDefinition or fallback to the default options.
-// Default options are wait=false that implies timeout=0 and atomic=false.
+// Default options are wait=false that implies timeout=0 and atomic=false, and Helm keeps helmclient.DefaultMaxHistory revisions.
 func getDeployOpts(appDefinition *appskubermaticv1.ApplicationDefinition, appInstall *appskubermaticv1.ApplicationInstallation) (*helmclient.DeployOpts, error) {
 	// Read options from applicationInstallation.
 	if appInstall.Spec.DeployOptions != nil && appInstall.Spec.DeployOptions.Helm != nil {
-		return helmclient.NewDeployOpts(appInstall.Spec.DeployOptions.Helm.Wait, appInstall.Spec.DeployOptions.Helm.Timeout.Duration, appInstall.Spec.DeployOptions.Helm.Atomic, appInstall.Spec.DeployOptions.Helm.EnableDNS)
+		return helmclient.NewDeployOpts(appInstall.Spec.DeployOptions.Helm.Wait, appInstall.Spec.DeployOptions.Helm.Timeout.Duration, appInstall.Spec.DeployOptions.Helm.Atomic, appInstall.Spec.DeployOptions.Helm.EnableDNS, appInstall.Spec.DeployOptions.Helm.MaxHistory)
 	}
 
 	// Fallback to options defined in ApplicationDefinition.
 	if appDefinition.Spec.DefaultDeployOptions != nil && appDefinition.Spec.DefaultDeployOptions.Helm != nil {
-		return helmclient.NewDeployOpts(appDefinition.Spec.DefaultDeployOptions.Helm.Wait, appDefinition.Spec.DefaultDeployOptions.Helm.Timeout.Duration, appDefinition.Spec.DefaultDeployOptions.Helm.Atomic, appDefinition.Spec.DefaultDeployOptions.Helm.EnableDNS)
+		return helmclient.NewDeployOpts(appDefinition.Spec.DefaultDeployOptions.Helm.Wait, appDefinition.Spec.DefaultDeployOptions.Helm.Timeout.Duration, appDefinition.Spec.DefaultDeployOptions.Helm.Atomic, appDefinition.Spec.DefaultDeployOptions.Helm.EnableDNS, appDefinition.Spec.DefaultDeployOptions.Helm.MaxHistory)
 	}
 
 	// Fallback to default options.
-	return helmclient.NewDeployOpts(false, 0, false, false)
+	return helmclient.NewDeployOpts(false, 0, false, false, 0)
 }
@@ -100,7 +100,7 @@ func TestGetDeployOps(t *testing.T) {
 					EnableDNS: true,
 				}}},
 			},
-			want:    newDeployOpts(t, true, 1000, true, true),
+			want:    newDeployOpts(t, true, 1000, true, true, 0),
 			wantErr: false,
 		},
 		{
@@ -116,7 +116,7 @@ func TestGetDeployOps(t *testing.T) {
 					EnableDNS: true,
 				}}},
 			},
-			want:    newDeployOpts(t, true, 1000, true, true),
+			want:    newDeployOpts(t, true, 1000, true, true, 0),
 			wantErr: false,
 		},
 		{
@@ -126,13 +126,14 @@ func TestGetDeployOps(t *testing.T) {
 			},
 			appInstall: &appskubermaticv1.ApplicationInstallation{
 				Spec: appskubermaticv1.ApplicationInstallationSpec{DeployOptions: &appskubermaticv1.DeployOptions{Helm: &appskubermaticv1.HelmDeployOptions{
-					Wait:      true,
-					Timeout:   metav1.Duration{Duration: 1000},
-					Atomic:    true,
-					EnableDNS: true,
+					Wait:       true,
+					Timeout:    metav1.Duration{Duration: 1000},
+					Atomic:     true,
+					EnableDNS:  true,
+					MaxHistory: 5,
 				}}},
 			},
-			want:    newDeployOpts(t, true, 1000, true, true),
+			want:    newDeployOpts(t, true, 1000, true, true, 5),
 			wantErr: false,
 		},
 		{
@@ -148,7 +149,7 @@ func TestGetDeployOps(t *testing.T) {
 			appInstall: &appskubermaticv1.ApplicationInstallation{
 				Spec: appskubermaticv1.ApplicationInstallationSpec{DeployOptions: &appskubermaticv1.DeployOptions{Helm: nil}},
 			},
-			want:    newDeployOpts(t, true, 500, false, true),
+			want:    newDeployOpts(t, true, 500, false, true, 0),
 			wantErr: false,
 		},
 		{
@@ -164,7 +165,7 @@ func TestGetDeployOps(t *testing.T) {
 			appInstall: &appskubermaticv1.ApplicationInstallation{
 				Spec: appskubermaticv1.ApplicationInstallationSpec{DeployOptions: nil},
 			},
-			want:    newDeployOpts(t, true, 500, false, true),
+			want:    newDeployOpts(t, true, 500, false, true, 0),
 			wantErr: false,
 		},
 		{
@@ -175,7 +176,7 @@ func TestGetDeployOps(t *testing.T) {
 			appInstall: &appskubermaticv1.ApplicationInstallation{
 				Spec: appskubermaticv1.ApplicationInstallationSpec{DeployOptions: nil},
 			},
-			want:    newDeployOpts(t, false, 0, false, false),
+			want:    newDeployOpts(t, false, 0, false, false, 0),
 			wantErr: false,
 		},
 		{
@@ -222,9 +223,9 @@ func TestGetDeployOps(t *testing.T) {
 	}
 }
 
-func newDeployOpts(t *testing.T, wait bool, timeout time.Duration, atomic bool, enableDns bool) *helmclient.DeployOpts {
+func newDeployOpts(t *testing.T, wait bool, timeout time.Duration, atomic bool, enableDns bool, maxHistory int) *helmclient.DeployOpts {
 	t.Helper()
-	deployOps, err := helmclient.NewDeployOpts(wait, timeout, atomic, enableDns)
+	deployOps, err := helmclient.NewDeployOpts(wait, timeout, atomic, enableDns, maxHistory)
 	if err != nil {
 		t.Fatalf("failed to build deployOpts: %s", err)
 	}
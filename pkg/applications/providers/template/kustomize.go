@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KustomizeTemplate builds a Kustomization and applies the result into the user cluster using server-side apply.
+type KustomizeTemplate struct {
+	Ctx context.Context
+
+	Log *zap.SugaredLogger
+
+	// UserClient to user cluster.
+	UserClient ctrlruntimeclient.Client
+}
+
+// InstallOrUpgrade builds the kustomization located at source and applies the result into the user cluster. Objects that
+// have been removed from the kustomization since the last installation are pruned.
+func (k KustomizeTemplate) InstallOrUpgrade(source string, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error) {
+	dir, err := resolveSourceDir(source)
+	if err != nil {
+		return util.NoStatusUpdate, err
+	}
+
+	objects, err := buildKustomization(dir)
+	if err != nil {
+		return util.NoStatusUpdate, err
+	}
+
+	return applyObjects(k.Ctx, k.Log, k.UserClient, applicationInstallation.Spec.Namespace.Name, objects, applicationInstallation.Status.AppliedResources)
+}
+
+// Uninstall deletes the objects applied by the application from the user cluster.
+func (k KustomizeTemplate) Uninstall(applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error) {
+	return uninstallAppliedResources(k.Ctx, k.Log, k.UserClient, applicationInstallation)
+}
+
+// Rollback is not supported by KustomizeTemplate.
+func (k KustomizeTemplate) Rollback(appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error) {
+	return util.NoStatusUpdate, errors.New("rollback is not supported by template method 'kustomize'")
+}
+
+// Render builds the kustomization located at source and returns the resulting objects.
+func (k KustomizeTemplate) Render(source string, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]*unstructured.Unstructured, error) {
+	dir, err := resolveSourceDir(source)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := buildKustomization(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setDefaultNamespace(k.UserClient.RESTMapper(), objects, applicationInstallation.Spec.Namespace.Name); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// buildKustomization runs kustomize build on dir and returns the resulting objects.
+func buildKustomization(dir string) ([]*unstructured.Unstructured, error) {
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+
+	resMap, err := kustomizer.Run(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization: %w", err)
+	}
+
+	manifests, err := resMap.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize kustomization: %w", err)
+	}
+
+	return decodeObjects(manifests)
+}
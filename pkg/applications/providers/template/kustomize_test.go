@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildKustomization(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base/kustomization.yaml": "resources:\n- cm.yaml\n",
+		"base/cm.yaml":            configMap("cm"),
+		"overlay/kustomization.yaml": `resources:
+- ../base
+namePrefix: prod-
+namespace: prod
+commonLabels:
+  app: example
+`,
+	})
+
+	objects, err := buildKustomization(filepath.Join(dir, "overlay"))
+	if err != nil {
+		t.Fatalf("failed to build kustomization: %v", err)
+	}
+
+	if len(objects) != 1 {
+		t.Fatalf("expected 1 object, got %d", len(objects))
+	}
+
+	obj := objects[0]
+	if obj.GetName() != "prod-cm" || obj.GetNamespace() != "prod" || obj.GetLabels()["app"] != "example" {
+		t.Errorf("kustomization has not been applied correctly, got name=%q namespace=%q labels=%v", obj.GetName(), obj.GetNamespace(), obj.GetLabels())
+	}
+}
+
+func TestBuildKustomizationWithoutKustomizationFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"cm.yaml": configMap("cm")})
+
+	if _, err := buildKustomization(dir); err == nil {
+		t.Error("expected an error when the directory has no kustomization file")
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManifestTemplate applies plain YAML / JSON manifests into the user cluster using server-side apply.
+type ManifestTemplate struct {
+	Ctx context.Context
+
+	Log *zap.SugaredLogger
+
+	// UserClient to user cluster.
+	UserClient ctrlruntimeclient.Client
+}
+
+// InstallOrUpgrade applies all manifests found in the source directory into the user cluster and prunes the objects
+// that have been removed from the manifests since the last installation.
+func (m ManifestTemplate) InstallOrUpgrade(source string, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error) {
+	dir, err := resolveSourceDir(source)
+	if err != nil {
+		return util.NoStatusUpdate, err
+	}
+
+	objects, err := loadManifests(dir)
+	if err != nil {
+		return util.NoStatusUpdate, err
+	}
+
+	return applyObjects(m.Ctx, m.Log, m.UserClient, applicationInstallation.Spec.Namespace.Name, objects, applicationInstallation.Status.AppliedResources)
+}
+
+// Uninstall deletes the objects applied by the application from the user cluster.
+func (m ManifestTemplate) Uninstall(applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error) {
+	return uninstallAppliedResources(m.Ctx, m.Log, m.UserClient, applicationInstallation)
+}
+
+// Rollback is not supported by ManifestTemplate.
+func (m ManifestTemplate) Rollback(appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error) {
+	return util.NoStatusUpdate, errors.New("rollback is not supported by template method 'manifest'")
+}
+
+// Render returns the objects defined by the manifests found in the source directory.
+func (m ManifestTemplate) Render(source string, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]*unstructured.Unstructured, error) {
+	dir, err := resolveSourceDir(source)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := loadManifests(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setDefaultNamespace(m.UserClient.RESTMapper(), objects, applicationInstallation.Spec.Namespace.Name); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// uninstallAppliedResources deletes the objects listed in applicationInstallation.Status.AppliedResources. The objects
+// that could not be deleted are kept in the status.
+func uninstallAppliedResources(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error) {
+	resources := append([]appskubermaticv1.AppliedResource{}, applicationInstallation.Status.AppliedResources...)
+	sortForDeletion(resources)
+
+	remaining, err := deleteResources(ctx, log, userClient, resources)
+	return appliedResourcesUpdater(remaining), err
+}
+
+// loadManifests reads all files with extension .yaml, .yml or .json in dir and its subdirectories (in lexical order)
+// and decodes them into unstructured objects. Hidden files and directories are skipped.
+func loadManifests(dir string) ([]*unstructured.Unstructured, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manifests: %w", err)
+	}
+	sort.Strings(files)
+
+	var objects []*unstructured.Unstructured
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+
+		objs, err := decodeObjects(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", strings.TrimPrefix(file, dir+string(filepath.Separator)), err)
+		}
+		objects = append(objects, objs...)
+	}
+
+	if len(objects) == 0 {
+		return nil, errors.New("no manifests found in application source")
+	}
+	return objects, nil
+}
+
+// resolveSourceDir returns the directory containing the application's source. If source is an archive (e.g. a packaged
+// chart downloaded by the helm source), it's extracted next to it. If the archive contains a single top level
+// directory, this directory is returned.
+func resolveSourceDir(source string) (string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to read application source: %w", err)
+	}
+	if info.IsDir() {
+		return source, nil
+	}
+
+	archive, err := os.Open(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to read application source: %w", err)
+	}
+	defer archive.Close()
+
+	dest := strings.TrimSuffix(strings.TrimSuffix(source, ".tgz"), ".tar.gz") + "-extracted"
+	if err := util.ExtractTar(archive, dest); err != nil {
+		return "", fmt.Errorf("failed to extract application source: %w", err)
+	}
+
+	return util.SingleDirOrRoot(dest)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func configMap(name string) string {
+	return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n"
+}
+
+func TestLoadManifests(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"b.yaml":          configMap("b"),
+		"a.yml":           configMap("a"),
+		"sub/c.json":      `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "c"}}`,
+		"README.md":       "not a manifest",
+		".hidden/d.yaml":  configMap("d"),
+		".ignored.yaml":   configMap("e"),
+		"sub/values.toml": "foo = 1",
+	})
+
+	objects, err := loadManifests(dir)
+	if err != nil {
+		t.Fatalf("failed to load manifests: %v", err)
+	}
+
+	var names []string
+	for _, obj := range objects {
+		names = append(names, obj.GetName())
+	}
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected objects %v, got %v", expected, names)
+	}
+}
+
+func TestLoadManifestsEmptyDir(t *testing.T) {
+	if _, err := loadManifests(t.TempDir()); err == nil {
+		t.Error("expected an error when no manifests are found")
+	}
+}
+
+func TestResolveSourceDirFromArchive(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "manifests-1.0.0.tgz")
+
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+	for name, content := range map[string]string{
+		"manifests/cm.yaml":       configMap("cm"),
+		"../../escape/cm.yaml":    configMap("escape"),
+		"manifests/sub/cm2.yaml":  configMap("cm2"),
+		"manifests/sub/notes.txt": "notes",
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gzw.Close()
+	f.Close()
+
+	resolved, err := resolveSourceDir(archive)
+	if err != nil {
+		t.Fatalf("failed to resolve source dir: %v", err)
+	}
+
+	// the archive has 2 top-level directories (manifests and escape), so the extraction root is returned.
+	if expected := filepath.Join(dir, "manifests-1.0.0-extracted"); resolved != expected {
+		t.Errorf("expected source dir %q, got %q", expected, resolved)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape")); !os.IsNotExist(err) {
+		t.Error("archive entries must not be extracted outside of the destination")
+	}
+
+	objects, err := loadManifests(resolved)
+	if err != nil {
+		t.Fatalf("failed to load manifests: %v", err)
+	}
+	if len(objects) != 3 {
+		t.Errorf("expected 3 objects, got %d", len(objects))
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/applications/providers/template"
 	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -46,12 +47,14 @@ func NewSourceProvider(ctx context.Context, log *zap.SugaredLogger, client ctrlr
 		return source.HelmSource{Ctx: ctx, SeedClient: client, Kubeconfig: kubeconfig, CacheDir: cacheDir, Log: log, Source: appSource.Helm, SecretNamespace: secretNamespace}, nil
 	case appSource.Git != nil:
 		return source.GitSource{Ctx: ctx, SeedClient: client, Source: appSource.Git, SecretNamespace: secretNamespace}, nil
+	case appSource.OCI != nil:
+		return source.OCISource{Ctx: ctx, SeedClient: client, Source: appSource.OCI, SecretNamespace: secretNamespace}, nil
 	default: // This should not happen. The admission webhook prevents that.
 		return nil, errors.New("no source found")
 	}
 }
 
-// TemplateProvider is an interface to install, upgrade or uninstall application.
+// TemplateProvider is an interface to install, upgrade, roll back or uninstall application.
 type TemplateProvider interface {
 
 	// InstallOrUpgrade the application from the source.
@@ -59,13 +62,24 @@ type TemplateProvider interface {
 
 	// Uninstall the application.
 	Uninstall(applicationInstallation *appskubermaticv1.ApplicationInstallation) (util.StatusUpdater, error)
+
+	// Rollback the application to the given revision of its release. Only supported by the helm template method.
+	Rollback(appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error)
+
+	// Render returns the objects deployed by the application, with their namespace defaulted. For the helm template
+	// method, the objects are read from the deployed release and source is ignored.
+	Render(source string, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]*unstructured.Unstructured, error)
 }
 
 // NewTemplateProvider return the concrete implementation of TemplateProvider according to the templateMethod.
-func NewTemplateProvider(ctx context.Context, seedClient ctrlruntimeclient.Client, kubeconfig string, cacheDir string, log *zap.SugaredLogger, appInstallation *appskubermaticv1.ApplicationInstallation, secretNamespace string) (TemplateProvider, error) {
+func NewTemplateProvider(ctx context.Context, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, kubeconfig string, cacheDir string, log *zap.SugaredLogger, appInstallation *appskubermaticv1.ApplicationInstallation, secretNamespace string) (TemplateProvider, error) {
 	switch appInstallation.Status.Method {
 	case appskubermaticv1.HelmTemplateMethod:
-		return template.HelmTemplate{Ctx: ctx, Kubeconfig: kubeconfig, CacheDir: cacheDir, Log: log, SecretNamespace: secretNamespace, SeedClient: seedClient}, nil
+		return template.HelmTemplate{Ctx: ctx, Kubeconfig: kubeconfig, CacheDir: cacheDir, Log: log, SecretNamespace: secretNamespace, SeedClient: seedClient, UserClient: userClient}, nil
+	case appskubermaticv1.KustomizeTemplateMethod:
+		return template.KustomizeTemplate{Ctx: ctx, Log: log, UserClient: userClient}, nil
+	case appskubermaticv1.ManifestTemplateMethod:
+		return template.ManifestTemplate{Ctx: ctx, Log: log, UserClient: userClient}, nil
 	default:
 		return nil, fmt.Errorf("template method '%v' not implemented", appInstallation.Status.Method)
 	}
@@ -17,9 +17,15 @@ limitations under the License.
 package util
 
 import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 
 	"go.uber.org/zap"
 
@@ -106,3 +112,76 @@ func HelmAuthFromCredentials(ctx context.Context, client ctrlruntimeclient.Clien
 	}
 	return auth, nil
 }
+
+// maxExtractedFileSize is the maximum size of a single file extracted from an archive.
+const maxExtractedFileSize = 10 * 1024 * 1024
+
+// ExtractTar extracts the regular files and directories of the tarball read from r into dest. The tarball may be
+// compressed with gzip. Entries can not be extracted outside of dest.
+func ExtractTar(r io.Reader, dest string) error {
+	br := bufio.NewReader(r)
+	var reader io.Reader = br
+
+	// gzip magic number
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzr, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("failed to read gzip archive: %w", err)
+		}
+		defer gzr.Close()
+		reader = gzr
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+
+		target := filepath.Join(dest, filepath.Clean("/"+header.Name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			// the header size is not trusted, so at most one byte more than allowed is read to detect oversized files
+			n, err := io.Copy(f, io.LimitReader(tr, maxExtractedFileSize+1))
+			if err != nil {
+				f.Close()
+				return err
+			}
+			if n > maxExtractedFileSize {
+				f.Close()
+				return fmt.Errorf("file %s exceeds the maximum size of %d bytes", header.Name, maxExtractedFileSize)
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SingleDirOrRoot returns the only entry of root if it's a directory (e.g. the chart directory of an extracted Helm
+// chart archive). Otherwise root is returned.
+func SingleDirOrRoot(root string) (string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to read directory: %w", err)
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(root, entries[0].Name()), nil
+	}
+	return root, nil
+}
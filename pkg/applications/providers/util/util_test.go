@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func genTar(t *testing.T, files map[string]int) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for name, size := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(size)}); err != nil {
+			t.Fatalf("failed to write header: %v", err)
+		}
+		if _, err := tw.Write(bytes.Repeat([]byte("a"), size)); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+	return buf
+}
+
+func TestExtractTar(t *testing.T) {
+	testCases := []struct {
+		name          string
+		files         map[string]int
+		expectedFile  string
+		expectedError string
+	}{
+		{
+			name:         "scenario 1: files are extracted",
+			files:        map[string]int{"chart/Chart.yaml": 10},
+			expectedFile: "chart/Chart.yaml",
+		},
+		{
+			name:         "scenario 2: files can not be extracted outside of dest",
+			files:        map[string]int{"../../escape.yaml": 10},
+			expectedFile: "escape.yaml",
+		},
+		{
+			name:          "scenario 3: oversized files are rejected",
+			files:         map[string]int{"chart/huge.yaml": maxExtractedFileSize + 1},
+			expectedError: "exceeds the maximum size",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dest := t.TempDir()
+
+			err := ExtractTar(genTar(t, tc.files), dest)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to extract tar: %v", err)
+			}
+
+			if _, err := os.Stat(filepath.Join(dest, tc.expectedFile)); err != nil {
+				t.Errorf("expected file %s to be extracted: %v", tc.expectedFile, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeletion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// applicationCleanupTimeout is the time after which the cleanup of the ApplicationInstallations is given up, so that
+// a broken application or user cluster cannot block the cluster deletion forever.
+const applicationCleanupTimeout = 30 * time.Minute
+
+// cleanupApplications deletes the ApplicationInstallations in the user cluster and waits for them to be gone. The
+// application-installation-controller uninstalls them in the reverse order of their dependencies. The cleanup is
+// skipped if the user cluster's API server is down or if it takes longer than applicationCleanupTimeout, as the
+// applications are removed together with the user cluster anyway.
+func (d *Deletion) cleanupApplications(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) error {
+	if !kuberneteshelper.HasFinalizer(cluster, kubermaticv1.InClusterApplicationCleanupFinalizer) {
+		return nil
+	}
+
+	switch {
+	case cluster.Status.NamespaceName == "":
+		// without a namespace, no control plane and thereby no applications exist
+
+	case cluster.Status.ExtendedHealth.Apiserver == kubermaticv1.HealthStatusDown:
+		log.Info("User cluster API server is down, skipping the uninstallation of ApplicationInstallations")
+		d.recorder.Event(cluster, corev1.EventTypeWarning, "ApplicationCleanup", "User cluster API server is down, skipping the uninstallation of ApplicationInstallations.")
+		return kuberneteshelper.TryRemoveFinalizer(ctx, d.seedClient, cluster, kubermaticv1.InClusterApplicationCleanupFinalizer)
+
+	case cluster.DeletionTimestamp != nil && time.Since(cluster.DeletionTimestamp.Time) > applicationCleanupTimeout:
+		log.Infow("Uninstalling ApplicationInstallations timed out, skipping it", "timeout", applicationCleanupTimeout)
+		d.recorder.Eventf(cluster, corev1.EventTypeWarning, "ApplicationCleanup", "Uninstalling ApplicationInstallations did not finish within %v, skipping it.", applicationCleanupTimeout)
+		return kuberneteshelper.TryRemoveFinalizer(ctx, d.seedClient, cluster, kubermaticv1.InClusterApplicationCleanupFinalizer)
+
+	default:
+		userClusterClient, err := d.userClusterClientGetter()
+		if err != nil {
+			return err
+		}
+
+		appList := &appskubermaticv1.ApplicationInstallationList{}
+		if err := userClusterClient.List(ctx, appList); err != nil && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to list ApplicationInstallations: %w", err)
+		}
+
+		if len(appList.Items) > 0 {
+			for _, app := range appList.Items {
+				if app.DeletionTimestamp != nil {
+					continue
+				}
+
+				log.Debugw("Deleting ApplicationInstallation", "applicationinstallation", fmt.Sprintf("%s/%s", app.Namespace, app.Name))
+				if err := userClusterClient.Delete(ctx, &app); err != nil && !apierrors.IsNotFound(err) {
+					return fmt.Errorf("failed to delete ApplicationInstallation %s/%s: %w", app.Namespace, app.Name, err)
+				}
+			}
+
+			d.recorder.Eventf(cluster, corev1.EventTypeNormal, "ApplicationCleanup", "There are %d ApplicationInstallations waiting for deletion.", len(appList.Items))
+			d.waitingFor(kubermaticv1.InClusterApplicationCleanupFinalizer, "%d ApplicationInstallation(s) to be deleted", len(appList.Items))
+			return nil
+		}
+	}
+
+	d.recorder.Event(cluster, corev1.EventTypeNormal, "ApplicationCleanup", "Cleanup has been completed, all ApplicationInstallations have been deleted.")
+
+	return kuberneteshelper.TryRemoveFinalizer(ctx, d.seedClient, cluster, kubermaticv1.InClusterApplicationCleanupFinalizer)
+}
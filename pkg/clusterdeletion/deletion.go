@@ -26,6 +26,7 @@ import (
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -46,17 +47,45 @@ type Deletion struct {
 	seedClient              ctrlruntimeclient.Client
 	recorder                record.EventRecorder
 	userClusterClientGetter func() (ctrlruntimeclient.Client, error)
+
+	// progress maps finalizers to what their cleanup is currently waiting on.
+	progress map[string]string
 }
 
-// CleanupCluster is responsible for cleaning up a cluster.
+// CleanupCluster is responsible for cleaning up a cluster. The progress of the
+// cleanup is recorded in the cluster status.
 func (d *Deletion) CleanupCluster(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) error {
-	log = log.Named("cleanup")
+	err := d.cleanupCluster(ctx, log.Named("cleanup"), cluster)
+
+	if statusErr := d.updateDeletionStatus(ctx, cluster); statusErr != nil {
+		statusErr = fmt.Errorf("failed to update deletion status: %w", statusErr)
+		return kerrors.NewAggregate([]error{err, statusErr})
+	}
+
+	return err
+}
+
+func (d *Deletion) cleanupCluster(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) error {
+	// Skip cloud-side cleanups if they took longer than the user was willing to wait.
+	if err := d.forceCleanup(ctx, log, cluster); err != nil {
+		return err
+	}
 
 	// Delete OPA constraints first to make sure some rules dont block deletion
 	if err := d.cleanupConstraints(ctx, log, cluster); err != nil {
 		return err
 	}
 
+	// Uninstall applications while the workloads and controllers they rely on are still running. This happens before
+	// the cleanup of Volumes and LB's as applications may own some of them.
+	if err := d.cleanupApplications(ctx, log, cluster); err != nil {
+		return err
+	}
+
+	if kuberneteshelper.HasFinalizer(cluster, kubermaticv1.InClusterApplicationCleanupFinalizer) {
+		return nil // an event was already emitted in cleanupApplications
+	}
+
 	// Delete Volumes and LB's inside the user cluster
 	if err := d.cleanupInClusterResources(ctx, log, cluster); err != nil {
 		return err
@@ -68,6 +97,8 @@ func (d *Deletion) CleanupCluster(ctx context.Context, log *zap.SugaredLogger, c
 		kubermaticv1.InClusterLBCleanupFinalizer,
 		kubermaticv1.InClusterPVCleanupFinalizer) {
 		d.recorder.Event(cluster, corev1.EventTypeNormal, "ClusterCleanup", "LoadBalancers / PersistentVolumeClaims have been deleted, waiting for them to be destroyed.")
+		d.waitingFor(kubermaticv1.InClusterLBCleanupFinalizer, "LoadBalancers have been deleted, waiting for them to be destroyed")
+		d.waitingFor(kubermaticv1.InClusterPVCleanupFinalizer, "PersistentVolumeClaims have been deleted, waiting for the volumes to be destroyed")
 		return nil
 	}
 
@@ -98,6 +129,8 @@ func (d *Deletion) CleanupCluster(ctx context.Context, log *zap.SugaredLogger, c
 
 	if !kuberneteshelper.HasFinalizerSuperset(cluster, cred, ns) {
 		d.recorder.Eventf(cluster, corev1.EventTypeNormal, "ClusterCleanup", "Waiting for all finalizers except %q and %q to be removed before removing cluster namespace.", cred, ns)
+		d.waitingFor(ns, "removal of all finalizers except %q and %q", cred, ns)
+		d.waitingFor(cred, "removal of all finalizers except %q and %q", cred, ns)
 		return nil
 	}
 
@@ -20,20 +20,26 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/test/diff"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -42,6 +48,9 @@ func init() {
 	if err := clusterv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
 		panic(fmt.Sprintf("failed to add clusterv1alpha1 to scheme: %v", err))
 	}
+	if err := appskubermaticv1.AddToScheme(scheme.Scheme); err != nil {
+		panic(fmt.Sprintf("failed to add appskubermaticv1 to scheme: %v", err))
+	}
 }
 
 const testNS = "test-ns"
@@ -147,6 +156,13 @@ func TestNodesRemainUntilInClusterResourcesAreGone(t *testing.T) {
 			cluster: getClusterWithFinalizer(clusterName, kubermaticv1.InClusterPVCleanupFinalizer),
 			objects: []ctrlruntimeclient.Object{&corev1.PersistentVolume{}},
 		},
+		{
+			name:    "Nodes remain because application finalizer exists",
+			cluster: getClusterWithFinalizer(clusterName, kubermaticv1.InClusterApplicationCleanupFinalizer),
+			objects: []ctrlruntimeclient.Object{&appskubermaticv1.ApplicationInstallation{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "app"},
+			}},
+		},
 	}
 
 	for idx := range testCases {
@@ -209,3 +225,246 @@ func unstructuredWithAPIVersionAndKind(apiVersion, kind string) *unstructured.Un
 	u.SetKind(kind)
 	return u
 }
+
+func TestCleanupApplications(t *testing.T) {
+	const clusterName = "cluster"
+	testCases := []struct {
+		name              string
+		modify            func(*kubermaticv1.Cluster)
+		objects           []ctrlruntimeclient.Object
+		expectedApps      int
+		expectedFinalizer bool
+	}{
+		{
+			name: "ApplicationInstallations are deleted and finalizer is kept",
+			objects: []ctrlruntimeclient.Object{
+				&appskubermaticv1.ApplicationInstallation{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "app-1"}},
+				&appskubermaticv1.ApplicationInstallation{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "app-2"}},
+			},
+			expectedFinalizer: true,
+		},
+		{
+			name:              "Finalizer is removed when there is no ApplicationInstallation",
+			expectedFinalizer: false,
+		},
+		{
+			name: "Cleanup is skipped when the API server is down",
+			modify: func(c *kubermaticv1.Cluster) {
+				c.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusDown
+			},
+			objects: []ctrlruntimeclient.Object{
+				&appskubermaticv1.ApplicationInstallation{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "app-1"}},
+			},
+			expectedApps:      1,
+			expectedFinalizer: false,
+		},
+		{
+			name: "Cleanup is skipped after the timeout",
+			modify: func(c *kubermaticv1.Cluster) {
+				c.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-applicationCleanupTimeout - time.Minute)}
+				// keep the deleted cluster around
+				c.Finalizers = append(c.Finalizers, kubermaticv1.NamespaceCleanupFinalizer)
+			},
+			objects: []ctrlruntimeclient.Object{
+				&appskubermaticv1.ApplicationInstallation{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "app-1"}},
+			},
+			expectedApps:      1,
+			expectedFinalizer: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := getClusterWithFinalizer(clusterName, kubermaticv1.InClusterApplicationCleanupFinalizer)
+			cluster.Status.NamespaceName = "cluster-" + clusterName
+			cluster.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusUp
+			if tc.modify != nil {
+				tc.modify(cluster)
+			}
+
+			userClusterClient := fake.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(tc.objects...).
+				Build()
+			seedClient := fake.NewClientBuilder().WithObjects(cluster).Build()
+
+			ctx := context.Background()
+			deletion := &Deletion{
+				seedClient: seedClient,
+				recorder:   &record.FakeRecorder{},
+				userClusterClientGetter: func() (ctrlruntimeclient.Client, error) {
+					return userClusterClient, nil
+				},
+			}
+
+			if err := deletion.cleanupApplications(ctx, zap.NewNop().Sugar(), cluster); err != nil {
+				t.Fatalf("Cleanup failed: %v", err)
+			}
+
+			apps := &appskubermaticv1.ApplicationInstallationList{}
+			if err := userClusterClient.List(ctx, apps); err != nil {
+				t.Fatalf("failed to list ApplicationInstallations: %v", err)
+			}
+			if len(apps.Items) != tc.expectedApps {
+				t.Errorf("expected %d ApplicationInstallations to remain, got %d", tc.expectedApps, len(apps.Items))
+			}
+
+			if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			if hasFinalizer := kuberneteshelper.HasFinalizer(cluster, kubermaticv1.InClusterApplicationCleanupFinalizer); hasFinalizer != tc.expectedFinalizer {
+				t.Errorf("expected finalizer=%v, got %v", tc.expectedFinalizer, hasFinalizer)
+			}
+		})
+	}
+}
+
+func TestForceDeletion(t *testing.T) {
+	const clusterName = "cluster"
+	testCases := []struct {
+		name               string
+		deletedAgo         time.Duration
+		expectedFinalizers []string
+		expectedOrphaned   []kubermaticv1.OrphanedResource
+	}{
+		{
+			name:       "Cleanup is not skipped before the timeout",
+			deletedAgo: 10 * time.Minute,
+			expectedFinalizers: []string{
+				kubermaticv1.InClusterApplicationCleanupFinalizer,
+				kubermaticv1.InClusterLBCleanupFinalizer,
+				kubermaticv1.NodeDeletionFinalizer,
+				kubermaticv1.NamespaceCleanupFinalizer,
+			},
+		},
+		{
+			name:       "Cleanup is skipped after the timeout and orphaned resources are recorded",
+			deletedAgo: 2 * time.Hour,
+			expectedFinalizers: []string{
+				kubermaticv1.NamespaceCleanupFinalizer,
+			},
+			expectedOrphaned: []kubermaticv1.OrphanedResource{
+				{Finalizer: kubermaticv1.InClusterApplicationCleanupFinalizer, Kind: "ApplicationInstallation", Name: testNS + "/app"},
+				{Finalizer: kubermaticv1.InClusterLBCleanupFinalizer, Kind: "Service", Name: "default/lb"},
+				{Finalizer: kubermaticv1.NodeDeletionFinalizer, Kind: "Machine", Name: "kube-system/worker", Message: "provider ID aws:///eu-central-1a/i-1234"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := getClusterWithFinalizer(clusterName,
+				kubermaticv1.InClusterApplicationCleanupFinalizer,
+				kubermaticv1.InClusterLBCleanupFinalizer,
+				kubermaticv1.NodeDeletionFinalizer,
+				kubermaticv1.NamespaceCleanupFinalizer,
+			)
+			cluster.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-tc.deletedAgo)}
+			cluster.Spec.ForceDeletion = &kubermaticv1.ForceDeletionPolicy{After: metav1.Duration{Duration: time.Hour}}
+			cluster.Status.NamespaceName = "cluster-" + clusterName
+
+			userClusterClient := fake.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(
+					&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "lb"},
+						Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+					},
+					&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "kubernetes"},
+						Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+					},
+					&clusterv1alpha1.Machine{
+						ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: "worker"},
+						Spec:       clusterv1alpha1.MachineSpec{ProviderID: pointer.String("aws:///eu-central-1a/i-1234")},
+					},
+					&appskubermaticv1.ApplicationInstallation{
+						ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "app"},
+					},
+				).
+				Build()
+			seedClient := fake.NewClientBuilder().WithObjects(cluster).Build()
+
+			ctx := context.Background()
+			deletion := &Deletion{
+				seedClient: seedClient,
+				recorder:   &record.FakeRecorder{},
+				userClusterClientGetter: func() (ctrlruntimeclient.Client, error) {
+					return userClusterClient, nil
+				},
+			}
+
+			if err := deletion.forceCleanup(ctx, zap.NewNop().Sugar(), cluster); err != nil {
+				t.Fatalf("Force cleanup failed: %v", err)
+			}
+
+			if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+
+			if !sets.New(cluster.Finalizers...).Equal(sets.New(tc.expectedFinalizers...)) {
+				t.Errorf("expected finalizers %v, got %v", tc.expectedFinalizers, cluster.Finalizers)
+			}
+
+			var orphaned []kubermaticv1.OrphanedResource
+			if cluster.Status.Deletion != nil {
+				orphaned = cluster.Status.Deletion.OrphanedResources
+			}
+			if !diff.SemanticallyEqual(tc.expectedOrphaned, orphaned) {
+				t.Errorf("Orphaned resources differ:\n%v", diff.ObjectDiff(tc.expectedOrphaned, orphaned))
+			}
+		})
+	}
+}
+
+func TestNewDeletionStatus(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(time.Date(2023, 1, 1, 13, 0, 0, 0, time.UTC))
+
+	cluster := getClusterWithFinalizer("cluster",
+		kubermaticv1.NodeDeletionFinalizer,
+		kubermaticv1.NamespaceCleanupFinalizer,
+		"kubermatic.k8c.io/cleanup-aws-security-group",
+	)
+	cluster.Status.Deletion = &kubermaticv1.ClusterDeletionStatus{
+		PendingFinalizers: []kubermaticv1.PendingFinalizer{
+			{Name: kubermaticv1.NodeDeletionFinalizer, Since: earlier},
+			{Name: kubermaticv1.InClusterLBCleanupFinalizer, Since: earlier},
+		},
+		OrphanedResources: []kubermaticv1.OrphanedResource{
+			{Finalizer: kubermaticv1.InClusterLBCleanupFinalizer, Kind: "Service", Name: "default/lb"},
+		},
+	}
+
+	progress := map[string]string{
+		kubermaticv1.NodeDeletionFinalizer: "2 Machine(s) to be destroyed",
+	}
+
+	expected := &kubermaticv1.ClusterDeletionStatus{
+		PendingFinalizers: []kubermaticv1.PendingFinalizer{
+			{
+				Name:       kubermaticv1.NodeDeletionFinalizer,
+				WaitingFor: "2 Machine(s) to be destroyed",
+				Since:      earlier,
+			},
+			{
+				Name:       kubermaticv1.NamespaceCleanupFinalizer,
+				WaitingFor: finalizerDescriptions[kubermaticv1.NamespaceCleanupFinalizer],
+				Since:      now,
+			},
+			{
+				Name:       "kubermatic.k8c.io/cleanup-aws-security-group",
+				WaitingFor: "cleanup by another controller, e.g. of the cloud provider resources",
+				Since:      now,
+			},
+		},
+		OrphanedResources: cluster.Status.Deletion.OrphanedResources,
+	}
+
+	status := newDeletionStatus(cluster, progress, now)
+	if !diff.SemanticallyEqual(expected, status) {
+		t.Errorf("Deletion status differs:\n%v", diff.ObjectDiff(expected, status))
+	}
+}
@@ -47,6 +47,7 @@ func (d *Deletion) cleanupEtcdBackupConfigs(ctx context.Context, cluster *kuberm
 			}
 
 			d.recorder.Eventf(cluster, corev1.EventTypeNormal, "EtcdBackupConfigCleanup", "There are %d EtcdBackupConfig objects waiting for deletion.", len(backupConfigs.Items))
+			d.waitingFor(kubermaticv1.EtcdBackupConfigCleanupFinalizer, "%d EtcdBackupConfig(s) to be deleted", len(backupConfigs.Items))
 			return nil
 		}
 	}
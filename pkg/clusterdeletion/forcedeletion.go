@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeletion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// forceableFinalizers are the finalizers whose cleanup only removes applications and
+// cloud resources of the user cluster and can therefore be skipped by the force
+// deletion policy. The cleanup finalizers of the cloud providers (e.g.
+// kubermatic.k8c.io/cleanup-aws-security-group) are deliberately not included, as
+// skipping them would leak the infrastructure of the cluster (e.g. networks,
+// security groups or roles) instead of single resources.
+// A deletion stuck in the cloud provider cleanup has to be resolved manually.
+var forceableFinalizers = []string{
+	kubermaticv1.InClusterApplicationCleanupFinalizer,
+	kubermaticv1.InClusterLBCleanupFinalizer,
+	kubermaticv1.InClusterPVCleanupFinalizer,
+	kubermaticv1.NodeDeletionFinalizer,
+}
+
+// forceDeletionDue returns true if the cluster has a force deletion policy and
+// its timeout has been reached.
+func forceDeletionDue(cluster *kubermaticv1.Cluster) bool {
+	policy := cluster.Spec.ForceDeletion
+	if policy == nil || cluster.DeletionTimestamp == nil {
+		return false
+	}
+
+	return time.Since(cluster.DeletionTimestamp.Time) > policy.After.Duration
+}
+
+// forceCleanup removes the forceable finalizers once the force deletion timeout has been
+// reached. All resources that are left behind are recorded in the cluster status first.
+func (d *Deletion) forceCleanup(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) error {
+	if !forceDeletionDue(cluster) {
+		return nil
+	}
+
+	var skipped []string
+	for _, finalizer := range forceableFinalizers {
+		if kuberneteshelper.HasFinalizer(cluster, finalizer) {
+			skipped = append(skipped, finalizer)
+		}
+	}
+
+	if len(skipped) == 0 {
+		return nil
+	}
+
+	var orphaned []kubermaticv1.OrphanedResource
+	for _, finalizer := range skipped {
+		orphaned = append(orphaned, d.findOrphanedResources(ctx, cluster, finalizer)...)
+	}
+
+	log.Warnw("Force deletion timeout reached, skipping cleanup", "finalizers", skipped, "orphaned", len(orphaned))
+
+	err := kubermaticv1helper.UpdateClusterStatus(ctx, d.seedClient, cluster, func(c *kubermaticv1.Cluster) {
+		if c.Status.Deletion == nil {
+			c.Status.Deletion = &kubermaticv1.ClusterDeletionStatus{}
+		}
+		c.Status.Deletion.OrphanedResources = append(c.Status.Deletion.OrphanedResources, orphaned...)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record orphaned resources: %w", err)
+	}
+
+	d.recorder.Eventf(cluster, corev1.EventTypeWarning, "ForceDeletion",
+		"Force deletion timeout of %v reached, skipping %s; %d resource(s) have been orphaned and must be cleaned up manually.",
+		cluster.Spec.ForceDeletion.After.Duration, strings.Join(skipped, ", "), len(orphaned))
+
+	return kuberneteshelper.TryRemoveFinalizer(ctx, d.seedClient, cluster, skipped...)
+}
+
+// findOrphanedResources lists the resources inside the user cluster whose cleanup is
+// guarded by the given finalizer. Errors are recorded as part of the result, as a
+// broken user cluster is a common reason for a stuck deletion.
+func (d *Deletion) findOrphanedResources(ctx context.Context, cluster *kubermaticv1.Cluster, finalizer string) []kubermaticv1.OrphanedResource {
+	var kind string
+	switch finalizer {
+	case kubermaticv1.InClusterApplicationCleanupFinalizer:
+		kind = "ApplicationInstallation"
+	case kubermaticv1.InClusterLBCleanupFinalizer:
+		kind = "Service"
+	case kubermaticv1.InClusterPVCleanupFinalizer:
+		kind = "PersistentVolume"
+	case kubermaticv1.NodeDeletionFinalizer:
+		kind = "Machine"
+	}
+
+	failed := func(err error) []kubermaticv1.OrphanedResource {
+		return []kubermaticv1.OrphanedResource{{
+			Finalizer: finalizer,
+			Kind:      kind,
+			Message:   fmt.Sprintf("failed to list remaining resources: %v", err),
+		}}
+	}
+
+	// without a namespace, no control plane and thereby no resources exist
+	if cluster.Status.NamespaceName == "" {
+		return nil
+	}
+
+	userClusterClient, err := d.userClusterClientGetter()
+	if err != nil {
+		return failed(err)
+	}
+
+	var orphaned []kubermaticv1.OrphanedResource
+
+	switch finalizer {
+	case kubermaticv1.InClusterApplicationCleanupFinalizer:
+		apps := &appskubermaticv1.ApplicationInstallationList{}
+		if err := userClusterClient.List(ctx, apps); err != nil {
+			if meta.IsNoMatchError(err) {
+				return nil
+			}
+			return failed(err)
+		}
+
+		for _, app := range apps.Items {
+			orphaned = append(orphaned, kubermaticv1.OrphanedResource{
+				Finalizer: finalizer,
+				Kind:      kind,
+				Name:      fmt.Sprintf("%s/%s", app.Namespace, app.Name),
+			})
+		}
+
+	case kubermaticv1.InClusterLBCleanupFinalizer:
+		services := &corev1.ServiceList{}
+		if err := userClusterClient.List(ctx, services); err != nil {
+			return failed(err)
+		}
+
+		for _, service := range services.Items {
+			if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+				orphaned = append(orphaned, kubermaticv1.OrphanedResource{
+					Finalizer: finalizer,
+					Kind:      kind,
+					Name:      fmt.Sprintf("%s/%s", service.Namespace, service.Name),
+				})
+			}
+		}
+
+		// Services that were already deleted, but whose cloud load balancer was not yet confirmed to be gone.
+		for _, uid := range sets.List(parseStringSet(cluster.Annotations[deletedLBAnnotationName])) {
+			orphaned = append(orphaned, kubermaticv1.OrphanedResource{
+				Finalizer: finalizer,
+				Kind:      kind,
+				Message:   fmt.Sprintf("deleted Service with UID %s, its cloud load balancer might still exist", uid),
+			})
+		}
+
+	case kubermaticv1.InClusterPVCleanupFinalizer:
+		pvs := &corev1.PersistentVolumeList{}
+		if err := userClusterClient.List(ctx, pvs); err != nil {
+			return failed(err)
+		}
+
+		for _, pv := range pvs.Items {
+			if pv.Annotations[AnnDynamicallyProvisioned] == "" || pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimDelete {
+				continue
+			}
+
+			resource := kubermaticv1.OrphanedResource{
+				Finalizer: finalizer,
+				Kind:      kind,
+				Name:      pv.Name,
+			}
+			if pv.Spec.CSI != nil {
+				resource.Message = fmt.Sprintf("volume handle %s", pv.Spec.CSI.VolumeHandle)
+			}
+
+			orphaned = append(orphaned, resource)
+		}
+
+	case kubermaticv1.NodeDeletionFinalizer:
+		machines := &clusterv1alpha1.MachineList{}
+		if err := userClusterClient.List(ctx, machines, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+			if meta.IsNoMatchError(err) {
+				return nil
+			}
+			return failed(err)
+		}
+
+		for _, machine := range machines.Items {
+			resource := kubermaticv1.OrphanedResource{
+				Finalizer: finalizer,
+				Kind:      kind,
+				Name:      fmt.Sprintf("%s/%s", machine.Namespace, machine.Name),
+			}
+			if machine.Spec.ProviderID != nil {
+				resource.Message = fmt.Sprintf("provider ID %s", *machine.Spec.ProviderID)
+			}
+
+			orphaned = append(orphaned, resource)
+		}
+	}
+
+	return orphaned
+}
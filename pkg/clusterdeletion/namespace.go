@@ -63,6 +63,7 @@ func (d *Deletion) cleanupNamespace(ctx context.Context, log *zap.SugaredLogger,
 		}
 
 		d.recorder.Event(cluster, corev1.EventTypeNormal, "ClusterNamespaceCleanup", "Cluster namespace is still terminating, some resources might be blocked by finalizers.")
+		d.waitingFor(kubermaticv1.NamespaceCleanupFinalizer, "namespace %s to terminate, some resources might be blocked by finalizers", ns.Name)
 		return nil
 	}
 
@@ -80,6 +80,7 @@ func (d *Deletion) cleanupNodes(ctx context.Context, cluster *kubermaticv1.Clust
 
 		// Return here to make sure we don't attempt to delete MachineSets until the MachineDeployment is actually gone
 		d.recorder.Eventf(cluster, corev1.EventTypeNormal, "NodeCleanup", "Waiting for %d MachineDeployment(s) to be destroyed.", len(machineDeploymentList.Items))
+		d.waitingFor(kubermaticv1.NodeDeletionFinalizer, "%d MachineDeployment(s) to be destroyed", len(machineDeploymentList.Items))
 		return nil
 	}
 
@@ -95,6 +96,7 @@ func (d *Deletion) cleanupNodes(ctx context.Context, cluster *kubermaticv1.Clust
 
 		// Return here to make sure we don't attempt to delete Machines until the MachineSet is actually gone
 		d.recorder.Eventf(cluster, corev1.EventTypeNormal, "NodeCleanup", "Waiting for %d MachineSet(s) to be destroyed.", len(machineSetList.Items))
+		d.waitingFor(kubermaticv1.NodeDeletionFinalizer, "%d MachineSet(s) to be destroyed", len(machineSetList.Items))
 		return nil
 	}
 
@@ -109,6 +111,7 @@ func (d *Deletion) cleanupNodes(ctx context.Context, cluster *kubermaticv1.Clust
 		}
 
 		d.recorder.Eventf(cluster, corev1.EventTypeNormal, "NodeCleanup", "Waiting for %d Machine(s) to be destroyed.", len(machineList.Items))
+		d.waitingFor(kubermaticv1.NodeDeletionFinalizer, "%d Machine(s) to be destroyed", len(machineList.Items))
 		return nil
 	}
 
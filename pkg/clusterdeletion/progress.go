@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeletion
+
+import (
+	"context"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// finalizerDescriptions describe what the cleanup behind a finalizer waits on, unless
+// the cleanup step reported something more specific during the current reconciliation.
+var finalizerDescriptions = map[string]string{
+	kubermaticv1.KubermaticConstraintCleanupFinalizer: "deletion of the cluster's Constraints",
+	kubermaticv1.InClusterApplicationCleanupFinalizer: "uninstallation of all ApplicationInstallations",
+	kubermaticv1.InClusterLBCleanupFinalizer:          "deletion of all LoadBalancer Services and their cloud load balancers",
+	kubermaticv1.InClusterPVCleanupFinalizer:          "deletion of all PersistentVolumeClaims and dynamically provisioned volumes",
+	kubermaticv1.EtcdBackupConfigCleanupFinalizer:     "deletion of all EtcdBackupConfigs",
+	kubermaticv1.NodeDeletionFinalizer:                "deletion of all MachineDeployments, MachineSets and Machines",
+	clusterRoleBindingsCleanupFinalizer:               "deletion of the cluster's ClusterRoleBindings",
+	kubermaticv1.NamespaceCleanupFinalizer:            "removal of all other finalizers and deletion of the cluster namespace",
+	kubermaticv1.CredentialsSecretsCleanupFinalizer:   "removal of all other finalizers and deletion of the credential Secrets",
+}
+
+// waitingFor records what the cleanup behind the given finalizer is currently waiting on.
+func (d *Deletion) waitingFor(finalizer string, format string, args ...interface{}) {
+	if d.progress == nil {
+		d.progress = map[string]string{}
+	}
+	d.progress[finalizer] = fmt.Sprintf(format, args...)
+}
+
+// updateDeletionStatus records the finalizers that still block the deletion of the
+// cluster and since when they do so in the cluster status.
+func (d *Deletion) updateDeletionStatus(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	// once the last finalizer is gone, the cluster is gone as well
+	if len(cluster.Finalizers) == 0 {
+		return nil
+	}
+
+	err := kubermaticv1helper.UpdateClusterStatus(ctx, d.seedClient, cluster, func(c *kubermaticv1.Cluster) {
+		c.Status.Deletion = newDeletionStatus(c, d.progress, metav1.Now())
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+func newDeletionStatus(cluster *kubermaticv1.Cluster, progress map[string]string, now metav1.Time) *kubermaticv1.ClusterDeletionStatus {
+	status := &kubermaticv1.ClusterDeletionStatus{}
+	since := map[string]metav1.Time{}
+
+	if cluster.Status.Deletion != nil {
+		status.OrphanedResources = cluster.Status.Deletion.OrphanedResources
+		for _, pending := range cluster.Status.Deletion.PendingFinalizers {
+			since[pending.Name] = pending.Since
+		}
+	}
+
+	for _, finalizer := range cluster.Finalizers {
+		pending := kubermaticv1.PendingFinalizer{
+			Name:       finalizer,
+			WaitingFor: progress[finalizer],
+			Since:      now,
+		}
+
+		if pending.WaitingFor == "" {
+			pending.WaitingFor = finalizerDescriptions[finalizer]
+		}
+		if pending.WaitingFor == "" {
+			pending.WaitingFor = "cleanup by another controller, e.g. of the cloud provider resources"
+		}
+
+		if s, ok := since[finalizer]; ok {
+			pending.Since = s
+		}
+
+		status.PendingFinalizers = append(status.PendingFinalizers, pending)
+	}
+
+	return status
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	complianceReportPrefix = "kubermatic_compliance_report_"
+)
+
+var complianceCheckStates = []kubermaticv1.ComplianceCheckState{
+	kubermaticv1.ComplianceCheckPass,
+	kubermaticv1.ComplianceCheckFail,
+	kubermaticv1.ComplianceCheckWarn,
+	kubermaticv1.ComplianceCheckInfo,
+}
+
+// ComplianceReportCollector exports metrics for compliance reports.
+type ComplianceReportCollector struct {
+	client ctrlruntimeclient.Reader
+
+	checks       *prometheus.Desc
+	lastScanTime *prometheus.Desc
+	scanErrors   *prometheus.Desc
+}
+
+// MustRegisterComplianceReportCollector registers the compliance report collector at the given prometheus registry.
+func MustRegisterComplianceReportCollector(registry prometheus.Registerer, client ctrlruntimeclient.Reader) {
+	registry.MustRegister(newComplianceReportCollector(client))
+}
+
+func newComplianceReportCollector(client ctrlruntimeclient.Reader) *ComplianceReportCollector {
+	return &ComplianceReportCollector{
+		client: client,
+		checks: prometheus.NewDesc(
+			complianceReportPrefix+"checks",
+			"Number of compliance checks of the latest scan by target and state",
+			[]string{"cluster", "benchmark", "target", "state"},
+			nil,
+		),
+		lastScanTime: prometheus.NewDesc(
+			complianceReportPrefix+"last_scan_time",
+			"Unix timestamp of the latest completed compliance scan",
+			[]string{"cluster"},
+			nil,
+		),
+		scanErrors: prometheus.NewDesc(
+			complianceReportPrefix+"scan_errors",
+			"Number of errors that prevented parts of the cluster from being scanned",
+			[]string{"cluster"},
+			nil,
+		),
+	}
+}
+
+// Describe returns the metrics descriptors.
+func (cc *ComplianceReportCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cc.checks
+	ch <- cc.lastScanTime
+	ch <- cc.scanErrors
+}
+
+// Collect gets called by prometheus to collect the metrics.
+func (cc *ComplianceReportCollector) Collect(ch chan<- prometheus.Metric) {
+	reports := &kubermaticv1.ComplianceReportList{}
+	if err := cc.client.List(context.Background(), reports); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list compliance reports in ComplianceReportCollector: %w", err))
+		return
+	}
+
+	for i := range reports.Items {
+		cc.collectReport(ch, &reports.Items[i])
+	}
+}
+
+func (cc *ComplianceReportCollector) collectReport(ch chan<- prometheus.Metric, report *kubermaticv1.ComplianceReport) {
+	// reports are created before the first scan has completed
+	if report.Status.LastScanTime.IsZero() {
+		return
+	}
+
+	counts := map[kubermaticv1.ComplianceCheckTarget]map[kubermaticv1.ComplianceCheckState]int{
+		kubermaticv1.ComplianceCheckTargetControlPlane: {},
+		kubermaticv1.ComplianceCheckTargetNode:         {},
+	}
+	for _, check := range report.Status.Checks {
+		if _, ok := counts[check.Target]; ok {
+			counts[check.Target][check.State]++
+		}
+	}
+
+	for target, states := range counts {
+		for _, state := range complianceCheckStates {
+			ch <- prometheus.MustNewConstMetric(
+				cc.checks,
+				prometheus.GaugeValue,
+				float64(states[state]),
+				report.Spec.Cluster,
+				report.Status.Benchmark,
+				string(target),
+				string(state),
+			)
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		cc.lastScanTime,
+		prometheus.GaugeValue,
+		float64(report.Status.LastScanTime.Unix()),
+		report.Spec.Cluster,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		cc.scanErrors,
+		prometheus.GaugeValue,
+		float64(len(report.Status.Errors)),
+		report.Spec.Cluster,
+	)
+}
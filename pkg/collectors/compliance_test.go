@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestComplianceReportMetrics(t *testing.T) {
+	kubermaticFakeClient := fake.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			&kubermaticv1.ComplianceReport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      kubermaticv1.CISBenchmarkComplianceReportName,
+					Namespace: "cluster-scanned",
+				},
+				Spec: kubermaticv1.ComplianceReportSpec{
+					Cluster: "scanned",
+				},
+				Status: kubermaticv1.ComplianceReportStatus{
+					Benchmark:    "cis-1.23",
+					LastScanTime: metav1.NewTime(time.Unix(1700000000, 0)),
+					Checks: []kubermaticv1.ComplianceCheckResult{
+						{ID: "1.2.2", Target: kubermaticv1.ComplianceCheckTargetControlPlane, State: kubermaticv1.ComplianceCheckFail},
+						{ID: "1.2.18", Target: kubermaticv1.ComplianceCheckTargetControlPlane, State: kubermaticv1.ComplianceCheckPass},
+						{ID: "4.2.1", Target: kubermaticv1.ComplianceCheckTargetNode, State: kubermaticv1.ComplianceCheckPass},
+						{ID: "4.2.6", Target: kubermaticv1.ComplianceCheckTargetNode, State: kubermaticv1.ComplianceCheckWarn},
+					},
+					Errors: []string{"node worker-2: scan timed out"},
+				},
+			},
+			&kubermaticv1.ComplianceReport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      kubermaticv1.CISBenchmarkComplianceReportName,
+					Namespace: "cluster-pending",
+				},
+				Spec: kubermaticv1.ComplianceReportSpec{
+					Cluster: "pending",
+				},
+			},
+		).
+		Build()
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(newComplianceReportCollector(kubermaticFakeClient)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+# HELP kubermatic_compliance_report_checks Number of compliance checks of the latest scan by target and state
+# TYPE kubermatic_compliance_report_checks gauge
+kubermatic_compliance_report_checks{benchmark="cis-1.23",cluster="scanned",state="Fail",target="ControlPlane"} 1
+kubermatic_compliance_report_checks{benchmark="cis-1.23",cluster="scanned",state="Fail",target="Node"} 0
+kubermatic_compliance_report_checks{benchmark="cis-1.23",cluster="scanned",state="Info",target="ControlPlane"} 0
+kubermatic_compliance_report_checks{benchmark="cis-1.23",cluster="scanned",state="Info",target="Node"} 0
+kubermatic_compliance_report_checks{benchmark="cis-1.23",cluster="scanned",state="Pass",target="ControlPlane"} 1
+kubermatic_compliance_report_checks{benchmark="cis-1.23",cluster="scanned",state="Pass",target="Node"} 1
+kubermatic_compliance_report_checks{benchmark="cis-1.23",cluster="scanned",state="Warn",target="ControlPlane"} 0
+kubermatic_compliance_report_checks{benchmark="cis-1.23",cluster="scanned",state="Warn",target="Node"} 1
+# HELP kubermatic_compliance_report_last_scan_time Unix timestamp of the latest completed compliance scan
+# TYPE kubermatic_compliance_report_last_scan_time gauge
+kubermatic_compliance_report_last_scan_time{cluster="scanned"} 1.7e+09
+# HELP kubermatic_compliance_report_scan_errors Number of errors that prevented parts of the cluster from being scanned
+# TYPE kubermatic_compliance_report_scan_errors gauge
+kubermatic_compliance_report_scan_errors{cluster="scanned"} 1
+`
+
+	if err := testutil.CollectAndCompare(registry, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonconfigsynchronizer
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kkp-addon-config-synchronizer"
+
+	// cleanupFinalizer indicates that the AddonConfig still needs to be deleted on the seed clusters.
+	cleanupFinalizer = "kubermatic.k8c.io/cleanup-seed-addon-config"
+)
+
+type reconciler struct {
+	log          *zap.SugaredLogger
+	recorder     record.EventRecorder
+	masterClient ctrlruntimeclient.Client
+	seedClients  kuberneteshelper.SeedClientMap
+}
+
+func Add(
+	masterManager manager.Manager,
+	seedManagers map[string]manager.Manager,
+	log *zap.SugaredLogger,
+	numWorkers int,
+) error {
+	r := &reconciler{
+		log:          log.Named(ControllerName),
+		recorder:     masterManager.GetEventRecorderFor(ControllerName),
+		masterClient: masterManager.GetClient(),
+		seedClients:  kuberneteshelper.SeedClientMap{},
+	}
+
+	for seedName, seedManager := range seedManagers {
+		r.seedClients[seedName] = seedManager.GetClient()
+	}
+
+	c, err := controller.New(ControllerName, masterManager, controller.Options{Reconciler: r, MaxConcurrentReconciles: numWorkers})
+	if err != nil {
+		return fmt.Errorf("failed to construct controller: %w", err)
+	}
+
+	// Watch for changes to AddonConfig
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.AddonConfig{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to create watch for addonConfigs: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile reconciles AddonConfig objects from master cluster to all seed clusters.
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("addonconfig", request.Name)
+	log.Debug("Processing")
+
+	addonConfig := &kubermaticv1.AddonConfig{}
+	if err := r.masterClient.Get(ctx, request.NamespacedName, addonConfig); err != nil {
+		return reconcile.Result{}, ctrlruntimeclient.IgnoreNotFound(err)
+	}
+
+	err := r.reconcile(ctx, log, addonConfig)
+	if err != nil {
+		log.Errorw("ReconcilingError", zap.Error(err))
+		r.recorder.Event(addonConfig, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, addonConfig *kubermaticv1.AddonConfig) error {
+	// handling deletion
+	if !addonConfig.DeletionTimestamp.IsZero() {
+		if err := r.handleDeletion(ctx, log, addonConfig); err != nil {
+			return fmt.Errorf("handling deletion of addon config: %w", err)
+		}
+		return nil
+	}
+
+	if err := kuberneteshelper.TryAddFinalizer(ctx, r.masterClient, addonConfig, cleanupFinalizer); err != nil {
+		return fmt.Errorf("failed to add finalizer: %w", err)
+	}
+
+	addonConfigReconcilerFactories := []reconciling.NamedAddonConfigReconcilerFactory{
+		addonConfigReconcilerFactory(addonConfig),
+	}
+
+	err := r.seedClients.Each(ctx, log, func(_ string, seedClient ctrlruntimeclient.Client, log *zap.SugaredLogger) error {
+		log.Debug("Reconciling addon config with seed")
+
+		seedAddonConfig := &kubermaticv1.AddonConfig{}
+		if err := seedClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(addonConfig), seedAddonConfig); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to fetch AddonConfig on seed cluster: %w", err)
+		}
+
+		// see project-synchronizer's syncAllSeeds comment
+		if seedAddonConfig.UID != "" && seedAddonConfig.UID == addonConfig.UID {
+			return nil
+		}
+
+		return reconciling.ReconcileAddonConfigs(ctx, addonConfigReconcilerFactories, "", seedClient)
+	})
+	if err != nil {
+		return fmt.Errorf("reconciled addon config %s: %w", addonConfig.Name, err)
+	}
+
+	return nil
+}
+
+func (r *reconciler) handleDeletion(ctx context.Context, log *zap.SugaredLogger, addonConfig *kubermaticv1.AddonConfig) error {
+	if kuberneteshelper.HasFinalizer(addonConfig, cleanupFinalizer) {
+		if err := r.seedClients.Each(ctx, log, func(_ string, seedClient ctrlruntimeclient.Client, log *zap.SugaredLogger) error {
+			log.Debug("Deleting addon config on seed")
+
+			err := seedClient.Delete(ctx, &kubermaticv1.AddonConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: addonConfig.Name,
+				},
+			})
+
+			return ctrlruntimeclient.IgnoreNotFound(err)
+		}); err != nil {
+			return err
+		}
+
+		if err := kuberneteshelper.TryRemoveFinalizer(ctx, r.masterClient, addonConfig, cleanupFinalizer); err != nil {
+			return fmt.Errorf("failed to remove addon config finalizer %s: %w", addonConfig.Name, err)
+		}
+	}
+	return nil
+}
+
+func addonConfigReconcilerFactory(addonConfig *kubermaticv1.AddonConfig) reconciling.NamedAddonConfigReconcilerFactory {
+	return func() (string, reconciling.AddonConfigReconciler) {
+		return addonConfig.Name, func(a *kubermaticv1.AddonConfig) (*kubermaticv1.AddonConfig, error) {
+			a.Labels = addonConfig.Labels
+			a.Annotations = addonConfig.Annotations
+			a.Spec = addonConfig.Spec
+			return a, nil
+		}
+	}
+}
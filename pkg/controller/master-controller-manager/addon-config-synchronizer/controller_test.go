@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonconfigsynchronizer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/test/diff"
+	"k8c.io/kubermatic/v2/pkg/test/generator"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	utilruntime.Must(kubermaticv1.AddToScheme(scheme.Scheme))
+}
+
+const addonConfigName = "cilium"
+
+func TestReconcile(t *testing.T) {
+	testCases := []struct {
+		name                string
+		requestName         string
+		expectedAddonConfig *kubermaticv1.AddonConfig
+		masterClient        ctrlruntimeclient.Client
+		seedClient          ctrlruntimeclient.Client
+	}{
+		{
+			name:                "scenario 1: sync addon config from master cluster to seed cluster",
+			requestName:         addonConfigName,
+			expectedAddonConfig: generateAddonConfig(addonConfigName, false),
+			masterClient: fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(generateAddonConfig(addonConfigName, false), generator.GenTestSeed()).
+				Build(),
+			seedClient: fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				Build(),
+		},
+		{
+			name:                "scenario 2: cleanup addon config on the seed cluster when master addon config is being terminated",
+			requestName:         addonConfigName,
+			expectedAddonConfig: nil,
+			masterClient: fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(generateAddonConfig(addonConfigName, true), generator.GenTestSeed()).
+				Build(),
+			seedClient: fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(generateAddonConfig(addonConfigName, false), generator.GenTestSeed()).
+				Build(),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := &reconciler{
+				log:          kubermaticlog.Logger,
+				recorder:     &record.FakeRecorder{},
+				masterClient: tc.masterClient,
+				seedClients:  map[string]ctrlruntimeclient.Client{"first": tc.seedClient},
+			}
+
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: tc.requestName}}
+			if _, err := r.Reconcile(ctx, request); err != nil {
+				t.Fatalf("reconciling failed: %v", err)
+			}
+
+			seedAddonConfig := &kubermaticv1.AddonConfig{}
+			err := tc.seedClient.Get(ctx, request.NamespacedName, seedAddonConfig)
+
+			if tc.expectedAddonConfig == nil {
+				if err == nil {
+					t.Fatal("failed clean up addon config on the seed cluster")
+				} else if !apierrors.IsNotFound(err) {
+					t.Fatalf("failed to get addon config: %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("failed to get addon config: %v", err)
+				}
+
+				seedAddonConfig.ResourceVersion = ""
+				seedAddonConfig.APIVersion = ""
+				seedAddonConfig.Kind = ""
+
+				if !diff.SemanticallyEqual(tc.expectedAddonConfig, seedAddonConfig) {
+					t.Fatalf("Objects differ:\n%v", diff.ObjectDiff(tc.expectedAddonConfig, seedAddonConfig))
+				}
+			}
+		})
+	}
+}
+
+func generateAddonConfig(name string, deleted bool) *kubermaticv1.AddonConfig {
+	addonConfig := &kubermaticv1.AddonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"someLabelKey": "someLabelValue",
+			},
+			Annotations: map[string]string{
+				"someAnnotationKey": "someAnnotationValue",
+			},
+		},
+		Spec: kubermaticv1.AddonConfigSpec{
+			Description: "sample addon",
+			HealthProbes: []kubermaticv1.AddonHealthProbe{
+				{
+					Name:      "operator",
+					Namespace: "kube-system",
+					Service:   "cilium-operator",
+					Port:      intstr.FromString("health"),
+					Scheme:    corev1.URISchemeHTTP,
+					Path:      "/healthz",
+				},
+			},
+		},
+	}
+	if deleted {
+		deleteTime := metav1.NewTime(time.Now())
+		addonConfig.DeletionTimestamp = &deleteTime
+		addonConfig.Finalizers = append(addonConfig.Finalizers, cleanupFinalizer)
+	}
+
+	return addonConfig
+}
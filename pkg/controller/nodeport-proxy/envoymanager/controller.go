@@ -64,6 +64,10 @@ type Options struct {
 	// When the value is less or equal than 0 the HTTP/2 CONNECT Listener is
 	// disabled and won't be configured in Envoy.
 	EnvoyTunnelingListenerPort int
+
+	// DefaultRateLimits are the connection limits applied to every exposed
+	// Service, unless overridden by the Service annotations.
+	DefaultRateLimits RateLimits
 }
 
 func (o Options) IsSNIEnabled() bool {
@@ -57,6 +57,7 @@ func TestSync(t *testing.T) {
 		resources             []ctrlruntimeclient.Object
 		sniListenerPort       int
 		tunnelingListenerPort int
+		defaultRateLimits     RateLimits
 		expectedClusters      map[string]*envoyclusterv3.Cluster
 		expectedListener      map[string]*envoylistenerv3.Listener
 	}{
@@ -86,6 +87,53 @@ func TestSync(t *testing.T) {
 				"test/my-nodeport-http":  makeNodePortListener(t, "test/my-nodeport-http", 32001),
 			},
 		},
+		{
+			name: "nodeport-service-with-rate-limit-annotations",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithServiceType(corev1.ServiceTypeNodePort).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "NodePort").
+					WithAnnotation(nodeportproxy.MaxConnectionsAnnotationKey, "100").
+					WithAnnotation(nodeportproxy.ConnectionsPerSecondAnnotationKey, "0").
+					WithServicePort("https", 443, 32000, intstr.FromString("https"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("https", 8443, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+			},
+			defaultRateLimits: RateLimits{MaxConnections: 500, MaxPendingConnections: 50, ConnectionsPerSecond: 10},
+			expectedClusters: map[string]*envoyclusterv3.Cluster{
+				"test/my-nodeport-https": makeClusterWithLimits(t, "test/my-nodeport-https", 8443, RateLimits{MaxConnections: 100, MaxPendingConnections: 50}, "172.16.0.1"),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{
+				"test/my-nodeport-https": makeNodePortListener(t, "test/my-nodeport-https", 32000),
+			},
+		},
+		{
+			name: "nodeport-service-with-invalid-rate-limit-annotation",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithServiceType(corev1.ServiceTypeNodePort).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "NodePort").
+					WithAnnotation(nodeportproxy.ConnectionBurstAnnotationKey, "-1").
+					WithServicePort("https", 443, 32000, intstr.FromString("https"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("https", 8443, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+			},
+			defaultRateLimits: RateLimits{ConnectionsPerSecond: 10, ConnectionBurst: 20},
+			expectedClusters: map[string]*envoyclusterv3.Cluster{
+				"test/my-nodeport-https": makeCluster(t, "test/my-nodeport-https", 8443, "172.16.0.1"),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{
+				"test/my-nodeport-https": makeNodePortListenerWithLimits(t, "test/my-nodeport-https", 32000, RateLimits{ConnectionsPerSecond: 10, ConnectionBurst: 20}),
+			},
+		},
 		{
 			name: "1-port-2-pods-one-unhealthy",
 			resources: []ctrlruntimeclient.Object{
@@ -160,6 +208,43 @@ func TestSync(t *testing.T) {
 				"sni_listener": makeSNIListener(t, 443, hostClusterName{Cluster: "test/my-cluster-ip-https", Hostname: "host.com"}),
 			},
 		},
+		{
+			name: "sni-services-with-default-rate-limits",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "noisy-service", Namespace: "test"}).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "SNI").
+					WithAnnotation(nodeportproxy.PortHostMappingAnnotationKey, `{"https": "noisy.host.com"}`).
+					WithAnnotation(nodeportproxy.ConnectionsPerSecondAnnotationKey, "5").
+					WithServicePort("https", 8080, 0, intstr.FromString("https"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "noisy-service", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("https", 8443, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+				test.NewServiceBuilder(test.NamespacedName{Name: "quiet-service", Namespace: "test"}).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "SNI").
+					WithAnnotation(nodeportproxy.PortHostMappingAnnotationKey, `{"https": "quiet.host.com"}`).
+					WithServicePort("https", 8080, 0, intstr.FromString("https"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "quiet-service", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("https", 8443, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.2").
+					DoneWithEndpointSubset().Build(),
+			},
+			sniListenerPort:   443,
+			defaultRateLimits: RateLimits{MaxConnections: 200, ConnectionsPerSecond: 20, ConnectionBurst: 40},
+			expectedClusters: map[string]*envoyclusterv3.Cluster{
+				"test/noisy-service-https": makeClusterWithLimits(t, "test/noisy-service-https", 8443, RateLimits{MaxConnections: 200}, "172.16.0.1"),
+				"test/quiet-service-https": makeClusterWithLimits(t, "test/quiet-service-https", 8443, RateLimits{MaxConnections: 200}, "172.16.0.2"),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{
+				"sni_listener": makeSNIListener(t, 443,
+					hostClusterName{Cluster: "test/noisy-service-https", Hostname: "noisy.host.com", Limits: RateLimits{ConnectionsPerSecond: 5, ConnectionBurst: 40}},
+					hostClusterName{Cluster: "test/quiet-service-https", Hostname: "quiet.host.com", Limits: RateLimits{ConnectionsPerSecond: 20, ConnectionBurst: 40}}),
+			},
+		},
 		{
 			name: "1-sni-service-with-2-exposed-ports",
 			resources: []ctrlruntimeclient.Object{
@@ -360,6 +445,7 @@ func TestSync(t *testing.T) {
 					ExposeAnnotationKey:        nodeportproxy.DefaultExposeAnnotationKey,
 					EnvoySNIListenerPort:       test.sniListenerPort,
 					EnvoyTunnelingListenerPort: test.tunnelingListenerPort,
+					DefaultRateLimits:          test.defaultRateLimits,
 				},
 			)
 
@@ -395,6 +481,10 @@ func TestSync(t *testing.T) {
 }
 
 func makeNodePortListener(t *testing.T, name string, portValue uint32) *envoylistenerv3.Listener {
+	return makeNodePortListenerWithLimits(t, name, portValue, RateLimits{})
+}
+
+func makeNodePortListenerWithLimits(t *testing.T, name string, portValue uint32, limits RateLimits) *envoylistenerv3.Listener {
 	return &envoylistenerv3.Listener{
 		Name: name,
 		Address: &envoycorev3.Address{
@@ -410,19 +500,17 @@ func makeNodePortListener(t *testing.T, name string, portValue uint32) *envoylis
 		},
 		FilterChains: []*envoylistenerv3.FilterChain{
 			{
-				Filters: []*envoylistenerv3.Filter{
-					{
-						Name: envoywellknown.TCPProxy,
-						ConfigType: &envoylistenerv3.Filter_TypedConfig{
-							TypedConfig: marshalMessage(t, &envoytcpfilterv3.TcpProxy{
-								StatPrefix: "ingress_tcp",
-								ClusterSpecifier: &envoytcpfilterv3.TcpProxy_Cluster{
-									Cluster: name,
-								},
-							}),
-						},
+				Filters: append(limits.networkFilters(name), &envoylistenerv3.Filter{
+					Name: envoywellknown.TCPProxy,
+					ConfigType: &envoylistenerv3.Filter_TypedConfig{
+						TypedConfig: marshalMessage(t, &envoytcpfilterv3.TcpProxy{
+							StatPrefix: name,
+							ClusterSpecifier: &envoytcpfilterv3.TcpProxy_Cluster{
+								Cluster: name,
+							},
+						}),
 					},
-				},
+				}),
 			},
 		},
 	}
@@ -431,13 +519,14 @@ func makeNodePortListener(t *testing.T, name string, portValue uint32) *envoylis
 type hostClusterName struct {
 	Hostname string
 	Cluster  string
+	Limits   RateLimits
 }
 
 func makeSNIListener(t *testing.T, portValue uint32, hostClusterNames ...hostClusterName) *envoylistenerv3.Listener {
 	fcs := []*envoylistenerv3.FilterChain{}
 	for _, hc := range hostClusterNames {
 		tcpProxyConfig := &envoytcpfilterv3.TcpProxy{
-			StatPrefix: "ingress_tcp",
+			StatPrefix: hc.Cluster,
 			ClusterSpecifier: &envoytcpfilterv3.TcpProxy_Cluster{
 				Cluster: hc.Cluster,
 			},
@@ -450,14 +539,12 @@ func makeSNIListener(t *testing.T, portValue uint32, hostClusterNames ...hostClu
 		}
 
 		fcs = append(fcs, &envoylistenerv3.FilterChain{
-			Filters: []*envoylistenerv3.Filter{
-				{
-					Name: envoywellknown.TCPProxy,
-					ConfigType: &envoylistenerv3.Filter_TypedConfig{
-						TypedConfig: tcpProxyConfigMarshalled,
-					},
+			Filters: append(hc.Limits.networkFilters(hc.Cluster), &envoylistenerv3.Filter{
+				Name: envoywellknown.TCPProxy,
+				ConfigType: &envoylistenerv3.Filter_TypedConfig{
+					TypedConfig: tcpProxyConfigMarshalled,
 				},
-			},
+			}),
 			FilterChainMatch: &envoylistenerv3.FilterChainMatch{
 				ServerNames:       []string{hc.Hostname},
 				TransportProtocol: "tls",
@@ -507,6 +594,10 @@ func makeTunnelingListener(t *testing.T, portValue int, hostClusterNames ...host
 }
 
 func makeCluster(t *testing.T, name string, portValue uint32, addresses ...string) *envoyclusterv3.Cluster {
+	return makeClusterWithLimits(t, name, portValue, RateLimits{}, addresses...)
+}
+
+func makeClusterWithLimits(t *testing.T, name string, portValue uint32, limits RateLimits, addresses ...string) *envoyclusterv3.Cluster {
 	lbs := []*envoyendpointv3.LbEndpoint{}
 	for _, address := range addresses {
 		lbs = append(lbs, &envoyendpointv3.LbEndpoint{
@@ -542,6 +633,7 @@ func makeCluster(t *testing.T, name string, portValue uint32, addresses ...strin
 				},
 			},
 		},
+		CircuitBreakers: limits.circuitBreakers(),
 	}
 }
 
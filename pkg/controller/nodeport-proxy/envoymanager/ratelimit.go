@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoymanager
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	envoyclusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	envoycorev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoylistenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoylocalratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/local_ratelimit/v3"
+	envoytypev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+
+	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const localRateLimitFilterName = "envoy.filters.network.local_ratelimit"
+
+// RateLimits contains the connection limits applied to an exposed Service.
+type RateLimits struct {
+	// MaxConnections is the maximum number of concurrent upstream connections
+	// per service port. 0 keeps Envoy's default circuit breaker of 1024.
+	MaxConnections uint32
+	// MaxPendingConnections is the maximum number of connections waiting for
+	// an upstream connection per service port. 0 keeps Envoy's default circuit
+	// breaker of 1024.
+	MaxPendingConnections uint32
+	// ConnectionsPerSecond is the number of new downstream connections accepted
+	// per second and service port. 0 disables the rate limit.
+	ConnectionsPerSecond uint32
+	// ConnectionBurst is the number of new downstream connections that can be
+	// accepted at once. Defaults to ConnectionsPerSecond.
+	ConnectionBurst uint32
+}
+
+// rateLimitsFromAnnotations returns the rate limits for the given Service,
+// starting from the defaults and overriding them with the values found in
+// the annotations.
+func rateLimitsFromAnnotations(svc *corev1.Service, defaults RateLimits) (RateLimits, error) {
+	limits := defaults
+	overrides := []struct {
+		annotation string
+		value      *uint32
+	}{
+		{annotation: nodeportproxy.MaxConnectionsAnnotationKey, value: &limits.MaxConnections},
+		{annotation: nodeportproxy.MaxPendingConnectionsAnnotationKey, value: &limits.MaxPendingConnections},
+		{annotation: nodeportproxy.ConnectionsPerSecondAnnotationKey, value: &limits.ConnectionsPerSecond},
+		{annotation: nodeportproxy.ConnectionBurstAnnotationKey, value: &limits.ConnectionBurst},
+	}
+
+	for _, o := range overrides {
+		val, ok := svc.GetAnnotations()[o.annotation]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseUint(val, 10, 32)
+		if err != nil {
+			return defaults, fmt.Errorf("invalid value %q for annotation %s: %w", val, o.annotation, err)
+		}
+		*o.value = uint32(parsed)
+	}
+
+	return limits, nil
+}
+
+// circuitBreakers returns the circuit breakers for the upstream cluster of a
+// service port or nil if no limit is configured.
+func (l RateLimits) circuitBreakers() *envoyclusterv3.CircuitBreakers {
+	if l.MaxConnections == 0 && l.MaxPendingConnections == 0 {
+		return nil
+	}
+
+	thresholds := &envoyclusterv3.CircuitBreakers_Thresholds{
+		Priority: envoycorev3.RoutingPriority_DEFAULT,
+		// Exposes the remaining_cx and remaining_pending gauges per cluster.
+		TrackRemaining: true,
+	}
+	if l.MaxConnections > 0 {
+		thresholds.MaxConnections = wrapperspb.UInt32(l.MaxConnections)
+	}
+	if l.MaxPendingConnections > 0 {
+		thresholds.MaxPendingRequests = wrapperspb.UInt32(l.MaxPendingConnections)
+	}
+
+	return &envoyclusterv3.CircuitBreakers{
+		Thresholds: []*envoyclusterv3.CircuitBreakers_Thresholds{thresholds},
+	}
+}
+
+// networkFilters returns the network filters limiting the rate of new
+// connections, to be placed in front of the TCP proxy filter.
+func (l RateLimits) networkFilters(statPrefix string) []*envoylistenerv3.Filter {
+	if l.ConnectionsPerSecond == 0 {
+		return nil
+	}
+
+	burst := l.ConnectionBurst
+	if burst == 0 {
+		burst = l.ConnectionsPerSecond
+	}
+
+	rateLimitConfig, err := anypb.New(&envoylocalratelimitv3.LocalRateLimit{
+		StatPrefix: statPrefix,
+		TokenBucket: &envoytypev3.TokenBucket{
+			MaxTokens:     burst,
+			TokensPerFill: wrapperspb.UInt32(l.ConnectionsPerSecond),
+			FillInterval:  durationpb.New(time.Second),
+		},
+	})
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal local rate limit config: %w", err))
+	}
+
+	return []*envoylistenerv3.Filter{
+		{
+			Name: localRateLimitFilterName,
+			ConfigType: &envoylistenerv3.Filter_TypedConfig{
+				TypedConfig: rateLimitConfig,
+			},
+		},
+	}
+}
@@ -105,6 +105,11 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 		svcLog.Debug("skipping service: no expose types provided")
 	}
 
+	limits, err := rateLimitsFromAnnotations(svc, sb.DefaultRateLimits)
+	if err != nil {
+		svcLog.Warnw("ignoring rate limit annotations", "error", err)
+	}
+
 	// Exclude all ports by default, to avoid creating unused clusters.
 	var includePorts sets.Set[string]
 	// Create listeners for NodePortType
@@ -114,14 +119,14 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 			svcLog.Warn("skipping service: it is not of type NodePort", "service")
 		} else {
 			// Add listeners for nodeport services
-			ls, ports := sb.makeListenersForNodePortService(svc)
+			ls, ports := sb.makeListenersForNodePortService(svc, limits)
 			includePorts = ports.Union(includePorts)
 			sb.listeners = append(sb.listeners, ls...)
 		}
 	}
 	// Create filter chains for SNIType
 	if expTypes.Has(nodeportproxy.SNIType) && sb.IsSNIEnabled() {
-		fcs, ports := sb.makeSNIFilterChains(svcLog, svc)
+		fcs, ports := sb.makeSNIFilterChains(svcLog, svc, limits)
 		includePorts = ports.Union(includePorts)
 		sb.fcs = append(sb.fcs, fcs...)
 	}
@@ -134,13 +139,13 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 
 	// Create clusters
 	sb.log.Debugw("creating clusters", "includePorts", includePorts)
-	sb.clusters = append(sb.clusters, sb.makeClusters(svc, eps, includePorts)...)
+	sb.clusters = append(sb.clusters, sb.makeClusters(svc, eps, includePorts, limits)...)
 }
 
 // makeSNIFilterChains returns the FilterChains for the given service and the
 // set of ports that are exposed. Note that the set can be nil, don't try to
 // write to it before doing a nil check.
-func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *corev1.Service) ([]*envoylistenerv3.FilterChain, sets.Set[string]) {
+func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *corev1.Service, limits RateLimits) ([]*envoylistenerv3.FilterChain, sets.Set[string]) {
 	m, err := sb.portHostMappingGetter(svc)
 	if err != nil {
 		svcLog.Warnw("port host mapping is required with SNI expose type", "error", err)
@@ -165,7 +170,7 @@ func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *c
 
 	svcLog.Debugw("creating sni filter chains", "portHostMapping", m)
 	// Besides the filter chains returns the ports that are exposed.
-	return makeSNIFilterChains(svc, m), ports
+	return makeSNIFilterChains(svc, m, limits), ports
 }
 
 // build returns a new Snapshot from the resources derived by the Services
@@ -207,7 +212,7 @@ func makeAccessLog() []*envoyaccesslogv3.AccessLog {
 	return accessLog
 }
 
-func makeSNIFilterChains(service *corev1.Service, p portHostMapping) []*envoylistenerv3.FilterChain {
+func makeSNIFilterChains(service *corev1.Service, p portHostMapping, limits RateLimits) []*envoylistenerv3.FilterChain {
 	var sniFilterChains []*envoylistenerv3.FilterChain
 
 	serviceKey := ServiceKey(service)
@@ -216,7 +221,7 @@ func makeSNIFilterChains(service *corev1.Service, p portHostMapping) []*envoylis
 			servicePortKey := ServicePortKey(serviceKey, &servicePort)
 
 			tcpProxyConfig := &envoytcpfilterv3.TcpProxy{
-				StatPrefix: "ingress_tcp",
+				StatPrefix: servicePortKey,
 				ClusterSpecifier: &envoytcpfilterv3.TcpProxy_Cluster{
 					Cluster: servicePortKey,
 				},
@@ -229,14 +234,12 @@ func makeSNIFilterChains(service *corev1.Service, p portHostMapping) []*envoylis
 			}
 
 			sniFilterChains = append(sniFilterChains, &envoylistenerv3.FilterChain{
-				Filters: []*envoylistenerv3.Filter{
-					{
-						Name: envoywellknown.TCPProxy,
-						ConfigType: &envoylistenerv3.Filter_TypedConfig{
-							TypedConfig: tcpProxyConfigMarshalled,
-						},
+				Filters: append(limits.networkFilters(servicePortKey), &envoylistenerv3.Filter{
+					Name: envoywellknown.TCPProxy,
+					ConfigType: &envoylistenerv3.Filter_TypedConfig{
+						TypedConfig: tcpProxyConfigMarshalled,
 					},
-				},
+				}),
 				FilterChainMatch: &envoylistenerv3.FilterChainMatch{
 					ServerNames:       []string{name},
 					TransportProtocol: "tls",
@@ -398,7 +401,7 @@ func (sb *snapshotBuilder) makeTunnelingListener(vhs ...*envoyroutev3.VirtualHos
 	return tunnelingListener
 }
 
-func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *corev1.Endpoints, includePorts sets.Set[string]) (clusters []envoycachetype.Resource) {
+func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *corev1.Endpoints, includePorts sets.Set[string], limits RateLimits) (clusters []envoycachetype.Resource) {
 	serviceKey := ServiceKey(service)
 	for _, servicePort := range service.Spec.Ports {
 		if !includePorts.Has(servicePort.Name) {
@@ -430,13 +433,14 @@ func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *core
 					},
 				},
 			},
+			CircuitBreakers: limits.circuitBreakers(),
 		}
 		clusters = append(clusters, cluster)
 	}
 	return
 }
 
-func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Service) (listeners []envoycachetype.Resource, exposedPorts sets.Set[string]) {
+func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Service, limits RateLimits) (listeners []envoycachetype.Resource, exposedPorts sets.Set[string]) {
 	serviceKey := ServiceKey(service)
 	exposedPorts = sets.New[string]()
 	for _, servicePort := range service.Spec.Ports {
@@ -448,7 +452,7 @@ func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Servi
 		servicePortKey := ServicePortKey(serviceKey, &servicePort)
 
 		tcpProxyConfig := &envoytcpfilterv3.TcpProxy{
-			StatPrefix: "ingress_tcp",
+			StatPrefix: servicePortKey,
 			ClusterSpecifier: &envoytcpfilterv3.TcpProxy_Cluster{
 				Cluster: servicePortKey,
 			},
@@ -476,14 +480,12 @@ func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Servi
 			},
 			FilterChains: []*envoylistenerv3.FilterChain{
 				{
-					Filters: []*envoylistenerv3.Filter{
-						{
-							Name: envoywellknown.TCPProxy,
-							ConfigType: &envoylistenerv3.Filter_TypedConfig{
-								TypedConfig: tcpProxyConfigMarshalled,
-							},
+					Filters: append(limits.networkFilters(servicePortKey), &envoylistenerv3.Filter{
+						Name: envoywellknown.TCPProxy,
+						ConfigType: &envoylistenerv3.Filter_TypedConfig{
+							TypedConfig: tcpProxyConfigMarshalled,
 						},
-					},
+					}),
 				},
 			},
 		}
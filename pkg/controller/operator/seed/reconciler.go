@@ -229,6 +229,10 @@ func (r *Reconciler) cleanupDeletedSeed(ctx context.Context, cfg *kubermaticv1.K
 		return fmt.Errorf("failed to clean up Cluster MutatingWebhookConfiguration: %w", err)
 	}
 
+	if err := common.CleanupClusterResource(ctx, client, &admissionregistrationv1.ValidatingWebhookConfiguration{}, kubermaticseed.AddonAdmissionWebhookName); err != nil {
+		return fmt.Errorf("failed to clean up Addon ValidatingWebhookConfiguration: %w", err)
+	}
+
 	if err := common.CleanupClusterResource(ctx, client, &admissionregistrationv1.MutatingWebhookConfiguration{}, kubermaticseed.MLAAdminSettingAdmissionWebhookName); err != nil {
 		return fmt.Errorf("failed to clean up Cluster MutatingWebhookConfiguration: %w", err)
 	}
@@ -237,6 +241,10 @@ func (r *Reconciler) cleanupDeletedSeed(ctx context.Context, cfg *kubermaticv1.K
 		return fmt.Errorf("failed to clean up IPAMPool ValidatingWebhookConfiguration: %w", err)
 	}
 
+	if err := common.CleanupClusterResource(ctx, client, &admissionregistrationv1.ValidatingWebhookConfiguration{}, kubermaticseed.EtcdRestoreAdmissionWebhookName); err != nil {
+		return fmt.Errorf("failed to clean up EtcdRestore ValidatingWebhookConfiguration: %w", err)
+	}
+
 	// On shared master+seed clusters, the kubermatic-webhook currently has the -seed-name
 	// flag set; now that the seed (maybe the shared seed, maybe another) is gone, we must
 	// trigger a reconciliation once to get rid of the flag. If the deleted Seed is just
@@ -650,6 +658,8 @@ func (r *Reconciler) reconcileAdmissionWebhooks(ctx context.Context, cfg *kuberm
 		kubermaticseed.ClusterValidatingWebhookConfigurationReconciler(ctx, cfg, client),
 		common.ApplicationDefinitionValidatingWebhookConfigurationReconciler(ctx, cfg, client),
 		kubermaticseed.IPAMPoolValidatingWebhookConfigurationReconciler(ctx, cfg, client),
+		kubermaticseed.AddonValidatingWebhookConfigurationReconciler(ctx, cfg, client),
+		kubermaticseed.EtcdRestoreValidatingWebhookConfigurationReconciler(ctx, cfg, client),
 	}
 
 	if err := reconciling.ReconcileValidatingWebhookConfigurations(ctx, validatingWebhookReconcilers, "", client); err != nil {
@@ -96,6 +96,14 @@ func EnvoyDeploymentReconciler(cfg *kubermaticv1.KubermaticConfiguration, seed *
 				fmt.Sprintf("-envoy-sni-port=%d", EnvoySNIPort),
 				fmt.Sprintf("-envoy-tunneling-port=%d", EnvoyTunnelingPort),
 			}
+			if limits := seed.Spec.NodeportProxy.RateLimits; limits != nil {
+				args = append(args,
+					fmt.Sprintf("-max-connections=%d", limits.MaxConnections),
+					fmt.Sprintf("-max-pending-connections=%d", limits.MaxPendingConnections),
+					fmt.Sprintf("-connections-per-second=%d", limits.ConnectionsPerSecond),
+					fmt.Sprintf("-connection-burst=%d", limits.ConnectionBurst),
+				)
+			}
 			d.Spec.Template.Spec.Containers = []corev1.Container{
 				{
 					Name:    "envoy-manager",
@@ -17,11 +17,8 @@ limitations under the License.
 package addon
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"path"
 	"reflect"
 	"strings"
@@ -35,18 +32,16 @@ import (
 	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/resources"
-	"k8c.io/kubermatic/v2/pkg/semver"
-	"k8c.io/kubermatic/v2/pkg/util/kubectl"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -56,7 +51,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
-	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -71,6 +65,7 @@ const (
 type KubeconfigProvider interface {
 	GetAdminKubeconfig(ctx context.Context, c *kubermaticv1.Cluster) ([]byte, error)
 	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+	GetK8sClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (kubernetes.Interface, error)
 }
 
 // Reconciler stores necessary components that are required to manage in-cluster Add-On's.
@@ -235,7 +230,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 
 // garbageCollectAddon is called when the cluster that owns the addon is gone
 // or in deletion. The function ensures that the addon is removed without going
-// through the normal cleanup procedure (i.e. no `kubectl delete`).
+// through the normal cleanup procedure (i.e. without deleting the applied objects).
 func (r *Reconciler) garbageCollectAddon(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon) error {
 	if addon.DeletionTimestamp == nil {
 		if err := r.Delete(ctx, addon); err != nil {
@@ -278,7 +273,7 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, addo
 	// we do this to allow users to "edit/delete" resources deployed by unlabeled addons,
 	// while we enfornce the labeled ones
 	if addonResourcesCreated(addon) && !hasEnsureResourcesLabel(addon) {
-		return nil, nil
+		return r.ensureHealthStatus(ctx, log, addon, cluster)
 	}
 
 	// Reconciling
@@ -291,13 +286,50 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, addo
 	if err := r.ensureResourcesCreatedConditionIsSet(ctx, addon); err != nil {
 		return nil, fmt.Errorf("failed to set add ResourcesCreated Condition: %w", err)
 	}
-	return nil, nil
+	return r.ensureHealthStatus(ctx, log, addon, cluster)
 }
 
 func (r *Reconciler) removeCleanupFinalizer(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon) error {
 	return kuberneteshelper.TryRemoveFinalizer(ctx, r, addon, cleanupFinalizerName)
 }
 
+// getAddonVariables returns the variables of the addon merged over the globally configured variables
+// for the addon, defaulted, validated and typed according to the variables schema of the matching
+// AddonConfig, if there is one. Schema defaults are only applied to variables that are neither set
+// on the addon nor globally.
+func (r *Reconciler) getAddonVariables(ctx context.Context, addon *kubermaticv1.Addon) (map[string]interface{}, error) {
+	addonVariables, err := addonutils.ParseVariables(addon.Spec.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make(map[string]interface{})
+	if sub := r.addonVariables[addon.Spec.Name]; sub != nil {
+		for k, v := range sub.(map[string]interface{}) {
+			variables[k] = v
+		}
+	}
+	for k, v := range addonVariables {
+		variables[k] = v
+	}
+
+	addonConfig := &kubermaticv1.AddonConfig{}
+	if err := r.Get(ctx, types.NamespacedName{Name: addon.Spec.Name}, addonConfig); err != nil {
+		// the AddonConfig CRD might not be installed on the seed yet
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return variables, nil
+		}
+		return nil, fmt.Errorf("failed to get AddonConfig: %w", err)
+	}
+
+	variables, err = addonutils.ProcessVariables(addonConfig.Spec.VariablesSchema, variables)
+	if err != nil {
+		return nil, fmt.Errorf("invalid addon variables: %w", err)
+	}
+
+	return variables, nil
+}
+
 func (r *Reconciler) getAddonManifests(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) ([]addonutils.Manifest, error) {
 	addonDir := r.kubernetesAddonDir
 	clusterIP, err := resources.UserClusterDNSResolverIP(cluster)
@@ -321,16 +353,9 @@ func (r *Reconciler) getAddonManifests(ctx context.Context, log *zap.SugaredLogg
 	}
 
 	// Add addon variables if available.
-	variables := make(map[string]interface{})
-
-	if sub := r.addonVariables[addon.Spec.Name]; sub != nil {
-		variables = sub.(map[string]interface{})
-	}
-
-	if addon.Spec.Variables != nil && len(addon.Spec.Variables.Raw) > 0 {
-		if err = json.Unmarshal(addon.Spec.Variables.Raw, &variables); err != nil {
-			return nil, err
-		}
+	variables, err := r.getAddonVariables(ctx, addon)
+	if err != nil {
+		return nil, err
 	}
 
 	// listing IPAM allocations for cluster
@@ -363,23 +388,9 @@ func (r *Reconciler) getAddonManifests(ctx context.Context, log *zap.SugaredLogg
 	return allManifests, nil
 }
 
-// combineManifests returns all manifests combined into a multi document yaml.
-func (r *Reconciler) combineManifests(manifests []*bytes.Buffer) *bytes.Buffer {
-	parts := make([]string, len(manifests))
-	for i, m := range manifests {
-		s := m.String()
-		s = strings.TrimSuffix(s, "\n")
-		s = strings.TrimSpace(s)
-		parts[i] = s
-	}
-
-	return bytes.NewBufferString(strings.Join(parts, "\n---\n") + "\n")
-}
-
-// ensureAddonLabelOnManifests adds the addonLabelKey label to all manifests.
-// For this to happen we need to decode all yaml files to json, parse them, add the label and finally encode to yaml again.
-func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, manifests []addonutils.Manifest) ([]*bytes.Buffer, error) {
-	var rawManifests []*bytes.Buffer
+// ensureAddonLabelOnManifests decodes all manifests and adds the addonLabelKey label to them.
+func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, manifests []addonutils.Manifest) ([]*metav1unstructured.Unstructured, error) {
+	var objects []*metav1unstructured.Unstructured
 
 	wantLabels := r.getAddonLabel(addon)
 	for _, m := range manifests {
@@ -399,22 +410,10 @@ func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, mani
 		}
 		parsedUnstructuredObj.SetLabels(existingLabels)
 
-		jsonBuffer := &bytes.Buffer{}
-		if err := metav1unstructured.UnstructuredJSONScheme.Encode(parsedUnstructuredObj, jsonBuffer); err != nil {
-			return nil, fmt.Errorf("encoding json failed: %w", err)
-		}
-
-		// Must be encoding back to yaml, otherwise kubectl fails to apply because it tries to parse the whole
-		// thing as json
-		yamlBytes, err := yaml.JSONToYAML(jsonBuffer.Bytes())
-		if err != nil {
-			return nil, err
-		}
-
-		rawManifests = append(rawManifests, bytes.NewBuffer(yamlBytes))
+		objects = append(objects, parsedUnstructuredObj)
 	}
 
-	return rawManifests, nil
+	return objects, nil
 }
 
 func (r *Reconciler) getAddonLabel(addon *kubermaticv1.Addon) map[string]string {
@@ -423,123 +422,61 @@ func (r *Reconciler) getAddonLabel(addon *kubermaticv1.Addon) map[string]string
 	}
 }
 
-type fileHandlingDone func()
-
-func getFileDeleteFinalizer(log *zap.SugaredLogger, filename string) fileHandlingDone {
-	return func() {
-		if err := os.RemoveAll(filename); err != nil {
-			log.Errorw("Failed to delete file", zap.Error(err), "file", filename)
-		}
-	}
-}
-
-func (r *Reconciler) writeCombinedManifest(log *zap.SugaredLogger, manifest *bytes.Buffer, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, fileHandlingDone, error) {
-	// Write combined Manifest to disk
-	manifestFilename := path.Join("/tmp", fmt.Sprintf("cluster-%s-%s.yaml", cluster.Name, addon.Name))
-	if err := os.WriteFile(manifestFilename, manifest.Bytes(), 0644); err != nil {
-		return "", nil, fmt.Errorf("failed to write combined manifest to %s: %w", manifestFilename, err)
-	}
-	log.Debugw("Wrote combined manifest", "file", manifestFilename)
-
-	return manifestFilename, getFileDeleteFinalizer(log, manifestFilename), nil
-}
-
-func (r *Reconciler) writeAdminKubeconfig(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, fileHandlingDone, error) {
-	// Write kubeconfig to disk
-	kubeconfig, err := r.KubeconfigProvider.GetAdminKubeconfig(ctx, cluster)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to get admin kubeconfig for cluster %s: %w", cluster.Name, err)
-	}
-	kubeconfigFilename := path.Join("/tmp", fmt.Sprintf("cluster-%s-addon-%s-kubeconfig", cluster.Name, addon.Name))
-	if err := os.WriteFile(kubeconfigFilename, kubeconfig, 0644); err != nil {
-		return "", nil, fmt.Errorf("failed to write admin kubeconfig for cluster %s: %w", cluster.Name, err)
-	}
-	log.Debugw("Wrote admin kubeconfig", "file", kubeconfigFilename)
-
-	return kubeconfigFilename, getFileDeleteFinalizer(log, kubeconfigFilename), nil
-}
-
-func (r *Reconciler) setupManifestInteraction(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, string, fileHandlingDone, error) {
+// renderManifests returns the labeled objects of all addon manifests.
+func (r *Reconciler) renderManifests(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) ([]*metav1unstructured.Unstructured, error) {
 	manifests, err := r.getAddonManifests(ctx, log, addon, cluster)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to get addon manifests: %w", err)
+		return nil, fmt.Errorf("failed to get addon manifests: %w", err)
 	}
 
-	rawManifests, err := r.ensureAddonLabelOnManifests(addon, manifests)
+	objects, err := r.ensureAddonLabelOnManifests(addon, manifests)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to add the addon specific label to all addon resources: %w", err)
+		return nil, fmt.Errorf("failed to add the addon specific label to all addon resources: %w", err)
 	}
 
-	rawManifest := r.combineManifests(rawManifests)
-	manifestFilename, manifestDone, err := r.writeCombinedManifest(log, rawManifest, addon, cluster)
+	return objects, nil
+}
+
+func (r *Reconciler) ensureIsInstalled(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
+	objects, err := r.renderManifests(ctx, log, addon, cluster)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to write all addon resources into a combined manifest file: %w", err)
+		return err
 	}
 
-	kubeconfigFilename, kubeconfigDone, err := r.writeAdminKubeconfig(ctx, log, addon, cluster)
+	userClusterClient, err := r.KubeconfigProvider.GetClient(ctx, cluster)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to write the admin kubeconfig to the local filesystem: %w", err)
+		return fmt.Errorf("failed to get client for usercluster: %w", err)
 	}
 
-	done := func() {
-		kubeconfigDone()
-		manifestDone()
+	// Objects that have been applied before but are not part of the manifests anymore are deleted.
+	applied, applyErr := applyObjects(ctx, log, userClusterClient, objects, addon.Status.AppliedResources)
+	if err := r.updateAppliedStatus(ctx, addon, applied, applyErr); err != nil {
+		return fmt.Errorf("failed to update addon status: %w", err)
 	}
-	return kubeconfigFilename, manifestFilename, done, nil
-}
-
-func (r *Reconciler) getApplyCommand(ctx context.Context, kubeconfigFilename, manifestFilename string, selector fmt.Stringer, clusterVersion semver.Semver) (*exec.Cmd, error) {
-	binary, err := kubectl.BinaryForClusterVersion(&clusterVersion)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine kubectl binary to use: %w", err)
+	if applyErr != nil {
+		return fmt.Errorf("failed to apply addon %s of cluster %s: %w", addon.Name, cluster.Name, applyErr)
 	}
 
-	cmd := exec.CommandContext(
-		ctx,
-		binary,
-		"--kubeconfig", kubeconfigFilename,
-		"apply",
-		"--prune",
-		"--filename", manifestFilename,
-		"--selector", selector.String(),
-	)
-	return cmd, nil
+	return nil
 }
 
-func (r *Reconciler) ensureIsInstalled(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
-	kubeconfigFilename, manifestFilename, done, err := r.setupManifestInteraction(ctx, log, addon, cluster)
-	if err != nil {
-		return err
+// updateAppliedStatus records the applied objects and the per-object errors in the addon status.
+// The status is only patched if it changed, as every patch triggers another reconciliation.
+func (r *Reconciler) updateAppliedStatus(ctx context.Context, addon *kubermaticv1.Addon, applied []kubermaticv1.AddonAppliedResource, applyErr error) error {
+	status, reason, message := corev1.ConditionTrue, "", ""
+	if applyErr != nil {
+		status, reason, message = corev1.ConditionFalse, "ApplyFailed", applyErr.Error()
 	}
-	defer done()
 
-	d, err := os.ReadFile(manifestFilename)
-	if err != nil {
-		return err
-	}
-	sd := strings.TrimSpace(string(d))
-	if len(sd) == 0 {
-		log.Debug("Skipping addon installation as the manifest is empty after parsing")
+	if !addonConditionChanged(addon, kubermaticv1.AddonResourcesApplied, status, reason, message) &&
+		equality.Semantic.DeepEqual(addon.Status.AppliedResources, applied) {
 		return nil
 	}
 
-	// We delete all resources with this label which are not in the combined manifest
-	selector := labels.SelectorFromSet(r.getAddonLabel(addon))
-	cmd, err := r.getApplyCommand(ctx, kubeconfigFilename, manifestFilename, selector, cluster.Status.Versions.ControlPlane)
-	if err != nil {
-		return fmt.Errorf("failed to create command: %w", err)
-	}
-
-	cmdLog := log.With("cmd", strings.Join(cmd.Args, " "))
-
-	cmdLog.Debug("Applying manifest...")
-	out, err := cmd.CombinedOutput()
-	cmdLog.Debugw("Finished executing command", "output", string(out))
-	if err != nil {
-		return fmt.Errorf("failed to execute '%s' for addon %s of cluster %s: %w\n%s", strings.Join(cmd.Args, " "), addon.Name, cluster.Name, err, string(out))
-	}
-
-	return nil
+	oldAddon := addon.DeepCopy()
+	addon.Status.AppliedResources = applied
+	setAddonCondition(addon, kubermaticv1.AddonResourcesApplied, status, reason, message)
+	return r.Client.Status().Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
 }
 
 func (r *Reconciler) ensureFinalizerIsSet(ctx context.Context, addon *kubermaticv1.Addon) error {
@@ -552,35 +489,38 @@ func (r *Reconciler) ensureResourcesCreatedConditionIsSet(ctx context.Context, a
 	}
 
 	oldAddon := addon.DeepCopy()
-	setAddonCondition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionTrue)
+	setAddonCondition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionTrue, "", "")
 	return r.Client.Status().Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
 }
 
 func (r *Reconciler) cleanupManifests(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
-	kubeconfigFilename, manifestFilename, done, err := r.setupManifestInteraction(ctx, log, addon, cluster)
+	objects, err := r.renderManifests(ctx, log, addon, cluster)
 	if err != nil {
 		// FIXME: use a dedicated error type and proper error unwrapping when we have the technology to do it
-		if strings.Contains(err.Error(), "no such file or directory") { // if the manifest is already deleted, that's ok
-			log.Debugf("cleanupManifests failed for addon %s/%s: %v", addon.Namespace, addon.Name, err)
-			return nil
+		if !strings.Contains(err.Error(), "no such file or directory") {
+			return err
 		}
-		return err
+		// if the manifest is already deleted, only the previously applied objects are deleted
+		log.Debugf("Failed to render manifests for addon %s/%s: %v", addon.Namespace, addon.Name, err)
 	}
-	defer done()
 
-	binary, err := kubectl.BinaryForClusterVersion(&cluster.Status.Versions.ControlPlane)
+	userClusterClient, err := r.KubeconfigProvider.GetClient(ctx, cluster)
 	if err != nil {
-		return fmt.Errorf("failed to determine kubectl binary to use: %w", err)
+		return fmt.Errorf("failed to get client for usercluster: %w", err)
 	}
 
-	cmd := exec.CommandContext(ctx, binary, "--kubeconfig", kubeconfigFilename, "delete", "-f", manifestFilename, "--ignore-not-found")
-	cmdLog := log.With("cmd", strings.Join(cmd.Args, " "))
+	if err := setDefaultNamespace(userClusterClient.RESTMapper(), objects); err != nil {
+		return err
+	}
 
-	cmdLog.Debug("Deleting resources...")
-	out, err := cmd.CombinedOutput()
-	cmdLog.Debugw("Finished executing command", "output", string(out))
-	if err != nil {
-		return fmt.Errorf("failed to execute '%s' for addon %s of cluster %s: %w\n%s", strings.Join(cmd.Args, " "), addon.Name, cluster.Name, err, string(out))
+	resources := addon.Status.AppliedResources
+	for _, obj := range objects {
+		resources = mergeResources(resources, []kubermaticv1.AddonAppliedResource{toAppliedResource(obj)})
+	}
+
+	log.Debug("Deleting resources...")
+	if _, err := deleteResources(ctx, log, userClusterClient, resources); err != nil {
+		return fmt.Errorf("failed to delete resources of addon %s of cluster %s: %w", addon.Name, cluster.Name, err)
 	}
 	return nil
 }
@@ -620,7 +560,7 @@ func formatGVK(gvk kubermaticv1.GroupVersionKind) string {
 	return fmt.Sprintf("%s/%s %s", gvk.Group, gvk.Version, gvk.Kind)
 }
 
-func setAddonCondition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus) {
+func setAddonCondition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus, reason, message string) {
 	now := metav1.Now()
 
 	condition, exists := a.Status.Conditions[condType]
@@ -630,6 +570,8 @@ func setAddonCondition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditi
 
 	condition.Status = status
 	condition.LastHeartbeatTime = now
+	condition.Reason = reason
+	condition.Message = message
 
 	if a.Status.Conditions == nil {
 		a.Status.Conditions = map[kubermaticv1.AddonConditionType]kubermaticv1.AddonCondition{}
@@ -637,6 +579,12 @@ func setAddonCondition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditi
 	a.Status.Conditions[condType] = condition
 }
 
+// addonConditionChanged returns true if setting the condition would change anything but its heartbeat.
+func addonConditionChanged(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus, reason, message string) bool {
+	condition, exists := a.Status.Conditions[condType]
+	return !exists || condition.Status != status || condition.Reason != reason || condition.Message != message
+}
+
 func addonResourcesCreated(addon *kubermaticv1.Addon) bool {
 	return addon.Status.Conditions[kubermaticv1.AddonResourcesCreated].Status == corev1.ConditionTrue
 }
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -30,17 +31,18 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
 	"k8c.io/kubermatic/v2/pkg/cni"
-	"k8c.io/kubermatic/v2/pkg/defaulting"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/semver"
-	"k8c.io/kubermatic/v2/pkg/util/kubectl"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
 )
 
 var testManifests = []string{
@@ -119,11 +121,6 @@ spec:
 `
 )
 
-var (
-	// testManifest1 & testManifest3 have a linebreak at the end, testManifest2 not.
-	combinedTestManifest = fmt.Sprintf("%s---\n%s\n---\n%s", testManifests[0], testManifests[1], testManifests[2])
-)
-
 type fakeKubeconfigProvider struct{}
 
 func (f *fakeKubeconfigProvider) GetAdminKubeconfig(_ context.Context, c *kubermaticv1.Cluster) ([]byte, error) {
@@ -134,19 +131,8 @@ func (f *fakeKubeconfigProvider) GetClient(_ context.Context, c *kubermaticv1.Cl
 	return nil, errors.New("not implemented")
 }
 
-func TestController_combineManifests(t *testing.T) {
-	controller := &Reconciler{}
-
-	var manifests []*bytes.Buffer
-	for _, m := range testManifests {
-		manifests = append(manifests, bytes.NewBufferString(m))
-	}
-
-	manifest := controller.combineManifests(manifests)
-
-	if manifest.String() != combinedTestManifest {
-		t.Fatalf("invalid combined manifest returned. Expected \n%s, Got \n%s", combinedTestManifest, manifest.String())
-	}
+func (f *fakeKubeconfigProvider) GetK8sClient(_ context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (kubernetes.Interface, error) {
+	return nil, errors.New("not implemented")
 }
 
 func setupTestCluster(cidrBlock string) *kubermaticv1.Cluster {
@@ -225,6 +211,7 @@ func TestController_getAddonKubeDNStManifests(t *testing.T) {
 
 	controller := &Reconciler{
 		kubernetesAddonDir: addonDir,
+		Client:             fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
 	manifests, err := controller.getAddonManifests(ctx, log, addon, cluster)
@@ -279,6 +266,7 @@ func TestController_getAddonDeploymentManifests(t *testing.T) {
 
 	controller := &Reconciler{
 		kubernetesAddonDir: addonDir,
+		Client:             fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
 		overwriteRegistry:  "bar.io",
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
@@ -318,6 +306,7 @@ func TestController_getAddonDeploymentManifestsDefault(t *testing.T) {
 
 	controller := &Reconciler{
 		kubernetesAddonDir: addonDir,
+		Client:             fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
 	manifests, err := controller.getAddonManifests(context.Background(), log, addon, cluster)
@@ -362,6 +351,7 @@ func TestController_getAddonManifests(t *testing.T) {
 
 	controller := &Reconciler{
 		kubernetesAddonDir: addonDir,
+		Client:             fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
 	manifests, err := controller.getAddonManifests(context.Background(), log, addon, cluster)
@@ -411,33 +401,12 @@ func TestController_ensureAddonLabelOnManifests(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if labeledManifests[0].String() != testManifest1WithLabel {
-		t.Fatalf("invalid labeled manifest returned. Expected \n%q, Got \n%q", testManifest1WithLabel, labeledManifests[0].String())
-	}
-}
-
-func TestController_getApplyCommand(t *testing.T) {
-	controller := &Reconciler{}
-
-	clusterVersion := defaulting.DefaultKubernetesVersioning.Default
-	if clusterVersion == nil {
-		t.Fatal("Should be able to determine default Kubernetes version, but got nil")
-	}
-
-	binary, err := kubectl.BinaryForClusterVersion(clusterVersion)
+	labeledManifest, err := yaml.Marshal(labeledManifests[0].Object)
 	if err != nil {
-		t.Fatalf("Should be able to determine a kubectl binary for %q, but got %v", clusterVersion, err)
-	}
-
-	cmd, err := controller.getApplyCommand(context.Background(), "/opt/kubeconfig", "/opt/manifest.yaml", labels.SelectorFromSet(map[string]string{"foo": "bar"}), *clusterVersion)
-	if err != nil {
-		t.Fatalf("Should be able to determine the command, but got %v", err)
+		t.Fatal(err)
 	}
-
-	expected := fmt.Sprintf("%s --kubeconfig /opt/kubeconfig apply --prune --filename /opt/manifest.yaml --selector foo=bar", binary)
-	got := strings.Join(cmd.Args, " ")
-	if got != expected {
-		t.Fatalf("invalid apply command returned. Expected \n%s, Got \n%s", expected, got)
+	if string(labeledManifest) != testManifest1WithLabel {
+		t.Fatalf("invalid labeled manifest returned. Expected \n%q, Got \n%q", testManifest1WithLabel, string(labeledManifest))
 	}
 }
 
@@ -447,9 +416,58 @@ func TestHugeManifest(t *testing.T) {
 	addon := setupTestAddon("istio")
 	r := &Reconciler{
 		kubernetesAddonDir: "./testdata",
+		Client:             fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
-	if _, _, _, err := r.setupManifestInteraction(context.Background(), log, addon, cluster); err != nil {
-		t.Fatalf("failed to setup manifest interaction: %v", err)
+	if _, err := r.renderManifests(context.Background(), log, addon, cluster); err != nil {
+		t.Fatalf("failed to render manifests: %v", err)
+	}
+}
+
+func TestGetAddonVariables(t *testing.T) {
+	addon := setupTestAddon("test")
+	addon.Spec.Variables = &runtime.RawExtension{Raw: []byte(`{"replicas":3}`)}
+
+	addonConfig := &kubermaticv1.AddonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: addon.Spec.Name,
+		},
+		Spec: kubermaticv1.AddonConfigSpec{
+			VariablesSchema: &runtime.RawExtension{Raw: []byte(`{
+				"type": "object",
+				"properties": {
+					"replicas": {"type": "integer", "default": 1},
+					"image": {"type": "string", "default": "schema-default"},
+					"logLevel": {"type": "string", "default": "info"}
+				}
+			}`)},
+		},
+	}
+
+	r := &Reconciler{
+		Client: fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(addonConfig).Build(),
+		addonVariables: map[string]interface{}{
+			addon.Spec.Name: map[string]interface{}{
+				"replicas": 2,
+				"image":    "operator-configured",
+			},
+		},
+	}
+
+	variables, err := r.getAddonVariables(context.Background(), addon)
+	if err != nil {
+		t.Fatalf("failed to get addon variables: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		// set on the addon
+		"replicas": int64(3),
+		// set globally, the schema default must not override it
+		"image": "operator-configured",
+		// set nowhere
+		"logLevel": "info",
+	}
+	if !reflect.DeepEqual(variables, expected) {
+		t.Fatalf("expected variables %v, but got %v", expected, variables)
 	}
 }
@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldManager is the field manager used for server-side apply of the addon manifests.
+const fieldManager = ControllerName
+
+// clientSideApplyManagers are the field managers of objects that have been applied with
+// `kubectl apply`, which the addon controller used before it switched to server-side apply.
+var clientSideApplyManagers = sets.New("kubectl-client-side-apply", "before-first-apply")
+
+// applyObjects applies objects into the user cluster with server-side apply, then deletes the
+// objects listed in previouslyApplied that are not part of objects anymore.
+// It returns the objects that have to be recorded in the addon status. If an error occurred,
+// previouslyApplied objects are kept, so they can be pruned by a later reconciliation.
+func applyObjects(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, objects []*unstructured.Unstructured, previouslyApplied []kubermaticv1.AddonAppliedResource) ([]kubermaticv1.AddonAppliedResource, error) {
+	if err := setDefaultNamespace(userClient.RESTMapper(), objects); err != nil {
+		return previouslyApplied, err
+	}
+
+	known := sets.New[string]()
+	for _, res := range previouslyApplied {
+		known.Insert(resourceKey(res))
+	}
+
+	var applied []kubermaticv1.AddonAppliedResource
+	var errs []error
+	for _, obj := range objects {
+		res := toAppliedResource(obj)
+
+		// Objects that have never been applied by this controller might still be owned
+		// by kubectl, so their fields are handed over to our field manager first.
+		if !known.Has(resourceKey(res)) {
+			if err := migrateClientSideApply(ctx, userClient, obj); err != nil {
+				errs = append(errs, fmt.Errorf("failed to migrate %s to server-side apply: %w", formatResource(res), err))
+				continue
+			}
+		}
+
+		log.Debugw("Applying object", "kind", res.Kind, "namespace", res.Namespace, "name", res.Name)
+		if err := userClient.Patch(ctx, obj, ctrlruntimeclient.Apply, ctrlruntimeclient.FieldOwner(fieldManager), ctrlruntimeclient.ForceOwnership); err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply %s: %w", formatResource(res), err))
+			continue
+		}
+		applied = append(applied, res)
+	}
+
+	if len(errs) > 0 {
+		return mergeResources(previouslyApplied, applied), kerrors.NewAggregate(errs)
+	}
+
+	prune := prunableResources(previouslyApplied, applied)
+	notPruned, err := deleteResources(ctx, log, userClient, prune)
+	if err != nil {
+		return mergeResources(notPruned, applied), err
+	}
+
+	return applied, nil
+}
+
+// migrateClientSideApply transfers the ownership of the fields managed by `kubectl apply` to
+// our field manager. Otherwise fields that have been removed from the manifests would never
+// be removed from the object.
+func migrateClientSideApply(ctx context.Context, userClient ctrlruntimeclient.Client, obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := userClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), existing); err != nil {
+		// nothing to migrate; if the kind is unknown, applying the object reports the error
+		if isGoneError(err) {
+			return nil
+		}
+		return err
+	}
+
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, clientSideApplyManagers, fieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+
+	return userClient.Patch(ctx, existing, ctrlruntimeclient.RawPatch(types.JSONPatchType, patch))
+}
+
+// deleteResources deletes the resources from the user cluster in reverse order. It returns the
+// resources that could not be deleted.
+func deleteResources(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, resources []kubermaticv1.AddonAppliedResource) ([]kubermaticv1.AddonAppliedResource, error) {
+	var remaining []kubermaticv1.AddonAppliedResource
+	var errs []error
+	for i := len(resources) - 1; i >= 0; i-- {
+		res := resources[i]
+		log.Debugw("Deleting object", "kind", res.Kind, "namespace", res.Namespace, "name", res.Name)
+		if err := userClient.Delete(ctx, toObject(res), ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !isGoneError(err) {
+			remaining = append(remaining, res)
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", formatResource(res), err))
+		}
+	}
+	return remaining, kerrors.NewAggregate(errs)
+}
+
+// setDefaultNamespace sets the default namespace on namespaced objects that do not define one,
+// like `kubectl apply` does.
+func setDefaultNamespace(mapper meta.RESTMapper, objects []*unstructured.Unstructured) error {
+	for _, obj := range objects {
+		if obj.GetNamespace() != "" {
+			continue
+		}
+
+		gvk := obj.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			// The kind may be defined by a CRD that is not yet installed, applying the object
+			// reports the error and it is retried later.
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get REST mapping for %s: %w", gvk, err)
+		}
+
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			obj.SetNamespace(metav1.NamespaceDefault)
+		}
+	}
+	return nil
+}
+
+// toAppliedResource returns the AddonAppliedResource identifying obj.
+func toAppliedResource(obj *unstructured.Unstructured) kubermaticv1.AddonAppliedResource {
+	gvk := obj.GroupVersionKind()
+	return kubermaticv1.AddonAppliedResource{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+// toObject returns an unstructured object that only holds the identity of res.
+func toObject(res kubermaticv1.AddonAppliedResource) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: res.Group, Version: res.Version, Kind: res.Kind})
+	obj.SetNamespace(res.Namespace)
+	obj.SetName(res.Name)
+	return obj
+}
+
+// resourceKey identifies an applied resource independently of its API version, so that an object
+// whose apiVersion has been bumped in the manifests is not pruned.
+func resourceKey(res kubermaticv1.AddonAppliedResource) string {
+	return res.Group + "/" + res.Kind + "/" + res.Namespace + "/" + res.Name
+}
+
+func formatResource(res kubermaticv1.AddonAppliedResource) string {
+	if res.Namespace == "" {
+		return fmt.Sprintf("%s %s", res.Kind, res.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", res.Kind, res.Namespace, res.Name)
+}
+
+// prunableResources returns the resources from previous that are not part of current.
+func prunableResources(previous []kubermaticv1.AddonAppliedResource, current []kubermaticv1.AddonAppliedResource) []kubermaticv1.AddonAppliedResource {
+	keep := sets.New[string]()
+	for _, res := range current {
+		keep.Insert(resourceKey(res))
+	}
+
+	var prune []kubermaticv1.AddonAppliedResource
+	for _, res := range previous {
+		if !keep.Has(resourceKey(res)) {
+			prune = append(prune, res)
+		}
+	}
+	return prune
+}
+
+// mergeResources returns the union of a and b without duplicates. Resources from b take
+// precedence, so that their API version is recorded.
+func mergeResources(a []kubermaticv1.AddonAppliedResource, b []kubermaticv1.AddonAppliedResource) []kubermaticv1.AddonAppliedResource {
+	seen := sets.New[string]()
+	var res []kubermaticv1.AddonAppliedResource
+	for _, r := range append(append([]kubermaticv1.AddonAppliedResource{}, b...), a...) {
+		if seen.Has(resourceKey(r)) {
+			continue
+		}
+		seen.Insert(resourceKey(r))
+		res = append(res, r)
+	}
+	return res
+}
+
+// isGoneError returns true if the object or its kind does not exist anymore.
+func isGoneError(err error) bool {
+	return apierrors.IsNotFound(err) || meta.IsNoMatchError(err)
+}
@@ -0,0 +1,375 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var (
+	testConfigMap     = kubermaticv1.AddonAppliedResource{Version: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "cm"}
+	testNamespace     = kubermaticv1.AddonAppliedResource{Version: "v1", Kind: "Namespace", Name: "ns"}
+	testDeployment    = kubermaticv1.AddonAppliedResource{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "kube-system", Name: "deploy"}
+	testDeploymentOld = kubermaticv1.AddonAppliedResource{Group: "apps", Version: "v1beta1", Kind: "Deployment", Namespace: "kube-system", Name: "deploy"}
+)
+
+func TestSetDefaultNamespace(t *testing.T) {
+	objects := []*unstructured.Unstructured{
+		toObject(kubermaticv1.AddonAppliedResource{Version: "v1", Kind: "ConfigMap", Name: "cm"}),
+		toObject(testNamespace),
+		toObject(testDeployment),
+		toObject(kubermaticv1.AddonAppliedResource{Group: "example.com", Version: "v1", Kind: "Foo", Name: "foo"}),
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	if err := setDefaultNamespace(mapper, objects); err != nil {
+		t.Fatalf("failed to set default namespace: %v", err)
+	}
+
+	namespaces := map[string]string{}
+	for _, obj := range objects {
+		namespaces[obj.GetKind()] = obj.GetNamespace()
+	}
+
+	expected := map[string]string{
+		"ConfigMap":  metav1.NamespaceDefault,
+		"Namespace":  "",
+		"Deployment": "kube-system",
+		// unknown kinds are left untouched
+		"Foo": "",
+	}
+	if !reflect.DeepEqual(namespaces, expected) {
+		t.Errorf("expected namespaces %v, got %v", expected, namespaces)
+	}
+}
+
+func TestPrunableResources(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []kubermaticv1.AddonAppliedResource
+		current  []kubermaticv1.AddonAppliedResource
+		expected []kubermaticv1.AddonAppliedResource
+	}{
+		{
+			name:     "nothing is pruned on first installation",
+			previous: nil,
+			current:  []kubermaticv1.AddonAppliedResource{testConfigMap, testDeployment},
+			expected: nil,
+		},
+		{
+			name:     "removed objects are pruned",
+			previous: []kubermaticv1.AddonAppliedResource{testNamespace, testConfigMap, testDeployment},
+			current:  []kubermaticv1.AddonAppliedResource{testConfigMap},
+			expected: []kubermaticv1.AddonAppliedResource{testNamespace, testDeployment},
+		},
+		{
+			name:     "objects whose api version changed are not pruned",
+			previous: []kubermaticv1.AddonAppliedResource{testDeploymentOld},
+			current:  []kubermaticv1.AddonAppliedResource{testDeployment},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if res := prunableResources(tt.previous, tt.current); !reflect.DeepEqual(res, tt.expected) {
+				t.Errorf("prunableResources() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMergeResources(t *testing.T) {
+	res := mergeResources(
+		[]kubermaticv1.AddonAppliedResource{testNamespace, testDeploymentOld},
+		[]kubermaticv1.AddonAppliedResource{testConfigMap, testDeployment},
+	)
+
+	expected := []kubermaticv1.AddonAppliedResource{testConfigMap, testDeployment, testNamespace}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("mergeResources() = %v, want %v", res, expected)
+	}
+}
+
+func TestUpdateAppliedStatus(t *testing.T) {
+	addon := setupTestAddon("test")
+	addon.Namespace = "cluster-test"
+	client := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(addon).
+		Build()
+	r := &Reconciler{Client: client}
+
+	getAddon := func() *kubermaticv1.Addon {
+		current := &kubermaticv1.Addon{}
+		if err := client.Get(context.Background(), ctrlruntimeclient.ObjectKeyFromObject(addon), current); err != nil {
+			t.Fatalf("failed to get addon: %v", err)
+		}
+		return current
+	}
+
+	// a failed apply records the error and keeps the previously applied resources
+	applied := []kubermaticv1.AddonAppliedResource{testConfigMap, testNamespace}
+	if err := r.updateAppliedStatus(context.Background(), addon, applied, errors.New("failed to apply Namespace ns")); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+
+	current := getAddon()
+	condition := current.Status.Conditions[kubermaticv1.AddonResourcesApplied]
+	if condition.Status != corev1.ConditionFalse || condition.Message != "failed to apply Namespace ns" {
+		t.Errorf("expected a false condition with the apply error, got %+v", condition)
+	}
+	if !reflect.DeepEqual(current.Status.AppliedResources, applied) {
+		t.Errorf("expected applied resources %v, got %v", applied, current.Status.AppliedResources)
+	}
+
+	// a successful apply clears the error
+	applied = []kubermaticv1.AddonAppliedResource{testConfigMap}
+	if err := r.updateAppliedStatus(context.Background(), current, applied, nil); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+
+	current = getAddon()
+	condition = current.Status.Conditions[kubermaticv1.AddonResourcesApplied]
+	if condition.Status != corev1.ConditionTrue || condition.Reason != "" || condition.Message != "" {
+		t.Errorf("expected a true condition without message, got %+v", condition)
+	}
+
+	// an unchanged status is not patched, as that would trigger another reconciliation
+	resourceVersion := current.ResourceVersion
+	if err := r.updateAppliedStatus(context.Background(), current, applied, nil); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+	if current = getAddon(); current.ResourceVersion != resourceVersion {
+		t.Errorf("expected the unchanged status not to be patched")
+	}
+}
+
+// applyRecordingClient records server-side apply patches, which the fake client does not support,
+// and fails applying or deleting the objects with the given names.
+type applyRecordingClient struct {
+	ctrlruntimeclient.Client
+
+	applied    []string
+	deleted    []string
+	failApply  sets.Set[string]
+	failDelete sets.Set[string]
+}
+
+func (c *applyRecordingClient) Patch(ctx context.Context, obj ctrlruntimeclient.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	patchOpts := &ctrlruntimeclient.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	if patchOpts.FieldManager != fieldManager || patchOpts.Force == nil || !*patchOpts.Force {
+		return fmt.Errorf("%s was not applied with forced ownership by %s", obj.GetName(), fieldManager)
+	}
+
+	if c.failApply.Has(obj.GetName()) {
+		return errors.New("apply failed")
+	}
+
+	c.applied = append(c.applied, obj.GetName())
+	return nil
+}
+
+func (c *applyRecordingClient) Delete(ctx context.Context, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.DeleteOption) error {
+	if c.failDelete.Has(obj.GetName()) {
+		return errors.New("delete failed")
+	}
+
+	c.deleted = append(c.deleted, obj.GetName())
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func genConfigMap(name string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+		},
+	}
+}
+
+func TestApplyObjects(t *testing.T) {
+	obsolete := kubermaticv1.AddonAppliedResource{Version: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "obsolete"}
+
+	tests := []struct {
+		name              string
+		failApply         sets.Set[string]
+		failDelete        sets.Set[string]
+		expectErr         bool
+		expectedApplied   []string
+		expectedDeleted   []string
+		expectedResources []kubermaticv1.AddonAppliedResource
+	}{
+		{
+			name:              "objects are applied and removed objects are pruned",
+			expectedApplied:   []string{"cm", "deploy"},
+			expectedDeleted:   []string{"obsolete"},
+			expectedResources: []kubermaticv1.AddonAppliedResource{testConfigMap, testDeployment},
+		},
+		{
+			name:              "nothing is pruned if applying fails",
+			failApply:         sets.New("deploy"),
+			expectErr:         true,
+			expectedApplied:   []string{"cm"},
+			expectedResources: []kubermaticv1.AddonAppliedResource{testConfigMap, obsolete},
+		},
+		{
+			name:              "objects that could not be pruned are kept",
+			failDelete:        sets.New("obsolete"),
+			expectErr:         true,
+			expectedApplied:   []string{"cm", "deploy"},
+			expectedResources: []kubermaticv1.AddonAppliedResource{testConfigMap, testDeployment, obsolete},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &applyRecordingClient{
+				Client: fakectrlruntimeclient.
+					NewClientBuilder().
+					WithScheme(scheme.Scheme).
+					WithObjects(genConfigMap("cm"), genConfigMap("obsolete")).
+					Build(),
+				failApply:  tt.failApply,
+				failDelete: tt.failDelete,
+			}
+
+			objects := []*unstructured.Unstructured{toObject(testConfigMap), toObject(testDeployment)}
+			previouslyApplied := []kubermaticv1.AddonAppliedResource{testConfigMap, obsolete}
+
+			resources, err := applyObjects(context.Background(), zap.NewNop().Sugar(), client, objects, previouslyApplied)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expected error: %v, got %v", tt.expectErr, err)
+			}
+
+			if !reflect.DeepEqual(client.applied, tt.expectedApplied) {
+				t.Errorf("expected applied objects %v, got %v", tt.expectedApplied, client.applied)
+			}
+			if !reflect.DeepEqual(client.deleted, tt.expectedDeleted) {
+				t.Errorf("expected deleted objects %v, got %v", tt.expectedDeleted, client.deleted)
+			}
+			if !reflect.DeepEqual(resources, tt.expectedResources) {
+				t.Errorf("expected resources %v, got %v", tt.expectedResources, resources)
+			}
+		})
+	}
+}
+
+func TestMigrateClientSideApply(t *testing.T) {
+	cm := genConfigMap("cm")
+	cm.Data = map[string]string{"key": "value"}
+	cm.ManagedFields = []metav1.ManagedFieldsEntry{
+		{
+			Manager:    "kubectl-client-side-apply",
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:key":{}}}`)},
+		},
+	}
+
+	client := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(cm).
+		Build()
+
+	ctx := context.Background()
+	if err := migrateClientSideApply(ctx, client, toObject(testConfigMap)); err != nil {
+		t.Fatalf("failed to migrate object: %v", err)
+	}
+
+	migrated := &corev1.ConfigMap{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(cm), migrated); err != nil {
+		t.Fatalf("failed to get object: %v", err)
+	}
+
+	managers := map[string]metav1.ManagedFieldsOperationType{}
+	for _, entry := range migrated.ManagedFields {
+		managers[entry.Manager] = entry.Operation
+	}
+
+	expected := map[string]metav1.ManagedFieldsOperationType{fieldManager: metav1.ManagedFieldsOperationApply}
+	if !reflect.DeepEqual(managers, expected) {
+		t.Errorf("expected managers %v, got %v", expected, managers)
+	}
+
+	// objects that do not exist yet have nothing to migrate
+	if err := migrateClientSideApply(ctx, client, toObject(testDeployment)); err != nil {
+		t.Errorf("expected missing object to be skipped, got %v", err)
+	}
+}
+
+func TestDeleteResources(t *testing.T) {
+	client := &applyRecordingClient{
+		Client: fakectrlruntimeclient.
+			NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(genConfigMap("cm"), genConfigMap("stuck")).
+			Build(),
+		failDelete: sets.New("stuck"),
+	}
+
+	stuck := kubermaticv1.AddonAppliedResource{Version: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "stuck"}
+	resources := []kubermaticv1.AddonAppliedResource{stuck, testConfigMap, testDeployment}
+
+	remaining, err := deleteResources(context.Background(), zap.NewNop().Sugar(), client, resources)
+	if err == nil {
+		t.Error("expected an error for the object that could not be deleted")
+	}
+
+	// objects are deleted in reverse order and objects that are already gone are skipped
+	if expected := []string{"deploy", "cm"}; !reflect.DeepEqual(client.deleted, expected) {
+		t.Errorf("expected deleted objects %v, got %v", expected, client.deleted)
+	}
+	if expected := []kubermaticv1.AddonAppliedResource{stuck}; !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected remaining resources %v, got %v", expected, remaining)
+	}
+
+	if err := client.Get(context.Background(), ctrlruntimeclient.ObjectKeyFromObject(genConfigMap("cm")), &corev1.ConfigMap{}); err == nil {
+		t.Error("expected cm to be deleted")
+	}
+}
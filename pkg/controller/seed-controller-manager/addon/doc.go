@@ -17,7 +17,7 @@ limitations under the License.
 /*
 Package addon contains a controller that applies addons based on a Addon CRD. It needs
 a folder per addon that contains all manifests, then adds a label to all objects and applies
-the addon via `kubectl apply --purge -l $added-label`, which result in all objects that
-do have the label but are not in the on-disk manifests being removed.
+them into the user cluster with server-side apply. The applied objects are recorded in the
+Addon status, so that objects which are not part of the on-disk manifests anymore are removed.
 */
 package addon
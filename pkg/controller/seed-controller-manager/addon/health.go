@@ -0,0 +1,272 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// unhealthyRecheckInterval is the interval in which the health of addons that are not healthy is checked.
+const unhealthyRecheckInterval = 30 * time.Second
+
+// probeTimeout is the time a single health probe may take before it is considered failed.
+const probeTimeout = 10 * time.Second
+
+// failingContainerReasons are the reasons of waiting containers that will not become ready without
+// intervention.
+var failingContainerReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// addonHealth is the result of the health check of an addon.
+type addonHealth struct {
+	// notReady lists the reasons why the addon is not healthy.
+	notReady []string
+	// failing lists the reasons why the addon is degraded.
+	failing []string
+}
+
+func (h *addonHealth) healthy() bool {
+	return len(h.notReady) == 0 && len(h.failing) == 0
+}
+
+// ensureHealthStatus checks the health of the addon and records it in the Healthy and Degraded
+// conditions. Addons that are not healthy are checked again after unhealthyRecheckInterval.
+func (r *Reconciler) ensureHealthStatus(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	health, err := r.checkHealth(ctx, addon, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check addon health: %w", err)
+	}
+
+	if err := r.updateHealthStatus(ctx, addon, health); err != nil {
+		return nil, fmt.Errorf("failed to update addon status: %w", err)
+	}
+
+	if !health.healthy() {
+		log.Debugw("Addon is not healthy", "reasons", append(health.failing, health.notReady...))
+		return &reconcile.Result{RequeueAfter: unhealthyRecheckInterval}, nil
+	}
+
+	return nil, nil
+}
+
+// checkHealth evaluates the readiness of the Deployments, DaemonSets and StatefulSets created by the
+// addon and the health probes declared in the matching AddonConfig.
+func (r *Reconciler) checkHealth(ctx context.Context, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (*addonHealth, error) {
+	userClusterClient, err := r.KubeconfigProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for usercluster: %w", err)
+	}
+
+	health := &addonHealth{}
+	if err := checkWorkloads(ctx, userClusterClient, ctrlruntimeclient.MatchingLabels(r.getAddonLabel(addon)), health); err != nil {
+		return nil, err
+	}
+
+	addonConfig := &kubermaticv1.AddonConfig{}
+	if err := r.Get(ctx, types.NamespacedName{Name: addon.Spec.Name}, addonConfig); err != nil {
+		// the AddonConfig CRD might not be installed on the seed yet
+		if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("failed to get AddonConfig: %w", err)
+		}
+		addonConfig = nil
+	}
+
+	if addonConfig != nil && len(addonConfig.Spec.HealthProbes) > 0 {
+		if err := r.checkProbes(ctx, cluster, addonConfig.Spec.HealthProbes, health); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(health.notReady)
+	sort.Strings(health.failing)
+
+	return health, nil
+}
+
+// checkWorkloads records the Deployments, DaemonSets and StatefulSets matching the selector that are
+// not ready. Workloads whose pods are failing, e.g. because they are crash-looping, are recorded as
+// failing.
+func checkWorkloads(ctx context.Context, userClusterClient ctrlruntimeclient.Client, selector ctrlruntimeclient.MatchingLabels, health *addonHealth) error {
+	deployments := &appsv1.DeploymentList{}
+	if err := userClusterClient.List(ctx, deployments, selector); err != nil {
+		return fmt.Errorf("failed to list Deployments: %w", err)
+	}
+	for _, deployment := range deployments.Items {
+		name := fmt.Sprintf("Deployment %s/%s", deployment.Namespace, deployment.Name)
+		replicas := pointer.Int32Deref(deployment.Spec.Replicas, 1)
+
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+				health.failing = append(health.failing, fmt.Sprintf("%s exceeded its progress deadline", name))
+			}
+		}
+		if deployment.Status.ReadyReplicas < replicas {
+			if err := checkWorkload(ctx, userClusterClient, name, deployment.Namespace, deployment.Spec.Selector, deployment.Status.ReadyReplicas, replicas, health); err != nil {
+				return err
+			}
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := userClusterClient.List(ctx, statefulSets, selector); err != nil {
+		return fmt.Errorf("failed to list StatefulSets: %w", err)
+	}
+	for _, statefulSet := range statefulSets.Items {
+		name := fmt.Sprintf("StatefulSet %s/%s", statefulSet.Namespace, statefulSet.Name)
+		replicas := pointer.Int32Deref(statefulSet.Spec.Replicas, 1)
+
+		if statefulSet.Status.ReadyReplicas < replicas {
+			if err := checkWorkload(ctx, userClusterClient, name, statefulSet.Namespace, statefulSet.Spec.Selector, statefulSet.Status.ReadyReplicas, replicas, health); err != nil {
+				return err
+			}
+		}
+	}
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := userClusterClient.List(ctx, daemonSets, selector); err != nil {
+		return fmt.Errorf("failed to list DaemonSets: %w", err)
+	}
+	for _, daemonSet := range daemonSets.Items {
+		name := fmt.Sprintf("DaemonSet %s/%s", daemonSet.Namespace, daemonSet.Name)
+
+		if daemonSet.Status.NumberReady < daemonSet.Status.DesiredNumberScheduled {
+			if err := checkWorkload(ctx, userClusterClient, name, daemonSet.Namespace, daemonSet.Spec.Selector, daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled, health); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkWorkload records a workload that is not ready. If any of its pods has a failing container,
+// the workload is recorded as failing.
+func checkWorkload(ctx context.Context, userClusterClient ctrlruntimeclient.Client, name, namespace string, selector *metav1.LabelSelector, ready, desired int32, health *addonHealth) error {
+	podSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector of %s: %w", name, err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := userClusterClient.List(ctx, pods, ctrlruntimeclient.InNamespace(namespace), ctrlruntimeclient.MatchingLabelsSelector{Selector: podSelector}); err != nil {
+		return fmt.Errorf("failed to list pods of %s: %w", name, err)
+	}
+
+	for i := range pods.Items {
+		if reason := failingContainerReason(&pods.Items[i]); reason != "" {
+			health.failing = append(health.failing, fmt.Sprintf("%s has failing pod %s: %s", name, pods.Items[i].Name, reason))
+			return nil
+		}
+	}
+
+	health.notReady = append(health.notReady, fmt.Sprintf("%s has %d/%d ready pods", name, ready, desired))
+	return nil
+}
+
+// failingContainerReason returns the reason of the first failing container of the pod, if any.
+func failingContainerReason(pod *corev1.Pod) string {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting != nil && failingContainerReasons[status.State.Waiting.Reason] {
+			return fmt.Sprintf("container %s is in %s", status.Name, status.State.Waiting.Reason)
+		}
+	}
+	return ""
+}
+
+// checkProbes sends the health probes through the API server proxy of the user cluster. Failing
+// probes of an addon whose workloads are ready are recorded as failing, as the workloads are not
+// going to become any more ready.
+func (r *Reconciler) checkProbes(ctx context.Context, cluster *kubermaticv1.Cluster, probes []kubermaticv1.AddonHealthProbe, health *addonHealth) error {
+	userClusterClient, err := r.KubeconfigProvider.GetK8sClient(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get client for usercluster: %w", err)
+	}
+
+	workloadsReady := health.healthy()
+	for _, probe := range probes {
+		scheme := probe.Scheme
+		if scheme == "" {
+			scheme = corev1.URISchemeHTTP
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		_, err := userClusterClient.CoreV1().Services(probe.Namespace).ProxyGet(strings.ToLower(string(scheme)), probe.Service, probe.Port.String(), probe.Path, nil).DoRaw(probeCtx)
+		cancel()
+		if err == nil {
+			continue
+		}
+
+		reason := fmt.Sprintf("health probe %s failed: %v", probe.Name, err)
+		if workloadsReady {
+			health.failing = append(health.failing, reason)
+		} else {
+			health.notReady = append(health.notReady, reason)
+		}
+	}
+
+	return nil
+}
+
+// updateHealthStatus records the addon health in the Healthy and Degraded conditions. The status is
+// only patched if it changed, as every patch triggers another reconciliation.
+func (r *Reconciler) updateHealthStatus(ctx context.Context, addon *kubermaticv1.Addon, health *addonHealth) error {
+	healthy, healthyReason, healthyMessage := corev1.ConditionTrue, "", ""
+	if !health.healthy() {
+		healthy, healthyReason, healthyMessage = corev1.ConditionFalse, "NotReady", strings.Join(append(health.failing, health.notReady...), "; ")
+	}
+
+	degraded, degradedReason, degradedMessage := corev1.ConditionFalse, "", ""
+	if len(health.failing) > 0 {
+		degraded, degradedReason, degradedMessage = corev1.ConditionTrue, "Failing", strings.Join(health.failing, "; ")
+	}
+
+	if !addonConditionChanged(addon, kubermaticv1.AddonHealthy, healthy, healthyReason, healthyMessage) &&
+		!addonConditionChanged(addon, kubermaticv1.AddonDegraded, degraded, degradedReason, degradedMessage) {
+		return nil
+	}
+
+	oldAddon := addon.DeepCopy()
+	setAddonCondition(addon, kubermaticv1.AddonHealthy, healthy, healthyReason, healthyMessage)
+	setAddonCondition(addon, kubermaticv1.AddonDegraded, degraded, degradedReason, degradedMessage)
+	return r.Client.Status().Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func genDeployment(name string, replicas, ready int32, addonName string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+			Labels:    map[string]string{addonLabelKey: addonName},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas: ready,
+		},
+	}
+}
+
+func genDaemonSet(name string, desired, ready int32, addonName string) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+			Labels:    map[string]string{addonLabelKey: addonName},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: desired,
+			NumberReady:            ready,
+		},
+	}
+}
+
+func genPod(name, app, waitingReason string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+			Labels:    map[string]string{"app": app},
+		},
+	}
+	if waitingReason != "" {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  "main",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waitingReason}},
+		}}
+	}
+	return pod
+}
+
+func TestCheckWorkloads(t *testing.T) {
+	testCases := []struct {
+		name     string
+		objects  []ctrlruntimeclient.Object
+		expected addonHealth
+	}{
+		{
+			name: "all workloads are ready",
+			objects: []ctrlruntimeclient.Object{
+				genDeployment("controller", 2, 2, "cni"),
+				genDaemonSet("agent", 3, 3, "cni"),
+			},
+			expected: addonHealth{},
+		},
+		{
+			name: "workloads of other addons are ignored",
+			objects: []ctrlruntimeclient.Object{
+				genDeployment("controller", 2, 2, "cni"),
+				genDeployment("other", 1, 0, "csi"),
+			},
+			expected: addonHealth{},
+		},
+		{
+			name: "workloads that are starting are not ready",
+			objects: []ctrlruntimeclient.Object{
+				genDeployment("controller", 2, 1, "cni"),
+				genDaemonSet("agent", 3, 3, "cni"),
+				genPod("controller-1", "controller", "ContainerCreating"),
+			},
+			expected: addonHealth{
+				notReady: []string{"Deployment kube-system/controller has 1/2 ready pods"},
+			},
+		},
+		{
+			name: "crash-looping workloads are failing",
+			objects: []ctrlruntimeclient.Object{
+				genDeployment("controller", 2, 2, "cni"),
+				genDaemonSet("agent", 3, 2, "cni"),
+				genPod("agent-1", "agent", ""),
+				genPod("agent-2", "agent", "CrashLoopBackOff"),
+			},
+			expected: addonHealth{
+				failing: []string{"DaemonSet kube-system/agent has failing pod agent-2: container main is in CrashLoopBackOff"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(tc.objects...).
+				Build()
+
+			health := addonHealth{}
+			if err := checkWorkloads(context.Background(), client, ctrlruntimeclient.MatchingLabels{addonLabelKey: "cni"}, &health); err != nil {
+				t.Fatalf("failed to check workloads: %v", err)
+			}
+
+			if !reflect.DeepEqual(health, tc.expected) {
+				t.Errorf("expected %+v, got %+v", tc.expected, health)
+			}
+		})
+	}
+}
+
+func TestUpdateHealthStatus(t *testing.T) {
+	addon := setupTestAddon("test")
+	addon.Namespace = "cluster-test"
+	client := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(addon).
+		Build()
+	r := &Reconciler{Client: client}
+
+	testCases := []struct {
+		name             string
+		health           addonHealth
+		expectedHealthy  corev1.ConditionStatus
+		expectedDegraded corev1.ConditionStatus
+		expectedMessage  string
+	}{
+		{
+			name:             "addon is not ready yet",
+			health:           addonHealth{notReady: []string{"Deployment kube-system/controller has 0/1 ready pods"}},
+			expectedHealthy:  corev1.ConditionFalse,
+			expectedDegraded: corev1.ConditionFalse,
+			expectedMessage:  "Deployment kube-system/controller has 0/1 ready pods",
+		},
+		{
+			name:             "addon is failing",
+			health:           addonHealth{failing: []string{"health probe metrics failed: boom"}},
+			expectedHealthy:  corev1.ConditionFalse,
+			expectedDegraded: corev1.ConditionTrue,
+			expectedMessage:  "health probe metrics failed: boom",
+		},
+		{
+			name:             "addon is healthy",
+			health:           addonHealth{},
+			expectedHealthy:  corev1.ConditionTrue,
+			expectedDegraded: corev1.ConditionFalse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := &kubermaticv1.Addon{}
+			if err := client.Get(context.Background(), ctrlruntimeclient.ObjectKeyFromObject(addon), current); err != nil {
+				t.Fatalf("failed to get addon: %v", err)
+			}
+
+			if err := r.updateHealthStatus(context.Background(), current, &tc.health); err != nil {
+				t.Fatalf("failed to update status: %v", err)
+			}
+
+			if err := client.Get(context.Background(), ctrlruntimeclient.ObjectKeyFromObject(addon), current); err != nil {
+				t.Fatalf("failed to get addon: %v", err)
+			}
+
+			healthy := current.Status.Conditions[kubermaticv1.AddonHealthy]
+			if healthy.Status != tc.expectedHealthy || healthy.Message != tc.expectedMessage {
+				t.Errorf("expected Healthy condition %s with message %q, got %+v", tc.expectedHealthy, tc.expectedMessage, healthy)
+			}
+			if degraded := current.Status.Conditions[kubermaticv1.AddonDegraded]; degraded.Status != tc.expectedDegraded {
+				t.Errorf("expected Degraded condition %s, got %+v", tc.expectedDegraded, degraded)
+			}
+		})
+	}
+}
@@ -147,7 +147,15 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 
 	updateManager := version.NewFromConfiguration(config)
 
-	if err := r.controlPlaneUpgrade(ctx, log, cluster, updateManager); err != nil {
+	// clusters that are part of an UpgradePlan are upgraded in waves by the upgrade plan controller
+	managed, err := r.managedByUpgradePlan(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if managed {
+		log.Debug("Skipping automatic control-plane upgrade because the cluster is selected by an UpgradePlan")
+	} else if err := r.controlPlaneUpgrade(ctx, log, cluster, updateManager); err != nil {
 		return nil, fmt.Errorf("failed to update the controlplane: %w", err)
 	}
 
@@ -160,6 +168,32 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 	return nil, nil
 }
 
+// managedByUpgradePlan returns true if the cluster is selected by an UpgradePlan that is still rolling out.
+// Completed plans do not upgrade clusters anymore, so they must not keep the cluster from being auto-updated.
+func (r *Reconciler) managedByUpgradePlan(ctx context.Context, cluster *kubermaticv1.Cluster) (bool, error) {
+	plans := &kubermaticv1.UpgradePlanList{}
+	if err := r.List(ctx, plans); err != nil {
+		return false, fmt.Errorf("failed to list UpgradePlans: %w", err)
+	}
+
+	for _, plan := range plans.Items {
+		if plan.DeletionTimestamp != nil || plan.Status.Phase == kubermaticv1.UpgradePlanPhaseCompleted {
+			continue
+		}
+
+		selected, err := plan.SelectsCluster(cluster)
+		if err != nil {
+			return false, fmt.Errorf("failed to check UpgradePlan %s: %w", plan.Name, err)
+		}
+
+		if selected {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (r *Reconciler) nodeUpdate(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, updateManager *version.Manager) error {
 	c, err := r.userClusterConnectionProvider.GetClient(ctx, cluster)
 	if err != nil {
@@ -210,13 +244,24 @@ func (r *Reconciler) controlPlaneUpgrade(ctx context.Context, log *zap.SugaredLo
 	if update == nil {
 		return nil
 	}
-	oldCluster := cluster.DeepCopy()
-
 	sver, err := semver.NewSemver(update.Version.String())
 	if err != nil {
 		return fmt.Errorf("failed to parse version %q: %w", update.Version.String(), err)
 	}
 
+	// Do not automatically update clusters that still use APIs which are removed in the new
+	// version; the update controller would refuse to roll out the update anyway.
+	if removed := kubermaticv1helper.RemovedAPIsInUse(cluster, &cluster.Status.Versions.ControlPlane, sver); len(removed) > 0 && cluster.Annotations[kubermaticv1.SkipUpgradeCompatibilityCheckAnnotation] != "true" {
+		message := kubermaticv1helper.RemovedAPIsMessage(removed, sver)
+
+		log.Infow("Skipping automatic control-plane upgrade because removed APIs are still in use", "to", sver)
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, "AutoUpdateBlocked", "Automatic update to v%s is blocked: %s", sver, message)
+
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+
 	log.Infow("Applying automatic control-plane upgrade", "from", oldCluster.Spec.Version, "to", cluster.Spec.Version)
 
 	// Set the new target version; this in turn will trigger the incremental update controller
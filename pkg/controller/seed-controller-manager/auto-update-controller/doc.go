@@ -20,5 +20,7 @@ the cluster version and potentially to the MachineDeployments inside the userclu
 It will not itself reconcile any control plane components, this task is handled by
 other controllers that properly handle the version skew policy and are smart enough
 to update step-by-step.
+Clusters that are selected by an UpgradePlan do not receive automatic control plane
+updates, as these are rolled out by the upgrade plan controller instead.
 */
 package autoupdatecontroller
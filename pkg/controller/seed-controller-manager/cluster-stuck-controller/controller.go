@@ -37,6 +37,10 @@ import (
 
 const (
 	ControllerName = "kkp-cluster-stuck-controller"
+
+	// stuckFinalizerThreshold is the time after which a pending finalizer is
+	// considered to be stuck.
+	stuckFinalizerThreshold = 30 * time.Minute
 )
 
 type Reconciler struct {
@@ -112,6 +116,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		}
 	}
 
+	// cleanup steps that take much longer than usual
+	if cluster.Status.Deletion != nil {
+		for _, pending := range cluster.Status.Deletion.PendingFinalizers {
+			waiting := time.Since(pending.Since.Time)
+			if waiting > stuckFinalizerThreshold {
+				r.recorder.Eventf(cluster, corev1.EventTypeWarning, "DeletionStuck", "Finalizer %q has been pending for %s, waiting for %s.",
+					pending.Name, waiting.Round(time.Minute), pending.WaitingFor)
+			}
+		}
+	}
+
 	// renew the event to keep it visible
 	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
 }
@@ -19,7 +19,8 @@ Package clusterstuckcontroller contains a controller that is only useful
 during development. The controller will watch deleted Cluster objects and
 issue an event if the cluster still has a working name or is paused.
 Forgotten worker names are the most common reason of clusters seemingly
-stuck in deletion.
+stuck in deletion. It also warns about finalizers that have been pending
+for a long time, based on the deletion progress in the cluster status.
 
 This controller is not started by default, only when the dev environment
 feature flag is set in the KubermaticConfiguration.
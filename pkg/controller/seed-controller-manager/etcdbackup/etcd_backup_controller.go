@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -37,6 +38,8 @@ import (
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
 	"k8c.io/kubermatic/v2/pkg/resources/etcd"
+	"k8c.io/kubermatic/v2/pkg/util/backupencryption"
+	"k8c.io/kubermatic/v2/pkg/util/backupretention"
 	utilerrors "k8c.io/kubermatic/v2/pkg/util/errors"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 	"k8c.io/reconciler/pkg/reconciling"
@@ -49,6 +52,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
 	utilpointer "k8s.io/utils/pointer"
@@ -86,6 +90,12 @@ const (
 	backupScheduleEnvVarKey = "BACKUP_SCHEDULE"
 	// backupKeepCountEnvVarKey defines the environment variable key for the number of backups to keep.
 	backupKeepCountEnvVarKey = "BACKUP_KEEP_COUNT"
+	// backupKeepHourlyEnvVarKey, backupKeepDailyEnvVarKey, backupKeepWeeklyEnvVarKey and backupKeepMonthlyEnvVarKey
+	// define the environment variable keys for the tiers of the retention policy, if one is configured.
+	backupKeepHourlyEnvVarKey  = "BACKUP_KEEP_HOURLY"
+	backupKeepDailyEnvVarKey   = "BACKUP_KEEP_DAILY"
+	backupKeepWeeklyEnvVarKey  = "BACKUP_KEEP_WEEKLY"
+	backupKeepMonthlyEnvVarKey = "BACKUP_KEEP_MONTHLY"
 	// backupConfigEnvVarKey defines the environment variable key for the name of the backup configuration resource.
 	backupConfigEnvVarKey = "BACKUP_CONFIG"
 	// AccessKeyIdEnvVarKey defines the environment variable key for the backup credentials access key id.
@@ -99,6 +109,14 @@ const (
 	// backupInsecureEnvVarKey defines the environment variable key for a boolean that tells whether the
 	// configured endpoint uses HTTPS ("false") or HTTP ("true").
 	backupInsecureEnvVarKey = "INSECURE"
+	// encryptSnapshotCommand and decryptSnapshotCommand are the etcd-launcher commands that
+	// encrypt and decrypt a snapshot in place.
+	encryptSnapshotCommand = "encrypt-snapshot"
+	decryptSnapshotCommand = "decrypt-snapshot"
+	// encrypterContainerName is the name of the container encrypting new snapshots before they are uploaded.
+	encrypterContainerName = "backup-encrypter"
+	// decrypterContainerName is the name of the container decrypting downloaded snapshots.
+	decrypterContainerName = "backup-decrypter"
 
 	// requeueAfter time after starting a job
 	// should be the time after which a started job will usually have completed.
@@ -124,13 +142,17 @@ type Reconciler struct {
 	// backupContainerImage holds the image used for creating the etcd backup
 	// It must be configurable to cover offline use cases
 	backupContainerImage string
-	clock                clock.WithTickerAndDelayedExecution
-	randStringGenerator  func() string
-	caBundle             resources.CABundle
-	recorder             record.EventRecorder
-	versions             kubermatic.Versions
-	seedGetter           provider.SeedGetter
-	configGetter         provider.KubermaticConfigurationGetter
+	// etcdLauncherImage holds the image used for encrypting and decrypting backups
+	etcdLauncherImage   string
+	clock               clock.WithTickerAndDelayedExecution
+	randStringGenerator func() string
+	caBundle            resources.CABundle
+	recorder            record.EventRecorder
+	versions            kubermatic.Versions
+	seedGetter          provider.SeedGetter
+	configGetter        provider.KubermaticConfigurationGetter
+	// podLogs returns the logs of a container, the verifier reports the snapshot status on stdout
+	podLogs func(ctx context.Context, namespace, name, container string) (string, error)
 }
 
 // Add creates a new Backup controller that is responsible for
@@ -141,6 +163,7 @@ func Add(
 	numWorkers int,
 	workerName string,
 	backupContainerImage string,
+	etcdLauncherImage string,
 	versions kubermatic.Versions,
 	caBundle resources.CABundle,
 	seedGetter provider.SeedGetter,
@@ -153,12 +176,18 @@ func Add(
 		backupContainerImage = DefaultBackupContainerImage
 	}
 
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %w", err)
+	}
+
 	reconciler := &Reconciler{
 		Client:               client,
 		log:                  log,
 		scheme:               mgr.GetScheme(),
 		workerName:           workerName,
 		backupContainerImage: backupContainerImage,
+		etcdLauncherImage:    etcdLauncherImage,
 		recorder:             mgr.GetEventRecorderFor(ControllerName),
 		versions:             versions,
 		clock:                &clock.RealClock{},
@@ -166,6 +195,10 @@ func Add(
 		randStringGenerator: func() string {
 			return rand.String(10)
 		},
+		podLogs: func(ctx context.Context, namespace, name, container string) (string, error) {
+			logs, err := clientset.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{Container: container}).DoRaw(ctx)
+			return string(logs), err
+		},
 		seedGetter:   seedGetter,
 		configGetter: configGetter,
 	}
@@ -283,6 +316,9 @@ func (r *Reconciler) reconcile(
 	if destination.Credentials == nil {
 		return nil, fmt.Errorf("credentials not set for backup destination %q", backupConfig.Spec.Destination)
 	}
+	if err := r.validateEncryptionKey(ctx, destination); err != nil {
+		return nil, fmt.Errorf("invalid encryption key for backup destination %q: %w", backupConfig.Spec.Destination, err)
+	}
 
 	if err := r.ensureSecrets(ctx, cluster); err != nil {
 		return nil, fmt.Errorf("failed to create backup secrets: %w", err)
@@ -302,6 +338,11 @@ func (r *Reconciler) reconcile(
 		return nil, fmt.Errorf("failed to create backup delete container: %w", err)
 	}
 
+	backupVerifyContainer, err := getBackupVerifyContainer(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup verify container: %w", err)
+	}
+
 	var nextReconcile, totalReconcile *reconcile.Result
 	errorReconcile := &reconcile.Result{RequeueAfter: 1 * time.Minute}
 
@@ -317,6 +358,12 @@ func (r *Reconciler) reconcile(
 
 	totalReconcile = minReconcile(totalReconcile, nextReconcile)
 
+	if nextReconcile, err = r.updateBackupVerifications(ctx, log, backupConfig, cluster, destination, backupVerifyContainer); err != nil {
+		return errorReconcile, fmt.Errorf("failed to update backup verifications: %w", err)
+	}
+
+	totalReconcile = minReconcile(totalReconcile, nextReconcile)
+
 	if nextReconcile, err = r.startPendingBackupDeleteJobs(ctx, backupConfig, cluster, destination, backupDeleteContainer); err != nil {
 		return errorReconcile, fmt.Errorf("failed to start pending backup delete jobs: %w", err)
 	}
@@ -344,6 +391,32 @@ func (r *Reconciler) reconcile(
 	return totalReconcile, nil
 }
 
+// validateEncryptionKey ensures that the encryption key of the destination can be used by the
+// backup jobs, so that no snapshot is ever uploaded unencrypted or gets stuck because its job
+// can't mount the key. Jobs run in kube-system, so the key has to live there as well.
+func (r *Reconciler) validateEncryptionKey(ctx context.Context, destination *kubermaticv1.BackupDestination) error {
+	if destination.EncryptionKey == nil {
+		return nil
+	}
+
+	if destination.Credentials.Namespace != metav1.NamespaceSystem {
+		return fmt.Errorf("encryption key secret must be in namespace %q, but the credentials are in %q", metav1.NamespaceSystem, destination.Credentials.Namespace)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: destination.EncryptionKey.Name}, secret); err != nil {
+		return fmt.Errorf("failed to get encryption key secret: %w", err)
+	}
+
+	key, ok := secret.Data[destination.EncryptionKey.Key]
+	if !ok {
+		return fmt.Errorf("encryption key secret %q has no key %q", destination.EncryptionKey.Name, destination.EncryptionKey.Key)
+	}
+
+	_, err := backupencryption.ParseKey(key)
+	return err
+}
+
 func getBackupStoreContainer(cfg *kubermaticv1.KubermaticConfiguration, seed *kubermaticv1.Seed) (*corev1.Container, error) {
 	// a customized container is configured
 	if cfg.Spec.SeedController.BackupStoreContainer != "" {
@@ -466,6 +539,9 @@ func (r *Reconciler) ensurePendingBackupIsScheduled(ctx context.Context, backupC
 
 	backupToSchedule.JobName = r.limitNameLength(fmt.Sprintf("%s-backup-%s-create-%s", cluster.Name, backupConfig.Name, r.randStringGenerator()))
 	backupToSchedule.DeleteJobName = r.limitNameLength(fmt.Sprintf("%s-backup-%s-delete-%s", cluster.Name, backupConfig.Name, r.randStringGenerator()))
+	if backupConfig.IsVerificationEnabled() {
+		backupToSchedule.VerifyJobName = r.limitNameLength(fmt.Sprintf("%s-backup-%s-verify-%s", cluster.Name, backupConfig.Name, r.randStringGenerator()))
+	}
 
 	status := backupConfig.Status.DeepCopy()
 
@@ -591,7 +667,8 @@ func (r *Reconciler) startPendingBackupJobs(ctx context.Context, backupConfig *k
 	return returnReconcile, nil
 }
 
-// create any backup delete jobs that can be created, i.e. for all completed backups older than the last backupConfig.GetKeptBackupsCount() ones.
+// create any backup delete jobs that can be created, i.e. for all completed backups older than the last backupConfig.GetKeptBackupsCount() ones
+// or, if a retention policy is configured, for all completed backups that are not retained by any of its tiers.
 func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
 	destination *kubermaticv1.BackupDestination, deleteContainer *corev1.Container) (*reconcile.Result, error) {
 	// one-shot backups are not deleted until their backupConfig is deleted
@@ -599,15 +676,20 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupCon
 		return nil, nil
 	}
 
+	oldBackupConfig := backupConfig.DeepCopy()
+
 	var backupsToDelete []*kubermaticv1.BackupStatus
 	keepCount := backupConfig.GetKeptBackupsCount()
 	if backupConfig.DeletionTimestamp != nil {
 		keepCount = 0
 	}
+	useRetention := backupConfig.HasRetentionPolicy() && backupConfig.DeletionTimestamp == nil
+	retainedBy := retainedBackups(backupConfig)
 	kept := 0
 	runningDeleteJobsCount := 0
 	for i := len(backupConfig.Status.CurrentBackups) - 1; i >= 0; i-- {
 		backup := &backupConfig.Status.CurrentBackups[i]
+		backup.RetainedBy = nil
 		if backup.DeletePhase == kubermaticv1.BackupStatusPhaseRunning {
 			runningDeleteJobsCount++
 		}
@@ -615,14 +697,18 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupCon
 			backupsToDelete = append(backupsToDelete, backup)
 		} else if backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted {
 			kept++
-			if kept > keepCount && backup.DeletePhase == "" {
+			expired := kept > keepCount
+			if useRetention {
+				backup.RetainedBy = retainedBy[backup.BackupName]
+				expired = len(backup.RetainedBy) == 0
+			}
+			// backups are not deleted while they are being verified; they will be picked up once the verification finished
+			if expired && backup.DeletePhase == "" && backup.VerifyPhase != kubermaticv1.BackupStatusPhaseRunning {
 				backupsToDelete = append(backupsToDelete, backup)
 			}
 		}
 	}
 
-	oldBackupConfig := backupConfig.DeepCopy()
-
 	modified := false
 	for _, backup := range backupsToDelete {
 		if runningDeleteJobsCount < maxSimultaneousDeleteJobsPerConfig {
@@ -642,9 +728,44 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupCon
 		return &reconcile.Result{RequeueAfter: assumedJobRuntime}, nil
 	}
 
+	if !reflect.DeepEqual(oldBackupConfig.Status, backupConfig.Status) {
+		if err := r.Status().Patch(ctx, backupConfig, ctrlruntimeclient.MergeFrom(oldBackupConfig)); err != nil {
+			return nil, fmt.Errorf("failed to update backup status: %w", err)
+		}
+	}
+
 	return nil, nil
 }
 
+// retainedBackups applies the retention policy of the backupConfig to its completed backups
+// and returns the retaining tiers by backup name.
+func retainedBackups(backupConfig *kubermaticv1.EtcdBackupConfig) map[string][]kubermaticv1.EtcdBackupRetentionTier {
+	if !backupConfig.HasRetentionPolicy() {
+		return nil
+	}
+
+	var (
+		names []string
+		times []time.Time
+	)
+
+	for _, backup := range backupConfig.Status.CurrentBackups {
+		if backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted && backup.DeletePhase == "" {
+			names = append(names, backup.BackupName)
+			times = append(times, backup.ScheduledTime.Time)
+		}
+	}
+
+	result := map[string][]kubermaticv1.EtcdBackupRetentionTier{}
+	for i, tiers := range backupretention.Retain(*backupConfig.Spec.Retention, times) {
+		if len(tiers) > 0 {
+			result[names[i]] = tiers
+		}
+	}
+
+	return result
+}
+
 func (r *Reconciler) createBackupDeleteJob(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backup *kubermaticv1.BackupStatus,
 	destination *kubermaticv1.BackupDestination, deleteContainer *corev1.Container) error {
 	if deleteContainer != nil {
@@ -818,7 +939,44 @@ func (r *Reconciler) deleteFinishedBackupJobs(ctx context.Context, log *zap.Suga
 			}
 		}
 
-		if backupJobDeleted && deleteJobDeleted {
+		verifyJobDeleted := backup.VerifyPhase == ""
+		if !backup.VerifyFinishedTime.IsZero() {
+			var retentionTime time.Duration
+			switch {
+			case !backupConfig.DeletionTimestamp.IsZero():
+				retentionTime = 0
+			case backup.VerifyPhase == kubermaticv1.BackupStatusPhaseCompleted:
+				retentionTime = succeededJobRetentionTime
+			default:
+				retentionTime = failedJobRetentionTime
+			}
+
+			age := r.clock.Now().Sub(backup.VerifyFinishedTime.Time)
+
+			if age < retentionTime {
+				// don't delete the job yet, but reconcile when the time has come to delete it
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: retentionTime - age})
+			} else {
+				// delete job
+				job := &batchv1.Job{}
+
+				err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: backup.VerifyJobName}, job)
+				switch {
+				case apierrors.IsNotFound(err):
+					verifyJobDeleted = true
+				case err == nil:
+					err := r.Delete(ctx, job, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground))
+					if err != nil && !apierrors.IsNotFound(err) {
+						return nil, fmt.Errorf("backup %s: failed to delete verify job %s: %w", backup.BackupName, backup.VerifyJobName, err)
+					}
+					verifyJobDeleted = true
+				case !apierrors.IsNotFound(err):
+					return nil, fmt.Errorf("backup %s: failed to get verify job %s: %w", backup.BackupName, backup.VerifyJobName, err)
+				}
+			}
+		}
+
+		if backupJobDeleted && deleteJobDeleted && verifyJobDeleted {
 			// don't add backup to newBackups, which ends up deleting it from backupConfig.Status.CurrentBackups below
 			modified = true
 			continue
@@ -919,6 +1077,28 @@ func (r *Reconciler) backupJob(backupConfig *kubermaticv1.EtcdBackupConfig, clus
 			Value: backupConfig.Name,
 		})
 
+	if backupConfig.HasRetentionPolicy() {
+		retention := backupConfig.Spec.Retention
+		storeContainer.Env = append(
+			storeContainer.Env,
+			corev1.EnvVar{
+				Name:  backupKeepHourlyEnvVarKey,
+				Value: strconv.Itoa(retention.Hourly),
+			},
+			corev1.EnvVar{
+				Name:  backupKeepDailyEnvVarKey,
+				Value: strconv.Itoa(retention.Daily),
+			},
+			corev1.EnvVar{
+				Name:  backupKeepWeeklyEnvVarKey,
+				Value: strconv.Itoa(retention.Weekly),
+			},
+			corev1.EnvVar{
+				Name:  backupKeepMonthlyEnvVarKey,
+				Value: strconv.Itoa(retention.Monthly),
+			})
+	}
+
 	storeContainer.VolumeMounts = append(storeContainer.VolumeMounts, corev1.VolumeMount{
 		Name:      "ca-bundle",
 		MountPath: "/etc/ca-bundle/",
@@ -979,6 +1159,13 @@ func (r *Reconciler) backupJob(backupConfig *kubermaticv1.EtcdBackupConfig, clus
 		},
 	}
 
+	// Encrypt the snapshot before the store container gets to upload it. This happens regardless
+	// of the store container in use, so customized store containers upload encrypted snapshots
+	// as well. reconcile() always resolves a destination and validates its key beforehand.
+	if destination != nil && destination.EncryptionKey != nil {
+		job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, r.snapshotEncryptionContainer(encrypterContainerName, encryptSnapshotCommand, destination))
+	}
+
 	job.Spec.Template.Spec.Volumes = []corev1.Volume{
 		{
 			Name: SharedVolumeName,
@@ -1032,6 +1219,38 @@ func genSecretEnvVar(name, key string, destination *kubermaticv1.BackupDestinati
 	}
 }
 
+// snapshotEncryptionContainer returns a container that runs the given etcd-launcher
+// command to encrypt or decrypt the snapshot in the shared volume in place.
+func (r *Reconciler) snapshotEncryptionContainer(name, command string, destination *kubermaticv1.BackupDestination) corev1.Container {
+	image := r.etcdLauncherImage
+	if !strings.Contains(image, ":") {
+		image = image + ":" + r.versions.Kubermatic
+	}
+
+	return corev1.Container{
+		Name:    name,
+		Image:   image,
+		Command: []string{"/etcd-launcher", command, "/backup/snapshot.db"},
+		Env: []corev1.EnvVar{
+			{
+				Name: resources.EtcdRestoreEncryptionKeyKey,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: destination.EncryptionKey.LocalObjectReference,
+						Key:                  destination.EncryptionKey.Key,
+					},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      SharedVolumeName,
+				MountPath: "/backup",
+			},
+		},
+	}
+}
+
 func (r *Reconciler) backupDeleteJob(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backupStatus *kubermaticv1.BackupStatus,
 	destination *kubermaticv1.BackupDestination, deleteContainer *corev1.Container) *batchv1.Job {
 	deleteContainer = deleteContainer.DeepCopy()
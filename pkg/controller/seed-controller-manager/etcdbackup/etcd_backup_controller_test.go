@@ -36,6 +36,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/test/diff"
 	"k8c.io/kubermatic/v2/pkg/test/generator"
 	"k8c.io/kubermatic/v2/pkg/util/yaml"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -680,6 +681,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 		name              string
 		currentTime       time.Time
 		keep              int
+		retention         *kubermaticv1.EtcdBackupRetention
 		existingBackups   []kubermaticv1.BackupStatus
 		existingJobs      []batchv1.Job
 		expectedBackups   []kubermaticv1.BackupStatus
@@ -926,6 +928,68 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 				return *genBackupDeleteJob(t, fmt.Sprintf("testbackup-%v", i+1), fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i+1))
 			}),
 		},
+		{
+			name:        "retention policy deletes backups not retained by any tier",
+			currentTime: time.Unix(3700, 0).UTC(),
+			keep:        1,
+			retention:   &kubermaticv1.EtcdBackupRetention{Hourly: 2, Daily: 1},
+			existingBackups: genBackupStatusList(3, func(i int) kubermaticv1.BackupStatus {
+				return kubermaticv1.BackupStatus{
+					ScheduledTime: metav1.NewTime(time.Unix([]int64{60, 120, 3660}[i], 0).UTC()),
+					BackupName:    fmt.Sprintf("testbackup-%v.db", i),
+					BackupPhase:   kubermaticv1.BackupStatusPhaseCompleted,
+					DeleteJobName: fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+				}
+			}),
+			existingJobs: []batchv1.Job{},
+			expectedBackups: genBackupStatusList(3, func(i int) kubermaticv1.BackupStatus {
+				result := kubermaticv1.BackupStatus{
+					ScheduledTime: metav1.NewTime(time.Unix([]int64{60, 120, 3660}[i], 0).UTC()),
+					BackupName:    fmt.Sprintf("testbackup-%v.db", i),
+					BackupPhase:   kubermaticv1.BackupStatusPhaseCompleted,
+					DeleteJobName: fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+				}
+				switch i {
+				case 0:
+					result.DeletePhase = kubermaticv1.BackupStatusPhaseRunning
+				case 1:
+					result.RetainedBy = []kubermaticv1.EtcdBackupRetentionTier{kubermaticv1.EtcdBackupRetentionTierHourly}
+				case 2:
+					result.RetainedBy = []kubermaticv1.EtcdBackupRetentionTier{kubermaticv1.EtcdBackupRetentionTierHourly, kubermaticv1.EtcdBackupRetentionTierDaily}
+				}
+				return result
+			}),
+			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
+			expectedJobs: []batchv1.Job{
+				*genBackupDeleteJob(t, "testbackup-0", "testcluster-backup-testbackup-0-delete"),
+			},
+		},
+		{
+			name:        "retention policy records retaining tiers without deleting anything",
+			currentTime: time.Unix(3700, 0).UTC(),
+			keep:        1,
+			retention:   &kubermaticv1.EtcdBackupRetention{Hourly: 2},
+			existingBackups: genBackupStatusList(2, func(i int) kubermaticv1.BackupStatus {
+				return kubermaticv1.BackupStatus{
+					ScheduledTime: metav1.NewTime(time.Unix([]int64{60, 3660}[i], 0).UTC()),
+					BackupName:    fmt.Sprintf("testbackup-%v.db", i),
+					BackupPhase:   kubermaticv1.BackupStatusPhaseCompleted,
+					DeleteJobName: fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+				}
+			}),
+			existingJobs: []batchv1.Job{},
+			expectedBackups: genBackupStatusList(2, func(i int) kubermaticv1.BackupStatus {
+				return kubermaticv1.BackupStatus{
+					ScheduledTime: metav1.NewTime(time.Unix([]int64{60, 3660}[i], 0).UTC()),
+					BackupName:    fmt.Sprintf("testbackup-%v.db", i),
+					BackupPhase:   kubermaticv1.BackupStatusPhaseCompleted,
+					DeleteJobName: fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+					RetainedBy:    []kubermaticv1.EtcdBackupRetentionTier{kubermaticv1.EtcdBackupRetentionTierHourly},
+				}
+			}),
+			expectedReconcile: nil,
+			expectedJobs:      nil,
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -938,6 +1002,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 			backupConfig.SetCreationTimestamp(metav1.Time{Time: clock.Now()})
 			backupConfig.Spec.Schedule = "xxx" // must be non-empty
 			backupConfig.Spec.Keep = intPtr(tc.keep)
+			backupConfig.Spec.Retention = tc.retention
 			backupConfig.Status.CurrentBackups = tc.existingBackups
 
 			initObjs := []ctrlruntimeclient.Object{
@@ -1629,6 +1694,40 @@ func TestMultipleBackupDestination(t *testing.T) {
 			expectedJobEnvVars: []corev1.EnvVar{},
 			expectedErr:        fmt.Sprintf("cannot find backup destination %q", "missing"),
 		},
+		{
+			name: "test reconcile with encrypted backup destination",
+			backupConfig: func() *kubermaticv1.EtcdBackupConfig {
+				c := genBackupConfig(genTestCluster(), "testbackup")
+				c.Spec.Destination = "encrypted"
+				return c
+			}(),
+			expectedJobEnvVars: []corev1.EnvVar{
+				{
+					Name:  bucketNameEnvVarKey,
+					Value: "encrypted",
+				},
+			},
+		},
+		{
+			name: "backup should fail if encryption key is missing",
+			backupConfig: func() *kubermaticv1.EtcdBackupConfig {
+				c := genBackupConfig(genTestCluster(), "testbackup")
+				c.Spec.Destination = "missing-key"
+				return c
+			}(),
+			expectedJobEnvVars: []corev1.EnvVar{},
+			expectedErr:        fmt.Sprintf("invalid encryption key for backup destination %q: encryption key secret %q has no key %q", "missing-key", "backup-encryption-key", "missing"),
+		},
+		{
+			name: "backup should fail if encryption key is outside of kube-system",
+			backupConfig: func() *kubermaticv1.EtcdBackupConfig {
+				c := genBackupConfig(genTestCluster(), "testbackup")
+				c.Spec.Destination = "foreign-namespace"
+				return c
+			}(),
+			expectedJobEnvVars: []corev1.EnvVar{},
+			expectedErr:        fmt.Sprintf("invalid encryption key for backup destination %q: encryption key secret must be in namespace %q, but the credentials are in %q", "foreign-namespace", metav1.NamespaceSystem, "backups"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1637,6 +1736,15 @@ func TestMultipleBackupDestination(t *testing.T) {
 				genTestCluster(),
 				tc.backupConfig,
 				genClusterRootCaSecret(),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backup-encryption-key",
+						Namespace: metav1.NamespaceSystem,
+					},
+					Data: map[string][]byte{
+						"key": []byte("0123456789abcdef0123456789abcdef"),
+					},
+				},
 			}
 
 			storeContainer := genStoreContainer()
@@ -1697,6 +1805,24 @@ func addSeedDestinations(seed *kubermaticv1.Seed) {
 				BucketName: "no-cred",
 				Endpoint:   "no-cred.com",
 			},
+			"encrypted":         genEncryptedBackupDestination("encrypted", metav1.NamespaceSystem, "key"),
+			"missing-key":       genEncryptedBackupDestination("missing-key", metav1.NamespaceSystem, "missing"),
+			"foreign-namespace": genEncryptedBackupDestination("foreign-namespace", "backups", "key"),
+		},
+	}
+}
+
+func genEncryptedBackupDestination(bucketName, namespace, key string) *kubermaticv1.BackupDestination {
+	return &kubermaticv1.BackupDestination{
+		Endpoint:   "aws.s3.com",
+		BucketName: bucketName,
+		Credentials: &corev1.SecretReference{
+			Name:      "credentials-s3",
+			Namespace: namespace,
+		},
+		EncryptionKey: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "backup-encryption-key"},
+			Key:                  key,
 		},
 	}
 }
@@ -1745,3 +1871,71 @@ func TestIsInsecure(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotEncryptionContainers(t *testing.T) {
+	cluster := genTestCluster()
+	backupConfig := genBackupConfig(cluster, "testbackup")
+	backup := genCompletedBackup()
+
+	destination := genDefaultBackupDestination()
+	destination.EncryptionKey = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "backup-encryption-key"},
+		Key:                  "key",
+	}
+
+	reconciler := Reconciler{
+		backupContainerImage: DefaultBackupContainerImage,
+		etcdLauncherImage:    "quay.io/kubermatic/etcd-launcher",
+		versions:             kubermatic.Versions{Kubermatic: "v2.23.0"},
+	}
+
+	testCases := []struct {
+		name      string
+		job       *batchv1.Job
+		container string
+		command   string
+	}{
+		{
+			name:      "backup job encrypts the snapshot after creating it",
+			job:       reconciler.backupJob(backupConfig, cluster, &backup, destination, genStoreContainer()),
+			container: encrypterContainerName,
+			command:   encryptSnapshotCommand,
+		},
+		{
+			name:      "verify job decrypts the snapshot after downloading it",
+			job:       reconciler.backupVerifyJob(backupConfig, cluster, &backup, destination, genVerifyContainer()),
+			container: decrypterContainerName,
+			command:   decryptSnapshotCommand,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initContainers := tc.job.Spec.Template.Spec.InitContainers
+			if len(initContainers) < 2 {
+				t.Fatalf("expected at least 2 init containers, got %d", len(initContainers))
+			}
+
+			// the snapshot must be processed right after the download or before the upload
+			container := initContainers[1]
+			if container.Name != tc.container {
+				t.Fatalf("expected the second init container to be %q, got %q", tc.container, container.Name)
+			}
+			if expected := "quay.io/kubermatic/etcd-launcher:v2.23.0"; container.Image != expected {
+				t.Errorf("expected image %q, got %q", expected, container.Image)
+			}
+			if expected := []string{"/etcd-launcher", tc.command, "/backup/snapshot.db"}; !diff.DeepEqual(expected, container.Command) {
+				t.Errorf("unexpected command:\n%v", diff.ObjectDiff(expected, container.Command))
+			}
+			if len(container.Env) != 1 || container.Env[0].ValueFrom == nil || !diff.DeepEqual(destination.EncryptionKey, container.Env[0].ValueFrom.SecretKeyRef) {
+				t.Errorf("expected the encryption key to be read from %+v, got %+v", destination.EncryptionKey, container.Env)
+			}
+		})
+	}
+
+	// unencrypted destinations must not get any additional containers
+	job := reconciler.backupJob(backupConfig, cluster, &backup, genDefaultBackupDestination(), genStoreContainer())
+	if len(job.Spec.Template.Spec.InitContainers) != 1 {
+		t.Errorf("expected only the backup creator for unencrypted destinations, got %d init containers", len(job.Spec.Template.Spec.InitContainers))
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	backupVerificationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kubermatic",
+		Subsystem: "etcd_backup",
+		Name:      "verifications_total",
+		Help:      "The total number of finished etcd backup verifications",
+	}, []string{"cluster", "backup_config"})
+
+	backupVerificationFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kubermatic",
+		Subsystem: "etcd_backup",
+		Name:      "verification_failures_total",
+		Help:      "The number of etcd backup verifications that failed, i.e. backups that could not be downloaded or restored",
+	}, []string{"cluster", "backup_config"})
+)
+
+func MustRegisterMetrics(c prometheus.Registerer) {
+	c.MustRegister(backupVerificationsTotal)
+	c.MustRegister(backupVerificationFailuresTotal)
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/defaulting"
+	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/etcd"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// backupToVerifyEnvVarKey defines the environment variable key for the name of the backup to verify.
+	backupToVerifyEnvVarKey = "BACKUP_TO_VERIFY"
+	// restorerContainerName is the name of the init container restoring the downloaded snapshot.
+	restorerContainerName = "backup-restorer"
+	// verifierContainerName is the name of the container reporting the status of the downloaded snapshot.
+	verifierContainerName = "backup-verifier"
+	// scratchVolumeName is the name of the `emptyDir` volume the snapshot is restored into.
+	scratchVolumeName = "etcd-scratch"
+	// jobNameLabelKey is the label the job controller puts on all pods of a job.
+	jobNameLabelKey = "job-name"
+
+	// restoring a snapshot takes considerably longer than creating it, so verify jobs
+	// get more time than backup jobs.
+	verifyJobActiveDeadline = 10 * 60
+)
+
+// snapshotStatus is the output of `etcdutl snapshot status --write-out=json`.
+type snapshotStatus struct {
+	Hash      int64 `json:"hash"`
+	Revision  int64 `json:"revision"`
+	TotalKey  int64 `json:"totalKey"`
+	TotalSize int64 `json:"totalSize"`
+}
+
+func getBackupVerifyContainer(cfg *kubermaticv1.KubermaticConfiguration) (*corev1.Container, error) {
+	// a customized container is configured
+	if cfg.Spec.SeedController.BackupVerifyContainer != "" {
+		return kuberneteshelper.ContainerFromString(cfg.Spec.SeedController.BackupVerifyContainer)
+	}
+
+	return kuberneteshelper.ContainerFromString(defaulting.DefaultBackupVerifyContainer)
+}
+
+// start verify jobs for all completed backups that have not been verified yet (if verification is enabled)
+// and update the status of backups whose verify jobs have finished.
+func (r *Reconciler) updateBackupVerifications(ctx context.Context, log *zap.SugaredLogger, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster,
+	destination *kubermaticv1.BackupDestination, verifyContainer *corev1.Container) (*reconcile.Result, error) {
+	var returnReconcile *reconcile.Result
+
+	oldBackupConfig := backupConfig.DeepCopy()
+
+	// the verifications finished during this reconciliation, in the order of the backups
+	var finished []*kubermaticv1.BackupStatus
+
+	for i := range backupConfig.Status.CurrentBackups {
+		backup := &backupConfig.Status.CurrentBackups[i]
+
+		switch {
+		case backup.VerifyPhase == kubermaticv1.BackupStatusPhaseRunning:
+			job := &batchv1.Job{}
+			err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: backup.VerifyJobName}, job)
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, fmt.Errorf("error getting verify job for backup %s: %w", backup.BackupName, err)
+				}
+				// job not found. Apparently deleted externally.
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+				backup.VerifyMessage = "verify job deleted externally"
+				backup.VerifyFinishedTime = metav1.NewTime(r.clock.Now())
+				finished = append(finished, backup)
+				continue
+			}
+
+			if cond := getJobConditionIfTrue(job, batchv1.JobComplete); cond != nil {
+				backup.VerifyFinishedTime = cond.LastTransitionTime
+				if err := r.setSnapshotStatus(ctx, backup); err != nil {
+					backup.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+					backup.VerifyMessage = err.Error()
+				} else {
+					backup.VerifyPhase = kubermaticv1.BackupStatusPhaseCompleted
+					backup.VerifyMessage = "snapshot restored successfully"
+				}
+				finished = append(finished, backup)
+			} else if cond := getJobConditionIfTrue(job, batchv1.JobFailed); cond != nil {
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+				backup.VerifyMessage = cond.Message
+				backup.VerifyFinishedTime = cond.LastTransitionTime
+				// the output of the verifier is much more helpful than the job's "BackoffLimitExceeded"
+				if output, err := r.getVerifierOutput(ctx, backup.VerifyJobName); err == nil && output != "" {
+					backup.VerifyMessage = fmt.Sprintf("%s: %s", cond.Message, output)
+				}
+				finished = append(finished, backup)
+			} else {
+				// job still running
+				returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+			}
+
+		case backup.VerifyPhase == "" && backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted && backup.DeletePhase == "" &&
+			backupConfig.IsVerificationEnabled() && backupConfig.DeletionTimestamp == nil:
+			if backup.VerifyJobName == "" {
+				// verification was enabled after this backup has been scheduled
+				backup.VerifyJobName = r.limitNameLength(fmt.Sprintf("%s-backup-%s-verify-%s", cluster.Name, backupConfig.Name, r.randStringGenerator()))
+			}
+			job := r.backupVerifyJob(backupConfig, cluster, backup, destination, verifyContainer)
+			if err := r.Create(ctx, job); ctrlruntimeclient.IgnoreAlreadyExists(err) != nil {
+				return nil, fmt.Errorf("error creating verify job for backup %s: %w", backup.BackupName, err)
+			}
+			backup.VerifyPhase = kubermaticv1.BackupStatusPhaseRunning
+			returnReconcile = minReconcile(returnReconcile, &reconcile.Result{RequeueAfter: assumedJobRuntime})
+		}
+	}
+
+	for _, backup := range finished {
+		backupVerificationsTotal.WithLabelValues(cluster.Name, backupConfig.Name).Inc()
+
+		if backup.VerifyPhase == kubermaticv1.BackupStatusPhaseCompleted {
+			log.Debugw("Backup verified", "backup", backup.BackupName, "revision", backup.SnapshotRevision, "keys", backup.SnapshotKeyCount)
+			r.setBackupConfigCondition(backupConfig, kubermaticv1.EtcdBackupConfigConditionBackupVerified, corev1.ConditionTrue, "BackupVerified",
+				fmt.Sprintf("backup %s was restored successfully", backup.BackupName))
+			continue
+		}
+
+		backupVerificationFailuresTotal.WithLabelValues(cluster.Name, backupConfig.Name).Inc()
+		message := fmt.Sprintf("backup %s could not be verified: %s", backup.BackupName, backup.VerifyMessage)
+		log.Infow("Backup verification failed", "backup", backup.BackupName, "message", backup.VerifyMessage)
+		r.setBackupConfigCondition(backupConfig, kubermaticv1.EtcdBackupConfigConditionBackupVerified, corev1.ConditionFalse, "VerificationFailed", message)
+		r.recorder.Event(backupConfig, corev1.EventTypeWarning, "BackupVerificationFailed", message)
+	}
+
+	if !backupConfig.IsVerificationEnabled() && returnReconcile == nil {
+		// no verification running anymore, so the condition would only be stale
+		delete(backupConfig.Status.Conditions, kubermaticv1.EtcdBackupConfigConditionBackupVerified)
+	}
+
+	if !reflect.DeepEqual(oldBackupConfig.Status, backupConfig.Status) {
+		if err := r.Status().Patch(ctx, backupConfig, ctrlruntimeclient.MergeFrom(oldBackupConfig)); err != nil {
+			return nil, fmt.Errorf("failed to update backup status: %w", err)
+		}
+	}
+
+	return returnReconcile, nil
+}
+
+// setSnapshotStatus parses the snapshot status reported by a successful verify job into the backup status.
+func (r *Reconciler) setSnapshotStatus(ctx context.Context, backup *kubermaticv1.BackupStatus) error {
+	output, err := r.getSnapshotStatusOutput(ctx, backup.VerifyJobName)
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot status: %w", err)
+	}
+	if output == "" {
+		return fmt.Errorf("verify job did not report a snapshot status")
+	}
+
+	status := snapshotStatus{}
+	if err := json.Unmarshal([]byte(output), &status); err != nil {
+		return fmt.Errorf("failed to parse snapshot status: %w", err)
+	}
+
+	backup.SnapshotRevision = status.Revision
+	backup.SnapshotKeyCount = status.TotalKey
+	backup.SnapshotHash = status.Hash
+
+	return nil
+}
+
+// getSnapshotStatusOutput returns the logs of the verifier container of a successful pod of the given job.
+func (r *Reconciler) getSnapshotStatusOutput(ctx context.Context, jobName string) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem), ctrlruntimeclient.MatchingLabels{jobNameLabelKey: jobName}); err != nil {
+		return "", fmt.Errorf("failed to list pods of job %s: %w", jobName, err)
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == verifierContainerName && status.State.Terminated != nil && status.State.Terminated.ExitCode == 0 {
+				output, err := r.podLogs(ctx, pod.Namespace, pod.Name, verifierContainerName)
+				if err != nil {
+					return "", fmt.Errorf("failed to get logs of pod %s: %w", pod.Name, err)
+				}
+				return strings.TrimSpace(output), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// getVerifierOutput returns the termination message of the first failed restorer or verifier
+// container of the given job.
+func (r *Reconciler) getVerifierOutput(ctx context.Context, jobName string) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem), ctrlruntimeclient.MatchingLabels{jobNameLabelKey: jobName}); err != nil {
+		return "", fmt.Errorf("failed to list pods of job %s: %w", jobName, err)
+	}
+
+	for _, pod := range pods.Items {
+		var statuses []corev1.ContainerStatus
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+		for _, status := range statuses {
+			if status.Name != restorerContainerName && status.Name != verifierContainerName {
+				continue
+			}
+			for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated != nil && terminated.ExitCode != 0 {
+					return strings.TrimSpace(terminated.Message), nil
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func (r *Reconciler) backupVerifyJob(backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster, backupStatus *kubermaticv1.BackupStatus,
+	destination *kubermaticv1.BackupDestination, verifyContainer *corev1.Container) *batchv1.Job {
+	verifyContainer = verifyContainer.DeepCopy()
+
+	// If destination is set, we need to set the credentials and backup bucket details to match the destination
+	if destination != nil {
+		verifyContainer.Env = setEnvVar(verifyContainer.Env, genSecretEnvVar(AccessKeyIdEnvVarKey, AccessKeyIdEnvVarKey, destination))
+		verifyContainer.Env = setEnvVar(verifyContainer.Env, genSecretEnvVar(SecretAccessKeyEnvVarKey, SecretAccessKeyEnvVarKey, destination))
+		verifyContainer.Env = setEnvVar(verifyContainer.Env, corev1.EnvVar{
+			Name:  bucketNameEnvVarKey,
+			Value: destination.BucketName,
+		})
+		verifyContainer.Env = setEnvVar(verifyContainer.Env, corev1.EnvVar{
+			Name:  backupEndpointEnvVarKey,
+			Value: destination.Endpoint,
+		})
+
+		insecure := "false"
+		if isInsecureURL(destination.Endpoint) {
+			insecure = "true"
+		}
+
+		verifyContainer.Env = setEnvVar(verifyContainer.Env, corev1.EnvVar{
+			Name:  backupInsecureEnvVarKey,
+			Value: insecure,
+		})
+	}
+
+	verifyContainer.Env = append(
+		verifyContainer.Env,
+		corev1.EnvVar{
+			Name:  clusterEnvVarKey,
+			Value: cluster.Name,
+		},
+		corev1.EnvVar{
+			Name:  backupToVerifyEnvVarKey,
+			Value: backupStatus.BackupName,
+		},
+		corev1.EnvVar{
+			Name:  backupConfigEnvVarKey,
+			Value: backupConfig.Name,
+		})
+
+	verifyContainer.VolumeMounts = append(verifyContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      "ca-bundle",
+		MountPath: "/etc/ca-bundle/",
+		ReadOnly:  true,
+	})
+
+	job := r.jobBase(backupConfig, cluster, backupStatus.VerifyJobName)
+	job.Spec.ActiveDeadlineSeconds = resources.Int64(verifyJobActiveDeadline)
+	// etcdutl refuses to restore into an existing data directory, so every attempt
+	// needs a new pod with an empty scratch volume
+	job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+
+	image := r.backupContainerImage
+	if !strings.Contains(image, ":") {
+		image = image + ":" + etcd.ImageTag(cluster)
+	}
+
+	// the etcd image has no shell, so every etcdutl call needs its own container
+	restorer := corev1.Container{
+		Name:                     restorerContainerName,
+		Image:                    image,
+		Command:                  []string{"etcdutl", "snapshot", "restore", "/backup/snapshot.db", "--data-dir", "/scratch/data"},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      SharedVolumeName,
+				MountPath: "/backup",
+				ReadOnly:  true,
+			},
+			{
+				Name:      scratchVolumeName,
+				MountPath: "/scratch",
+			},
+		},
+	}
+
+	// the download container fetches the snapshot, the restorer restores it into the
+	// scratch volume and the verifier finally reports the snapshot status
+	job.Spec.Template.Spec.InitContainers = []corev1.Container{*verifyContainer}
+	if destination != nil && destination.EncryptionKey != nil {
+		job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, r.snapshotEncryptionContainer(decrypterContainerName, decryptSnapshotCommand, destination))
+	}
+	job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, restorer)
+
+	job.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name:                     verifierContainerName,
+			Image:                    image,
+			Command:                  []string{"etcdutl", "snapshot", "status", "/backup/snapshot.db", "--write-out=json"},
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      SharedVolumeName,
+					MountPath: "/backup",
+					ReadOnly:  true,
+				},
+			},
+		},
+	}
+
+	job.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{
+			Name: SharedVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: scratchVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: "ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: caBundleConfigMapName(cluster),
+					},
+				},
+			},
+		},
+	}
+
+	return job
+}
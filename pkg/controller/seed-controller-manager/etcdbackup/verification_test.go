@@ -0,0 +1,390 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/test/diff"
+	"k8c.io/kubermatic/v2/pkg/test/generator"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func genVerifyContainer() *corev1.Container {
+	return &corev1.Container{
+		Name:  "test-download-container",
+		Image: "some-s3cmd:latest",
+		Command: []string{
+			"/bin/sh",
+			"-c",
+			"s3cmd get ...",
+		},
+	}
+}
+
+func genCompletedBackup() kubermaticv1.BackupStatus {
+	return kubermaticv1.BackupStatus{
+		ScheduledTime:      metav1.NewTime(time.Unix(60, 0).UTC()),
+		BackupName:         "testbackup-1970-01-01t00-01-00.db",
+		JobName:            "testcluster-backup-testbackup-create-aaaa",
+		BackupFinishedTime: metav1.NewTime(time.Unix(90, 0).UTC()),
+		BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+		BackupMessage:      "job completed",
+		DeleteJobName:      "testcluster-backup-testbackup-delete-aaaa",
+		VerifyJobName:      "testcluster-backup-testbackup-verify-aaaa",
+	}
+}
+
+func genVerifyJob(jobName string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+}
+
+func genVerifierPod(jobName string, containerName string, exitCode int32, message string) *corev1.Pod {
+	status := corev1.ContainerStatus{
+		Name: containerName,
+		State: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: exitCode,
+				Message:  message,
+			},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName + "-abcde",
+			Namespace: metav1.NamespaceSystem,
+			Labels: map[string]string{
+				jobNameLabelKey: jobName,
+			},
+		},
+	}
+
+	if containerName == restorerContainerName {
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{status}
+	} else {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{status}
+	}
+
+	return pod
+}
+
+func TestUpdateBackupVerifications(t *testing.T) {
+	testCases := []struct {
+		name               string
+		verification       *kubermaticv1.EtcdBackupVerification
+		existingBackups    []kubermaticv1.BackupStatus
+		existingObjects    []ctrlruntimeclient.Object
+		verifierLogs       string
+		expectedBackups    []kubermaticv1.BackupStatus
+		expectedReconcile  *reconcile.Result
+		expectedJobs       []string
+		expectedCondition  *kubermaticv1.EtcdBackupConfigCondition
+		expectedFailures   float64
+		existingConditions map[kubermaticv1.EtcdBackupConfigConditionType]kubermaticv1.EtcdBackupConfigCondition
+	}{
+		{
+			name:            "completed backups are not verified if verification is disabled",
+			existingBackups: []kubermaticv1.BackupStatus{genCompletedBackup()},
+			expectedBackups: []kubermaticv1.BackupStatus{genCompletedBackup()},
+		},
+		{
+			name:            "verify job is started for a completed backup",
+			verification:    &kubermaticv1.EtcdBackupVerification{Enabled: true},
+			existingBackups: []kubermaticv1.BackupStatus{genCompletedBackup()},
+			expectedBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseRunning
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
+			expectedJobs:      []string{"testcluster-backup-testbackup-verify-aaaa"},
+		},
+		{
+			name:         "verify job name is generated if verification was enabled after the backup was scheduled",
+			verification: &kubermaticv1.EtcdBackupVerification{Enabled: true},
+			existingBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyJobName = ""
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			expectedBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyJobName = "testcluster-backup-testbackup-verify-bbbb"
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseRunning
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
+			expectedJobs:      []string{"testcluster-backup-testbackup-verify-bbbb"},
+		},
+		{
+			name:         "snapshot status is recorded when the verify job succeeded",
+			verification: &kubermaticv1.EtcdBackupVerification{Enabled: true},
+			existingBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseRunning
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			existingObjects: []ctrlruntimeclient.Object{
+				jobAddCondition(genVerifyJob("testcluster-backup-testbackup-verify-aaaa"), batchv1.JobComplete, corev1.ConditionTrue, time.Unix(120, 0).UTC(), "job completed"),
+				genVerifierPod("testcluster-backup-testbackup-verify-aaaa", verifierContainerName, 0, ""),
+			},
+			verifierLogs: `{"hash":3127475614,"revision":4242,"totalKey":1337,"totalSize":5193728}` + "\n",
+			expectedBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseCompleted
+				backup.VerifyMessage = "snapshot restored successfully"
+				backup.VerifyFinishedTime = metav1.NewTime(time.Unix(120, 0).UTC())
+				backup.SnapshotRevision = 4242
+				backup.SnapshotKeyCount = 1337
+				backup.SnapshotHash = 3127475614
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			expectedJobs: []string{"testcluster-backup-testbackup-verify-aaaa"},
+			expectedCondition: &kubermaticv1.EtcdBackupConfigCondition{
+				Status:  corev1.ConditionTrue,
+				Reason:  "BackupVerified",
+				Message: "backup testbackup-1970-01-01t00-01-00.db was restored successfully",
+			},
+		},
+		{
+			name:         "failed verification is recorded as condition and metric",
+			verification: &kubermaticv1.EtcdBackupVerification{Enabled: true},
+			existingBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseRunning
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			existingObjects: []ctrlruntimeclient.Object{
+				jobAddCondition(genVerifyJob("testcluster-backup-testbackup-verify-aaaa"), batchv1.JobFailed, corev1.ConditionTrue, time.Unix(120, 0).UTC(), "Job has reached the specified backoff limit"),
+				genVerifierPod("testcluster-backup-testbackup-verify-aaaa", verifierContainerName, 1, "Error: snapshot file integrity check failed"),
+			},
+			expectedBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+				backup.VerifyMessage = "Job has reached the specified backoff limit: Error: snapshot file integrity check failed"
+				backup.VerifyFinishedTime = metav1.NewTime(time.Unix(120, 0).UTC())
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			expectedJobs: []string{"testcluster-backup-testbackup-verify-aaaa"},
+			expectedCondition: &kubermaticv1.EtcdBackupConfigCondition{
+				Status:  corev1.ConditionFalse,
+				Reason:  "VerificationFailed",
+				Message: "backup testbackup-1970-01-01t00-01-00.db could not be verified: Job has reached the specified backoff limit: Error: snapshot file integrity check failed",
+			},
+			expectedFailures: 1,
+		},
+		{
+			name:         "failed restore is recorded as condition and metric",
+			verification: &kubermaticv1.EtcdBackupVerification{Enabled: true},
+			existingBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseRunning
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			existingObjects: []ctrlruntimeclient.Object{
+				jobAddCondition(genVerifyJob("testcluster-backup-testbackup-verify-aaaa"), batchv1.JobFailed, corev1.ConditionTrue, time.Unix(120, 0).UTC(), "Job has reached the specified backoff limit"),
+				genVerifierPod("testcluster-backup-testbackup-verify-aaaa", restorerContainerName, 1, "Error: snapshot missing hash but --skip-hash-check=false"),
+			},
+			expectedBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+				backup.VerifyMessage = "Job has reached the specified backoff limit: Error: snapshot missing hash but --skip-hash-check=false"
+				backup.VerifyFinishedTime = metav1.NewTime(time.Unix(120, 0).UTC())
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			expectedJobs: []string{"testcluster-backup-testbackup-verify-aaaa"},
+			expectedCondition: &kubermaticv1.EtcdBackupConfigCondition{
+				Status:  corev1.ConditionFalse,
+				Reason:  "VerificationFailed",
+				Message: "backup testbackup-1970-01-01t00-01-00.db could not be verified: Job has reached the specified backoff limit: Error: snapshot missing hash but --skip-hash-check=false",
+			},
+			expectedFailures: 1,
+		},
+		{
+			name:         "verification fails if the verify job is deleted externally",
+			verification: &kubermaticv1.EtcdBackupVerification{Enabled: true},
+			existingBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseRunning
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			expectedBackups: func() []kubermaticv1.BackupStatus {
+				backup := genCompletedBackup()
+				backup.VerifyPhase = kubermaticv1.BackupStatusPhaseFailed
+				backup.VerifyMessage = "verify job deleted externally"
+				backup.VerifyFinishedTime = metav1.NewTime(time.Unix(170, 0).UTC())
+				return []kubermaticv1.BackupStatus{backup}
+			}(),
+			expectedCondition: &kubermaticv1.EtcdBackupConfigCondition{
+				Status:  corev1.ConditionFalse,
+				Reason:  "VerificationFailed",
+				Message: "backup testbackup-1970-01-01t00-01-00.db could not be verified: verify job deleted externally",
+			},
+			expectedFailures: 1,
+		},
+		{
+			name:            "stale condition is removed when verification is disabled",
+			existingBackups: []kubermaticv1.BackupStatus{genCompletedBackup()},
+			existingConditions: map[kubermaticv1.EtcdBackupConfigConditionType]kubermaticv1.EtcdBackupConfigCondition{
+				kubermaticv1.EtcdBackupConfigConditionBackupVerified: {Status: corev1.ConditionFalse, Reason: "VerificationFailed"},
+			},
+			expectedBackups: []kubermaticv1.BackupStatus{genCompletedBackup()},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backupVerificationFailuresTotal.Reset()
+
+			cluster := genTestCluster()
+			backupConfig := genBackupConfig(cluster, "testbackup")
+			backupConfig.Spec.Verification = tc.verification
+
+			clock := clocktesting.NewFakeClock(time.Unix(170, 0).UTC())
+			backupConfig.SetCreationTimestamp(metav1.Time{Time: clock.Now()})
+			backupConfig.Status.CurrentBackups = tc.existingBackups
+			backupConfig.Status.Conditions = tc.existingConditions
+
+			initObjs := append([]ctrlruntimeclient.Object{cluster, backupConfig}, tc.existingObjects...)
+			verifyContainer := genVerifyContainer()
+			reconciler := Reconciler{
+				log:      kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+				Client:   ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(initObjs...).Build(),
+				scheme:   scheme.Scheme,
+				recorder: record.NewFakeRecorder(10),
+				clock:    clock,
+				seedGetter: func() (*kubermaticv1.Seed, error) {
+					return generator.GenTestSeed(), nil
+				},
+				randStringGenerator:  constRandStringGenerator("bbbb"),
+				backupContainerImage: DefaultBackupContainerImage,
+				podLogs: func(_ context.Context, _, _, _ string) (string, error) {
+					return tc.verifierLogs, nil
+				},
+			}
+
+			reconcileAfter, err := reconciler.updateBackupVerifications(context.Background(), reconciler.log, backupConfig, cluster, nil, verifyContainer)
+			if err != nil {
+				t.Fatalf("updateBackupVerifications returned an error: %v", err)
+			}
+
+			readbackBackupConfig := &kubermaticv1.EtcdBackupConfig{}
+			if err := reconciler.Get(context.Background(), ctrlruntimeclient.ObjectKeyFromObject(backupConfig), readbackBackupConfig); err != nil {
+				t.Fatalf("Error reading back completed backupConfig: %v", err)
+			}
+
+			if d := diff.ObjectDiff(tc.expectedBackups, readbackBackupConfig.Status.CurrentBackups); d != "" {
+				t.Errorf("backupsConfig status differs from expected one:\n%v", d)
+			}
+
+			jobs := &batchv1.JobList{}
+			if err := reconciler.List(context.Background(), jobs); err != nil {
+				t.Fatalf("Error listing jobs: %v", err)
+			}
+			var jobNames []string
+			for _, job := range jobs.Items {
+				jobNames = append(jobNames, job.Name)
+			}
+			if d := diff.ObjectDiff(tc.expectedJobs, jobNames); d != "" {
+				t.Errorf("jobs differ from expected ones:\n%v", d)
+			}
+
+			condition, found := readbackBackupConfig.Status.Conditions[kubermaticv1.EtcdBackupConfigConditionBackupVerified]
+			switch {
+			case tc.expectedCondition == nil && found:
+				t.Errorf("expected no %s condition, got %+v", kubermaticv1.EtcdBackupConfigConditionBackupVerified, condition)
+			case tc.expectedCondition != nil && !found:
+				t.Errorf("expected %s condition %+v, got none", kubermaticv1.EtcdBackupConfigConditionBackupVerified, tc.expectedCondition)
+			case tc.expectedCondition != nil:
+				if condition.Status != tc.expectedCondition.Status || condition.Reason != tc.expectedCondition.Reason || condition.Message != tc.expectedCondition.Message {
+					t.Errorf("expected %s condition %+v, got %+v", kubermaticv1.EtcdBackupConfigConditionBackupVerified, tc.expectedCondition, condition)
+				}
+			}
+
+			if failures := testutil.ToFloat64(backupVerificationFailuresTotal.WithLabelValues(cluster.Name, backupConfig.Name)); failures != tc.expectedFailures {
+				t.Errorf("expected %v verification failures in metric, got %v", tc.expectedFailures, failures)
+			}
+
+			if !diff.SemanticallyEqual(reconcileAfter, tc.expectedReconcile) {
+				t.Errorf("reconcile time differs from expected, expected: %v, actual: %v", tc.expectedReconcile, reconcileAfter)
+			}
+		})
+	}
+}
+
+func TestBackupVerifyJob(t *testing.T) {
+	cluster := genTestCluster()
+	backupConfig := genBackupConfig(cluster, "testbackup")
+	backup := genCompletedBackup()
+
+	reconciler := Reconciler{backupContainerImage: DefaultBackupContainerImage}
+	job := reconciler.backupVerifyJob(backupConfig, cluster, &backup, genDefaultBackupDestination(), genVerifyContainer())
+
+	if job.Name != backup.VerifyJobName {
+		t.Errorf("expected job name %q, got %q", backup.VerifyJobName, job.Name)
+	}
+
+	initContainers := job.Spec.Template.Spec.InitContainers
+	if len(initContainers) != 2 || initContainers[0].Name != "test-download-container" || initContainers[1].Name != restorerContainerName {
+		t.Fatalf("expected the download and restorer init containers, got %+v", initContainers)
+	}
+	if !containsEnvVar(initContainers[0].Env, corev1.EnvVar{Name: backupToVerifyEnvVarKey, Value: backup.BackupName}) {
+		t.Errorf("expected the download container to have %s=%s, got %+v", backupToVerifyEnvVarKey, backup.BackupName, initContainers[0].Env)
+	}
+	if !containsEnvVar(initContainers[0].Env, corev1.EnvVar{Name: bucketNameEnvVarKey, Value: genDefaultBackupDestination().BucketName}) {
+		t.Errorf("expected the download container to use the backup destination, got %+v", initContainers[0].Env)
+	}
+
+	containers := job.Spec.Template.Spec.Containers
+	if len(containers) != 1 || containers[0].Name != verifierContainerName {
+		t.Fatalf("expected the verifier as the only container, got %+v", containers)
+	}
+	if expected := DefaultBackupContainerImage + ":v3.5.6"; containers[0].Image != expected {
+		t.Errorf("expected verifier image %q, got %q", expected, containers[0].Image)
+	}
+
+	// the etcd image has no shell, etcdutl must be called directly
+	for _, container := range []corev1.Container{initContainers[1], containers[0]} {
+		if container.Command[0] != "etcdutl" {
+			t.Errorf("expected container %s to run etcdutl, got %v", container.Name, container.Command)
+		}
+	}
+
+	if policy := job.Spec.Template.Spec.RestartPolicy; policy != corev1.RestartPolicyNever {
+		t.Errorf("expected restart policy %q, got %q", corev1.RestartPolicyNever, policy)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cleanupClonedCluster removes everything from a freshly cloned user cluster that still refers
+// to the source cluster. The Machines and Nodes of the source cluster are removed without
+// involving the machine-controller, so that their instances are left untouched.
+func cleanupClonedCluster(ctx context.Context, log *zap.SugaredLogger, client ctrlruntimeclient.Client) error {
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := client.List(ctx, machineDeployments); err != nil {
+		return fmt.Errorf("failed to list MachineDeployments: %w", err)
+	}
+
+	for _, md := range machineDeployments.Items {
+		if md.Spec.Replicas != nil && *md.Spec.Replicas == 0 {
+			continue
+		}
+
+		log.Infow("Scaling down MachineDeployment", "machinedeployment", ctrlruntimeclient.ObjectKeyFromObject(&md))
+
+		oldMD := md.DeepCopy()
+		md.Spec.Replicas = pointer.Int32(0)
+		if err := client.Patch(ctx, &md, ctrlruntimeclient.MergeFrom(oldMD)); err != nil {
+			return fmt.Errorf("failed to scale down MachineDeployment %s: %w", md.Name, err)
+		}
+	}
+
+	machineSets := &clusterv1alpha1.MachineSetList{}
+	if err := client.List(ctx, machineSets); err != nil {
+		return fmt.Errorf("failed to list MachineSets: %w", err)
+	}
+
+	for _, ms := range machineSets.Items {
+		if err := forceDelete(ctx, client, &ms); err != nil {
+			return fmt.Errorf("failed to delete MachineSet %s: %w", ms.Name, err)
+		}
+	}
+
+	// the finalizers on Machines would make the machine-controller delete the instances
+	// of the source cluster, so they are removed before deleting the Machines
+	machines := &clusterv1alpha1.MachineList{}
+	if err := client.List(ctx, machines); err != nil {
+		return fmt.Errorf("failed to list Machines: %w", err)
+	}
+
+	for _, machine := range machines.Items {
+		if err := forceDelete(ctx, client, &machine); err != nil {
+			return fmt.Errorf("failed to delete Machine %s: %w", machine.Name, err)
+		}
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := client.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list Nodes: %w", err)
+	}
+
+	for _, node := range nodes.Items {
+		if err := forceDelete(ctx, client, &node); err != nil {
+			return fmt.Errorf("failed to delete Node %s: %w", node.Name, err)
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := client.List(ctx, secrets); err != nil {
+		return fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	for _, secret := range secrets.Items {
+		switch {
+		// provisioning data contains the CA and endpoint of the source cluster and is
+		// regenerated for the MachineDeployments of the clone
+		case secret.Namespace == resources.CloudInitSettingsNamespace:
+			if err := client.Delete(ctx, &secret); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+			}
+
+		// tokens signed by the source cluster are invalid in the clone; the token
+		// controller re-issues them once their data is gone
+		case secret.Type == corev1.SecretTypeServiceAccountToken && len(secret.Data) > 0:
+			oldSecret := secret.DeepCopy()
+			secret.Data = nil
+			if err := client.Patch(ctx, &secret, ctrlruntimeclient.MergeFrom(oldSecret)); err != nil {
+				return fmt.Errorf("failed to reset token Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// forceDelete removes all finalizers from obj and deletes it.
+func forceDelete(ctx context.Context, client ctrlruntimeclient.Client, obj ctrlruntimeclient.Object) error {
+	if len(obj.GetFinalizers()) > 0 {
+		oldObj := obj.DeepCopyObject().(ctrlruntimeclient.Object)
+		obj.SetFinalizers(nil)
+		if err := client.Patch(ctx, obj, ctrlruntimeclient.MergeFrom(oldObj)); err != nil {
+			return ctrlruntimeclient.IgnoreNotFound(err)
+		}
+	}
+
+	return ctrlruntimeclient.IgnoreNotFound(client.Delete(ctx, obj))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"context"
+	"testing"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	if err := clusterv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+}
+
+func TestCleanupClonedCluster(t *testing.T) {
+	finalizers := []string{"foregroundDeletion"}
+
+	client := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			&clusterv1alpha1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: metav1.NamespaceSystem},
+				Spec:       clusterv1alpha1.MachineDeploymentSpec{Replicas: pointer.Int32(3)},
+			},
+			&clusterv1alpha1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "workers-abc", Namespace: metav1.NamespaceSystem, Finalizers: finalizers},
+			},
+			&clusterv1alpha1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "workers-abc-xyz", Namespace: metav1.NamespaceSystem, Finalizers: finalizers},
+			},
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "workers-abc-xyz"},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "workers-osc-provisioning", Namespace: resources.CloudInitSettingsNamespace},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "legacy-token", Namespace: metav1.NamespaceDefault},
+				Type:       corev1.SecretTypeServiceAccountToken,
+				Data:       map[string][]byte{"token": []byte("signed-by-the-source-cluster")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: metav1.NamespaceDefault},
+				Data:       map[string][]byte{"config": []byte("keep me")},
+			},
+		).
+		Build()
+
+	ctx := context.Background()
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+
+	if err := cleanupClonedCluster(ctx, log, client); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+
+	md := &clusterv1alpha1.MachineDeployment{}
+	if err := client.Get(ctx, types.NamespacedName{Name: "workers", Namespace: metav1.NamespaceSystem}, md); err != nil {
+		t.Fatalf("failed to get MachineDeployment: %v", err)
+	}
+	if md.Spec.Replicas == nil || *md.Spec.Replicas != 0 {
+		t.Errorf("expected MachineDeployment to be scaled to zero, got %v replicas", md.Spec.Replicas)
+	}
+
+	for _, obj := range []ctrlruntimeclient.Object{
+		&clusterv1alpha1.MachineSet{ObjectMeta: metav1.ObjectMeta{Name: "workers-abc", Namespace: metav1.NamespaceSystem}},
+		&clusterv1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "workers-abc-xyz", Namespace: metav1.NamespaceSystem}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "workers-abc-xyz"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "workers-osc-provisioning", Namespace: resources.CloudInitSettingsNamespace}},
+	} {
+		if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), obj); err == nil {
+			t.Errorf("expected %T %s to be deleted", obj, obj.GetName())
+		}
+	}
+
+	token := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Name: "legacy-token", Namespace: metav1.NamespaceDefault}, token); err != nil {
+		t.Fatalf("failed to get token Secret: %v", err)
+	}
+	if len(token.Data) > 0 {
+		t.Errorf("expected token Secret to be reset, but it still has data: %v", token.Data)
+	}
+
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Name: "app-config", Namespace: metav1.NamespaceDefault}, secret); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if string(secret.Data["config"]) != "keep me" {
+		t.Errorf("expected unrelated Secret to be left untouched, got %v", secret.Data)
+	}
+}
@@ -31,6 +31,8 @@ import (
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/util/clusterclient"
+	etcdrestorevalidation "k8c.io/kubermatic/v2/pkg/validation/etcdrestore"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -62,14 +64,20 @@ const (
 	ActiveRestoreAnnotationName = "kubermatic.k8c.io/active-restore"
 )
 
+// UserClusterClientProvider provides functionality to get a user cluster client.
+type UserClusterClientProvider interface {
+	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
 // Reconciler stores necessary components that are required to restore etcd backups.
 type Reconciler struct {
 	log        *zap.SugaredLogger
 	workerName string
 	ctrlruntimeclient.Client
-	recorder   record.EventRecorder
-	versions   kubermatic.Versions
-	seedGetter provider.SeedGetter
+	recorder                      record.EventRecorder
+	versions                      kubermatic.Versions
+	seedGetter                    provider.SeedGetter
+	userClusterConnectionProvider UserClusterClientProvider
 }
 
 // Add creates a new etcd restore controller that is responsible for
@@ -81,6 +89,7 @@ func Add(
 	workerName string,
 	versions kubermatic.Versions,
 	seedGetter provider.SeedGetter,
+	userClusterConnectionProvider UserClusterClientProvider,
 ) error {
 	log = log.Named(ControllerName)
 	client := mgr.GetClient()
@@ -92,6 +101,8 @@ func Add(
 		recorder:   mgr.GetEventRecorderFor(ControllerName),
 		versions:   versions,
 		seedGetter: seedGetter,
+
+		userClusterConnectionProvider: userClusterConnectionProvider,
 	}
 
 	ctrlOptions := controller.Options{
@@ -180,6 +191,14 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 		return nil, nil
 	}
 
+	// the webhook validates the source cluster as well, but the clusters might
+	// have changed since the restore was created
+	if restore.IsClone() && restore.Status.Phase == "" {
+		if err := r.validateSourceCluster(ctx, restore, cluster); err != nil {
+			return nil, err
+		}
+	}
+
 	log.Infof("performing etcd restore from backup %v", restore.Spec.BackupName)
 
 	if restore.DeletionTimestamp == nil {
@@ -209,11 +228,18 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 		return nil, fmt.Errorf("failed to obtain S3 client: %w", err)
 	}
 
-	objectName := fmt.Sprintf("%s-%s", cluster.GetName(), restore.Spec.BackupName)
+	objectName := restore.BackupObjectName()
 	if _, err := s3Client.StatObject(ctx, bucketName, objectName, minio.StatObjectOptions{}); err != nil {
 		return nil, fmt.Errorf("could not access backup object %s: %w", objectName, err)
 	}
 
+	// make sure etcd-launcher will be able to decrypt the backup before tearing down etcd
+	if destination != nil && destination.EncryptionKey != nil {
+		if _, err := resources.GetEtcdRestoreEncryptionKey(ctx, restore, r.Client, cluster); err != nil {
+			return nil, fmt.Errorf("invalid backup encryption key: %w", err)
+		}
+	}
+
 	// before proceeding, ensure restore's namespace/name is stored in the ActiveRestoreAnnotationName cluster annotation
 	// unless some other restore is already stored there
 	thisRestore := fmt.Sprintf("%s/%s", restore.Namespace, restore.Name)
@@ -242,10 +268,21 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 	// pause cluster
 	if err := r.updateCluster(ctx, cluster, func(cluster *kubermaticv1.Cluster) {
 		cluster.Spec.Pause = true
+		if restore.IsClone() {
+			cluster.Annotations[kubermaticv1.CloneInProgressAnnotation] = restore.Spec.SourceCluster
+		}
 	}); err != nil {
 		return nil, fmt.Errorf("failed to pause cluster: %w", err)
 	}
 
+	// the cluster controller keeps the machine-controller scaled down once the cluster is
+	// unpaused, but it must already be stopped before the restored Machines become visible
+	if restore.IsClone() {
+		if err := r.stopMachineController(ctx, cluster); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
 		restore.Status.Phase = kubermaticv1.EtcdRestorePhaseStarted
 	}); err != nil {
@@ -327,8 +364,27 @@ func (r *Reconciler) rebuildEtcdStatefulset(ctx context.Context, log *zap.Sugare
 		return &reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	if _, cloning := cluster.Annotations[kubermaticv1.CloneInProgressAnnotation]; cloning {
+		// the cleanup happens through the API of the clone
+		if cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+			return &reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+
+		userClusterClient, err := r.userClusterConnectionProvider.GetClient(ctx, cluster)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user cluster client: %w", err)
+		}
+
+		log.Info("Cleaning up cloned cluster...")
+
+		if err := cleanupClonedCluster(ctx, log, userClusterClient); err != nil {
+			return nil, fmt.Errorf("failed to clean up cloned cluster: %w", err)
+		}
+	}
+
 	if err := r.updateCluster(ctx, cluster, func(cluster *kubermaticv1.Cluster) {
 		delete(cluster.Annotations, ActiveRestoreAnnotationName)
+		delete(cluster.Annotations, kubermaticv1.CloneInProgressAnnotation)
 	}); err != nil {
 		return nil, fmt.Errorf("failed to clear cluster active restore annotation: %w", err)
 	}
@@ -343,6 +399,43 @@ func (r *Reconciler) rebuildEtcdStatefulset(ctx context.Context, log *zap.Sugare
 	return nil, nil
 }
 
+// validateSourceCluster ensures that the backup of the source cluster may be
+// cloned into the given cluster.
+func (r *Reconciler) validateSourceCluster(ctx context.Context, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) error {
+	sourceCluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.SourceCluster}, sourceCluster); err != nil {
+		return fmt.Errorf("failed to get source cluster: %w", err)
+	}
+
+	if errs := etcdrestorevalidation.ValidateSourceCluster(cluster, sourceCluster); len(errs) > 0 {
+		return fmt.Errorf("cannot clone cluster %s: %w", sourceCluster.Name, errs.ToAggregate())
+	}
+
+	return nil
+}
+
+func (r *Reconciler) stopMachineController(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.MachineControllerDeploymentName}, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get machine-controller deployment: %w", err)
+	}
+
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
+		return nil
+	}
+
+	oldDeployment := deployment.DeepCopy()
+	deployment.Spec.Replicas = resources.Int32(0)
+	if err := r.Patch(ctx, deployment, ctrlruntimeclient.MergeFrom(oldDeployment)); err != nil {
+		return fmt.Errorf("failed to scale down machine-controller: %w", err)
+	}
+
+	return nil
+}
+
 func (r *Reconciler) updateCluster(ctx context.Context, cluster *kubermaticv1.Cluster, modify func(*kubermaticv1.Cluster)) error {
 	oldCluster := cluster.DeepCopy()
 	modify(cluster)
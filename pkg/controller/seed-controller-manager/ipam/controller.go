@@ -19,6 +19,7 @@ package ipam
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -29,6 +30,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -45,6 +47,14 @@ import (
 
 const (
 	ControllerName = "kkp-ipam-controller"
+
+	// ipamAllocationPoolKey indexes IPAM allocations by the IPAM pool they were made from,
+	// which is also their name.
+	ipamAllocationPoolKey = "ipamPool"
+
+	// quotaRecheckInterval is how often clusters are reconciled again while their project
+	// has used up its quota for an IPAM pool, as the quota might have been raised or freed up.
+	quotaRecheckInterval = 5 * time.Minute
 )
 
 // Reconciler stores all components required for the IPAM controller.
@@ -85,6 +95,10 @@ func Add(
 		return fmt.Errorf("failed to create controller: %w", err)
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &kubermaticv1.IPAMAllocation{}, ipamAllocationPoolKey, ipamAllocationPoolIndex); err != nil {
+		return fmt.Errorf("failed to add index on IPAM allocation pool: %w", err)
+	}
+
 	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Cluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return fmt.Errorf("failed to create watch for clusters: %w", err)
 	}
@@ -114,9 +128,32 @@ func Add(
 		return fmt.Errorf("failed to create watch for IPAM Pools: %w", err)
 	}
 
+	// Project labels are matched against the project selectors of IPAM pools
+	enqueueClustersForProject := handler.EnqueueRequestsFromMapFunc(func(a ctrlruntimeclient.Object) []reconcile.Request {
+		clusterList := &kubermaticv1.ClusterList{}
+		if err := mgr.GetClient().List(context.Background(), clusterList, ctrlruntimeclient.MatchingLabels{kubermaticv1.ProjectIDLabelKey: a.GetName()}); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to list Clusters: %w", err))
+			log.Errorw("Failed to list clusters", zap.Error(err))
+			return []reconcile.Request{}
+		}
+
+		requests := []reconcile.Request{}
+		for _, cluster := range clusterList.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: cluster.Name}})
+		}
+		return requests
+	})
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Project{}}, enqueueClustersForProject); err != nil {
+		return fmt.Errorf("failed to create watch for projects: %w", err)
+	}
+
 	return nil
 }
 
+func ipamAllocationPoolIndex(rawObj ctrlruntimeclient.Object) []string {
+	return []string{rawObj.GetName()}
+}
+
 func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	log := r.log.With("request", request)
 	log.Debug("Processing")
@@ -170,6 +207,8 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *kubermaticv1.Cluste
 		return nil, fmt.Errorf("failed to list IPAM pools: %w", err)
 	}
 
+	var result *reconcile.Result
+
 	// Loop IPAM pools, considering only the relevant ones (i.e. IPAM pools with same cluster datacenter)
 	for _, ipamPool := range ipamPoolList.Items {
 		clusterDC := cluster.Spec.Cloud.DatacenterName
@@ -185,8 +224,10 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *kubermaticv1.Cluste
 				if err := r.Delete(ctx, ipamAllocation); err != nil && !apierrors.IsNotFound(err) {
 					return nil, err
 				}
+			} else if err := r.updateClusterAllocationForPool(ctx, cluster, &ipamPool, dcIPAMPoolCfg, ipamAllocation); err != nil {
+				return nil, err
 			}
-			// Skip because no allocation from this IPAM pool is needed for the cluster
+			// Skip because no new allocation from this IPAM pool is needed for the cluster
 			continue
 		} else if !apierrors.IsNotFound(err) {
 			return nil, err
@@ -197,36 +238,59 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *kubermaticv1.Cluste
 			continue
 		}
 
-		dcIPAMPoolUsageMap, err := r.compileCurrentAllocationsForPoolInDatacenter(ctx, ipamPool.Name, clusterDC, dcIPAMPoolCfg)
+		isClusterSelected, err := r.poolSelectsCluster(ctx, &ipamPool, cluster)
 		if err != nil {
 			return nil, err
 		}
+		if !isClusterSelected {
+			// This IPAM pool is restricted to other projects, so skip it
+			continue
+		}
 
-		err = r.generateNewClusterAllocationForPool(ctx, cluster, &ipamPool, dcIPAMPoolCfg, dcIPAMPoolUsageMap)
+		quotaExhausted, err := r.projectQuotaExhausted(ctx, &ipamPool, cluster)
+		if err != nil {
+			return nil, err
+		}
+		if quotaExhausted {
+			// Retrying would not help until the quota changes, so allocate from the
+			// remaining pools and check again later
+			projectID := cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, "IPAMPoolQuotaExhausted", "Project %s has used up its quota of %d allocations for IPAM pool %q", projectID, *ipamPool.Spec.QuotaForProject(projectID), ipamPool.Name)
+			result = &reconcile.Result{RequeueAfter: quotaRecheckInterval}
+			continue
+		}
+
+		err = r.generateNewClusterAllocationForPool(ctx, cluster, &ipamPool, dcIPAMPoolCfg)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return nil, nil
+	return result, nil
 }
 
-func (r *Reconciler) compileCurrentAllocationsForPoolInDatacenter(ctx context.Context, ipamPoolName, dc string, dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings) (sets.Set[string], error) {
+// compileCurrentAllocationsForPoolInDatacenter returns the used IPs (for range allocation type) or
+// used subnets (for prefix allocation type) of a datacenter pool. For dual-stack pools, dualStack
+// selects the IP family of the second pool CIDR.
+func (r *Reconciler) compileCurrentAllocationsForPoolInDatacenter(ctx context.Context, ipamPoolName, dc string, dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings, dualStack bool) (sets.Set[string], error) {
 	dcIPAMPoolUsageMap := sets.New[string]()
 
-	// Check for exclusions in the configuration to mark them as "not free"
-	switch dcIPAMPoolCfg.Type {
-	case kubermaticv1.IPAMPoolAllocationTypeRange:
-		ipsToExclude, err := getIPsFromAddressRanges(dcIPAMPoolCfg.ExcludeRanges)
+	if dualStack {
+		dcIPAMPoolCfg = getDualStackSettings(dcIPAMPoolCfg)
+	}
+
+	// Check for exclusions in the configuration to mark them as "not free". Exclusions and reservations
+	// of range pools are not added here but skipped as whole ranges by findFirstFreeRangesOfPool.
+	if dcIPAMPoolCfg.Type == kubermaticv1.IPAMPoolAllocationTypePrefix {
+		for _, subnetCIDRToExclude := range dcIPAMPoolCfg.ExcludePrefixes {
+			dcIPAMPoolUsageMap.Insert(normalizeSubnetCIDR(string(subnetCIDRToExclude)))
+		}
+		reservedSubnetCIDRs, err := getReservedSubnets(dcIPAMPoolCfg.Reservations, string(dcIPAMPoolCfg.PoolCIDR))
 		if err != nil {
 			return nil, err
 		}
-		for _, ipToExclude := range ipsToExclude {
-			dcIPAMPoolUsageMap.Insert(ipToExclude)
-		}
-	case kubermaticv1.IPAMPoolAllocationTypePrefix:
-		for _, subnetCIDRToExclude := range dcIPAMPoolCfg.ExcludePrefixes {
-			dcIPAMPoolUsageMap.Insert(string(subnetCIDRToExclude))
+		for _, reservedSubnetCIDR := range reservedSubnetCIDRs {
+			dcIPAMPoolUsageMap.Insert(reservedSubnetCIDR)
 		}
 	}
 
@@ -245,9 +309,18 @@ func (r *Reconciler) compileCurrentAllocationsForPoolInDatacenter(ctx context.Co
 			continue
 		}
 
-		switch ipamAllocation.Spec.Type {
+		ipamAllocationSpec := ipamAllocation.Spec
+		if dualStack {
+			if ipamAllocationSpec.DualStack == nil {
+				// This allocation has been made before the pool became dual-stack
+				continue
+			}
+			ipamAllocationSpec = getDualStackAllocation(ipamAllocationSpec)
+		}
+
+		switch ipamAllocationSpec.Type {
 		case kubermaticv1.IPAMPoolAllocationTypeRange:
-			currentAllocatedIPs, err := getIPsFromAddressRanges(ipamAllocation.Spec.Addresses)
+			currentAllocatedIPs, err := getIPsFromAddressRanges(ipamAllocationSpec.Addresses)
 			if err != nil {
 				return nil, err
 			}
@@ -261,18 +334,53 @@ func (r *Reconciler) compileCurrentAllocationsForPoolInDatacenter(ctx context.Co
 			}
 		case kubermaticv1.IPAMPoolAllocationTypePrefix:
 			// check if the current allocation is compatible with the IPAMPool being applied
-			err := checkPrefixAllocation(string(ipamAllocation.Spec.CIDR), string(dcIPAMPoolCfg.PoolCIDR), dcIPAMPoolCfg.AllocationPrefix)
+			err := checkPrefixAllocation(string(ipamAllocationSpec.CIDR), string(dcIPAMPoolCfg.PoolCIDR), dcIPAMPoolCfg.AllocationPrefix)
 			if err != nil {
 				return nil, err
 			}
-			dcIPAMPoolUsageMap.Insert(string(ipamAllocation.Spec.CIDR))
+			dcIPAMPoolUsageMap.Insert(normalizeSubnetCIDR(string(ipamAllocationSpec.CIDR)))
 		}
 	}
 
 	return dcIPAMPoolUsageMap, nil
 }
 
-func (r *Reconciler) generateNewClusterAllocationForPool(ctx context.Context, cluster *kubermaticv1.Cluster, ipamPool *kubermaticv1.IPAMPool, dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings, dcIPAMPoolUsageMap sets.Set[string]) error {
+// allocateFromPool finds free addresses (for range allocation type) or a free subnet (for prefix
+// allocation type) in a datacenter pool. For dual-stack pools, dualStack selects the IP family of
+// the second pool CIDR.
+func (r *Reconciler) allocateFromPool(ctx context.Context, ipamPoolName, dc string, dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings, dualStack bool) (kubermaticv1.SubnetCIDR, []string, error) {
+	dcIPAMPoolUsageMap, err := r.compileCurrentAllocationsForPoolInDatacenter(ctx, ipamPoolName, dc, dcIPAMPoolCfg, dualStack)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if dualStack {
+		dcIPAMPoolCfg = getDualStackSettings(dcIPAMPoolCfg)
+	}
+
+	switch dcIPAMPoolCfg.Type {
+	case kubermaticv1.IPAMPoolAllocationTypeRange:
+		blockedRanges, err := getBlockedRanges(dcIPAMPoolCfg)
+		if err != nil {
+			return "", nil, err
+		}
+		addresses, err := findFirstFreeRangesOfPool(ipamPoolName, string(dcIPAMPoolCfg.PoolCIDR), dcIPAMPoolCfg.AllocationRange, dcIPAMPoolUsageMap, blockedRanges)
+		if err != nil {
+			return "", nil, err
+		}
+		return "", addresses, nil
+	case kubermaticv1.IPAMPoolAllocationTypePrefix:
+		subnetCIDR, err := findFirstFreeSubnetOfPool(ipamPoolName, string(dcIPAMPoolCfg.PoolCIDR), dcIPAMPoolCfg.AllocationPrefix, dcIPAMPoolUsageMap)
+		if err != nil {
+			return "", nil, err
+		}
+		return kubermaticv1.SubnetCIDR(subnetCIDR), nil, nil
+	}
+
+	return "", nil, nil
+}
+
+func (r *Reconciler) generateNewClusterAllocationForPool(ctx context.Context, cluster *kubermaticv1.Cluster, ipamPool *kubermaticv1.IPAMPool, dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings) error {
 	newClustersAllocation := &kubermaticv1.IPAMAllocation{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: cluster.Status.NamespaceName,
@@ -280,10 +388,14 @@ func (r *Reconciler) generateNewClusterAllocationForPool(ctx context.Context, cl
 			Labels:    map[string]string{},
 		},
 		Spec: kubermaticv1.IPAMAllocationSpec{
-			Type: dcIPAMPoolCfg.Type,
-			DC:   cluster.Spec.Cloud.DatacenterName,
+			Type:         dcIPAMPoolCfg.Type,
+			DC:           cluster.Spec.Cloud.DatacenterName,
+			Reservations: getClusterReservations(cluster, dcIPAMPoolCfg),
 		},
 	}
+	if projectID := cluster.Labels[kubermaticv1.ProjectIDLabelKey]; projectID != "" {
+		newClustersAllocation.Labels[kubermaticv1.ProjectIDLabelKey] = projectID
+	}
 	kuberneteshelper.EnsureUniqueOwnerReference(newClustersAllocation, metav1.OwnerReference{
 		APIVersion: kubermaticv1.SchemeGroupVersion.String(),
 		Kind:       kubermaticv1.IPAMPoolKindName,
@@ -291,25 +403,78 @@ func (r *Reconciler) generateNewClusterAllocationForPool(ctx context.Context, cl
 		Name:       ipamPool.Name,
 	})
 
-	switch dcIPAMPoolCfg.Type {
-	case kubermaticv1.IPAMPoolAllocationTypeRange:
-		addresses, err := findFirstFreeRangesOfPool(ipamPool.Name, string(dcIPAMPoolCfg.PoolCIDR), dcIPAMPoolCfg.AllocationRange, dcIPAMPoolUsageMap)
+	cidr, addresses, err := r.allocateFromPool(ctx, ipamPool.Name, newClustersAllocation.Spec.DC, dcIPAMPoolCfg, false)
+	if err != nil {
+		return err
+	}
+	newClustersAllocation.Spec.CIDR = cidr
+	newClustersAllocation.Spec.Addresses = addresses
+
+	if dcIPAMPoolCfg.DualStack != nil {
+		cidr, addresses, err := r.allocateFromPool(ctx, ipamPool.Name, newClustersAllocation.Spec.DC, dcIPAMPoolCfg, true)
 		if err != nil {
 			return err
 		}
-		newClustersAllocation.Spec.Addresses = addresses
-	case kubermaticv1.IPAMPoolAllocationTypePrefix:
-		subnetCIDR, err := findFirstFreeSubnetOfPool(ipamPool.Name, string(dcIPAMPoolCfg.PoolCIDR), dcIPAMPoolCfg.AllocationPrefix, dcIPAMPoolUsageMap)
-		if err != nil {
-			return err
+		newClustersAllocation.Spec.DualStack = &kubermaticv1.IPAMAllocationDualStack{
+			CIDR:      cidr,
+			Addresses: addresses,
 		}
-		newClustersAllocation.Spec.CIDR = kubermaticv1.SubnetCIDR(subnetCIDR)
 	}
 
-	err := r.Create(ctx, newClustersAllocation)
+	err = r.Create(ctx, newClustersAllocation)
 	if err != nil {
 		return fmt.Errorf("failed to create IPAM Pool Allocation for IPAM Pool %s in cluster %s: %w", ipamPool.Name, cluster.Name, err)
 	}
 
 	return nil
 }
+
+// updateClusterAllocationForPool keeps the reservations of an existing allocation in sync with the
+// reservations for the cluster in the IPAM pool and completes the allocation when the pool has
+// become dual-stack after the allocation was made.
+func (r *Reconciler) updateClusterAllocationForPool(ctx context.Context, cluster *kubermaticv1.Cluster, ipamPool *kubermaticv1.IPAMPool, dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings, ipamAllocation *kubermaticv1.IPAMAllocation) error {
+	oldIPAMAllocation := ipamAllocation.DeepCopy()
+
+	reservations := getClusterReservations(cluster, dcIPAMPoolCfg)
+	if !equality.Semantic.DeepEqual(ipamAllocation.Spec.Reservations, reservations) {
+		ipamAllocation.Spec.Reservations = reservations
+	}
+
+	if dcIPAMPoolCfg.DualStack != nil && ipamAllocation.Spec.DualStack == nil {
+		cidr, addresses, err := r.allocateFromPool(ctx, ipamPool.Name, ipamAllocation.Spec.DC, dcIPAMPoolCfg, true)
+		if err != nil {
+			return err
+		}
+		ipamAllocation.Spec.DualStack = &kubermaticv1.IPAMAllocationDualStack{
+			CIDR:      cidr,
+			Addresses: addresses,
+		}
+	}
+
+	if equality.Semantic.DeepEqual(oldIPAMAllocation, ipamAllocation) {
+		return nil
+	}
+
+	if err := r.Patch(ctx, ipamAllocation, ctrlruntimeclient.MergeFrom(oldIPAMAllocation)); err != nil {
+		return fmt.Errorf("failed to update IPAM Pool Allocation for IPAM Pool %s in cluster %s: %w", ipamPool.Name, cluster.Name, err)
+	}
+
+	return nil
+}
+
+// getClusterReservations returns the addresses reserved for the cluster, keyed by reservation name.
+func getClusterReservations(cluster *kubermaticv1.Cluster, dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings) map[string]string {
+	var reservations map[string]string
+
+	for _, reservation := range dcIPAMPoolCfg.Reservations {
+		if reservation.Cluster != cluster.Name {
+			continue
+		}
+		if reservations == nil {
+			reservations = map[string]string{}
+		}
+		reservations[reservation.Name] = reservation.Address
+	}
+
+	return reservations
+}
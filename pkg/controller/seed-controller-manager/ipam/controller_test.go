@@ -29,8 +29,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var testScheme = runtime.NewScheme()
@@ -53,12 +56,19 @@ func generateTestCluster(clusterName, dc string) *kubermaticv1.Cluster {
 	}
 }
 
+func generateTestProjectCluster(clusterName, dc, projectID string) *kubermaticv1.Cluster {
+	cluster := generateTestCluster(clusterName, dc)
+	cluster.Labels = map[string]string{kubermaticv1.ProjectIDLabelKey: projectID}
+	return cluster
+}
+
 func TestReconcileCluster(t *testing.T) {
 	testCases := []struct {
 		name                       string
 		objects                    []ctrlruntimeclient.Object
 		cluster                    *kubermaticv1.Cluster
 		expectedClusterAllocations *kubermaticv1.IPAMAllocationList
+		expectedResult             *reconcile.Result
 		expectedError              error
 	}{
 		{
@@ -1016,6 +1026,402 @@ func TestReconcileCluster(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "project selector: skip cluster of other project",
+			cluster: generateTestProjectCluster("test-cluster-1", "test-dc-1", "project-1"),
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.Project{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "project-1",
+						Labels: map[string]string{"network": "shared"},
+					},
+				},
+				&kubermaticv1.IPAMPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-pool-1",
+					},
+					Spec: kubermaticv1.IPAMPoolSpec{
+						Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+							"test-dc-1": {
+								Type:             "prefix",
+								PoolCIDR:         "192.168.0.0/16",
+								AllocationPrefix: 28,
+							},
+						},
+						ProjectSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"network": "dedicated"},
+						},
+					},
+				},
+			},
+			expectedClusterAllocations: &kubermaticv1.IPAMAllocationList{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "IPAMAllocationList",
+					APIVersion: "kubermatic.k8c.io/v1",
+				},
+			},
+		},
+		{
+			name:    "project selector: allocate for cluster of selected project",
+			cluster: generateTestProjectCluster("test-cluster-1", "test-dc-1", "project-1"),
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.Project{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "project-1",
+						Labels: map[string]string{"network": "dedicated"},
+					},
+				},
+				&kubermaticv1.IPAMPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-pool-1",
+					},
+					Spec: kubermaticv1.IPAMPoolSpec{
+						Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+							"test-dc-1": {
+								Type:             "prefix",
+								PoolCIDR:         "192.168.0.0/16",
+								AllocationPrefix: 28,
+							},
+						},
+						ProjectSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"network": "dedicated"},
+						},
+					},
+				},
+			},
+			expectedClusterAllocations: &kubermaticv1.IPAMAllocationList{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "IPAMAllocationList",
+					APIVersion: "kubermatic.k8c.io/v1",
+				},
+				Items: []kubermaticv1.IPAMAllocation{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "test-pool-1",
+							Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-1"),
+							ResourceVersion: "1",
+							Labels:          map[string]string{kubermaticv1.ProjectIDLabelKey: "project-1"},
+							OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-1"}},
+						},
+						Spec: kubermaticv1.IPAMAllocationSpec{
+							Type: kubermaticv1.IPAMPoolAllocationTypePrefix,
+							DC:   "test-dc-1",
+							CIDR: "192.168.0.0/28",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "project quota: skip pool with used up quota",
+			cluster: generateTestProjectCluster("test-cluster-2", "test-dc-1", "project-1"),
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.IPAMPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-pool-1",
+					},
+					Spec: kubermaticv1.IPAMPoolSpec{
+						Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+							"test-dc-1": {
+								Type:             "prefix",
+								PoolCIDR:         "192.168.0.0/16",
+								AllocationPrefix: 28,
+							},
+						},
+						ProjectQuota:  pointer.Int(5),
+						ProjectQuotas: map[string]int{"project-1": 1},
+					},
+				},
+				&kubermaticv1.IPAMPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-pool-2",
+					},
+					Spec: kubermaticv1.IPAMPoolSpec{
+						Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+							"test-dc-1": {
+								Type:             "prefix",
+								PoolCIDR:         "10.0.0.0/16",
+								AllocationPrefix: 28,
+							},
+						},
+					},
+				},
+				// allocation without project label, mapped to the project via its cluster
+				generateTestProjectCluster("test-cluster-1", "test-dc-1", "project-1"),
+				&kubermaticv1.IPAMAllocation{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "test-pool-1",
+						Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-1"),
+						ResourceVersion: "1",
+						OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-1"}},
+					},
+					Spec: kubermaticv1.IPAMAllocationSpec{
+						Type: kubermaticv1.IPAMPoolAllocationTypePrefix,
+						DC:   "test-dc-1",
+						CIDR: "192.168.0.0/28",
+					},
+				},
+			},
+			expectedClusterAllocations: &kubermaticv1.IPAMAllocationList{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "IPAMAllocationList",
+					APIVersion: "kubermatic.k8c.io/v1",
+				},
+				Items: []kubermaticv1.IPAMAllocation{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "test-pool-2",
+							Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-2"),
+							ResourceVersion: "1",
+							Labels:          map[string]string{kubermaticv1.ProjectIDLabelKey: "project-1"},
+							OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-2"}},
+						},
+						Spec: kubermaticv1.IPAMAllocationSpec{
+							Type: kubermaticv1.IPAMPoolAllocationTypePrefix,
+							DC:   "test-dc-1",
+							CIDR: "10.0.0.0/28",
+						},
+					},
+				},
+			},
+			expectedResult: &reconcile.Result{RequeueAfter: quotaRecheckInterval},
+		},
+		{
+			name:    "range: reservations",
+			cluster: generateTestCluster("test-cluster-1", "test-dc-1"),
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.IPAMPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-pool-1",
+					},
+					Spec: kubermaticv1.IPAMPoolSpec{
+						Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+							"test-dc-1": {
+								Type:            "range",
+								PoolCIDR:        "192.168.1.0/28",
+								AllocationRange: 4,
+								Reservations: []kubermaticv1.IPAMPoolReservation{
+									{Name: "gateway", Address: "192.168.1.0-192.168.1.1"},
+									{Name: "ingress-vip", Address: "192.168.1.3", Cluster: "test-cluster-1"},
+									{Name: "other-vip", Address: "192.168.1.4", Cluster: "test-cluster-2"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedClusterAllocations: &kubermaticv1.IPAMAllocationList{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "IPAMAllocationList",
+					APIVersion: "kubermatic.k8c.io/v1",
+				},
+				Items: []kubermaticv1.IPAMAllocation{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "test-pool-1",
+							Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-1"),
+							ResourceVersion: "1",
+							OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-1"}},
+						},
+						Spec: kubermaticv1.IPAMAllocationSpec{
+							Type:         kubermaticv1.IPAMPoolAllocationTypeRange,
+							DC:           "test-dc-1",
+							Addresses:    []string{"192.168.1.2-192.168.1.2", "192.168.1.5-192.168.1.7"},
+							Reservations: map[string]string{"ingress-vip": "192.168.1.3"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "prefix: reservation added for already allocated cluster",
+			cluster: generateTestCluster("test-cluster-1", "test-dc-1"),
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.IPAMPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-pool-1",
+					},
+					Spec: kubermaticv1.IPAMPoolSpec{
+						Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+							"test-dc-1": {
+								Type:             "prefix",
+								PoolCIDR:         "192.168.0.0/16",
+								AllocationPrefix: 28,
+								Reservations: []kubermaticv1.IPAMPoolReservation{
+									{Name: "storage", Address: "192.168.0.32/28", Cluster: "test-cluster-1"},
+								},
+							},
+						},
+					},
+				},
+				&kubermaticv1.IPAMAllocation{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "test-pool-1",
+						Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-1"),
+						ResourceVersion: "1",
+						OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-1"}},
+					},
+					Spec: kubermaticv1.IPAMAllocationSpec{
+						Type: kubermaticv1.IPAMPoolAllocationTypePrefix,
+						DC:   "test-dc-1",
+						CIDR: "192.168.0.0/28",
+					},
+				},
+			},
+			expectedClusterAllocations: &kubermaticv1.IPAMAllocationList{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "IPAMAllocationList",
+					APIVersion: "kubermatic.k8c.io/v1",
+				},
+				Items: []kubermaticv1.IPAMAllocation{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "test-pool-1",
+							Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-1"),
+							ResourceVersion: "2",
+							OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-1"}},
+						},
+						Spec: kubermaticv1.IPAMAllocationSpec{
+							Type:         kubermaticv1.IPAMPoolAllocationTypePrefix,
+							DC:           "test-dc-1",
+							CIDR:         "192.168.0.0/28",
+							Reservations: map[string]string{"storage": "192.168.0.32/28"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "dual-stack range: paired allocation",
+			cluster: generateTestCluster("test-cluster-2", "test-dc-1"),
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.IPAMPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-pool-1",
+					},
+					Spec: kubermaticv1.IPAMPoolSpec{
+						Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+							"test-dc-1": {
+								Type:            "range",
+								PoolCIDR:        "192.168.1.0/28",
+								AllocationRange: 4,
+								DualStack: &kubermaticv1.IPAMPoolDualStackSettings{
+									PoolCIDR:        "fd00::/120",
+									AllocationRange: 4,
+									ExcludeRanges:   []string{"FD00:0::0-fd00::1"},
+								},
+								Reservations: []kubermaticv1.IPAMPoolReservation{
+									{Name: "ipv4-vip", Address: "192.168.1.4"},
+									{Name: "ipv6-vip", Address: "fd00::4"},
+								},
+							},
+						},
+					},
+				},
+				&kubermaticv1.IPAMAllocation{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "test-pool-1",
+						Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-1"),
+						ResourceVersion: "1",
+						OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-1"}},
+					},
+					Spec: kubermaticv1.IPAMAllocationSpec{
+						Type:      kubermaticv1.IPAMPoolAllocationTypeRange,
+						DC:        "test-dc-1",
+						Addresses: []string{"192.168.1.0-192.168.1.3"},
+						DualStack: &kubermaticv1.IPAMAllocationDualStack{
+							Addresses: []string{"fd00::2-fd00::3", "fd00::5-fd00::6"},
+						},
+					},
+				},
+			},
+			expectedClusterAllocations: &kubermaticv1.IPAMAllocationList{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "IPAMAllocationList",
+					APIVersion: "kubermatic.k8c.io/v1",
+				},
+				Items: []kubermaticv1.IPAMAllocation{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "test-pool-1",
+							Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-2"),
+							ResourceVersion: "1",
+							OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-1"}},
+						},
+						Spec: kubermaticv1.IPAMAllocationSpec{
+							Type:      kubermaticv1.IPAMPoolAllocationTypeRange,
+							DC:        "test-dc-1",
+							Addresses: []string{"192.168.1.5-192.168.1.8"},
+							DualStack: &kubermaticv1.IPAMAllocationDualStack{
+								Addresses: []string{"fd00::7-fd00::a"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "dual-stack prefix: complete allocation made before the pool became dual-stack",
+			cluster: generateTestCluster("test-cluster-1", "test-dc-1"),
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.IPAMPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-pool-1",
+					},
+					Spec: kubermaticv1.IPAMPoolSpec{
+						Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+							"test-dc-1": {
+								Type:             "prefix",
+								PoolCIDR:         "192.168.0.0/16",
+								AllocationPrefix: 28,
+								DualStack: &kubermaticv1.IPAMPoolDualStackSettings{
+									PoolCIDR:         "fd00::/56",
+									AllocationPrefix: 64,
+									ExcludePrefixes:  []kubermaticv1.SubnetCIDR{"FD00:0::/64"},
+								},
+							},
+						},
+					},
+				},
+				&kubermaticv1.IPAMAllocation{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "test-pool-1",
+						Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-1"),
+						ResourceVersion: "1",
+						OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-1"}},
+					},
+					Spec: kubermaticv1.IPAMAllocationSpec{
+						Type: kubermaticv1.IPAMPoolAllocationTypePrefix,
+						DC:   "test-dc-1",
+						CIDR: "192.168.0.0/28",
+					},
+				},
+			},
+			expectedClusterAllocations: &kubermaticv1.IPAMAllocationList{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "IPAMAllocationList",
+					APIVersion: "kubermatic.k8c.io/v1",
+				},
+				Items: []kubermaticv1.IPAMAllocation{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "test-pool-1",
+							Namespace:       fmt.Sprintf("cluster-%s", "test-cluster-1"),
+							ResourceVersion: "2",
+							OwnerReferences: []metav1.OwnerReference{{APIVersion: "kubermatic.k8c.io/v1", Kind: "IPAMPool", Name: "test-pool-1"}},
+						},
+						Spec: kubermaticv1.IPAMAllocationSpec{
+							Type: kubermaticv1.IPAMPoolAllocationTypePrefix,
+							DC:   "test-dc-1",
+							CIDR: "192.168.0.0/28",
+							DualStack: &kubermaticv1.IPAMAllocationDualStack{
+								CIDR: "fd00:0:0:1::/64",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1026,11 +1432,14 @@ func TestReconcileCluster(t *testing.T) {
 					NewClientBuilder().
 					WithObjects(tc.objects...).
 					WithScheme(testScheme).
+					WithIndex(&kubermaticv1.IPAMAllocation{}, ipamAllocationPoolKey, ipamAllocationPoolIndex).
 					Build(),
+				recorder: record.NewFakeRecorder(10),
 			}
 
-			_, err := reconciler.reconcile(ctx, tc.cluster)
+			result, err := reconciler.reconcile(ctx, tc.cluster)
 			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedResult, result)
 
 			ipamAllocationList := &kubermaticv1.IPAMAllocationList{}
 			err = reconciler.List(ctx, ipamAllocationList, ctrlruntimeclient.InNamespace(tc.cluster.Status.NamespaceName))
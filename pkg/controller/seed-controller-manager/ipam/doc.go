@@ -18,5 +18,12 @@ limitations under the License.
 Package ipam contains a controller that is responsible for managing
 IPAM (Multi-Cluster IP Address Management) pools. It is in charge of the allocation of
 IP ranges or subnets from the defined pools for the user clusters.
+
+Pools can be restricted to projects via a project selector, limit the number of
+allocations per project and hold back named static addresses. The utilization
+of all pools is exposed as Prometheus metrics.
+
+Datacenter pools can be dual-stack, in which case every cluster gets a paired
+allocation from both the IPv4 and the IPv6 pool CIDR.
 */
 package ipam
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+// getDualStackSettings returns the settings for the second IP family of a dual-stack datacenter
+// pool in the same form as the settings of the first IP family, so that both can be allocated alike.
+func getDualStackSettings(dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings) kubermaticv1.IPAMPoolDatacenterSettings {
+	return kubermaticv1.IPAMPoolDatacenterSettings{
+		Type:             dcIPAMPoolCfg.Type,
+		PoolCIDR:         dcIPAMPoolCfg.DualStack.PoolCIDR,
+		AllocationPrefix: dcIPAMPoolCfg.DualStack.AllocationPrefix,
+		ExcludePrefixes:  dcIPAMPoolCfg.DualStack.ExcludePrefixes,
+		AllocationRange:  dcIPAMPoolCfg.DualStack.AllocationRange,
+		ExcludeRanges:    dcIPAMPoolCfg.DualStack.ExcludeRanges,
+		Reservations:     dcIPAMPoolCfg.Reservations,
+	}
+}
+
+// getDualStackAllocation returns the allocation for the second IP family of a dual-stack
+// allocation in the same form as the allocation of the first IP family.
+func getDualStackAllocation(ipamAllocationSpec kubermaticv1.IPAMAllocationSpec) kubermaticv1.IPAMAllocationSpec {
+	return kubermaticv1.IPAMAllocationSpec{
+		Type:      ipamAllocationSpec.Type,
+		DC:        ipamAllocationSpec.DC,
+		CIDR:      ipamAllocationSpec.DualStack.CIDR,
+		Addresses: ipamAllocationSpec.DualStack.Addresses,
+	}
+}
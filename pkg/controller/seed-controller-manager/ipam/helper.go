@@ -114,3 +114,13 @@ func isTheNextIP(ipToCheck string, previousIP string) bool {
 	nextIP := incIP(net.ParseIP(previousIP))
 	return nextIP.Equal(net.ParseIP(ipToCheck))
 }
+
+// getIPFamily returns "IPv4" or "IPv6" depending on the IP family of a CIDR.
+func getIPFamily(cidr string) string {
+	ip, _, err := net.ParseCIDR(cidr)
+	if err == nil && ip.To4() == nil {
+		return "IPv6"
+	}
+
+	return "IPv4"
+}
@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func TestAddressRange(t *testing.T) {
@@ -124,3 +126,113 @@ func TestIncIP(t *testing.T) {
 		})
 	}
 }
+
+func TestGetIPsFromAddressRanges(t *testing.T) {
+	testCases := []struct {
+		name          string
+		addressRanges []string
+		expectedIPs   []string
+		expectedError string
+	}{
+		{
+			name:          "IPv4 range and single IP",
+			addressRanges: []string{"192.168.1.254-192.168.2.1", "10.0.0.1"},
+			expectedIPs:   []string{"192.168.1.254", "192.168.1.255", "192.168.2.0", "192.168.2.1", "10.0.0.1"},
+		},
+		{
+			name:          "IPv6 range in non-canonical notation",
+			addressRanges: []string{"FD00:0:0::fffe-fd00::1:1"},
+			expectedIPs:   []string{"fd00::fffe", "fd00::ffff", "fd00::1:0", "fd00::1:1"},
+		},
+		{
+			name:          "reversed range",
+			addressRanges: []string{"fd00::10-fd00::1"},
+			expectedError: "wrong ip range order",
+		},
+		{
+			name:          "range with different IP versions",
+			addressRanges: []string{"192.168.1.1-fd00::1"},
+			expectedError: "different ip versions in range",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ips, err := getIPsFromAddressRanges(tc.addressRanges)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedIPs, ips)
+		})
+	}
+}
+
+func TestCalculateRangeFreeIPsFromDatacenterPool(t *testing.T) {
+	testCases := []struct {
+		name          string
+		poolCIDR      string
+		usedIPs       []string
+		blockedRanges []string
+		expectedIPs   []string
+	}{
+		{
+			name:          "IPv4 pool with used IPs and blocked ranges",
+			poolCIDR:      "192.168.1.0/29",
+			usedIPs:       []string{"192.168.1.1"},
+			blockedRanges: []string{"192.168.1.0", "192.168.1.3-192.168.1.5"},
+			expectedIPs:   []string{"192.168.1.2", "192.168.1.6", "192.168.1.7"},
+		},
+		{
+			name:          "IPv6 pool with a huge blocked range starting outside of the pool",
+			poolCIDR:      "fd00::/126",
+			blockedRanges: []string{"fc00::-fd00::1"},
+			expectedIPs:   []string{"fd00::2", "fd00::3"},
+		},
+		{
+			name:          "IPv6 pool completely blocked by a huge range",
+			poolCIDR:      "fd00::/126",
+			blockedRanges: []string{"fd00::-fdff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"},
+			expectedIPs:   []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			blockedRanges := []ipRange{}
+			for _, blockedRange := range tc.blockedRanges {
+				r, err := parseAddressRange(blockedRange)
+				assert.NoError(t, err)
+				blockedRanges = append(blockedRanges, r)
+			}
+
+			ips, err := calculateRangeFreeIPsFromDatacenterPool(tc.poolCIDR, sets.New(tc.usedIPs...), blockedRanges, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedIPs, ips)
+		})
+	}
+}
+
+func TestCountIPsInPool(t *testing.T) {
+	testCases := []struct {
+		addressRange  string
+		poolCIDR      string
+		expectedCount int
+	}{
+		{addressRange: "192.168.1.2-192.168.1.5", poolCIDR: "192.168.1.0/24", expectedCount: 4},
+		{addressRange: "192.168.0.250-192.168.1.1", poolCIDR: "192.168.1.0/24", expectedCount: 2},
+		{addressRange: "192.168.2.1", poolCIDR: "192.168.1.0/24", expectedCount: 0},
+		{addressRange: "fc00::-fe00::", poolCIDR: "fd00::/120", expectedCount: 256},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.addressRange, func(t *testing.T) {
+			r, err := parseAddressRange(tc.addressRange)
+			assert.NoError(t, err)
+			_, poolSubnet, err := net.ParseCIDR(tc.poolCIDR)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCount, countIPsInPool(r, poolSubnet))
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	metricsPrefix = "kubermatic_ipam_pool_"
+
+	usageAllocated = "allocated"
+	usageReserved  = "reserved"
+	usageExcluded  = "excluded"
+)
+
+// poolCollector exports the utilization of IPAM pools.
+type poolCollector struct {
+	client ctrlruntimeclient.Reader
+
+	capacity           *prometheus.Desc
+	used               *prometheus.Desc
+	projectAllocations *prometheus.Desc
+}
+
+// MustRegisterMetrics registers the IPAM pool collector at the given prometheus registry.
+func MustRegisterMetrics(registry prometheus.Registerer, client ctrlruntimeclient.Reader) {
+	registry.MustRegister(newPoolCollector(client))
+}
+
+func newPoolCollector(client ctrlruntimeclient.Reader) *poolCollector {
+	return &poolCollector{
+		client: client,
+		capacity: prometheus.NewDesc(
+			metricsPrefix+"capacity",
+			"The number of IPs (type=range) or subnets (type=prefix) of an IPAM pool in a datacenter",
+			[]string{"ipam_pool", "datacenter", "type", "ip_family"},
+			nil,
+		),
+		used: prometheus.NewDesc(
+			metricsPrefix+"used",
+			"The number of IPs (type=range) or subnets (type=prefix) of an IPAM pool in a datacenter that are allocated, reserved or excluded",
+			[]string{"ipam_pool", "datacenter", "type", "ip_family", "usage"},
+			nil,
+		),
+		projectAllocations: prometheus.NewDesc(
+			metricsPrefix+"project_allocations",
+			"The number of allocations from an IPAM pool per project",
+			[]string{"ipam_pool", "project"},
+			nil,
+		),
+	}
+}
+
+// Describe returns the metrics descriptors.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+// Collect gets called by prometheus to collect the metrics.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	ipamPoolList := &kubermaticv1.IPAMPoolList{}
+	if err := c.client.List(ctx, ipamPoolList); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list IPAM pools in IPAM pool collector: %w", err))
+		return
+	}
+
+	ipamAllocationList := &kubermaticv1.IPAMAllocationList{}
+	if err := c.client.List(ctx, ipamAllocationList); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list IPAM allocations in IPAM pool collector: %w", err))
+		return
+	}
+
+	clusterList := &kubermaticv1.ClusterList{}
+	if err := c.client.List(ctx, clusterList); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list clusters in IPAM pool collector: %w", err))
+		return
+	}
+
+	namespaceProjects := getNamespaceProjects(clusterList.Items)
+
+	for _, ipamPool := range ipamPoolList.Items {
+		for dc, dcIPAMPoolCfg := range ipamPool.Spec.Datacenters {
+			var dcAllocations []kubermaticv1.IPAMAllocation
+			for _, ipamAllocation := range ipamAllocationList.Items {
+				if ipamAllocation.Name == ipamPool.Name && ipamAllocation.Spec.DC == dc {
+					dcAllocations = append(dcAllocations, ipamAllocation)
+				}
+			}
+
+			c.collectPoolUsage(ch, ipamPool.Name, dc, dcIPAMPoolCfg, dcAllocations)
+
+			if dcIPAMPoolCfg.DualStack != nil {
+				var dualStackAllocations []kubermaticv1.IPAMAllocation
+				for _, ipamAllocation := range dcAllocations {
+					if ipamAllocation.Spec.DualStack != nil {
+						ipamAllocation.Spec = getDualStackAllocation(ipamAllocation.Spec)
+						dualStackAllocations = append(dualStackAllocations, ipamAllocation)
+					}
+				}
+
+				c.collectPoolUsage(ch, ipamPool.Name, dc, getDualStackSettings(dcIPAMPoolCfg), dualStackAllocations)
+			}
+		}
+
+		for projectID, count := range countProjectAllocations(ipamPool.Name, ipamAllocationList.Items, namespaceProjects) {
+			ch <- prometheus.MustNewConstMetric(c.projectAllocations, prometheus.GaugeValue, float64(count), ipamPool.Name, projectID)
+		}
+	}
+}
+
+func (c *poolCollector) collectPoolUsage(ch chan<- prometheus.Metric, ipamPoolName, dc string, dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings, dcAllocations []kubermaticv1.IPAMAllocation) {
+	capacity, usage, err := calculatePoolUsage(dcIPAMPoolCfg, dcAllocations)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to calculate usage of IPAM pool %s in datacenter %s: %w", ipamPoolName, dc, err))
+		return
+	}
+
+	allocationType := dcIPAMPoolCfg.Type.String()
+	ipFamily := getIPFamily(string(dcIPAMPoolCfg.PoolCIDR))
+
+	ch <- prometheus.MustNewConstMetric(c.capacity, prometheus.GaugeValue, capacity, ipamPoolName, dc, allocationType, ipFamily)
+	for _, usageType := range []string{usageAllocated, usageReserved, usageExcluded} {
+		ch <- prometheus.MustNewConstMetric(c.used, prometheus.GaugeValue, float64(usage[usageType]), ipamPoolName, dc, allocationType, ipFamily, usageType)
+	}
+}
+
+// calculatePoolUsage returns the capacity of a datacenter pool and how much of it is in use. The unit
+// is IPs for range pools and subnets for prefix pools.
+func calculatePoolUsage(dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings, dcAllocations []kubermaticv1.IPAMAllocation) (float64, map[string]int, error) {
+	_, poolSubnet, err := net.ParseCIDR(string(dcIPAMPoolCfg.PoolCIDR))
+	if err != nil {
+		return 0, nil, err
+	}
+	poolPrefix, bits := poolSubnet.Mask.Size()
+
+	usage := map[string]int{}
+
+	switch dcIPAMPoolCfg.Type {
+	case kubermaticv1.IPAMPoolAllocationTypeRange:
+		for _, ipamAllocation := range dcAllocations {
+			allocatedIPs, err := getIPsFromAddressRanges(ipamAllocation.Spec.Addresses)
+			if err != nil {
+				return 0, nil, err
+			}
+			usage[usageAllocated] += len(allocatedIPs)
+		}
+
+		// reservations and exclusions are counted without expanding them, as exclusions may be huge
+		for _, reservation := range dcIPAMPoolCfg.Reservations {
+			reservedRange, err := parseAddressRange(reservation.Address)
+			if err != nil {
+				return 0, nil, err
+			}
+			if poolSubnet.Contains(reservedRange.first) {
+				usage[usageReserved] += countIPsInPool(reservedRange, poolSubnet)
+			}
+		}
+
+		for _, excludeRange := range dcIPAMPoolCfg.ExcludeRanges {
+			excludedRange, err := parseAddressRange(excludeRange)
+			if err != nil {
+				return 0, nil, err
+			}
+			usage[usageExcluded] += countIPsInPool(excludedRange, poolSubnet)
+		}
+
+		return math.Ldexp(1, bits-poolPrefix), usage, nil
+
+	case kubermaticv1.IPAMPoolAllocationTypePrefix:
+		usage[usageAllocated] = len(dcAllocations)
+		reservedSubnetCIDRs, err := getReservedSubnets(dcIPAMPoolCfg.Reservations, string(dcIPAMPoolCfg.PoolCIDR))
+		if err != nil {
+			return 0, nil, err
+		}
+		usage[usageReserved] = len(reservedSubnetCIDRs)
+		usage[usageExcluded] = len(dcIPAMPoolCfg.ExcludePrefixes)
+
+		return math.Ldexp(1, dcIPAMPoolCfg.AllocationPrefix-poolPrefix), usage, nil
+	}
+
+	return 0, nil, fmt.Errorf("unknown allocation type %q", dcIPAMPoolCfg.Type)
+}
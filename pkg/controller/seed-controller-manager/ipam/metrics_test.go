@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPoolCollector(t *testing.T) {
+	client := ctrlruntimefakeclient.
+		NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(
+			&kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-pool-1",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"test-dc-1": {
+							Type:            "range",
+							PoolCIDR:        "192.168.1.0/28",
+							AllocationRange: 4,
+							ExcludeRanges:   []string{"192.168.1.0-192.168.1.1"},
+							Reservations: []kubermaticv1.IPAMPoolReservation{
+								{Name: "ingress-vip", Address: "192.168.1.2", Cluster: "test-cluster-1"},
+							},
+						},
+						"test-dc-2": {
+							Type:             "prefix",
+							PoolCIDR:         "10.0.0.0/16",
+							AllocationPrefix: 24,
+							DualStack: &kubermaticv1.IPAMPoolDualStackSettings{
+								PoolCIDR:         "fd00::/56",
+								AllocationPrefix: 64,
+								ExcludePrefixes:  []kubermaticv1.SubnetCIDR{"fd00::/64"},
+							},
+						},
+					},
+				},
+			},
+			generateTestProjectCluster("test-cluster-1", "test-dc-1", "project-1"),
+			&kubermaticv1.IPAMAllocation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-pool-1",
+					Namespace: "cluster-test-cluster-1",
+				},
+				Spec: kubermaticv1.IPAMAllocationSpec{
+					Type:      kubermaticv1.IPAMPoolAllocationTypeRange,
+					DC:        "test-dc-1",
+					Addresses: []string{"192.168.1.3-192.168.1.6"},
+				},
+			},
+			&kubermaticv1.IPAMAllocation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-pool-1",
+					Namespace: "cluster-test-cluster-2",
+					Labels:    map[string]string{kubermaticv1.ProjectIDLabelKey: "project-2"},
+				},
+				Spec: kubermaticv1.IPAMAllocationSpec{
+					Type: kubermaticv1.IPAMPoolAllocationTypePrefix,
+					DC:   "test-dc-2",
+					CIDR: "10.0.0.0/24",
+					DualStack: &kubermaticv1.IPAMAllocationDualStack{
+						CIDR: "fd00:0:0:1::/64",
+					},
+				},
+			},
+		).
+		Build()
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(newPoolCollector(client)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+# HELP kubermatic_ipam_pool_capacity The number of IPs (type=range) or subnets (type=prefix) of an IPAM pool in a datacenter
+# TYPE kubermatic_ipam_pool_capacity gauge
+kubermatic_ipam_pool_capacity{datacenter="test-dc-1",ip_family="IPv4",ipam_pool="test-pool-1",type="range"} 16
+kubermatic_ipam_pool_capacity{datacenter="test-dc-2",ip_family="IPv4",ipam_pool="test-pool-1",type="prefix"} 256
+kubermatic_ipam_pool_capacity{datacenter="test-dc-2",ip_family="IPv6",ipam_pool="test-pool-1",type="prefix"} 256
+# HELP kubermatic_ipam_pool_used The number of IPs (type=range) or subnets (type=prefix) of an IPAM pool in a datacenter that are allocated, reserved or excluded
+# TYPE kubermatic_ipam_pool_used gauge
+kubermatic_ipam_pool_used{datacenter="test-dc-1",ip_family="IPv4",ipam_pool="test-pool-1",type="range",usage="allocated"} 4
+kubermatic_ipam_pool_used{datacenter="test-dc-1",ip_family="IPv4",ipam_pool="test-pool-1",type="range",usage="excluded"} 2
+kubermatic_ipam_pool_used{datacenter="test-dc-1",ip_family="IPv4",ipam_pool="test-pool-1",type="range",usage="reserved"} 1
+kubermatic_ipam_pool_used{datacenter="test-dc-2",ip_family="IPv4",ipam_pool="test-pool-1",type="prefix",usage="allocated"} 1
+kubermatic_ipam_pool_used{datacenter="test-dc-2",ip_family="IPv4",ipam_pool="test-pool-1",type="prefix",usage="excluded"} 0
+kubermatic_ipam_pool_used{datacenter="test-dc-2",ip_family="IPv4",ipam_pool="test-pool-1",type="prefix",usage="reserved"} 0
+kubermatic_ipam_pool_used{datacenter="test-dc-2",ip_family="IPv6",ipam_pool="test-pool-1",type="prefix",usage="allocated"} 1
+kubermatic_ipam_pool_used{datacenter="test-dc-2",ip_family="IPv6",ipam_pool="test-pool-1",type="prefix",usage="excluded"} 1
+kubermatic_ipam_pool_used{datacenter="test-dc-2",ip_family="IPv6",ipam_pool="test-pool-1",type="prefix",usage="reserved"} 0
+# HELP kubermatic_ipam_pool_project_allocations The number of allocations from an IPAM pool per project
+# TYPE kubermatic_ipam_pool_project_allocations gauge
+kubermatic_ipam_pool_project_allocations{ipam_pool="test-pool-1",project="project-1"} 1
+kubermatic_ipam_pool_project_allocations{ipam_pool="test-pool-1",project="project-2"} 1
+`
+
+	if err := testutil.CollectAndCompare(registry, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
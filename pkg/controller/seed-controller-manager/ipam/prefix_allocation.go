@@ -21,9 +21,41 @@ import (
 	"fmt"
 	"net"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// getReservedSubnets returns all subnet CIDRs of the reservations of a prefix pool that belong to the
+// given pool CIDR. For dual-stack pools, this separates the reservations of both IP families.
+func getReservedSubnets(reservations []kubermaticv1.IPAMPoolReservation, poolCIDR string) ([]string, error) {
+	_, poolSubnet, err := net.ParseCIDR(poolCIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	subnetCIDRs := []string{}
+	for _, reservation := range reservations {
+		subnetIP, _, err := net.ParseCIDR(reservation.Address)
+		if err == nil && poolSubnet.Contains(subnetIP) {
+			subnetCIDRs = append(subnetCIDRs, normalizeSubnetCIDR(reservation.Address))
+		}
+	}
+
+	return subnetCIDRs, nil
+}
+
+// normalizeSubnetCIDR returns the canonical notation of a subnet CIDR, so that subnets
+// can be compared regardless of how they are written (e.g. "FD00:0::/64" and "fd00::/64").
+func normalizeSubnetCIDR(subnetCIDR string) string {
+	_, subnet, err := net.ParseCIDR(subnetCIDR)
+	if err != nil {
+		return subnetCIDR
+	}
+
+	return subnet.String()
+}
+
 func checkPrefixAllocation(subnetCIDR, poolCIDR string, allocationPrefix int) error {
 	subnetIP, subnet, err := net.ParseCIDR(subnetCIDR)
 	if err != nil {
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// poolSelectsCluster checks whether the project of the cluster matches the project selector of the IPAM pool.
+func (r *Reconciler) poolSelectsCluster(ctx context.Context, ipamPool *kubermaticv1.IPAMPool, cluster *kubermaticv1.Cluster) (bool, error) {
+	if ipamPool.Spec.ProjectSelector == nil {
+		return true, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(ipamPool.Spec.ProjectSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid project selector for IPAM pool %s: %w", ipamPool.Name, err)
+	}
+
+	projectID := cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+	if projectID == "" {
+		return false, nil
+	}
+
+	// projects are replicated from the master cluster, so a missing project is
+	// an error that is resolved by retrying later
+	project := &kubermaticv1.Project{}
+	if err := r.Get(ctx, types.NamespacedName{Name: projectID}, project); err != nil {
+		return false, fmt.Errorf("failed to get project %s: %w", projectID, err)
+	}
+
+	return selector.Matches(labels.Set(project.Labels)), nil
+}
+
+// projectQuotaExhausted returns true if the project of the cluster has already used up
+// its quota of allocations from the IPAM pool. Only the allocations of the pool and the
+// clusters of the project are read, both from the cache.
+func (r *Reconciler) projectQuotaExhausted(ctx context.Context, ipamPool *kubermaticv1.IPAMPool, cluster *kubermaticv1.Cluster) (bool, error) {
+	projectID := cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+	if projectID == "" {
+		return false, nil
+	}
+
+	quota := ipamPool.Spec.QuotaForProject(projectID)
+	if quota == nil {
+		return false, nil
+	}
+
+	ipamAllocationList := &kubermaticv1.IPAMAllocationList{}
+	if err := r.List(ctx, ipamAllocationList, ctrlruntimeclient.MatchingFields{ipamAllocationPoolKey: ipamPool.Name}); err != nil {
+		return false, fmt.Errorf("failed to list IPAM allocations: %w", err)
+	}
+
+	// the clusters are needed to map allocations without a project label to the project
+	clusterList := &kubermaticv1.ClusterList{}
+	if err := r.List(ctx, clusterList, ctrlruntimeclient.MatchingLabels{kubermaticv1.ProjectIDLabelKey: projectID}); err != nil {
+		return false, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	projectAllocations := countProjectAllocations(ipamPool.Name, ipamAllocationList.Items, getNamespaceProjects(clusterList.Items))
+
+	return projectAllocations[projectID] >= *quota, nil
+}
+
+// countProjectAllocationsForPool returns the number of allocations from the given IPAM pool per project.
+func countProjectAllocationsForPool(ctx context.Context, client ctrlruntimeclient.Reader, ipamPoolName string) (map[string]int, error) {
+	ipamAllocationList := &kubermaticv1.IPAMAllocationList{}
+	if err := client.List(ctx, ipamAllocationList); err != nil {
+		return nil, fmt.Errorf("failed to list IPAM allocations: %w", err)
+	}
+
+	clusterList := &kubermaticv1.ClusterList{}
+	if err := client.List(ctx, clusterList); err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	return countProjectAllocations(ipamPoolName, ipamAllocationList.Items, getNamespaceProjects(clusterList.Items)), nil
+}
+
+func countProjectAllocations(ipamPoolName string, ipamAllocations []kubermaticv1.IPAMAllocation, namespaceProjects map[string]string) map[string]int {
+	projectAllocations := map[string]int{}
+
+	for _, ipamAllocation := range ipamAllocations {
+		if ipamAllocation.Name != ipamPoolName {
+			continue
+		}
+
+		if projectID := getAllocationProject(&ipamAllocation, namespaceProjects); projectID != "" {
+			projectAllocations[projectID]++
+		}
+	}
+
+	return projectAllocations
+}
+
+// getNamespaceProjects maps the namespaces of the given clusters to their project IDs.
+func getNamespaceProjects(clusters []kubermaticv1.Cluster) map[string]string {
+	namespaceProjects := map[string]string{}
+
+	for _, cluster := range clusters {
+		if cluster.Status.NamespaceName != "" {
+			namespaceProjects[cluster.Status.NamespaceName] = cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+		}
+	}
+
+	return namespaceProjects
+}
+
+// getAllocationProject returns the project ID of an IPAM allocation. Allocations created
+// before the project label was introduced are mapped to a project via their cluster namespace.
+func getAllocationProject(ipamAllocation *kubermaticv1.IPAMAllocation, namespaceProjects map[string]string) string {
+	if projectID := ipamAllocation.Labels[kubermaticv1.ProjectIDLabelKey]; projectID != "" {
+		return projectID
+	}
+
+	return namespaceProjects[ipamAllocation.Namespace]
+}
@@ -17,43 +17,130 @@ limitations under the License.
 package ipam
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"math/big"
 	"net"
 	"strings"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// ipRange is an inclusive range of IPs, both stored in their 16-byte representation.
+type ipRange struct {
+	first net.IP
+	last  net.IP
+}
+
+func (r ipRange) contains(ip net.IP) bool {
+	ip = ip.To16()
+	return bytes.Compare(ip, r.first) >= 0 && bytes.Compare(ip, r.last) <= 0
+}
+
+// parseAddressRange parses an address range in the "{first_ip}-{last_ip}" or single "{ip}" format.
+func parseAddressRange(addressRange string) (ipRange, error) {
+	bounds := strings.SplitN(addressRange, "-", 2)
+	firstIP := net.ParseIP(bounds[0])
+	if firstIP == nil {
+		return ipRange{}, errors.New("wrong ip format")
+	}
+	if len(bounds) == 1 {
+		return ipRange{first: firstIP, last: firstIP}, nil
+	}
+
+	lastIP := net.ParseIP(bounds[1])
+	if lastIP == nil {
+		return ipRange{}, errors.New("wrong ip format")
+	}
+	if (firstIP.To4() == nil) != (lastIP.To4() == nil) {
+		return ipRange{}, errors.New("different ip versions in range")
+	}
+	if bytes.Compare(lastIP, firstIP) < 0 {
+		return ipRange{}, errors.New("wrong ip range order")
+	}
+
+	return ipRange{first: firstIP, last: lastIP}, nil
+}
+
+// getIPsFromAddressRanges returns all IPs of the address ranges. It must only be used for ranges of
+// a known, small size like allocations; exclusions and reservations are handled as ipRanges instead.
 func getIPsFromAddressRanges(addressRanges []string) ([]string, error) {
 	ips := []string{}
 
 	for _, addressRange := range addressRanges {
-		ipRange := strings.SplitN(addressRange, "-", 2)
-		if len(ipRange) != 2 && len(ipRange) != 1 {
-			return nil, errors.New("wrong ip range format")
+		r, err := parseAddressRange(addressRange)
+		if err != nil {
+			return nil, err
 		}
-		firstIP := net.ParseIP(ipRange[0])
-		if firstIP == nil {
-			return nil, errors.New("wrong ip format")
-		}
-		if len(ipRange) == 2 {
-			lastIP := net.ParseIP(ipRange[1])
-			if lastIP == nil {
-				return nil, errors.New("wrong ip format")
-			}
-			for ip := firstIP; !ip.Equal(lastIP); ip = incIP(ip) {
-				ips = append(ips, ip.String())
-			}
-			ips = append(ips, lastIP.String())
-		} else {
-			ips = append(ips, firstIP.String())
+		for ip := r.first; !ip.Equal(r.last); ip = incIP(ip) {
+			ips = append(ips, ip.String())
 		}
+		ips = append(ips, r.last.String())
 	}
 
 	return ips, nil
 }
 
+// getBlockedRanges returns the exclusions and the reservations of a range pool that belong to the
+// given pool CIDR. For dual-stack pools, this separates the reservations of both IP families.
+// Ranges are not expanded to single IPs, as pools created before exclusions had to be part of the pool
+// CIDR may contain huge (e.g. IPv6) ones.
+func getBlockedRanges(dcIPAMPoolCfg kubermaticv1.IPAMPoolDatacenterSettings) ([]ipRange, error) {
+	_, poolSubnet, err := net.ParseCIDR(string(dcIPAMPoolCfg.PoolCIDR))
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := []ipRange{}
+	for _, excludeRange := range dcIPAMPoolCfg.ExcludeRanges {
+		r, err := parseAddressRange(excludeRange)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+
+	for _, reservation := range dcIPAMPoolCfg.Reservations {
+		r, err := parseAddressRange(reservation.Address)
+		if err != nil {
+			return nil, err
+		}
+		if poolSubnet.Contains(r.first) {
+			ranges = append(ranges, r)
+		}
+	}
+
+	return ranges, nil
+}
+
+// countIPsInPool returns the number of IPs of a range that are part of the pool subnet.
+func countIPsInPool(r ipRange, poolSubnet *net.IPNet) int {
+	// work on the 16-byte representation for both IP families
+	ones, bits := poolSubnet.Mask.Size()
+	mask := net.CIDRMask(ones+8*net.IPv6len-bits, 8*net.IPv6len)
+	poolFirst := poolSubnet.IP.To16().Mask(mask)
+	poolLast := make(net.IP, net.IPv6len)
+	for i := range poolFirst {
+		poolLast[i] = poolFirst[i] | ^mask[i]
+	}
+
+	first, last := r.first.To16(), r.last.To16()
+	if bytes.Compare(first, poolFirst) < 0 {
+		first = poolFirst
+	}
+	if bytes.Compare(last, poolLast) > 0 {
+		last = poolLast
+	}
+	if bytes.Compare(first, last) > 0 {
+		return 0
+	}
+
+	return int(new(big.Int).Sub(new(big.Int).SetBytes(last), new(big.Int).SetBytes(first)).Int64()) + 1
+}
+
 func checkRangeAllocation(ips []string, poolCIDR string, allocationRange int) error {
 	if allocationRange != len(ips) {
 		return errIncompatiblePool
@@ -73,27 +160,44 @@ func checkRangeAllocation(ips []string, poolCIDR string, allocationRange int) er
 	return nil
 }
 
-func calculateRangeFreeIPsFromDatacenterPool(poolCIDR string, dcIPAMPoolUsageMap sets.Set[string]) ([]string, error) {
+// calculateRangeFreeIPsFromDatacenterPool returns up to limit free IPs of the pool, so that large
+// (e.g. IPv6) pools do not need to be iterated completely. Blocked ranges are skipped as a whole.
+func calculateRangeFreeIPsFromDatacenterPool(poolCIDR string, dcIPAMPoolUsageMap sets.Set[string], blockedRanges []ipRange, limit int) ([]string, error) {
 	rangeFreeIPs := []string{}
 
 	ip, ipNet, err := net.ParseCIDR(poolCIDR)
 	if err != nil {
 		return nil, err
 	}
-	for ip := ip.Mask(ipNet.Mask); ipNet.Contains(ip); ip = incIP(ip) {
-		if dcIPAMPoolUsageMap.Has(ip.String()) {
+	ip = ip.Mask(ipNet.Mask)
+	for ipNet.Contains(ip) && len(rangeFreeIPs) < limit {
+		if blocked := findBlockingRange(blockedRanges, ip); blocked != nil {
+			ip = incIP(blocked.last)
 			continue
 		}
-		rangeFreeIPs = append(rangeFreeIPs, ip.String())
+		if !dcIPAMPoolUsageMap.Has(ip.String()) {
+			rangeFreeIPs = append(rangeFreeIPs, ip.String())
+		}
+		ip = incIP(ip)
 	}
 
 	return rangeFreeIPs, nil
 }
 
-func findFirstFreeRangesOfPool(poolName, poolCIDR string, allocationRange int, dcIPAMPoolUsageMap sets.Set[string]) ([]string, error) {
+// findBlockingRange returns the blocked range containing the IP, if any.
+func findBlockingRange(blockedRanges []ipRange, ip net.IP) *ipRange {
+	for i := range blockedRanges {
+		if blockedRanges[i].contains(ip) {
+			return &blockedRanges[i]
+		}
+	}
+	return nil
+}
+
+func findFirstFreeRangesOfPool(poolName, poolCIDR string, allocationRange int, dcIPAMPoolUsageMap sets.Set[string], blockedRanges []ipRange) ([]string, error) {
 	addressRanges := []string{}
 
-	rangeFreeIPs, err := calculateRangeFreeIPsFromDatacenterPool(poolCIDR, dcIPAMPoolUsageMap)
+	rangeFreeIPs, err := calculateRangeFreeIPsFromDatacenterPool(poolCIDR, dcIPAMPoolUsageMap, blockedRanges, allocationRange)
 	if err != nil {
 		return nil, err
 	}
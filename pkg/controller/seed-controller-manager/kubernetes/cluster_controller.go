@@ -194,6 +194,7 @@ func Add(
 		&rbacv1.Role{},
 		&rbacv1.RoleBinding{},
 		&networkingv1.NetworkPolicy{},
+		&kubermaticv1.Addon{},
 	}
 
 	// During cluster deletions, we do not care about changes that happen inside the cluster namespace.
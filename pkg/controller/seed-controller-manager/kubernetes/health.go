@@ -30,6 +30,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *Reconciler) clusterHealth(ctx context.Context, cluster *kubermaticv1.Cluster) (*kubermaticv1.ExtendedClusterHealth, error) {
@@ -116,6 +117,12 @@ func (r *Reconciler) clusterHealth(ctx context.Context, cluster *kubermaticv1.Cl
 		extendedHealth.KubernetesDashboard = &status
 	}
 
+	addonsHealth, err := r.addonsHealthCheck(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get addons health: %w", err)
+	}
+	extendedHealth.Addons = addonsHealth
+
 	return extendedHealth, nil
 }
 
@@ -284,3 +291,29 @@ func (r *Reconciler) statefulSetHealthCheck(ctx context.Context, c *kubermaticv1
 
 	return ready && updated, nil
 }
+
+// addonsHealthCheck combines the Healthy and Degraded conditions of all addons of the cluster.
+// It returns nil if the cluster has no addons.
+func (r *Reconciler) addonsHealthCheck(ctx context.Context, namespace string) (*kubermaticv1.HealthStatus, error) {
+	addons := &kubermaticv1.AddonList{}
+	if err := r.List(ctx, addons, ctrlruntimeclient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list addons: %w", err)
+	}
+
+	if len(addons.Items) == 0 {
+		return nil, nil
+	}
+
+	status := kubermaticv1.HealthStatusUp
+	for _, addon := range addons.Items {
+		if addon.Status.Conditions[kubermaticv1.AddonDegraded].Status == corev1.ConditionTrue {
+			status = kubermaticv1.HealthStatusDown
+			break
+		}
+		if addon.Status.Conditions[kubermaticv1.AddonHealthy].Status != corev1.ConditionTrue {
+			status = kubermaticv1.HealthStatusProvisioning
+		}
+	}
+
+	return &status, nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func addonWithConditions(name string, healthy, degraded corev1.ConditionStatus) *kubermaticv1.Addon {
+	addon := &kubermaticv1.Addon{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "cluster-test",
+		},
+		Status: kubermaticv1.AddonStatus{
+			Conditions: map[kubermaticv1.AddonConditionType]kubermaticv1.AddonCondition{},
+		},
+	}
+
+	if healthy != "" {
+		addon.Status.Conditions[kubermaticv1.AddonHealthy] = kubermaticv1.AddonCondition{Status: healthy}
+	}
+	if degraded != "" {
+		addon.Status.Conditions[kubermaticv1.AddonDegraded] = kubermaticv1.AddonCondition{Status: degraded}
+	}
+
+	return addon
+}
+
+func healthStatus(status kubermaticv1.HealthStatus) *kubermaticv1.HealthStatus {
+	return &status
+}
+
+func TestAddonsHealthCheck(t *testing.T) {
+	testcases := []struct {
+		name     string
+		addons   []ctrlruntimeclient.Object
+		expected *kubermaticv1.HealthStatus
+	}{
+		{
+			name:     "no addons",
+			expected: nil,
+		},
+		{
+			name: "all addons healthy",
+			addons: []ctrlruntimeclient.Object{
+				addonWithConditions("canal", corev1.ConditionTrue, corev1.ConditionFalse),
+				addonWithConditions("csi", corev1.ConditionTrue, corev1.ConditionFalse),
+			},
+			expected: healthStatus(kubermaticv1.HealthStatusUp),
+		},
+		{
+			name: "addon without health conditions yet",
+			addons: []ctrlruntimeclient.Object{
+				addonWithConditions("canal", corev1.ConditionTrue, corev1.ConditionFalse),
+				addonWithConditions("csi", "", ""),
+			},
+			expected: healthStatus(kubermaticv1.HealthStatusProvisioning),
+		},
+		{
+			name: "addon not ready",
+			addons: []ctrlruntimeclient.Object{
+				addonWithConditions("canal", corev1.ConditionFalse, corev1.ConditionFalse),
+			},
+			expected: healthStatus(kubermaticv1.HealthStatusProvisioning),
+		},
+		{
+			name: "degraded addon takes precedence",
+			addons: []ctrlruntimeclient.Object{
+				addonWithConditions("canal", corev1.ConditionFalse, corev1.ConditionFalse),
+				addonWithConditions("csi", corev1.ConditionFalse, corev1.ConditionTrue),
+				addonWithConditions("dns", corev1.ConditionTrue, corev1.ConditionFalse),
+			},
+			expected: healthStatus(kubermaticv1.HealthStatusDown),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Reconciler{
+				Client: ctrlruntimefakeclient.NewClientBuilder().WithObjects(tc.addons...).Build(),
+			}
+
+			status, err := r.addonsHealthCheck(context.Background(), "cluster-test")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if (status == nil) != (tc.expected == nil) || (status != nil && *status != *tc.expected) {
+				t.Fatalf("Expected status %v, but got %v", tc.expected, status)
+			}
+		})
+	}
+}
@@ -73,6 +73,11 @@ func (r *Reconciler) reconcileCluster(ctx context.Context, cluster *kubermaticv1
 		if !kuberneteshelper.HasFinalizer(cluster, kubermaticv1.NodeDeletionFinalizer) {
 			finalizers = append(finalizers, kubermaticv1.NodeDeletionFinalizer)
 		}
+
+		// Applications are uninstalled from the user cluster before it is torn down.
+		if !kuberneteshelper.HasFinalizer(cluster, kubermaticv1.InClusterApplicationCleanupFinalizer) {
+			finalizers = append(finalizers, kubermaticv1.InClusterApplicationCleanupFinalizer)
+		}
 	}
 
 	if !kuberneteshelper.HasFinalizer(cluster, kubermaticv1.KubermaticConstraintCleanupFinalizer) {
@@ -58,6 +58,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -93,6 +94,12 @@ func (r *Reconciler) ensureResourcesAreDeployed(ctx context.Context, cluster *ku
 		return nil, fmt.Errorf("failed to sync address: %w", err)
 	}
 
+	// publish the exposed services on the seed's Gateway, or clean up
+	// the routes when the cluster is using a different strategy
+	if err := r.ensureTLSRoutes(ctx, cluster, data); err != nil {
+		return nil, err
+	}
+
 	// We should not proceed without having an IP address unless tunneling
 	// strategy is used. Its required for all Kubeconfigs & triggers errors
 	// otherwise.
@@ -338,7 +345,7 @@ func GetServiceReconcilers(data *resources.TemplateData) []reconciling.NamedServ
 	extName := data.Cluster().Status.Address.ExternalName
 
 	creators := []reconciling.NamedServiceReconcilerFactory{
-		apiserver.ServiceReconciler(data.Cluster().Spec.ExposeStrategy, extName),
+		apiserver.ServiceReconciler(data.Cluster().Spec.ExposeStrategy, extName, data.Cluster().Status.Address.Port),
 		etcd.ServiceReconciler(data),
 		machinecontroller.ServiceReconciler(),
 		userclusterwebhook.ServiceReconciler(),
@@ -370,6 +377,42 @@ func (r *Reconciler) ensureServices(ctx context.Context, c *kubermaticv1.Cluster
 	return reconciling.ReconcileServices(ctx, creators, c.Status.NamespaceName, r)
 }
 
+// GetTLSRouteReconcilers returns the TLSRoutes that expose the control plane
+// when the GatewayAPI expose strategy is used.
+func GetTLSRouteReconcilers(data *resources.TemplateData) []kkpreconciling.NamedTLSRouteReconcilerFactory {
+	cfg := data.Seed().Spec.GatewayAPI
+	extName := data.Cluster().Status.Address.ExternalName
+
+	creators := []kkpreconciling.NamedTLSRouteReconcilerFactory{
+		nodeportproxy.TLSRouteReconciler(resources.ApiserverServiceName, 443, extName, cfg),
+	}
+
+	if data.IsKonnectivityEnabled() {
+		konnectivityHost := fmt.Sprintf("%s.%s", resources.KonnectivityProxyServiceName, extName)
+		creators = append(creators, nodeportproxy.TLSRouteReconciler(resources.KonnectivityProxyServiceName, 443, konnectivityHost, cfg))
+	}
+
+	return creators
+}
+
+func (r *Reconciler) ensureTLSRoutes(ctx context.Context, c *kubermaticv1.Cluster, data *resources.TemplateData) error {
+	if c.Spec.ExposeStrategy != kubermaticv1.ExposeStrategyGatewayAPI {
+		return r.ensureTLSRoutesAreRemoved(ctx, data)
+	}
+
+	if data.Seed().Spec.GatewayAPI == nil {
+		return fmt.Errorf("expose strategy %q requires a Gateway API configuration in the Seed", c.Spec.ExposeStrategy)
+	}
+
+	// the hostnames cannot be determined before the address has been synced
+	if c.Status.Address.ExternalName == "" {
+		return nil
+	}
+
+	creators := GetTLSRouteReconcilers(data)
+	return kkpreconciling.ReconcileTLSRoutes(ctx, creators, c.Status.NamespaceName, r)
+}
+
 // GetDeploymentReconcilers returns all DeploymentReconcilers that are currently in use.
 func GetDeploymentReconcilers(data *resources.TemplateData, enableAPIserverOIDCAuthentication bool, versions kubermatic.Versions) []reconciling.NamedDeploymentReconcilerFactory {
 	deployments := []reconciling.NamedDeploymentReconcilerFactory{
@@ -870,6 +913,31 @@ func (r *Reconciler) ensureKonnectivitySetupIsRemoved(ctx context.Context, data
 	return nil
 }
 
+// ensureTLSRoutesAreRemoved removes the TLSRoutes of clusters that no longer use the GatewayAPI
+// expose strategy. TLSRoutes can only have been created on seeds with a Gateway API configuration,
+// so other seeds, which usually do not even have the Gateway API CRDs installed, are skipped. The
+// routes are read from the cache first to not send needless deletions on every reconciliation.
+func (r *Reconciler) ensureTLSRoutesAreRemoved(ctx context.Context, data *resources.TemplateData) error {
+	if data.Seed().Spec.GatewayAPI == nil {
+		return nil
+	}
+
+	namespace := data.Cluster().Status.NamespaceName
+	for _, resource := range nodeportproxy.TLSRoutesForDeletion(namespace, resources.ApiserverServiceName, resources.KonnectivityProxyServiceName) {
+		if err := r.Client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(resource), resource); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get TLSRoute %s: %w", resource.GetName(), err)
+		}
+
+		if err := r.Client.Delete(ctx, resource); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to ensure TLSRoutes are removed/not present: %w", err)
+		}
+	}
+	return nil
+}
+
 // ensureKonnectivityNetworkPolicyIsRemoved removes the NetworkPolicy put in place for
 // konnectivity-server -> external API server endpoint communication.
 func (r *Reconciler) ensureKonnectivityNetworkPolicyIsRemoved(ctx context.Context, data *resources.TemplateData) error {
@@ -31,9 +31,13 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 func init() {
@@ -376,3 +380,101 @@ func (k KCMDeploymentConfig) Create(td *resources.TemplateData) *appsv1.Deployme
 	d.Spec.Template.Spec = *wrappedPodSpec
 	return &d
 }
+
+func TestEnsureTLSRoutes(t *testing.T) {
+	ctx := context.Background()
+
+	gatewayScheme := runtime.NewScheme()
+	if err := gatewayapiv1alpha2.AddToScheme(gatewayScheme); err != nil {
+		t.Fatalf("Failed to build scheme: %v", err)
+	}
+
+	gatewaySeed := &kubermaticv1.Seed{
+		Spec: kubermaticv1.SeedSpec{
+			GatewayAPI: &kubermaticv1.SeedGatewayAPIConfiguration{
+				Gateway: kubermaticv1.GatewayReference{Name: "kkp", Namespace: "gateway"},
+			},
+		},
+	}
+
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "abcd",
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			ExposeStrategy: kubermaticv1.ExposeStrategyGatewayAPI,
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: "cluster-abcd",
+			Address: kubermaticv1.ClusterAddress{
+				ExternalName: "abcd.europe-west3-c.dev.kubermatic.io",
+			},
+		},
+	}
+
+	routeNames := func(client ctrlruntimeclient.Client) []string {
+		t.Helper()
+
+		routes := &gatewayapiv1alpha2.TLSRouteList{}
+		if err := client.List(ctx, routes); err != nil {
+			t.Fatalf("Failed to list TLSRoutes: %v", err)
+		}
+
+		names := []string{}
+		for _, route := range routes.Items {
+			names = append(names, route.Name)
+		}
+
+		return sets.List(sets.New(names...))
+	}
+
+	client := fake.NewClientBuilder().WithScheme(gatewayScheme).Build()
+	r := &Reconciler{Client: client}
+
+	td := resources.NewTemplateDataBuilder().
+		WithContext(ctx).
+		WithCluster(cluster).
+		WithSeed(gatewaySeed).
+		WithKonnectivityEnabled(true).
+		Build()
+
+	if err := r.ensureTLSRoutes(ctx, cluster, td); err != nil {
+		t.Fatalf("Failed to ensure TLSRoutes: %v", err)
+	}
+
+	expected := []string{resources.ApiserverServiceName, resources.KonnectivityProxyServiceName}
+	if names := routeNames(client); !sets.New(names...).Equal(sets.New(expected...)) {
+		t.Fatalf("Expected TLSRoutes %v, but got %v", expected, names)
+	}
+
+	route := &gatewayapiv1alpha2.TLSRoute{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.KonnectivityProxyServiceName}, route); err != nil {
+		t.Fatalf("Failed to get konnectivity TLSRoute: %v", err)
+	}
+	if hostname := "konnectivity-server." + cluster.Status.Address.ExternalName; len(route.Spec.Hostnames) != 1 || string(route.Spec.Hostnames[0]) != hostname {
+		t.Errorf("Expected konnectivity TLSRoute for %s, but got %v", hostname, route.Spec.Hostnames)
+	}
+
+	// switching to another expose strategy removes the routes
+	cluster.Spec.ExposeStrategy = kubermaticv1.ExposeStrategyNodePort
+	if err := r.ensureTLSRoutes(ctx, cluster, td); err != nil {
+		t.Fatalf("Failed to ensure TLSRoutes: %v", err)
+	}
+
+	if names := routeNames(client); len(names) != 0 {
+		t.Fatalf("Expected TLSRoutes to be removed, but got %v", names)
+	}
+
+	// seeds without a Gateway API configuration must not touch the TLSRoute API at all,
+	// as the Gateway API CRDs are usually not installed there
+	r = &Reconciler{Client: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()}
+	td = resources.NewTemplateDataBuilder().
+		WithContext(ctx).
+		WithCluster(cluster).
+		WithSeed(&kubermaticv1.Seed{}).
+		Build()
+
+	if err := r.ensureTLSRoutes(ctx, cluster, td); err != nil {
+		t.Fatalf("Expected TLSRoutes to be skipped, but got: %v", err)
+	}
+}
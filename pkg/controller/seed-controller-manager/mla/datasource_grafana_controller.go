@@ -32,12 +32,16 @@ import (
 	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
 	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
 	"k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
+	kkpreconciling "k8c.io/kubermatic/v2/pkg/resources/reconciling"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 	"k8c.io/reconciler/pkg/reconciling"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
@@ -149,6 +153,7 @@ func (r *datasourceGrafanaReconciler) Reconcile(ctx context.Context, request rec
 type datasourceGrafanaController struct {
 	ctrlruntimeclient.Client
 	clientProvider grafanaClientProvider
+	seedGetter     provider.SeedGetter
 	mlaNamespace   string
 
 	log               *zap.SugaredLogger
@@ -158,6 +163,7 @@ type datasourceGrafanaController struct {
 func newDatasourceGrafanaController(
 	client ctrlruntimeclient.Client,
 	clientProvider grafanaClientProvider,
+	seedGetter provider.SeedGetter,
 	mlaNamespace string,
 
 	log *zap.SugaredLogger,
@@ -167,6 +173,7 @@ func newDatasourceGrafanaController(
 		Client:         client,
 		mlaNamespace:   mlaNamespace,
 		clientProvider: clientProvider,
+		seedGetter:     seedGetter,
 
 		log:               log,
 		overwriteRegistry: overwriteRegistry,
@@ -256,6 +263,9 @@ func (r *datasourceGrafanaController) reconcile(ctx context.Context, cluster *ku
 	if err := r.ensureServices(ctx, cluster); err != nil {
 		return nil, fmt.Errorf("failed to reconcile Services in namespace %s: %w", "mla", err)
 	}
+	if err := r.ensureTLSRoutes(ctx, cluster); err != nil {
+		return nil, fmt.Errorf("failed to reconcile TLSRoutes in namespace %s: %w", cluster.Status.NamespaceName, err)
+	}
 
 	alertmanagerDS := grafanasdk.Datasource{
 		OrgID:  org.ID,
@@ -401,6 +411,57 @@ func (r *datasourceGrafanaController) ensureServices(ctx context.Context, c *kub
 	return reconciling.ReconcileServices(ctx, creators, c.Status.NamespaceName, r.Client)
 }
 
+// ensureTLSRoutes publishes the MLA gateway on the seed's Gateway when the
+// cluster uses the GatewayAPI expose strategy.
+func (r *datasourceGrafanaController) ensureTLSRoutes(ctx context.Context, c *kubermaticv1.Cluster) error {
+	if c.Spec.ExposeStrategy != kubermaticv1.ExposeStrategyGatewayAPI {
+		return r.ensureTLSRoutesAreRemoved(ctx, c)
+	}
+
+	seed, err := r.seedGetter()
+	if err != nil {
+		return fmt.Errorf("failed to get Seed: %w", err)
+	}
+	if seed.Spec.GatewayAPI == nil {
+		return fmt.Errorf("expose strategy %q requires a Gateway API configuration in the Seed", c.Spec.ExposeStrategy)
+	}
+
+	if c.Status.Address.ExternalName == "" {
+		return nil
+	}
+
+	creators := []kkpreconciling.NamedTLSRouteReconcilerFactory{
+		nodeportproxy.TLSRouteReconciler(gatewayExternalName, 80, resources.MLAGatewaySNIPrefix+c.Status.Address.ExternalName, seed.Spec.GatewayAPI),
+	}
+	return kkpreconciling.ReconcileTLSRoutes(ctx, creators, c.Status.NamespaceName, r.Client)
+}
+
+// ensureTLSRoutesAreRemoved removes the MLA gateway TLSRoute. Seeds without a Gateway API
+// configuration cannot have any TLSRoutes and are skipped.
+func (r *datasourceGrafanaController) ensureTLSRoutesAreRemoved(ctx context.Context, c *kubermaticv1.Cluster) error {
+	seed, err := r.seedGetter()
+	if err != nil {
+		return fmt.Errorf("failed to get Seed: %w", err)
+	}
+	if seed.Spec.GatewayAPI == nil {
+		return nil
+	}
+
+	for _, resource := range nodeportproxy.TLSRoutesForDeletion(c.Status.NamespaceName, gatewayExternalName) {
+		if err := r.Client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(resource), resource); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get TLSRoute %s: %w", resource.GetName(), err)
+		}
+
+		if err := r.Client.Delete(ctx, resource); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete TLSRoute %s: %w", resource.GetName(), err)
+		}
+	}
+	return nil
+}
+
 func (r *datasourceGrafanaController) CleanUp(ctx context.Context) error {
 	clusterList := &kubermaticv1.ClusterList{}
 	if err := r.List(ctx, clusterList); err != nil {
@@ -455,6 +516,9 @@ func (r *datasourceGrafanaController) handleDeletion(ctx context.Context, cluste
 				return fmt.Errorf("failed to delete %s: %w", resource.GetName(), err)
 			}
 		}
+		if err := r.ensureTLSRoutesAreRemoved(ctx, cluster); err != nil {
+			return err
+		}
 	}
 
 	return kubernetes.TryRemoveFinalizer(ctx, r, cluster, mlaFinalizer)
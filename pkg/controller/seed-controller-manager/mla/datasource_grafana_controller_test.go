@@ -35,6 +35,7 @@ import (
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
+	"k8c.io/kubermatic/v2/pkg/test"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -45,11 +46,13 @@ import (
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 func newTestDatasourceGrafanaReconciler(t *testing.T, objects []ctrlruntimeclient.Object, handler http.Handler) (*datasourceGrafanaReconciler, *httptest.Server) {
 	dynamicClient := ctrlruntimefakeclient.
 		NewClientBuilder().
+		WithScheme(testScheme).
 		WithObjects(objects...).
 		Build()
 	ts := httptest.NewServer(handler)
@@ -59,9 +62,20 @@ func newTestDatasourceGrafanaReconciler(t *testing.T, objects []ctrlruntimeclien
 		t.Fatalf("unable to initialize grafana client: %v", err)
 	}
 
+	seed := &kubermaticv1.Seed{
+		Spec: kubermaticv1.SeedSpec{
+			GatewayAPI: &kubermaticv1.SeedGatewayAPIConfiguration{
+				Gateway: kubermaticv1.GatewayReference{
+					Name:      "kkp",
+					Namespace: "gateway-system",
+				},
+			},
+		},
+	}
+
 	datasourceGrafanaController := newDatasourceGrafanaController(dynamicClient, func(ctx context.Context) (*grafanasdk.Client, error) {
 		return grafanaClient, nil
-	}, "mla", kubermaticlog.Logger, "")
+	}, test.NewSeedGetter(seed), "mla", kubermaticlog.Logger, "")
 	reconciler := datasourceGrafanaReconciler{
 		Client:                      dynamicClient,
 		log:                         kubermaticlog.Logger,
@@ -82,6 +96,7 @@ func TestDatasourceGrafanaReconcile(t *testing.T) {
 		hasFinalizer            bool
 		hasResources            bool
 		expectExposeAnnotations map[string]string
+		expectTLSRouteHostname  string
 	}{
 		{
 			name:         "create datasource for cluster",
@@ -540,6 +555,98 @@ func TestDatasourceGrafanaReconcile(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:         "MLA gateway exposed via Gateway API",
+			requestName:  "clusterUID",
+			hasFinalizer: true,
+			hasResources: true,
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.Project{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "projectUID",
+						Annotations: map[string]string{GrafanaOrgAnnotationKey: "1"},
+					},
+					Spec: kubermaticv1.ProjectSpec{
+						Name: "projectName",
+					},
+				},
+				&kubermaticv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: "projectUID"},
+						Name:   "clusterUID",
+					},
+					Spec: kubermaticv1.ClusterSpec{
+						HumanReadableName: "Super Cluster",
+						MLA: &kubermaticv1.MLASettings{
+							MonitoringEnabled: true,
+							LoggingEnabled:    false,
+						},
+						ExposeStrategy: kubermaticv1.ExposeStrategyGatewayAPI,
+					},
+					Status: kubermaticv1.ClusterStatus{
+						NamespaceName: "cluster-clusterUID",
+						Address: kubermaticv1.ClusterAddress{
+							ExternalName: "abcd.test.kubermatic.io",
+						},
+					},
+				},
+			},
+			expectTLSRouteHostname: resources.MLAGatewaySNIPrefix + "abcd.test.kubermatic.io",
+			requests: []request{
+				{
+					name:     "get org by id",
+					request:  httptest.NewRequest(http.MethodGet, "/api/orgs/1", nil),
+					response: &http.Response{Body: io.NopCloser(strings.NewReader(`{"id":1,"name":"projectName-projectUID","address":{"address1":"","address2":"","city":"","zipCode":"","state":"","country":""}}`)), StatusCode: http.StatusOK},
+				},
+				{
+					name: "get datasource by uid",
+					request: &http.Request{
+						Method: http.MethodGet,
+						URL:    &url.URL{Path: "/api/datasources/uid/alertmanager-clusterUID"},
+						Header: map[string][]string{"X-Grafana-Org-Id": {"1"}},
+					},
+					response: &http.Response{StatusCode: http.StatusNotFound},
+				},
+				{
+					name: "create alertmanager datasource",
+					request: &http.Request{
+						Method: http.MethodPost,
+						URL:    &url.URL{Path: "/api/datasources"},
+						Body:   io.NopCloser(strings.NewReader(`{"name":"Alertmanager Super Cluster", "orgId":1,  "type":"alertmanager", "uid":"alertmanager-clusterUID", "url":"http://mla-gateway.cluster-clusterUID.svc.cluster.local/api/prom", "access":"proxy", "id":0, "isDefault":false, "jsonData":null, "secureJsonData":null}`)),
+						Header: map[string][]string{"X-Grafana-Org-Id": {"1"}},
+					},
+					response: &http.Response{Body: io.NopCloser(strings.NewReader(`{"message": "datasource created", "id": 1}`)), StatusCode: http.StatusOK},
+				},
+				{
+					name: "delete loki datasource",
+					request: &http.Request{
+						Method: http.MethodDelete,
+						URL:    &url.URL{Path: "/api/datasources/uid/loki-clusterUID"},
+						Header: map[string][]string{"X-Grafana-Org-Id": {"1"}},
+					},
+					response: &http.Response{Body: io.NopCloser(strings.NewReader(`{"message": "datasource deleted"}`)), StatusCode: http.StatusOK},
+				},
+				{
+					name: "get datasource by uid",
+					request: &http.Request{
+						Method: http.MethodGet,
+						URL:    &url.URL{Path: "/api/datasources/uid/prometheus-clusterUID"},
+						Header: map[string][]string{"X-Grafana-Org-Id": {"1"}},
+					},
+					response: &http.Response{StatusCode: http.StatusNotFound},
+				},
+				{
+					name: "create prometheus datasource",
+					request: &http.Request{
+						Method: http.MethodPost,
+						URL:    &url.URL{Path: "/api/datasources"},
+						Body:   io.NopCloser(strings.NewReader(`{"name":"Prometheus Super Cluster", "orgId":1,  "type":"prometheus", "uid":"prometheus-clusterUID", "url":"http://mla-gateway.cluster-clusterUID.svc.cluster.local/api/prom", "access":"proxy", "id":0, "isDefault":false, "jsonData":null, "secureJsonData":null}`)),
+						Header: map[string][]string{"X-Grafana-Org-Id": {"1"}},
+					},
+					response: &http.Response{Body: io.NopCloser(strings.NewReader(`{"message": "datasource created", "id": 2}`)), StatusCode: http.StatusOK},
+				},
+			},
+		},
 		{
 			name:         "MLA Monitoring disabled for cluster",
 			requestName:  "clusterUID",
@@ -689,6 +796,15 @@ func TestDatasourceGrafanaReconcile(t *testing.T) {
 				assert.True(t, apierrors.IsNotFound(err))
 			}
 
+			route := &gatewayapiv1alpha2.TLSRoute{}
+			err = controller.Get(ctx, request.NamespacedName, route)
+			if tc.expectTLSRouteHostname != "" {
+				assert.Nil(t, err)
+				assert.Equal(t, []gatewayapiv1alpha2.Hostname{gatewayapiv1alpha2.Hostname(tc.expectTLSRouteHostname)}, route.Spec.Hostnames)
+			} else {
+				assert.True(t, apierrors.IsNotFound(err))
+			}
+
 			secret := &corev1.Secret{}
 			request = reconcile.Request{NamespacedName: types.NamespacedName{Name: resources.MLAGatewayCASecretName, Namespace: cluster.Status.NamespaceName}}
 			err = controller.Get(ctx, request.NamespacedName, secret)
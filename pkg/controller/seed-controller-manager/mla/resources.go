@@ -277,6 +277,12 @@ func GatewayExternalServiceReconciler(c *kubermaticv1.Cluster) reconciling.Named
 				s.Annotations[nodeportproxy.PortHostMappingAnnotationKey] =
 					fmt.Sprintf(`{%q: %q}`, extPortName, resources.MLAGatewaySNIPrefix+c.Status.Address.ExternalName)
 				delete(s.Annotations, nodeportproxy.NodePortProxyExposeNamespacedAnnotationKey)
+			case kubermaticv1.ExposeStrategyGatewayAPI:
+				// Exposes MLA GW via a TLSRoute on the seed's Gateway.
+				s.Spec.Type = corev1.ServiceTypeClusterIP
+				delete(s.Annotations, nodeportproxy.DefaultExposeAnnotationKey)
+				delete(s.Annotations, nodeportproxy.NodePortProxyExposeNamespacedAnnotationKey)
+				delete(s.Annotations, nodeportproxy.PortHostMappingAnnotationKey)
 			default:
 				return nil, fmt.Errorf("unsupported expose strategy: %q", c.Spec.ExposeStrategy)
 			}
@@ -290,7 +296,7 @@ func GatewayExternalServiceReconciler(c *kubermaticv1.Cluster) reconciling.Named
 			s.Spec.Ports[0].Port = 80
 			s.Spec.Ports[0].TargetPort = intstr.FromString(extPortName)
 
-			if c.Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+			if c.Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling || c.Spec.ExposeStrategy == kubermaticv1.ExposeStrategyGatewayAPI {
 				s.Spec.Ports[0].NodePort = 0 // allows switching from other expose strategies
 			}
 
@@ -51,6 +51,7 @@ const (
 	ClusterConditionUpToDate    = "UpToDate"
 	ClusterConditionProgressing = "Progressing"
 	ClusterConditionOldNodes    = "OldNodes"
+	ClusterConditionRemovedAPIs = "RemovedAPIsInUse"
 )
 
 type controlPlaneChecker func(context.Context, ctrlruntimeclient.Client, *zap.SugaredLogger, *kubermaticv1.Cluster) (*controlPlaneStatus, error)
@@ -212,6 +213,17 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 		// or in need of reconciling, but for this controller there is no further work to be done.
 		log.Debugw("Cluster control plane has reached the spec'ed version.", "spec", spec)
 
+		// Check the next minor release already, so that cluster owners get a chance to migrate
+		// away from removed APIs before the next (automatic) update.
+		nextMinor, err := semver.NewSemver(fmt.Sprintf("%d.%d.0", spec.Semver().Major(), spec.Semver().Minor()+1))
+		if err != nil {
+			return fmt.Errorf("failed to determine next minor version: %w", err)
+		}
+
+		if _, err := r.checkUpgradeCompatibility(ctx, cluster, nextMinor); err != nil {
+			return err
+		}
+
 		return r.setClusterCondition(ctx, cluster, ClusterConditionUpToDate, "No update in progress, cluster has reached its desired version.")
 	}
 
@@ -322,6 +334,18 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 		return fmt.Errorf("failed to determine update path: %w", err)
 	}
 
+	// Objects and Helm releases that still use APIs which are removed in the new release would
+	// break, so the update must not happen before they have been migrated.
+	compatible, err := r.checkUpgradeCompatibility(ctx, cluster, newVersion)
+	if err != nil {
+		return err
+	}
+
+	if !compatible {
+		log.Infow("Cluster control plane is healthy but APIs removed in the next version are still in use.", "next", newVersion)
+		return r.setClusterCondition(ctx, cluster, ClusterConditionRemovedAPIs, fmt.Sprintf("Update to v%s is blocked, APIs removed in this release are still in use (see the %s condition).", newVersion.String(), kubermaticv1.ClusterConditionUpgradeCompatible))
+	}
+
 	// Set this new target version as the next step on our upgrading journey. This will trigger a
 	// reconciliation for us and also make the KKP kubernetes controller roll out the new apiserver.
 	if err := kubermaticv1helper.UpdateClusterStatus(ctx, r, cluster, func(c *kubermaticv1.Cluster) {
@@ -336,6 +360,47 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 	return nil
 }
 
+// checkUpgradeCompatibility updates the UpgradeCompatible condition based on the deprecated APIs
+// the usercluster-controller-manager found in the cluster and returns false if APIs removed in the
+// target version are still in use, unless the check is skipped for the cluster.
+func (r *Reconciler) checkUpgradeCompatibility(ctx context.Context, cluster *kubermaticv1.Cluster, target *semver.Semver) (bool, error) {
+	removed := kubermaticv1helper.RemovedAPIsInUse(cluster, &cluster.Status.Versions.ControlPlane, target)
+	skipped := cluster.Annotations[kubermaticv1.SkipUpgradeCompatibilityCheckAnnotation] == "true"
+
+	status := corev1.ConditionTrue
+	reason := "NoRemovedAPIsInUse"
+	message := fmt.Sprintf("No APIs removed in Kubernetes %s are in use.", target.MajorMinor())
+
+	switch {
+	case len(removed) > 0:
+		status = corev1.ConditionFalse
+		reason = ClusterConditionRemovedAPIs
+		message = kubermaticv1helper.RemovedAPIsMessage(removed, target)
+
+		if skipped {
+			message = fmt.Sprintf("%s The check is skipped because of the %s annotation.", message, kubermaticv1.SkipUpgradeCompatibilityCheckAnnotation)
+		}
+
+	case cluster.Status.DeprecatedAPIs == nil:
+		reason = "NotScanned"
+		message = "The cluster has not been scanned for deprecated APIs yet."
+	}
+
+	wasCompatible := !cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionUpgradeCompatible, corev1.ConditionFalse)
+
+	if err := kubermaticv1helper.UpdateClusterStatus(ctx, r, cluster, func(c *kubermaticv1.Cluster) {
+		kubermaticv1helper.SetClusterCondition(c, r.versions, kubermaticv1.ClusterConditionUpgradeCompatible, status, reason, message)
+	}); err != nil {
+		return false, fmt.Errorf("failed to update cluster condition: %w", err)
+	}
+
+	if wasCompatible && status == corev1.ConditionFalse {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "RemovedAPIsInUse", message)
+	}
+
+	return len(removed) == 0 || skipped, nil
+}
+
 // setInitialClusterVersions assumes that the cluster was never up and running and sets
 // the desired versions in the status to be equal to the version from the spec. The
 // status about currently running components is left empty and filled in during later
@@ -344,6 +344,7 @@ func TestReconcile(t *testing.T) {
 			*semver.NewSemverOrDie("1.22.5"),
 			*semver.NewSemverOrDie("1.23.0"),
 			*semver.NewSemverOrDie("1.24.0"),
+			*semver.NewSemverOrDie("1.25.0"),
 		},
 		Updates: []kubermaticv1.Update{
 			{
@@ -379,13 +380,31 @@ func TestReconcile(t *testing.T) {
 				From: "1.24.*",
 				To:   "1.24.*",
 			},
+			{
+				From: "1.24.*",
+				To:   "1.25.*",
+			},
+		},
+	}
+
+	removedAPIs := &kubermaticv1.DeprecatedAPIsStatus{
+		Usages: []kubermaticv1.DeprecatedAPIUsage{
+			{
+				Group:            "batch",
+				Version:          "v1beta1",
+				Resource:         "cronjobs",
+				RemovedInVersion: "1.25",
+				Objects:          []string{"cleanup (Helm release apps/app)"},
+			},
 		},
 	}
 
 	testcases := []struct {
 		name           string
 		specVersion    semver.Semver
+		annotations    map[string]string
 		clusterStatus  kubermaticv1.ClusterVersionsStatus
+		deprecatedAPIs *kubermaticv1.DeprecatedAPIsStatus
 		currentStatus  controlPlaneStatus
 		healthy        bool
 		expectedStatus kubermaticv1.ClusterVersionsStatus
@@ -607,6 +626,59 @@ func TestReconcile(t *testing.T) {
 			},
 		},
 
+		// ///////////////////////////////////////////////////////
+		// the following tests demonstrate how removed APIs block updates
+
+		{
+			name:        "removed APIs are still in use, cannot progress with updates",
+			specVersion: *semver.NewSemverOrDie("1.25.0"),
+			healthy:     true,
+			clusterStatus: kubermaticv1.ClusterVersionsStatus{
+				ControlPlane:      *semver.NewSemverOrDie("1.24.0"),
+				Apiserver:         *semver.NewSemverOrDie("1.24.0"),
+				ControllerManager: *semver.NewSemverOrDie("1.24.0"),
+				Scheduler:         *semver.NewSemverOrDie("1.24.0"),
+			},
+			deprecatedAPIs: removedAPIs,
+			currentStatus: controlPlaneStatus{
+				apiserver:         semver.NewSemverOrDie("1.24.0"),
+				controllerManager: semver.NewSemverOrDie("1.24.0"),
+				scheduler:         semver.NewSemverOrDie("1.24.0"),
+			},
+			expectedStatus: kubermaticv1.ClusterVersionsStatus{
+				ControlPlane:      *semver.NewSemverOrDie("1.24.0"),
+				Apiserver:         *semver.NewSemverOrDie("1.24.0"),
+				ControllerManager: *semver.NewSemverOrDie("1.24.0"),
+				Scheduler:         *semver.NewSemverOrDie("1.24.0"),
+			},
+		},
+		{
+			name:        "removed APIs are still in use, but the check is skipped",
+			specVersion: *semver.NewSemverOrDie("1.25.0"),
+			annotations: map[string]string{
+				kubermaticv1.SkipUpgradeCompatibilityCheckAnnotation: "true",
+			},
+			healthy: true,
+			clusterStatus: kubermaticv1.ClusterVersionsStatus{
+				ControlPlane:      *semver.NewSemverOrDie("1.24.0"),
+				Apiserver:         *semver.NewSemverOrDie("1.24.0"),
+				ControllerManager: *semver.NewSemverOrDie("1.24.0"),
+				Scheduler:         *semver.NewSemverOrDie("1.24.0"),
+			},
+			deprecatedAPIs: removedAPIs,
+			currentStatus: controlPlaneStatus{
+				apiserver:         semver.NewSemverOrDie("1.24.0"),
+				controllerManager: semver.NewSemverOrDie("1.24.0"),
+				scheduler:         semver.NewSemverOrDie("1.24.0"),
+			},
+			expectedStatus: kubermaticv1.ClusterVersionsStatus{
+				ControlPlane:      *semver.NewSemverOrDie("1.24.0"),
+				Apiserver:         *semver.NewSemverOrDie("1.25.0"),
+				ControllerManager: *semver.NewSemverOrDie("1.24.0"),
+				Scheduler:         *semver.NewSemverOrDie("1.24.0"),
+			},
+		},
+
 		// ///////////////////////////////////////////////////////
 		// the following tests demonstrate how we must wait for nodes
 		// before proceeding with the control plane
@@ -761,7 +833,8 @@ func TestReconcile(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			cluster := &kubermaticv1.Cluster{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "testcluster",
+					Name:        "testcluster",
+					Annotations: tt.annotations,
 				},
 				Spec: kubermaticv1.ClusterSpec{
 					Version: tt.specVersion,
@@ -770,7 +843,8 @@ func TestReconcile(t *testing.T) {
 					},
 				},
 				Status: kubermaticv1.ClusterStatus{
-					Versions: tt.clusterStatus,
+					Versions:       tt.clusterStatus,
+					DeprecatedAPIs: tt.deprecatedAPIs,
 				},
 			}
 
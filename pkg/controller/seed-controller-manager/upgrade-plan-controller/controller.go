@@ -0,0 +1,513 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeplancontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/util/workerlabel"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kkp-upgrade-plan-controller"
+
+	defaultHealthTimeout = 30 * time.Minute
+
+	// updateFailureGracePeriod is the time the update controller may fail to reconcile
+	// a cluster before the cluster is considered failed, so that transient errors do not
+	// pause the whole plan.
+	updateFailureGracePeriod = 5 * time.Minute
+)
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	workerName string
+	recorder   record.EventRecorder
+	log        *zap.SugaredLogger
+	clock      clock.PassiveClock
+}
+
+// Add creates a new upgrade plan controller.
+func Add(mgr manager.Manager, numWorkers int, workerName string, log *zap.SugaredLogger) error {
+	reconciler := &Reconciler{
+		Client: mgr.GetClient(),
+
+		workerName: workerName,
+		recorder:   mgr.GetEventRecorderFor(ControllerName),
+		log:        log.Named(ControllerName),
+		clock:      &clock.RealClock{},
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: numWorkers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.UpgradePlan{}}, &handler.EnqueueRequestForObject{}, workerlabel.Predicates(workerName)); err != nil {
+		return fmt.Errorf("failed to create watch: %w", err)
+	}
+
+	// watch clusters to react to their health and version changing
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Cluster{}}, enqueueUpgradePlans(mgr.GetClient(), reconciler.log), workerlabel.Predicates(workerName)); err != nil {
+		return fmt.Errorf("failed to create watch: %w", err)
+	}
+
+	return nil
+}
+
+func enqueueUpgradePlans(client ctrlruntimeclient.Client, log *zap.SugaredLogger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(a ctrlruntimeclient.Object) []reconcile.Request {
+		cluster, ok := a.(*kubermaticv1.Cluster)
+		if !ok {
+			return nil
+		}
+
+		plans := &kubermaticv1.UpgradePlanList{}
+		if err := client.List(context.Background(), plans); err != nil {
+			log.Errorw("Failed to list UpgradePlans", zap.Error(err))
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, plan := range plans.Items {
+			// a cluster that is no longer selected still has to be removed from the plan's status
+			_, tracked := plan.Status.Clusters[cluster.Name]
+
+			selected, err := plan.SelectsCluster(cluster)
+			if err != nil {
+				continue
+			}
+
+			if selected || tracked {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: plan.Name}})
+			}
+		}
+
+		return requests
+	})
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("upgradeplan", request.Name)
+	log.Debug("Reconciling")
+
+	plan := &kubermaticv1.UpgradePlan{}
+	if err := r.Get(ctx, request.NamespacedName, plan); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if plan.DeletionTimestamp != nil || plan.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconcile(ctx, log, plan)
+	if err != nil {
+		log.Errorw("Failed to reconcile", zap.Error(err))
+		r.recorder.Event(plan, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	return result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, plan *kubermaticv1.UpgradePlan) (reconcile.Result, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&plan.Spec.ClusterSelector)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("invalid cluster selector: %w", err)
+	}
+
+	clusterList := &kubermaticv1.ClusterList{}
+	if err := r.List(ctx, clusterList, &ctrlruntimeclient.ListOptions{LabelSelector: selector}); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	clusters := map[string]*kubermaticv1.Cluster{}
+	for i, cluster := range clusterList.Items {
+		if cluster.DeletionTimestamp != nil || cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
+			continue
+		}
+
+		clusters[cluster.Name] = &clusterList.Items[i]
+	}
+
+	oldPlan := plan.DeepCopy()
+	now := r.clock.Now()
+
+	toUpgrade, requeueAfter := updateStatus(plan, clusters, now)
+
+	for _, name := range toUpgrade {
+		if err := r.upgradeCluster(ctx, log, plan, clusters[name]); err != nil {
+			// the cluster might not accept the new version, which must pause the plan just like any other failure
+			log.Errorw("Failed to upgrade cluster", "cluster", name, zap.Error(err))
+
+			clusterStatus := plan.Status.Clusters[name]
+			clusterStatus.Phase = kubermaticv1.UpgradePlanClusterPhaseFailed
+			clusterStatus.Message = fmt.Sprintf("Failed to set cluster version: %v", err)
+			plan.Status.Clusters[name] = clusterStatus
+
+			updatePhase(plan, now)
+		}
+	}
+
+	if !reflect.DeepEqual(oldPlan.Status, plan.Status) {
+		if err := r.Status().Patch(ctx, plan, ctrlruntimeclient.MergeFrom(oldPlan)); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to update status: %w", err)
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *Reconciler) upgradeCluster(ctx context.Context, log *zap.SugaredLogger, plan *kubermaticv1.UpgradePlan, cluster *kubermaticv1.Cluster) error {
+	oldCluster := cluster.DeepCopy()
+	cluster.Spec.Version = plan.Spec.Version
+
+	// Setting the new version will make the update controller perform the upgrade step by step.
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return err
+	}
+
+	log.Infow("Started cluster upgrade", "cluster", cluster.Name, "from", oldCluster.Spec.Version, "to", cluster.Spec.Version)
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "UpgradePlanApplied", "UpgradePlan %s started the upgrade from v%s to v%s.", plan.Name, oldCluster.Spec.Version, cluster.Spec.Version)
+	r.recorder.Eventf(plan, corev1.EventTypeNormal, "ClusterUpgradeStarted", "Started upgrade of cluster %s from v%s to v%s.", cluster.Name, oldCluster.Spec.Version, cluster.Spec.Version)
+
+	return nil
+}
+
+// updateStatus updates the plan's status based on the current state of the selected
+// clusters. It returns the names of the clusters whose upgrade should be started now
+// (these are already marked as Upgrading in the status) and the time after which the
+// plan should be reconciled again, because a timeout, soak time or update window
+// will have passed.
+func updateStatus(plan *kubermaticv1.UpgradePlan, clusters map[string]*kubermaticv1.Cluster, now time.Time) ([]string, time.Duration) {
+	status := &plan.Status
+	if status.Clusters == nil {
+		status.Clusters = map[string]kubermaticv1.UpgradePlanClusterStatus{}
+	}
+
+	// forget about clusters that have been deleted or are no longer selected
+	for name := range status.Clusters {
+		if _, exists := clusters[name]; !exists {
+			delete(status.Clusters, name)
+		}
+	}
+
+	clampWaves(plan)
+	assignWaves(plan, clusters)
+
+	var requeue requeueTimer
+
+	for name, clusterStatus := range status.Clusters {
+		status.Clusters[name] = updateClusterStatus(plan, clusterStatus, clusters[name], now, &requeue)
+	}
+
+	updatePhase(plan, now)
+
+	if status.Phase != kubermaticv1.UpgradePlanPhaseProgressing {
+		if status.Phase == kubermaticv1.UpgradePlanPhaseSoaking {
+			soakTime := plan.Spec.Waves[status.CurrentWave].SoakTime.Duration
+			requeue.after(status.WaveCompletionTime.Add(soakTime).Sub(now))
+		}
+
+		return nil, requeue.duration
+	}
+
+	// start the upgrade of all pending clusters in the current wave,
+	// as long as they are within their update window
+	toUpgrade := []string{}
+
+	for _, name := range sortedClusterNames(status.Clusters) {
+		clusterStatus := status.Clusters[name]
+		if clusterStatus.Wave != status.CurrentWave || clusterStatus.Phase != kubermaticv1.UpgradePlanClusterPhasePending {
+			continue
+		}
+
+		cluster := clusters[name]
+		if cluster.Spec.Pause {
+			clusterStatus.Message = "Cluster is paused."
+			status.Clusters[name] = clusterStatus
+			continue
+		}
+
+		open, opensIn := updateWindowOpen(cluster.Spec.UpdateWindow, now)
+		if !open {
+			clusterStatus.Message = fmt.Sprintf("Waiting for the update window to open in %v.", opensIn.Round(time.Minute))
+			status.Clusters[name] = clusterStatus
+			requeue.after(opensIn)
+			continue
+		}
+
+		fromVersion := cluster.Spec.Version
+
+		clusterStatus.Phase = kubermaticv1.UpgradePlanClusterPhaseUpgrading
+		clusterStatus.FromVersion = &fromVersion
+		clusterStatus.StartTime = &metav1.Time{Time: now}
+		clusterStatus.Message = ""
+		status.Clusters[name] = clusterStatus
+
+		requeue.after(healthTimeout(plan))
+		toUpgrade = append(toUpgrade, name)
+	}
+
+	return toUpgrade, requeue.duration
+}
+
+// clampWaves moves the plan and its clusters from waves that no longer exist, because
+// waves have been removed while the plan was in progress, into the last wave.
+func clampWaves(plan *kubermaticv1.UpgradePlan) {
+	status := &plan.Status
+	lastWave := len(plan.Spec.Waves) - 1
+
+	if status.CurrentWave > lastWave {
+		status.CurrentWave = lastWave
+		status.WaveCompletionTime = nil
+	}
+
+	for name, clusterStatus := range status.Clusters {
+		if clusterStatus.Wave > lastWave {
+			clusterStatus.Wave = lastWave
+			status.Clusters[name] = clusterStatus
+		}
+	}
+}
+
+// assignWaves adds all newly selected clusters to the status. Clusters are assigned
+// to the current or a later wave, so that a cluster is never added to a wave that
+// has already been rolled out.
+func assignWaves(plan *kubermaticv1.UpgradePlan, clusters map[string]*kubermaticv1.Cluster) {
+	status := &plan.Status
+	lastWave := len(plan.Spec.Waves) - 1
+
+	waveSizes := make([]int, len(plan.Spec.Waves))
+	for _, clusterStatus := range status.Clusters {
+		if clusterStatus.Phase != kubermaticv1.UpgradePlanClusterPhaseSkipped && clusterStatus.Wave <= lastWave {
+			waveSizes[clusterStatus.Wave]++
+		}
+	}
+
+	for _, name := range sortedClusterNames(clusters) {
+		if _, exists := status.Clusters[name]; exists {
+			continue
+		}
+
+		cluster := clusters[name]
+		if !cluster.Spec.Version.LessThan(&plan.Spec.Version) {
+			status.Clusters[name] = kubermaticv1.UpgradePlanClusterStatus{
+				Wave:    status.CurrentWave,
+				Phase:   kubermaticv1.UpgradePlanClusterPhaseSkipped,
+				Message: fmt.Sprintf("Cluster already runs v%s.", cluster.Spec.Version),
+			}
+			continue
+		}
+
+		wave := lastWave
+		for i := status.CurrentWave; i < lastWave; i++ {
+			if size := plan.Spec.Waves[i].Size; size == 0 || waveSizes[i] < size {
+				wave = i
+				break
+			}
+		}
+
+		waveSizes[wave]++
+		status.Clusters[name] = kubermaticv1.UpgradePlanClusterStatus{
+			Wave:  wave,
+			Phase: kubermaticv1.UpgradePlanClusterPhasePending,
+		}
+	}
+}
+
+func updateClusterStatus(plan *kubermaticv1.UpgradePlan, clusterStatus kubermaticv1.UpgradePlanClusterStatus, cluster *kubermaticv1.Cluster, now time.Time, requeue *requeueTimer) kubermaticv1.UpgradePlanClusterStatus {
+	upgraded := !cluster.Status.Versions.ControlPlane.LessThan(&plan.Spec.Version) && cluster.Status.ExtendedHealth.AllHealthy()
+
+	switch clusterStatus.Phase {
+	case kubermaticv1.UpgradePlanClusterPhaseUpgrading:
+		if failed, message := updateFailed(cluster, now); failed {
+			clusterStatus.Phase = kubermaticv1.UpgradePlanClusterPhaseFailed
+			clusterStatus.Message = message
+			break
+		}
+
+		if upgraded {
+			clusterStatus.Phase = kubermaticv1.UpgradePlanClusterPhaseUpgraded
+			clusterStatus.CompletionTime = &metav1.Time{Time: now}
+			clusterStatus.Message = ""
+			break
+		}
+
+		deadline := clusterStatus.StartTime.Add(healthTimeout(plan))
+		if !now.Before(deadline) {
+			clusterStatus.Phase = kubermaticv1.UpgradePlanClusterPhaseFailed
+			clusterStatus.Message = fmt.Sprintf("Cluster did not reach v%s and become healthy within %v.", plan.Spec.Version, healthTimeout(plan))
+			break
+		}
+
+		requeue.after(deadline.Sub(now))
+
+	case kubermaticv1.UpgradePlanClusterPhaseUpgraded:
+		// clusters of the wave that is currently rolled out or soaking must stay healthy
+		if clusterStatus.Wave == plan.Status.CurrentWave && !cluster.Status.ExtendedHealth.AllHealthy() {
+			clusterStatus.Phase = kubermaticv1.UpgradePlanClusterPhaseFailed
+			clusterStatus.Message = "Cluster became unhealthy after its upgrade."
+		}
+
+	case kubermaticv1.UpgradePlanClusterPhaseFailed:
+		if failed, _ := updateFailed(cluster, now); !failed && upgraded {
+			clusterStatus.Phase = kubermaticv1.UpgradePlanClusterPhaseUpgraded
+			clusterStatus.CompletionTime = &metav1.Time{Time: now}
+			clusterStatus.Message = "Cluster has recovered."
+		}
+	}
+
+	return clusterStatus
+}
+
+// updateFailed checks whether the update controller failed to upgrade the cluster.
+func updateFailed(cluster *kubermaticv1.Cluster, now time.Time) (bool, string) {
+	if condition, ok := cluster.Status.Conditions[kubermaticv1.ClusterConditionUpdateProgress]; ok && condition.Status == corev1.ConditionFalse {
+		return true, fmt.Sprintf("Update failed: %s", condition.Message)
+	}
+
+	condition, ok := cluster.Status.Conditions[kubermaticv1.ClusterConditionUpdateControllerReconcilingSuccess]
+	if ok && condition.Status == corev1.ConditionFalse && now.Sub(condition.LastTransitionTime.Time) >= updateFailureGracePeriod {
+		return true, fmt.Sprintf("Update controller has been failing to reconcile the cluster since %s.", condition.LastTransitionTime.UTC().Format(time.RFC3339))
+	}
+
+	return false, ""
+}
+
+// updatePhase determines the plan's phase and advances it to the next wave once
+// the current wave has been upgraded and its soak time has passed.
+func updatePhase(plan *kubermaticv1.UpgradePlan, now time.Time) {
+	status := &plan.Status
+
+	for _, name := range sortedClusterNames(status.Clusters) {
+		if clusterStatus := status.Clusters[name]; clusterStatus.Phase == kubermaticv1.UpgradePlanClusterPhaseFailed {
+			status.Phase = kubermaticv1.UpgradePlanPhasePaused
+			status.Message = fmt.Sprintf("Cluster %s failed: %s", name, clusterStatus.Message)
+			return
+		}
+	}
+
+	if plan.Spec.Paused {
+		status.Phase = kubermaticv1.UpgradePlanPhasePaused
+		status.Message = "UpgradePlan has been paused."
+		return
+	}
+
+	for {
+		wave := plan.Spec.Waves[status.CurrentWave]
+		waveUpgraded := true
+		waveEmpty := true
+
+		for _, clusterStatus := range status.Clusters {
+			if clusterStatus.Wave != status.CurrentWave || clusterStatus.Phase == kubermaticv1.UpgradePlanClusterPhaseSkipped {
+				continue
+			}
+
+			waveEmpty = false
+			if clusterStatus.Phase != kubermaticv1.UpgradePlanClusterPhaseUpgraded {
+				waveUpgraded = false
+			}
+		}
+
+		if !waveUpgraded {
+			status.Phase = kubermaticv1.UpgradePlanPhaseProgressing
+			status.Message = fmt.Sprintf("Upgrading wave %q.", wave.Name)
+			status.WaveCompletionTime = nil
+			return
+		}
+
+		if status.WaveCompletionTime == nil {
+			status.WaveCompletionTime = &metav1.Time{Time: now}
+		}
+
+		if !waveEmpty && now.Before(status.WaveCompletionTime.Add(wave.SoakTime.Duration)) {
+			status.Phase = kubermaticv1.UpgradePlanPhaseSoaking
+			status.Message = fmt.Sprintf("Wave %q has been upgraded, waiting for its soak time to pass.", wave.Name)
+			return
+		}
+
+		if status.CurrentWave >= len(plan.Spec.Waves)-1 {
+			status.Phase = kubermaticv1.UpgradePlanPhaseCompleted
+			status.Message = "All waves have been upgraded."
+			return
+		}
+
+		status.CurrentWave++
+		status.WaveCompletionTime = nil
+	}
+}
+
+func healthTimeout(plan *kubermaticv1.UpgradePlan) time.Duration {
+	if plan.Spec.HealthTimeout != nil && plan.Spec.HealthTimeout.Duration > 0 {
+		return plan.Spec.HealthTimeout.Duration
+	}
+
+	return defaultHealthTimeout
+}
+
+func sortedClusterNames[T any](clusters map[string]T) []string {
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// requeueTimer keeps track of the earliest point in time a reconciliation is needed.
+type requeueTimer struct {
+	duration time.Duration
+}
+
+func (t *requeueTimer) after(d time.Duration) {
+	// make sure to not requeue immediately due to rounding
+	if d < time.Second {
+		d = time.Second
+	}
+
+	if t.duration == 0 || d < t.duration {
+		t.duration = d
+	}
+}
@@ -0,0 +1,421 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeplancontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestUpdateWindowOpen(t *testing.T) {
+	// 2023-03-15 is a Wednesday
+	now := time.Date(2023, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name     string
+		window   *kubermaticv1.UpdateWindow
+		open     bool
+		opensIn  time.Duration
+		location *time.Location
+	}{
+		{
+			name:   "no window",
+			window: nil,
+			open:   true,
+		},
+		{
+			name:   "daily window is open",
+			window: &kubermaticv1.UpdateWindow{Start: "10:00", Length: "1h"},
+			open:   true,
+		},
+		{
+			name:    "daily window has passed",
+			window:  &kubermaticv1.UpdateWindow{Start: "08:00", Length: "2h"},
+			open:    false,
+			opensIn: 21*time.Hour + 30*time.Minute,
+		},
+		{
+			name:    "daily window opens later today",
+			window:  &kubermaticv1.UpdateWindow{Start: "22:00", Length: "2h"},
+			open:    false,
+			opensIn: 11*time.Hour + 30*time.Minute,
+		},
+		{
+			name:   "daily window spanning midnight is open",
+			window: &kubermaticv1.UpdateWindow{Start: "22:00", Length: "13h"},
+			open:   true,
+		},
+		{
+			name:   "weekly window is open",
+			window: &kubermaticv1.UpdateWindow{Start: "Mon 00:00", Length: "72h"},
+			open:   true,
+		},
+		{
+			name:    "weekly window opens on the next day",
+			window:  &kubermaticv1.UpdateWindow{Start: "Thu 10:30", Length: "1h"},
+			open:    false,
+			opensIn: 24 * time.Hour,
+		},
+		{
+			name:    "weekly window opens next week",
+			window:  &kubermaticv1.UpdateWindow{Start: "Wed 09:00", Length: "1h"},
+			open:    false,
+			opensIn: 7*24*time.Hour - 90*time.Minute,
+		},
+		{
+			name:     "time is converted to UTC",
+			window:   &kubermaticv1.UpdateWindow{Start: "10:00", Length: "1h"},
+			open:     true,
+			location: time.FixedZone("UTC+2", 2*60*60),
+		},
+		{
+			name:   "invalid window",
+			window: &kubermaticv1.UpdateWindow{Start: "Someday 10:00", Length: "1h"},
+			open:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := now
+			if tc.location != nil {
+				current = now.In(tc.location)
+			}
+
+			open, opensIn := updateWindowOpen(tc.window, current)
+			if open != tc.open {
+				t.Fatalf("Expected open to be %v, but got %v.", tc.open, open)
+			}
+			if opensIn != tc.opensIn {
+				t.Fatalf("Expected window to open in %v, but got %v.", tc.opensIn, opensIn)
+			}
+		})
+	}
+}
+
+func healthyCluster(name string, version string) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"env": "prod"},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Version: *semver.NewSemverOrDie(version),
+		},
+		Status: kubermaticv1.ClusterStatus{
+			Versions: kubermaticv1.ClusterVersionsStatus{
+				ControlPlane: *semver.NewSemverOrDie(version),
+			},
+			ExtendedHealth: kubermaticv1.ExtendedClusterHealth{
+				Apiserver:                    kubermaticv1.HealthStatusUp,
+				Scheduler:                    kubermaticv1.HealthStatusUp,
+				Controller:                   kubermaticv1.HealthStatusUp,
+				MachineController:            kubermaticv1.HealthStatusUp,
+				Etcd:                         kubermaticv1.HealthStatusUp,
+				CloudProviderInfrastructure:  kubermaticv1.HealthStatusUp,
+				UserClusterControllerManager: kubermaticv1.HealthStatusUp,
+			},
+		},
+	}
+}
+
+func TestReconcileRollout(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2023, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+	plan := &kubermaticv1.UpgradePlan{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "prod",
+		},
+		Spec: kubermaticv1.UpgradePlanSpec{
+			ClusterSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"env": "prod"},
+			},
+			Version: *semver.NewSemverOrDie("1.26.1"),
+			Waves: []kubermaticv1.UpgradePlanWave{
+				{Name: "canary", Size: 1, SoakTime: metav1.Duration{Duration: time.Hour}},
+				{Name: "rest"},
+			},
+		},
+	}
+
+	unselected := healthyCluster("other", "1.25.5")
+	unselected.Labels = nil
+
+	client := fake.NewClientBuilder().WithObjects(
+		plan,
+		healthyCluster("a", "1.25.5"),
+		healthyCluster("b", "1.25.5"),
+		healthyCluster("c", "1.25.5"),
+		healthyCluster("d", "1.26.1"),
+		unselected,
+	).Build()
+
+	clock := clocktesting.NewFakeClock(start)
+	r := &Reconciler{
+		Client:   client,
+		recorder: record.NewFakeRecorder(20),
+		log:      zap.NewNop().Sugar(),
+		clock:    clock,
+	}
+
+	reconcilePlan := func() *kubermaticv1.UpgradePlan {
+		t.Helper()
+
+		if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: plan.Name}}); err != nil {
+			t.Fatalf("Reconciling failed: %v", err)
+		}
+
+		current := &kubermaticv1.UpgradePlan{}
+		if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(plan), current); err != nil {
+			t.Fatalf("Failed to get UpgradePlan: %v", err)
+		}
+
+		return current
+	}
+
+	updateCluster := func(name string, modify func(*kubermaticv1.Cluster)) {
+		t.Helper()
+
+		cluster := &kubermaticv1.Cluster{}
+		if err := client.Get(ctx, types.NamespacedName{Name: name}, cluster); err != nil {
+			t.Fatalf("Failed to get cluster: %v", err)
+		}
+
+		modify(cluster)
+
+		if err := client.Update(ctx, cluster); err != nil {
+			t.Fatalf("Failed to update cluster: %v", err)
+		}
+	}
+
+	assertClusterPhase := func(plan *kubermaticv1.UpgradePlan, name string, wave int, phase kubermaticv1.UpgradePlanClusterPhase) {
+		t.Helper()
+
+		status, ok := plan.Status.Clusters[name]
+		if !ok {
+			t.Fatalf("Expected cluster %s to be part of the plan, but it is not.", name)
+		}
+		if status.Wave != wave || status.Phase != phase {
+			t.Fatalf("Expected cluster %s to be %s in wave %d, but it is %s in wave %d.", name, phase, wave, status.Phase, status.Wave)
+		}
+	}
+
+	assertClusterVersion := func(name string, version string) {
+		t.Helper()
+
+		cluster := &kubermaticv1.Cluster{}
+		if err := client.Get(ctx, types.NamespacedName{Name: name}, cluster); err != nil {
+			t.Fatalf("Failed to get cluster: %v", err)
+		}
+		if cluster.Spec.Version.String() != version {
+			t.Fatalf("Expected cluster %s to have version %s, but has %s.", name, version, cluster.Spec.Version.String())
+		}
+	}
+
+	// the canary wave is started
+	current := reconcilePlan()
+	if current.Status.Phase != kubermaticv1.UpgradePlanPhaseProgressing {
+		t.Fatalf("Expected plan to be progressing, but it is %s.", current.Status.Phase)
+	}
+	if len(current.Status.Clusters) != 4 {
+		t.Fatalf("Expected 4 clusters in the plan, but got %d.", len(current.Status.Clusters))
+	}
+
+	assertClusterPhase(current, "a", 0, kubermaticv1.UpgradePlanClusterPhaseUpgrading)
+	assertClusterPhase(current, "b", 1, kubermaticv1.UpgradePlanClusterPhasePending)
+	assertClusterPhase(current, "c", 1, kubermaticv1.UpgradePlanClusterPhasePending)
+	assertClusterPhase(current, "d", 0, kubermaticv1.UpgradePlanClusterPhaseSkipped)
+	assertClusterVersion("a", "1.26.1")
+	assertClusterVersion("b", "1.25.5")
+
+	// the canary has been upgraded, the plan waits for the soak time
+	updateCluster("a", func(c *kubermaticv1.Cluster) {
+		c.Status.Versions.ControlPlane = c.Spec.Version
+	})
+	clock.Step(10 * time.Minute)
+
+	current = reconcilePlan()
+	if current.Status.Phase != kubermaticv1.UpgradePlanPhaseSoaking {
+		t.Fatalf("Expected plan to be soaking, but it is %s.", current.Status.Phase)
+	}
+	assertClusterPhase(current, "a", 0, kubermaticv1.UpgradePlanClusterPhaseUpgraded)
+	assertClusterVersion("b", "1.25.5")
+
+	// once the soak time has passed, the remaining clusters are upgraded,
+	// unless they are outside of their update window
+	updateCluster("c", func(c *kubermaticv1.Cluster) {
+		c.Spec.UpdateWindow = &kubermaticv1.UpdateWindow{Start: "22:00", Length: "2h"}
+	})
+	clock.Step(time.Hour)
+
+	current = reconcilePlan()
+	if current.Status.CurrentWave != 1 || current.Status.Phase != kubermaticv1.UpgradePlanPhaseProgressing {
+		t.Fatalf("Expected plan to be progressing in wave 1, but it is %s in wave %d.", current.Status.Phase, current.Status.CurrentWave)
+	}
+	assertClusterPhase(current, "b", 1, kubermaticv1.UpgradePlanClusterPhaseUpgrading)
+	assertClusterPhase(current, "c", 1, kubermaticv1.UpgradePlanClusterPhasePending)
+	assertClusterVersion("b", "1.26.1")
+	assertClusterVersion("c", "1.25.5")
+
+	// a failed update pauses the plan
+	updateCluster("b", func(c *kubermaticv1.Cluster) {
+		c.Status.Conditions = map[kubermaticv1.ClusterConditionType]kubermaticv1.ClusterCondition{
+			kubermaticv1.ClusterConditionUpdateProgress: {
+				Status:  corev1.ConditionFalse,
+				Message: "etcd is broken",
+			},
+		}
+	})
+	clock.Step(time.Minute)
+
+	current = reconcilePlan()
+	if current.Status.Phase != kubermaticv1.UpgradePlanPhasePaused {
+		t.Fatalf("Expected plan to be paused, but it is %s.", current.Status.Phase)
+	}
+	assertClusterPhase(current, "b", 1, kubermaticv1.UpgradePlanClusterPhaseFailed)
+
+	// the window of cluster c opens, but the plan is still paused
+	clock.Step(12 * time.Hour)
+
+	current = reconcilePlan()
+	assertClusterPhase(current, "c", 1, kubermaticv1.UpgradePlanClusterPhasePending)
+	assertClusterVersion("c", "1.25.5")
+
+	// cluster b recovers and the rollout continues
+	updateCluster("b", func(c *kubermaticv1.Cluster) {
+		c.Status.Conditions = nil
+		c.Status.Versions.ControlPlane = c.Spec.Version
+	})
+
+	current = reconcilePlan()
+	assertClusterPhase(current, "b", 1, kubermaticv1.UpgradePlanClusterPhaseUpgraded)
+	assertClusterPhase(current, "c", 1, kubermaticv1.UpgradePlanClusterPhaseUpgrading)
+	assertClusterVersion("c", "1.26.1")
+
+	updateCluster("c", func(c *kubermaticv1.Cluster) {
+		c.Status.Versions.ControlPlane = c.Spec.Version
+	})
+
+	current = reconcilePlan()
+	if current.Status.Phase != kubermaticv1.UpgradePlanPhaseCompleted {
+		t.Fatalf("Expected plan to be completed, but it is %s.", current.Status.Phase)
+	}
+}
+
+func TestUpgradeTimeout(t *testing.T) {
+	now := time.Date(2023, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+	plan := &kubermaticv1.UpgradePlan{
+		Spec: kubermaticv1.UpgradePlanSpec{
+			Version:       *semver.NewSemverOrDie("1.26.1"),
+			Waves:         []kubermaticv1.UpgradePlanWave{{Name: "all"}},
+			HealthTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+		},
+		Status: kubermaticv1.UpgradePlanStatus{
+			Clusters: map[string]kubermaticv1.UpgradePlanClusterStatus{
+				"a": {
+					Phase:     kubermaticv1.UpgradePlanClusterPhaseUpgrading,
+					StartTime: &metav1.Time{Time: now.Add(-5 * time.Minute)},
+				},
+			},
+		},
+	}
+
+	clusters := map[string]*kubermaticv1.Cluster{
+		"a": healthyCluster("a", "1.25.5"),
+	}
+
+	toUpgrade, requeueAfter := updateStatus(plan, clusters, now)
+	if len(toUpgrade) > 0 {
+		t.Fatalf("Expected no clusters to be upgraded, but got %v.", toUpgrade)
+	}
+	if requeueAfter != 5*time.Minute {
+		t.Fatalf("Expected to requeue after the remaining health timeout, but got %v.", requeueAfter)
+	}
+	if phase := plan.Status.Clusters["a"].Phase; phase != kubermaticv1.UpgradePlanClusterPhaseUpgrading {
+		t.Fatalf("Expected cluster to still be upgrading, but it is %s.", phase)
+	}
+
+	updateStatus(plan, clusters, now.Add(5*time.Minute))
+	if phase := plan.Status.Clusters["a"].Phase; phase != kubermaticv1.UpgradePlanClusterPhaseFailed {
+		t.Fatalf("Expected cluster to have failed, but it is %s.", phase)
+	}
+	if plan.Status.Phase != kubermaticv1.UpgradePlanPhasePaused {
+		t.Fatalf("Expected plan to be paused, but it is %s.", plan.Status.Phase)
+	}
+}
+
+func TestRemovedWaves(t *testing.T) {
+	now := time.Date(2023, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+	// the plan was in its third wave when the waves were reduced to one
+	plan := &kubermaticv1.UpgradePlan{
+		Spec: kubermaticv1.UpgradePlanSpec{
+			Version: *semver.NewSemverOrDie("1.26.1"),
+			Waves:   []kubermaticv1.UpgradePlanWave{{Name: "all"}},
+		},
+		Status: kubermaticv1.UpgradePlanStatus{
+			Phase:       kubermaticv1.UpgradePlanPhaseSoaking,
+			CurrentWave: 2,
+			Clusters: map[string]kubermaticv1.UpgradePlanClusterStatus{
+				"a": {Wave: 0, Phase: kubermaticv1.UpgradePlanClusterPhaseUpgraded},
+				"b": {Wave: 2, Phase: kubermaticv1.UpgradePlanClusterPhaseUpgraded},
+				"c": {Wave: 3, Phase: kubermaticv1.UpgradePlanClusterPhasePending},
+			},
+			WaveCompletionTime: &metav1.Time{Time: now.Add(-time.Minute)},
+		},
+	}
+
+	clusters := map[string]*kubermaticv1.Cluster{
+		"a": healthyCluster("a", "1.26.1"),
+		"b": healthyCluster("b", "1.26.1"),
+		"c": healthyCluster("c", "1.25.5"),
+	}
+
+	toUpgrade, _ := updateStatus(plan, clusters, now)
+
+	if plan.Status.CurrentWave != 0 {
+		t.Fatalf("Expected plan to be moved to the last wave, but it is in wave %d.", plan.Status.CurrentWave)
+	}
+	if plan.Status.Phase != kubermaticv1.UpgradePlanPhaseProgressing {
+		t.Fatalf("Expected plan to be progressing, but it is %s.", plan.Status.Phase)
+	}
+	if len(toUpgrade) != 1 || toUpgrade[0] != "c" {
+		t.Fatalf("Expected cluster c of a removed wave to be upgraded, but got %v.", toUpgrade)
+	}
+	for name, clusterStatus := range plan.Status.Clusters {
+		if clusterStatus.Wave != 0 {
+			t.Errorf("Expected cluster %s to be moved to the last wave, but it is in wave %d.", name, clusterStatus.Wave)
+		}
+	}
+}
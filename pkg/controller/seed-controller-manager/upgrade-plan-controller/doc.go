@@ -0,0 +1,24 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package upgradeplancontroller contains a controller that rolls out UpgradePlans.
+It assigns the selected clusters to the plan's waves and bumps the cluster.Spec.Version
+of one wave after another, respecting the clusters' update windows. The actual upgrade
+of the control plane is left to the update controller; this controller only watches the
+cluster health and pauses the rollout if an upgraded cluster fails.
+*/
+package upgradeplancontroller
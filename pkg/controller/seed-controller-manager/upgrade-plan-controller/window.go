@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgradeplancontroller
+
+import (
+	"strings"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// updateWindowOpen checks whether the given update window is open at the given time,
+// interpreting the window in UTC. If the window is closed, the duration until it
+// opens the next time is returned as well. Clusters without an update window (or
+// with an invalid one, which is prevented by the validation webhook) can always
+// be upgraded.
+func updateWindowOpen(window *kubermaticv1.UpdateWindow, now time.Time) (bool, time.Duration) {
+	if window == nil || window.Start == "" || window.Length == "" {
+		return true, 0
+	}
+
+	length, err := time.ParseDuration(window.Length)
+	if err != nil || length <= 0 {
+		return true, 0
+	}
+
+	start := window.Start
+	weekly := false
+
+	var weekday time.Weekday
+	if day, clock, found := strings.Cut(start, " "); found {
+		weekday, weekly = weekdays[day]
+		if !weekly {
+			return true, 0
+		}
+		start = clock
+	}
+
+	timeOfDay, err := time.Parse("15:04", start)
+	if err != nil {
+		return true, 0
+	}
+
+	now = now.UTC()
+
+	period := 24 * time.Hour
+	if weekly {
+		period *= 7
+	}
+
+	// find the most recent start of the window that is not in the future
+	lastStart := time.Date(now.Year(), now.Month(), now.Day(), timeOfDay.Hour(), timeOfDay.Minute(), 0, 0, time.UTC)
+	if weekly {
+		lastStart = lastStart.AddDate(0, 0, int(weekday)-int(now.Weekday()))
+	}
+	for lastStart.After(now) {
+		lastStart = lastStart.Add(-period)
+	}
+
+	if now.Before(lastStart.Add(length)) {
+		return true, 0
+	}
+
+	return false, lastStart.Add(period).Sub(now)
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationdriftcontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications"
+	userclustercontrollermanager "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "kkp-app-drift-controller"
+
+	// defaultInterval is the interval at which the drift is checked if spec.driftDetection.interval is not set.
+	defaultInterval = 10 * time.Minute
+
+	// Reason of the Drifted condition when no drift has been detected.
+	noDriftReason = "NoDrift"
+
+	// Reason of the Drifted condition when a drift has been detected and not corrected.
+	driftDetectedReason = "DriftDetected"
+
+	// Reason of the Drifted condition when a drift has been detected and corrected.
+	driftCorrectedReason = "DriftCorrected"
+
+	// Reason of the Drifted condition when a drift has been detected but could not be corrected.
+	driftCorrectionFailedReason = "DriftCorrectionFailed"
+
+	// Event raised when a drift has been detected.
+	applicationDriftDetectedEvent = "ApplicationDriftDetected"
+
+	// Event raised when a drift has been corrected.
+	applicationDriftCorrectedEvent = "ApplicationDriftCorrected"
+
+	// Event raised when the drift detection failed.
+	applicationDriftDetectionFailedEvent = "ApplicationDriftDetectionFailed"
+)
+
+type reconciler struct {
+	log             *zap.SugaredLogger
+	seedClient      ctrlruntimeclient.Client
+	userClient      ctrlruntimeclient.Client
+	userRecorder    record.EventRecorder
+	clusterIsPaused userclustercontrollermanager.IsPausedChecker
+	appInstaller    applications.ApplicationInstaller
+}
+
+func Add(ctx context.Context, log *zap.SugaredLogger, seedMgr, userMgr manager.Manager, clusterIsPaused userclustercontrollermanager.IsPausedChecker, appInstaller applications.ApplicationInstaller) error {
+	log = log.Named(controllerName)
+
+	r := &reconciler{
+		log:             log,
+		seedClient:      seedMgr.GetClient(),
+		userClient:      userMgr.GetClient(),
+		userRecorder:    userMgr.GetEventRecorderFor(controllerName),
+		clusterIsPaused: clusterIsPaused,
+		appInstaller:    appInstaller,
+	}
+
+	c, err := controller.New(controllerName, userMgr, controller.Options{
+		Reconciler: r,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller %s: %w", controllerName, err)
+	}
+
+	// The drift is checked periodically (see RequeueAfter) and as soon as the application has been (re)installed. Other
+	// status updates (including the ones made by this controller) are filtered out.
+	if err = c.Watch(&source.Kind{Type: &appskubermaticv1.ApplicationInstallation{}}, &handler.EnqueueRequestForObject{}, predicate.Or(predicate.GenerationChangedPredicate{}, readyConditionChangedPredicate())); err != nil {
+		return fmt.Errorf("failed to create watch for ApplicationInstallation: %w", err)
+	}
+
+	return nil
+}
+
+// readyConditionChangedPredicate filters update events where the Ready condition has not changed. The heartbeat time is
+// not taken into account.
+func readyConditionChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldApp, okOld := e.ObjectOld.(*appskubermaticv1.ApplicationInstallation)
+			newApp, okNew := e.ObjectNew.(*appskubermaticv1.ApplicationInstallation)
+			if !okOld || !okNew {
+				return false
+			}
+			oldCond := oldApp.Status.Conditions[appskubermaticv1.Ready]
+			newCond := newApp.Status.Conditions[appskubermaticv1.Ready]
+			return oldCond.Status != newCond.Status || oldCond.Reason != newCond.Reason || oldCond.ObservedGeneration != newCond.ObservedGeneration
+		},
+	}
+}
+
+// Reconcile checks whether the objects deployed by the ApplicationInstallation have drifted.
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("applicationinstallation", request)
+	log.Debug("Processing")
+
+	paused, err := r.clusterIsPaused(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to check cluster pause status: %w", err)
+	}
+	if paused {
+		return reconcile.Result{}, nil
+	}
+
+	appInstallation := &appskubermaticv1.ApplicationInstallation{}
+	if err := r.userClient.Get(ctx, request.NamespacedName, appInstallation); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debug("applicationInstallation not found, returning")
+			deleteDriftedObjectsMetric(request.NamespacedName)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("failed to get applicationInstallation: %w", err)
+	}
+
+	result, err := r.reconcile(ctx, log, appInstallation)
+	if err != nil {
+		log.Errorw("ReconcilingError", zap.Error(err))
+		r.userRecorder.Event(appInstallation, corev1.EventTypeWarning, applicationDriftDetectionFailedEvent, err.Error())
+	}
+
+	log.Debug("Processed")
+	return result, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, appInstallation *appskubermaticv1.ApplicationInstallation) (reconcile.Result, error) {
+	key := ctrlruntimeclient.ObjectKeyFromObject(appInstallation)
+
+	driftDetection := appInstallation.Spec.DriftDetection
+	if driftDetection == nil || !appInstallation.DeletionTimestamp.IsZero() {
+		deleteDriftedObjectsMetric(key)
+		return reconcile.Result{}, r.removeDriftedCondition(ctx, appInstallation)
+	}
+
+	// The drift is only meaningful once the current spec has been successfully deployed. When the application is
+	// (re)installed, the Ready condition changes and triggers a new reconciliation.
+	if !isDeployed(appInstallation) {
+		log.Debug("application is not deployed, skipping drift detection")
+		return reconcile.Result{}, nil
+	}
+
+	appDefinition := &appskubermaticv1.ApplicationDefinition{}
+	if err := r.seedClient.Get(ctx, types.NamespacedName{Name: appInstallation.Spec.ApplicationRef.Name}, appDefinition); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get applicationDefinition: %w", err)
+	}
+
+	drifts, err := r.appInstaller.DetectDrift(ctx, log, r.seedClient, r.userClient, appDefinition, appInstallation)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to detect drift: %w", err)
+	}
+	setDriftedObjectsMetric(key, len(drifts))
+
+	var status corev1.ConditionStatus
+	var reason, message string
+	var correctionErr error
+	switch {
+	case len(drifts) == 0:
+		status, reason, message = corev1.ConditionFalse, noDriftReason, applications.SummarizeDrift(drifts)
+
+	case driftDetection.Policy == appskubermaticv1.DriftPolicyCorrect:
+		log.Infow("Drift detected, correcting it", "objects", len(drifts))
+		if correctionErr = r.appInstaller.CorrectDrift(ctx, log, r.userClient, drifts); correctionErr != nil {
+			status, reason, message = corev1.ConditionTrue, driftCorrectionFailedReason, fmt.Sprintf("%s. Correction failed: %s", applications.SummarizeDrift(drifts), correctionErr)
+		} else {
+			setDriftedObjectsMetric(key, 0)
+			status, reason, message = corev1.ConditionFalse, driftCorrectedReason, "corrected "+applications.SummarizeDrift(drifts)
+			r.userRecorder.Event(appInstallation, corev1.EventTypeNormal, applicationDriftCorrectedEvent, message)
+		}
+
+	default:
+		status, reason, message = corev1.ConditionTrue, driftDetectedReason, applications.SummarizeDrift(drifts)
+	}
+
+	oldCondition := appInstallation.Status.Conditions[appskubermaticv1.Drifted]
+	if reason == driftDetectedReason && oldCondition.Reason != driftDetectedReason {
+		r.userRecorder.Event(appInstallation, corev1.EventTypeWarning, applicationDriftDetectedEvent, message)
+	}
+	if err := r.setDriftedCondition(ctx, appInstallation, status, reason, message); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if correctionErr != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to correct drift: %w", correctionErr)
+	}
+	return reconcile.Result{RequeueAfter: interval(driftDetection)}, nil
+}
+
+// setDriftedCondition updates the Drifted condition. To avoid useless writes, the status is only patched if the
+// condition has changed.
+func (r *reconciler) setDriftedCondition(ctx context.Context, appInstallation *appskubermaticv1.ApplicationInstallation, status corev1.ConditionStatus, reason string, message string) error {
+	oldCondition, found := appInstallation.Status.Conditions[appskubermaticv1.Drifted]
+	if found && oldCondition.Status == status && oldCondition.Reason == reason && oldCondition.Message == message && oldCondition.ObservedGeneration == appInstallation.Generation {
+		return nil
+	}
+
+	oldAppInstallation := appInstallation.DeepCopy()
+	appInstallation.SetCondition(appskubermaticv1.Drifted, status, reason, message)
+	if err := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	return nil
+}
+
+// removeDriftedCondition removes the Drifted condition, e.g. when drift detection has been disabled.
+func (r *reconciler) removeDriftedCondition(ctx context.Context, appInstallation *appskubermaticv1.ApplicationInstallation) error {
+	if _, found := appInstallation.Status.Conditions[appskubermaticv1.Drifted]; !found {
+		return nil
+	}
+
+	oldAppInstallation := appInstallation.DeepCopy()
+	delete(appInstallation.Status.Conditions, appskubermaticv1.Drifted)
+	if err := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	return nil
+}
+
+// isDeployed returns true if the current spec of the application has been successfully deployed.
+func isDeployed(appInstallation *appskubermaticv1.ApplicationInstallation) bool {
+	condition := appInstallation.Status.Conditions[appskubermaticv1.Ready]
+	return condition.Status == corev1.ConditionTrue && condition.ObservedGeneration == appInstallation.Generation
+}
+
+// interval returns the interval at which the drift is checked.
+func interval(driftDetection *appskubermaticv1.DriftDetection) time.Duration {
+	if driftDetection.Interval.Duration <= 0 {
+		return defaultInterval
+	}
+	return driftDetection.Interval.Duration
+}
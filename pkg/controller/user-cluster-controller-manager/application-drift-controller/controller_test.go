@@ -0,0 +1,237 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationdriftcontroller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications"
+	"k8c.io/kubermatic/v2/pkg/applications/fake"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	utilruntime.Must(appskubermaticv1.AddToScheme(scheme.Scheme))
+}
+
+const applicationNamespace = "apps"
+
+func TestReconcile(t *testing.T) {
+	drifted := []applications.ObjectDrift{{Object: genConfigMap(), Fields: []string{"data.key"}}}
+
+	testCases := []struct {
+		name                  string
+		driftDetection        *appskubermaticv1.DriftDetection
+		ready                 bool
+		existingCondition     *appskubermaticv1.ApplicationInstallationCondition
+		drifts                []applications.ObjectDrift
+		correctErr            error
+		wantErr               bool
+		expectDetection       bool
+		expectCorrection      bool
+		expectedCondition     *appskubermaticv1.ApplicationInstallationCondition
+		expectedMetric        float64
+		expectedRequeueAfter  time.Duration
+		expectMetricToBeEmpty bool
+	}{
+		{
+			name:                 "scenario 1: no drift",
+			driftDetection:       &appskubermaticv1.DriftDetection{Policy: appskubermaticv1.DriftPolicyReport},
+			ready:                true,
+			expectDetection:      true,
+			expectedCondition:    &appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: noDriftReason},
+			expectedRequeueAfter: defaultInterval,
+		},
+		{
+			name:                 "scenario 2: drift is reported with policy report",
+			driftDetection:       &appskubermaticv1.DriftDetection{Policy: appskubermaticv1.DriftPolicyReport, Interval: metav1.Duration{Duration: 5 * time.Minute}},
+			ready:                true,
+			drifts:               drifted,
+			expectDetection:      true,
+			expectedCondition:    &appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionTrue, Reason: driftDetectedReason, Message: "1 object(s) drifted: ConfigMap default/config (data.key)"},
+			expectedMetric:       1,
+			expectedRequeueAfter: 5 * time.Minute,
+		},
+		{
+			name:                 "scenario 3: drift is corrected with policy correct",
+			driftDetection:       &appskubermaticv1.DriftDetection{Policy: appskubermaticv1.DriftPolicyCorrect},
+			ready:                true,
+			drifts:               drifted,
+			expectDetection:      true,
+			expectCorrection:     true,
+			expectedCondition:    &appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: driftCorrectedReason, Message: "corrected 1 object(s) drifted: ConfigMap default/config (data.key)"},
+			expectedRequeueAfter: defaultInterval,
+		},
+		{
+			name:              "scenario 4: failed correction is reported",
+			driftDetection:    &appskubermaticv1.DriftDetection{Policy: appskubermaticv1.DriftPolicyCorrect},
+			ready:             true,
+			drifts:            drifted,
+			correctErr:        errors.New("conflict"),
+			wantErr:           true,
+			expectDetection:   true,
+			expectCorrection:  true,
+			expectedCondition: &appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionTrue, Reason: driftCorrectionFailedReason, Message: "1 object(s) drifted: ConfigMap default/config (data.key). Correction failed: conflict"},
+			expectedMetric:    1,
+		},
+		{
+			name:                  "scenario 5: drift is not checked if the application is not deployed",
+			driftDetection:        &appskubermaticv1.DriftDetection{Policy: appskubermaticv1.DriftPolicyReport},
+			ready:                 false,
+			expectMetricToBeEmpty: true,
+		},
+		{
+			name:                  "scenario 6: condition is removed when drift detection is disabled",
+			ready:                 true,
+			existingCondition:     &appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionTrue, Reason: driftDetectedReason},
+			expectMetricToBeEmpty: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			driftedObjects.Reset()
+
+			app := genApplicationInstallation(tc.driftDetection, tc.ready)
+			if tc.existingCondition != nil {
+				app.Status.Conditions[appskubermaticv1.Drifted] = *tc.existingCondition
+			}
+			userClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(app).Build()
+			seedClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(genApplicationDefinition()).Build()
+
+			detected, corrected := false, false
+			appInstaller := fake.CustomApplicationInstaller{
+				DetectDriftFunc: func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation) ([]applications.ObjectDrift, error) {
+					detected = true
+					return tc.drifts, nil
+				},
+				CorrectDriftFunc: func(ctx context.Context, log *zap.SugaredLogger, userClient ctrlruntimeclient.Client, drifts []applications.ObjectDrift) error {
+					corrected = true
+					return tc.correctErr
+				},
+			}
+			r := reconciler{log: kubermaticlog.Logger, seedClient: seedClient, userClient: userClient, appInstaller: appInstaller, userRecorder: record.NewFakeRecorder(10)}
+
+			result, err := r.reconcile(ctx, kubermaticlog.Logger, app)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error=%v, got %v", tc.wantErr, err)
+			}
+			if detected != tc.expectDetection {
+				t.Errorf("expected drift detection=%v, got %v", tc.expectDetection, detected)
+			}
+			if corrected != tc.expectCorrection {
+				t.Errorf("expected drift correction=%v, got %v", tc.expectCorrection, corrected)
+			}
+			if result.RequeueAfter != tc.expectedRequeueAfter {
+				t.Errorf("expected requeue after %v, got %v", tc.expectedRequeueAfter, result.RequeueAfter)
+			}
+
+			updatedApp := &appskubermaticv1.ApplicationInstallation{}
+			if err := userClient.Get(ctx, types.NamespacedName{Namespace: applicationNamespace, Name: "app"}, updatedApp); err != nil {
+				t.Fatalf("failed to get applicationInstallation: %v", err)
+			}
+			condition, found := updatedApp.Status.Conditions[appskubermaticv1.Drifted]
+			switch {
+			case tc.expectedCondition == nil && found:
+				t.Errorf("expected no Drifted condition, got %+v", condition)
+			case tc.expectedCondition != nil && !found:
+				t.Errorf("expected Drifted condition %+v, got none", tc.expectedCondition)
+			case tc.expectedCondition != nil:
+				if condition.Status != tc.expectedCondition.Status || condition.Reason != tc.expectedCondition.Reason || (tc.expectedCondition.Message != "" && condition.Message != tc.expectedCondition.Message) {
+					t.Errorf("expected Drifted condition %+v, got %+v", tc.expectedCondition, condition)
+				}
+			}
+
+			if tc.expectMetricToBeEmpty {
+				if count := testutil.CollectAndCount(driftedObjects); count != 0 {
+					t.Errorf("expected no metric, got %d", count)
+				}
+			} else if value := testutil.ToFloat64(driftedObjects.WithLabelValues(applicationNamespace, "app")); value != tc.expectedMetric {
+				t.Errorf("expected %v drifted objects in metric, got %v", tc.expectedMetric, value)
+			}
+		})
+	}
+}
+
+func genApplicationInstallation(driftDetection *appskubermaticv1.DriftDetection, ready bool) *appskubermaticv1.ApplicationInstallation {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	return &appskubermaticv1.ApplicationInstallation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  applicationNamespace,
+			Generation: 1,
+		},
+		Spec: appskubermaticv1.ApplicationInstallationSpec{
+			Namespace:      appskubermaticv1.AppNamespaceSpec{Name: "default"},
+			ApplicationRef: appskubermaticv1.ApplicationRef{Name: "app-def", Version: "1.0.0"},
+			DriftDetection: driftDetection,
+		},
+		Status: appskubermaticv1.ApplicationInstallationStatus{
+			Method: appskubermaticv1.ManifestTemplateMethod,
+			Conditions: map[appskubermaticv1.ApplicationInstallationConditionType]appskubermaticv1.ApplicationInstallationCondition{
+				appskubermaticv1.Ready: {Status: readyStatus, ObservedGeneration: 1},
+			},
+		},
+	}
+}
+
+func genApplicationDefinition() *appskubermaticv1.ApplicationDefinition {
+	return &appskubermaticv1.ApplicationDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-def"},
+		Spec: appskubermaticv1.ApplicationDefinitionSpec{
+			Method: appskubermaticv1.ManifestTemplateMethod,
+			Versions: []appskubermaticv1.ApplicationVersion{{
+				Version: "1.0.0",
+				Template: appskubermaticv1.ApplicationTemplate{
+					Source: appskubermaticv1.ApplicationSource{
+						Git: &appskubermaticv1.GitSource{Remote: "https://git.local/app", Ref: appskubermaticv1.GitReference{Tag: "v1.0.0"}},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func genConfigMap() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "config", "namespace": "default"},
+		"data":       map[string]interface{}{"key": "value"},
+	}}
+}
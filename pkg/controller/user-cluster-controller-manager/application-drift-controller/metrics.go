@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationdriftcontroller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimemetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var driftedObjects = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "kubermatic",
+		Subsystem: "application_installation",
+		Name:      "drifted_objects",
+		Help:      "The number of objects deployed by an ApplicationInstallation that have drifted from their deployed state",
+	},
+	[]string{"namespace", "name"},
+)
+
+func init() {
+	// the user-cluster-controller-manager only serves the metrics of the controller-runtime registry.
+	ctrlruntimemetrics.Registry.MustRegister(driftedObjects)
+}
+
+func setDriftedObjectsMetric(key types.NamespacedName, count int) {
+	driftedObjects.WithLabelValues(key.Namespace, key.Name).Set(float64(count))
+}
+
+func deleteDriftedObjectsMetric(key types.NamespacedName) {
+	driftedObjects.DeleteLabelValues(key.Namespace, key.Name)
+}
@@ -20,12 +20,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"go.uber.org/zap"
 
 	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/apis/equality"
 	"k8c.io/kubermatic/v2/pkg/applications"
+	"k8c.io/kubermatic/v2/pkg/applications/helmclient"
 	userclustercontrollermanager "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 
@@ -60,6 +62,12 @@ const (
 
 	// maxRetries is the maximum number of retries on installation or upgrade failure.
 	maxRetries = 5
+
+	// Reason of the Ready condition when the application waits for its dependencies to be ready before being installed.
+	waitingForDependenciesReason = "WaitingForDependencies"
+
+	// Reason of the Ready condition when the application waits for the applications depending on it to be removed before being uninstalled.
+	waitingForDependentsReason = "WaitingForDependents"
 )
 
 type reconciler struct {
@@ -108,6 +116,13 @@ func Add(ctx context.Context, log *zap.SugaredLogger, seedMgr, userMgr manager.M
 		return fmt.Errorf("failed to watch applicationDefinition: %w", err)
 	}
 
+	// ApplicationInstallations wait for their dependencies to be ready before being installed, and for their dependents
+	// to be removed before being uninstalled. So any change (including status) of an ApplicationInstallation is fanned
+	// out to the ApplicationInstallations related to it.
+	if err = c.Watch(&source.Kind{Type: &appskubermaticv1.ApplicationInstallation{}}, handler.EnqueueRequestsFromMapFunc(enqueueRelatedAppInstallations(ctx, r.userClient))); err != nil {
+		return fmt.Errorf("failed to create watch for ApplicationInstallation dependencies: %w", err)
+	}
+
 	return nil
 }
 
@@ -180,6 +195,14 @@ func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, appI
 		return r.userClient.Delete(ctx, appInstallation)
 	}
 
+	// the application is pinned to a revision of its history.
+	if appInstallation.Spec.RollbackTo != "" {
+		if err := r.handleRollback(ctx, log, applicationDef, appInstallation); err != nil {
+			return fmt.Errorf("handling rollback of application installation: %w", err)
+		}
+		return nil
+	}
+
 	// get applicationVersion. If it can not be found, there are 2 cases:
 	//   1) KKP admin has removed the applicationVersion, and we have to remove the corresponding ApplicationInstallation(s)
 	//   2) User made a mistake, or applicationDefinition has not been synced yet on this seed. So we just notify the user.
@@ -203,6 +226,15 @@ func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, appI
 		}
 	}
 
+	// wait for the dependencies to be ready before installing or upgrading the application.
+	dependenciesReady, err := r.checkDependencies(ctx, log, appInstallation)
+	if err != nil {
+		return err
+	}
+	if !dependenciesReady {
+		return nil
+	}
+
 	// install application into the user-cluster
 	if err := r.handleInstallation(ctx, log, applicationDef, appInstallation); err != nil {
 		return fmt.Errorf("handling installation of application installation: %w", err)
@@ -211,6 +243,91 @@ func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, appI
 	return nil
 }
 
+// checkDependencies returns true if all the ApplicationInstallations appInstallation depends on are ready. Otherwise,
+// the Ready condition of appInstallation is set to False with the list of dependencies not ready yet.
+func (r *reconciler) checkDependencies(ctx context.Context, log *zap.SugaredLogger, appInstallation *appskubermaticv1.ApplicationInstallation) (bool, error) {
+	var notReady []string
+	for _, name := range appInstallation.Spec.DependsOn {
+		dependency := &appskubermaticv1.ApplicationInstallation{}
+		if err := r.userClient.Get(ctx, types.NamespacedName{Namespace: appInstallation.Namespace, Name: name}, dependency); err != nil {
+			if apierrors.IsNotFound(err) {
+				notReady = append(notReady, fmt.Sprintf("%s (not found)", name))
+				continue
+			}
+			return false, fmt.Errorf("failed to get dependency '%s': %w", name, err)
+		}
+		if !isReady(dependency) {
+			notReady = append(notReady, name)
+		}
+	}
+
+	if len(notReady) == 0 {
+		return true, nil
+	}
+
+	message := "waiting for dependencies to be ready: " + strings.Join(notReady, ", ")
+	log.Debugw("Dependencies are not ready, postponing installation", "dependencies", notReady)
+	return false, r.setNotReadyCondition(ctx, appInstallation, waitingForDependenciesReason, message)
+}
+
+// checkDependents returns true if no other ApplicationInstallation depends on appInstallation. Otherwise, the Ready
+// condition of appInstallation is set to False with the list of dependents that must be removed first.
+func (r *reconciler) checkDependents(ctx context.Context, log *zap.SugaredLogger, appInstallation *appskubermaticv1.ApplicationInstallation) (bool, error) {
+	appList := &appskubermaticv1.ApplicationInstallationList{}
+	if err := r.userClient.List(ctx, appList, ctrlruntimeclient.InNamespace(appInstallation.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list applicationInstallations: %w", err)
+	}
+
+	var dependents []string
+	for _, app := range appList.Items {
+		if app.Name != appInstallation.Name && dependsOn(&app, appInstallation.Name) {
+			dependents = append(dependents, app.Name)
+		}
+	}
+
+	if len(dependents) == 0 {
+		return true, nil
+	}
+
+	message := "waiting for dependent applications to be removed: " + strings.Join(dependents, ", ")
+	log.Debugw("Application is still required by other applications, postponing uninstallation", "dependents", dependents)
+	return false, r.setNotReadyCondition(ctx, appInstallation, waitingForDependentsReason, message)
+}
+
+// setNotReadyCondition sets the Ready condition to False with reason and message. To avoid updating the status in loop,
+// the status is only patched if the condition has changed.
+func (r *reconciler) setNotReadyCondition(ctx context.Context, appInstallation *appskubermaticv1.ApplicationInstallation, reason, message string) error {
+	condition, exists := appInstallation.Status.Conditions[appskubermaticv1.Ready]
+	if exists && condition.Status == corev1.ConditionFalse && condition.Reason == reason && condition.Message == message && condition.ObservedGeneration == appInstallation.Generation {
+		return nil
+	}
+
+	oldAppInstallation := appInstallation.DeepCopy()
+	appInstallation.SetCondition(appskubermaticv1.Ready, corev1.ConditionFalse, reason, message)
+	if err := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	return nil
+}
+
+// isReady returns true if the application has been successfully installed with its current spec and is not being deleted.
+func isReady(appInstallation *appskubermaticv1.ApplicationInstallation) bool {
+	condition := appInstallation.Status.Conditions[appskubermaticv1.Ready]
+	return appInstallation.DeletionTimestamp.IsZero() &&
+		condition.Status == corev1.ConditionTrue &&
+		condition.ObservedGeneration == appInstallation.Generation
+}
+
+// dependsOn returns true if appInstallation depends on the ApplicationInstallation named name.
+func dependsOn(appInstallation *appskubermaticv1.ApplicationInstallation, name string) bool {
+	for _, dependency := range appInstallation.Spec.DependsOn {
+		if dependency == name {
+			return true
+		}
+	}
+	return false
+}
+
 // getApplicationVersion finds the applicationVersion defined by appInstallation into the applicationDef and updates the struct appVersion with it.
 // An error is returned if the applicationVersion is not found.
 func (r *reconciler) getApplicationVersion(appInstallation *appskubermaticv1.ApplicationInstallation, applicationDef *appskubermaticv1.ApplicationDefinition, appVersion *appskubermaticv1.ApplicationVersion) error {
@@ -230,6 +347,12 @@ func (r *reconciler) handleInstallation(ctx context.Context, log *zap.SugaredLog
 		return err
 	}
 
+	// The application has been rolled back after too many failures. Wait for the spec to change before trying again.
+	if isAutoRolledBack(appInstallation) {
+		log.Debug("Application has been automatically rolled back. Do not reconcile application until its spec changes")
+		return nil
+	}
+
 	// Install or upgrade application only if max number of retries is not exceeded.
 	if appInstallation.Status.Failures > maxRetries && hasLimitedRetries(appDefinition, appInstallation) {
 		oldAppInstallation := appInstallation.DeepCopy()
@@ -273,13 +396,21 @@ func (r *reconciler) handleInstallation(ctx context.Context, log *zap.SugaredLog
 	statusUpdater, installErr := r.appInstaller.Apply(ctx, log, r.seedClient, r.userClient, appDefinition, appInstallation, appSourcePath)
 
 	statusUpdater(&appInstallation.Status)
-	appInstallation.SetReadyCondition(installErr, hasLimitedRetries(appDefinition, appInstallation))
+	// failures are also counted when auto-rollback is enabled to know when to roll back.
+	appInstallation.SetReadyCondition(installErr, hasLimitedRetries(appDefinition, appInstallation) || appInstallation.Spec.AutoRollback != nil)
+	if installErr == nil {
+		recordRevision(appInstallation, helmMaxHistory(appDefinition, appInstallation), appInstallation.Spec.ApplicationRef.Version, valuesHash(appInstallation))
+	}
 
 	// we set condition in every case and condition update the LastHeartbeatTime. So patch will not be empty.
 	if err := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 
+	if installErr != nil && shouldAutoRollback(appInstallation) {
+		return r.autoRollback(ctx, log, appDefinition, appInstallation, installErr)
+	}
+
 	return installErr
 }
 
@@ -297,6 +428,20 @@ func hasLimitedRetries(appDefinition *appskubermaticv1.ApplicationDefinition, ap
 	return false
 }
 
+// helmMaxHistory returns the number of revisions Helm keeps for the release of the application.
+func helmMaxHistory(appDefinition *appskubermaticv1.ApplicationDefinition, appInstallation *appskubermaticv1.ApplicationInstallation) int {
+	maxHistory := 0
+	if appInstallation.Spec.DeployOptions != nil && appInstallation.Spec.DeployOptions.Helm != nil {
+		maxHistory = appInstallation.Spec.DeployOptions.Helm.MaxHistory
+	} else if appDefinition.Spec.DefaultDeployOptions != nil && appDefinition.Spec.DefaultDeployOptions.Helm != nil {
+		maxHistory = appDefinition.Spec.DefaultDeployOptions.Helm.MaxHistory
+	}
+	if maxHistory == 0 {
+		return helmclient.DefaultMaxHistory
+	}
+	return maxHistory
+}
+
 // resetFailuresIfSpecHasChanged set Status.Failures to 0 if the spec has changed. Returns an error if status can not be updated.
 func (r reconciler) resetFailuresIfSpecHasChanged(ctx context.Context, appInstallation *appskubermaticv1.ApplicationInstallation) error {
 	oldAppInstallation := appInstallation.DeepCopy()
@@ -312,6 +457,15 @@ func (r reconciler) resetFailuresIfSpecHasChanged(ctx context.Context, appInstal
 // handleDeletion uninstalls the application in the user cluster.
 func (r *reconciler) handleDeletion(ctx context.Context, log *zap.SugaredLogger, appInstallation *appskubermaticv1.ApplicationInstallation) error {
 	if kuberneteshelper.HasFinalizer(appInstallation, appskubermaticv1.ApplicationInstallationCleanupFinalizer) {
+		// applications are uninstalled in the reverse order of installation.
+		noDependents, err := r.checkDependents(ctx, log, appInstallation)
+		if err != nil {
+			return err
+		}
+		if !noDependents {
+			return nil
+		}
+
 		statusUpdater, uninstallErr := r.appInstaller.Delete(ctx, log, r.seedClient, r.userClient, appInstallation)
 		oldAppInstallation := appInstallation.DeepCopy()
 		if uninstallErr != nil {
@@ -357,3 +511,35 @@ func enqueueAppInstallationForAppDef(ctx context.Context, userClient ctrlruntime
 		return res
 	}
 }
+
+// enqueueRelatedAppInstallations fan-out updates from an ApplicationInstallation to the ApplicationInstallations that
+// depend on it (so they can be installed once it is ready) and, if it's being deleted, to the ApplicationInstallations
+// it depends on (so they can be uninstalled once it is removed).
+func enqueueRelatedAppInstallations(ctx context.Context, userClient ctrlruntimeclient.Client) func(object ctrlruntimeclient.Object) []reconcile.Request {
+	return func(object ctrlruntimeclient.Object) []reconcile.Request {
+		appInstallation, ok := object.(*appskubermaticv1.ApplicationInstallation)
+		if !ok {
+			return []reconcile.Request{}
+		}
+
+		var res []reconcile.Request
+		if !appInstallation.DeletionTimestamp.IsZero() {
+			for _, dependency := range appInstallation.Spec.DependsOn {
+				res = append(res, reconcile.Request{NamespacedName: types.NamespacedName{Name: dependency, Namespace: appInstallation.Namespace}})
+			}
+		}
+
+		appList := &appskubermaticv1.ApplicationInstallationList{}
+		if err := userClient.List(ctx, appList, ctrlruntimeclient.InNamespace(appInstallation.Namespace)); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to list applicationInstallation: %w", err))
+			return res
+		}
+
+		for _, app := range appList.Items {
+			if app.Name != appInstallation.Name && dependsOn(&app, appInstallation.Name) {
+				res = append(res, reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: app.Namespace}})
+			}
+		}
+		return res
+	}
+}
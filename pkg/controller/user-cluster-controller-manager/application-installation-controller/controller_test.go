@@ -456,3 +456,188 @@ func TestHasLimitedRetries(t *testing.T) {
 		})
 	}
 }
+
+func TestEnqueueRelatedAppInstallations(t *testing.T) {
+	deleting := func(app *appskubermaticv1.ApplicationInstallation) *appskubermaticv1.ApplicationInstallation {
+		now := metav1.Now()
+		app.DeletionTimestamp = &now
+		return app
+	}
+
+	testCases := []struct {
+		name                      string
+		appInstallation           *appskubermaticv1.ApplicationInstallation
+		userClient                ctrlruntimeclient.Client
+		expectedReconcileRequests []reconcile.Request
+	}{
+		{
+			name:            "scenario 1: applications depending on 'cert-manager' are enqueued",
+			appInstallation: genApplicationInstallationWithDependencies("cert-manager"),
+			userClient: fakectrlruntimeclient.
+				NewClientBuilder().
+				WithObjects(
+					genApplicationInstallationWithDependencies("cert-manager"),
+					genApplicationInstallationWithDependencies("issuer", "cert-manager"),
+					genApplicationInstallationWithDependencies("ingress", "cert-manager", "issuer"),
+					genApplicationInstallationWithDependencies("other")).
+				Build(),
+			expectedReconcileRequests: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "issuer", Namespace: applicationNamespace}},
+				{NamespacedName: types.NamespacedName{Name: "ingress", Namespace: applicationNamespace}},
+			},
+		},
+		{
+			name:            "scenario 2: dependencies of an application being deleted are enqueued",
+			appInstallation: deleting(genApplicationInstallationWithDependencies("ingress", "cert-manager", "issuer")),
+			userClient: fakectrlruntimeclient.
+				NewClientBuilder().
+				WithObjects(
+					genApplicationInstallationWithDependencies("cert-manager"),
+					genApplicationInstallationWithDependencies("issuer", "cert-manager")).
+				Build(),
+			expectedReconcileRequests: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "cert-manager", Namespace: applicationNamespace}},
+				{NamespacedName: types.NamespacedName{Name: "issuer", Namespace: applicationNamespace}},
+			},
+		},
+		{
+			name:            "scenario 3: dependencies of an application not being deleted are not enqueued",
+			appInstallation: genApplicationInstallationWithDependencies("ingress", "cert-manager"),
+			userClient: fakectrlruntimeclient.
+				NewClientBuilder().
+				WithObjects(genApplicationInstallationWithDependencies("cert-manager")).
+				Build(),
+			expectedReconcileRequests: []reconcile.Request{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			actual := enqueueRelatedAppInstallations(context.Background(), tc.userClient)(tc.appInstallation)
+
+			g.Expect(actual).Should(gomega.ConsistOf(tc.expectedReconcileRequests))
+		})
+	}
+}
+
+func TestInstallationWaitsForDependencies(t *testing.T) {
+	ready := func(app *appskubermaticv1.ApplicationInstallation) *appskubermaticv1.ApplicationInstallation {
+		app.SetCondition(appskubermaticv1.Ready, corev1.ConditionTrue, "InstallationSuccessful", "application successfully installed or upgraded")
+		return app
+	}
+
+	testCases := []struct {
+		name              string
+		dependencies      []ctrlruntimeclient.Object
+		expectInstalled   bool
+		expectedCondition appskubermaticv1.ApplicationInstallationCondition
+	}{
+		{
+			name:              "scenario 1: application is installed when all dependencies are ready",
+			dependencies:      []ctrlruntimeclient.Object{ready(genApplicationInstallationWithDependencies("dep-1")), ready(genApplicationInstallationWithDependencies("dep-2"))},
+			expectInstalled:   true,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionTrue, Reason: "InstallationSuccessful"},
+		},
+		{
+			name:              "scenario 2: application is not installed when a dependency is not ready",
+			dependencies:      []ctrlruntimeclient.Object{ready(genApplicationInstallationWithDependencies("dep-1")), genApplicationInstallationWithDependencies("dep-2")},
+			expectInstalled:   false,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: waitingForDependenciesReason, Message: "waiting for dependencies to be ready: dep-2"},
+		},
+		{
+			name:              "scenario 3: application is not installed when a dependency does not exist",
+			dependencies:      []ctrlruntimeclient.Object{ready(genApplicationInstallationWithDependencies("dep-1"))},
+			expectInstalled:   false,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: waitingForDependenciesReason, Message: "waiting for dependencies to be ready: dep-2 (not found)"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			seedClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(genApplicationDefinition("app-def-1")).Build()
+			userClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithObjects(append(tc.dependencies, genApplicationInstallationWithDependencies("app", "dep-1", "dep-2"))...).
+				Build()
+			appInstaller := &fake.ApplicationInstallerRecorder{}
+			r := reconciler{log: kubermaticlog.Logger, seedClient: seedClient, userClient: userClient, appInstaller: appInstaller}
+
+			appInstall := &appskubermaticv1.ApplicationInstallation{}
+			if err := userClient.Get(ctx, types.NamespacedName{Name: "app", Namespace: applicationNamespace}, appInstall); err != nil {
+				t.Fatalf("failed to get application installation: %v", err)
+			}
+			if err := r.reconcile(ctx, kubermaticlog.Logger, appInstall); err != nil {
+				t.Fatalf("expect no error but error '%v' was raised'", err)
+			}
+
+			if _, installed := appInstaller.ApplyEvents.Load("app"); installed != tc.expectInstalled {
+				t.Errorf("expected application installed=%v, got %v", tc.expectInstalled, installed)
+			}
+
+			if err := userClient.Get(ctx, types.NamespacedName{Name: "app", Namespace: applicationNamespace}, appInstall); err != nil {
+				t.Fatalf("failed to get application installation: %v", err)
+			}
+			condition := appInstall.Status.Conditions[appskubermaticv1.Ready]
+			if condition.Status != tc.expectedCondition.Status || condition.Reason != tc.expectedCondition.Reason {
+				t.Errorf("expected ready condition status='%v' and reason='%v', got '%v' and '%v'", tc.expectedCondition.Status, tc.expectedCondition.Reason, condition.Status, condition.Reason)
+			}
+			if tc.expectedCondition.Message != "" && condition.Message != tc.expectedCondition.Message {
+				t.Errorf("expected ready condition message='%v', got '%v'", tc.expectedCondition.Message, condition.Message)
+			}
+		})
+	}
+}
+
+func TestUninstallationWaitsForDependents(t *testing.T) {
+	testCases := []struct {
+		name              string
+		objects           []ctrlruntimeclient.Object
+		expectUninstalled bool
+	}{
+		{
+			name:              "scenario 1: application is uninstalled when no other application depends on it",
+			objects:           []ctrlruntimeclient.Object{genApplicationInstallationWithDependencies("other", "unrelated")},
+			expectUninstalled: true,
+		},
+		{
+			name:              "scenario 2: application is not uninstalled while another application depends on it",
+			objects:           []ctrlruntimeclient.Object{genApplicationInstallationWithDependencies("dependent", "app")},
+			expectUninstalled: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			app := genApplicationInstallationWithDependencies("app")
+			now := metav1.Now()
+			app.DeletionTimestamp = &now
+			app.Finalizers = []string{appskubermaticv1.ApplicationInstallationCleanupFinalizer}
+
+			userClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(append(tc.objects, app)...).Build()
+			appInstaller := &fake.ApplicationInstallerRecorder{}
+			r := reconciler{log: kubermaticlog.Logger, userClient: userClient, appInstaller: appInstaller}
+
+			if err := r.handleDeletion(ctx, kubermaticlog.Logger, app); err != nil {
+				t.Fatalf("expect no error but error '%v' was raised'", err)
+			}
+
+			if _, uninstalled := appInstaller.DeleteEvents.Load("app"); uninstalled != tc.expectUninstalled {
+				t.Errorf("expected application uninstalled=%v, got %v", tc.expectUninstalled, uninstalled)
+			}
+			if !tc.expectUninstalled && app.Status.Conditions[appskubermaticv1.Ready].Reason != waitingForDependentsReason {
+				t.Errorf("expected ready condition reason='%v', got '%v'", waitingForDependentsReason, app.Status.Conditions[appskubermaticv1.Ready].Reason)
+			}
+		})
+	}
+}
+
+func genApplicationInstallationWithDependencies(name string, dependencies ...string) *appskubermaticv1.ApplicationInstallation {
+	app := genApplicationInstallation(name, "app-def-1", "1.0.0", 0, 1, 1)
+	app.Spec.DependsOn = dependencies
+	return app
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationinstallationcontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Reason of the Ready condition when the application has been rolled back to the revision defined in spec.rollbackTo.
+	rolledBackReason = "RolledBack"
+
+	// Reason of the Ready condition when the application could not be rolled back.
+	rollbackFailedReason = "RollbackFailed"
+
+	// Reason of the Ready condition when the application has been automatically rolled back after too many failures.
+	autoRolledBackReason = "AutoRolledBack"
+
+	// Event raised when an applicationInstallation has been automatically rolled back.
+	applicationAutoRolledBackEvent = "ApplicationAutoRolledBack"
+
+	// defaultAutoRollbackAfterFailures is the number of failures after which the application is rolled back if
+	// spec.autoRollback.afterFailures is not set.
+	defaultAutoRollbackAfterFailures = 3
+)
+
+// handleRollback rolls back the application to the revision defined in spec.rollbackTo. Nothing is done if this
+// revision is already the deployed one.
+func (r *reconciler) handleRollback(ctx context.Context, log *zap.SugaredLogger, appDefinition *appskubermaticv1.ApplicationDefinition, appInstallation *appskubermaticv1.ApplicationInstallation) error {
+	revisionName := appInstallation.Spec.RollbackTo
+	revision := appInstallation.GetRevision(revisionName)
+	if revision == nil {
+		// retrying won't help, the user has to fix the spec.
+		log.Infow("Revision to roll back to not found in history", "revision", revisionName)
+		return r.setNotReadyCondition(ctx, appInstallation, rollbackFailedReason, fmt.Sprintf("revision '%s' not found in history", revisionName))
+	}
+
+	condition := appInstallation.Status.Conditions[appskubermaticv1.Ready]
+	if isDeployedRevision(appInstallation, revision) && condition.Status == corev1.ConditionTrue && condition.Reason == rolledBackReason && condition.ObservedGeneration == appInstallation.Generation {
+		return nil
+	}
+
+	oldAppInstallation := appInstallation.DeepCopy()
+	var rollbackErr error
+	if !isDeployedRevision(appInstallation, revision) {
+		log.Infow("Rolling back application", "revision", revisionName, "helmReleaseRevision", revision.HelmReleaseRevision)
+		rollbackErr = r.rollback(ctx, log, appDefinition, appInstallation, *revision)
+	}
+
+	if rollbackErr != nil {
+		appInstallation.SetCondition(appskubermaticv1.Ready, corev1.ConditionFalse, rollbackFailedReason, rollbackErr.Error())
+	} else {
+		appInstallation.SetCondition(appskubermaticv1.Ready, corev1.ConditionTrue, rolledBackReason, fmt.Sprintf("application rolled back to revision '%s'", revisionName))
+	}
+	if err := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	return rollbackErr
+}
+
+// autoRollback rolls back the application to the last successfully deployed revision after installErr. The application
+// is then not reconciled anymore until its spec changes.
+func (r *reconciler) autoRollback(ctx context.Context, log *zap.SugaredLogger, appDefinition *appskubermaticv1.ApplicationDefinition, appInstallation *appskubermaticv1.ApplicationInstallation, installErr error) error {
+	revision := appInstallation.Status.History[0]
+	log.Infow("Too many failures, rolling back application", "failures", appInstallation.Status.Failures, "revision", revision.Name)
+
+	oldAppInstallation := appInstallation.DeepCopy()
+	if err := r.rollback(ctx, log, appDefinition, appInstallation, revision); err != nil {
+		appInstallation.SetCondition(appskubermaticv1.Ready, corev1.ConditionFalse, rollbackFailedReason, err.Error())
+		if patchErr := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); patchErr != nil {
+			return fmt.Errorf("failed to update status: %w", patchErr)
+		}
+		return err
+	}
+
+	message := fmt.Sprintf("application rolled back to revision '%s' after %d failed installations or upgrades. Last error: %s", revision.Name, appInstallation.Status.Failures, installErr)
+	appInstallation.SetCondition(appskubermaticv1.Ready, corev1.ConditionFalse, autoRolledBackReason, message)
+	if err := r.userClient.Status().Patch(ctx, appInstallation, ctrlruntimeclient.MergeFrom(oldAppInstallation)); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+
+	r.traceWarning(appInstallation, log, applicationAutoRolledBackEvent, message)
+	return nil
+}
+
+// rollback rolls back the application to revision and records it as the most recent revision in the history.
+// The status is only updated in memory.
+func (r *reconciler) rollback(ctx context.Context, log *zap.SugaredLogger, appDefinition *appskubermaticv1.ApplicationDefinition, appInstallation *appskubermaticv1.ApplicationInstallation, revision appskubermaticv1.ApplicationInstallationRevision) error {
+	if revision.HelmReleaseRevision == 0 {
+		return fmt.Errorf("can not roll back to revision '%s': revision has no helm release", revision.Name)
+	}
+
+	statusUpdater, err := r.appInstaller.Rollback(ctx, log, r.seedClient, r.userClient, appDefinition, appInstallation, revision.HelmReleaseRevision)
+	statusUpdater(&appInstallation.Status)
+	if err != nil {
+		return fmt.Errorf("failed to roll back to revision '%s': %w", revision.Name, err)
+	}
+
+	recordRevision(appInstallation, helmMaxHistory(appDefinition, appInstallation), revision.ApplicationVersion, revision.ValuesHash)
+	return nil
+}
+
+// shouldAutoRollback returns true if the auto-rollback policy is enabled, the number of consecutive failures has been
+// reached and there is a revision to roll back to.
+func shouldAutoRollback(appInstallation *appskubermaticv1.ApplicationInstallation) bool {
+	policy := appInstallation.Spec.AutoRollback
+	if policy == nil || len(appInstallation.Status.History) == 0 {
+		return false
+	}
+
+	afterFailures := policy.AfterFailures
+	if afterFailures <= 0 {
+		afterFailures = defaultAutoRollbackAfterFailures
+	}
+	return appInstallation.Status.Failures >= afterFailures
+}
+
+// isAutoRolledBack returns true if the application has been automatically rolled back and its spec has not changed since.
+func isAutoRolledBack(appInstallation *appskubermaticv1.ApplicationInstallation) bool {
+	condition := appInstallation.Status.Conditions[appskubermaticv1.Ready]
+	return appInstallation.Spec.AutoRollback != nil &&
+		condition.Reason == autoRolledBackReason &&
+		condition.ObservedGeneration == appInstallation.Generation
+}
+
+// isDeployedRevision returns true if revision is the revision currently deployed.
+func isDeployedRevision(appInstallation *appskubermaticv1.ApplicationInstallation, revision *appskubermaticv1.ApplicationInstallationRevision) bool {
+	return appInstallation.Status.HelmRelease != nil && appInstallation.Status.HelmRelease.Version == revision.HelmReleaseRevision
+}
+
+// recordRevision records the deployment of the application with the given version and values hash in the history.
+// Revisions whose helm release has been purged by Helm, which keeps maxHistory revisions, are removed from the history
+// as they can not be rolled back to.
+func recordRevision(appInstallation *appskubermaticv1.ApplicationInstallation, maxHistory int, version string, hash string) {
+	revision := appskubermaticv1.ApplicationInstallationRevision{
+		Name:               revisionName(version, hash),
+		ApplicationVersion: version,
+		ValuesHash:         hash,
+		DeployedAt:         metav1.Now(),
+	}
+	if appInstallation.Status.Method == appskubermaticv1.HelmTemplateMethod && appInstallation.Status.HelmRelease != nil {
+		revision.HelmReleaseRevision = appInstallation.Status.HelmRelease.Version
+	}
+	appInstallation.AddRevision(revision)
+
+	if revision.HelmReleaseRevision > 0 {
+		// Helm never purges the release that was deployed before the new one, so at least two revisions are kept.
+		if maxHistory < 2 {
+			maxHistory = 2
+		}
+		oldestKeptRevision := revision.HelmReleaseRevision - maxHistory + 1
+		history := appInstallation.Status.History[:0]
+		for _, rev := range appInstallation.Status.History {
+			if rev.HelmReleaseRevision == 0 || rev.HelmReleaseRevision >= oldestKeptRevision {
+				history = append(history, rev)
+			}
+		}
+		appInstallation.Status.History = history
+	}
+}
+
+// revisionName returns the name of the revision for an application version and values hash (i.e. <version>-<hash[:8]>).
+func revisionName(version string, hash string) string {
+	if len(hash) > 8 {
+		hash = hash[:8]
+	}
+	return version + "-" + hash
+}
+
+// valuesHash returns the sha256 hash of the values of the application. Values are normalized before being hashed so
+// that formatting or key order does not change the hash.
+func valuesHash(appInstallation *appskubermaticv1.ApplicationInstallation) string {
+	data := appInstallation.Spec.Values.Raw
+	values := map[string]interface{}{}
+	if len(data) == 0 || json.Unmarshal(data, &values) == nil {
+		// json.Marshal sorts map keys.
+		if normalized, err := json.Marshal(values); err == nil {
+			data = normalized
+		}
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
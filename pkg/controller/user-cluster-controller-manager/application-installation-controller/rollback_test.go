@@ -0,0 +1,379 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationinstallationcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/applications/fake"
+	"k8c.io/kubermatic/v2/pkg/applications/helmclient"
+	"k8c.io/kubermatic/v2/pkg/applications/providers/util"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecordRevision(t *testing.T) {
+	app := genApplicationInstallation("app", "app-def-1", "1.0.0", 0, 1, 1)
+	app.Status.Method = appskubermaticv1.HelmTemplateMethod
+
+	const maxHistory = 5
+
+	deploy := func(version string, hash string, helmRevision int) {
+		app.Status.HelmRelease = &appskubermaticv1.HelmRelease{Version: helmRevision}
+		recordRevision(app, maxHistory, version, hash)
+	}
+
+	deploy("1.0.0", "aaaaaaaaaaaa", 1)
+	deploy("2.0.0", "aaaaaaaaaaaa", 2)
+	deploy("1.0.0", "bbbbbbbbbbbb", 3)
+	assertHistory(t, app, "1.0.0-bbbbbbbb", "2.0.0-aaaaaaaa", "1.0.0-aaaaaaaa")
+
+	// deploying an existing revision again moves it to the top of the history.
+	deploy("2.0.0", "aaaaaaaaaaaa", 4)
+	assertHistory(t, app, "2.0.0-aaaaaaaa", "1.0.0-bbbbbbbb", "1.0.0-aaaaaaaa")
+	if app.Status.History[0].HelmReleaseRevision != 4 {
+		t.Errorf("expected helm release revision of the redeployed revision to be 4, got %d", app.Status.History[0].HelmReleaseRevision)
+	}
+
+	// revisions whose helm release has been purged are removed from the history.
+	deploy("2.0.0", "aaaaaaaaaaaa", maxHistory+2)
+	assertHistory(t, app, "2.0.0-aaaaaaaa", "1.0.0-bbbbbbbb")
+
+	// with the default max history, Helm still keeps the previously deployed release.
+	app.Status.HelmRelease = &appskubermaticv1.HelmRelease{Version: maxHistory + 3}
+	recordRevision(app, helmclient.DefaultMaxHistory, "1.0.0", "dddddddddddd")
+	assertHistory(t, app, "1.0.0-dddddddd", "2.0.0-aaaaaaaa")
+
+	// the history is bounded.
+	for i := 0; i < 2*appskubermaticv1.ApplicationInstallationMaxHistory; i++ {
+		app.Status.HelmRelease = nil
+		recordRevision(app, maxHistory, fmt.Sprintf("3.0.%d", i), "cccccccccccc")
+	}
+	if len(app.Status.History) != appskubermaticv1.ApplicationInstallationMaxHistory {
+		t.Errorf("expected history to hold %d revisions, got %d", appskubermaticv1.ApplicationInstallationMaxHistory, len(app.Status.History))
+	}
+}
+
+func TestValuesHash(t *testing.T) {
+	withValues := func(values string) *appskubermaticv1.ApplicationInstallation {
+		app := genApplicationInstallation("app", "app-def-1", "1.0.0", 0, 1, 1)
+		app.Spec.Values = runtime.RawExtension{Raw: []byte(values)}
+		return app
+	}
+
+	if valuesHash(withValues(`{"a": 1, "b": {"c": true}}`)) != valuesHash(withValues(`{"b":{"c":true},"a":1}`)) {
+		t.Error("expected the hash to not depend on formatting or key order")
+	}
+	if valuesHash(withValues("")) != valuesHash(withValues("{}")) {
+		t.Error("expected empty values to have the same hash as an empty object")
+	}
+	if valuesHash(withValues(`{"a": 1}`)) == valuesHash(withValues(`{"a": 2}`)) {
+		t.Error("expected different values to have different hashes")
+	}
+}
+
+func TestRevisionIsRecordedOnInstallation(t *testing.T) {
+	ctx := context.Background()
+	app := genApplicationInstallation("app", "app-def-1", "1.0.0", 0, 1, 1)
+	app.Spec.Values = runtime.RawExtension{Raw: []byte(`{"key": "value"}`)}
+	userClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(app).Build()
+	seedClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(genApplicationDefinition("app-def-1")).Build()
+	appInstaller := fake.CustomApplicationInstaller{ApplyFunc: helmReleaseApplier(7, nil)}
+	r := reconciler{log: kubermaticlog.Logger, seedClient: seedClient, userClient: userClient, appInstaller: appInstaller}
+
+	if err := r.reconcile(ctx, kubermaticlog.Logger, getApplicationInstallation(t, userClient, "app")); err != nil {
+		t.Fatalf("expect no error but error '%v' was raised'", err)
+	}
+
+	app = getApplicationInstallation(t, userClient, "app")
+	assertHistory(t, app, revisionName("1.0.0", valuesHash(app)))
+	if revision := app.Status.History[0]; revision.HelmReleaseRevision != 7 || revision.ApplicationVersion != "1.0.0" {
+		t.Errorf("expected revision with version 1.0.0 and helm release revision 7, got %+v", revision)
+	}
+}
+
+func TestRollbackToRevision(t *testing.T) {
+	rollbackErr := errors.New("rollback error")
+
+	testCases := []struct {
+		name                 string
+		rollbackTo           string
+		deployedRevision     int
+		rollbackErr          error
+		wantErr              bool
+		expectedRollback     int
+		expectedCondition    appskubermaticv1.ApplicationInstallationCondition
+		expectedHistoryNames []string
+	}{
+		{
+			name:                 "scenario 1: application is rolled back to the revision",
+			rollbackTo:           "1.0.0-aaaaaaaa",
+			deployedRevision:     2,
+			expectedRollback:     1,
+			expectedCondition:    appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionTrue, Reason: rolledBackReason},
+			expectedHistoryNames: []string{"1.0.0-aaaaaaaa", "2.0.0-bbbbbbbb"},
+		},
+		{
+			name:                 "scenario 2: nothing is done if the revision is already deployed",
+			rollbackTo:           "2.0.0-bbbbbbbb",
+			deployedRevision:     2,
+			expectedCondition:    appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionTrue, Reason: rolledBackReason},
+			expectedHistoryNames: []string{"2.0.0-bbbbbbbb", "1.0.0-aaaaaaaa"},
+		},
+		{
+			name:                 "scenario 3: revision does not exist",
+			rollbackTo:           "3.0.0-cccccccc",
+			deployedRevision:     2,
+			expectedCondition:    appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: rollbackFailedReason, Message: "revision '3.0.0-cccccccc' not found in history"},
+			expectedHistoryNames: []string{"2.0.0-bbbbbbbb", "1.0.0-aaaaaaaa"},
+		},
+		{
+			name:                 "scenario 4: rollback fails",
+			rollbackTo:           "1.0.0-aaaaaaaa",
+			deployedRevision:     2,
+			rollbackErr:          rollbackErr,
+			wantErr:              true,
+			expectedRollback:     1,
+			expectedCondition:    appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: rollbackFailedReason, Message: "failed to roll back to revision '1.0.0-aaaaaaaa': rollback error"},
+			expectedHistoryNames: []string{"2.0.0-bbbbbbbb", "1.0.0-aaaaaaaa"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			app := genApplicationInstallationWithHistory(tc.deployedRevision)
+			app.Spec.RollbackTo = tc.rollbackTo
+			userClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(app).Build()
+			seedClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(genApplicationDefinition("app-def-1")).Build()
+
+			rollbackRevision := 0
+			appInstaller := fake.CustomApplicationInstaller{
+				ApplyFunc: func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, appSourcePath string) (util.StatusUpdater, error) {
+					t.Fatal("application must not be installed while rollbackTo is set")
+					return util.NoStatusUpdate, nil
+				},
+				RollbackFunc: helmReleaseRollbacker(&rollbackRevision, tc.deployedRevision+1, tc.rollbackErr),
+			}
+			r := reconciler{log: kubermaticlog.Logger, seedClient: seedClient, userClient: userClient, appInstaller: appInstaller}
+
+			err := r.reconcile(ctx, kubermaticlog.Logger, getApplicationInstallation(t, userClient, "app"))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error=%v, got %v", tc.wantErr, err)
+			}
+
+			if rollbackRevision != tc.expectedRollback {
+				t.Errorf("expected rollback to helm release revision %d, got %d", tc.expectedRollback, rollbackRevision)
+			}
+
+			app = getApplicationInstallation(t, userClient, "app")
+			assertReadyCondition(t, app, tc.expectedCondition)
+			assertHistory(t, app, tc.expectedHistoryNames...)
+		})
+	}
+}
+
+func TestAutoRollback(t *testing.T) {
+	installError := errors.New("an install error")
+
+	testCases := []struct {
+		name              string
+		app               func() *appskubermaticv1.ApplicationInstallation
+		wantErr           bool
+		expectInstalled   bool
+		expectedRollback  int
+		expectedFailures  int
+		expectedCondition appskubermaticv1.ApplicationInstallationCondition
+	}{
+		{
+			name: "scenario 1: application is rolled back when the number of failures is reached",
+			app: func() *appskubermaticv1.ApplicationInstallation {
+				app := genApplicationInstallationWithHistory(3)
+				app.Spec.AutoRollback = &appskubermaticv1.AutoRollbackPolicy{AfterFailures: 2}
+				app.Status.Failures = 1
+				return app
+			},
+			expectInstalled:   true,
+			expectedRollback:  2,
+			expectedFailures:  2,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: autoRolledBackReason, Message: "application rolled back to revision '2.0.0-bbbbbbbb' after 2 failed installations or upgrades. Last error: an install error"},
+		},
+		{
+			name: "scenario 2: application is not rolled back when the number of failures is not reached",
+			app: func() *appskubermaticv1.ApplicationInstallation {
+				app := genApplicationInstallationWithHistory(3)
+				app.Spec.AutoRollback = &appskubermaticv1.AutoRollbackPolicy{}
+				app.Status.Failures = 1
+				return app
+			},
+			wantErr:           true,
+			expectInstalled:   true,
+			expectedFailures:  2,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: "InstallationFailed", Message: "an install error"},
+		},
+		{
+			name: "scenario 3: application is not reconciled after having been rolled back",
+			app: func() *appskubermaticv1.ApplicationInstallation {
+				app := genApplicationInstallationWithHistory(3)
+				app.Spec.AutoRollback = &appskubermaticv1.AutoRollbackPolicy{AfterFailures: 2}
+				app.Status.Failures = 2
+				app.SetCondition(appskubermaticv1.Ready, corev1.ConditionFalse, autoRolledBackReason, "rolled back")
+				return app
+			},
+			expectInstalled:   false,
+			expectedFailures:  2,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: autoRolledBackReason, Message: "rolled back"},
+		},
+		{
+			name: "scenario 4: application is not rolled back when the history is empty",
+			app: func() *appskubermaticv1.ApplicationInstallation {
+				app := genApplicationInstallation("app", "app-def-1", "1.0.0", 5, 1, 1)
+				app.Spec.DeployOptions = nil
+				app.Spec.AutoRollback = &appskubermaticv1.AutoRollbackPolicy{AfterFailures: 2}
+				return app
+			},
+			wantErr:           true,
+			expectInstalled:   true,
+			expectedFailures:  6,
+			expectedCondition: appskubermaticv1.ApplicationInstallationCondition{Status: corev1.ConditionFalse, Reason: "InstallationFailed", Message: "an install error"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			userClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.app()).Build()
+			seedClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(genApplicationDefinition("app-def-1")).Build()
+
+			installed := false
+			rollbackRevision := 0
+			appInstaller := fake.CustomApplicationInstaller{
+				ApplyFunc: func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, appSourcePath string) (util.StatusUpdater, error) {
+					installed = true
+					return helmReleaseStatusUpdater(4), installError
+				},
+				RollbackFunc: helmReleaseRollbacker(&rollbackRevision, 5, nil),
+			}
+			r := reconciler{log: kubermaticlog.Logger, seedClient: seedClient, userClient: userClient, appInstaller: appInstaller, userRecorder: record.NewFakeRecorder(10)}
+
+			err := r.reconcile(ctx, kubermaticlog.Logger, getApplicationInstallation(t, userClient, "app"))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error=%v, got %v", tc.wantErr, err)
+			}
+
+			if installed != tc.expectInstalled {
+				t.Errorf("expected application installed=%v, got %v", tc.expectInstalled, installed)
+			}
+			if rollbackRevision != tc.expectedRollback {
+				t.Errorf("expected rollback to helm release revision %d, got %d", tc.expectedRollback, rollbackRevision)
+			}
+
+			app := getApplicationInstallation(t, userClient, "app")
+			if app.Status.Failures != tc.expectedFailures {
+				t.Errorf("expected failures=%d, got %d", tc.expectedFailures, app.Status.Failures)
+			}
+			assertReadyCondition(t, app, tc.expectedCondition)
+			if tc.expectedRollback != 0 && app.Status.History[0].HelmReleaseRevision != 5 {
+				t.Errorf("expected the rolled back revision to be recorded with helm release revision 5, got %d", app.Status.History[0].HelmReleaseRevision)
+			}
+		})
+	}
+}
+
+// genApplicationInstallationWithHistory returns an installed ApplicationInstallation with 2 revisions in its history:
+// "2.0.0-bbbbbbbb" (helm release revision 2) and "1.0.0-aaaaaaaa" (helm release revision 1).
+func genApplicationInstallationWithHistory(deployedRevision int) *appskubermaticv1.ApplicationInstallation {
+	app := genApplicationInstallation("app", "app-def-1", "1.0.0", 0, 1, 1)
+	app.Spec.DeployOptions = nil
+	app.Status.Method = appskubermaticv1.HelmTemplateMethod
+	app.Status.ApplicationVersion = &genApplicationDefinition("app-def-1").Spec.Versions[0]
+	app.Status.HelmRelease = &appskubermaticv1.HelmRelease{Version: deployedRevision}
+	app.Status.History = []appskubermaticv1.ApplicationInstallationRevision{
+		{Name: "2.0.0-bbbbbbbb", ApplicationVersion: "2.0.0", ValuesHash: "bbbbbbbbbbbb", HelmReleaseRevision: 2},
+		{Name: "1.0.0-aaaaaaaa", ApplicationVersion: "1.0.0", ValuesHash: "aaaaaaaaaaaa", HelmReleaseRevision: 1},
+	}
+	return app
+}
+
+// helmReleaseStatusUpdater returns a StatusUpdater that sets the version of the helm release.
+func helmReleaseStatusUpdater(version int) util.StatusUpdater {
+	return func(status *appskubermaticv1.ApplicationInstallationStatus) {
+		status.HelmRelease = &appskubermaticv1.HelmRelease{Version: version}
+	}
+}
+
+// helmReleaseApplier returns an ApplyFunc that creates the helm release revision newRevision.
+func helmReleaseApplier(newRevision int, err error) func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, appSourcePath string) (util.StatusUpdater, error) {
+	return func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, appSourcePath string) (util.StatusUpdater, error) {
+		return helmReleaseStatusUpdater(newRevision), err
+	}
+}
+
+// helmReleaseRollbacker returns a RollbackFunc that records the revision rolled back to and creates the helm release
+// revision newRevision if err is nil.
+func helmReleaseRollbacker(rolledBackTo *int, newRevision int, err error) func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error) {
+	return func(ctx context.Context, log *zap.SugaredLogger, seedClient ctrlruntimeclient.Client, userClient ctrlruntimeclient.Client, appDefinition *appskubermaticv1.ApplicationDefinition, applicationInstallation *appskubermaticv1.ApplicationInstallation, revision int) (util.StatusUpdater, error) {
+		*rolledBackTo = revision
+		if err != nil {
+			return util.NoStatusUpdate, err
+		}
+		return helmReleaseStatusUpdater(newRevision), nil
+	}
+}
+
+func getApplicationInstallation(t *testing.T, userClient ctrlruntimeclient.Client, name string) *appskubermaticv1.ApplicationInstallation {
+	t.Helper()
+	app := &appskubermaticv1.ApplicationInstallation{}
+	if err := userClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: applicationNamespace}, app); err != nil {
+		t.Fatalf("failed to get application installation: %v", err)
+	}
+	return app
+}
+
+func assertReadyCondition(t *testing.T, app *appskubermaticv1.ApplicationInstallation, expected appskubermaticv1.ApplicationInstallationCondition) {
+	t.Helper()
+	condition := app.Status.Conditions[appskubermaticv1.Ready]
+	if condition.Status != expected.Status || condition.Reason != expected.Reason {
+		t.Errorf("expected ready condition status='%v' and reason='%v', got '%v' and '%v'", expected.Status, expected.Reason, condition.Status, condition.Reason)
+	}
+	if expected.Message != "" && condition.Message != expected.Message {
+		t.Errorf("expected ready condition message='%v', got '%v'", expected.Message, condition.Message)
+	}
+}
+
+func assertHistory(t *testing.T, app *appskubermaticv1.ApplicationInstallation, expectedNames ...string) {
+	t.Helper()
+	var names []string
+	for _, revision := range app.Status.History {
+		names = append(names, revision.Name)
+	}
+	if fmt.Sprint(names) != fmt.Sprint(expectedNames) {
+		t.Errorf("expected history %v, got %v", expectedNames, names)
+	}
+}
@@ -0,0 +1,409 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliancecontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	userclustercontrollermanager "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager"
+	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/compliance"
+	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
+	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/registry"
+	"k8c.io/kubermatic/v2/pkg/validation"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "kkp-compliance-controller"
+
+	// pollInterval is the time between two checks of running node scans.
+	pollInterval = 30 * time.Second
+
+	// maxNodes is the number of nodes per check that are stored in the report.
+	maxNodes = 10
+)
+
+type logsGetter func(ctx context.Context, namespace, name string) ([]byte, error)
+
+// reconciler runs the compliance scans of the user cluster and
+// stores their results in a ComplianceReport in the seed cluster.
+type reconciler struct {
+	log        *zap.SugaredLogger
+	seedClient ctrlruntimeclient.Client
+	// the report and control plane are read uncached to not act on an outdated scan time
+	seedReader ctrlruntimeclient.Reader
+	userClient ctrlruntimeclient.Client
+	// Jobs, Pods and Nodes are read uncached, as finished scans are deleted right away
+	userReader      ctrlruntimeclient.Reader
+	getLogs         logsGetter
+	imageRewriter   registry.ImageRewriter
+	clusterName     string
+	clusterIsPaused userclustercontrollermanager.IsPausedChecker
+}
+
+func Add(ctx context.Context, log *zap.SugaredLogger, seedMgr, userMgr manager.Manager, clusterName string, overwriteRegistry string, clusterIsPaused userclustercontrollermanager.IsPausedChecker) error {
+	clientset, err := kubernetes.NewForConfig(userMgr.GetConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	r := &reconciler{
+		log:        log.Named(controllerName),
+		seedClient: seedMgr.GetClient(),
+		seedReader: seedMgr.GetAPIReader(),
+		userClient: userMgr.GetClient(),
+		userReader: userMgr.GetAPIReader(),
+		getLogs: func(ctx context.Context, namespace, name string) ([]byte, error) {
+			return clientset.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{}).DoRaw(ctx)
+		},
+		imageRewriter:   registry.GetImageRewriterFunc(overwriteRegistry),
+		clusterName:     clusterName,
+		clusterIsPaused: clusterIsPaused,
+	}
+
+	c, err := controller.New(controllerName, userMgr, controller.Options{
+		Reconciler: r,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}}, controllerutil.EnqueueConst(""), predicateutil.ByNamespace(compliance.Namespace)); err != nil {
+		return fmt.Errorf("failed to establish watch for Jobs: %w", err)
+	}
+
+	// Watch the cluster to react to compliance scanning being enabled or disabled;
+	// afterwards the reconciler requeues itself according to the schedule.
+	seedWatch := &source.Kind{Type: &kubermaticv1.Cluster{}}
+	if err := seedWatch.InjectCache(seedMgr.GetCache()); err != nil {
+		return fmt.Errorf("failed to inject seed cache into watch: %w", err)
+	}
+	if err := c.Watch(seedWatch, controllerutil.EnqueueConst(""), predicateutil.ByName(clusterName)); err != nil {
+		return fmt.Errorf("failed to establish watch for the seed cluster: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	r.log.Debug("Reconciling")
+
+	paused, err := r.clusterIsPaused(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to check cluster pause status: %w", err)
+	}
+	if paused {
+		return reconcile.Result{}, nil
+	}
+
+	requeueAfter, err := r.reconcile(ctx)
+	if err != nil {
+		r.log.Errorw("Reconciling failed", zap.Error(err))
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, err
+}
+
+// reconcile returns the duration after which the cluster should be reconciled again.
+func (r *reconciler) reconcile(ctx context.Context) (time.Duration, error) {
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.seedClient.Get(ctx, types.NamespacedName{Name: r.clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("failed to get cluster %q: %w", r.clusterName, err)
+	}
+
+	if cluster.DeletionTimestamp != nil || cluster.Status.NamespaceName == "" {
+		return 0, nil
+	}
+
+	// The scan Jobs are removed together with the namespace by the resources controller.
+	if !cluster.Spec.IsComplianceScanningEnabled() {
+		report := &kubermaticv1.ComplianceReport{}
+		report.Name = kubermaticv1.CISBenchmarkComplianceReportName
+		report.Namespace = cluster.Status.NamespaceName
+
+		if err := r.seedClient.Delete(ctx, report); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return 0, fmt.Errorf("failed to delete ComplianceReport: %w", err)
+		}
+
+		return 0, nil
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := r.userReader.List(ctx, jobs, ctrlruntimeclient.InNamespace(compliance.Namespace), ctrlruntimeclient.MatchingLabels(resources.BaseAppLabels(compliance.AppName, nil))); err != nil {
+		return 0, fmt.Errorf("failed to list scan Jobs: %w", err)
+	}
+
+	if len(jobs.Items) > 0 {
+		for _, job := range jobs.Items {
+			if !isJobFinished(&job) {
+				return pollInterval, nil
+			}
+		}
+
+		if err := r.completeScan(ctx, cluster, jobs.Items); err != nil {
+			return 0, err
+		}
+
+		if err := r.deleteJobs(ctx, jobs.Items); err != nil {
+			return 0, err
+		}
+
+		return pollInterval, nil
+	}
+
+	report, err := r.getReport(ctx, cluster)
+	if err != nil {
+		return 0, err
+	}
+
+	schedule, err := parseSchedule(cluster.Spec.ComplianceScanning.Schedule)
+	if err != nil {
+		return 0, err
+	}
+
+	if report != nil && !report.Status.LastScanTime.IsZero() {
+		next := schedule.Next(report.Status.LastScanTime.Time)
+		if wait := time.Until(next); wait > 0 {
+			return wait, nil
+		}
+	}
+
+	return pollInterval, r.startScan(ctx, cluster)
+}
+
+func parseSchedule(schedule string) (cron.Schedule, error) {
+	if schedule == "" {
+		schedule = kubermaticv1.DefaultComplianceScanSchedule
+	}
+
+	parsed, err := validation.GetCronExpressionParser().Parse(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schedule %q: %w", schedule, err)
+	}
+
+	return parsed, nil
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isJobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startScan creates a scan Job for every node of the user cluster. Clusters without any
+// nodes are reported right away, containing only the control plane checks.
+func (r *reconciler) startScan(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	nodes := &corev1.NodeList{}
+	if err := r.userReader.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	if len(nodes.Items) == 0 {
+		return r.completeScan(ctx, cluster, nil)
+	}
+
+	r.log.Infow("Starting compliance scan", "nodes", len(nodes.Items))
+
+	var created []batchv1.Job
+	for _, node := range nodes.Items {
+		job, err := compliance.ScanJob(node.Name, r.imageRewriter)
+		if err == nil {
+			err = r.userClient.Create(ctx, job)
+		}
+
+		if err != nil {
+			// An incomplete set of Jobs would be reported as a full scan, so the scan is
+			// aborted and started from scratch during the next reconciliation.
+			if deleteErr := r.deleteJobs(ctx, created); deleteErr != nil {
+				return kerrors.NewAggregate([]error{fmt.Errorf("failed to create scan Job for node %s: %w", node.Name, err), deleteErr})
+			}
+
+			return fmt.Errorf("failed to create scan Job for node %s: %w", node.Name, err)
+		}
+
+		created = append(created, *job)
+	}
+
+	return nil
+}
+
+// completeScan collects the results of all finished scan Jobs and
+// the control plane checks and writes them into the report.
+func (r *reconciler) completeScan(ctx context.Context, cluster *kubermaticv1.Cluster, jobs []batchv1.Job) error {
+	checks := checkMap{}
+	errs := []string{}
+
+	for _, job := range jobs {
+		nodeName := job.Annotations[compliance.NodeAnnotation]
+
+		results, err := r.getNodeResults(ctx, &job)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("node %s: %v", nodeName, err))
+			continue
+		}
+
+		for _, result := range results {
+			checks.add(result, nodeName)
+		}
+	}
+
+	results, controlPlaneErrs, err := checkControlPlane(ctx, r.seedReader, cluster.Status.NamespaceName)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		checks.add(result, "")
+	}
+	errs = append(errs, controlPlaneErrs...)
+
+	return r.updateReport(ctx, cluster, checks.results(), errs)
+}
+
+func (r *reconciler) getNodeResults(ctx context.Context, job *batchv1.Job) ([]kubermaticv1.ComplianceCheckResult, error) {
+	if isJobFailed(job) {
+		return nil, fmt.Errorf("scan Job %s failed", job.Name)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector on scan Job %s: %w", job.Name, err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.userReader.List(ctx, pods, ctrlruntimeclient.InNamespace(job.Namespace), ctrlruntimeclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list Pods of scan Job %s: %w", job.Name, err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+
+		logs, err := r.getLogs(ctx, pod.Namespace, pod.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get logs of Pod %s: %w", pod.Name, err)
+		}
+
+		return parseKubeBenchOutput(logs)
+	}
+
+	return nil, fmt.Errorf("scan Job %s has no succeeded Pod", job.Name)
+}
+
+func (r *reconciler) deleteJobs(ctx context.Context, jobs []batchv1.Job) error {
+	for i := range jobs {
+		if err := r.userClient.Delete(ctx, &jobs[i], ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground)); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete scan Job %s: %w", jobs[i].Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *reconciler) getReport(ctx context.Context, cluster *kubermaticv1.Cluster) (*kubermaticv1.ComplianceReport, error) {
+	report := &kubermaticv1.ComplianceReport{}
+	key := types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: kubermaticv1.CISBenchmarkComplianceReportName}
+
+	if err := r.seedReader.Get(ctx, key, report); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get ComplianceReport: %w", err)
+	}
+
+	return report, nil
+}
+
+func (r *reconciler) updateReport(ctx context.Context, cluster *kubermaticv1.Cluster, checks []kubermaticv1.ComplianceCheckResult, errs []string) error {
+	report, err := r.getReport(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	if report == nil {
+		report = &kubermaticv1.ComplianceReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      kubermaticv1.CISBenchmarkComplianceReportName,
+				Namespace: cluster.Status.NamespaceName,
+			},
+			Spec: kubermaticv1.ComplianceReportSpec{
+				Cluster: cluster.Name,
+			},
+		}
+
+		if err := r.seedClient.Create(ctx, report); err != nil {
+			return fmt.Errorf("failed to create ComplianceReport: %w", err)
+		}
+	}
+
+	oldReport := report.DeepCopy()
+	report.Status = kubermaticv1.ComplianceReportStatus{
+		Benchmark:    compliance.Benchmark,
+		LastScanTime: metav1.Now(),
+		Summary:      summarize(checks),
+		Checks:       checks,
+		Errors:       errs,
+	}
+
+	if err := r.seedClient.Status().Patch(ctx, report, ctrlruntimeclient.MergeFrom(oldReport)); err != nil {
+		return fmt.Errorf("failed to update ComplianceReport status: %w", err)
+	}
+
+	r.log.Infow("Compliance scan completed", "pass", report.Status.Summary.Pass, "fail", report.Status.Summary.Fail, "errors", len(errs))
+
+	return nil
+}
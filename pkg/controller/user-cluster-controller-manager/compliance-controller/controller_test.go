@@ -0,0 +1,306 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliancecontroller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/compliance"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/registry"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const nodeScanOutput = `[WARN] some noise before the report
+{"Controls":[{"id":"4","tests":[{"section":"4.1","results":[
+	{"test_number":"4.1.1","test_desc":"Ensure that the kubelet service file permissions are set to 600","remediation":"chmod 600 kubelet.service","status":"PASS"},
+	{"test_number":"4.1.10","test_desc":"Ensure that the kubelet --config file ownership is set to root:root","remediation":"chown root:root /etc/kubernetes/kubelet.conf","status":"FAIL"},
+	{"test_number":"4.1.2","test_desc":"Ensure that the kubelet service file ownership is set to root:root","remediation":"chown root:root kubelet.service","status":"WARN"}
+]}]}]}`
+
+const clusterNamespace = "cluster-test"
+
+func controlPlaneDeployment(name string, args ...string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: clusterNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    name,
+							Command: []string{"/usr/local/bin/" + name},
+							Args:    args,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			ComplianceScanning: &kubermaticv1.ComplianceScanningSettings{
+				Enabled: true,
+			},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: clusterNamespace,
+		},
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+		},
+	}
+
+	seedClient := fake.NewClientBuilder().WithObjects(
+		cluster,
+		controlPlaneDeployment(resources.ApiserverDeploymentName, "--authorization-mode", "Node,RBAC", "--profiling=false"),
+		controlPlaneDeployment(resources.ControllerManagerDeploymentName, "--profiling=false", "--use-service-account-credentials"),
+	).Build()
+	userClient := fake.NewClientBuilder().WithObjects(node).Build()
+
+	r := &reconciler{
+		log:        zap.NewNop().Sugar(),
+		seedClient: seedClient,
+		seedReader: seedClient,
+		userClient: userClient,
+		userReader: userClient,
+		getLogs: func(ctx context.Context, namespace, name string) ([]byte, error) {
+			return []byte(nodeScanOutput), nil
+		},
+		imageRewriter: registry.GetImageRewriterFunc(""),
+		clusterName:   cluster.Name,
+		clusterIsPaused: func(ctx context.Context) (bool, error) {
+			return false, nil
+		},
+	}
+
+	// the first reconciliation starts the scan of all nodes
+	if _, err := r.reconcile(ctx); err != nil {
+		t.Fatalf("Reconciling failed: %v", err)
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := userClient.List(ctx, jobs, ctrlruntimeclient.InNamespace(compliance.Namespace)); err != nil {
+		t.Fatalf("Failed to list Jobs: %v", err)
+	}
+	if len(jobs.Items) != 1 {
+		t.Fatalf("Expected 1 scan Job, but got %d", len(jobs.Items))
+	}
+
+	job := jobs.Items[0]
+	if job.Spec.Template.Spec.NodeName != node.Name {
+		t.Fatalf("Expected scan Job to run on node %s, but got %q", node.Name, job.Spec.Template.Spec.NodeName)
+	}
+
+	// unfinished Jobs are waited for
+	if requeue, err := r.reconcile(ctx); err != nil || requeue != pollInterval {
+		t.Fatalf("Expected reconciling to wait for the scan, but got requeue=%v, err=%v", requeue, err)
+	}
+
+	// let the Job complete like the Job controller would
+	job.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": job.Name}}
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	if err := userClient.Update(ctx, &job); err != nil {
+		t.Fatalf("Failed to update Job: %v", err)
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name + "-abcde",
+			Namespace: compliance.Namespace,
+			Labels:    map[string]string{"job-name": job.Name},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+		},
+	}
+	if err := userClient.Create(ctx, pod); err != nil {
+		t.Fatalf("Failed to create Pod: %v", err)
+	}
+
+	if _, err := r.reconcile(ctx); err != nil {
+		t.Fatalf("Reconciling failed: %v", err)
+	}
+
+	report := &kubermaticv1.ComplianceReport{}
+	if err := seedClient.Get(ctx, types.NamespacedName{Namespace: clusterNamespace, Name: kubermaticv1.CISBenchmarkComplianceReportName}, report); err != nil {
+		t.Fatalf("Failed to get ComplianceReport: %v", err)
+	}
+
+	if report.Spec.Cluster != cluster.Name {
+		t.Errorf("Expected report to reference cluster %s, but got %q", cluster.Name, report.Spec.Cluster)
+	}
+	if report.Status.LastScanTime.IsZero() {
+		t.Error("Expected report to have a scan time.")
+	}
+
+	var nodeChecks []string
+	for _, check := range report.Status.Checks {
+		if check.Target == kubermaticv1.ComplianceCheckTargetNode {
+			nodeChecks = append(nodeChecks, check.ID)
+		}
+	}
+	if expected := []string{"4.1.1", "4.1.2", "4.1.10"}; !reflect.DeepEqual(nodeChecks, expected) {
+		t.Errorf("Expected node checks %v, but got %v", expected, nodeChecks)
+	}
+
+	summary := report.Status.Summary
+	if total := summary.Pass + summary.Fail + summary.Warn + summary.Info; total != len(report.Status.Checks) {
+		t.Errorf("Expected summary to cover %d checks, but it covers %d", len(report.Status.Checks), total)
+	}
+
+	// the scheduler Deployment is missing
+	if len(report.Status.Errors) != 1 {
+		t.Errorf("Expected 1 error, but got %v", report.Status.Errors)
+	}
+
+	if err := userClient.List(ctx, jobs, ctrlruntimeclient.InNamespace(compliance.Namespace)); err != nil {
+		t.Fatalf("Failed to list Jobs: %v", err)
+	}
+	if len(jobs.Items) != 0 {
+		t.Fatalf("Expected finished scan Jobs to be deleted, but got %d", len(jobs.Items))
+	}
+
+	// no new scan is started before the next scheduled time
+	requeue, err := r.reconcile(ctx)
+	if err != nil {
+		t.Fatalf("Reconciling failed: %v", err)
+	}
+	if requeue <= pollInterval {
+		t.Errorf("Expected to wait for the next scheduled scan, but got requeue=%v", requeue)
+	}
+
+	if err := userClient.List(ctx, jobs, ctrlruntimeclient.InNamespace(compliance.Namespace)); err != nil {
+		t.Fatalf("Failed to list Jobs: %v", err)
+	}
+	if len(jobs.Items) != 0 {
+		t.Fatalf("Expected no scan to be started, but got %d Jobs", len(jobs.Items))
+	}
+
+	// disabling the scans removes the report
+	cluster.Spec.ComplianceScanning.Enabled = false
+	if err := seedClient.Update(ctx, cluster); err != nil {
+		t.Fatalf("Failed to update cluster: %v", err)
+	}
+
+	if _, err := r.reconcile(ctx); err != nil {
+		t.Fatalf("Reconciling failed: %v", err)
+	}
+
+	if err := seedClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(report), report); err == nil {
+		t.Error("Expected ComplianceReport to be deleted.")
+	}
+}
+
+func TestCheckMap(t *testing.T) {
+	checks := checkMap{}
+
+	checks.add(kubermaticv1.ComplianceCheckResult{ID: "4.2.1", Target: kubermaticv1.ComplianceCheckTargetNode, State: kubermaticv1.ComplianceCheckPass}, "node-a")
+	checks.add(kubermaticv1.ComplianceCheckResult{ID: "4.2.1", Target: kubermaticv1.ComplianceCheckTargetNode, State: kubermaticv1.ComplianceCheckFail}, "node-c")
+	checks.add(kubermaticv1.ComplianceCheckResult{ID: "4.2.1", Target: kubermaticv1.ComplianceCheckTargetNode, State: kubermaticv1.ComplianceCheckWarn}, "node-d")
+	checks.add(kubermaticv1.ComplianceCheckResult{ID: "4.2.1", Target: kubermaticv1.ComplianceCheckTargetNode, State: kubermaticv1.ComplianceCheckFail}, "node-b")
+
+	results := checks.results()
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, but got %d", len(results))
+	}
+
+	if results[0].State != kubermaticv1.ComplianceCheckFail {
+		t.Errorf("Expected the worst state to win, but got %s", results[0].State)
+	}
+
+	if expected := []string{"node-b", "node-c"}; !reflect.DeepEqual(results[0].Nodes, expected) {
+		t.Errorf("Expected nodes %v, but got %v", expected, results[0].Nodes)
+	}
+}
+
+// failingClient fails to create more than the given number of objects.
+type failingClient struct {
+	ctrlruntimeclient.Client
+	creations int
+}
+
+func (c *failingClient) Create(ctx context.Context, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.CreateOption) error {
+	if c.creations == 0 {
+		return errors.New("quota exceeded")
+	}
+
+	c.creations--
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestStartScanCleansUpPartialScans(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+
+	userClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-3"}},
+	).Build()
+
+	r := &reconciler{
+		log:           zap.NewNop().Sugar(),
+		userClient:    &failingClient{Client: userClient, creations: 2},
+		userReader:    userClient,
+		imageRewriter: registry.GetImageRewriterFunc(""),
+	}
+
+	if err := r.startScan(ctx, cluster); err == nil {
+		t.Fatal("Expected starting the scan to fail.")
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := userClient.List(ctx, jobs, ctrlruntimeclient.InNamespace(compliance.Namespace)); err != nil {
+		t.Fatalf("Failed to list Jobs: %v", err)
+	}
+	if len(jobs.Items) != 0 {
+		t.Fatalf("Expected the Jobs of the partial scan to be deleted, but got %d", len(jobs.Items))
+	}
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliancecontroller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// commandLineFlags maps flag names (without leading dashes) to their values.
+// Boolean flags that are given without a value are set to "true".
+type commandLineFlags map[string]string
+
+// parseFlags supports "--flag=value", "--flag value" and "--flag" for boolean flags.
+func parseFlags(args []string) commandLineFlags {
+	flags := commandLineFlags{}
+
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+
+		name := strings.TrimLeft(args[i], "-")
+		if key, value, found := strings.Cut(name, "="); found {
+			flags[key] = value
+			continue
+		}
+
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			flags[name] = args[i+1]
+			i++
+			continue
+		}
+
+		flags[name] = "true"
+	}
+
+	return flags
+}
+
+func (f commandLineFlags) list(name string) sets.Set[string] {
+	value, ok := f[name]
+	if !ok || value == "" {
+		return sets.New[string]()
+	}
+
+	return sets.New(strings.Split(value, ",")...)
+}
+
+func flagsSet(names ...string) func(commandLineFlags) bool {
+	return func(f commandLineFlags) bool {
+		for _, name := range names {
+			if f[name] == "" {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func flagNotSet(name string) func(commandLineFlags) bool {
+	return func(f commandLineFlags) bool {
+		_, ok := f[name]
+		return !ok
+	}
+}
+
+func flagEquals(name, value string) func(commandLineFlags) bool {
+	return func(f commandLineFlags) bool {
+		return f[name] == value
+	}
+}
+
+// flagNotEquals also passes if the flag is not set at all.
+func flagNotEquals(name, value string) func(commandLineFlags) bool {
+	return func(f commandLineFlags) bool {
+		return f[name] != value
+	}
+}
+
+func flagListContains(name, item string) func(commandLineFlags) bool {
+	return func(f commandLineFlags) bool {
+		return f.list(name).Has(item)
+	}
+}
+
+func flagListNotContains(name, item string) func(commandLineFlags) bool {
+	return func(f commandLineFlags) bool {
+		return !f.list(name).Has(item)
+	}
+}
+
+func flagAtLeast(name string, min int) func(commandLineFlags) bool {
+	return func(f commandLineFlags) bool {
+		value, err := strconv.Atoi(f[name])
+		return err == nil && value >= min
+	}
+}
+
+// flagCheck is a CIS benchmark recommendation that can be verified by looking at the
+// command line flags of a control plane component.
+type flagCheck struct {
+	id          string
+	description string
+	remediation string
+	// manual recommendations cannot be fully verified automatically, so they are reported
+	// as warnings instead of failures.
+	manual bool
+	passes func(commandLineFlags) bool
+}
+
+func (c *flagCheck) evaluate(flags commandLineFlags) kubermaticv1.ComplianceCheckResult {
+	state := kubermaticv1.ComplianceCheckPass
+	if !c.passes(flags) {
+		state = kubermaticv1.ComplianceCheckFail
+		if c.manual {
+			state = kubermaticv1.ComplianceCheckWarn
+		}
+	}
+
+	result := kubermaticv1.ComplianceCheckResult{
+		ID:          c.id,
+		Description: c.description,
+		Target:      kubermaticv1.ComplianceCheckTargetControlPlane,
+		State:       state,
+	}
+	if state != kubermaticv1.ComplianceCheckPass {
+		result.Remediation = c.remediation
+	}
+
+	return result
+}
+
+// controlPlaneComponent is a Deployment in the cluster namespace whose container of the same
+// name runs a Kubernetes control plane component.
+type controlPlaneComponent struct {
+	name   string
+	checks []flagCheck
+}
+
+// The etcd checks of the benchmark are not included, as the flags of etcd are set by the
+// etcd-launcher at runtime and cannot be inspected from the outside.
+var controlPlaneComponents = []controlPlaneComponent{
+	{
+		name: resources.ApiserverDeploymentName,
+		checks: []flagCheck{
+			{
+				id:          "1.2.1",
+				description: "Ensure that the --anonymous-auth argument is set to false",
+				remediation: "Set --anonymous-auth=false for the kube-apiserver.",
+				manual:      true,
+				passes:      flagEquals("anonymous-auth", "false"),
+			},
+			{
+				id:          "1.2.2",
+				description: "Ensure that the --token-auth-file parameter is not set",
+				remediation: "Use an alternate authentication mechanism and remove --token-auth-file from the kube-apiserver.",
+				passes:      flagNotSet("token-auth-file"),
+			},
+			{
+				id:          "1.2.4",
+				description: "Ensure that the --kubelet-client-certificate and --kubelet-client-key arguments are set as appropriate",
+				remediation: "Set --kubelet-client-certificate and --kubelet-client-key for the kube-apiserver.",
+				passes:      flagsSet("kubelet-client-certificate", "kubelet-client-key"),
+			},
+			{
+				id:          "1.2.5",
+				description: "Ensure that the --kubelet-certificate-authority argument is set as appropriate",
+				remediation: "Set --kubelet-certificate-authority for the kube-apiserver.",
+				passes:      flagsSet("kubelet-certificate-authority"),
+			},
+			{
+				id:          "1.2.6",
+				description: "Ensure that the --authorization-mode argument is not set to AlwaysAllow",
+				remediation: "Remove AlwaysAllow from --authorization-mode of the kube-apiserver.",
+				passes:      flagListNotContains("authorization-mode", "AlwaysAllow"),
+			},
+			{
+				id:          "1.2.7",
+				description: "Ensure that the --authorization-mode argument includes Node",
+				remediation: "Add Node to --authorization-mode of the kube-apiserver.",
+				passes:      flagListContains("authorization-mode", "Node"),
+			},
+			{
+				id:          "1.2.8",
+				description: "Ensure that the --authorization-mode argument includes RBAC",
+				remediation: "Add RBAC to --authorization-mode of the kube-apiserver.",
+				passes:      flagListContains("authorization-mode", "RBAC"),
+			},
+			{
+				id:          "1.2.10",
+				description: "Ensure that the admission control plugin AlwaysAdmit is not set",
+				remediation: "Remove AlwaysAdmit from the admission plugins of the cluster.",
+				passes:      flagListNotContains("enable-admission-plugins", "AlwaysAdmit"),
+			},
+			{
+				id:          "1.2.16",
+				description: "Ensure that the admission control plugin NodeRestriction is set",
+				remediation: "Add NodeRestriction to --enable-admission-plugins of the kube-apiserver.",
+				passes:      flagListContains("enable-admission-plugins", "NodeRestriction"),
+			},
+			{
+				id:          "1.2.17",
+				description: "Ensure that the --secure-port argument is not set to 0",
+				remediation: "Remove --secure-port=0 from the kube-apiserver.",
+				passes:      flagNotEquals("secure-port", "0"),
+			},
+			{
+				id:          "1.2.18",
+				description: "Ensure that the --profiling argument is set to false",
+				remediation: "Set --profiling=false for the kube-apiserver.",
+				passes:      flagEquals("profiling", "false"),
+			},
+			{
+				id:          "1.2.19",
+				description: "Ensure that the --audit-log-path argument is set",
+				remediation: "Set --audit-log-path for the kube-apiserver.",
+				passes:      flagsSet("audit-log-path"),
+			},
+			{
+				id:          "1.2.20",
+				description: "Ensure that the --audit-log-maxage argument is set to 30 or as appropriate",
+				remediation: "Set --audit-log-maxage=30 or higher for the kube-apiserver.",
+				passes:      flagAtLeast("audit-log-maxage", 30),
+			},
+			{
+				id:          "1.2.21",
+				description: "Ensure that the --audit-log-maxbackup argument is set to 10 or as appropriate",
+				remediation: "Set --audit-log-maxbackup=10 or higher for the kube-apiserver.",
+				passes:      flagAtLeast("audit-log-maxbackup", 10),
+			},
+			{
+				id:          "1.2.22",
+				description: "Ensure that the --audit-log-maxsize argument is set to 100 or as appropriate",
+				remediation: "Set --audit-log-maxsize=100 or higher for the kube-apiserver.",
+				passes:      flagAtLeast("audit-log-maxsize", 100),
+			},
+			{
+				id:          "1.2.24",
+				description: "Ensure that the --service-account-lookup argument is set to true",
+				remediation: "Remove --service-account-lookup=false from the kube-apiserver.",
+				passes:      flagNotEquals("service-account-lookup", "false"),
+			},
+			{
+				id:          "1.2.25",
+				description: "Ensure that the --service-account-key-file argument is set as appropriate",
+				remediation: "Set --service-account-key-file for the kube-apiserver.",
+				passes:      flagsSet("service-account-key-file"),
+			},
+			{
+				id:          "1.2.26",
+				description: "Ensure that the --etcd-certfile and --etcd-keyfile arguments are set as appropriate",
+				remediation: "Set --etcd-certfile and --etcd-keyfile for the kube-apiserver.",
+				passes:      flagsSet("etcd-certfile", "etcd-keyfile"),
+			},
+			{
+				id:          "1.2.27",
+				description: "Ensure that the --tls-cert-file and --tls-private-key-file arguments are set as appropriate",
+				remediation: "Set --tls-cert-file and --tls-private-key-file for the kube-apiserver.",
+				passes:      flagsSet("tls-cert-file", "tls-private-key-file"),
+			},
+			{
+				id:          "1.2.28",
+				description: "Ensure that the --client-ca-file argument is set as appropriate",
+				remediation: "Set --client-ca-file for the kube-apiserver.",
+				passes:      flagsSet("client-ca-file"),
+			},
+			{
+				id:          "1.2.29",
+				description: "Ensure that the --etcd-cafile argument is set as appropriate",
+				remediation: "Set --etcd-cafile for the kube-apiserver.",
+				passes:      flagsSet("etcd-cafile"),
+			},
+			{
+				id:          "1.2.30",
+				description: "Ensure that the --encryption-provider-config argument is set as appropriate",
+				remediation: "Enable encryption at rest for the cluster.",
+				manual:      true,
+				passes:      flagsSet("encryption-provider-config"),
+			},
+		},
+	},
+	{
+		name: resources.ControllerManagerDeploymentName,
+		checks: []flagCheck{
+			{
+				id:          "1.3.2",
+				description: "Ensure that the --profiling argument is set to false",
+				remediation: "Set --profiling=false for the kube-controller-manager.",
+				passes:      flagEquals("profiling", "false"),
+			},
+			{
+				id:          "1.3.3",
+				description: "Ensure that the --use-service-account-credentials argument is set to true",
+				remediation: "Set --use-service-account-credentials=true for the kube-controller-manager.",
+				passes:      flagEquals("use-service-account-credentials", "true"),
+			},
+			{
+				id:          "1.3.4",
+				description: "Ensure that the --service-account-private-key-file argument is set as appropriate",
+				remediation: "Set --service-account-private-key-file for the kube-controller-manager.",
+				passes:      flagsSet("service-account-private-key-file"),
+			},
+			{
+				id:          "1.3.5",
+				description: "Ensure that the --root-ca-file argument is set as appropriate",
+				remediation: "Set --root-ca-file for the kube-controller-manager.",
+				passes:      flagsSet("root-ca-file"),
+			},
+			{
+				id:          "1.3.6",
+				description: "Ensure that the RotateKubeletServerCertificate argument is set to true",
+				remediation: "Remove RotateKubeletServerCertificate=false from --feature-gates of the kube-controller-manager.",
+				passes:      flagListNotContains("feature-gates", "RotateKubeletServerCertificate=false"),
+			},
+		},
+	},
+	{
+		name: resources.SchedulerDeploymentName,
+		checks: []flagCheck{
+			{
+				id:          "1.4.1",
+				description: "Ensure that the --profiling argument is set to false",
+				remediation: "Set --profiling=false for the kube-scheduler.",
+				passes:      flagEquals("profiling", "false"),
+			},
+		},
+	},
+}
+
+// checkControlPlane evaluates the control plane checks against the Deployments in the given
+// cluster namespace. Components that cannot be found are reported as errors.
+func checkControlPlane(ctx context.Context, client ctrlruntimeclient.Reader, namespace string) ([]kubermaticv1.ComplianceCheckResult, []string, error) {
+	var (
+		results []kubermaticv1.ComplianceCheckResult
+		errs    []string
+	)
+
+	for _, component := range controlPlaneComponents {
+		deployment := &appsv1.Deployment{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: component.name}, deployment); err != nil {
+			if apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Sprintf("control plane component %s not found", component.name))
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to get %s Deployment: %w", component.name, err)
+		}
+
+		var args []string
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Name == component.name {
+				args = append(append(args, container.Command...), container.Args...)
+			}
+		}
+		if len(args) == 0 {
+			errs = append(errs, fmt.Sprintf("control plane component %s has no %s container", component.name, component.name))
+			continue
+		}
+
+		flags := parseFlags(args)
+		for i := range component.checks {
+			results = append(results, component.checks[i].evaluate(flags))
+		}
+	}
+
+	return results, errs, nil
+}
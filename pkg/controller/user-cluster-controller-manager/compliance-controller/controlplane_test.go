@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliancecontroller
+
+import (
+	"reflect"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+func TestParseFlags(t *testing.T) {
+	testcases := []struct {
+		name     string
+		args     []string
+		expected commandLineFlags
+	}{
+		{
+			name:     "flags with separate values",
+			args:     []string{"/usr/local/bin/kube-apiserver", "--secure-port", "6443", "--profiling", "false"},
+			expected: commandLineFlags{"secure-port": "6443", "profiling": "false"},
+		},
+		{
+			name:     "flags with inline values",
+			args:     []string{"--authorization-mode=Node,RBAC", "--feature-gates=A=true,B=false"},
+			expected: commandLineFlags{"authorization-mode": "Node,RBAC", "feature-gates": "A=true,B=false"},
+		},
+		{
+			name:     "boolean flags without value",
+			args:     []string{"--allow-privileged", "--use-service-account-credentials", "--v", "4"},
+			expected: commandLineFlags{"allow-privileged": "true", "use-service-account-credentials": "true", "v": "4"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := parseFlags(tc.args)
+			if !reflect.DeepEqual(flags, tc.expected) {
+				t.Fatalf("Expected %v, but got %v", tc.expected, flags)
+			}
+		})
+	}
+}
+
+func TestFlagCheck(t *testing.T) {
+	flags := parseFlags([]string{
+		"--authorization-mode", "Node,RBAC",
+		"--audit-log-maxage=30",
+		"--audit-log-maxbackup", "3",
+		"--token-auth-file", "/etc/kubernetes/tokens/tokens.csv",
+	})
+
+	testcases := []struct {
+		check    flagCheck
+		expected kubermaticv1.ComplianceCheckState
+	}{
+		{
+			check:    flagCheck{passes: flagListContains("authorization-mode", "RBAC")},
+			expected: kubermaticv1.ComplianceCheckPass,
+		},
+		{
+			check:    flagCheck{passes: flagListNotContains("authorization-mode", "AlwaysAllow")},
+			expected: kubermaticv1.ComplianceCheckPass,
+		},
+		{
+			check:    flagCheck{passes: flagAtLeast("audit-log-maxage", 30)},
+			expected: kubermaticv1.ComplianceCheckPass,
+		},
+		{
+			check:    flagCheck{passes: flagAtLeast("audit-log-maxbackup", 10)},
+			expected: kubermaticv1.ComplianceCheckFail,
+		},
+		{
+			check:    flagCheck{passes: flagAtLeast("audit-log-maxsize", 100)},
+			expected: kubermaticv1.ComplianceCheckFail,
+		},
+		{
+			check:    flagCheck{passes: flagNotSet("token-auth-file")},
+			expected: kubermaticv1.ComplianceCheckFail,
+		},
+		{
+			check:    flagCheck{passes: flagNotEquals("secure-port", "0")},
+			expected: kubermaticv1.ComplianceCheckPass,
+		},
+		{
+			check:    flagCheck{passes: flagEquals("anonymous-auth", "false"), manual: true},
+			expected: kubermaticv1.ComplianceCheckWarn,
+		},
+	}
+
+	for i, tc := range testcases {
+		result := tc.check.evaluate(flags)
+		if result.State != tc.expected {
+			t.Errorf("Expected check %d to be %s, but got %s", i, tc.expected, result.State)
+		}
+		if result.Target != kubermaticv1.ComplianceCheckTargetControlPlane {
+			t.Errorf("Expected check %d to target the control plane, but got %s", i, result.Target)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package compliancecontroller contains a controller that periodically checks a user
+cluster against the CIS Kubernetes benchmark and stores the results in a
+ComplianceReport in the cluster namespace in the seed.
+
+The checks are gathered from two sources:
+
+  - the nodes are scanned by kube-bench Jobs that run in the user cluster, one per
+    node, and whose output is read from the Pod logs, and
+  - the control plane components are checked by inspecting the command line flags
+    of their Deployments in the seed cluster, as the control plane does not run on
+    any of the user cluster nodes.
+
+Scans are only performed if compliance scanning is enabled in the Cluster spec.
+*/
+package compliancecontroller
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliancecontroller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+// kubeBenchOutput is the subset of the JSON output of "kube-bench run --json"
+// that is relevant for the compliance report.
+type kubeBenchOutput struct {
+	Controls []struct {
+		Tests []struct {
+			Results []kubeBenchResult `json:"results"`
+		} `json:"tests"`
+	} `json:"Controls"`
+}
+
+type kubeBenchResult struct {
+	TestNumber  string `json:"test_number"`
+	TestDesc    string `json:"test_desc"`
+	Remediation string `json:"remediation"`
+	Status      string `json:"status"`
+}
+
+// parseKubeBenchOutput returns the check results of a single node scan.
+func parseKubeBenchOutput(output []byte) ([]kubermaticv1.ComplianceCheckResult, error) {
+	// kube-bench might log warnings before the actual report
+	start := bytes.IndexByte(output, '{')
+	if start < 0 {
+		return nil, errors.New("output does not contain a JSON report")
+	}
+
+	report := kubeBenchOutput{}
+	if err := json.Unmarshal(output[start:], &report); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+
+	var results []kubermaticv1.ComplianceCheckResult
+	for _, control := range report.Controls {
+		for _, test := range control.Tests {
+			for _, result := range test.Results {
+				state := parseKubeBenchStatus(result.Status)
+
+				check := kubermaticv1.ComplianceCheckResult{
+					ID:          result.TestNumber,
+					Description: result.TestDesc,
+					Target:      kubermaticv1.ComplianceCheckTargetNode,
+					State:       state,
+				}
+				if state != kubermaticv1.ComplianceCheckPass {
+					check.Remediation = strings.TrimSpace(result.Remediation)
+				}
+
+				results = append(results, check)
+			}
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, errors.New("report does not contain any results")
+	}
+
+	return results, nil
+}
+
+func parseKubeBenchStatus(status string) kubermaticv1.ComplianceCheckState {
+	switch strings.ToUpper(status) {
+	case "PASS":
+		return kubermaticv1.ComplianceCheckPass
+	case "FAIL":
+		return kubermaticv1.ComplianceCheckFail
+	case "INFO":
+		return kubermaticv1.ComplianceCheckInfo
+	default:
+		// unknown states must never be reported as passing
+		return kubermaticv1.ComplianceCheckWarn
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliancecontroller
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+// stateSeverity is used to determine the overall state of a check across all nodes.
+var stateSeverity = map[kubermaticv1.ComplianceCheckState]int{
+	kubermaticv1.ComplianceCheckPass: 0,
+	kubermaticv1.ComplianceCheckInfo: 1,
+	kubermaticv1.ComplianceCheckWarn: 2,
+	kubermaticv1.ComplianceCheckFail: 3,
+}
+
+type checkKey struct {
+	target kubermaticv1.ComplianceCheckTarget
+	id     string
+}
+
+// checkMap aggregates the results of all nodes into a single result per check, so that the
+// size of the report does not grow with the number of nodes.
+type checkMap map[checkKey]*kubermaticv1.ComplianceCheckResult
+
+// add records a result. The worst state across all nodes wins, and only nodes with that
+// state are listed in the result.
+func (m checkMap) add(result kubermaticv1.ComplianceCheckResult, nodeName string) {
+	key := checkKey{target: result.Target, id: result.ID}
+
+	existing, ok := m[key]
+	if !ok || stateSeverity[result.State] > stateSeverity[existing.State] {
+		result.Nodes = nil
+		existing = &result
+		m[key] = existing
+	} else if result.State != existing.State {
+		return
+	}
+
+	if nodeName != "" && result.State != kubermaticv1.ComplianceCheckPass && len(existing.Nodes) < maxNodes {
+		existing.Nodes = append(existing.Nodes, nodeName)
+	}
+}
+
+// results returns the aggregated results, sorted by target and ID.
+func (m checkMap) results() []kubermaticv1.ComplianceCheckResult {
+	results := make([]kubermaticv1.ComplianceCheckResult, 0, len(m))
+	for _, result := range m {
+		sort.Strings(result.Nodes)
+		results = append(results, *result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+
+	return results
+}
+
+// compareIDs compares dotted benchmark IDs numerically, so that 1.2.10 comes after 1.2.9.
+func compareIDs(a, b string) bool {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if aParts[i] == bParts[i] {
+			continue
+		}
+
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		if aErr == nil && bErr == nil {
+			return aNum < bNum
+		}
+
+		return aParts[i] < bParts[i]
+	}
+
+	return len(aParts) < len(bParts)
+}
+
+func summarize(results []kubermaticv1.ComplianceCheckResult) kubermaticv1.ComplianceReportSummary {
+	summary := kubermaticv1.ComplianceReportSummary{}
+
+	for _, result := range results {
+		switch result.State {
+		case kubermaticv1.ComplianceCheckPass:
+			summary.Pass++
+		case kubermaticv1.ComplianceCheckFail:
+			summary.Fail++
+		case kubermaticv1.ComplianceCheckWarn:
+			summary.Warn++
+		case kubermaticv1.ComplianceCheckInfo:
+			summary.Info++
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedapicontroller
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// removedAPI is an API version that has been (or will be) removed from Kubernetes.
+type removedAPI struct {
+	schema.GroupVersionResource
+
+	kind        string
+	removedIn   string
+	replacement string
+}
+
+// removedAPIs is based on https://kubernetes.io/docs/reference/using-api/deprecation-guide/.
+var removedAPIs = []removedAPI{
+	// removed in 1.25
+	{GroupVersionResource: gvr("batch", "v1beta1", "cronjobs"), kind: "CronJob", removedIn: "1.25", replacement: "batch/v1"},
+	{GroupVersionResource: gvr("discovery.k8s.io", "v1beta1", "endpointslices"), kind: "EndpointSlice", removedIn: "1.25", replacement: "discovery.k8s.io/v1"},
+	{GroupVersionResource: gvr("events.k8s.io", "v1beta1", "events"), kind: "Event", removedIn: "1.25", replacement: "events.k8s.io/v1"},
+	{GroupVersionResource: gvr("autoscaling", "v2beta1", "horizontalpodautoscalers"), kind: "HorizontalPodAutoscaler", removedIn: "1.25", replacement: "autoscaling/v2"},
+	{GroupVersionResource: gvr("policy", "v1beta1", "poddisruptionbudgets"), kind: "PodDisruptionBudget", removedIn: "1.25", replacement: "policy/v1"},
+	{GroupVersionResource: gvr("policy", "v1beta1", "podsecuritypolicies"), kind: "PodSecurityPolicy", removedIn: "1.25"},
+	{GroupVersionResource: gvr("node.k8s.io", "v1beta1", "runtimeclasses"), kind: "RuntimeClass", removedIn: "1.25", replacement: "node.k8s.io/v1"},
+
+	// removed in 1.26
+	{GroupVersionResource: gvr("flowcontrol.apiserver.k8s.io", "v1beta1", "flowschemas"), kind: "FlowSchema", removedIn: "1.26", replacement: "flowcontrol.apiserver.k8s.io/v1beta3"},
+	{GroupVersionResource: gvr("flowcontrol.apiserver.k8s.io", "v1beta1", "prioritylevelconfigurations"), kind: "PriorityLevelConfiguration", removedIn: "1.26", replacement: "flowcontrol.apiserver.k8s.io/v1beta3"},
+	{GroupVersionResource: gvr("autoscaling", "v2beta2", "horizontalpodautoscalers"), kind: "HorizontalPodAutoscaler", removedIn: "1.26", replacement: "autoscaling/v2"},
+
+	// removed in 1.27
+	{GroupVersionResource: gvr("storage.k8s.io", "v1beta1", "csistoragecapacities"), kind: "CSIStorageCapacity", removedIn: "1.27", replacement: "storage.k8s.io/v1"},
+
+	// removed in 1.29
+	{GroupVersionResource: gvr("flowcontrol.apiserver.k8s.io", "v1beta2", "flowschemas"), kind: "FlowSchema", removedIn: "1.29", replacement: "flowcontrol.apiserver.k8s.io/v1beta3"},
+	{GroupVersionResource: gvr("flowcontrol.apiserver.k8s.io", "v1beta2", "prioritylevelconfigurations"), kind: "PriorityLevelConfiguration", removedIn: "1.29", replacement: "flowcontrol.apiserver.k8s.io/v1beta3"},
+}
+
+func gvr(group, version, resource string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+}
+
+// findRemovedAPIByKind returns the removed API for the given apiVersion and kind, if any.
+func findRemovedAPIByKind(apiVersion string, kind string) *removedAPI {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil
+	}
+
+	for i, api := range removedAPIs {
+		if api.Group == gv.Group && api.Version == gv.Version && api.kind == kind {
+			return &removedAPIs[i]
+		}
+	}
+
+	return nil
+}
+
+// findRemovedAPIByResource returns the removed API for the given resource, if any.
+func findRemovedAPIByResource(resource schema.GroupVersionResource) *removedAPI {
+	for i, api := range removedAPIs {
+		if api.GroupVersionResource == resource {
+			return &removedAPIs[i]
+		}
+	}
+
+	return nil
+}
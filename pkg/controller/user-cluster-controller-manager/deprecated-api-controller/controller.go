@@ -0,0 +1,322 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedapicontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+	userclustercontrollermanager "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager"
+	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "kkp-deprecated-api-controller"
+
+	// scanInterval is the time between two scans of the user cluster. As the apiserver
+	// metrics do not trigger any watch events, the cluster has to be scanned periodically.
+	scanInterval = 15 * time.Minute
+
+	// maxObjects is the number of objects per API that are stored in the cluster status.
+	maxObjects = 10
+)
+
+type metricsGetter func(ctx context.Context) ([]byte, error)
+
+// reconciler scans the user cluster for deprecated APIs and
+// updates the deprecatedAPIs field in the cluster status.
+type reconciler struct {
+	log             *zap.SugaredLogger
+	seedClient      ctrlruntimeclient.Client
+	userClient      ctrlruntimeclient.Reader
+	discovery       discovery.DiscoveryInterface
+	getMetrics      metricsGetter
+	clusterName     string
+	clusterIsPaused userclustercontrollermanager.IsPausedChecker
+}
+
+func Add(ctx context.Context, log *zap.SugaredLogger, seedMgr, userMgr manager.Manager, clusterName string, clusterIsPaused userclustercontrollermanager.IsPausedChecker) error {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(userMgr.GetConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	r := &reconciler{
+		log:        log.Named(controllerName),
+		seedClient: seedMgr.GetClient(),
+		// use uncached reads, as Helm release secrets and objects of deprecated
+		// APIs are not worth keeping informers for
+		userClient: userMgr.GetAPIReader(),
+		discovery:  discoveryClient,
+		getMetrics: func(ctx context.Context) ([]byte, error) {
+			return discoveryClient.RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+		},
+		clusterName:     clusterName,
+		clusterIsPaused: clusterIsPaused,
+	}
+
+	c, err := controller.New(controllerName, userMgr, controller.Options{
+		Reconciler: r,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	// Namespaces are only watched to start the first scan, afterwards the
+	// reconciler requeues itself.
+	if err := c.Watch(&source.Kind{Type: &corev1.Namespace{}}, controllerutil.EnqueueConst("")); err != nil {
+		return fmt.Errorf("failed to establish watch for namespaces: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	r.log.Debug("Reconciling")
+
+	paused, err := r.clusterIsPaused(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to check cluster pause status: %w", err)
+	}
+	if paused {
+		return reconcile.Result{}, nil
+	}
+
+	err = r.reconcile(ctx)
+	if err != nil {
+		r.log.Errorw("Reconciling failed", zap.Error(err))
+	}
+
+	return reconcile.Result{RequeueAfter: scanInterval}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context) error {
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.seedClient.Get(ctx, types.NamespacedName{Name: r.clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get cluster %q: %w", r.clusterName, err)
+	}
+
+	usages, err := r.scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	return kubermaticv1helper.UpdateClusterStatus(ctx, r.seedClient, cluster, func(c *kubermaticv1.Cluster) {
+		c.Status.DeprecatedAPIs = &kubermaticv1.DeprecatedAPIsStatus{
+			LastScanTime: metav1.Now(),
+			Usages:       usages,
+		}
+	})
+}
+
+type usageMap map[schema.GroupVersionResource]*kubermaticv1.DeprecatedAPIUsage
+
+func (m usageMap) get(resource schema.GroupVersionResource, removedIn string, replacement string) *kubermaticv1.DeprecatedAPIUsage {
+	usage, ok := m[resource]
+	if !ok {
+		usage = &kubermaticv1.DeprecatedAPIUsage{
+			Group:            resource.Group,
+			Version:          resource.Version,
+			Resource:         resource.Resource,
+			RemovedInVersion: removedIn,
+			Replacement:      replacement,
+		}
+		m[resource] = usage
+	}
+
+	return usage
+}
+
+func (m usageMap) addObject(api *removedAPI, object string) {
+	usage := m.get(api.GroupVersionResource, api.removedIn, api.replacement)
+
+	usage.ObjectCount++
+	if len(usage.Objects) < maxObjects {
+		usage.Objects = append(usage.Objects, object)
+	}
+}
+
+// scan determines all deprecated APIs that are in use in the user cluster.
+func (r *reconciler) scan(ctx context.Context) ([]kubermaticv1.DeprecatedAPIUsage, error) {
+	usages := usageMap{}
+
+	if err := r.scanObjects(ctx, usages); err != nil {
+		return nil, err
+	}
+
+	if err := r.scanHelmReleases(ctx, usages); err != nil {
+		return nil, err
+	}
+
+	if err := r.scanMetrics(ctx, usages); err != nil {
+		return nil, err
+	}
+
+	result := make([]kubermaticv1.DeprecatedAPIUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, *usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.RemovedInVersion != b.RemovedInVersion {
+			return a.RemovedInVersion < b.RemovedInVersion
+		}
+		if a.GroupVersion() != b.GroupVersion() {
+			return a.GroupVersion() < b.GroupVersion()
+		}
+		return a.Resource < b.Resource
+	})
+
+	return result, nil
+}
+
+// isServed checks whether the apiserver still serves the given resource.
+func (r *reconciler) isServed(resource schema.GroupVersionResource) (bool, error) {
+	resources, err := r.discovery.ServerResourcesForGroupVersion(resource.GroupVersion().String())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to discover %s: %w", resource.GroupVersion(), err)
+	}
+
+	for _, apiResource := range resources.APIResources {
+		if apiResource.Name == resource.Resource {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// scanObjects lists the objects of served APIs that have no replacement. Objects of APIs with a
+// replacement are not considered, because the apiserver returns all objects regardless of the
+// API version they were created with.
+func (r *reconciler) scanObjects(ctx context.Context, usages usageMap) error {
+	for i, api := range removedAPIs {
+		if api.replacement != "" {
+			continue
+		}
+
+		served, err := r.isServed(api.GroupVersionResource)
+		if err != nil {
+			return err
+		}
+		if !served {
+			continue
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(api.GroupVersion().WithKind(api.kind + "List"))
+
+		if err := r.userClient.List(ctx, list); err != nil {
+			return fmt.Errorf("failed to list %s: %w", api.GroupVersionResource, err)
+		}
+
+		for _, object := range list.Items {
+			name := object.GetName()
+			if object.GetNamespace() != "" {
+				name = fmt.Sprintf("%s/%s", object.GetNamespace(), name)
+			}
+
+			usages.addObject(&removedAPIs[i], name)
+		}
+	}
+
+	return nil
+}
+
+// scanHelmReleases checks the manifests of all deployed Helm releases, as Helm fails to
+// upgrade a release once the API of any object in its manifest is no longer served.
+func (r *reconciler) scanHelmReleases(ctx context.Context, usages usageMap) error {
+	secrets := &corev1.SecretList{}
+	if err := r.userClient.List(ctx, secrets, ctrlruntimeclient.MatchingLabels(helmReleaseLabels)); err != nil {
+		return fmt.Errorf("failed to list Helm releases: %w", err)
+	}
+
+	for _, secret := range secrets.Items {
+		release, err := decodeHelmRelease(&secret)
+		if err != nil {
+			// a single broken release must not prevent the scan of all other releases
+			r.log.Warnw("Failed to decode Helm release", "secret", ctrlruntimeclient.ObjectKeyFromObject(&secret), zap.Error(err))
+			continue
+		}
+
+		objects, err := parseManifest(release.Manifest)
+		if err != nil {
+			r.log.Warnw("Failed to parse Helm release manifest", "release", release.Name, "namespace", release.Namespace, zap.Error(err))
+			continue
+		}
+
+		for _, object := range objects {
+			if api := findRemovedAPIByKind(object.APIVersion, object.Kind); api != nil {
+				usages.addObject(api, fmt.Sprintf("%s (Helm release %s/%s)", object.Metadata.Name, release.Namespace, release.Name))
+			}
+		}
+	}
+
+	return nil
+}
+
+// scanMetrics checks the apiserver metrics for requests to deprecated APIs.
+func (r *reconciler) scanMetrics(ctx context.Context, usages usageMap) error {
+	metrics, err := r.getMetrics(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get apiserver metrics: %w", err)
+	}
+
+	requested, err := parseRequestedAPIs(metrics)
+	if err != nil {
+		return fmt.Errorf("failed to parse apiserver metrics: %w", err)
+	}
+
+	for _, request := range requested {
+		replacement := ""
+		if api := findRemovedAPIByResource(request.GroupVersionResource); api != nil {
+			replacement = api.replacement
+		}
+
+		usages.get(request.GroupVersionResource, request.removedIn, replacement).Requested = true
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedapicontroller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const releaseManifest = `---
+# Source: app/templates/cronjob.yaml
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: cleanup
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+---
+# Source: app/templates/pdb.yaml
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: app
+`
+
+const apiserverMetrics = `# HELP apiserver_requested_deprecated_apis [STABLE] Gauge of deprecated APIs that have been requested, broken out by API group, version, resource, subresource, and removed_release.
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="poddisruptionbudgets",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="example.com",removed_release="",resource="widgets",subresource="",version="v1alpha1"} 1
+apiserver_requested_deprecated_apis{group="flowcontrol.apiserver.k8s.io",removed_release="1.26",resource="flowschemas",subresource="",version="v1beta1"} 1
+# HELP apiserver_request_total [STABLE] Counter of apiserver requests.
+# TYPE apiserver_request_total counter
+apiserver_request_total{code="200",verb="GET"} 42
+`
+
+func encodeHelmRelease(t *testing.T, release helmRelease) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(release)
+	if err != nil {
+		t.Fatalf("Failed to encode release: %v", err)
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatalf("Failed to compress release: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to compress release: %v", err)
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Version: *semver.NewSemverOrDie("1.24.10"),
+		},
+	}
+
+	releaseSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1.app.v3",
+			Namespace: "apps",
+			Labels: map[string]string{
+				"owner":  "helm",
+				"status": "deployed",
+				"name":   "app",
+			},
+		},
+		Data: map[string][]byte{
+			"release": encodeHelmRelease(t, helmRelease{Name: "app", Namespace: "apps", Manifest: releaseManifest}),
+		},
+	}
+
+	// superseded revisions must be ignored
+	oldReleaseSecret := releaseSecret.DeepCopy()
+	oldReleaseSecret.Name = "sh.helm.release.v1.app.v2"
+	oldReleaseSecret.Labels["status"] = "superseded"
+	oldReleaseSecret.Data["release"] = encodeHelmRelease(t, helmRelease{Name: "app", Namespace: "apps", Manifest: `
+apiVersion: policy/v1beta1
+kind: PodSecurityPolicy
+metadata:
+  name: old
+`})
+
+	psp := &policyv1beta1.PodSecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "restricted",
+		},
+	}
+
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	discovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "policy/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget", Namespaced: true},
+				{Name: "podsecuritypolicies", Kind: "PodSecurityPolicy"},
+			},
+		},
+	}
+
+	r := &reconciler{
+		log:        zap.NewNop().Sugar(),
+		seedClient: fake.NewClientBuilder().WithObjects(cluster).Build(),
+		userClient: fake.NewClientBuilder().WithObjects(releaseSecret, oldReleaseSecret, psp).Build(),
+		discovery:  discovery,
+		getMetrics: func(ctx context.Context) ([]byte, error) {
+			return []byte(apiserverMetrics), nil
+		},
+		clusterName: cluster.Name,
+		clusterIsPaused: func(ctx context.Context) (bool, error) {
+			return false, nil
+		},
+	}
+
+	if err := r.reconcile(ctx); err != nil {
+		t.Fatalf("Reconciling failed: %v", err)
+	}
+
+	updated := &kubermaticv1.Cluster{}
+	if err := r.seedClient.Get(ctx, types.NamespacedName{Name: cluster.Name}, updated); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+
+	if updated.Status.DeprecatedAPIs == nil {
+		t.Fatal("Expected deprecated APIs to be set in the cluster status.")
+	}
+
+	expected := []kubermaticv1.DeprecatedAPIUsage{
+		{
+			Group:            "batch",
+			Version:          "v1beta1",
+			Resource:         "cronjobs",
+			RemovedInVersion: "1.25",
+			Replacement:      "batch/v1",
+			Objects:          []string{"cleanup (Helm release apps/app)"},
+			ObjectCount:      1,
+		},
+		{
+			Group:            "policy",
+			Version:          "v1beta1",
+			Resource:         "poddisruptionbudgets",
+			RemovedInVersion: "1.25",
+			Replacement:      "policy/v1",
+			Objects:          []string{"app (Helm release apps/app)"},
+			ObjectCount:      1,
+			Requested:        true,
+		},
+		{
+			Group:            "policy",
+			Version:          "v1beta1",
+			Resource:         "podsecuritypolicies",
+			RemovedInVersion: "1.25",
+			Objects:          []string{"restricted"},
+			ObjectCount:      1,
+		},
+		{
+			Group:            "flowcontrol.apiserver.k8s.io",
+			Version:          "v1beta1",
+			Resource:         "flowschemas",
+			RemovedInVersion: "1.26",
+			Replacement:      "flowcontrol.apiserver.k8s.io/v1beta3",
+			Requested:        true,
+		},
+	}
+
+	usages := updated.Status.DeprecatedAPIs.Usages
+	if len(usages) != len(expected) {
+		t.Fatalf("Expected %d deprecated APIs, but got %d: %+v", len(expected), len(usages), usages)
+	}
+
+	for i := range expected {
+		exp, _ := json.Marshal(expected[i])
+		got, _ := json.Marshal(usages[i])
+
+		if !bytes.Equal(exp, got) {
+			t.Errorf("Expected usage %d to be\n%s\nbut got\n%s", i, exp, got)
+		}
+	}
+}
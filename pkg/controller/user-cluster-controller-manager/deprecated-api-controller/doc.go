@@ -0,0 +1,30 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package deprecatedapicontroller contains a controller that periodically scans the
+user cluster for deprecated APIs that are still in use and that are removed in a
+future Kubernetes release. The findings are stored in the Cluster's status and are
+used to block control plane upgrades that would break these objects.
+
+The usage is determined from three sources:
+
+  - objects of served APIs that have no replacement (like PodSecurityPolicies),
+  - the manifests of deployed Helm releases, as Helm cannot upgrade releases that
+    contain objects of removed APIs, and
+  - the apiserver's apiserver_requested_deprecated_apis metric.
+*/
+package deprecatedapicontroller
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedapicontroller
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// helmReleaseLabels select the Secrets that Helm uses to store the currently
+// deployed revision of each release.
+var helmReleaseLabels = map[string]string{
+	"owner":  "helm",
+	"status": "deployed",
+}
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// helmRelease contains the parts of a Helm release that are relevant for this controller.
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Manifest  string `json:"manifest"`
+}
+
+// manifestObject is an object from a Helm release's manifest.
+type manifestObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+}
+
+// decodeHelmRelease decodes a release stored by Helm's Secret storage driver, which
+// stores the release as base64 encoded, gzipped JSON (in addition to the base64
+// encoding of the Secret data itself).
+func decodeHelmRelease(secret *corev1.Secret) (*helmRelease, error) {
+	data, ok := secret.Data["release"]
+	if !ok {
+		return nil, errors.New("secret contains no release")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	// releases stored by very old Helm versions are not compressed
+	if bytes.HasPrefix(decoded, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress release: %w", err)
+		}
+		defer reader.Close()
+
+		decoded, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress release: %w", err)
+		}
+	}
+
+	release := &helmRelease{}
+	if err := json.Unmarshal(decoded, release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+
+	return release, nil
+}
+
+// parseManifest returns all objects in the given multi-document YAML manifest.
+func parseManifest(manifest string) ([]manifestObject, error) {
+	reader := yamlutil.NewYAMLReader(bufio.NewReader(bytes.NewBufferString(manifest)))
+
+	var objects []manifestObject
+	for {
+		doc, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		object := manifestObject{}
+		if err := yaml.Unmarshal(doc, &object); err != nil {
+			return nil, err
+		}
+
+		// skip empty documents
+		if object.Kind == "" {
+			continue
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
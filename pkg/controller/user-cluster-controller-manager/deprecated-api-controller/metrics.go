@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedapicontroller
+
+import (
+	"bytes"
+
+	"github.com/prometheus/common/expfmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// requestedDeprecatedAPIsMetric is set to 1 by the apiserver for every deprecated API
+// that has been requested since the apiserver was started.
+const requestedDeprecatedAPIsMetric = "apiserver_requested_deprecated_apis"
+
+// requestedAPI is a deprecated API that was requested by a client.
+type requestedAPI struct {
+	schema.GroupVersionResource
+
+	removedIn string
+}
+
+// parseRequestedAPIs returns all requested deprecated APIs that have a planned removal
+// from the given apiserver metrics (in the Prometheus text format).
+func parseRequestedAPIs(metrics []byte) ([]requestedAPI, error) {
+	parser := expfmt.TextParser{}
+
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(metrics))
+	if err != nil {
+		return nil, err
+	}
+
+	family, ok := families[requestedDeprecatedAPIsMetric]
+	if !ok {
+		return nil, nil
+	}
+
+	var result []requestedAPI
+	for _, metric := range family.GetMetric() {
+		if metric.GetGauge().GetValue() < 1 {
+			continue
+		}
+
+		api := requestedAPI{}
+		for _, label := range metric.GetLabel() {
+			switch label.GetName() {
+			case "group":
+				api.Group = label.GetValue()
+			case "version":
+				api.Version = label.GetValue()
+			case "resource":
+				api.Resource = label.GetValue()
+			case "removed_release":
+				api.removedIn = label.GetValue()
+			}
+		}
+
+		// deprecated APIs without a planned removal do not block upgrades
+		if api.removedIn == "" || api.Resource == "" {
+			continue
+		}
+
+		result = append(result, api)
+	}
+
+	return result, nil
+}
@@ -28,6 +28,7 @@ import (
 	"go.uber.org/zap"
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	"github.com/kubermatic/machine-controller/pkg/cloudprovider/util"
 	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 
 	corev1 "k8s.io/api/core/v1"
@@ -121,12 +122,18 @@ func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, mach
 	mask, _ := network.IPNet.Mask.Size()
 	cidr := fmt.Sprintf("%s/%d", ip.String(), mask)
 
+	ipFamily := r.getIPFamily(ip)
+	if cfg.Network != nil && cfg.Network.IPFamily != util.IPFamilyUnspecified {
+		ipFamily = cfg.Network.IPFamily
+	}
+
 	cfg.Network = &providerconfig.NetworkConfig{
 		CIDR:    cidr,
 		Gateway: network.Gateway.String(),
 		DNS: providerconfig.DNSConfig{
 			Servers: r.ipsToStrs(network.DNSServers),
 		},
+		IPFamily: ipFamily,
 	}
 
 	cfgSerialized, err := json.Marshal(cfg)
@@ -171,6 +178,33 @@ func (r *reconciler) getNextFreeIP(ctx context.Context) (net.IP, Network, error)
 	return nil, Network{}, errors.New("cidr exhausted")
 }
 
+// getIPFamily returns the IP family for a machine with the given static IP.
+// IPv4-only configurations are left unspecified, which machine-controller
+// interprets as IPv4. If the configured CIDR ranges contain both families,
+// the machine is dual-stack with the family of the static IP as primary.
+func (r *reconciler) getIPFamily(ip net.IP) util.IPFamily {
+	var hasIPv4, hasIPv6 bool
+	for _, network := range r.cidrRanges {
+		if network.IPNet.IP.To4() != nil {
+			hasIPv4 = true
+		} else {
+			hasIPv6 = true
+		}
+	}
+
+	isIPv4 := ip.To4() != nil
+	switch {
+	case isIPv4 && hasIPv6:
+		return util.IPFamilyIPv4IPv6
+	case !isIPv4 && hasIPv4:
+		return util.IPFamilyIPv6IPv4
+	case !isIPv4:
+		return util.IPFamilyIPv6
+	default:
+		return util.IPFamilyUnspecified
+	}
+}
+
 func (r *reconciler) ipsToStrs(ips []net.IP) []string {
 	strs := make([]string, len(ips))
 
@@ -219,7 +253,17 @@ func (r *reconciler) getUsedIPs(ctx context.Context) ([]net.IP, error) {
 
 func (r *reconciler) getNextFreeIPForCIDR(network Network, usedIps []net.IP) (net.IP, error) {
 	for ip := network.IP.Mask(network.IPNet.Mask); network.IPNet.Contains(ip); inc(ip) {
-		if ip[len(ip)-1] == 0 || ip[len(ip)-1] == 255 || ip.Equal(network.Gateway) {
+		if ip.Equal(network.Gateway) {
+			continue
+		}
+
+		// skip network and broadcast addresses for IPv4; IPv6 has no broadcast
+		// address, but the first address is the subnet-router anycast address
+		if ip4 := ip.To4(); ip4 != nil {
+			if ip4[len(ip4)-1] == 0 || ip4[len(ip4)-1] == 255 {
+				continue
+			}
+		} else if ip.Equal(network.IPNet.IP) {
 			continue
 		}
 
@@ -26,6 +26,7 @@ import (
 	"go.uber.org/zap"
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	"github.com/kubermatic/machine-controller/pkg/cloudprovider/util"
 	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 
@@ -99,6 +100,63 @@ func TestMultipleCIDRAllocation(t *testing.T) {
 	}
 }
 
+func TestIPv6CIDRAllocation(t *testing.T) {
+	t.Parallel()
+
+	nets := []Network{buildNet(t, "fd00::/120", "fd00::1", "fd00::53")}
+
+	m := createMachine("Wash")
+	r := newTestReconciler(nets, m)
+
+	if err := r.reconcile(context.Background(), zap.NewNop().Sugar(), m); err != nil {
+		t.Fatalf("failed to reconcile machine: %v", err)
+	}
+
+	resultMachine := &clusterv1alpha1.Machine{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, resultMachine); err != nil {
+		t.Fatalf("failed to get machine after reconciling: %v", err)
+	}
+
+	assertNetworkEquals(t, resultMachine, "fd00::2/120", "fd00::1", "fd00::53")
+	assertIPFamilyEquals(t, resultMachine, util.IPFamilyIPv6)
+}
+
+func TestDualStackCIDRAllocation(t *testing.T) {
+	t.Parallel()
+
+	nets := []Network{
+		buildNet(t, "192.168.0.0/30", "192.168.0.1", "8.8.8.8"),
+		buildNet(t, "fd00::/126", "fd00::1", "8.8.8.8"),
+	}
+
+	machines := []struct {
+		machineTestData
+		ipFamily util.IPFamily
+	}{
+		{machineTestData{"192.168.0.2/30", "192.168.0.1", createMachine("Badger")}, util.IPFamilyIPv4IPv6},
+		{machineTestData{"192.168.0.3/30", "192.168.0.1", createMachine("Saffron")}, util.IPFamilyIPv4IPv6},
+		{machineTestData{"fd00::2/126", "fd00::1", createMachine("Niska")}, util.IPFamilyIPv6IPv4},
+	}
+
+	machineObjects := []ctrlruntimeclient.Object{}
+	for _, m := range machines {
+		machineObjects = append(machineObjects, m.machine)
+	}
+
+	r := newTestReconciler(nets, machineObjects...)
+	for _, tuple := range machines {
+		if err := r.reconcile(context.Background(), zap.NewNop().Sugar(), tuple.machine); err != nil {
+			t.Errorf("failed to sync machine %q: %v", tuple.machine.Name, err)
+		}
+		reconciledMachine := &clusterv1alpha1.Machine{}
+		if err := r.Get(context.Background(), types.NamespacedName{Name: tuple.machine.Name, Namespace: tuple.machine.Namespace}, reconciledMachine); err != nil {
+			t.Errorf("failed to get machine %q after reconcile: %v", tuple.machine.Name, err)
+		}
+		assertNetworkEquals(t, reconciledMachine, tuple.ip, tuple.gw, "8.8.8.8")
+		assertIPFamilyEquals(t, reconciledMachine, tuple.ipFamily)
+	}
+}
+
 func TestReuseReleasedIP(t *testing.T) {
 	t.Parallel()
 
@@ -227,6 +285,17 @@ func assertNetworkEquals(t *testing.T, m *clusterv1alpha1.Machine, ip string, gw
 	}
 }
 
+func assertIPFamilyEquals(t *testing.T, m *clusterv1alpha1.Machine, ipFamily util.IPFamily) {
+	network, err := getNetworkForMachine(m)
+	if err != nil {
+		t.Fatalf("couldn't get network for machine %s, see: %v", m.Name, err)
+	}
+
+	if network.IPFamily != ipFamily {
+		t.Errorf("Assertion mismatch for machine %s, see: expected ip family '%s' but got '%s'", m.Name, ipFamily, network.IPFamily)
+	}
+}
+
 func getNetworkForMachine(m *clusterv1alpha1.Machine) (*providerconfig.NetworkConfig, error) {
 	cfg, err := providerconfig.GetConfig(m.Spec.ProviderSpec)
 	if err != nil {
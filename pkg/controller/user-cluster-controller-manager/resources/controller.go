@@ -260,6 +260,22 @@ func Add(
 		}
 	}
 
+	// Watch cluster so that the compliance scanning resources are deployed or removed when the scanning is toggled.
+	complianceWatch := &source.Kind{Type: &kubermaticv1.Cluster{}}
+	if err := complianceWatch.InjectCache(seedMgr.GetCache()); err != nil {
+		return fmt.Errorf("failed to inject cache in seed cluster watch for cluster: %w", err)
+	}
+	compliancePredicate := predicate.Funcs{
+		UpdateFunc: func(event event.UpdateEvent) bool {
+			oldCluster := event.ObjectOld.(*kubermaticv1.Cluster)
+			newCluster := event.ObjectNew.(*kubermaticv1.Cluster)
+			return oldCluster.Spec.IsComplianceScanningEnabled() != newCluster.Spec.IsComplianceScanningEnabled()
+		},
+	}
+	if err := c.Watch(complianceWatch, mapFn, compliancePredicate); err != nil {
+		return fmt.Errorf("failed to watch cluster in seed: %w", err)
+	}
+
 	// A very simple but limited way to express the first successful reconciling to the seed cluster
 	return registerReconciledCheck(fmt.Sprintf("%s-%s", controllerName, "reconciled_successfully_once"), func(_ *http.Request) error {
 		r.rLock.Lock()
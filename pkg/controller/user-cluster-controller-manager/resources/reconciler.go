@@ -31,6 +31,7 @@ import (
 	cabundle "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/ca-bundle"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/cloudinitsettings"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/clusterautoscaler"
+	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/compliance"
 	controllermanager "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/controller-manager"
 	coredns "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/core-dns"
 	csimigration "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/csi-migration"
@@ -53,6 +54,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/openvpn"
 	operatingsystemmanager "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/operating-system-manager"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/prometheus"
+	resourcequota "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/resource-quota"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/scheduler"
 	systembasicuser "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/system-basic-user"
 	userauth "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/user-auth"
@@ -145,6 +147,7 @@ func (r *reconciler) reconcile(ctx context.Context) error {
 	data.clusterVersion = clusterVersion
 	data.operatingSystemManagerEnabled = cluster.Spec.IsOperatingSystemManagerEnabled()
 	data.kubernetesDashboardEnabled = cluster.Spec.IsKubernetesDashboardEnabled()
+	data.complianceScanningEnabled = cluster.Spec.IsComplianceScanningEnabled()
 
 	// Must be first because of openshift
 	if err := r.ensureAPIServices(ctx, data); err != nil {
@@ -293,6 +296,12 @@ func (r *reconciler) reconcile(ctx context.Context) error {
 		}
 	}
 
+	if !data.complianceScanningEnabled {
+		if err := r.ensureComplianceScanningResourcesAreRemoved(ctx); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -338,6 +347,15 @@ func (r *reconciler) reconcileServiceAccounts(ctx context.Context, data reconcil
 		}
 	}
 
+	if data.complianceScanningEnabled {
+		creators = []reconciling.NamedServiceAccountReconcilerFactory{
+			compliance.ServiceAccountReconciler(),
+		}
+		if err := reconciling.ReconcileServiceAccounts(ctx, creators, compliance.Namespace, r.Client); err != nil {
+			return fmt.Errorf("failed to reconcile ServiceAccounts in the namespace %s: %w", compliance.Namespace, err)
+		}
+	}
+
 	cloudInitSAReconciler := []reconciling.NamedServiceAccountReconcilerFactory{
 		cloudinitsettings.ServiceAccountReconciler(),
 	}
@@ -703,6 +721,7 @@ func (r *reconciler) reconcileValidatingWebhookConfigurations(ctx context.Contex
 	creators := []reconciling.NamedValidatingWebhookConfigurationReconcilerFactory{
 		applications.ApplicationInstallationValidatingWebhookConfigurationReconciler(data.caCert.Cert, r.namespace),
 		machine.ValidatingWebhookConfigurationReconciler(data.caCert.Cert, r.namespace),
+		resourcequota.ValidatingWebhookConfigurationReconciler(data.caCert.Cert, r.namespace),
 	}
 	if r.opaIntegration {
 		creators = append(creators, gatekeeper.ValidatingWebhookConfigurationReconciler(r.opaWebhookTimeout))
@@ -1013,6 +1032,10 @@ func (r *reconciler) reconcileNamespaces(ctx context.Context, data reconcileData
 		creators = append(creators, kubernetesdashboard.NamespaceReconciler)
 	}
 
+	if data.complianceScanningEnabled {
+		creators = append(creators, compliance.NamespaceReconciler)
+	}
+
 	if r.opaIntegration {
 		creators = append(creators, gatekeeper.NamespaceReconciler)
 		creators = append(creators, gatekeeper.KubeSystemLabeler)
@@ -1165,6 +1188,7 @@ type reconcileData struct {
 	k8sServiceEndpointPort        int32
 	reconcileK8sSvcEndpoints      bool
 	kubernetesDashboardEnabled    bool
+	complianceScanningEnabled     bool
 	operatingSystemManagerEnabled bool
 	coreDNSReplicas               *int32
 }
@@ -1410,6 +1434,16 @@ func (r *reconciler) ensureKubernetesDashboardResourcesAreRemoved(ctx context.Co
 	return nil
 }
 
+func (r *reconciler) ensureComplianceScanningResourcesAreRemoved(ctx context.Context) error {
+	for _, resource := range compliance.ResourcesForDeletion() {
+		err := r.Client.Delete(ctx, resource)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to ensure compliance scanning resources are removed/not present: %w", err)
+		}
+	}
+	return nil
+}
+
 func (r *reconciler) getUserClusterMonitoringAgentCustomScrapeConfigs(ctx context.Context) (string, error) {
 	if r.userClusterMLA.MonitoringAgentScrapeConfigPrefix == "" {
 		return "", nil
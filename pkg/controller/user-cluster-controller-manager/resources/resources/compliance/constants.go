@@ -0,0 +1,35 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliance
+
+const (
+	// Namespace is the namespace in the user cluster in which the node scans are run.
+	Namespace = "kubermatic-compliance"
+	// AppName is used as the app label of the node scan Jobs.
+	AppName = "kube-bench"
+	// ServiceAccountName is the name of the ServiceAccount the node scans run as.
+	ServiceAccountName = "kube-bench"
+
+	// Benchmark is the version of the CIS Kubernetes benchmark the nodes are checked against.
+	Benchmark = "cis-1.23"
+
+	// NodeAnnotation is set on the node scan Jobs and contains the name of the scanned node.
+	NodeAnnotation = "kubermatic.k8c.io/compliance-scan-node"
+
+	imageName = "aquasec/kube-bench"
+	imageTag  = "v0.6.15"
+)
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compliance
+
+import (
+	"fmt"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/registry"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// scanTimeout is the time after which a node scan is aborted. A scan usually takes a few seconds.
+const scanTimeout = 600
+
+// hostPaths are the directories of the node that kube-bench inspects, mapped to their mount
+// path in the container. The binaries are mounted to the location kube-bench expects them
+// at to determine the kubelet version.
+var hostPaths = []struct {
+	name      string
+	hostPath  string
+	mountPath string
+}{
+	{name: "var-lib-kubelet", hostPath: "/var/lib/kubelet", mountPath: "/var/lib/kubelet"},
+	{name: "etc-systemd", hostPath: "/etc/systemd", mountPath: "/etc/systemd"},
+	{name: "lib-systemd", hostPath: "/lib/systemd", mountPath: "/lib/systemd"},
+	{name: "etc-kubernetes", hostPath: "/etc/kubernetes", mountPath: "/etc/kubernetes"},
+	{name: "usr-bin", hostPath: "/usr/bin", mountPath: "/usr/local/mount-from-host/bin"},
+	{name: "etc-cni-netd", hostPath: "/etc/cni/net.d", mountPath: "/etc/cni/net.d"},
+	{name: "opt-cni-bin", hostPath: "/opt/cni/bin", mountPath: "/opt/cni/bin"},
+}
+
+// ScanJob returns a Job that runs the node checks of the CIS benchmark on the given node
+// and writes the results as JSON to its log.
+func ScanJob(nodeName string, imageRewriter registry.ImageRewriter) (*batchv1.Job, error) {
+	image, err := imageRewriter(fmt.Sprintf("%s/%s:%s", resources.RegistryDocker, imageName, imageTag))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewrite image: %w", err)
+	}
+
+	var (
+		volumes      []corev1.Volume
+		volumeMounts []corev1.VolumeMount
+	)
+
+	for _, path := range hostPaths {
+		volumes = append(volumes, corev1.Volume{
+			Name: path.name,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: path.hostPath,
+				},
+			},
+		})
+
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      path.name,
+			MountPath: path.mountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	labels := resources.BaseAppLabels(AppName, nil)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: AppName + "-",
+			Namespace:    Namespace,
+			Labels:       labels,
+			Annotations: map[string]string{
+				NodeAnnotation: nodeName,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          pointer.Int32(0),
+			ActiveDeadlineSeconds: pointer.Int64(scanTimeout),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					NodeName:                     nodeName,
+					HostPID:                      true,
+					RestartPolicy:                corev1.RestartPolicyNever,
+					ServiceAccountName:           ServiceAccountName,
+					AutomountServiceAccountToken: pointer.Bool(false),
+					// the node has to be scanned regardless of its taints
+					Tolerations: []corev1.Toleration{
+						{
+							Operator: corev1.TolerationOpExists,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    AppName,
+							Image:   image,
+							Command: []string{"kube-bench", "run", "--targets", "node", "--benchmark", Benchmark, "--json"},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("32Mi"),
+									corev1.ResourceCPU:    resource.MustParse("10m"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("128Mi"),
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+								},
+							},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}, nil
+}
@@ -35,7 +35,7 @@ const (
 	machineValidatingWebhookConfigurationName = "kubermatic-machine-validation"
 )
 
-// ValidatingWebhookConfigurationReconciler returns the ValidatingWebhookConfiguration for the machine CRD.
+// ValidatingWebhookConfigurationReconciler returns the ValidatingWebhookConfiguration for the machine and machinedeployment CRDs.
 func ValidatingWebhookConfigurationReconciler(caCert *x509.Certificate, namespace string) reconciling.NamedValidatingWebhookConfigurationReconcilerFactory {
 	return func() (string, reconciling.ValidatingWebhookConfigurationReconciler) {
 		return machineValidatingWebhookConfigurationName, func(hook *admissionregistrationv1.ValidatingWebhookConfiguration) (*admissionregistrationv1.ValidatingWebhookConfiguration, error) {
@@ -44,11 +44,8 @@ func ValidatingWebhookConfigurationReconciler(caCert *x509.Certificate, namespac
 			sideEffects := admissionregistrationv1.SideEffectClassNone
 			scope := admissionregistrationv1.NamespacedScope
 
-			url := fmt.Sprintf("https://%s.%s.svc.cluster.local.:%d/validate-cluster-k8s-io-v1alpha1-machine",
-				resources.UserClusterWebhookServiceName,
-				namespace,
-				resources.UserClusterWebhookUserListenPort,
-			)
+			machineURL := webhookURL(namespace, "machine")
+			machineDeploymentURL := webhookURL(namespace, "machinedeployment")
 
 			hook.Webhooks = []admissionregistrationv1.ValidatingWebhook{
 				{
@@ -60,7 +57,7 @@ func ValidatingWebhookConfigurationReconciler(caCert *x509.Certificate, namespac
 					TimeoutSeconds:          pointer.Int32(3),
 					ClientConfig: admissionregistrationv1.WebhookClientConfig{
 						CABundle: triple.EncodeCertPEM(caCert),
-						URL:      &url,
+						URL:      &machineURL,
 					},
 					ObjectSelector:    &metav1.LabelSelector{},
 					NamespaceSelector: &metav1.LabelSelector{},
@@ -78,8 +75,44 @@ func ValidatingWebhookConfigurationReconciler(caCert *x509.Certificate, namespac
 						},
 					},
 				},
+				{
+					Name:                    "machinedeployments.cluster.k8c.io", // this should be a FQDN
+					AdmissionReviewVersions: []string{admissionregistrationv1.SchemeGroupVersion.Version, admissionregistrationv1beta1.SchemeGroupVersion.Version},
+					MatchPolicy:             &matchPolicy,
+					FailurePolicy:           &failurePolicy,
+					SideEffects:             &sideEffects,
+					TimeoutSeconds:          pointer.Int32(3),
+					ClientConfig: admissionregistrationv1.WebhookClientConfig{
+						CABundle: triple.EncodeCertPEM(caCert),
+						URL:      &machineDeploymentURL,
+					},
+					ObjectSelector:    &metav1.LabelSelector{},
+					NamespaceSelector: &metav1.LabelSelector{},
+					Rules: []admissionregistrationv1.RuleWithOperations{
+						{
+							Rule: admissionregistrationv1.Rule{
+								APIGroups:   []string{clusterv1alpha1.SchemeGroupVersion.Group},
+								APIVersions: []string{clusterv1alpha1.SchemeGroupVersion.Version},
+								Resources:   []string{"machinedeployments"},
+								Scope:       &scope,
+							},
+							Operations: []admissionregistrationv1.OperationType{
+								admissionregistrationv1.Create,
+							},
+						},
+					},
+				},
 			}
 			return hook, nil
 		}
 	}
 }
+
+func webhookURL(namespace, kind string) string {
+	return fmt.Sprintf("https://%s.%s.svc.cluster.local.:%d/validate-cluster-k8s-io-v1alpha1-%s",
+		resources.UserClusterWebhookServiceName,
+		namespace,
+		resources.UserClusterWebhookUserListenPort,
+		kind,
+	)
+}
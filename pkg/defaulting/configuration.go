@@ -847,6 +847,31 @@ command:
   esac
 `
 
+const DefaultBackupVerifyContainer = `
+name: download-container
+image: d3fk/s3cmd@sha256:2061883abbf0ebcf0ea3d5d218558c9c229f212e9c08af4acdaa3758980eb67a
+command:
+- /bin/sh
+- -c
+- |
+  set -e
+
+  SSL_FLAGS="--ca-certs=/etc/ca-bundle/ca-bundle.pem"
+  if [ "${INSECURE:-false}" == "true" ]; then
+    SSL_FLAGS="--no-ssl"
+  fi
+
+  s3cmd $SSL_FLAGS \
+    --access_key=$ACCESS_KEY_ID \
+    --secret_key=$SECRET_ACCESS_KEY \
+    --host=$ENDPOINT \
+    --host-bucket='%(bucket).'$ENDPOINT \
+    get --force s3://$BUCKET_NAME/$CLUSTER-$BACKUP_TO_VERIFY /backup/snapshot.db
+volumeMounts:
+- name: etcd-backup
+  mountPath: /backup
+`
+
 const DefaultBackupCleanupContainer = `
 name: cleanup-container
 image: quay.io/kubermatic/s3-storer:v0.1.6
@@ -130,16 +130,7 @@ func (r *reconciler) reconcile(ctx context.Context, resourceQuota *kubermaticv1.
 			}
 			return fmt.Errorf("error getting seed %q resource quota: %w", seed, err)
 		}
-		localUsage := seedResourceQuota.Status.LocalUsage
-		if localUsage.CPU != nil {
-			globalUsage.CPU.Add(*localUsage.CPU)
-		}
-		if localUsage.Memory != nil {
-			globalUsage.Memory.Add(*localUsage.Memory)
-		}
-		if localUsage.Storage != nil {
-			globalUsage.Storage.Add(*localUsage.Storage)
-		}
+		globalUsage.Add(seedResourceQuota.Status.LocalUsage)
 	}
 
 	if err := r.ensureGlobalUsage(ctx, log, resourceQuota, globalUsage); err != nil {
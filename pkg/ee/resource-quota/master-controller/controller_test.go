@@ -37,6 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -56,9 +57,14 @@ func TestReconcile(t *testing.T) {
 		seedClients   map[string]ctrlruntimeclient.Client
 	}{
 		{
-			name:          "scenario 1: calculate rq global usage",
-			requestName:   rqName,
-			expectedUsage: *genResourceDetails("7", "7G", "18G"),
+			name:        "scenario 1: calculate rq global usage",
+			requestName: rqName,
+			expectedUsage: func() kubermaticv1.ResourceDetails {
+				usage := genResourceDetails("7", "7G", "18G")
+				usage.Clusters = pointer.Int64(3)
+				usage.LoadBalancers = pointer.Int64(4)
+				return *usage
+			}(),
 			masterClient: fakectrlruntimeclient.
 				NewClientBuilder().
 				WithScheme(scheme).
@@ -68,12 +74,21 @@ func TestReconcile(t *testing.T) {
 				"first": fakectrlruntimeclient.
 					NewClientBuilder().
 					WithScheme(scheme).
-					WithObjects(genResourceQuota(rqName, *genResourceDetails("2", "5G", "10G"))).
+					WithObjects(genResourceQuota(rqName, func() kubermaticv1.ResourceDetails {
+						usage := genResourceDetails("2", "5G", "10G")
+						usage.Clusters = pointer.Int64(1)
+						return *usage
+					}())).
 					Build(),
 				"second": fakectrlruntimeclient.
 					NewClientBuilder().
 					WithScheme(scheme).
-					WithObjects(genResourceQuota(rqName, *genResourceDetails("5", "2G", "8G"))).
+					WithObjects(genResourceQuota(rqName, func() kubermaticv1.ResourceDetails {
+						usage := genResourceDetails("5", "2G", "8G")
+						usage.Clusters = pointer.Int64(2)
+						usage.LoadBalancers = pointer.Int64(4)
+						return *usage
+					}())).
 					Build(),
 			},
 		},
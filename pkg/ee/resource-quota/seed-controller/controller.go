@@ -43,6 +43,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -146,18 +147,10 @@ func (r *reconciler) reconcile(ctx context.Context, resourceQuota *kubermaticv1.
 	}
 
 	localUsage := kubermaticv1.NewResourceDetails(resource.Quantity{}, resource.Quantity{}, resource.Quantity{})
+	localUsage.Clusters = pointer.Int64(int64(len(clusterList.Items)))
 	for _, cluster := range clusterList.Items {
 		if cluster.Status.ResourceUsage != nil {
-			clusterUsage := cluster.Status.ResourceUsage
-			if clusterUsage.CPU != nil {
-				localUsage.CPU.Add(*clusterUsage.CPU)
-			}
-			if clusterUsage.Memory != nil {
-				localUsage.Memory.Add(*clusterUsage.Memory)
-			}
-			if clusterUsage.Storage != nil {
-				localUsage.Storage.Add(*clusterUsage.Storage)
-			}
+			localUsage.Add(*cluster.Status.ResourceUsage)
 		}
 	}
 
@@ -189,9 +182,10 @@ func (r *reconciler) ensureLocalUsage(ctx context.Context, log *zap.SugaredLogge
 
 func withClusterEventFilter() predicate.Predicate {
 	return predicate.Funcs{
-		// when cluster is created, no point to calculate yet as the machines are not created
+		// when cluster is created, its machines do not exist yet, but the number
+		// of clusters in the project has changed
 		CreateFunc: func(e event.CreateEvent) bool {
-			return false
+			return true
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			oldCluster, ok := e.ObjectOld.(*kubermaticv1.Cluster)
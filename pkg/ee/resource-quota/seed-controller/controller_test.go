@@ -36,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -63,11 +64,16 @@ func TestReconcile(t *testing.T) {
 				NewClientBuilder().
 				WithScheme(scheme).
 				WithObjects(genResourceQuota(rqName),
-					genCluster("c1", projectId, "2", "5G", "10G"),
-					genCluster("c2", projectId, "5", "2G", "8G"),
-					genCluster("notSameProjectCluster", "impostor", "3", "3G", "3G")).
+					genCluster("c1", projectId, "2", "5G", "10G", 2),
+					genCluster("c2", projectId, "5", "2G", "8G", 3),
+					genCluster("notSameProjectCluster", "impostor", "3", "3G", "3G", 4)).
 				Build(),
-			expectedUsage: *genResourceDetails("7", "7G", "18G"),
+			expectedUsage: func() kubermaticv1.ResourceDetails {
+				usage := genResourceDetails("7", "7G", "18G")
+				usage.Clusters = pointer.Int64(2)
+				usage.Nodes = pointer.Int64(5)
+				return *usage
+			}(),
 		},
 	}
 
@@ -116,11 +122,12 @@ func genResourceDetails(cpu, mem, storage string) *kubermaticv1.ResourceDetails
 	return kubermaticv1.NewResourceDetails(resource.MustParse(cpu), resource.MustParse(mem), resource.MustParse(storage))
 }
 
-func genCluster(name, projectId, cpu, mem, storage string) *kubermaticv1.Cluster {
+func genCluster(name, projectId, cpu, mem, storage string, nodes int64) *kubermaticv1.Cluster {
 	cluster := &kubermaticv1.Cluster{}
 	cluster.Name = name
 	cluster.Labels = map[string]string{kubermaticv1.ProjectIDLabelKey: projectId}
 	cluster.Status.ResourceUsage = genResourceDetails(cpu, mem, storage)
+	cluster.Status.ResourceUsage.Nodes = pointer.Int64(nodes)
 
 	return cluster
 }
@@ -0,0 +1,329 @@
+//go:build ee
+
+/*
+                  Kubermatic Enterprise Read-Only License
+                         Version 1.0 ("KERO-1.0”)
+                     Copyright © 2023 Kubermatic GmbH
+
+   1.	You may only view, read and display for studying purposes the source
+      code of the software licensed under this license, and, to the extent
+      explicitly provided under this license, the binary code.
+   2.	Any use of the software which exceeds the foregoing right, including,
+      without limitation, its execution, compilation, copying, modification
+      and distribution, is expressly prohibited.
+   3.	THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND,
+      EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+      MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+      IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY
+      CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+      TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+      SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+   END OF TERMS AND CONDITIONS
+*/
+
+package thresholdcontroller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// This controller compares the global usage of ResourceQuotas with their warning
+// thresholds and warns the quota subject before the webhooks start denying requests.
+const ControllerName = "kkp-resource-quota-threshold-controller"
+
+const (
+	thresholdReachedReason  = "ThresholdReached"
+	belowThresholdsReason   = "BelowThresholds"
+	thresholdReachedEvent   = "ResourceQuotaThresholdReached"
+	notificationFailedEvent = "ResourceQuotaNotificationFailed"
+)
+
+type reconciler struct {
+	log          *zap.SugaredLogger
+	masterClient ctrlruntimeclient.Client
+	recorder     record.EventRecorder
+	httpClient   *http.Client
+}
+
+func Add(masterMgr manager.Manager,
+	log *zap.SugaredLogger,
+	numWorkers int,
+) error {
+	r := &reconciler{
+		log:          log.Named(ControllerName),
+		masterClient: masterMgr.GetClient(),
+		recorder:     masterMgr.GetEventRecorderFor(ControllerName),
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+
+	c, err := controller.New(ControllerName, masterMgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: numWorkers})
+	if err != nil {
+		return fmt.Errorf("failed to construct controller: %w", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.ResourceQuota{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to watch resource quotas: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile compares the global usage of a resource quota with its warning thresholds.
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("request", request)
+	log.Debug("Reconciling")
+
+	resourceQuota := &kubermaticv1.ResourceQuota{}
+	if err := r.masterClient.Get(ctx, request.NamespacedName, resourceQuota); err != nil {
+		if apierrors.IsNotFound(err) {
+			deleteMetrics(request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("failed to get resource quota %q: %w", request.Name, err)
+	}
+
+	err := r.reconcile(ctx, resourceQuota, log)
+	if err != nil {
+		log.Errorw("ReconcilingError", zap.Error(err))
+		r.recorder.Event(resourceQuota, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, resourceQuota *kubermaticv1.ResourceQuota, log *zap.SugaredLogger) error {
+	if !resourceQuota.DeletionTimestamp.IsZero() {
+		deleteMetrics(resourceQuota.Name)
+		return nil
+	}
+
+	usages := getResourceUsages(resourceQuota)
+	setMetrics(resourceQuota, usages)
+
+	reached := map[string]kubermaticv1.ResourceQuotaThreshold{}
+	notified := map[string]kubermaticv1.ResourceQuotaThreshold{}
+	var crossed, undelivered []resourceUsage
+
+	for _, usage := range usages {
+		threshold := highestReachedThreshold(resourceQuota.Spec.WarningThresholds, usage.ratio)
+		if threshold == 0 {
+			continue
+		}
+
+		usage.threshold = threshold
+		reached[usage.name] = threshold
+		if threshold > resourceQuota.Status.ReachedThresholds[usage.name] {
+			crossed = append(crossed, usage)
+		}
+
+		if resourceQuota.Spec.Notification == nil {
+			continue
+		}
+
+		// lower thresholds stay delivered, higher ones still have to be sent
+		if delivered := resourceQuota.Status.NotifiedThresholds[usage.name]; delivered >= threshold {
+			notified[usage.name] = threshold
+		} else {
+			if delivered > 0 {
+				notified[usage.name] = delivered
+			}
+			undelivered = append(undelivered, usage)
+		}
+	}
+
+	if len(crossed) > 0 {
+		if err := r.emitEvents(ctx, log, resourceQuota, crossed); err != nil {
+			return err
+		}
+	}
+
+	// webhook failures must neither block the events nor the status, so they are only
+	// returned after the status has been updated and just the failed notifications are retried
+	var notifyErr error
+	if len(undelivered) > 0 {
+		notifyErr = r.notify(ctx, resourceQuota, undelivered, notified)
+	}
+
+	if len(reached) == 0 {
+		reached = nil
+	}
+	if len(notified) == 0 {
+		notified = nil
+	}
+
+	if err := kubermaticv1helper.UpdateResourceQuotaStatus(ctx, r.masterClient, resourceQuota, func(rq *kubermaticv1.ResourceQuota) {
+		rq.Status.ReachedThresholds = reached
+		rq.Status.NotifiedThresholds = notified
+		setThresholdCondition(rq, reached)
+	}); err != nil {
+		return err
+	}
+
+	return notifyErr
+}
+
+// emitEvents informs the quota subject about newly crossed thresholds.
+func (r *reconciler) emitEvents(ctx context.Context, log *zap.SugaredLogger, resourceQuota *kubermaticv1.ResourceQuota, crossed []resourceUsage) error {
+	subject, err := r.getSubject(ctx, resourceQuota)
+	if err != nil {
+		return err
+	}
+
+	for _, usage := range crossed {
+		log.Infow("Resource quota threshold reached", "resource", usage.name, "threshold", usage.threshold)
+
+		msg := fmt.Sprintf("%s usage reached %d%% of the quota (used/quota %s/%s)", usage.name, usage.threshold, usage.used, usage.quota)
+		r.recorder.Event(resourceQuota, corev1.EventTypeWarning, thresholdReachedEvent, msg)
+		if subject != nil {
+			r.recorder.Event(subject, corev1.EventTypeWarning, thresholdReachedEvent, msg)
+		}
+	}
+
+	return nil
+}
+
+// notify sends the notifications for the given usages to the webhook and records every
+// successful delivery in notified.
+func (r *reconciler) notify(ctx context.Context, resourceQuota *kubermaticv1.ResourceQuota, usages []resourceUsage, notified map[string]kubermaticv1.ResourceQuotaThreshold) error {
+	var errs []error
+
+	for _, usage := range usages {
+		if err := r.callWebhook(ctx, resourceQuota.Spec.Notification.WebhookURL, newNotification(resourceQuota, usage)); err != nil {
+			err = fmt.Errorf("failed to notify webhook about %s usage: %w", usage.name, err)
+			r.recorder.Event(resourceQuota, corev1.EventTypeWarning, notificationFailedEvent, err.Error())
+			errs = append(errs, err)
+			continue
+		}
+
+		notified[usage.name] = usage.threshold
+	}
+
+	return kerrors.NewAggregate(errs)
+}
+
+// getSubject returns the object the quota applies to, or nil if it does not exist.
+func (r *reconciler) getSubject(ctx context.Context, resourceQuota *kubermaticv1.ResourceQuota) (ctrlruntimeclient.Object, error) {
+	if resourceQuota.Spec.Subject.Kind != kubermaticv1.ProjectSubjectKind {
+		return nil, nil
+	}
+
+	project := &kubermaticv1.Project{}
+	if err := r.masterClient.Get(ctx, types.NamespacedName{Name: resourceQuota.Spec.Subject.Name}, project); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+func setThresholdCondition(rq *kubermaticv1.ResourceQuota, reached map[string]kubermaticv1.ResourceQuotaThreshold) {
+	if len(reached) == 0 {
+		kubermaticv1helper.SetResourceQuotaCondition(rq, kubermaticv1.ResourceQuotaConditionThresholdReached, corev1.ConditionFalse,
+			belowThresholdsReason, "All resources are below the warning thresholds.")
+		return
+	}
+
+	names := make([]string, 0, len(reached))
+	for name := range reached {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s (%d%%)", name, reached[name]))
+	}
+
+	kubermaticv1helper.SetResourceQuotaCondition(rq, kubermaticv1.ResourceQuotaConditionThresholdReached, corev1.ConditionTrue,
+		thresholdReachedReason, fmt.Sprintf("Warning thresholds reached for %s.", strings.Join(parts, ", ")))
+}
+
+// highestReachedThreshold returns the highest threshold that the given usage ratio
+// (1.0 being 100%) has reached, or 0 if none has been reached.
+func highestReachedThreshold(thresholds []kubermaticv1.ResourceQuotaThreshold, ratio float64) kubermaticv1.ResourceQuotaThreshold {
+	var highest kubermaticv1.ResourceQuotaThreshold
+	for _, threshold := range thresholds {
+		if ratio*100 >= float64(threshold) && threshold > highest {
+			highest = threshold
+		}
+	}
+
+	return highest
+}
+
+type resourceUsage struct {
+	name      string
+	used      string
+	quota     string
+	ratio     float64
+	threshold kubermaticv1.ResourceQuotaThreshold
+}
+
+// getResourceUsages returns the usage ratio of all resources that have a non-zero quota.
+func getResourceUsages(rq *kubermaticv1.ResourceQuota) []resourceUsage {
+	quota := rq.Spec.Quota
+	used := rq.Status.GlobalUsage
+
+	var usages []resourceUsage
+
+	addQuantity := func(name string, q, u *resource.Quantity) {
+		if q == nil || q.IsZero() {
+			return
+		}
+		usage := resourceUsage{name: name, quota: q.String(), used: "0"}
+		if u != nil {
+			usage.used = u.String()
+			usage.ratio = u.AsApproximateFloat64() / q.AsApproximateFloat64()
+		}
+		usages = append(usages, usage)
+	}
+
+	addCount := func(name string, q, u *int64) {
+		if q == nil || *q == 0 {
+			return
+		}
+		usage := resourceUsage{name: name, quota: fmt.Sprint(*q), used: "0"}
+		if u != nil {
+			usage.used = fmt.Sprint(*u)
+			usage.ratio = float64(*u) / float64(*q)
+		}
+		usages = append(usages, usage)
+	}
+
+	addQuantity("cpu", quota.CPU, used.CPU)
+	addQuantity("memory", quota.Memory, used.Memory)
+	addQuantity("storage", quota.Storage, used.Storage)
+	addCount("clusters", quota.Clusters, used.Clusters)
+	addCount("machineDeployments", quota.MachineDeployments, used.MachineDeployments)
+	addCount("nodes", quota.Nodes, used.Nodes)
+	addCount("loadBalancers", quota.LoadBalancers, used.LoadBalancers)
+	addCount("persistentVolumes", quota.PersistentVolumes, used.PersistentVolumes)
+
+	return usages
+}
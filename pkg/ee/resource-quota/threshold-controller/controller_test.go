@@ -0,0 +1,268 @@
+//go:build ee
+
+/*
+                  Kubermatic Enterprise Read-Only License
+                         Version 1.0 ("KERO-1.0”)
+                     Copyright © 2023 Kubermatic GmbH
+
+   1.	You may only view, read and display for studying purposes the source
+      code of the software licensed under this license, and, to the extent
+      explicitly provided under this license, the binary code.
+   2.	Any use of the software which exceeds the foregoing right, including,
+      without limitation, its execution, compilation, copying, modification
+      and distribution, is expressly prohibited.
+   3.	THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND,
+      EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+      MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+      IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY
+      CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+      TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+      SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+   END OF TERMS AND CONDITIONS
+*/
+
+package thresholdcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/test/diff"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	rqName    = "project-project1"
+	projectID = "project1"
+)
+
+func TestReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kubermaticv1.AddToScheme(scheme)
+
+	testCases := []struct {
+		name                  string
+		resourceQuota         *kubermaticv1.ResourceQuota
+		failingResource       string
+		expectedThresholds    map[string]kubermaticv1.ResourceQuotaThreshold
+		expectedNotified      map[string]kubermaticv1.ResourceQuotaThreshold
+		expectedStatus        corev1.ConditionStatus
+		expectedEvents        int
+		expectedNotifications []Notification
+		expectedError         bool
+	}{
+		{
+			name:           "scenario 1: usage below all thresholds",
+			resourceQuota:  genResourceQuota("3", 2, nil),
+			expectedStatus: corev1.ConditionFalse,
+		},
+		{
+			name:          "scenario 2: crossing thresholds emits events and notifications",
+			resourceQuota: genResourceQuota("9", 8, nil),
+			expectedThresholds: map[string]kubermaticv1.ResourceQuotaThreshold{
+				"cpu":      80,
+				"clusters": 80,
+			},
+			expectedNotified: map[string]kubermaticv1.ResourceQuotaThreshold{
+				"cpu":      80,
+				"clusters": 80,
+			},
+			expectedStatus: corev1.ConditionTrue,
+			// one event on the quota and one on the project for each resource
+			expectedEvents: 4,
+			expectedNotifications: []Notification{
+				{ResourceQuota: rqName, Subject: kubermaticv1.Subject{Name: projectID, Kind: kubermaticv1.ProjectSubjectKind}, Resource: "cpu", Threshold: 80, Used: "9", Quota: "10"},
+				{ResourceQuota: rqName, Subject: kubermaticv1.Subject{Name: projectID, Kind: kubermaticv1.ProjectSubjectKind}, Resource: "clusters", Threshold: 80, Used: "8", Quota: "10"},
+			},
+		},
+		{
+			name: "scenario 3: already reached thresholds are not notified again",
+			resourceQuota: genResourceQuota("9", 10, map[string]kubermaticv1.ResourceQuotaThreshold{
+				"cpu":      80,
+				"clusters": 80,
+			}),
+			expectedThresholds: map[string]kubermaticv1.ResourceQuotaThreshold{
+				"cpu":      80,
+				"clusters": 95,
+			},
+			expectedNotified: map[string]kubermaticv1.ResourceQuotaThreshold{
+				"cpu":      80,
+				"clusters": 95,
+			},
+			expectedStatus: corev1.ConditionTrue,
+			expectedEvents: 2,
+			expectedNotifications: []Notification{
+				{ResourceQuota: rqName, Subject: kubermaticv1.Subject{Name: projectID, Kind: kubermaticv1.ProjectSubjectKind}, Resource: "clusters", Threshold: 95, Used: "10", Quota: "10"},
+			},
+		},
+		{
+			name: "scenario 4: usage dropping below thresholds resets the status",
+			resourceQuota: genResourceQuota("1", 1, map[string]kubermaticv1.ResourceQuotaThreshold{
+				"cpu": 95,
+			}),
+			expectedStatus: corev1.ConditionFalse,
+		},
+		{
+			name:            "scenario 5: failing notifications do not block events and status",
+			resourceQuota:   genResourceQuota("9", 8, nil),
+			failingResource: "cpu",
+			expectedThresholds: map[string]kubermaticv1.ResourceQuotaThreshold{
+				"cpu":      80,
+				"clusters": 80,
+			},
+			expectedNotified: map[string]kubermaticv1.ResourceQuotaThreshold{
+				"clusters": 80,
+			},
+			expectedStatus: corev1.ConditionTrue,
+			// the threshold events plus one for the failed notification and one for the reconciling error
+			expectedEvents: 6,
+			expectedNotifications: []Notification{
+				{ResourceQuota: rqName, Subject: kubermaticv1.Subject{Name: projectID, Kind: kubermaticv1.ProjectSubjectKind}, Resource: "cpu", Threshold: 80, Used: "9", Quota: "10"},
+				{ResourceQuota: rqName, Subject: kubermaticv1.Subject{Name: projectID, Kind: kubermaticv1.ProjectSubjectKind}, Resource: "clusters", Threshold: 80, Used: "8", Quota: "10"},
+			},
+			expectedError: true,
+		},
+		{
+			name: "scenario 6: only failed notifications are retried",
+			resourceQuota: func() *kubermaticv1.ResourceQuota {
+				rq := genResourceQuota("9", 8, map[string]kubermaticv1.ResourceQuotaThreshold{
+					"cpu":      80,
+					"clusters": 80,
+				})
+				rq.Status.NotifiedThresholds = map[string]kubermaticv1.ResourceQuotaThreshold{
+					"clusters": 80,
+				}
+				return rq
+			}(),
+			expectedThresholds: map[string]kubermaticv1.ResourceQuotaThreshold{
+				"cpu":      80,
+				"clusters": 80,
+			},
+			expectedNotified: map[string]kubermaticv1.ResourceQuotaThreshold{
+				"cpu":      80,
+				"clusters": 80,
+			},
+			expectedStatus: corev1.ConditionTrue,
+			expectedNotifications: []Notification{
+				{ResourceQuota: rqName, Subject: kubermaticv1.Subject{Name: projectID, Kind: kubermaticv1.ProjectSubjectKind}, Resource: "cpu", Threshold: 80, Used: "9", Quota: "10"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			var notifications []Notification
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := Notification{}
+				if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+					t.Errorf("failed to decode notification: %v", err)
+				}
+				notifications = append(notifications, n)
+				if n.Resource == tc.failingResource {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer server.Close()
+
+			tc.resourceQuota.Spec.Notification = &kubermaticv1.ResourceQuotaNotification{WebhookURL: server.URL}
+
+			project := &kubermaticv1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: projectID,
+				},
+			}
+
+			client := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(tc.resourceQuota, project).
+				Build()
+
+			recorder := record.NewFakeRecorder(10)
+			r := &reconciler{
+				log:          kubermaticlog.Logger,
+				masterClient: client,
+				recorder:     recorder,
+				httpClient:   server.Client(),
+			}
+
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: rqName}}
+			if _, err := r.Reconcile(ctx, request); (err != nil) != tc.expectedError {
+				t.Fatalf("Expected error: %v, but got: %v", tc.expectedError, err)
+			}
+
+			rq := &kubermaticv1.ResourceQuota{}
+			if err := client.Get(ctx, request.NamespacedName, rq); err != nil {
+				t.Fatalf("failed to get resource quota: %v", err)
+			}
+
+			if !diff.SemanticallyEqual(tc.expectedThresholds, rq.Status.ReachedThresholds) {
+				t.Fatalf("Reached thresholds differ:\n%v", diff.ObjectDiff(tc.expectedThresholds, rq.Status.ReachedThresholds))
+			}
+
+			if !diff.SemanticallyEqual(tc.expectedNotified, rq.Status.NotifiedThresholds) {
+				t.Fatalf("Notified thresholds differ:\n%v", diff.ObjectDiff(tc.expectedNotified, rq.Status.NotifiedThresholds))
+			}
+
+			condition := rq.Status.Conditions[kubermaticv1.ResourceQuotaConditionThresholdReached]
+			if condition.Status != tc.expectedStatus {
+				t.Fatalf("Expected condition status %q, got %q", tc.expectedStatus, condition.Status)
+			}
+
+			if len(recorder.Events) != tc.expectedEvents {
+				t.Fatalf("Expected %d events, got %d", tc.expectedEvents, len(recorder.Events))
+			}
+
+			if !diff.SemanticallyEqual(tc.expectedNotifications, notifications) {
+				t.Fatalf("Notifications differ:\n%v", diff.ObjectDiff(tc.expectedNotifications, notifications))
+			}
+		})
+	}
+}
+
+func genResourceQuota(usedCPU string, usedClusters int64, reached map[string]kubermaticv1.ResourceQuotaThreshold) *kubermaticv1.ResourceQuota {
+	cpuQuota := resource.MustParse("10")
+	cpuUsage := resource.MustParse(usedCPU)
+
+	return &kubermaticv1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: rqName,
+		},
+		Spec: kubermaticv1.ResourceQuotaSpec{
+			Subject: kubermaticv1.Subject{
+				Name: projectID,
+				Kind: kubermaticv1.ProjectSubjectKind,
+			},
+			Quota: kubermaticv1.ResourceDetails{
+				CPU:      &cpuQuota,
+				Clusters: pointer.Int64(10),
+			},
+			WarningThresholds: []kubermaticv1.ResourceQuotaThreshold{80, 95},
+		},
+		Status: kubermaticv1.ResourceQuotaStatus{
+			GlobalUsage: kubermaticv1.ResourceDetails{
+				CPU:      &cpuUsage,
+				Clusters: pointer.Int64(usedClusters),
+			},
+			ReachedThresholds:  reached,
+			NotifiedThresholds: reached,
+		},
+	}
+}
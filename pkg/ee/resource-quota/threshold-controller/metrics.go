@@ -0,0 +1,74 @@
+//go:build ee
+
+/*
+                  Kubermatic Enterprise Read-Only License
+                         Version 1.0 ("KERO-1.0”)
+                     Copyright © 2023 Kubermatic GmbH
+
+   1.	You may only view, read and display for studying purposes the source
+      code of the software licensed under this license, and, to the extent
+      explicitly provided under this license, the binary code.
+   2.	Any use of the software which exceeds the foregoing right, including,
+      without limitation, its execution, compilation, copying, modification
+      and distribution, is expressly prohibited.
+   3.	THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND,
+      EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+      MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+      IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY
+      CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+      TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+      SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+   END OF TERMS AND CONDITIONS
+*/
+
+package thresholdcontroller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+var (
+	usageRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kubermatic",
+			Subsystem: "resource_quota",
+			Name:      "usage_ratio",
+			Help:      "The global usage of a resource divided by its quota",
+		},
+		[]string{"resource_quota", "subject_kind", "subject_name", "resource"},
+	)
+
+	reachedThreshold = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kubermatic",
+			Subsystem: "resource_quota",
+			Name:      "reached_warning_threshold",
+			Help:      "The highest warning threshold (in percent) reached by a resource, 0 if none has been reached",
+		},
+		[]string{"resource_quota", "subject_kind", "subject_name", "resource"},
+	)
+)
+
+func init() {
+	// the master-controller-manager serves the metrics of the default registry.
+	prometheus.MustRegister(usageRatio, reachedThreshold)
+}
+
+func setMetrics(rq *kubermaticv1.ResourceQuota, usages []resourceUsage) {
+	// resources might have lost their quota since the last reconciliation
+	deleteMetrics(rq.Name)
+
+	for _, usage := range usages {
+		labels := []string{rq.Name, rq.Spec.Subject.Kind, rq.Spec.Subject.Name, usage.name}
+		usageRatio.WithLabelValues(labels...).Set(usage.ratio)
+		reachedThreshold.WithLabelValues(labels...).Set(float64(highestReachedThreshold(rq.Spec.WarningThresholds, usage.ratio)))
+	}
+}
+
+func deleteMetrics(resourceQuota string) {
+	usageRatio.DeletePartialMatch(prometheus.Labels{"resource_quota": resourceQuota})
+	reachedThreshold.DeletePartialMatch(prometheus.Labels{"resource_quota": resourceQuota})
+}
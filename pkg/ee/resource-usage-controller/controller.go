@@ -34,6 +34,7 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
 	userclustercontrollermanager "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager"
+	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
 	"k8c.io/kubermatic/v2/pkg/controller/util/predicate"
 	machinevalidation "k8c.io/kubermatic/v2/pkg/ee/validation/machine"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
@@ -43,6 +44,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -89,6 +91,25 @@ func Add(log *zap.SugaredLogger, seedMgr, userMgr manager.Manager, clusterName s
 		return fmt.Errorf("failed to establish watch for Machines: %w", err)
 	}
 
+	// Watch for changes to the other countable resources; as the reconciler always
+	// recalculates the entire usage, all events are funneled into a single request.
+	enqueueCluster := controllerutil.EnqueueConst(clusterName)
+
+	if err = c.Watch(
+		&source.Kind{Type: &clusterv1alpha1.MachineDeployment{}}, enqueueCluster, predicate.ByNamespace(metav1.NamespaceSystem)); err != nil {
+		return fmt.Errorf("failed to establish watch for MachineDeployments: %w", err)
+	}
+
+	if err = c.Watch(
+		&source.Kind{Type: &corev1.Service{}}, enqueueCluster, predicate.Factory(isLoadBalancer)); err != nil {
+		return fmt.Errorf("failed to establish watch for Services: %w", err)
+	}
+
+	if err = c.Watch(
+		&source.Kind{Type: &corev1.PersistentVolume{}}, enqueueCluster); err != nil {
+		return fmt.Errorf("failed to establish watch for PersistentVolumes: %w", err)
+	}
+
 	return nil
 }
 
@@ -137,9 +158,41 @@ func (r *reconciler) reconcile(ctx context.Context, cluster *kubermaticv1.Cluste
 		resourceUsage.Storage.Add(*resourceDetails.Storage())
 	}
 
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := r.userClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return fmt.Errorf("failed to list machine deployments: %w", err)
+	}
+
+	services := &corev1.ServiceList{}
+	if err := r.userClient.List(ctx, services); err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	var loadBalancers int64
+	for i := range services.Items {
+		if isLoadBalancer(&services.Items[i]) {
+			loadBalancers++
+		}
+	}
+
+	persistentVolumes := &corev1.PersistentVolumeList{}
+	if err := r.userClient.List(ctx, persistentVolumes); err != nil {
+		return fmt.Errorf("failed to list persistent volumes: %w", err)
+	}
+
+	resourceUsage.MachineDeployments = pointer.Int64(int64(len(machineDeployments.Items)))
+	resourceUsage.Nodes = pointer.Int64(int64(len(machines.Items)))
+	resourceUsage.LoadBalancers = pointer.Int64(loadBalancers)
+	resourceUsage.PersistentVolumes = pointer.Int64(int64(len(persistentVolumes.Items)))
+
 	cluster.Status.ResourceUsage = resourceUsage
 
 	return kubermaticv1helper.UpdateClusterStatus(ctx, r.seedClient, cluster, func(c *kubermaticv1.Cluster) {
 		c.Status.ResourceUsage = resourceUsage
 	})
 }
+
+func isLoadBalancer(obj ctrlruntimeclient.Object) bool {
+	service, ok := obj.(*corev1.Service)
+	return ok && service.Spec.Type == corev1.ServiceTypeLoadBalancer
+}
@@ -35,10 +35,14 @@ import (
 	"k8c.io/kubermatic/v2/pkg/test/diff"
 	"k8c.io/kubermatic/v2/pkg/test/generator"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -48,6 +52,7 @@ func TestReconcile(t *testing.T) {
 		name                  string
 		cluster               *kubermaticv1.Cluster
 		machines              []*clusterv1alpha1.Machine
+		objects               []ctrlruntimeclient.Object
 		expectedResourceUsage *kubermaticv1.ResourceDetails
 	}{
 		{
@@ -55,9 +60,13 @@ func TestReconcile(t *testing.T) {
 			cluster:  generator.GenDefaultCluster(),
 			machines: []*clusterv1alpha1.Machine{genFakeMachine("m1", "5", "5G", "10G")},
 			expectedResourceUsage: &kubermaticv1.ResourceDetails{
-				CPU:     getQuantity("5"),
-				Memory:  getQuantity("5G"),
-				Storage: getQuantity("10G"),
+				CPU:                getQuantity("5"),
+				Memory:             getQuantity("5G"),
+				Storage:            getQuantity("10G"),
+				MachineDeployments: pointer.Int64(0),
+				Nodes:              pointer.Int64(1),
+				LoadBalancers:      pointer.Int64(0),
+				PersistentVolumes:  pointer.Int64(0),
 			},
 		},
 		{
@@ -69,9 +78,13 @@ func TestReconcile(t *testing.T) {
 			}(),
 			machines: []*clusterv1alpha1.Machine{genFakeMachine("m1", "5", "5G", "10G")},
 			expectedResourceUsage: &kubermaticv1.ResourceDetails{
-				CPU:     getQuantity("5"),
-				Memory:  getQuantity("5G"),
-				Storage: getQuantity("10G"),
+				CPU:                getQuantity("5"),
+				Memory:             getQuantity("5G"),
+				Storage:            getQuantity("10G"),
+				MachineDeployments: pointer.Int64(0),
+				Nodes:              pointer.Int64(1),
+				LoadBalancers:      pointer.Int64(0),
+				PersistentVolumes:  pointer.Int64(0),
 			},
 		},
 		{
@@ -81,9 +94,13 @@ func TestReconcile(t *testing.T) {
 				genFakeMachine("m1", "5", "5G", "10G"),
 				genFakeMachine("m2", "2", "3G", "5G")},
 			expectedResourceUsage: &kubermaticv1.ResourceDetails{
-				CPU:     getQuantity("7"),
-				Memory:  getQuantity("8G"),
-				Storage: getQuantity("15G"),
+				CPU:                getQuantity("7"),
+				Memory:             getQuantity("8G"),
+				Storage:            getQuantity("15G"),
+				MachineDeployments: pointer.Int64(0),
+				Nodes:              pointer.Int64(2),
+				LoadBalancers:      pointer.Int64(0),
+				PersistentVolumes:  pointer.Int64(0),
 			},
 		},
 		{
@@ -94,9 +111,43 @@ func TestReconcile(t *testing.T) {
 				return c
 			}(),
 			expectedResourceUsage: &kubermaticv1.ResourceDetails{
-				CPU:     getQuantity("0"),
-				Memory:  getQuantity("0"),
-				Storage: getQuantity("0"),
+				CPU:                getQuantity("0"),
+				Memory:             getQuantity("0"),
+				Storage:            getQuantity("0"),
+				MachineDeployments: pointer.Int64(0),
+				Nodes:              pointer.Int64(0),
+				LoadBalancers:      pointer.Int64(0),
+				PersistentVolumes:  pointer.Int64(0),
+			},
+		},
+		{
+			name:    "scenario 5: count machine deployments, load balancers and persistent volumes",
+			cluster: generator.GenDefaultCluster(),
+			machines: []*clusterv1alpha1.Machine{
+				genFakeMachine("m1", "5", "5G", "10G"),
+			},
+			objects: []ctrlruntimeclient.Object{
+				genMachineDeployment("md1"),
+				genMachineDeployment("md2"),
+				// not reconciled by the machine-controller and thus not counted
+				func() ctrlruntimeclient.Object {
+					md := genMachineDeployment("md3")
+					md.Namespace = metav1.NamespaceDefault
+					return md
+				}(),
+				genService("lb1", corev1.ServiceTypeLoadBalancer),
+				genService("lb2", corev1.ServiceTypeLoadBalancer),
+				genService("internal", corev1.ServiceTypeClusterIP),
+				genPersistentVolume("pv1"),
+			},
+			expectedResourceUsage: &kubermaticv1.ResourceDetails{
+				CPU:                getQuantity("5"),
+				Memory:             getQuantity("5G"),
+				Storage:            getQuantity("10G"),
+				MachineDeployments: pointer.Int64(2),
+				Nodes:              pointer.Int64(1),
+				LoadBalancers:      pointer.Int64(2),
+				PersistentVolumes:  pointer.Int64(1),
 			},
 		},
 	}
@@ -108,6 +159,7 @@ func TestReconcile(t *testing.T) {
 			scheme := runtime.NewScheme()
 			_ = kubermaticv1.AddToScheme(scheme)
 			_ = clusterv1alpha1.AddToScheme(scheme)
+			_ = corev1.AddToScheme(scheme)
 
 			seedClientBuilder := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme)
 			seedClientBuilder.WithObjects(tc.cluster)
@@ -116,6 +168,7 @@ func TestReconcile(t *testing.T) {
 			for _, m := range tc.machines {
 				userClientBuilder.WithObjects(m)
 			}
+			userClientBuilder.WithObjects(tc.objects...)
 
 			seedClient := seedClientBuilder.Build()
 			userClient := userClientBuilder.Build()
@@ -161,3 +214,32 @@ func getQuantity(q string) *resource.Quantity {
 	res := resource.MustParse(q)
 	return &res
 }
+
+func genMachineDeployment(name string) *clusterv1alpha1.MachineDeployment {
+	return &clusterv1alpha1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+}
+
+func genService(name string, serviceType corev1.ServiceType) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: serviceType,
+		},
+	}
+}
+
+func genPersistentVolume(name string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
@@ -33,6 +33,7 @@ import (
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/ee/validation/resourcequota"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
 
@@ -48,6 +49,12 @@ func ValidateQuota(ctx context.Context,
 	caBundle *certificates.CABundle,
 	resourceQuota *kubermaticv1.ResourceQuota,
 ) error {
+	// every Machine becomes exactly one node
+	if err := resourcequota.ValidateCount(resourceQuota, resourcequota.Nodes, 1); err != nil {
+		log.Debugw("requested node would exceed current quota", zap.Error(err))
+		return err
+	}
+
 	machineResourceUsage, err := GetMachineResourceUsage(ctx, userClient, machine, caBundle)
 	if err != nil {
 		return fmt.Errorf("error getting machine resource request: %w", err)
@@ -99,6 +106,16 @@ func ValidateQuota(ctx context.Context,
 	return nil
 }
 
+// ValidateMachineDeploymentQuota validates if another MachineDeployment fits in the quota of the clusters project.
+func ValidateMachineDeploymentQuota(log *zap.SugaredLogger, resourceQuota *kubermaticv1.ResourceQuota) error {
+	if err := resourcequota.ValidateCount(resourceQuota, resourcequota.MachineDeployments, 1); err != nil {
+		log.Debugw("requested machine deployment would exceed current quota", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 type ResourceDetails struct {
 	cpu     resource.Quantity
 	mem     resource.Quantity
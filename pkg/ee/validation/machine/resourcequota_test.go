@@ -36,6 +36,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/test/generator"
 
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
 )
 
 func TestResourceQuotaValidation(t *testing.T) {
@@ -44,6 +45,7 @@ func TestResourceQuotaValidation(t *testing.T) {
 	testCases := []struct {
 		name        string
 		machine     *clusterv1alpha1.Machine
+		usedNodes   int64
 		expectedErr bool
 	}{
 		{
@@ -66,11 +68,17 @@ func TestResourceQuotaValidation(t *testing.T) {
 			machine:     genFakeMachine("2", "2G", "5000G"),
 			expectedErr: true,
 		},
+		{
+			name:        "should fail with node quota exceeded",
+			machine:     genFakeMachine("2", "2G", "10G"),
+			usedNodes:   5,
+			expectedErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := machine.ValidateQuota(context.Background(), l, nil, tc.machine, nil, genResourceQuota())
+			err := machine.ValidateQuota(context.Background(), l, nil, tc.machine, nil, genResourceQuota(tc.usedNodes))
 			if err != nil {
 				if !tc.expectedErr {
 					t.Fatalf("unexpected error: %v", err)
@@ -90,10 +98,12 @@ func genFakeMachine(cpu, memory, storage string) *clusterv1alpha1.Machine {
 		nil, nil)
 }
 
-func genResourceQuota() *kubermaticv1.ResourceQuota {
+func genResourceQuota(usedNodes int64) *kubermaticv1.ResourceQuota {
 	rq := &kubermaticv1.ResourceQuota{}
 	rq.Spec.Quota = *kubermaticv1.NewResourceDetails(resource.MustParse("50"), resource.MustParse("50G"), resource.MustParse("1000G"))
 	rq.Status.GlobalUsage = *kubermaticv1.NewResourceDetails(resource.MustParse("3"), resource.MustParse("3G"), resource.MustParse("60G"))
+	rq.Spec.Quota.Nodes = pointer.Int64(5)
+	rq.Status.GlobalUsage.Nodes = pointer.Int64(usedNodes)
 
 	return rq
 }
@@ -0,0 +1,144 @@
+//go:build ee
+
+/*
+                  Kubermatic Enterprise Read-Only License
+                         Version 1.0 ("KERO-1.0”)
+                     Copyright © 2023 Kubermatic GmbH
+
+   1.	You may only view, read and display for studying purposes the source
+      code of the software licensed under this license, and, to the extent
+      explicitly provided under this license, the binary code.
+   2.	Any use of the software which exceeds the foregoing right, including,
+      without limitation, its execution, compilation, copying, modification
+      and distribution, is expressly prohibited.
+   3.	THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND,
+      EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+      MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+      IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY
+      CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+      TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+      SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+   END OF TERMS AND CONDITIONS
+*/
+
+package resourcequota
+
+import (
+	"context"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CountableResource describes one of the countable resources of a ResourceQuota.
+type CountableResource struct {
+	// Name is the human readable name used in denial messages.
+	Name string
+	// Get returns the count of the resource from the given details.
+	Get func(kubermaticv1.ResourceDetails) *int64
+}
+
+var (
+	Clusters = CountableResource{
+		Name: "clusters",
+		Get:  func(r kubermaticv1.ResourceDetails) *int64 { return r.Clusters },
+	}
+	MachineDeployments = CountableResource{
+		Name: "machine deployments",
+		Get:  func(r kubermaticv1.ResourceDetails) *int64 { return r.MachineDeployments },
+	}
+	Nodes = CountableResource{
+		Name: "nodes",
+		Get:  func(r kubermaticv1.ResourceDetails) *int64 { return r.Nodes },
+	}
+	LoadBalancers = CountableResource{
+		Name: "load balancers",
+		Get:  func(r kubermaticv1.ResourceDetails) *int64 { return r.LoadBalancers },
+	}
+	PersistentVolumes = CountableResource{
+		Name: "persistent volumes",
+		Get:  func(r kubermaticv1.ResourceDetails) *int64 { return r.PersistentVolumes },
+	}
+)
+
+// ValidateCount validates if adding the requested number of objects of the given resource
+// fits in the quota. Resources without a configured quota are not limited.
+func ValidateCount(resourceQuota *kubermaticv1.ResourceQuota, res CountableResource, requested int64) error {
+	return validateCount(resourceQuota, res, count(res, resourceQuota.Status.GlobalUsage), requested)
+}
+
+// ValidateLiveCount is like ValidateCount, but for quotas whose global usage is not up to date yet.
+// The usage reported for a subset of the objects (e.g. all clusters of one seed or all objects in one
+// user cluster) is replaced by the live count of these objects.
+func ValidateLiveCount(resourceQuota *kubermaticv1.ResourceQuota, res CountableResource, reported kubermaticv1.ResourceDetails, live, requested int64) error {
+	used := count(res, resourceQuota.Status.GlobalUsage) - count(res, reported) + live
+	if used < live {
+		used = live
+	}
+
+	return validateCount(resourceQuota, res, used, requested)
+}
+
+func count(res CountableResource, details kubermaticv1.ResourceDetails) int64 {
+	if c := res.Get(details); c != nil {
+		return *c
+	}
+	return 0
+}
+
+func validateCount(resourceQuota *kubermaticv1.ResourceQuota, res CountableResource, used, requested int64) error {
+	quota := res.Get(resourceQuota.Spec.Quota)
+	if quota == nil {
+		return nil
+	}
+
+	if used+requested > *quota {
+		return fmt.Errorf("requested number of %s (%d) would exceed the quota of project %q (quota/used %d/%d)",
+			res.Name, requested, resourceQuota.Spec.Subject.Name, *quota, used)
+	}
+
+	return nil
+}
+
+// GetProjectQuota returns the ResourceQuota of the given project, or nil if it has none.
+func GetProjectQuota(ctx context.Context, client ctrlruntimeclient.Reader, projectID string) (*kubermaticv1.ResourceQuota, error) {
+	quotaList := &kubermaticv1.ResourceQuotaList{}
+	if err := client.List(ctx, quotaList, ctrlruntimeclient.MatchingLabels{
+		kubermaticv1.ResourceQuotaSubjectNameLabelKey: projectID,
+		kubermaticv1.ResourceQuotaSubjectKindLabelKey: kubermaticv1.ProjectSubjectKind,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+
+	if len(quotaList.Items) == 0 {
+		return nil, nil
+	}
+
+	return &quotaList.Items[0], nil
+}
+
+// ValidateUserClusterCount validates if another object of the given resource fits in the quota of the
+// project of the given user cluster. live is the current number of these objects in the user cluster,
+// it replaces the possibly outdated usage reported in the cluster status.
+func ValidateUserClusterCount(ctx context.Context, seedClient ctrlruntimeclient.Reader, clusterName string, res CountableResource, live int64) error {
+	cluster := &kubermaticv1.Cluster{}
+	if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	resourceQuota, err := GetProjectQuota(ctx, seedClient, cluster.Labels[kubermaticv1.ProjectIDLabelKey])
+	if err != nil || resourceQuota == nil {
+		return err
+	}
+
+	var reported kubermaticv1.ResourceDetails
+	if cluster.Status.ResourceUsage != nil {
+		reported = *cluster.Status.ResourceUsage
+	}
+
+	return ValidateLiveCount(resourceQuota, res, reported, live, 1)
+}
@@ -0,0 +1,98 @@
+//go:build ee
+
+/*
+                  Kubermatic Enterprise Read-Only License
+                         Version 1.0 ("KERO-1.0”)
+                     Copyright © 2022 Kubermatic GmbH
+
+   1.	You may only view, read and display for studying purposes the source
+      code of the software licensed under this license, and, to the extent
+      explicitly provided under this license, the binary code.
+   2.	Any use of the software which exceeds the foregoing right, including,
+      without limitation, its execution, compilation, copying, modification
+      and distribution, is expressly prohibited.
+   3.	THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND,
+      EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+      MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+      IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY
+      CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+      TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+      SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+   END OF TERMS AND CONDITIONS
+*/
+
+package resourcequota_test
+
+import (
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/ee/validation/resourcequota"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestValidateLiveCount(t *testing.T) {
+	testCases := []struct {
+		name        string
+		quota       *int64
+		global      *int64
+		reported    *int64
+		live        int64
+		errExpected bool
+	}{
+		{
+			name:   "no quota for the resource",
+			global: pointer.Int64(10),
+			live:   10,
+		},
+		{
+			name:     "global usage is up to date",
+			quota:    pointer.Int64(3),
+			global:   pointer.Int64(2),
+			reported: pointer.Int64(1),
+			live:     1,
+		},
+		{
+			name:        "objects missing in the global usage are counted",
+			quota:       pointer.Int64(3),
+			global:      pointer.Int64(2),
+			reported:    pointer.Int64(1),
+			live:        2,
+			errExpected: true,
+		},
+		{
+			name:     "deleted objects are no longer counted",
+			quota:    pointer.Int64(3),
+			global:   pointer.Int64(3),
+			reported: pointer.Int64(2),
+			live:     1,
+		},
+		{
+			name:        "live objects are counted without any usage",
+			quota:       pointer.Int64(3),
+			live:        3,
+			errExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			quota := &kubermaticv1.ResourceQuota{
+				Spec: kubermaticv1.ResourceQuotaSpec{
+					Quota: kubermaticv1.ResourceDetails{LoadBalancers: tc.quota},
+				},
+				Status: kubermaticv1.ResourceQuotaStatus{
+					GlobalUsage: kubermaticv1.ResourceDetails{LoadBalancers: tc.global},
+				},
+			}
+			reported := kubermaticv1.ResourceDetails{LoadBalancers: tc.reported}
+
+			err := resourcequota.ValidateLiveCount(quota, resourcequota.LoadBalancers, reported, tc.live, 1)
+			if (err != nil) != tc.errExpected {
+				t.Fatalf("Expected err: %t, but got err: %v", tc.errExpected, err)
+			}
+		})
+	}
+}
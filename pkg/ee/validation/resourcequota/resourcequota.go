@@ -28,6 +28,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	resourcequotadefaultcontroller "k8c.io/kubermatic/v2/pkg/ee/resource-quota/default-quota-controller"
@@ -54,6 +55,10 @@ func ValidateCreate(ctx context.Context,
 		return fmt.Errorf("failed to list resource quotas: %w", err)
 	}
 
+	if err := validateNotification(incomingQuota.Spec.Notification); err != nil {
+		return err
+	}
+
 	incomingSubject := incomingQuota.Spec.Subject
 	for _, currentQuota := range currentQuotaList.Items {
 		currentSubject := currentQuota.Spec.Subject
@@ -89,6 +94,22 @@ func ValidateUpdate(ctx context.Context,
 		return fmt.Errorf("Operation not permitted: updating ResourceQuota Subject is not allowed!")
 	}
 
+	return validateNotification(newQuota.Spec.Notification)
+}
+
+func validateNotification(notification *kubermaticv1.ResourceQuotaNotification) error {
+	if notification == nil {
+		return nil
+	}
+
+	u, err := url.Parse(notification.WebhookURL)
+	if err != nil {
+		return fmt.Errorf("ResourceQuota: invalid notification webhook URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("ResourceQuota: notification webhook URL %q must be an absolute http(s) URL", notification.WebhookURL)
+	}
+
 	return nil
 }
 
@@ -185,6 +185,70 @@ func TestValidateUpdate(t *testing.T) {
 			},
 			errExpected: true,
 		},
+		{
+			name: "Update ResourceQuota invalid notification webhook URL",
+			oldResourceQuota: &kubermaticv1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "existing-quota",
+				},
+				Spec: kubermaticv1.ResourceQuotaSpec{
+					Subject: kubermaticv1.Subject{
+						Name: "wwqrvcccq6",
+						Kind: "project",
+					},
+					Quota: kubermaticv1.ResourceDetails{},
+				},
+			},
+			newResourceQuota: &kubermaticv1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "existing-quota",
+				},
+				Spec: kubermaticv1.ResourceQuotaSpec{
+					Subject: kubermaticv1.Subject{
+						Name: "wwqrvcccq6",
+						Kind: "project",
+					},
+					Quota:             kubermaticv1.ResourceDetails{},
+					WarningThresholds: []kubermaticv1.ResourceQuotaThreshold{80, 95},
+					Notification: &kubermaticv1.ResourceQuotaNotification{
+						WebhookURL: "hooks.example.com/quota",
+					},
+				},
+			},
+			errExpected: true,
+		},
+		{
+			name: "Update ResourceQuota valid notification webhook URL",
+			oldResourceQuota: &kubermaticv1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "existing-quota",
+				},
+				Spec: kubermaticv1.ResourceQuotaSpec{
+					Subject: kubermaticv1.Subject{
+						Name: "wwqrvcccq6",
+						Kind: "project",
+					},
+					Quota: kubermaticv1.ResourceDetails{},
+				},
+			},
+			newResourceQuota: &kubermaticv1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "existing-quota",
+				},
+				Spec: kubermaticv1.ResourceQuotaSpec{
+					Subject: kubermaticv1.Subject{
+						Name: "wwqrvcccq6",
+						Kind: "project",
+					},
+					Quota:             kubermaticv1.ResourceDetails{},
+					WarningThresholds: []kubermaticv1.ResourceQuotaThreshold{80, 95},
+					Notification: &kubermaticv1.ResourceQuotaNotification{
+						WebhookURL: "https://hooks.example.com/quota",
+					},
+				},
+			},
+			errExpected: false,
+		},
 	}
 
 	for _, tc := range testCases {
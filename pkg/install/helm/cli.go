@@ -226,6 +226,10 @@ func (c *cli) GetValues(namespace string, releaseName string) (*yamled.Document,
 	return yamled.Load(bytes.NewReader(output))
 }
 
+func (c *cli) GetManifest(namespace string, releaseName string) ([]byte, error) {
+	return c.run(namespace, "get", "manifest", releaseName)
+}
+
 func (c *cli) Version() (*semverlib.Version, error) {
 	// add --client to gracefully handle Helm 2 (Helm 3 ignores the flag, thankfully);
 	// Helm 2 will output "<no value>", whereas Helm 3 would outright reject the
@@ -34,4 +34,5 @@ type Client interface {
 	UninstallRelease(namespace string, name string) error
 	RenderChart(namespace string, releaseName string, chartDirectory string, valuesFile string, values map[string]string) ([]byte, error)
 	GetValues(namespace string, releaseName string) (*yamled.Document, error)
+	GetManifest(namespace string, releaseName string) ([]byte, error)
 }
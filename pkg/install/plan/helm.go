@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+
+	"k8c.io/kubermatic/v2/pkg/install/helm"
+)
+
+type helmClient struct {
+	helm.Client
+
+	plan *Plan
+}
+
+// NewHelmClient wraps a Helm client so that installing and uninstalling
+// releases is not performed, but recorded in the plan instead. Installing
+// a chart renders it and diffs the result against the live release.
+func NewHelmClient(client helm.Client, plan *Plan) helm.Client {
+	return &helmClient{
+		Client: client,
+		plan:   plan,
+	}
+}
+
+func (c *helmClient) InstallChart(namespace string, releaseName string, chartDirectory string, valuesFile string, values map[string]string, flags []string) error {
+	chart, err := helm.LoadChart(chartDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to load Helm chart: %w", err)
+	}
+
+	release, err := c.Client.GetRelease(namespace, releaseName)
+	if err != nil {
+		return fmt.Errorf("failed to check for an existing release: %w", err)
+	}
+
+	// a defunct release that would have been uninstalled beforehand
+	if c.plan.releaseUninstalled(namespace, releaseName) {
+		release = nil
+	}
+
+	rendered, err := c.Client.RenderChart(namespace, releaseName, chartDirectory, valuesFile, values)
+	if err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	var live []byte
+	if release != nil {
+		live, err = c.Client.GetManifest(namespace, releaseName)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve manifest of release: %w", err)
+		}
+	}
+
+	resources, err := DiffManifests(live, rendered)
+	if err != nil {
+		return err
+	}
+
+	change := Release{
+		Namespace:     namespace,
+		Name:          releaseName,
+		Chart:         chart.Name,
+		TargetVersion: chart.Version.String(),
+		Action:        ActionCreate,
+		Resources:     resources,
+	}
+
+	if release != nil {
+		change.CurrentVersion = release.Version.String()
+		change.Action = ActionNone
+
+		if len(resources) > 0 || !release.Version.Equal(chart.Version) {
+			change.Action = ActionUpdate
+		}
+	}
+
+	c.plan.Releases = append(c.plan.Releases, change)
+
+	return nil
+}
+
+func (c *helmClient) UninstallRelease(namespace string, name string) error {
+	c.plan.Releases = append(c.plan.Releases, Release{
+		Namespace: namespace,
+		Name:      name,
+		Action:    ActionDelete,
+	})
+
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	semverlib "github.com/Masterminds/semver/v3"
+
+	"k8c.io/kubermatic/v2/pkg/install/helm"
+)
+
+// readOnlyHelmClient serves a single release and fails the test whenever
+// a release would be changed.
+type readOnlyHelmClient struct {
+	helm.Client
+
+	t        *testing.T
+	release  *helm.Release
+	manifest string
+}
+
+func (c *readOnlyHelmClient) InstallChart(_ string, releaseName string, _ string, _ string, _ map[string]string, _ []string) error {
+	c.t.Errorf("InstallChart(%s) reached Helm", releaseName)
+	return nil
+}
+
+func (c *readOnlyHelmClient) UninstallRelease(_ string, name string) error {
+	c.t.Errorf("UninstallRelease(%s) reached Helm", name)
+	return nil
+}
+
+func (c *readOnlyHelmClient) GetRelease(namespace string, name string) (*helm.Release, error) {
+	if c.release != nil && c.release.Namespace == namespace && c.release.Name == name {
+		return c.release, nil
+	}
+
+	return nil, nil
+}
+
+func (c *readOnlyHelmClient) GetManifest(_ string, _ string) ([]byte, error) {
+	return []byte(c.manifest), nil
+}
+
+func (c *readOnlyHelmClient) RenderChart(_ string, _ string, _ string, _ string, _ map[string]string) ([]byte, error) {
+	return []byte(renderedManifest), nil
+}
+
+func writeChart(t *testing.T, version string) string {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: test\nversion: "+version+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write Chart.yaml: %v", err)
+	}
+
+	return dir
+}
+
+func TestHelmClientRecordsReleases(t *testing.T) {
+	testcases := []struct {
+		name            string
+		release         *helm.Release
+		uninstall       bool
+		expectedAction  Action
+		expectedCurrent string
+		expectedChanges int
+	}{
+		{
+			name:            "new release",
+			expectedAction:  ActionCreate,
+			expectedChanges: 3,
+		},
+		{
+			name:            "unchanged release",
+			release:         &helm.Release{Namespace: "test", Name: "test", Version: semverlib.MustParse("1.0.0")},
+			expectedAction:  ActionNone,
+			expectedCurrent: "1.0.0",
+		},
+		{
+			name:            "upgraded release",
+			release:         &helm.Release{Namespace: "test", Name: "test", Version: semverlib.MustParse("0.9.0")},
+			expectedAction:  ActionUpdate,
+			expectedCurrent: "0.9.0",
+		},
+		{
+			name:            "reinstalled defunct release",
+			release:         &helm.Release{Namespace: "test", Name: "test", Version: semverlib.MustParse("1.0.0")},
+			uninstall:       true,
+			expectedAction:  ActionCreate,
+			expectedChanges: 3,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New("test")
+			client := NewHelmClient(&readOnlyHelmClient{
+				t:        t,
+				release:  tc.release,
+				manifest: renderedManifest,
+			}, p)
+
+			if tc.uninstall {
+				if err := client.UninstallRelease("test", "test"); err != nil {
+					t.Fatalf("Failed to uninstall release: %v", err)
+				}
+			}
+
+			if err := client.InstallChart("test", "test", writeChart(t, "1.0.0"), "", nil, nil); err != nil {
+				t.Fatalf("Failed to install chart: %v", err)
+			}
+
+			change := p.Releases[len(p.Releases)-1]
+			if change.Action != tc.expectedAction {
+				t.Errorf("Expected action %q, but got %q", tc.expectedAction, change.Action)
+			}
+
+			if change.CurrentVersion != tc.expectedCurrent {
+				t.Errorf("Expected current version %q, but got %q", tc.expectedCurrent, change.CurrentVersion)
+			}
+
+			if change.TargetVersion != "1.0.0" {
+				t.Errorf("Expected target version 1.0.0, but got %q", change.TargetVersion)
+			}
+
+			if len(change.Resources) != tc.expectedChanges {
+				t.Errorf("Expected %d changed resources, but got %d: %+v", tc.expectedChanges, len(change.Resources), change.Resources)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+type kubeClient struct {
+	ctrlruntimeclient.Client
+
+	plan *Plan
+}
+
+// NewKubeClient wraps a Kubernetes client so that all write operations are
+// not sent to the cluster, but recorded in the plan instead. Reads are passed
+// through unchanged.
+func NewKubeClient(client ctrlruntimeclient.Client, plan *Plan) ctrlruntimeclient.Client {
+	return &kubeClient{
+		Client: client,
+		plan:   plan,
+	}
+}
+
+func (c *kubeClient) groupVersionKind(obj ctrlruntimeclient.Object) schema.GroupVersionKind {
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		return gvk
+	}
+
+	return obj.GetObjectKind().GroupVersionKind()
+}
+
+func (c *kubeClient) record(obj ctrlruntimeclient.Object, action Action) {
+	c.plan.Objects = append(c.plan.Objects, Object{
+		Kind:      c.groupVersionKind(obj).Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Action:    action,
+	})
+}
+
+// Create mimics the API server by returning an AlreadyExists error for
+// existing objects, as callers often rely on this to ensure objects exist.
+func (c *kubeClient) Create(ctx context.Context, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.CreateOption) error {
+	existing, ok := obj.DeepCopyObject().(ctrlruntimeclient.Object)
+	if !ok {
+		return fmt.Errorf("cannot copy %T", obj)
+	}
+
+	err := c.Client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), existing)
+	switch {
+	case err == nil:
+		gvk := c.groupVersionKind(obj)
+		return apierrors.NewAlreadyExists(schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind)}, obj.GetName())
+
+	// the CRD for the object might not be installed yet
+	case apierrors.IsNotFound(err), meta.IsNoMatchError(err):
+		c.record(obj, ActionCreate)
+		return nil
+
+	default:
+		return err
+	}
+}
+
+func (c *kubeClient) Update(ctx context.Context, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.UpdateOption) error {
+	c.record(obj, ActionUpdate)
+	return nil
+}
+
+func (c *kubeClient) Patch(ctx context.Context, obj ctrlruntimeclient.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+	c.record(obj, ActionUpdate)
+	return nil
+}
+
+func (c *kubeClient) Delete(ctx context.Context, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.DeleteOption) error {
+	c.record(obj, ActionDelete)
+	return nil
+}
+
+func (c *kubeClient) DeleteAllOf(ctx context.Context, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.DeleteAllOfOption) error {
+	c.record(obj, ActionDelete)
+	return nil
+}
+
+func (c *kubeClient) Status() ctrlruntimeclient.SubResourceWriter {
+	return &subResourceWriter{client: c}
+}
+
+func (c *kubeClient) SubResource(subResource string) ctrlruntimeclient.SubResourceClient {
+	return &subResourceClient{
+		SubResourceReader: c.Client.SubResource(subResource),
+		subResourceWriter: subResourceWriter{client: c},
+	}
+}
+
+type subResourceWriter struct {
+	client *kubeClient
+}
+
+func (w *subResourceWriter) Create(ctx context.Context, obj ctrlruntimeclient.Object, subResource ctrlruntimeclient.Object, opts ...ctrlruntimeclient.SubResourceCreateOption) error {
+	w.client.record(obj, ActionUpdate)
+	return nil
+}
+
+func (w *subResourceWriter) Update(ctx context.Context, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.SubResourceUpdateOption) error {
+	w.client.record(obj, ActionUpdate)
+	return nil
+}
+
+func (w *subResourceWriter) Patch(ctx context.Context, obj ctrlruntimeclient.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.SubResourcePatchOption) error {
+	w.client.record(obj, ActionUpdate)
+	return nil
+}
+
+type subResourceClient struct {
+	ctrlruntimeclient.SubResourceReader
+	subResourceWriter
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// readOnlyClient fails the test whenever a mutating call reaches it.
+type readOnlyClient struct {
+	ctrlruntimeclient.Client
+
+	t *testing.T
+}
+
+func (c *readOnlyClient) Create(_ context.Context, obj ctrlruntimeclient.Object, _ ...ctrlruntimeclient.CreateOption) error {
+	c.t.Errorf("Create(%s) reached the cluster", obj.GetName())
+	return nil
+}
+
+func (c *readOnlyClient) Update(_ context.Context, obj ctrlruntimeclient.Object, _ ...ctrlruntimeclient.UpdateOption) error {
+	c.t.Errorf("Update(%s) reached the cluster", obj.GetName())
+	return nil
+}
+
+func (c *readOnlyClient) Patch(_ context.Context, obj ctrlruntimeclient.Object, _ ctrlruntimeclient.Patch, _ ...ctrlruntimeclient.PatchOption) error {
+	c.t.Errorf("Patch(%s) reached the cluster", obj.GetName())
+	return nil
+}
+
+func (c *readOnlyClient) Delete(_ context.Context, obj ctrlruntimeclient.Object, _ ...ctrlruntimeclient.DeleteOption) error {
+	c.t.Errorf("Delete(%s) reached the cluster", obj.GetName())
+	return nil
+}
+
+func (c *readOnlyClient) DeleteAllOf(_ context.Context, obj ctrlruntimeclient.Object, _ ...ctrlruntimeclient.DeleteAllOfOption) error {
+	c.t.Errorf("DeleteAllOf(%T) reached the cluster", obj)
+	return nil
+}
+
+func (c *readOnlyClient) Status() ctrlruntimeclient.SubResourceWriter {
+	c.t.Error("Status() writer was requested from the cluster client")
+	return c.Client.Status()
+}
+
+func (c *readOnlyClient) SubResource(subResource string) ctrlruntimeclient.SubResourceClient {
+	return &readOnlySubResourceClient{SubResourceClient: c.Client.SubResource(subResource), t: c.t}
+}
+
+type readOnlySubResourceClient struct {
+	ctrlruntimeclient.SubResourceClient
+
+	t *testing.T
+}
+
+func (c *readOnlySubResourceClient) Create(_ context.Context, obj ctrlruntimeclient.Object, _ ctrlruntimeclient.Object, _ ...ctrlruntimeclient.SubResourceCreateOption) error {
+	c.t.Errorf("subresource Create(%s) reached the cluster", obj.GetName())
+	return nil
+}
+
+func (c *readOnlySubResourceClient) Update(_ context.Context, obj ctrlruntimeclient.Object, _ ...ctrlruntimeclient.SubResourceUpdateOption) error {
+	c.t.Errorf("subresource Update(%s) reached the cluster", obj.GetName())
+	return nil
+}
+
+func (c *readOnlySubResourceClient) Patch(_ context.Context, obj ctrlruntimeclient.Object, _ ctrlruntimeclient.Patch, _ ...ctrlruntimeclient.SubResourcePatchOption) error {
+	c.t.Errorf("subresource Patch(%s) reached the cluster", obj.GetName())
+	return nil
+}
+
+func configMap(name string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kubermatic",
+		},
+	}
+}
+
+func TestKubeClientRecordsWrites(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(configMap("existing")).
+		Build()
+
+	p := New("test")
+	client := NewKubeClient(&readOnlyClient{Client: fakeClient, t: t}, p)
+
+	if err := client.Create(ctx, configMap("existing")); !apierrors.IsAlreadyExists(err) {
+		t.Errorf("Expected AlreadyExists error when creating an existing object, but got %v", err)
+	}
+
+	if err := client.Create(ctx, configMap("new")); err != nil {
+		t.Errorf("Failed to create object: %v", err)
+	}
+
+	existing := configMap("existing")
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(existing), existing); err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+
+	existing.Data = map[string]string{"changed": "true"}
+
+	if err := client.Update(ctx, existing); err != nil {
+		t.Errorf("Failed to update object: %v", err)
+	}
+
+	if err := client.Patch(ctx, existing, ctrlruntimeclient.MergeFrom(configMap("existing"))); err != nil {
+		t.Errorf("Failed to patch object: %v", err)
+	}
+
+	if err := client.Status().Update(ctx, existing); err != nil {
+		t.Errorf("Failed to update status: %v", err)
+	}
+
+	if err := client.SubResource("status").Patch(ctx, existing, ctrlruntimeclient.MergeFrom(configMap("existing"))); err != nil {
+		t.Errorf("Failed to patch subresource: %v", err)
+	}
+
+	if err := client.Delete(ctx, existing); err != nil {
+		t.Errorf("Failed to delete object: %v", err)
+	}
+
+	if err := client.DeleteAllOf(ctx, &corev1.ConfigMap{}, ctrlruntimeclient.InNamespace("kubermatic")); err != nil {
+		t.Errorf("Failed to delete objects: %v", err)
+	}
+
+	expected := []Object{
+		{Kind: "ConfigMap", Namespace: "kubermatic", Name: "new", Action: ActionCreate},
+		{Kind: "ConfigMap", Namespace: "kubermatic", Name: "existing", Action: ActionUpdate},
+		{Kind: "ConfigMap", Namespace: "kubermatic", Name: "existing", Action: ActionUpdate},
+		{Kind: "ConfigMap", Namespace: "kubermatic", Name: "existing", Action: ActionUpdate},
+		{Kind: "ConfigMap", Namespace: "kubermatic", Name: "existing", Action: ActionUpdate},
+		{Kind: "ConfigMap", Namespace: "kubermatic", Name: "existing", Action: ActionDelete},
+		{Kind: "ConfigMap", Action: ActionDelete},
+	}
+
+	if len(p.Objects) != len(expected) {
+		t.Fatalf("Expected %d recorded objects, but got %d: %+v", len(expected), len(p.Objects), p.Objects)
+	}
+
+	for i, obj := range p.Objects {
+		if obj != expected[i] {
+			t.Errorf("Expected object %d to be %+v, but got %+v", i, expected[i], obj)
+		}
+	}
+
+	// the cluster itself must be unchanged
+	live := configMap("existing")
+	if err := fakeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(live), live); err != nil {
+		t.Fatalf("Existing object is gone: %v", err)
+	}
+
+	if len(live.Data) > 0 {
+		t.Errorf("Existing object was modified: %v", live.Data)
+	}
+
+	if err := fakeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(configMap("new")), &corev1.ConfigMap{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected new object to not exist, but got %v", err)
+	}
+}
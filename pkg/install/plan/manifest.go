@@ -0,0 +1,214 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// helmHookAnnotation marks resources that are not part of a release's
+	// manifest, but are only run as hooks during installs/upgrades.
+	helmHookAnnotation = "helm.sh/hook"
+
+	redactedValue        = "<redacted>"
+	redactedChangedValue = "<redacted, changed>"
+)
+
+// DiffManifests compares two multi-document YAML manifests, like they are
+// rendered by Helm, and returns all resources that would be created, updated
+// or deleted when going from the live to the rendered manifest. Helm hooks are
+// ignored, as they are not part of a release's stored manifest. The data of
+// Secrets is redacted, so the diff only shows which keys have changed.
+func DiffManifests(live []byte, rendered []byte) ([]Resource, error) {
+	liveObjects, err := parseManifest(live)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse live manifest: %w", err)
+	}
+
+	renderedObjects, err := parseManifest(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rendered manifest: %w", err)
+	}
+
+	resources := []Resource{}
+
+	for key, renderedObject := range renderedObjects {
+		resource := Resource{
+			Kind:      key.Kind,
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		}
+
+		liveObject, exists := liveObjects[key]
+		if !exists {
+			resource.Action = ActionCreate
+			resources = append(resources, resource)
+			continue
+		}
+
+		if key.Kind == "Secret" {
+			redactSecretData(liveObject, renderedObject)
+		}
+
+		diff, err := Diff(liveObject.Object, renderedObject.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff %s %s: %w", key.Kind, key.Name, err)
+		}
+
+		if diff != "" {
+			resource.Action = ActionUpdate
+			resource.Diff = diff
+			resources = append(resources, resource)
+		}
+	}
+
+	for key := range liveObjects {
+		if _, exists := renderedObjects[key]; !exists {
+			resources = append(resources, Resource{
+				Kind:      key.Kind,
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Action:    ActionDelete,
+			})
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return resources, nil
+}
+
+// Diff returns a unified diff of the YAML representations of both objects,
+// or an empty string if they are identical.
+func Diff(current interface{}, desired interface{}) (string, error) {
+	currentYAML, err := yaml.Marshal(current)
+	if err != nil {
+		return "", err
+	}
+
+	desiredYAML, err := yaml.Marshal(desired)
+	if err != nil {
+		return "", err
+	}
+
+	if bytes.Equal(currentYAML, desiredYAML) {
+		return "", nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(currentYAML)),
+		B:        difflib.SplitLines(string(desiredYAML)),
+		FromFile: "current",
+		ToFile:   "desired",
+		Context:  3,
+	})
+}
+
+// redactSecretData replaces the values of both Secrets' data and stringData
+// with placeholders. Values that differ between both Secrets get a distinct
+// placeholder in the rendered Secret, so that changed keys remain visible.
+func redactSecretData(live *unstructured.Unstructured, rendered *unstructured.Unstructured) {
+	for _, field := range []string{"data", "stringData"} {
+		liveData, _ := live.Object[field].(map[string]interface{})
+		renderedData, _ := rendered.Object[field].(map[string]interface{})
+
+		for key, renderedValue := range renderedData {
+			liveValue, exists := liveData[key]
+			if exists && !reflect.DeepEqual(liveValue, renderedValue) {
+				renderedData[key] = redactedChangedValue
+			} else {
+				renderedData[key] = redactedValue
+			}
+		}
+
+		for key := range liveData {
+			liveData[key] = redactedValue
+		}
+	}
+}
+
+type objectKey struct {
+	// Kind includes the API group, but not the version, so that
+	// moving a resource to a new API version is shown as an update.
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func parseManifest(manifest []byte) (map[objectKey]*unstructured.Unstructured, error) {
+	objects := map[objectKey]*unstructured.Unstructured{}
+	reader := yamlutil.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		data := map[string]interface{}{}
+		if err := yaml.Unmarshal(doc, &data); err != nil {
+			return nil, err
+		}
+
+		// documents consisting only of comments
+		if len(data) == 0 {
+			continue
+		}
+
+		object := &unstructured.Unstructured{Object: data}
+		if _, isHook := object.GetAnnotations()[helmHookAnnotation]; isHook {
+			continue
+		}
+
+		gk := schema.FromAPIVersionAndKind(object.GetAPIVersion(), object.GetKind()).GroupKind()
+		key := objectKey{
+			Kind:      gk.String(),
+			Namespace: object.GetNamespace(),
+			Name:      object.GetName(),
+		}
+
+		objects[key] = object
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"strings"
+	"testing"
+)
+
+const liveManifest = `---
+# Source: test/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: test
+spec:
+  ports:
+  - port: 80
+---
+# Source: test/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  replicas: 1
+---
+# Source: test/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: obsolete
+`
+
+const renderedManifest = `---
+# Source: test/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: test
+spec:
+  ports:
+  - port: 80
+---
+# Source: test/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  replicas: 2
+---
+# Source: test/templates/pdb.yaml
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: test
+---
+# Source: test/templates/tests/test-connection.yaml
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-connection
+  annotations:
+    helm.sh/hook: test
+`
+
+func TestDiffManifests(t *testing.T) {
+	resources, err := DiffManifests([]byte(liveManifest), []byte(renderedManifest))
+	if err != nil {
+		t.Fatalf("Failed to diff manifests: %v", err)
+	}
+
+	expected := []Resource{
+		{Kind: "ConfigMap", Name: "obsolete", Action: ActionDelete},
+		{Kind: "Deployment.apps", Name: "test", Action: ActionUpdate},
+		{Kind: "PodDisruptionBudget.policy", Name: "test", Action: ActionCreate},
+	}
+
+	if len(resources) != len(expected) {
+		t.Fatalf("Expected %d changed resources, but got %d: %+v", len(expected), len(resources), resources)
+	}
+
+	for i, resource := range resources {
+		exp := expected[i]
+		if resource.Kind != exp.Kind || resource.Name != exp.Name || resource.Action != exp.Action {
+			t.Errorf("Expected resource %d to be %+v, but got %+v", i, exp, resource)
+		}
+	}
+
+	diff := resources[1].Diff
+	if !strings.Contains(diff, "-  replicas: 1") || !strings.Contains(diff, "+  replicas: 2") {
+		t.Errorf("Expected diff to contain the changed replicas, but got:\n%s", diff)
+	}
+}
+
+func TestDiffManifestsForNewRelease(t *testing.T) {
+	resources, err := DiffManifests(nil, []byte(renderedManifest))
+	if err != nil {
+		t.Fatalf("Failed to diff manifests: %v", err)
+	}
+
+	// the hook is not part of the release
+	if len(resources) != 3 {
+		t.Fatalf("Expected 3 resources, but got %d: %+v", len(resources), resources)
+	}
+
+	for _, resource := range resources {
+		if resource.Action != ActionCreate {
+			t.Errorf("Expected %s %s to be created, but action is %q", resource.Kind, resource.Name, resource.Action)
+		}
+	}
+}
+
+func TestDiffManifestsRedactsSecrets(t *testing.T) {
+	live := `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+data:
+  username: YWRtaW4=
+  password: b2xkLXNlY3JldA==
+stringData:
+  token: old-token
+`
+
+	rendered := `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+data:
+  username: YWRtaW4=
+  password: bmV3LXNlY3JldA==
+stringData:
+  token: new-token
+`
+
+	resources, err := DiffManifests([]byte(live), []byte(rendered))
+	if err != nil {
+		t.Fatalf("Failed to diff manifests: %v", err)
+	}
+
+	if len(resources) != 1 || resources[0].Action != ActionUpdate {
+		t.Fatalf("Expected the Secret to be updated, but got %+v", resources)
+	}
+
+	diff := resources[0].Diff
+	for _, secret := range []string{"YWRtaW4=", "b2xkLXNlY3JldA==", "bmV3LXNlY3JldA==", "old-token", "new-token"} {
+		if strings.Contains(diff, secret) {
+			t.Errorf("Expected diff not to contain %q, but got:\n%s", secret, diff)
+		}
+	}
+
+	for _, line := range []string{"-  password: <redacted>", "+  password: <redacted, changed>", "-  token: <redacted>", "+  token: <redacted, changed>", "   username: <redacted>"} {
+		if !strings.Contains(diff, line) {
+			t.Errorf("Expected diff to contain %q, but got:\n%s", line, diff)
+		}
+	}
+
+	// unchanged Secrets are not part of the plan
+	resources, err = DiffManifests([]byte(live), []byte(live))
+	if err != nil {
+		t.Fatalf("Failed to diff manifests: %v", err)
+	}
+
+	if len(resources) != 0 {
+		t.Errorf("Expected no changes, but got %+v", resources)
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan implements the dry-run mode of the installer. Instead of
+// changing the cluster, all changes a deployment would make are collected
+// in a Plan, which can then be reviewed before the actual deployment.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionNone   Action = "none"
+)
+
+func (a Action) symbol() string {
+	switch a {
+	case ActionCreate:
+		return "+"
+	case ActionUpdate:
+		return "~"
+	case ActionDelete:
+		return "-"
+	default:
+		return "="
+	}
+}
+
+// Plan describes all changes that deploying a stack would perform.
+type Plan struct {
+	Stack      string      `json:"stack"`
+	Releases   []Release   `json:"releases"`
+	CRDs       []CRD       `json:"crds"`
+	Migrations []Migration `json:"migrations"`
+	// Objects are all objects that the installer itself (i.e. not Helm)
+	// would create, update or delete.
+	Objects []Object `json:"objects"`
+}
+
+// Release is a Helm release that would be installed, upgraded or uninstalled.
+type Release struct {
+	Namespace      string     `json:"namespace"`
+	Name           string     `json:"name"`
+	Chart          string     `json:"chart,omitempty"`
+	CurrentVersion string     `json:"currentVersion,omitempty"`
+	TargetVersion  string     `json:"targetVersion,omitempty"`
+	Action         Action     `json:"action"`
+	Resources      []Resource `json:"resources,omitempty"`
+}
+
+// Resource is a single object rendered by a Helm chart that differs from
+// the currently deployed release.
+type Resource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Action    Action `json:"action"`
+	Diff      string `json:"diff,omitempty"`
+}
+
+// CRD is a CustomResourceDefinition that would be created or updated.
+type CRD struct {
+	Name   string `json:"name"`
+	Action Action `json:"action"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+// Migration is a migration procedure that would run during the deployment.
+type Migration struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Flag is the CLI flag that must be given to acknowledge the migration.
+	// Migrations that run unconditionally have no flag.
+	Flag         string `json:"flag,omitempty"`
+	Acknowledged bool   `json:"acknowledged"`
+}
+
+type Object struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Action    Action `json:"action"`
+}
+
+func New(stack string) *Plan {
+	return &Plan{
+		Stack:      stack,
+		Releases:   []Release{},
+		CRDs:       []CRD{},
+		Migrations: []Migration{},
+		Objects:    []Object{},
+	}
+}
+
+func (p *Plan) AddMigration(name string, description string, flag string, acknowledged bool) {
+	p.Migrations = append(p.Migrations, Migration{
+		Name:         name,
+		Description:  description,
+		Flag:         flag,
+		Acknowledged: acknowledged || flag == "",
+	})
+}
+
+// HasChanges returns true if deploying the stack would change anything.
+func (p *Plan) HasChanges() bool {
+	for _, r := range p.Releases {
+		if r.Action != ActionNone {
+			return true
+		}
+	}
+
+	for _, c := range p.CRDs {
+		if c.Action != ActionNone {
+			return true
+		}
+	}
+
+	return len(p.Migrations) > 0 || len(p.Objects) > 0
+}
+
+func (p *Plan) releaseUninstalled(namespace string, name string) bool {
+	for _, r := range p.Releases {
+		if r.Namespace == namespace && r.Name == name && r.Action == ActionDelete {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Plan) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(p)
+}
+
+func (p *Plan) WriteText(w io.Writer) error {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "Deployment plan for %s:\n", p.Stack)
+
+	if !p.HasChanges() {
+		fmt.Fprintln(b, "\nNo changes. The installation is up-to-date.")
+	}
+
+	if len(p.Migrations) > 0 {
+		fmt.Fprintln(b, "\nMigrations:")
+
+		for _, m := range p.Migrations {
+			fmt.Fprintf(b, "  ! %s: %s\n", m.Name, m.Description)
+			if !m.Acknowledged {
+				fmt.Fprintf(b, "      requires %s\n", m.Flag)
+			}
+		}
+	}
+
+	if len(p.CRDs) > 0 {
+		fmt.Fprintln(b, "\nCustom Resource Definitions:")
+
+		for _, c := range p.CRDs {
+			if c.Action == ActionNone {
+				continue
+			}
+
+			fmt.Fprintf(b, "  %s %s\n", c.Action.symbol(), c.Name)
+			writeDiff(b, c.Diff, "      ")
+		}
+	}
+
+	if len(p.Releases) > 0 {
+		fmt.Fprintln(b, "\nHelm releases:")
+
+		for _, r := range p.Releases {
+			fmt.Fprintf(b, "  %s %s/%s", r.Action.symbol(), r.Namespace, r.Name)
+
+			switch {
+			case r.Action == ActionDelete:
+				fmt.Fprint(b, " (defunct release is uninstalled)")
+			case r.CurrentVersion != "" && r.CurrentVersion != r.TargetVersion:
+				fmt.Fprintf(b, " (%s %s → %s)", r.Chart, r.CurrentVersion, r.TargetVersion)
+			default:
+				fmt.Fprintf(b, " (%s %s)", r.Chart, r.TargetVersion)
+			}
+			fmt.Fprintln(b)
+
+			for _, res := range r.Resources {
+				fmt.Fprintf(b, "      %s %s %s\n", res.Action.symbol(), res.Kind, objectName(res.Namespace, res.Name))
+				writeDiff(b, res.Diff, "          ")
+			}
+		}
+	}
+
+	if len(p.Objects) > 0 {
+		fmt.Fprintln(b, "\nOther objects:")
+
+		for _, o := range p.Objects {
+			fmt.Fprintf(b, "  %s %s %s\n", o.Action.symbol(), o.Kind, objectName(o.Namespace, o.Name))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func writeDiff(b *strings.Builder, diff string, indent string) {
+	if diff == "" {
+		return
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, line)
+	}
+}
+
+func objectName(namespace string, name string) string {
+	if name == "" {
+		name = "*"
+	}
+
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + "/" + name
+}
@@ -90,7 +90,29 @@ func deployCertManager(ctx context.Context, logger *logrus.Entry, kubeClient ctr
 	v2 := semverlib.MustParse("2.0.0")  // New CRDs - migration required
 	v21 := semverlib.MustParse("2.1.0") // Updated to use upstream chart - different label selectors
 
-	if release != nil && release.Version.LessThan(v2) && !chart.Version.LessThan(v2) {
+	if opt.Plan != nil {
+		if release != nil && release.Version.LessThan(v2) && !chart.Version.LessThan(v2) {
+			opt.Plan.AddMigration(
+				"cert-manager-v2",
+				"remove and recreate all cert-manager resources to migrate the CRDs from v1alpha2 to v1",
+				"--migrate-cert-manager",
+				opt.EnableCertManagerV2Migration,
+			)
+		}
+
+		if release != nil && release.Version.LessThan(v21) && !chart.Version.LessThan(v21) {
+			opt.Plan.AddMigration(
+				"cert-manager-upstream",
+				"remove the old cert-manager Deployments before upgrading to the upstream chart",
+				"--migrate-upstream-cert-manager",
+				opt.EnableCertManagerUpstreamMigration,
+			)
+		}
+
+		if err := util.PlanCRDs(ctx, kubeClient, sublogger, opt.Plan, filepath.Join(chartDir, "crd"), nil, crd.MasterCluster); err != nil {
+			return fmt.Errorf("failed to plan CRDs: %w", err)
+		}
+	} else if release != nil && release.Version.LessThan(v2) && !chart.Version.LessThan(v2) {
 		if !opt.EnableCertManagerV2Migration {
 			sublogger.Warn("cert-manager CRDs need to be migrated. This requires to temporarily remove and recreate")
 			sublogger.Warn("all related resources (like Certificates, Issuers, etc.). Rerun the installer with")
@@ -110,7 +132,7 @@ func deployCertManager(ctx context.Context, logger *logrus.Entry, kubeClient ctr
 		}
 	}
 
-	if release != nil && release.Version.LessThan(v21) && !chart.Version.LessThan(v21) {
+	if opt.Plan == nil && release != nil && release.Version.LessThan(v21) && !chart.Version.LessThan(v21) {
 		if !opt.EnableCertManagerUpstreamMigration {
 			sublogger.Warn("To upgrade cert-manager to a new version, the installer will")
 			sublogger.Warn("remove the old deployment objects before proceeding with the upgrade.")
@@ -135,8 +157,10 @@ func deployCertManager(ctx context.Context, logger *logrus.Entry, kubeClient ctr
 		return fmt.Errorf("failed to deploy Helm release: %w", err)
 	}
 
-	if err := waitForCertManagerWebhook(ctx, sublogger, kubeClient); err != nil {
-		return fmt.Errorf("failed to verify that the webhook is functioning: %w", err)
+	if opt.Plan == nil {
+		if err := waitForCertManagerWebhook(ctx, sublogger, kubeClient); err != nil {
+			return fmt.Errorf("failed to verify that the webhook is functioning: %w", err)
+		}
 	}
 
 	logger.Info("✅ Success.")
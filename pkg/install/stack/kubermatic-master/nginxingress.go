@@ -77,7 +77,16 @@ func deployNginxIngressController(ctx context.Context, logger *logrus.Entry, kub
 
 	isUpgrading := false
 
-	if release != nil && release.Version.LessThan(v13) && !chart.Version.LessThan(v13) {
+	if opt.Plan != nil {
+		if release != nil && release.Version.LessThan(v13) && !chart.Version.LessThan(v13) {
+			opt.Plan.AddMigration(
+				"nginx-ingress-controller-upstream",
+				"remove the old nginx-ingress-controller Deployment before upgrading to the upstream chart",
+				"--migrate-upstream-nginx-ingress",
+				opt.EnableNginxIngressMigration,
+			)
+		}
+	} else if release != nil && release.Version.LessThan(v13) && !chart.Version.LessThan(v13) {
 		if !opt.EnableNginxIngressMigration {
 			sublogger.Warn("To upgrade nginx-ingress-controller to a new version, the installer")
 			sublogger.Warn("will remove the old deployment object before proceeding with the upgrade.")
@@ -105,8 +114,10 @@ func deployNginxIngressController(ctx context.Context, logger *logrus.Entry, kub
 		return fmt.Errorf("failed to deploy Helm release: %w", err)
 	}
 
-	if err := waitForNginxIngressWebhook(ctx, sublogger, kubeClient, helmClient, opt); err != nil {
-		return fmt.Errorf("failed to verify that the webhook is functioning: %w", err)
+	if opt.Plan == nil {
+		if err := waitForNginxIngressWebhook(ctx, sublogger, kubeClient, helmClient, opt); err != nil {
+			return fmt.Errorf("failed to verify that the webhook is functioning: %w", err)
+		}
 	}
 
 	logger.Info("✅ Success.")
@@ -111,7 +111,10 @@ func (s *MasterStack) Deploy(ctx context.Context, opt stack.DeployOptions) error
 		return fmt.Errorf("failed to deploy Telemetry: %w", err)
 	}
 
-	showDNSSettings(ctx, opt.Logger, opt.KubeClient, opt)
+	// a dry-run creates no LoadBalancer, so waiting for its address would be pointless
+	if opt.Plan == nil {
+		showDNSSettings(ctx, opt.Logger, opt.KubeClient, opt)
+	}
 
 	return nil
 }
@@ -292,6 +295,14 @@ func (s *MasterStack) deployKubermaticOperator(ctx context.Context, logger *logr
 func (*MasterStack) InstallKubermaticCRDs(ctx context.Context, client ctrlruntimeclient.Client, logger logrus.FieldLogger, opt stack.DeployOptions) error {
 	crdDirectory := filepath.Join(opt.ChartsDirectory, "kubermatic-operator", "crd")
 
+	if opt.Plan != nil {
+		if err := util.PlanCRDs(ctx, client, logger, opt.Plan, filepath.Join(crdDirectory, "k8c.io"), &opt.Versions, crd.MasterCluster); err != nil {
+			return err
+		}
+
+		return util.PlanCRDs(ctx, client, logger, opt.Plan, filepath.Join(crdDirectory, "k8s.io"), nil, crd.MasterCluster)
+	}
+
 	// install KKP CRDs
 	if err := util.DeployCRDs(ctx, client, logger, filepath.Join(crdDirectory, "k8c.io"), &opt.Versions, crd.MasterCluster); err != nil {
 		return err
@@ -23,6 +23,7 @@ import (
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/install/helm"
+	"k8c.io/kubermatic/v2/pkg/install/plan"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/util/yamled"
 	kubermaticversion "k8c.io/kubermatic/v2/pkg/version/kubermatic"
@@ -62,6 +63,12 @@ type DeployOptions struct {
 	MLAIncludeIap            bool
 
 	SkipCharts []string
+
+	// Plan is set when the installer runs in dry-run mode. The Helm and
+	// Kubernetes clients are then wrapped to only record changes in the plan
+	// and stacks must not run migrations or wait for resources, but record
+	// those in the plan as well.
+	Plan *plan.Plan
 }
 
 type Stack interface {
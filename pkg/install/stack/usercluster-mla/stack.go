@@ -216,11 +216,15 @@ func deployConsul(ctx context.Context, logger *logrus.Entry, kubeClient ctrlrunt
 	v22 := semverlib.MustParse("2.22.0")
 
 	if release != nil && release.Version.LessThan(v22) && !chart.Version.LessThan(v22) {
-		sublogger.Warn("Installation process will temporarily remove and then upgrade Statefulset used by Consul.")
-
-		err = upgradeConsulStatefulsets(ctx, sublogger, kubeClient, helmClient, opt, chart, release)
-		if err != nil {
-			return fmt.Errorf("failed to prepare Consul for upgrade: %w", err)
+		if opt.Plan != nil {
+			opt.Plan.AddMigration("consul-statefulsets", "temporarily remove and then upgrade the Statefulset used by Consul", "", true)
+		} else {
+			sublogger.Warn("Installation process will temporarily remove and then upgrade Statefulset used by Consul.")
+
+			err = upgradeConsulStatefulsets(ctx, sublogger, kubeClient, helmClient, opt, chart, release)
+			if err != nil {
+				return fmt.Errorf("failed to prepare Consul for upgrade: %w", err)
+			}
 		}
 	}
 
@@ -254,11 +258,15 @@ func deployCortex(ctx context.Context, logger *logrus.Entry, kubeClient ctrlrunt
 	v22 := semverlib.MustParse("2.22.0")
 
 	if release != nil && release.Version.LessThan(v22) && !chart.Version.LessThan(v22) {
-		sublogger.Warn("Installation process will temporarily remove and then upgrade memcached instances used by Cortex.")
-
-		err = upgradeCortexStatefulsets(ctx, sublogger, kubeClient, helmClient, opt, chart, release)
-		if err != nil {
-			return fmt.Errorf("failed to prepare Cortex for upgrade: %w", err)
+		if opt.Plan != nil {
+			opt.Plan.AddMigration("cortex-statefulsets", "temporarily remove and then upgrade the memcached instances used by Cortex", "", true)
+		} else {
+			sublogger.Warn("Installation process will temporarily remove and then upgrade memcached instances used by Cortex.")
+
+			err = upgradeCortexStatefulsets(ctx, sublogger, kubeClient, helmClient, opt, chart, release)
+			if err != nil {
+				return fmt.Errorf("failed to prepare Cortex for upgrade: %w", err)
+			}
 		}
 	}
 
@@ -23,33 +23,105 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"k8c.io/kubermatic/v2/pkg/install/plan"
 	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/util/crd"
 	kubermaticversion "k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func DeployCRDs(ctx context.Context, kubeClient ctrlruntimeclient.Client, log logrus.FieldLogger, directory string, versions *kubermaticversion.Versions, kind crd.ClusterKind) error {
-	crds, err := crd.LoadFromDirectory(directory)
+	crds, err := loadCRDs(log, directory, versions, kind)
 	if err != nil {
-		return fmt.Errorf("failed to load CRDs: %w", err)
+		return err
+	}
+
+	for _, crdObject := range crds {
+		log.WithField("name", crdObject.GetName()).Debug("Creating CRD…")
+
+		if err := DeployCRD(ctx, kubeClient, crdObject); err != nil {
+			return fmt.Errorf("failed to deploy CRD %s: %w", crdObject.GetName(), err)
+		}
 	}
 
+	// wait for CRDs to be established
 	for _, crdObject := range crds {
-		logger := log.WithField("name", crdObject.GetName())
+		if err := WaitForReadyCRD(ctx, kubeClient, crdObject.GetName(), 30*time.Second); err != nil {
+			return fmt.Errorf("failed to wait for CRD %s to have Established=True condition: %w", crdObject.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// PlanCRDs compares the CRDs in the given directory with the ones in the cluster
+// and records all required changes in the plan, without modifying the cluster.
+func PlanCRDs(ctx context.Context, kubeClient ctrlruntimeclient.Client, log logrus.FieldLogger, p *plan.Plan, directory string, versions *kubermaticversion.Versions, kind crd.ClusterKind) error {
+	crds, err := loadCRDs(log, directory, versions, kind)
+	if err != nil {
+		return err
+	}
+
+	for _, crdObject := range crds {
+		desired := &apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(crdObject.UnstructuredContent(), desired); err != nil {
+			return fmt.Errorf("failed to parse CRD %s: %w", crdObject.GetName(), err)
+		}
+
+		// apply the same defaults as the API server, so they do not show up as changes
+		apiextensionsv1.SetObjectDefaults_CustomResourceDefinition(desired)
+
+		change := plan.CRD{Name: desired.Name}
+
+		existing := &apiextensionsv1.CustomResourceDefinition{}
+		err := kubeClient.Get(ctx, types.NamespacedName{Name: desired.Name}, existing)
+
+		switch {
+		case apierrors.IsNotFound(err):
+			change.Action = plan.ActionCreate
+
+		case err != nil:
+			return fmt.Errorf("failed to retrieve existing CRD %s: %w", desired.Name, err)
+
+		default:
+			change.Diff, err = plan.Diff(existing.Spec, desired.Spec)
+			if err != nil {
+				return fmt.Errorf("failed to diff CRD %s: %w", desired.Name, err)
+			}
 
+			change.Action = plan.ActionNone
+			if change.Diff != "" {
+				change.Action = plan.ActionUpdate
+			}
+		}
+
+		p.CRDs = append(p.CRDs, change)
+	}
+
+	return nil
+}
+
+func loadCRDs(log logrus.FieldLogger, directory string, versions *kubermaticversion.Versions, kind crd.ClusterKind) ([]*unstructured.Unstructured, error) {
+	crds, err := crd.LoadFromDirectory(directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CRDs: %w", err)
+	}
+
+	result := []*unstructured.Unstructured{}
+
+	for _, crdObject := range crds {
 		if crd.SkipCRDOnCluster(crdObject, kind) {
-			logger.Debug("Skipping CRD")
+			log.WithField("name", crdObject.GetName()).Debug("Skipping CRD")
 			continue
 		}
 
-		logger.Debug("Creating CRD…")
-
 		if versions != nil {
 			// inject the current KKP version, so the operator and other controllers
 			// can react to the changed CRDs (the seed-operator will do the same when
@@ -62,23 +134,10 @@ func DeployCRDs(ctx context.Context, kubeClient ctrlruntimeclient.Client, log lo
 			crdObject.SetAnnotations(annotations)
 		}
 
-		if err := DeployCRD(ctx, kubeClient, crdObject); err != nil {
-			return fmt.Errorf("failed to deploy CRD %s: %w", crdObject.GetName(), err)
-		}
+		result = append(result, crdObject.(*unstructured.Unstructured))
 	}
 
-	// wait for CRDs to be established
-	for _, crdObject := range crds {
-		if crd.SkipCRDOnCluster(crdObject, kind) {
-			continue
-		}
-
-		if err := WaitForReadyCRD(ctx, kubeClient, crdObject.GetName(), 30*time.Second); err != nil {
-			return fmt.Errorf("failed to wait for CRD %s to have Established=True condition: %w", crdObject.GetName(), err)
-		}
-	}
-
-	return nil
+	return result, nil
 }
 
 func DeployCRD(ctx context.Context, kubeClient ctrlruntimeclient.Client, crd ctrlruntimeclient.Object) error {
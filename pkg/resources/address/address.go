@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilnet "k8s.io/utils/net"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 type lookupFunction func(host string) ([]net.IP, error)
@@ -106,6 +107,20 @@ func (m *ModifiersBuilder) Build(ctx context.Context) ([]func(*kubermaticv1.Clus
 		frontProxyLBServiceIP, frontProxyLBServiceHostname = m.getFrontProxyLBServiceData(frontProxyLoadBalancerService)
 	}
 
+	var gateway *gatewayapiv1beta1.Gateway
+	if m.cluster.Spec.ExposeStrategy == kubermaticv1.ExposeStrategyGatewayAPI {
+		cfg := m.seed.Spec.GatewayAPI
+		if cfg == nil {
+			return nil, fmt.Errorf("expose strategy %q requires a Gateway API configuration in the Seed", m.cluster.Spec.ExposeStrategy)
+		}
+
+		gateway = &gatewayapiv1beta1.Gateway{}
+		nn := types.NamespacedName{Namespace: cfg.Gateway.Namespace, Name: cfg.Gateway.Name}
+		if err := m.client.Get(ctx, nn, gateway); err != nil {
+			return nil, fmt.Errorf("failed to get Gateway %q: %w", nn.String(), err)
+		}
+	}
+
 	// External Name
 	externalName := ""
 	if m.cluster.Spec.ExposeStrategy == kubermaticv1.ExposeStrategyLoadBalancer {
@@ -151,6 +166,12 @@ func (m *ModifiersBuilder) Build(ctx context.Context) ([]func(*kubermaticv1.Clus
 				return nil, err
 			}
 		}
+	case kubermaticv1.ExposeStrategyGatewayAPI:
+		var err error
+		ip, err = m.getGatewayIPv4(gateway, externalName)
+		if err != nil {
+			return nil, err
+		}
 	case kubermaticv1.ExposeStrategyNodePort:
 		fallthrough
 	case kubermaticv1.ExposeStrategyTunneling:
@@ -178,14 +199,20 @@ func (m *ModifiersBuilder) Build(ctx context.Context) ([]func(*kubermaticv1.Clus
 	}
 
 	// Port
-	var port int32 = service.Spec.Ports[0].TargetPort.IntVal
-	if m.cluster.Spec.ExposeStrategy != kubermaticv1.ExposeStrategyTunneling {
+	var port int32
+	switch m.cluster.Spec.ExposeStrategy {
+	case kubermaticv1.ExposeStrategyTunneling:
+		port = service.Spec.Ports[0].TargetPort.IntVal
+	case kubermaticv1.ExposeStrategyGatewayAPI:
+		port = getGatewayListenerPort(gateway, m.seed.Spec.GatewayAPI.ListenerName)
+	default:
 		port = service.Spec.Ports[0].NodePort
 	}
 
 	// Use the nodeport value for KAS secure port when strategy is NodePort or
 	// LoadBalancer. This is because the same service will be accessed both
-	// locally and passing from nodeport proxy.
+	// locally and passing from nodeport proxy. The same applies to the
+	// Gateway listener port when strategy is GatewayAPI.
 	if m.cluster.Status.Address.Port != port {
 		modifiers = append(modifiers, func(c *kubermaticv1.Cluster) {
 			c.Status.Address.Port = port
@@ -244,6 +271,57 @@ func (m *ModifiersBuilder) getFrontProxyLBServiceData(frontProxyLoadBalancerServ
 	return serviceIP, serviceHostname
 }
 
+// getGatewayIPv4 returns the first IPv4 address published in the Gateway status.
+// If the Gateway only publishes hostnames, the first one is resolved instead.
+// Gateways without any status addresses are reached via the cluster's
+// external name, like with the NodePort strategy.
+func (m *ModifiersBuilder) getGatewayIPv4(gateway *gatewayapiv1beta1.Gateway, externalName string) (string, error) {
+	hostname := ""
+	for _, addr := range gateway.Status.Addresses {
+		addrType := gatewayapiv1beta1.IPAddressType
+		if addr.Type != nil {
+			addrType = *addr.Type
+		}
+
+		switch addrType {
+		case gatewayapiv1beta1.IPAddressType:
+			if ip := net.ParseIP(addr.Value); ip != nil && ip.To4() != nil {
+				return ip.String(), nil
+			}
+		case gatewayapiv1beta1.HostnameAddressType:
+			if hostname == "" {
+				hostname = addr.Value
+			}
+		}
+	}
+
+	if hostname == "" {
+		hostname = externalName
+	}
+
+	return m.getExternalIPv4(hostname)
+}
+
+// getGatewayListenerPort returns the port of the Gateway listener the TLSRoutes
+// are attached to. Without an explicit listener name, the first TLS listener is
+// used. If none can be found, the default HTTPS port is assumed.
+func getGatewayListenerPort(gateway *gatewayapiv1beta1.Gateway, listenerName string) int32 {
+	for _, listener := range gateway.Spec.Listeners {
+		if listenerName != "" {
+			if string(listener.Name) == listenerName {
+				return int32(listener.Port)
+			}
+			continue
+		}
+
+		if listener.Protocol == gatewayapiv1beta1.TLSProtocolType {
+			return int32(listener.Port)
+		}
+	}
+
+	return 443
+}
+
 func (m *ModifiersBuilder) getExternalIPv4(hostname string) (string, error) {
 	resolvedIPs, err := m.lookupFunction(hostname)
 	if err != nil {
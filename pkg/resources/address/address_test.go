@@ -28,10 +28,22 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
+var (
+	testScheme = runtime.NewScheme()
+)
+
+func init() {
+	_ = clientgoscheme.AddToScheme(testScheme)
+	_ = gatewayapiv1beta1.AddToScheme(testScheme)
+}
+
 const (
 	fakeClusterName          = "fake-cluster"
 	fakeDCName               = "europe-west3-c"
@@ -75,8 +87,10 @@ func TestSyncClusterAddress(t *testing.T) {
 		name                 string
 		apiserverService     corev1.Service
 		frontproxyService    corev1.Service
+		gateway              gatewayapiv1beta1.Gateway
 		exposeStrategy       kubermaticv1.ExposeStrategy
 		seedDNSOverwrite     string
+		gatewayAPIConfig     *kubermaticv1.SeedGatewayAPIConfiguration
 		expectedExternalName string
 		expectedIP           string
 		expectedPort         int32
@@ -287,6 +301,81 @@ func TestSyncClusterAddress(t *testing.T) {
 			expectedPort:         int32(6443),
 			expectedURL:          fmt.Sprintf("https://%s.%s.%s:6443", fakeClusterName, fakeDCName, fakeExternalURL),
 		},
+		{
+			name: "Verify properties for GatewayAPI expose strategy",
+			apiserverService: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeClusterIP,
+					Ports: []corev1.ServicePort{
+						{
+							Port:       int32(443),
+							TargetPort: intstr.FromInt(8443),
+						}},
+				},
+			},
+			gateway: gatewayapiv1beta1.Gateway{
+				Spec: gatewayapiv1beta1.GatewaySpec{
+					Listeners: []gatewayapiv1beta1.Listener{
+						{Name: "http", Port: 80, Protocol: gatewayapiv1beta1.HTTPProtocolType},
+						{Name: "tls", Port: 8443, Protocol: gatewayapiv1beta1.TLSProtocolType},
+					},
+				},
+				Status: gatewayapiv1beta1.GatewayStatus{
+					Addresses: []gatewayapiv1beta1.GatewayAddress{
+						{Value: "2001:db8::1"},
+						{Value: "5.6.7.8"},
+					},
+				},
+			},
+			exposeStrategy:       kubermaticv1.ExposeStrategyGatewayAPI,
+			gatewayAPIConfig:     &kubermaticv1.SeedGatewayAPIConfiguration{},
+			expectedExternalName: fmt.Sprintf("%s.%s.%s", fakeClusterName, fakeDCName, fakeExternalURL),
+			expectedIP:           "5.6.7.8",
+			expectedPort:         int32(8443),
+			expectedURL:          fmt.Sprintf("https://%s.%s.%s:8443", fakeClusterName, fakeDCName, fakeExternalURL),
+		},
+		{
+			name: "Verify properties for GatewayAPI expose strategy with named listener and hostname address",
+			apiserverService: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeClusterIP,
+					Ports: []corev1.ServicePort{
+						{
+							Port:       int32(443),
+							TargetPort: intstr.FromInt(443),
+						}},
+				},
+			},
+			gateway: gatewayapiv1beta1.Gateway{
+				Spec: gatewayapiv1beta1.GatewaySpec{
+					Listeners: []gatewayapiv1beta1.Listener{
+						{Name: "tls", Port: 8443, Protocol: gatewayapiv1beta1.TLSProtocolType},
+						{Name: "kkp", Port: 443, Protocol: gatewayapiv1beta1.TLSProtocolType},
+					},
+				},
+				Status: gatewayapiv1beta1.GatewayStatus{
+					Addresses: []gatewayapiv1beta1.GatewayAddress{
+						{Type: addressTypePtr(gatewayapiv1beta1.HostnameAddressType), Value: loadbBalancerHostName},
+					},
+				},
+			},
+			exposeStrategy:       kubermaticv1.ExposeStrategyGatewayAPI,
+			gatewayAPIConfig:     &kubermaticv1.SeedGatewayAPIConfiguration{ListenerName: "kkp"},
+			expectedExternalName: fmt.Sprintf("%s.%s.%s", fakeClusterName, fakeDCName, fakeExternalURL),
+			expectedIP:           externalIP,
+			expectedPort:         int32(443),
+			expectedURL:          fmt.Sprintf("https://%s.%s.%s:443", fakeClusterName, fakeDCName, fakeExternalURL),
+		},
+		{
+			name: "Verify error for GatewayAPI expose strategy without Seed configuration",
+			apiserverService: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: int32(443)}},
+				},
+			},
+			exposeStrategy: kubermaticv1.ExposeStrategyGatewayAPI,
+			errExpected:    true,
+		},
 		{
 			name: "Verify error when service has less than one ports",
 			apiserverService: corev1.Service{
@@ -320,7 +409,17 @@ func TestSyncClusterAddress(t *testing.T) {
 			lbService := &tc.frontproxyService
 			lbService.Name = resources.FrontLoadBalancerServiceName
 			lbService.Namespace = fakeClusterNamespaceName
-			client := fakectrlruntimeclient.NewClientBuilder().WithObjects(apiserverService, lbService).Build()
+			gateway := &tc.gateway
+			gateway.Name = "kkp-gateway"
+			gateway.Namespace = "gateway-system"
+			client := fakectrlruntimeclient.NewClientBuilder().WithScheme(testScheme).WithObjects(apiserverService, lbService, gateway).Build()
+
+			if tc.gatewayAPIConfig != nil {
+				tc.gatewayAPIConfig.Gateway = kubermaticv1.GatewayReference{
+					Name:      gateway.Name,
+					Namespace: gateway.Namespace,
+				}
+			}
 
 			seed := &kubermaticv1.Seed{
 				ObjectMeta: metav1.ObjectMeta{
@@ -328,6 +427,7 @@ func TestSyncClusterAddress(t *testing.T) {
 				},
 				Spec: kubermaticv1.SeedSpec{
 					SeedDNSOverwrite: tc.seedDNSOverwrite,
+					GatewayAPI:       tc.gatewayAPIConfig,
 				},
 			}
 
@@ -371,3 +471,7 @@ func TestSyncClusterAddress(t *testing.T) {
 		})
 	}
 }
+
+func addressTypePtr(t gatewayapiv1beta1.AddressType) *gatewayapiv1beta1.AddressType {
+	return &t
+}
@@ -30,7 +30,9 @@ import (
 )
 
 // ServiceReconciler returns the function to reconcile the external API server service.
-func ServiceReconciler(exposeStrategy kubermaticv1.ExposeStrategy, externalURL string) reconciling.NamedServiceReconcilerFactory {
+// The externalPort is only used for the GatewayAPI expose strategy, where the API server
+// listens on the same port as the Gateway listener.
+func ServiceReconciler(exposeStrategy kubermaticv1.ExposeStrategy, externalURL string, externalPort int32) reconciling.NamedServiceReconcilerFactory {
 	return func() (string, reconciling.ServiceReconciler) {
 		return resources.ApiserverServiceName, func(se *corev1.Service) (*corev1.Service, error) {
 			if se.Annotations == nil {
@@ -59,6 +61,14 @@ func ServiceReconciler(exposeStrategy kubermaticv1.ExposeStrategy, externalURL s
 				// We map the secure port to the internal name for SNI routing.
 				se.Annotations[nodeportproxy.PortHostMappingAnnotationKey] = fmt.Sprintf(`{"secure": %q}`, externalURL)
 				delete(se.Annotations, nodeportproxy.NodePortProxyExposeNamespacedAnnotationKey)
+			case kubermaticv1.ExposeStrategyGatewayAPI:
+				// When using exposeStrategy==GatewayAPI the service is not
+				// handled by the nodeport proxy at all, but is the backend of
+				// a TLSRoute attached to the seed's Gateway.
+				se.Spec.Type = corev1.ServiceTypeClusterIP
+				delete(se.Annotations, nodeportproxy.DefaultExposeAnnotationKey)
+				delete(se.Annotations, nodeportproxy.NodePortProxyExposeNamespacedAnnotationKey)
+				delete(se.Annotations, nodeportproxy.PortHostMappingAnnotationKey)
 			default:
 				return nil, fmt.Errorf("unsupported expose strategy: %q", exposeStrategy)
 			}
@@ -83,10 +93,19 @@ func ServiceReconciler(exposeStrategy kubermaticv1.ExposeStrategy, externalURL s
 			se.Spec.Ports[0].Name = "secure"
 			se.Spec.Ports[0].Protocol = corev1.ProtocolTCP
 			se.Spec.Ports[0].Port = 443
-			if exposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+			switch exposeStrategy {
+			case kubermaticv1.ExposeStrategyTunneling:
 				se.Spec.Ports[0].TargetPort = intstr.FromInt(resources.APIServerSecurePort)
 				se.Spec.Ports[0].NodePort = 0 // allows switching from other expose strategies
-			} else {
+			case kubermaticv1.ExposeStrategyGatewayAPI:
+				// The port is unknown until the cluster address has been
+				// determined, use the service port in the meantime.
+				if externalPort == 0 {
+					externalPort = se.Spec.Ports[0].Port
+				}
+				se.Spec.Ports[0].TargetPort = intstr.FromInt(int(externalPort))
+				se.Spec.Ports[0].NodePort = 0
+			default:
 				// We assign the target port the same value as the NodePort port.
 				// The reason is that we need  both access the apiserver using
 				// this service (i.e. from seed cluster) and from the kubernetes
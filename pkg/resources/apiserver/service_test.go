@@ -30,6 +30,7 @@ func TestServiceReconcilerRequiresExposeStrategy(t *testing.T) {
 		name            string
 		exposeStrategy  kubermaticv1.ExposeStrategy
 		internalService string
+		externalPort    int32
 		errExpected     bool
 	}{
 		{
@@ -40,6 +41,10 @@ func TestServiceReconcilerRequiresExposeStrategy(t *testing.T) {
 			name:           "LoadBalancer is accepted as exposeStrategy",
 			exposeStrategy: kubermaticv1.ExposeStrategyNodePort,
 		},
+		{
+			name:           "GatewayAPI is accepted as exposeStrategy",
+			exposeStrategy: kubermaticv1.ExposeStrategyGatewayAPI,
+		},
 		{
 			name:        "Empty is not accepted as exposeStrategy",
 			errExpected: true,
@@ -48,7 +53,7 @@ func TestServiceReconcilerRequiresExposeStrategy(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, creator := ServiceReconciler(tc.exposeStrategy, tc.internalService)()
+			_, creator := ServiceReconciler(tc.exposeStrategy, tc.internalService, tc.externalPort)()
 			_, err := creator(&corev1.Service{})
 			if (err != nil) != tc.errExpected {
 				t.Errorf("Expected err: %t, but got err %v", tc.errExpected, err)
@@ -62,6 +67,7 @@ func TestServiceReconcilerSetsPort(t *testing.T) {
 		name               string
 		exposeStrategy     kubermaticv1.ExposeStrategy
 		internalService    string
+		externalPort       int32
 		inService          *corev1.Service
 		expectedPort       int32
 		expectedTargetPort intstr.IntOrString
@@ -128,11 +134,32 @@ func TestServiceReconcilerSetsPort(t *testing.T) {
 			expectedPort:       int32(443),
 			expectedTargetPort: intstr.FromInt(6443),
 		},
+		{
+			name:           "With GatewayAPI strategy KAS uses the Gateway listener port as secure port",
+			exposeStrategy: kubermaticv1.ExposeStrategyGatewayAPI,
+			externalPort:   int32(8443),
+			inService: &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeNodePort,
+					Ports: []corev1.ServicePort{
+						{
+							Name:       "my-fancy-port",
+							Port:       int32(8080),
+							TargetPort: intstr.FromInt(8080),
+							Protocol:   corev1.ProtocolUDP,
+							NodePort:   int32(32000),
+						},
+					},
+				},
+			},
+			expectedPort:       int32(443),
+			expectedTargetPort: intstr.FromInt(8443),
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, creator := ServiceReconciler(tc.exposeStrategy, tc.internalService)()
+			_, creator := ServiceReconciler(tc.exposeStrategy, tc.internalService, tc.externalPort)()
 			svc, err := creator(tc.inService)
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
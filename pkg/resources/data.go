@@ -426,8 +426,12 @@ func (d *TemplateData) GetPodTemplateLabels(appName string, volumes []corev1.Vol
 // GetOpenVPNServerPort returns the nodeport of the external apiserver service.
 func (d *TemplateData) GetOpenVPNServerPort() (int32, error) {
 	// When using tunneling expose strategy the port is fixed
-	if d.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+	switch d.Cluster().Spec.ExposeStrategy {
+	case kubermaticv1.ExposeStrategyTunneling:
 		return 1194, nil
+	case kubermaticv1.ExposeStrategyGatewayAPI:
+		// OpenVPN cannot be routed based on SNI
+		return 0, fmt.Errorf("expose strategy %q requires Konnectivity", d.Cluster().Spec.ExposeStrategy)
 	}
 	service := &corev1.Service{}
 	key := types.NamespacedName{Namespace: d.cluster.Status.NamespaceName, Name: OpenVPNServerServiceName}
@@ -440,8 +444,9 @@ func (d *TemplateData) GetOpenVPNServerPort() (int32, error) {
 
 // GetKonnectivityServerPort returns the nodeport of the external Konnectivity Server service.
 func (d *TemplateData) GetKonnectivityServerPort() (int32, error) {
-	// When using tunneling expose strategy the port is fixed and equal to apiserver port
-	if d.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+	// When using tunneling or Gateway API expose strategy the port is fixed and equal to apiserver port
+	switch d.Cluster().Spec.ExposeStrategy {
+	case kubermaticv1.ExposeStrategyTunneling, kubermaticv1.ExposeStrategyGatewayAPI:
 		return d.Cluster().Status.Address.Port, nil
 	}
 	service := &corev1.Service{}
@@ -472,8 +477,9 @@ func (d *TemplateData) GetTunnelingAgentIP() string {
 
 // GetMLAGatewayPort returns the NodePort of the external MLA Gateway service.
 func (d *TemplateData) GetMLAGatewayPort() (int32, error) {
-	// When using tunneling expose strategy the port is fixed and equal to apiserver port
-	if d.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+	// When using tunneling or Gateway API expose strategy the port is fixed and equal to apiserver port
+	switch d.Cluster().Spec.ExposeStrategy {
+	case kubermaticv1.ExposeStrategyTunneling, kubermaticv1.ExposeStrategyGatewayAPI:
 		return d.Cluster().Status.Address.Port, nil
 	}
 	service := &corev1.Service{}
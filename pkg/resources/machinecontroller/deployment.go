@@ -101,6 +101,11 @@ func DeploymentReconcilerWithoutInitWrapper(data machinecontrollerData) reconcil
 			dep.Labels = resources.BaseAppLabels(Name, nil)
 
 			dep.Spec.Replicas = resources.Int32(1)
+			// the Machines restored into a cloned cluster still refer to the instances
+			// of the source cluster and must not be reconciled until they are cleaned up
+			if _, cloning := data.Cluster().Annotations[kubermaticv1.CloneInProgressAnnotation]; cloning {
+				dep.Spec.Replicas = resources.Int32(0)
+			}
 			dep.Spec.Selector = &metav1.LabelSelector{
 				MatchLabels: resources.BaseAppLabels(Name, nil),
 			}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeportproxy
+
+import (
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kkpreconciling "k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// TLSRouteReconciler returns a reconciler for a TLSRoute that attaches the given
+// Service to the Gateway configured in the Seed. The Gateway listener must be
+// configured for TLS passthrough, connections are routed purely based on the
+// SNI hostname. The route is named after the Service it exposes.
+func TLSRouteReconciler(serviceName string, port int32, hostname string, cfg *kubermaticv1.SeedGatewayAPIConfiguration) kkpreconciling.NamedTLSRouteReconcilerFactory {
+	return func() (string, kkpreconciling.TLSRouteReconciler) {
+		return serviceName, func(r *gatewayapiv1alpha2.TLSRoute) (*gatewayapiv1alpha2.TLSRoute, error) {
+			parent := gatewayapiv1beta1.ParentReference{
+				Group:     groupPtr(gatewayapiv1beta1.GroupName),
+				Kind:      kindPtr("Gateway"),
+				Name:      gatewayapiv1beta1.ObjectName(cfg.Gateway.Name),
+				Namespace: namespacePtr(cfg.Gateway.Namespace),
+			}
+			if cfg.ListenerName != "" {
+				sectionName := gatewayapiv1beta1.SectionName(cfg.ListenerName)
+				parent.SectionName = &sectionName
+			}
+
+			backendPort := gatewayapiv1beta1.PortNumber(port)
+
+			r.Spec.ParentRefs = []gatewayapiv1beta1.ParentReference{parent}
+			r.Spec.Hostnames = []gatewayapiv1beta1.Hostname{gatewayapiv1beta1.Hostname(hostname)}
+			r.Spec.Rules = []gatewayapiv1alpha2.TLSRouteRule{{
+				BackendRefs: []gatewayapiv1beta1.BackendRef{{
+					BackendObjectReference: gatewayapiv1beta1.BackendObjectReference{
+						Group: groupPtr(""),
+						Kind:  kindPtr("Service"),
+						Name:  gatewayapiv1beta1.ObjectName(serviceName),
+						Port:  &backendPort,
+					},
+					Weight: pointer.Int32(1),
+				}},
+			}}
+
+			return r, nil
+		}
+	}
+}
+
+// TLSRoutesForDeletion returns the TLSRoutes for the given Services, so they
+// can be removed once a cluster is no longer using the GatewayAPI expose strategy.
+func TLSRoutesForDeletion(namespace string, serviceNames ...string) []ctrlruntimeclient.Object {
+	objects := []ctrlruntimeclient.Object{}
+	for _, name := range serviceNames {
+		objects = append(objects, &gatewayapiv1alpha2.TLSRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		})
+	}
+
+	return objects
+}
+
+func groupPtr(g string) *gatewayapiv1beta1.Group {
+	group := gatewayapiv1beta1.Group(g)
+	return &group
+}
+
+func kindPtr(k string) *gatewayapiv1beta1.Kind {
+	kind := gatewayapiv1beta1.Kind(k)
+	return &kind
+}
+
+func namespacePtr(ns string) *gatewayapiv1beta1.Namespace {
+	namespace := gatewayapiv1beta1.Namespace(ns)
+	return &namespace
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeportproxy
+
+import (
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestTLSRouteReconciler(t *testing.T) {
+	testcases := []struct {
+		name            string
+		cfg             *kubermaticv1.SeedGatewayAPIConfiguration
+		expectedSection string
+	}{
+		{
+			name: "all listeners",
+			cfg: &kubermaticv1.SeedGatewayAPIConfiguration{
+				Gateway: kubermaticv1.GatewayReference{Name: "kkp", Namespace: "gateway"},
+			},
+		},
+		{
+			name: "specific listener",
+			cfg: &kubermaticv1.SeedGatewayAPIConfiguration{
+				Gateway:      kubermaticv1.GatewayReference{Name: "kkp", Namespace: "gateway"},
+				ListenerName: "tls",
+			},
+			expectedSection: "tls",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			name, reconciler := TLSRouteReconciler("apiserver-external", 443, "abcd.europe-west3-c.dev.kubermatic.io", tc.cfg)()
+			if name != "apiserver-external" {
+				t.Fatalf("Expected route to be named after the Service, but got %q", name)
+			}
+
+			route, err := reconciler(&gatewayapiv1alpha2.TLSRoute{})
+			if err != nil {
+				t.Fatalf("Reconciling failed: %v", err)
+			}
+
+			if len(route.Spec.ParentRefs) != 1 {
+				t.Fatalf("Expected exactly one parent, but got %d", len(route.Spec.ParentRefs))
+			}
+
+			parent := route.Spec.ParentRefs[0]
+			if parent.Name != "kkp" || parent.Namespace == nil || *parent.Namespace != "gateway" {
+				t.Errorf("Expected parent to be the Gateway gateway/kkp, but got %+v", parent)
+			}
+			if parent.Kind == nil || *parent.Kind != "Gateway" || parent.Group == nil || *parent.Group != gatewayapiv1beta1.GroupName {
+				t.Errorf("Expected parent to be a Gateway, but got %+v", parent)
+			}
+
+			section := ""
+			if parent.SectionName != nil {
+				section = string(*parent.SectionName)
+			}
+			if section != tc.expectedSection {
+				t.Errorf("Expected listener %q, but got %q", tc.expectedSection, section)
+			}
+
+			if len(route.Spec.Hostnames) != 1 || route.Spec.Hostnames[0] != "abcd.europe-west3-c.dev.kubermatic.io" {
+				t.Errorf("Expected the cluster hostname, but got %v", route.Spec.Hostnames)
+			}
+
+			if len(route.Spec.Rules) != 1 || len(route.Spec.Rules[0].BackendRefs) != 1 {
+				t.Fatalf("Expected exactly one backend, but got %+v", route.Spec.Rules)
+			}
+
+			backend := route.Spec.Rules[0].BackendRefs[0]
+			if backend.Name != "apiserver-external" || backend.Kind == nil || *backend.Kind != "Service" || backend.Port == nil || *backend.Port != 443 {
+				t.Errorf("Expected the backend to be port 443 of the Service, but got %+v", backend.BackendObjectReference)
+			}
+		})
+	}
+}
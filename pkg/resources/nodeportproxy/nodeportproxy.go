@@ -52,6 +52,19 @@ const (
 	// SNIType.
 	PortHostMappingAnnotationKey = "nodeport-proxy.k8s.io/port-mapping"
 
+	// MaxConnectionsAnnotationKey overrides the maximum number of concurrent
+	// connections to the exposed service.
+	MaxConnectionsAnnotationKey = "nodeport-proxy.k8s.io/max-connections"
+	// MaxPendingConnectionsAnnotationKey overrides the maximum number of
+	// connections waiting for an upstream connection to the exposed service.
+	MaxPendingConnectionsAnnotationKey = "nodeport-proxy.k8s.io/max-pending-connections"
+	// ConnectionsPerSecondAnnotationKey overrides the number of new connections
+	// per second accepted for the exposed service.
+	ConnectionsPerSecondAnnotationKey = "nodeport-proxy.k8s.io/connections-per-second"
+	// ConnectionBurstAnnotationKey overrides the number of new connections that
+	// can be accepted at once on top of the connections per second.
+	ConnectionBurstAnnotationKey = "nodeport-proxy.k8s.io/connection-burst"
+
 	loadBalancerSourceRangesAnnotationKey = "service.beta.kubernetes.io/load-balancer-source-ranges"
 )
 
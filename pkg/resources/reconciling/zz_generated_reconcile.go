@@ -33,6 +33,7 @@ import (
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	instancetypev1alpha1 "kubevirt.io/api/instancetype/v1alpha1"
 	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 // VerticalPodAutoscalerReconciler defines an interface to create/update VerticalPodAutoscalers.
@@ -1034,6 +1035,43 @@ func ReconcileVirtualMachinePreferences(ctx context.Context, namedFactories []Na
 	return nil
 }
 
+// TLSRouteReconciler defines an interface to create/update TLSRoutes.
+type TLSRouteReconciler = func(existing *gatewayapiv1alpha2.TLSRoute) (*gatewayapiv1alpha2.TLSRoute, error)
+
+// NamedTLSRouteReconcilerFactory returns the name of the resource and the corresponding Reconciler function.
+type NamedTLSRouteReconcilerFactory = func() (name string, reconciler TLSRouteReconciler)
+
+// TLSRouteObjectWrapper adds a wrapper so the TLSRouteReconciler matches ObjectReconciler.
+// This is needed as Go does not support function interface matching.
+func TLSRouteObjectWrapper(reconciler TLSRouteReconciler) reconciling.ObjectReconciler {
+	return func(existing ctrlruntimeclient.Object) (ctrlruntimeclient.Object, error) {
+		if existing != nil {
+			return reconciler(existing.(*gatewayapiv1alpha2.TLSRoute))
+		}
+		return reconciler(&gatewayapiv1alpha2.TLSRoute{})
+	}
+}
+
+// ReconcileTLSRoutes will create and update the TLSRoutes coming from the passed TLSRouteReconciler slice.
+func ReconcileTLSRoutes(ctx context.Context, namedFactories []NamedTLSRouteReconcilerFactory, namespace string, client ctrlruntimeclient.Client, objectModifiers ...reconciling.ObjectModifier) error {
+	for _, factory := range namedFactories {
+		name, reconciler := factory()
+		reconcileObject := TLSRouteObjectWrapper(reconciler)
+		reconcileObject = reconciling.CreateWithNamespace(reconcileObject, namespace)
+		reconcileObject = reconciling.CreateWithName(reconcileObject, name)
+
+		for _, objectModifier := range objectModifiers {
+			reconcileObject = objectModifier(reconcileObject)
+		}
+
+		if err := reconciling.EnsureNamedObject(ctx, types.NamespacedName{Namespace: namespace, Name: name}, reconcileObject, client, &gatewayapiv1alpha2.TLSRoute{}, false); err != nil {
+			return fmt.Errorf("failed to ensure TLSRoute %s/%s: %w", namespace, name, err)
+		}
+	}
+
+	return nil
+}
+
 // DataVolumeReconciler defines an interface to create/update DataVolumes.
 type DataVolumeReconciler = func(existing *cdiv1beta1.DataVolume) (*cdiv1beta1.DataVolume, error)
 
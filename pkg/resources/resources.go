@@ -34,6 +34,7 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	"k8c.io/kubermatic/v2/pkg/util/backupencryption"
 	"k8c.io/kubermatic/v2/pkg/util/s3"
 	"k8c.io/reconciler/pkg/reconciling"
 
@@ -554,6 +555,8 @@ const (
 	EtcdRestoreS3BucketNameKey    = "BUCKET_NAME"
 	EtcdRestoreS3EndpointKey      = "ENDPOINT"
 	EtcdRestoreDefaultS3SEndpoint = "s3.amazonaws.com"
+	// EtcdRestoreEncryptionKeyKey is the key in the backup download secret holding the key to decrypt encrypted backups.
+	EtcdRestoreEncryptionKeyKey = "ENCRYPTION_KEY"
 
 	// ApiserverEtcdClientCertificateCertSecretKey apiserver-etcd-client.crt.
 	ApiserverEtcdClientCertificateCertSecretKey = "apiserver-etcd-client.crt"
@@ -1653,6 +1656,18 @@ func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdResto
 		secretData[EtcdRestoreS3BucketNameKey] = destination.BucketName
 		secretData[EtcdRestoreS3EndpointKey] = destination.Endpoint
 
+		if destination.EncryptionKey != nil {
+			keySecret := &corev1.Secret{}
+			if err := client.Get(ctx, types.NamespacedName{Namespace: destination.Credentials.Namespace, Name: destination.EncryptionKey.Name}, keySecret); err != nil {
+				return nil, "", fmt.Errorf("failed to get encryption key secret %v/%v: %w", destination.Credentials.Namespace, destination.EncryptionKey.Name, err)
+			}
+			key, ok := keySecret.Data[destination.EncryptionKey.Key]
+			if !ok {
+				return nil, "", fmt.Errorf("encryption key secret %v/%v has no key %q", destination.Credentials.Namespace, destination.EncryptionKey.Name, destination.EncryptionKey.Key)
+			}
+			secretData[EtcdRestoreEncryptionKeyKey] = string(key)
+		}
+
 		creator := func(se *corev1.Secret) (*corev1.Secret, error) {
 			if se.Data == nil {
 				se.Data = map[string][]byte{}
@@ -1718,6 +1733,26 @@ func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdResto
 	return s3Client, bucketName, nil
 }
 
+// GetEtcdRestoreEncryptionKey returns the key to decrypt the backup of a given EtcdRestore, as stored in
+// the secret referenced by the EtcdRestore. If the secret contains no key, nil is returned.
+func GetEtcdRestoreEncryptionKey(ctx context.Context, restore *kubermaticv1.EtcdRestore, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) ([]byte, error) {
+	if restore.Spec.BackupDownloadCredentialsSecret == "" {
+		return nil, fmt.Errorf("BackupDownloadCredentialsSecret not set")
+	}
+
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: restore.Spec.BackupDownloadCredentialsSecret}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get BackupDownloadCredentialsSecret credentials secret %v: %w", restore.Spec.BackupDownloadCredentialsSecret, err)
+	}
+
+	key, ok := secret.Data[EtcdRestoreEncryptionKeyKey]
+	if !ok {
+		return nil, nil
+	}
+
+	return backupencryption.ParseKey(key)
+}
+
 // GetClusterNodeCIDRMaskSizeIPv4 returns effective mask size used to address the nodes within provided IPv4 Pods CIDR.
 func GetClusterNodeCIDRMaskSizeIPv4(cluster *kubermaticv1.Cluster) int32 {
 	if cluster.Spec.ClusterNetwork.NodeCIDRMaskSizeIPv4 != nil {
@@ -53,6 +53,11 @@ func ClusterRole() reconciling.NamedClusterRoleReconcilerFactory {
 						"watch",
 					},
 				},
+				{
+					APIGroups: []string{kubermaticv1.GroupName},
+					Resources: []string{"clusters"},
+					Verbs:     []string{"get"},
+				},
 				{
 					APIGroups: []string{kubermaticv1.GroupName},
 					Resources: []string{"resourcequotas", "resourcequotas/status"},
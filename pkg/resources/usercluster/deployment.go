@@ -169,7 +169,7 @@ func DeploymentReconciler(data userclusterControllerData) reconciling.NamedDeplo
 				args = append(args, "-konnectivity-enabled=true")
 
 				kHost := address.ExternalName
-				if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+				if exposeStrategy := data.Cluster().Spec.ExposeStrategy; exposeStrategy == kubermaticv1.ExposeStrategyTunneling || exposeStrategy == kubermaticv1.ExposeStrategyGatewayAPI {
 					kHost = fmt.Sprintf("%s.%s", resources.KonnectivityProxyServiceName, kHost)
 				}
 				kPort, err := data.GetKonnectivityServerPort()
@@ -233,7 +233,7 @@ func DeploymentReconciler(data userclusterControllerData) reconciling.NamedDeplo
 						return nil, err
 					}
 					mlaEndpoint := net.JoinHostPort(address.ExternalName, fmt.Sprintf("%d", mlaGatewayPort))
-					if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+					if exposeStrategy := data.Cluster().Spec.ExposeStrategy; exposeStrategy == kubermaticv1.ExposeStrategyTunneling || exposeStrategy == kubermaticv1.ExposeStrategyGatewayAPI {
 						mlaEndpoint = resources.MLAGatewaySNIPrefix + mlaEndpoint
 					}
 					args = append(args, "-mla-gateway-url", "https://"+mlaEndpoint)
@@ -81,6 +81,33 @@ func RoleReconciler() (string, reconciling.RoleReconciler) {
 					"update",
 				},
 			},
+			{
+				APIGroups: []string{"apps"},
+				Resources: []string{"deployments"},
+				Verbs:     []string{"get"},
+			},
+			{
+				APIGroups: []string{"kubermatic.k8c.io"},
+				Resources: []string{"compliancereports"},
+				Verbs: []string{
+					"get",
+					"list",
+					"watch",
+					"create",
+					"update",
+					"patch",
+					"delete",
+				},
+			},
+			{
+				APIGroups: []string{"kubermatic.k8c.io"},
+				Resources: []string{"compliancereports/status"},
+				Verbs: []string{
+					"get",
+					"update",
+					"patch",
+				},
+			},
 		}
 		return r, nil
 	}
@@ -30,6 +30,8 @@ import (
 	"github.com/minio/minio-go/v7"
 	"go.uber.org/zap"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/util/backupretention"
 	"k8c.io/kubermatic/v2/pkg/util/s3"
 )
 
@@ -132,6 +134,42 @@ func (u *StoreUploader) DeleteOldBackups(ctx context.Context, bucket, prefix str
 	return nil
 }
 
+// DeleteExpiredBackups deletes revisions of all files of the given prefix which are not
+// retained by any tier of the given retention policy.
+func (u *StoreUploader) DeleteExpiredBackups(ctx context.Context, bucket, prefix string, policy kubermaticv1.EtcdBackupRetention) error {
+	if len(prefix) == 0 {
+		return errors.New("prefix cannot be empty")
+	}
+
+	logger := u.logger.With("bucket", bucket, "prefix", prefix, "hourly", policy.Hourly, "daily", policy.Daily, "weekly", policy.Weekly, "monthly", policy.Monthly)
+
+	logger.Debugw("Listing existing objects")
+
+	listOpts := minio.ListObjectsOptions{
+		Recursive: true,
+		Prefix:    fmt.Sprintf("%s-%s", prefix, prefixSeparator),
+	}
+
+	var existingObjects []minio.ObjectInfo
+	for object := range u.client.ListObjects(ctx, bucket, listOpts) {
+		if object.Err != nil {
+			return object.Err
+		}
+		existingObjects = append(existingObjects, object)
+	}
+
+	logger.Debugw("Done listing bucket", "objects", len(existingObjects))
+
+	for _, object := range u.getExpiredObjects(existingObjects, policy) {
+		logger.Infow("Removing object", "object", object.Key)
+		if err := u.client.RemoveObject(ctx, bucket, object.Key, minio.RemoveObjectOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // DeleteAll deletes all revisions of all files matching the given prefix.
 func (u *StoreUploader) DeleteAll(ctx context.Context, bucket, prefix string) error {
 	if len(prefix) == 0 {
@@ -170,6 +208,22 @@ func (u *StoreUploader) DeleteAll(ctx context.Context, bucket, prefix string) er
 	return nil
 }
 
+func (u *StoreUploader) getExpiredObjects(objects []minio.ObjectInfo, policy kubermaticv1.EtcdBackupRetention) []minio.ObjectInfo {
+	times := make([]time.Time, len(objects))
+	for i, object := range objects {
+		times[i] = object.LastModified
+	}
+
+	var expiredObjects []minio.ObjectInfo
+	for i, tiers := range backupretention.Retain(policy, times) {
+		if len(tiers) == 0 {
+			expiredObjects = append(expiredObjects, objects[i])
+		}
+	}
+
+	return expiredObjects
+}
+
 func (u *StoreUploader) getObjectsToDelete(objects []minio.ObjectInfo, revisionsToKeep int) []minio.ObjectInfo {
 	if len(objects) <= revisionsToKeep {
 		return nil
@@ -22,6 +22,7 @@ import (
 
 	"github.com/minio/minio-go/v7"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/test/diff"
 )
 
@@ -85,3 +86,28 @@ func TestGetObjectsToDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestGetExpiredObjects(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2023, time.March, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	existingObjects := []minio.ObjectInfo{
+		{Key: "day-1", LastModified: day(1)},
+		{Key: "day-2", LastModified: day(2)},
+		{Key: "day-2-later", LastModified: day(2).Add(time.Hour)},
+		{Key: "day-3", LastModified: day(3)},
+	}
+
+	expectedToDelete := []minio.ObjectInfo{
+		{Key: "day-1", LastModified: day(1)},
+		{Key: "day-2", LastModified: day(2)},
+	}
+
+	uploader := StoreUploader{}
+	gotToDelete := uploader.getExpiredObjects(existingObjects, kubermaticv1.EtcdBackupRetention{Daily: 2})
+
+	if !diff.DeepEqual(expectedToDelete, gotToDelete) {
+		t.Fatalf("Objects differ:\n%v", diff.ObjectDiff(expectedToDelete, gotToDelete))
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backupencryption implements the client-side encryption of etcd backups.
+//
+// Snapshots are encrypted with AES-256-GCM in chunks of 64 KiB, so that arbitrarily
+// large snapshots can be processed as a stream. Every chunk is sealed with a nonce made
+// of a random per-file prefix and the chunk counter; the last chunk is additionally
+// marked in its additional data, so that truncated or reordered files are detected.
+//
+// An encrypted file has the following layout:
+//
+//	magic (8 bytes) | nonce prefix (8 bytes) | sealed chunk | sealed chunk | ...
+package backupencryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// KeySize is the size of the AES-256 keys used to encrypt backups.
+	KeySize = 32
+
+	chunkSize       = 64 * 1024
+	noncePrefixSize = 8
+)
+
+// magic identifies encrypted backups. The trailing digit is the version of the format.
+var magic = []byte("KKPENC01")
+
+var (
+	lastChunk     = []byte{1}
+	intermediate  = []byte{0}
+	errTruncated  = errors.New("encrypted backup is truncated")
+	errWrongMagic = errors.New("file is not an encrypted backup")
+)
+
+// ParseKey returns the encryption key stored in a Secret. The key must either be
+// 32 raw bytes or their base64 encoding.
+func ParseKey(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+
+	if decoded, err := base64.StdEncoding.DecodeString(string(data)); err == nil && len(decoded) == KeySize {
+		return decoded, nil
+	}
+	if len(data) == KeySize {
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("encryption key must be %d bytes long (optionally base64-encoded)", KeySize)
+}
+
+// IsEncrypted returns true if r starts with the header of an encrypted backup.
+func IsEncrypted(r io.Reader) (bool, error) {
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return bytes.Equal(header, magic), nil
+}
+
+// Encrypt reads the plaintext from src and writes the encrypted backup to dst.
+func Encrypt(dst io.Writer, src io.Reader, key []byte) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	noncePrefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	if _, err := dst.Write(magic); err != nil {
+		return err
+	}
+	if _, err := dst.Write(noncePrefix); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReaderSize(src, chunkSize+1)
+	buf := make([]byte, chunkSize)
+
+	for counter := uint32(0); ; counter++ {
+		n, err := io.ReadFull(reader, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+
+		// a chunk is the last one if nothing follows it
+		_, peekErr := reader.Peek(1)
+		final := peekErr != nil
+
+		additionalData := intermediate
+		if final {
+			additionalData = lastChunk
+		}
+
+		if _, err := dst.Write(aead.Seal(nil, nonce(noncePrefix, counter), buf[:n], additionalData)); err != nil {
+			return err
+		}
+
+		if final {
+			return nil
+		}
+		if counter == ^uint32(0) {
+			return errors.New("backup is too large to be encrypted")
+		}
+	}
+}
+
+// Decrypt reads an encrypted backup from src and writes the plaintext to dst.
+func Decrypt(dst io.Writer, src io.Reader, key []byte) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReaderSize(src, chunkSize+aead.Overhead()+1)
+
+	encrypted, err := IsEncrypted(reader)
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		return errWrongMagic
+	}
+
+	noncePrefix := make([]byte, noncePrefixSize)
+	if _, err := io.ReadFull(reader, noncePrefix); err != nil {
+		return errTruncated
+	}
+
+	buf := make([]byte, chunkSize+aead.Overhead())
+
+	for counter := uint32(0); ; counter++ {
+		n, err := io.ReadFull(reader, buf)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			if errors.Is(err, io.EOF) {
+				return errTruncated
+			}
+			return err
+		}
+
+		_, peekErr := reader.Peek(1)
+		final := peekErr != nil
+
+		additionalData := intermediate
+		if final {
+			additionalData = lastChunk
+		}
+
+		plaintext, err := aead.Open(buf[:0], nonce(noncePrefix, counter), buf[:n], additionalData)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt backup, either the key is wrong or the backup is corrupted: %w", err)
+		}
+
+		if _, err := dst.Write(plaintext); err != nil {
+			return err
+		}
+
+		if final {
+			return nil
+		}
+	}
+}
+
+// EncryptFile encrypts the file at path in place.
+func EncryptFile(path string, key []byte) error {
+	return transformFile(path, func(dst io.Writer, src io.Reader) error {
+		return Encrypt(dst, src, key)
+	})
+}
+
+// DecryptFile decrypts the encrypted backup at path in place.
+func DecryptFile(path string, key []byte) error {
+	return transformFile(path, func(dst io.Writer, src io.Reader) error {
+		return Decrypt(dst, src, key)
+	})
+}
+
+// IsEncryptedFile returns true if the file at path is an encrypted backup.
+func IsEncryptedFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	return IsEncrypted(f)
+}
+
+// transformFile writes the transformed content of path into a temporary file
+// next to it and replaces path once the transformation succeeded.
+func transformFile(path string, transform func(dst io.Writer, src io.Reader) error) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dst.Name())
+
+	writer := bufio.NewWriter(dst)
+	if err := transform(writer, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(dst.Name(), path)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes long", KeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func nonce(prefix []byte, counter uint32) []byte {
+	n := make([]byte, noncePrefixSize+4)
+	copy(n, prefix)
+	binary.BigEndian.PutUint32(n[noncePrefixSize:], counter)
+
+	return n
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupencryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func genKey(t *testing.T) []byte {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	testCases := []struct {
+		name string
+		size int
+	}{
+		{name: "empty backup", size: 0},
+		{name: "backup smaller than a chunk", size: 1000},
+		{name: "backup of exactly one chunk", size: chunkSize},
+		{name: "backup spanning multiple chunks", size: 3*chunkSize + 17},
+		{name: "backup of exactly multiple chunks", size: 2 * chunkSize},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := genKey(t)
+			plaintext := make([]byte, tc.size)
+			if _, err := rand.Read(plaintext); err != nil {
+				t.Fatalf("failed to generate plaintext: %v", err)
+			}
+
+			encrypted := &bytes.Buffer{}
+			if err := Encrypt(encrypted, bytes.NewReader(plaintext), key); err != nil {
+				t.Fatalf("failed to encrypt: %v", err)
+			}
+
+			if isEncrypted, err := IsEncrypted(bytes.NewReader(encrypted.Bytes())); err != nil || !isEncrypted {
+				t.Errorf("expected encrypted backup to be detected, got %v (err: %v)", isEncrypted, err)
+			}
+			if tc.size > 0 && bytes.Contains(encrypted.Bytes(), plaintext) {
+				t.Error("encrypted backup contains the plaintext")
+			}
+
+			decrypted := &bytes.Buffer{}
+			if err := Decrypt(decrypted, bytes.NewReader(encrypted.Bytes()), key); err != nil {
+				t.Fatalf("failed to decrypt: %v", err)
+			}
+			if !bytes.Equal(decrypted.Bytes(), plaintext) {
+				t.Error("decrypted backup differs from the plaintext")
+			}
+		})
+	}
+}
+
+func TestDecryptRejectsTamperedBackups(t *testing.T) {
+	key := genKey(t)
+	plaintext := bytes.Repeat([]byte("etcd"), chunkSize)
+
+	encrypted := &bytes.Buffer{}
+	if err := Encrypt(encrypted, bytes.NewReader(plaintext), key); err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+	data := encrypted.Bytes()
+	headerSize := len(magic) + noncePrefixSize
+	sealedChunkSize := chunkSize + 16
+
+	testCases := []struct {
+		name string
+		data []byte
+		key  []byte
+	}{
+		{
+			name: "wrong key",
+			data: data,
+			key:  genKey(t),
+		},
+		{
+			name: "modified ciphertext",
+			data: func() []byte {
+				modified := append([]byte{}, data...)
+				modified[headerSize+42] ^= 0xff
+				return modified
+			}(),
+			key: key,
+		},
+		{
+			name: "truncated at a chunk boundary",
+			data: data[:headerSize+2*sealedChunkSize],
+			key:  key,
+		},
+		{
+			name: "truncated header",
+			data: data[:headerSize],
+			key:  key,
+		},
+		{
+			name: "plaintext backup",
+			data: plaintext,
+			key:  key,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := Decrypt(&bytes.Buffer{}, bytes.NewReader(tc.data), tc.key); err == nil {
+				t.Error("expected decryption to fail, but it succeeded")
+			}
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	rawKey := bytes.Repeat([]byte{'k'}, KeySize)
+
+	testCases := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{name: "raw key", data: rawKey},
+		{name: "base64-encoded key", data: []byte(base64.StdEncoding.EncodeToString(rawKey) + "\n")},
+		{name: "key too short", data: []byte("too-short"), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParseKey(tc.data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error=%v, got %v", tc.wantErr, err)
+			}
+			if !tc.wantErr && !bytes.Equal(key, rawKey) {
+				t.Errorf("expected key %q, got %q", rawKey, key)
+			}
+		})
+	}
+}
+
+func TestEncryptFileInPlace(t *testing.T) {
+	key := genKey(t)
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	plaintext := []byte("some etcd snapshot")
+
+	if err := os.WriteFile(path, plaintext, 0600); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+
+	if err := EncryptFile(path, key); err != nil {
+		t.Fatalf("failed to encrypt file: %v", err)
+	}
+	if encrypted, err := IsEncryptedFile(path); err != nil || !encrypted {
+		t.Fatalf("expected file to be encrypted, got %v (err: %v)", encrypted, err)
+	}
+
+	if err := DecryptFile(path, key); err != nil {
+		t.Fatalf("failed to decrypt file: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+	if !bytes.Equal(content, plaintext) {
+		t.Errorf("expected decrypted file to be %q, got %q", plaintext, content)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("failed to list directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected temporary files to be removed, found %d files", len(entries))
+	}
+}
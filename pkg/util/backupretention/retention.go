@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backupretention implements the tiered (grandfather-father-son) retention
+// of etcd backups. It is shared by the etcd backup controller and the s3-storeuploader.
+package backupretention
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+type tier struct {
+	name  kubermaticv1.EtcdBackupRetentionTier
+	count int
+	// period returns an identifier of the period t falls into.
+	period func(t time.Time) string
+}
+
+// Retain returns, for each of the given backup times, the tiers of the policy
+// that retain the backup. Backups that are not retained by any tier get a nil
+// entry and can be deleted.
+func Retain(policy kubermaticv1.EtcdBackupRetention, backups []time.Time) [][]kubermaticv1.EtcdBackupRetentionTier {
+	tiers := []tier{
+		{
+			name:   kubermaticv1.EtcdBackupRetentionTierHourly,
+			count:  policy.Hourly,
+			period: func(t time.Time) string { return t.Format("2006-01-02T15") },
+		},
+		{
+			name:   kubermaticv1.EtcdBackupRetentionTierDaily,
+			count:  policy.Daily,
+			period: func(t time.Time) string { return t.Format("2006-01-02") },
+		},
+		{
+			name:  kubermaticv1.EtcdBackupRetentionTierWeekly,
+			count: policy.Weekly,
+			period: func(t time.Time) string {
+				year, week := t.ISOWeek()
+				return fmt.Sprintf("%d-W%02d", year, week)
+			},
+		},
+		{
+			name:   kubermaticv1.EtcdBackupRetentionTierMonthly,
+			count:  policy.Monthly,
+			period: func(t time.Time) string { return t.Format("2006-01") },
+		},
+	}
+
+	// walk the backups from newest to oldest, so that each tier retains
+	// the newest backup of every period
+	order := make([]int, len(backups))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return backups[order[i]].After(backups[order[j]])
+	})
+
+	retained := make([][]kubermaticv1.EtcdBackupRetentionTier, len(backups))
+
+	for _, t := range tiers {
+		var (
+			kept       int
+			lastPeriod string
+		)
+
+		for _, idx := range order {
+			if kept >= t.count {
+				break
+			}
+
+			period := t.period(backups[idx].UTC())
+			if kept > 0 && period == lastPeriod {
+				continue
+			}
+
+			retained[idx] = append(retained[idx], t.name)
+			lastPeriod = period
+			kept++
+		}
+	}
+
+	return retained
+}
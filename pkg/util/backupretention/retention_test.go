@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupretention
+
+import (
+	"testing"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/test/diff"
+)
+
+const (
+	hourly  = kubermaticv1.EtcdBackupRetentionTierHourly
+	daily   = kubermaticv1.EtcdBackupRetentionTierDaily
+	weekly  = kubermaticv1.EtcdBackupRetentionTierWeekly
+	monthly = kubermaticv1.EtcdBackupRetentionTierMonthly
+)
+
+func TestRetain(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("invalid time %q: %v", value, err)
+		}
+		return parsed
+	}
+
+	testCases := []struct {
+		name     string
+		policy   kubermaticv1.EtcdBackupRetention
+		backups  []time.Time
+		expected [][]kubermaticv1.EtcdBackupRetentionTier
+	}{
+		{
+			name:   "hourly tier keeps the newest backup of each hour",
+			policy: kubermaticv1.EtcdBackupRetention{Hourly: 2},
+			backups: []time.Time{
+				at("2023-03-01T10:00:00Z"),
+				at("2023-03-01T10:30:00Z"),
+				at("2023-03-01T11:00:00Z"),
+				at("2023-03-01T11:30:00Z"),
+			},
+			expected: [][]kubermaticv1.EtcdBackupRetentionTier{nil, {hourly}, nil, {hourly}},
+		},
+		{
+			name:   "tiers retain backups independently",
+			policy: kubermaticv1.EtcdBackupRetention{Hourly: 1, Daily: 2, Monthly: 2},
+			backups: []time.Time{
+				at("2023-01-31T23:00:00Z"),
+				at("2023-02-27T12:00:00Z"),
+				at("2023-02-28T12:00:00Z"),
+				at("2023-02-28T13:00:00Z"),
+			},
+			expected: [][]kubermaticv1.EtcdBackupRetentionTier{{monthly}, {daily}, nil, {hourly, daily, monthly}},
+		},
+		{
+			name:   "weeks start on Monday",
+			policy: kubermaticv1.EtcdBackupRetention{Weekly: 2},
+			backups: []time.Time{
+				// Sunday and Monday
+				at("2023-03-05T12:00:00Z"),
+				at("2023-03-06T12:00:00Z"),
+				at("2023-03-07T12:00:00Z"),
+			},
+			expected: [][]kubermaticv1.EtcdBackupRetentionTier{{weekly}, nil, {weekly}},
+		},
+		{
+			name:   "backups do not need to be sorted",
+			policy: kubermaticv1.EtcdBackupRetention{Daily: 1},
+			backups: []time.Time{
+				at("2023-03-01T12:00:00Z"),
+				at("2023-03-01T08:00:00Z"),
+			},
+			expected: [][]kubermaticv1.EtcdBackupRetentionTier{{daily}, nil},
+		},
+		{
+			name:     "an empty policy retains nothing",
+			policy:   kubermaticv1.EtcdBackupRetention{},
+			backups:  []time.Time{at("2023-03-01T12:00:00Z")},
+			expected: [][]kubermaticv1.EtcdBackupRetentionTier{nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retained := Retain(tc.policy, tc.backups)
+			if !diff.SemanticallyEqual(tc.expected, retained) {
+				t.Fatalf("Unexpected retained backups:\n%v", diff.ObjectDiff(tc.expected, retained))
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ package validation
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/validation/openapi"
@@ -62,6 +64,12 @@ func ValidateApplicationVersions(vs []appskubermaticv1.ApplicationVersion, paren
 
 		allErrs = append(allErrs, validateSource(v.Template.Source, parentFieldPath.Child(curVField+".template.source"))...)
 
+		if v.ValuesSchema != nil {
+			if _, err := openapi.NewValidatorForSchema(v.ValuesSchema.Raw); err != nil {
+				allErrs = append(allErrs, field.Invalid(parentFieldPath.Child(curVField+".valuesSchema"), string(v.ValuesSchema.Raw), fmt.Sprintf("invalid schema: %v", err)))
+			}
+		}
+
 		if _, ok := lookup[v.Version]; ok {
 			allErrs = append(allErrs, field.Duplicate(parentFieldPath.Child(curVField+".Version"), v.Version))
 		} else {
@@ -75,8 +83,15 @@ func ValidateApplicationVersions(vs []appskubermaticv1.ApplicationVersion, paren
 func validateSource(source appskubermaticv1.ApplicationSource, f *field.Path) []*field.Error {
 	allErrs := field.ErrorList{}
 
+	numSources := 0
+	for _, defined := range []bool{source.Helm != nil, source.Git != nil, source.OCI != nil} {
+		if defined {
+			numSources++
+		}
+	}
+
 	switch {
-	case source.Helm != nil && source.Git != nil:
+	case numSources > 1:
 		allErrs = append(allErrs, field.Forbidden(f, "only source type can be provided"))
 	case source.Git != nil:
 		allErrs = append(allErrs, validateGitSource(source.Git, f.Child("git"))...)
@@ -84,6 +99,8 @@ func validateSource(source appskubermaticv1.ApplicationSource, f *field.Path) []
 		if e := validateHelmCredentials(source.Helm.Credentials, f.Child("helm.credentials")); e != nil {
 			allErrs = append(allErrs, e)
 		}
+	case source.OCI != nil:
+		allErrs = append(allErrs, validateOCISource(source.OCI, f.Child("oci"))...)
 
 	default:
 		allErrs = append(allErrs, field.Required(f, "no source provided"))
@@ -108,6 +125,32 @@ func validateHelmCredentials(credential *appskubermaticv1.HelmCredentials, f *fi
 	return nil
 }
 
+func validateOCISource(ociSource *appskubermaticv1.OCISource, f *field.Path) []*field.Error {
+	allErrs := field.ErrorList{}
+
+	if len(ociSource.Tag) == 0 && len(ociSource.Digest) == 0 {
+		allErrs = append(allErrs, field.Required(f, "at least a tag or a digest must be defined"))
+	}
+
+	if strings.Contains(strings.TrimPrefix(ociSource.URL, "oci://"), "@") {
+		allErrs = append(allErrs, field.Invalid(f.Child("url"), ociSource.URL, "url must not contain a digest, use the digest field instead"))
+	}
+
+	if e := validateHelmCredentials(ociSource.Credentials, f.Child("credentials")); e != nil {
+		allErrs = append(allErrs, e)
+	}
+
+	if p := path.Clean(ociSource.Path); p == ".." || strings.HasPrefix(p, "../") {
+		allErrs = append(allErrs, field.Invalid(f.Child("path"), ociSource.Path, "path must not point outside of the artifact"))
+	}
+
+	if ociSource.Verify != nil && (len(ociSource.Verify.PublicKey.Name) == 0 || len(ociSource.Verify.PublicKey.Key) == 0) {
+		allErrs = append(allErrs, field.Required(f.Child("verify.publicKey"), "name and key of the secret holding the public key must be defined"))
+	}
+
+	return allErrs
+}
+
 func validateGitSource(gitSource *appskubermaticv1.GitSource, f *field.Path) []*field.Error {
 	allErrs := field.ErrorList{}
 
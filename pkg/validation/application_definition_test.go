@@ -17,12 +17,14 @@ limitations under the License.
 package validation
 
 import (
+	"strings"
 	"testing"
 
 	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 var (
@@ -52,6 +54,13 @@ func validGitSource() *appskubermaticv1.GitSource {
 	}
 }
 
+func validOCISource() *appskubermaticv1.OCISource {
+	return &appskubermaticv1.OCISource{
+		URL: "oci://localhost:5000/manifests",
+		Tag: "1.0.0",
+	}
+}
+
 func TestValidateApplicationDefinitionSpec(t *testing.T) {
 	tt := map[string]struct {
 		ad        appskubermaticv1.ApplicationDefinition
@@ -258,6 +267,110 @@ func TestValidateApplicationDefinitionSpec(t *testing.T) {
 			},
 			1,
 		},
+		"valid kustomize method": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					s.Method = appskubermaticv1.KustomizeTemplateMethod
+					return *s
+				}(),
+			},
+			0,
+		},
+		"valid oci source with tag": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					s.Versions[0].Template.Source = appskubermaticv1.ApplicationSource{OCI: validOCISource()}
+					return *s
+				}(),
+			},
+			0,
+		},
+		"valid oci source with digest, credentials and signature verification": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					oci := validOCISource()
+					oci.Digest = "sha256:" + strings.Repeat("a", 64)
+					oci.Credentials = &appskubermaticv1.HelmCredentials{RegistryConfigFile: secretKeySelector}
+					oci.Verify = &appskubermaticv1.OCIVerification{PublicKey: *secretKeySelector}
+					s.Versions[0].Template.Source = appskubermaticv1.ApplicationSource{OCI: oci}
+					return *s
+				}(),
+			},
+			0,
+		},
+		"invalid oci source: neither tag nor digest": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					oci := validOCISource()
+					oci.Tag = ""
+					s.Versions[0].Template.Source = appskubermaticv1.ApplicationSource{OCI: oci}
+					return *s
+				}(),
+			},
+			1,
+		},
+		"invalid oci source: path escapes the artifact": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					oci := validOCISource()
+					oci.Path = "charts/../../etc"
+					s.Versions[0].Template.Source = appskubermaticv1.ApplicationSource{OCI: oci}
+					return *s
+				}(),
+			},
+			1,
+		},
+		"invalid oci source: malformed digest": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					oci := validOCISource()
+					oci.Digest = "sha256:1234"
+					s.Versions[0].Template.Source = appskubermaticv1.ApplicationSource{OCI: oci}
+					return *s
+				}(),
+			},
+			1,
+		},
+		"invalid oci source: digest in url": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					oci := validOCISource()
+					oci.URL += "@sha256:" + strings.Repeat("a", 64)
+					s.Versions[0].Template.Source = appskubermaticv1.ApplicationSource{OCI: oci}
+					return *s
+				}(),
+			},
+			1,
+		},
+		"invalid oci source: username without password": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					oci := validOCISource()
+					oci.Credentials = &appskubermaticv1.HelmCredentials{Username: secretKeySelector}
+					s.Versions[0].Template.Source = appskubermaticv1.ApplicationSource{OCI: oci}
+					return *s
+				}(),
+			},
+			1,
+		},
+		"invalid too many sources: oci and git": {
+			appskubermaticv1.ApplicationDefinition{
+				Spec: func() appskubermaticv1.ApplicationDefinitionSpec {
+					s := spec.DeepCopy()
+					s.Versions[0].Template.Source = appskubermaticv1.ApplicationSource{Git: validGitSource(), OCI: validOCISource()}
+					return *s
+				}(),
+			},
+			1,
+		},
 	}
 
 	for name, tc := range tt {
@@ -860,6 +973,18 @@ func TestValidateApplicationVersions(t *testing.T) {
 			},
 			1,
 		},
+		"valid values schema": {
+			[]appskubermaticv1.ApplicationVersion{
+				{Version: "v1", Template: appskubermaticv1.ApplicationTemplate{Source: appskubermaticv1.ApplicationSource{Helm: validHelmSource()}}, ValuesSchema: &runtime.RawExtension{Raw: []byte(`{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object", "properties": {"replicas": {"type": "integer"}}}`)}},
+			},
+			0,
+		},
+		"invalid values schema": {
+			[]appskubermaticv1.ApplicationVersion{
+				{Version: "v1", Template: appskubermaticv1.ApplicationTemplate{Source: appskubermaticv1.ApplicationSource{Helm: validHelmSource()}}, ValuesSchema: &runtime.RawExtension{Raw: []byte(`{"type": "object", "properties": "replicas"}`)}},
+			},
+			1,
+		},
 	}
 	for name, tc := range tt {
 		t.Run(name, func(t *testing.T) {
@@ -20,14 +20,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/cni/cilium"
+	"k8c.io/kubermatic/v2/pkg/validation/openapi"
 
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -42,6 +49,10 @@ func ValidateApplicationInstallationSpec(ctx context.Context, client ctrlruntime
 		allErrs = append(allErrs, field.Invalid(specPath.Child("reconciliationInterval"), spec.ReconciliationInterval.Duration.String(), "should be a positive value, or zero to disable"))
 	}
 
+	if spec.DriftDetection != nil && spec.DriftDetection.Interval.Duration != 0 && spec.DriftDetection.Interval.Duration < time.Minute {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("driftDetection", "interval"), spec.DriftDetection.Interval.Duration.String(), "should be at least 1m, or zero to use the default interval"))
+	}
+
 	// Ensure that the referenced ApplicationDefinition exists only if applicationInstallation is not deleting (removing finalizer raise an UPDATE event)
 	if ai.DeletionTimestamp.IsZero() {
 		ad := &appskubermaticv1.ApplicationDefinition{}
@@ -56,22 +67,136 @@ func ValidateApplicationInstallationSpec(ctx context.Context, client ctrlruntime
 		}
 
 		// Ensure that there is matching version defined in ApplicationDefinition
-		exists := false
+		var appVersion *appskubermaticv1.ApplicationVersion
 		desiredVersion := spec.ApplicationRef.Version
-		for _, version := range ad.Spec.Versions {
+		for i, version := range ad.Spec.Versions {
 			if version.Version == desiredVersion {
-				exists = true
+				appVersion = &ad.Spec.Versions[i]
 			}
 		}
 
-		if !exists {
+		if appVersion == nil {
 			allErrs = append(allErrs, field.NotFound(specPath.Child("applicationRef", "version"), spec.ApplicationRef.Version))
+		} else if appVersion.ValuesSchema != nil {
+			allErrs = append(allErrs, validateValuesAgainstSchema(spec.Values, appVersion.ValuesSchema, specPath.Child("values"))...)
+		}
+
+		// rollback relies on the Helm release history.
+		if ad.Spec.Method != appskubermaticv1.HelmTemplateMethod {
+			if spec.RollbackTo != "" {
+				allErrs = append(allErrs, field.Forbidden(specPath.Child("rollbackTo"), fmt.Sprintf("rollback is not supported by template method '%s'", ad.Spec.Method)))
+			}
+			if spec.AutoRollback != nil {
+				allErrs = append(allErrs, field.Forbidden(specPath.Child("autoRollback"), fmt.Sprintf("rollback is not supported by template method '%s'", ad.Spec.Method)))
+			}
 		}
 	}
 	allErrs = append(allErrs, ValidateDeployOpts(spec.DeployOptions, specPath.Child("deployOptions"))...)
 	return allErrs
 }
 
+// validateValuesAgainstSchema validates the values of an ApplicationInstallation against the values schema of its
+// ApplicationVersion.
+func validateValuesAgainstSchema(rawValues runtime.RawExtension, schema *runtime.RawExtension, f *field.Path) field.ErrorList {
+	validator, err := openapi.NewValidatorForSchema(schema.Raw)
+	if err != nil {
+		return field.ErrorList{field.InternalError(f, fmt.Errorf("invalid values schema in ApplicationDefinition: %w", err))}
+	}
+
+	values := map[string]interface{}{}
+	if len(rawValues.Raw) > 0 {
+		if err := json.Unmarshal(rawValues.Raw, &values); err != nil {
+			return field.ErrorList{field.Invalid(f, string(rawValues.Raw), fmt.Sprintf("unable to unmarshal values: %s", err))}
+		}
+	}
+
+	// the validator does not return errors in a stable order.
+	errs := validation.ValidateCustomResource(f, values, validator)
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+	return errs
+}
+
+// ValidateApplicationInstallationDependencies validates the dependencies of the ApplicationInstallation. Dependencies must
+// be unique and must not introduce a cycle in the dependency graph of the ApplicationInstallations of the namespace.
+// Dependencies that do not exist (yet) are allowed, the installation just waits for them.
+func ValidateApplicationInstallationDependencies(ctx context.Context, userClient ctrlruntimeclient.Reader, ai appskubermaticv1.ApplicationInstallation) field.ErrorList {
+	dependsOnPath := field.NewPath("spec", "dependsOn")
+	allErrs := field.ErrorList{}
+
+	// removing finalizer raises an UPDATE event, it must not be blocked.
+	if len(ai.Spec.DependsOn) == 0 || !ai.DeletionTimestamp.IsZero() {
+		return allErrs
+	}
+
+	seen := sets.New[string]()
+	for i, name := range ai.Spec.DependsOn {
+		switch {
+		case name == ai.Name:
+			allErrs = append(allErrs, field.Invalid(dependsOnPath.Index(i), name, "an application can not depend on itself"))
+		case seen.Has(name):
+			allErrs = append(allErrs, field.Duplicate(dependsOnPath.Index(i), name))
+		default:
+			for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(name, false) {
+				allErrs = append(allErrs, field.Invalid(dependsOnPath.Index(i), name, msg))
+			}
+		}
+		seen.Insert(name)
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	appList := &appskubermaticv1.ApplicationInstallationList{}
+	if err := userClient.List(ctx, appList, ctrlruntimeclient.InNamespace(ai.Namespace)); err != nil {
+		return append(allErrs, field.InternalError(dependsOnPath, err))
+	}
+
+	dependencies := map[string][]string{}
+	for _, app := range appList.Items {
+		dependencies[app.Name] = app.Spec.DependsOn
+	}
+	dependencies[ai.Name] = ai.Spec.DependsOn
+
+	if cycle := findDependencyCycle(dependencies, ai.Name); cycle != nil {
+		allErrs = append(allErrs, field.Forbidden(dependsOnPath, fmt.Sprintf("dependency cycle detected: %s", strings.Join(cycle, " -> "))))
+	}
+
+	return allErrs
+}
+
+// findDependencyCycle returns the path of a cycle going through start in the dependency graph, or nil if there is none.
+// The graph without start is assumed to be acyclic as every change of dependencies is validated.
+func findDependencyCycle(dependencies map[string][]string, start string) []string {
+	visited := sets.New[string]()
+	path := []string{}
+
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		path = append(path, name)
+		for _, dependency := range dependencies[name] {
+			if dependency == start {
+				path = append(path, start)
+				return true
+			}
+			if !visited.Has(dependency) {
+				visited.Insert(dependency)
+				if visit(dependency) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	if visit(start) {
+		return path
+	}
+	return nil
+}
+
 func ValidateDeployOpts(deployOpts *appskubermaticv1.DeployOptions, f *field.Path) []*field.Error {
 	allErrs := field.ErrorList{}
 	if deployOpts != nil && deployOpts.Helm != nil {
@@ -85,6 +210,9 @@ func ValidateDeployOpts(deployOpts *appskubermaticv1.DeployOptions, f *field.Pat
 		if !deployOpts.Helm.Wait && deployOpts.Helm.Timeout.Duration > 0 {
 			allErrs = append(allErrs, field.Forbidden(f.Child("helm"), "if timeout is defined then wait must be true"))
 		}
+		if deployOpts.Helm.MaxHistory < 0 || deployOpts.Helm.MaxHistory > appskubermaticv1.ApplicationInstallationMaxHistory {
+			allErrs = append(allErrs, field.Invalid(f.Child("helm", "maxHistory"), deployOpts.Helm.MaxHistory, fmt.Sprintf("must be between 1 and %d", appskubermaticv1.ApplicationInstallationMaxHistory)))
+		}
 	}
 	return allErrs
 }
@@ -120,6 +248,11 @@ func ValidateApplicationInstallationUpdate(ctx context.Context, client ctrlrunti
 		specPath.Child("applicationRef", "name"),
 	)...)
 
+	// Validate .Spec.RollbackTo references a revision of the history
+	if newAI.Spec.RollbackTo != "" && newAI.Spec.RollbackTo != oldAI.Spec.RollbackTo && oldAI.GetRevision(newAI.Spec.RollbackTo) == nil {
+		allErrs = append(allErrs, field.NotFound(specPath.Child("rollbackTo"), newAI.Spec.RollbackTo))
+	}
+
 	// Validate managed-by label immutability
 	allErrs = append(allErrs, validateImmutableLabel(
 		newAI.Labels,
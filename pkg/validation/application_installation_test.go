@@ -47,10 +47,22 @@ func init() {
 // TestValidateApplicationInstallationSpec tests the validation for ApplicationInstallation creation.
 func TestValidateApplicationInstallationSpec(t *testing.T) {
 	ad := getApplicationDefinition(defaultAppName)
+	manifestAD := getApplicationDefinition("manifest-app")
+	manifestAD.Spec.Method = appskubermaticv1.ManifestTemplateMethod
+	schemaAD := getApplicationDefinition("schema-app")
+	schemaAD.Spec.Versions[0].ValuesSchema = &runtime.RawExtension{Raw: []byte(`{
+		"type": "object",
+		"required": ["image"],
+		"additionalProperties": false,
+		"properties": {
+			"replicas": {"type": "integer", "minimum": 1},
+			"image": {"type": "object", "properties": {"tag": {"type": "string"}}}
+		}
+	}`)}
 	fakeClient := fakectrlruntimeclient.
 		NewClientBuilder().
 		WithScheme(testScheme).
-		WithObjects(ad).
+		WithObjects(ad, manifestAD, schemaAD).
 		Build()
 
 	ai := getApplicationInstallation(defaultAppName, defaultAppName, defaultAppVersion, nil)
@@ -195,6 +207,81 @@ func TestValidateApplicationInstallationSpec(t *testing.T) {
 				}(),
 			}, expectedError: `[spec.reconciliationInterval: Invalid value: "-10ns": should be a positive value, or zero to disable]`,
 		},
+		{
+			name: "Create ApplicationInstallation Success - AutoRollback with helm method",
+			ai: &appskubermaticv1.ApplicationInstallation{
+				Spec: func() appskubermaticv1.ApplicationInstallationSpec {
+					spec := ai.Spec.DeepCopy()
+					spec.AutoRollback = &appskubermaticv1.AutoRollbackPolicy{AfterFailures: 2}
+					return *spec
+				}(),
+			}, expectedError: `[]`,
+		},
+		{
+			name: "Create ApplicationInstallation Failure - RollbackTo and AutoRollback are not supported by manifest method",
+			ai: &appskubermaticv1.ApplicationInstallation{
+				Spec: func() appskubermaticv1.ApplicationInstallationSpec {
+					spec := ai.Spec.DeepCopy()
+					spec.ApplicationRef.Name = "manifest-app"
+					spec.RollbackTo = "1.2.3-0123abcd"
+					spec.AutoRollback = &appskubermaticv1.AutoRollbackPolicy{AfterFailures: 2}
+					return *spec
+				}(),
+			}, expectedError: `[spec.rollbackTo: Forbidden: rollback is not supported by template method 'manifest' spec.autoRollback: Forbidden: rollback is not supported by template method 'manifest']`,
+		},
+		{
+			name: "Create ApplicationInstallation Success - values match the values schema",
+			ai: &appskubermaticv1.ApplicationInstallation{
+				Spec: func() appskubermaticv1.ApplicationInstallationSpec {
+					spec := ai.Spec.DeepCopy()
+					spec.ApplicationRef.Name = "schema-app"
+					spec.Values = runtime.RawExtension{Raw: []byte(`{"replicas": 2, "image": {"tag": "1.0.0"}}`)}
+					return *spec
+				}(),
+			}, expectedError: `[]`,
+		},
+		{
+			name: "Create ApplicationInstallation Failure - values do not match the values schema",
+			ai: &appskubermaticv1.ApplicationInstallation{
+				Spec: func() appskubermaticv1.ApplicationInstallationSpec {
+					spec := ai.Spec.DeepCopy()
+					spec.ApplicationRef.Name = "schema-app"
+					spec.Values = runtime.RawExtension{Raw: []byte(`{"replicas": 0, "image": {"tag": 1}}`)}
+					return *spec
+				}(),
+			}, expectedError: `[spec.values.image.tag: Invalid value: "number": image.tag in body must be of type string: "number" spec.values.replicas: Invalid value: 0: replicas in body should be greater than or equal to 1]`,
+		},
+		{
+			name: "Create ApplicationInstallation Failure - values with unknown and missing fields",
+			ai: &appskubermaticv1.ApplicationInstallation{
+				Spec: func() appskubermaticv1.ApplicationInstallationSpec {
+					spec := ai.Spec.DeepCopy()
+					spec.ApplicationRef.Name = "schema-app"
+					spec.Values = runtime.RawExtension{Raw: []byte(`{"replicaz": 2}`)}
+					return *spec
+				}(),
+			}, expectedError: `[spec.values: Invalid value: "replicaz": .replicaz in body is a forbidden property spec.values.image: Required value]`,
+		},
+		{
+			name: "Create ApplicationInstallation Success - DriftDetection with default interval",
+			ai: &appskubermaticv1.ApplicationInstallation{
+				Spec: func() appskubermaticv1.ApplicationInstallationSpec {
+					spec := ai.Spec.DeepCopy()
+					spec.DriftDetection = &appskubermaticv1.DriftDetection{Policy: appskubermaticv1.DriftPolicyCorrect}
+					return *spec
+				}(),
+			}, expectedError: `[]`,
+		},
+		{
+			name: "Create ApplicationInstallation Failure - DriftDetection interval less than 1 minute",
+			ai: &appskubermaticv1.ApplicationInstallation{
+				Spec: func() appskubermaticv1.ApplicationInstallationSpec {
+					spec := ai.Spec.DeepCopy()
+					spec.DriftDetection = &appskubermaticv1.DriftDetection{Policy: appskubermaticv1.DriftPolicyReport, Interval: metav1.Duration{Duration: 30 * time.Second}}
+					return *spec
+				}(),
+			}, expectedError: `[spec.driftDetection.interval: Invalid value: "30s": should be at least 1m, or zero to use the default interval]`,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -223,6 +310,12 @@ func TestValidateApplicationInstallationUpdate(t *testing.T) {
 	ai := getApplicationInstallation(defaultAppName, defaultAppName, defaultAppVersion, nil)
 
 	aiVersionDoesExist := getApplicationInstallation(defaultAppName, defaultAppName, "0.0.0-does-not-exist", nil)
+
+	aiWithHistory := getApplicationInstallation(defaultAppName, defaultAppName, defaultAppSecondaryVersion, nil)
+	aiWithHistory.Status.History = []appskubermaticv1.ApplicationInstallationRevision{
+		{Name: defaultAppSecondaryVersion + "-44136fa3", ApplicationVersion: defaultAppSecondaryVersion, HelmReleaseRevision: 2},
+		{Name: defaultAppVersion + "-44136fa3", ApplicationVersion: defaultAppVersion, HelmReleaseRevision: 1},
+	}
 	testCases := []struct {
 		name          string
 		ai            *appskubermaticv1.ApplicationInstallation
@@ -378,6 +471,30 @@ func TestValidateApplicationInstallationUpdate(t *testing.T) {
 			},
 			expectedError: `[spec.values: Invalid value: "INVALID": unable to unmarshal values: invalid character 'I' looking for beginning of value]`,
 		},
+		{
+			name: "Update ApplicationInstallation Success - RollbackTo revision in history",
+			ai:   aiWithHistory,
+			updatedAI: &appskubermaticv1.ApplicationInstallation{
+				Spec: func() appskubermaticv1.ApplicationInstallationSpec {
+					spec := aiWithHistory.Spec.DeepCopy()
+					spec.RollbackTo = defaultAppVersion + "-44136fa3"
+					return *spec
+				}(),
+			},
+			expectedError: "[]",
+		},
+		{
+			name: "Update ApplicationInstallation Failure - RollbackTo revision not in history",
+			ai:   aiWithHistory,
+			updatedAI: &appskubermaticv1.ApplicationInstallation{
+				Spec: func() appskubermaticv1.ApplicationInstallationSpec {
+					spec := aiWithHistory.Spec.DeepCopy()
+					spec.RollbackTo = "0.0.1-44136fa3"
+					return *spec
+				}(),
+			},
+			expectedError: `[spec.rollbackTo: Not found: "0.0.1-44136fa3"]`,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -393,6 +510,86 @@ func TestValidateApplicationInstallationUpdate(t *testing.T) {
 	}
 }
 
+// TestValidateApplicationInstallationDependencies tests the validation of the dependencies of an ApplicationInstallation.
+func TestValidateApplicationInstallationDependencies(t *testing.T) {
+	withDependencies := func(name string, dependencies ...string) *appskubermaticv1.ApplicationInstallation {
+		ai := getApplicationInstallation(name, defaultAppName, defaultAppVersion, nil)
+		ai.Spec.DependsOn = dependencies
+		return ai
+	}
+
+	// existing dependency graph: ingress -> issuer -> cert-manager
+	fakeClient := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(
+			withDependencies("cert-manager"),
+			withDependencies("issuer", "cert-manager"),
+			withDependencies("ingress", "issuer"),
+		).
+		Build()
+
+	testCases := []struct {
+		name          string
+		ai            *appskubermaticv1.ApplicationInstallation
+		expectedError string
+	}{
+		{
+			name:          "No dependencies",
+			ai:            withDependencies("cert-manager"),
+			expectedError: "[]",
+		},
+		{
+			name:          "Dependencies on existing applications",
+			ai:            withDependencies("app", "ingress", "cert-manager"),
+			expectedError: "[]",
+		},
+		{
+			name:          "Dependency on a not (yet) existing application",
+			ai:            withDependencies("app", "does-not-exist"),
+			expectedError: "[]",
+		},
+		{
+			name:          "Failure - self dependency",
+			ai:            withDependencies("app", "app"),
+			expectedError: `[spec.dependsOn[0]: Invalid value: "app": an application can not depend on itself]`,
+		},
+		{
+			name:          "Failure - duplicated dependency",
+			ai:            withDependencies("app", "issuer", "issuer"),
+			expectedError: `[spec.dependsOn[1]: Duplicate value: "issuer"]`,
+		},
+		{
+			name:          "Failure - direct cycle",
+			ai:            withDependencies("cert-manager", "issuer"),
+			expectedError: "[spec.dependsOn: Forbidden: dependency cycle detected: cert-manager -> issuer -> cert-manager]",
+		},
+		{
+			name:          "Failure - transitive cycle",
+			ai:            withDependencies("cert-manager", "ingress"),
+			expectedError: "[spec.dependsOn: Forbidden: dependency cycle detected: cert-manager -> ingress -> issuer -> cert-manager]",
+		},
+		{
+			name: "Deleting ApplicationInstallation with cycle is not blocked",
+			ai: func() *appskubermaticv1.ApplicationInstallation {
+				ai := withDependencies("cert-manager", "ingress")
+				ai.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				return ai
+			}(),
+			expectedError: "[]",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ValidateApplicationInstallationDependencies(context.Background(), fakeClient, *testCase.ai)
+			if fmt.Sprint(err) != testCase.expectedError {
+				t.Fatalf("expected error to be %s but got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
 func getApplicationDefinition(name string) *appskubermaticv1.ApplicationDefinition {
 	return &appskubermaticv1.ApplicationDefinition{
 		ObjectMeta: metav1.ObjectMeta{
@@ -400,6 +597,7 @@ func getApplicationDefinition(name string) *appskubermaticv1.ApplicationDefiniti
 		},
 		Spec: appskubermaticv1.ApplicationDefinitionSpec{
 			Description: "Description",
+			Method:      appskubermaticv1.HelmTemplateMethod,
 			Versions: []appskubermaticv1.ApplicationVersion{
 				{
 					Version: defaultAppVersion,
@@ -109,6 +109,11 @@ func ValidateClusterSpec(spec *kubermaticv1.ClusterSpec, dc *kubermaticv1.Datace
 		allErrs = append(allErrs, field.Forbidden(parentFieldPath.Child("TunnelingAgentIP"), "Tunneling agent IP can be configured only for Tunneling Expose strategy"))
 	}
 
+	// OpenVPN cannot be routed based on SNI, so the GatewayAPI expose strategy requires Konnectivity.
+	if spec.ExposeStrategy == kubermaticv1.ExposeStrategyGatewayAPI && (spec.ClusterNetwork.KonnectivityEnabled == nil || !*spec.ClusterNetwork.KonnectivityEnabled) {
+		allErrs = append(allErrs, field.Forbidden(parentFieldPath.Child("exposeStrategy"), "GatewayAPI expose strategy requires Konnectivity to be enabled"))
+	}
+
 	// External CCM is not supported for all providers and all Kubernetes versions.
 	if spec.Features[kubermaticv1.ClusterFeatureExternalCloudProvider] {
 		if !resources.ExternalCloudControllerFeatureSupported(dc, &spec.Cloud, spec.Version, versionManager.GetIncompatibilities()...) {
@@ -163,6 +168,16 @@ func ValidateClusterSpec(spec *kubermaticv1.ClusterSpec, dc *kubermaticv1.Datace
 		allErrs = append(allErrs, errs...)
 	}
 
+	if spec.ForceDeletion != nil && spec.ForceDeletion.After.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(parentFieldPath.Child("forceDeletion", "after"), spec.ForceDeletion.After.Duration.String(), "must be a positive duration"))
+	}
+
+	if spec.ComplianceScanning != nil && spec.ComplianceScanning.Schedule != "" {
+		if _, err := GetCronExpressionParser().Parse(spec.ComplianceScanning.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(parentFieldPath.Child("complianceScanning", "schedule"), spec.ComplianceScanning.Schedule, fmt.Sprintf("invalid cron expression: %v", err)))
+		}
+	}
+
 	return allErrs
 }
 
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateSourceCluster validates that the backup of sourceCluster can be
+// cloned into cluster. Both clusters must belong to the same project, because
+// the clone receives all data of the source cluster, including its Secrets.
+// Additionally both clusters must use the same cloud provider and the same
+// minor Kubernetes version, as the restored objects would not be usable otherwise.
+func ValidateSourceCluster(cluster, sourceCluster *kubermaticv1.Cluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("spec", "sourceCluster")
+
+	project := cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+	if project == "" || project != sourceCluster.Labels[kubermaticv1.ProjectIDLabelKey] {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "source cluster must belong to the same project as the cluster"))
+		// do not reveal anything else about clusters of other projects
+		return allErrs
+	}
+
+	provider, err := kubermaticv1helper.ClusterCloudProviderName(cluster.Spec.Cloud)
+	if err != nil {
+		allErrs = append(allErrs, field.InternalError(fldPath, fmt.Errorf("failed to determine cloud provider of cluster: %w", err)))
+	}
+
+	sourceProvider, err := kubermaticv1helper.ClusterCloudProviderName(sourceCluster.Spec.Cloud)
+	if err != nil {
+		allErrs = append(allErrs, field.InternalError(fldPath, fmt.Errorf("failed to determine cloud provider of source cluster: %w", err)))
+	}
+
+	if len(allErrs) == 0 && provider != sourceProvider {
+		allErrs = append(allErrs, field.Invalid(fldPath, sourceCluster.Name, fmt.Sprintf("source cluster uses cloud provider %q, but cluster uses %q", sourceProvider, provider)))
+	}
+
+	if version, sourceVersion := cluster.Spec.Version.MajorMinor(), sourceCluster.Spec.Version.MajorMinor(); version != sourceVersion {
+		allErrs = append(allErrs, field.Invalid(fldPath, sourceCluster.Name, fmt.Sprintf("source cluster runs Kubernetes %s, but cluster runs %s", sourceVersion, version)))
+	}
+
+	return allErrs
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package openapi
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"k8c.io/kubermatic/v2/pkg/crd"
@@ -60,6 +61,26 @@ func NewValidatorForCRD(crd *apiextensionsv1.CustomResourceDefinition, version s
 	return sv, nil
 }
 
+// NewValidatorForSchema creates a new validator based on the supplied JSON encoded OpenAPI v3 / JSON schema. Keywords
+// that are not supported by OpenAPI v3 are ignored.
+func NewValidatorForSchema(schema []byte) (*validate.SchemaValidator, error) {
+	props := &apiextensionsv1.JSONSchemaProps{}
+	if err := json.Unmarshal(schema, props); err != nil {
+		return nil, fmt.Errorf("failed to decode schema: %w", err)
+	}
+
+	internalProps := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(props, internalProps, nil); err != nil {
+		return nil, err
+	}
+
+	sv, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: internalProps})
+	if err != nil {
+		return nil, err
+	}
+	return sv, nil
+}
+
 func NewValidatorForObject(obj runtime.Object) (*validate.SchemaValidator, error) {
 	c, err := crd.CRDForObject(obj)
 	if err != nil {
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// validator for validating Kubermatic Addon CRD.
+type validator struct {
+	seedGetter       provider.SeedGetter
+	seedClientGetter provider.SeedClientGetter
+}
+
+// NewValidator returns a new Addon validator.
+func NewValidator(seedGetter provider.SeedGetter, seedClientGetter provider.SeedClientGetter) *validator {
+	return &validator{
+		seedGetter:       seedGetter,
+		seedClientGetter: seedClientGetter,
+	}
+}
+
+var _ admission.CustomValidator = &validator{}
+
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	addon, ok := obj.(*kubermaticv1.Addon)
+	if !ok {
+		return errors.New("object is not an Addon")
+	}
+
+	return v.validateVariables(ctx, addon)
+}
+
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldAddon, ok := oldObj.(*kubermaticv1.Addon)
+	if !ok {
+		return errors.New("old object is not an Addon")
+	}
+
+	newAddon, ok := newObj.(*kubermaticv1.Addon)
+	if !ok {
+		return errors.New("new object is not an Addon")
+	}
+
+	// Removing finalizers or updating labels must not be blocked by variables that
+	// became invalid because the variables schema in the AddonConfig was changed.
+	if !newAddon.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	if oldAddon.Spec.Name == newAddon.Spec.Name && equality.Semantic.DeepEqual(oldAddon.Spec.Variables, newAddon.Spec.Variables) {
+		return nil
+	}
+
+	return v.validateVariables(ctx, newAddon)
+}
+
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *validator) validateVariables(ctx context.Context, addon *kubermaticv1.Addon) error {
+	seed, err := v.seedGetter()
+	if err != nil {
+		return fmt.Errorf("failed to get current Seed: %w", err)
+	}
+	if seed == nil {
+		return errors.New("webhook not configured for a Seed cluster, cannot validate Addon resources")
+	}
+
+	client, err := v.seedClientGetter(seed)
+	if err != nil {
+		return fmt.Errorf("failed to get Seed client: %w", err)
+	}
+
+	addonConfig := &kubermaticv1.AddonConfig{}
+	if err := client.Get(ctx, types.NamespacedName{Name: addon.Spec.Name}, addonConfig); err != nil {
+		// the AddonConfig CRD might not be installed on the seed yet
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get AddonConfig: %w", err)
+	}
+
+	schema := addonConfig.Spec.VariablesSchema
+	if schema == nil || len(schema.Raw) == 0 {
+		return nil
+	}
+
+	variablesPath := field.NewPath("spec", "variables")
+
+	variables, err := addonutils.ParseVariables(addon.Spec.Variables)
+	if err != nil {
+		return field.ErrorList{field.Invalid(variablesPath, string(addon.Spec.Variables.Raw), err.Error())}.ToAggregate()
+	}
+
+	// Defaults are only applied by the addon controller, after merging the global addon variables, and are
+	// not stored in the Addon. Required variables that have a default must not be rejected though.
+	if err := addonutils.DefaultVariables(schema, variables); err != nil {
+		return field.ErrorList{field.InternalError(variablesPath, fmt.Errorf("failed to default variables: %w", err))}.ToAggregate()
+	}
+
+	return addonutils.ValidateVariables(schema, variables, variablesPath).ToAggregate()
+}
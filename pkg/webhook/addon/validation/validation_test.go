@@ -0,0 +1,186 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var (
+	testScheme = runtime.NewScheme()
+)
+
+func init() {
+	_ = kubermaticv1.AddToScheme(testScheme)
+}
+
+func genAddon(name string, variables string) *kubermaticv1.Addon {
+	addon := &kubermaticv1.Addon{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "cluster-xyz",
+		},
+		Spec: kubermaticv1.AddonSpec{
+			Name: name,
+		},
+	}
+
+	if variables != "" {
+		addon.Spec.Variables = &runtime.RawExtension{Raw: []byte(variables)}
+	}
+
+	return addon
+}
+
+func TestValidator(t *testing.T) {
+	addonConfig := &kubermaticv1.AddonConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-addon",
+		},
+		Spec: kubermaticv1.AddonConfigSpec{
+			VariablesSchema: &runtime.RawExtension{
+				Raw: []byte(`{"type":"object","properties":{"replicas":{"type":"integer","minimum":1}},"additionalProperties":false}`),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		op          admissionv1.Operation
+		addon       *kubermaticv1.Addon
+		oldAddon    *kubermaticv1.Addon
+		objects     []ctrlruntimeclient.Object
+		expectError bool
+	}{
+		{
+			name:    "addon without AddonConfig is allowed",
+			op:      admissionv1.Create,
+			addon:   genAddon("other-addon", `{"anything":true}`),
+			objects: []ctrlruntimeclient.Object{addonConfig},
+		},
+		{
+			name:    "valid variables are allowed",
+			op:      admissionv1.Create,
+			addon:   genAddon("my-addon", `{"replicas":2}`),
+			objects: []ctrlruntimeclient.Object{addonConfig},
+		},
+		{
+			name:        "variables of the wrong type are rejected",
+			op:          admissionv1.Create,
+			addon:       genAddon("my-addon", `{"replicas":"2"}`),
+			objects:     []ctrlruntimeclient.Object{addonConfig},
+			expectError: true,
+		},
+		{
+			name:        "unknown variables are rejected",
+			op:          admissionv1.Create,
+			addon:       genAddon("my-addon", `{"replica":2}`),
+			objects:     []ctrlruntimeclient.Object{addonConfig},
+			expectError: true,
+		},
+		{
+			name:        "variables that are not an object are rejected",
+			op:          admissionv1.Create,
+			addon:       genAddon("my-addon", `[1, 2]`),
+			objects:     []ctrlruntimeclient.Object{addonConfig},
+			expectError: true,
+		},
+		{
+			name:        "invalid update of variables is rejected",
+			op:          admissionv1.Update,
+			oldAddon:    genAddon("my-addon", `{"replicas":2}`),
+			addon:       genAddon("my-addon", `{"replicas":0}`),
+			objects:     []ctrlruntimeclient.Object{addonConfig},
+			expectError: true,
+		},
+		{
+			name:  "missing required variable with a default is allowed",
+			op:    admissionv1.Create,
+			addon: genAddon("my-addon", `{}`),
+			objects: []ctrlruntimeclient.Object{&kubermaticv1.AddonConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-addon",
+				},
+				Spec: kubermaticv1.AddonConfigSpec{
+					VariablesSchema: &runtime.RawExtension{
+						Raw: []byte(`{"type":"object","required":["replicas"],"properties":{"replicas":{"type":"integer","default":2}}}`),
+					},
+				},
+			}},
+		},
+		{
+			name:     "update without changed variables is allowed",
+			op:       admissionv1.Update,
+			oldAddon: genAddon("my-addon", `{"replicas":0}`),
+			addon: func() *kubermaticv1.Addon {
+				addon := genAddon("my-addon", `{"replicas":0}`)
+				addon.Finalizers = []string{"test"}
+				return addon
+			}(),
+			objects: []ctrlruntimeclient.Object{addonConfig},
+		},
+		{
+			name:    "deletion is always allowed",
+			op:      admissionv1.Delete,
+			addon:   genAddon("my-addon", `{"replicas":0}`),
+			objects: []ctrlruntimeclient.Object{addonConfig},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			seedClient := ctrlruntimefakeclient.
+				NewClientBuilder().
+				WithScheme(testScheme).
+				WithObjects(tc.objects...).
+				Build()
+
+			validator := NewValidator(
+				func() (*kubermaticv1.Seed, error) {
+					return &kubermaticv1.Seed{}, nil
+				},
+				func(seed *kubermaticv1.Seed) (ctrlruntimeclient.Client, error) {
+					return seedClient, nil
+				})
+
+			ctx := context.Background()
+			var err error
+
+			switch tc.op {
+			case admissionv1.Create:
+				err = validator.ValidateCreate(ctx, tc.addon)
+			case admissionv1.Update:
+				err = validator.ValidateUpdate(ctx, tc.oldAddon, tc.addon)
+			case admissionv1.Delete:
+				err = validator.ValidateDelete(ctx, tc.addon)
+			}
+
+			if tc.expectError != (err != nil) {
+				t.Fatalf("Expected error: %v, got %v", tc.expectError, err)
+			}
+		})
+	}
+}
@@ -39,13 +39,16 @@ type AdmissionHandler struct {
 	log     logr.Logger
 	decoder *admission.Decoder
 
-	client ctrlruntimeclient.Client
+	client     ctrlruntimeclient.Client
+	userClient ctrlruntimeclient.Reader
 }
 
-// NewAdmissionHandler returns a new validation AdmissionHandler.
-func NewAdmissionHandler(client ctrlruntimeclient.Client) *AdmissionHandler {
+// NewAdmissionHandler returns a new validation AdmissionHandler. client is used to get the ApplicationDefinitions and
+// userClient to get the other ApplicationInstallations of the user cluster.
+func NewAdmissionHandler(client ctrlruntimeclient.Client, userClient ctrlruntimeclient.Reader) *AdmissionHandler {
 	return &AdmissionHandler{
-		client: client,
+		client:     client,
+		userClient: userClient,
 	}
 }
 
@@ -74,6 +77,7 @@ func (h *AdmissionHandler) Handle(ctx context.Context, req webhook.AdmissionRequ
 			return webhook.Errored(http.StatusBadRequest, err)
 		}
 		allErrs = append(allErrs, validation.ValidateApplicationInstallationSpec(ctx, h.client, *ad)...)
+		allErrs = append(allErrs, validation.ValidateApplicationInstallationDependencies(ctx, h.userClient, *ad)...)
 
 	case admissionv1.Update:
 		if err := h.decoder.Decode(req, ad); err != nil {
@@ -83,6 +87,7 @@ func (h *AdmissionHandler) Handle(ctx context.Context, req webhook.AdmissionRequ
 			return webhook.Errored(http.StatusBadRequest, err)
 		}
 		allErrs = append(allErrs, validation.ValidateApplicationInstallationUpdate(ctx, h.client, *ad, *oldAD)...)
+		allErrs = append(allErrs, validation.ValidateApplicationInstallationDependencies(ctx, h.userClient, *ad)...)
 
 	case admissionv1.Delete:
 		// NOP we always allow delete operations
@@ -144,9 +144,10 @@ func TestValidateApplicationInstallation(t *testing.T) {
 			}
 
 			handler := AdmissionHandler{
-				log:     logr.Discard(),
-				decoder: d,
-				client:  fakeClient,
+				log:        logr.Discard(),
+				decoder:    d,
+				client:     fakeClient,
+				userClient: fakeClient,
 			}
 
 			if res := handler.Handle(context.Background(), tt.req); res.Allowed != tt.wantAllowed {
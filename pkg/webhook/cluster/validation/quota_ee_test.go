@@ -0,0 +1,157 @@
+//go:build ee
+
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func genProjectQuota(projectID string, quota *int64, used *int64) *kubermaticv1.ResourceQuota {
+	return &kubermaticv1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "project-" + projectID,
+			Labels: map[string]string{
+				kubermaticv1.ResourceQuotaSubjectNameLabelKey: projectID,
+				kubermaticv1.ResourceQuotaSubjectKindLabelKey: kubermaticv1.ProjectSubjectKind,
+			},
+		},
+		Spec: kubermaticv1.ResourceQuotaSpec{
+			Subject: kubermaticv1.Subject{
+				Name: projectID,
+				Kind: kubermaticv1.ProjectSubjectKind,
+			},
+			Quota: kubermaticv1.ResourceDetails{Clusters: quota},
+		},
+		Status: kubermaticv1.ResourceQuotaStatus{
+			GlobalUsage: kubermaticv1.ResourceDetails{Clusters: used},
+		},
+	}
+}
+
+func genProjectCluster(name, projectID string) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: projectID},
+		},
+	}
+}
+
+func TestValidateQuota(t *testing.T) {
+	testCases := []struct {
+		name        string
+		objects     []ctrlruntimeclient.Object
+		wantAllowed bool
+	}{
+		{
+			name:        "project without resource quota",
+			wantAllowed: true,
+		},
+		{
+			name:        "resource quota without cluster quota",
+			objects:     []ctrlruntimeclient.Object{genProjectQuota("abcd1234", nil, pointer.Int64(10))},
+			wantAllowed: true,
+		},
+		{
+			name:        "cluster quota not reached",
+			objects:     []ctrlruntimeclient.Object{genProjectQuota("abcd1234", pointer.Int64(2), pointer.Int64(1))},
+			wantAllowed: true,
+		},
+		{
+			name:        "cluster quota reached",
+			objects:     []ctrlruntimeclient.Object{genProjectQuota("abcd1234", pointer.Int64(2), pointer.Int64(2))},
+			wantAllowed: false,
+		},
+		{
+			name: "cluster quota reached by clusters that are not in the usage yet",
+			objects: []ctrlruntimeclient.Object{
+				genProjectQuota("abcd1234", pointer.Int64(2), pointer.Int64(0)),
+				genProjectCluster("c1", "abcd1234"),
+				genProjectCluster("c2", "abcd1234"),
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "cluster quota reached by clusters on other seeds",
+			objects: []ctrlruntimeclient.Object{
+				func() *kubermaticv1.ResourceQuota {
+					quota := genProjectQuota("abcd1234", pointer.Int64(2), pointer.Int64(2))
+					quota.Status.LocalUsage.Clusters = pointer.Int64(1)
+					return quota
+				}(),
+				genProjectCluster("c1", "abcd1234"),
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "deleted clusters no longer count against the quota",
+			objects: []ctrlruntimeclient.Object{
+				func() *kubermaticv1.ResourceQuota {
+					quota := genProjectQuota("abcd1234", pointer.Int64(2), pointer.Int64(2))
+					quota.Status.LocalUsage.Clusters = pointer.Int64(2)
+					return quota
+				}(),
+				genProjectCluster("c1", "abcd1234"),
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "clusters of other projects do not count against the quota",
+			objects: []ctrlruntimeclient.Object{
+				genProjectQuota("abcd1234", pointer.Int64(2), pointer.Int64(0)),
+				genProjectCluster("c1", "abcd1234"),
+				genProjectCluster("c2", "wxyz0987"),
+			},
+			wantAllowed: true,
+		},
+		{
+			name:        "cluster quota of other project reached",
+			objects:     []ctrlruntimeclient.Object{genProjectQuota("wxyz0987", pointer.Int64(2), pointer.Int64(2))},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &validator{
+				client: ctrlruntimefakeclient.NewClientBuilder().WithScheme(testScheme).WithObjects(tc.objects...).Build(),
+			}
+
+			cluster := &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "foo",
+					Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: "abcd1234"},
+				},
+			}
+
+			err := v.validateQuota(context.Background(), cluster)
+			if allowed := err == nil; allowed != tc.wantAllowed {
+				t.Errorf("Expected allowed=%v, but got error %v", tc.wantAllowed, err)
+			}
+		})
+	}
+}
@@ -97,6 +97,8 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) erro
 
 	if err := v.validateProjectRelation(ctx, cluster, nil); err != nil {
 		errs = append(errs, err)
+	} else if err := v.validateQuota(ctx, cluster); err != nil {
+		errs = append(errs, err)
 	}
 
 	return errs.ToAggregate()
@@ -208,3 +210,15 @@ func (v *validator) validateProjectRelation(ctx context.Context, cluster *kuberm
 
 	return nil
 }
+
+// validateQuota ensures that the project of a new cluster has not yet reached its cluster quota.
+func (v *validator) validateQuota(ctx context.Context, cluster *kubermaticv1.Cluster) *field.Error {
+	label := kubermaticv1.ProjectIDLabelKey
+	fieldPath := field.NewPath("metadata", "labels").Key(label)
+
+	if err := validateClusterQuota(ctx, v.client, cluster.Labels[label]); err != nil {
+		return field.Forbidden(fieldPath, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build ee
+
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/ee/validation/resourcequota"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func validateClusterQuota(ctx context.Context, client ctrlruntimeclient.Client, projectID string) error {
+	resourceQuota, err := resourcequota.GetProjectQuota(ctx, client, projectID)
+	if err != nil || resourceQuota == nil {
+		return err
+	}
+
+	// the global usage is aggregated asynchronously, so count the clusters of this seed live
+	// to not allow bursts of new clusters
+	clusters := &kubermaticv1.ClusterList{}
+	if err := client.List(ctx, clusters, ctrlruntimeclient.MatchingLabels{kubermaticv1.ProjectIDLabelKey: projectID}); err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	return resourcequota.ValidateLiveCount(resourceQuota, resourcequota.Clusters, resourceQuota.Status.LocalUsage, int64(len(clusters.Items)), 1)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	etcdrestorevalidation "k8c.io/kubermatic/v2/pkg/validation/etcdrestore"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// validator for validating Kubermatic EtcdRestore CRD.
+type validator struct {
+	seedGetter       provider.SeedGetter
+	seedClientGetter provider.SeedClientGetter
+}
+
+// NewValidator returns a new EtcdRestore validator.
+func NewValidator(seedGetter provider.SeedGetter, seedClientGetter provider.SeedClientGetter) *validator {
+	return &validator{
+		seedGetter:       seedGetter,
+		seedClientGetter: seedClientGetter,
+	}
+}
+
+var _ admission.CustomValidator = &validator{}
+
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	restore, ok := obj.(*kubermaticv1.EtcdRestore)
+	if !ok {
+		return errors.New("object is not an EtcdRestore")
+	}
+
+	return v.validateSourceCluster(ctx, restore)
+}
+
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldRestore, ok := oldObj.(*kubermaticv1.EtcdRestore)
+	if !ok {
+		return errors.New("old object is not an EtcdRestore")
+	}
+
+	newRestore, ok := newObj.(*kubermaticv1.EtcdRestore)
+	if !ok {
+		return errors.New("new object is not an EtcdRestore")
+	}
+
+	// removing finalizers must not be blocked once the source cluster is gone
+	if !newRestore.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	if oldRestore.Spec.Cluster.Name == newRestore.Spec.Cluster.Name && oldRestore.Spec.SourceCluster == newRestore.Spec.SourceCluster {
+		return nil
+	}
+
+	return v.validateSourceCluster(ctx, newRestore)
+}
+
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *validator) validateSourceCluster(ctx context.Context, restore *kubermaticv1.EtcdRestore) error {
+	if !restore.IsClone() {
+		return nil
+	}
+
+	seed, err := v.seedGetter()
+	if err != nil {
+		return fmt.Errorf("failed to get current Seed: %w", err)
+	}
+	if seed == nil {
+		return errors.New("webhook not configured for a Seed cluster, cannot validate EtcdRestore resources")
+	}
+
+	client, err := v.seedClientGetter(seed)
+	if err != nil {
+		return fmt.Errorf("failed to get Seed client: %w", err)
+	}
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := client.Get(ctx, types.NamespacedName{Name: restore.Spec.Cluster.Name}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.NotFound(field.NewPath("spec", "cluster", "name"), restore.Spec.Cluster.Name)
+		}
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	sourceCluster := &kubermaticv1.Cluster{}
+	if err := client.Get(ctx, types.NamespacedName{Name: restore.Spec.SourceCluster}, sourceCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.NotFound(field.NewPath("spec", "sourceCluster"), restore.Spec.SourceCluster)
+		}
+		return fmt.Errorf("failed to get source cluster: %w", err)
+	}
+
+	return etcdrestorevalidation.ValidateSourceCluster(cluster, sourceCluster).ToAggregate()
+}
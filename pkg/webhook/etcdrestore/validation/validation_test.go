@@ -0,0 +1,189 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var (
+	testScheme = runtime.NewScheme()
+)
+
+func init() {
+	_ = kubermaticv1.AddToScheme(testScheme)
+}
+
+func genCluster(name, project, version string, modify func(*kubermaticv1.Cluster)) *kubermaticv1.Cluster {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				kubermaticv1.ProjectIDLabelKey: project,
+			},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			Version: *semver.NewSemverOrDie(version),
+			Cloud: kubermaticv1.CloudSpec{
+				ProviderName: string(kubermaticv1.HetznerCloudProvider),
+				Hetzner:      &kubermaticv1.HetznerCloudSpec{},
+			},
+		},
+	}
+
+	if modify != nil {
+		modify(cluster)
+	}
+
+	return cluster
+}
+
+func genRestore(cluster, sourceCluster string) *kubermaticv1.EtcdRestore {
+	return &kubermaticv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "restore",
+			Namespace: "cluster-" + cluster,
+		},
+		Spec: kubermaticv1.EtcdRestoreSpec{
+			Name:          "restore",
+			Cluster:       corev1.ObjectReference{Name: cluster},
+			BackupName:    "backup",
+			SourceCluster: sourceCluster,
+		},
+	}
+}
+
+func TestValidator(t *testing.T) {
+	target := genCluster("target", "project-a", "1.26.1", nil)
+
+	testCases := []struct {
+		name        string
+		op          admissionv1.Operation
+		restore     *kubermaticv1.EtcdRestore
+		oldRestore  *kubermaticv1.EtcdRestore
+		objects     []ctrlruntimeclient.Object
+		expectError bool
+	}{
+		{
+			name:    "restore without source cluster is allowed",
+			op:      admissionv1.Create,
+			restore: genRestore("target", ""),
+			objects: []ctrlruntimeclient.Object{target},
+		},
+		{
+			name:    "clone from a cluster in the same project is allowed",
+			op:      admissionv1.Create,
+			restore: genRestore("target", "source"),
+			objects: []ctrlruntimeclient.Object{target, genCluster("source", "project-a", "1.26.3", nil)},
+		},
+		{
+			name:        "clone from a cluster in another project is rejected",
+			op:          admissionv1.Create,
+			restore:     genRestore("target", "source"),
+			objects:     []ctrlruntimeclient.Object{target, genCluster("source", "project-b", "1.26.1", nil)},
+			expectError: true,
+		},
+		{
+			name:        "clone from a missing cluster is rejected",
+			op:          admissionv1.Create,
+			restore:     genRestore("target", "source"),
+			objects:     []ctrlruntimeclient.Object{target},
+			expectError: true,
+		},
+		{
+			name:        "clone from a cluster with another minor version is rejected",
+			op:          admissionv1.Create,
+			restore:     genRestore("target", "source"),
+			objects:     []ctrlruntimeclient.Object{target, genCluster("source", "project-a", "1.25.6", nil)},
+			expectError: true,
+		},
+		{
+			name:    "clone from a cluster with another cloud provider is rejected",
+			op:      admissionv1.Create,
+			restore: genRestore("target", "source"),
+			objects: []ctrlruntimeclient.Object{target, genCluster("source", "project-a", "1.26.1", func(c *kubermaticv1.Cluster) {
+				c.Spec.Cloud = kubermaticv1.CloudSpec{
+					ProviderName: string(kubermaticv1.DigitaloceanCloudProvider),
+					Digitalocean: &kubermaticv1.DigitaloceanCloudSpec{},
+				}
+			})},
+			expectError: true,
+		},
+		{
+			name:        "changing the source cluster to another project is rejected",
+			op:          admissionv1.Update,
+			oldRestore:  genRestore("target", "source"),
+			restore:     genRestore("target", "other"),
+			objects:     []ctrlruntimeclient.Object{target, genCluster("source", "project-a", "1.26.1", nil), genCluster("other", "project-b", "1.26.1", nil)},
+			expectError: true,
+		},
+		{
+			name:       "update without changed clusters is allowed",
+			op:         admissionv1.Update,
+			oldRestore: genRestore("target", "source"),
+			restore: func() *kubermaticv1.EtcdRestore {
+				restore := genRestore("target", "source")
+				restore.Finalizers = []string{"test"}
+				return restore
+			}(),
+			objects: []ctrlruntimeclient.Object{target},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			seedClient := ctrlruntimefakeclient.
+				NewClientBuilder().
+				WithScheme(testScheme).
+				WithObjects(tc.objects...).
+				Build()
+
+			validator := NewValidator(
+				func() (*kubermaticv1.Seed, error) {
+					return &kubermaticv1.Seed{}, nil
+				},
+				func(seed *kubermaticv1.Seed) (ctrlruntimeclient.Client, error) {
+					return seedClient, nil
+				})
+
+			ctx := context.Background()
+			var err error
+
+			switch tc.op {
+			case admissionv1.Create:
+				err = validator.ValidateCreate(ctx, tc.restore)
+			case admissionv1.Update:
+				err = validator.ValidateUpdate(ctx, tc.oldRestore, tc.restore)
+			}
+
+			if tc.expectError != (err != nil) {
+				t.Fatalf("Expected error: %v, got %v", tc.expectError, err)
+			}
+		})
+	}
+}
@@ -50,6 +50,54 @@ func subnetCIDRSliceToStringSlice(s []kubermaticv1.SubnetCIDR) []string {
 	return convertedSlice
 }
 
+func getReservationAddresses(reservations []kubermaticv1.IPAMPoolReservation) []string {
+	addresses := make([]string, len(reservations))
+
+	for i, reservation := range reservations {
+		addresses[i] = reservation.Address
+	}
+
+	return addresses
+}
+
+// getDualStackSettings returns the settings for the second IP family of a dual-stack
+// datacenter pool in the same form as the settings of the first IP family.
+func getDualStackSettings(dcConfig kubermaticv1.IPAMPoolDatacenterSettings) kubermaticv1.IPAMPoolDatacenterSettings {
+	return kubermaticv1.IPAMPoolDatacenterSettings{
+		Type:             dcConfig.Type,
+		PoolCIDR:         dcConfig.DualStack.PoolCIDR,
+		AllocationPrefix: dcConfig.DualStack.AllocationPrefix,
+		ExcludePrefixes:  dcConfig.DualStack.ExcludePrefixes,
+		AllocationRange:  dcConfig.DualStack.AllocationRange,
+		ExcludeRanges:    dcConfig.DualStack.ExcludeRanges,
+	}
+}
+
+// splitReservations separates the reservations that belong to the given pool subnet from all others.
+func splitReservations(reservations []kubermaticv1.IPAMPoolReservation, poolSubnet *net.IPNet) ([]kubermaticv1.IPAMPoolReservation, []kubermaticv1.IPAMPoolReservation) {
+	var inPool, others []kubermaticv1.IPAMPoolReservation
+
+	for _, reservation := range reservations {
+		address := strings.Split(strings.Split(reservation.Address, "-")[0], "/")[0]
+		if ip := net.ParseIP(address); ip != nil && poolSubnet.Contains(ip) {
+			inPool = append(inPool, reservation)
+		} else {
+			others = append(others, reservation)
+		}
+	}
+
+	return inPool, others
+}
+
+func normalizeSubnetCIDR(subnetCIDR string) string {
+	_, subnet, err := net.ParseCIDR(subnetCIDR)
+	if err != nil {
+		return subnetCIDR
+	}
+
+	return subnet.String()
+}
+
 func addressRangesConflict(firstAddressRanges []string, secondAddressRanges []string) bool {
 	firstAddressRangesIPs := map[string]struct{}{}
 
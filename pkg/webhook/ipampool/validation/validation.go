@@ -28,7 +28,9 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/provider"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -95,6 +97,37 @@ func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.O
 			)
 		}
 
+		addedExclusions = append(addedExclusions, getSliceAdditions(
+			getReservationAddresses(dcOldConfig.Reservations),
+			getReservationAddresses(dcNewConfig.Reservations),
+		)...)
+
+		if dcOldConfig.DualStack != nil {
+			if dcNewConfig.DualStack == nil {
+				return errors.New("it's not allowed to remove the dual-stack configuration for a datacenter")
+			}
+
+			if dcOldConfig.DualStack.PoolCIDR != dcNewConfig.DualStack.PoolCIDR {
+				return errors.New("it's not allowed to update the dual-stack pool CIDR for a datacenter")
+			}
+
+			switch dcOldConfig.Type {
+			case kubermaticv1.IPAMPoolAllocationTypeRange:
+				if dcOldConfig.DualStack.AllocationRange != dcNewConfig.DualStack.AllocationRange {
+					return errors.New("it's not allowed to update the dual-stack allocation range for a datacenter")
+				}
+				addedExclusions = append(addedExclusions, getSliceAdditions(dcOldConfig.DualStack.ExcludeRanges, dcNewConfig.DualStack.ExcludeRanges)...)
+			case kubermaticv1.IPAMPoolAllocationTypePrefix:
+				if dcOldConfig.DualStack.AllocationPrefix != dcNewConfig.DualStack.AllocationPrefix {
+					return errors.New("it's not allowed to update the dual-stack allocation prefix for a datacenter")
+				}
+				addedExclusions = append(addedExclusions, getSliceAdditions(
+					subnetCIDRSliceToStringSlice(dcOldConfig.DualStack.ExcludePrefixes),
+					subnetCIDRSliceToStringSlice(dcNewConfig.DualStack.ExcludePrefixes),
+				)...)
+			}
+		}
+
 		if err := v.checkExclusionsNotAllocated(ctx, addedExclusions, oldIPAMPool.Name, dc, dcOldConfig.Type); err != nil {
 			return err
 		}
@@ -114,55 +147,135 @@ func (v *validator) validate(ctx context.Context, obj runtime.Object) error {
 		return errors.New("object is not a IPAMPool")
 	}
 
+	if ipamPool.Spec.ProjectSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(ipamPool.Spec.ProjectSelector); err != nil {
+			return fmt.Errorf("invalid project selector: %w", err)
+		}
+	}
+
+	if ipamPool.Spec.ProjectQuota != nil && *ipamPool.Spec.ProjectQuota < 0 {
+		return errors.New("project quota cannot be negative")
+	}
+
+	for projectID, quota := range ipamPool.Spec.ProjectQuotas {
+		if quota < 0 {
+			return fmt.Errorf("quota for project \"%s\" cannot be negative", projectID)
+		}
+	}
+
 	for _, dcConfig := range ipamPool.Spec.Datacenters {
-		_, poolSubnet, err := net.ParseCIDR(string(dcConfig.PoolCIDR))
-		if err != nil {
+		if err := validateReservationNames(dcConfig.Reservations); err != nil {
 			return err
 		}
-		poolPrefix, bits := poolSubnet.Mask.Size()
 
-		switch dcConfig.Type {
-		case kubermaticv1.IPAMPoolAllocationTypeRange:
-			if dcConfig.AllocationRange <= 0 {
-				return errors.New("allocation range should be greater than zero")
-			}
+		reservations := dcConfig.Reservations
+		if dcConfig.DualStack != nil {
+			dualStackConfig := getDualStackSettings(dcConfig)
 
-			numberOfPoolSubnetIPsFloat64 := math.Pow(2, float64(bits-poolPrefix))
-			numberOfPoolSubnetIPs := int(numberOfPoolSubnetIPsFloat64)
-			if float64(numberOfPoolSubnetIPs) != numberOfPoolSubnetIPsFloat64 {
-				return errors.New("the pool is too big to be processed")
+			_, poolSubnet, err := net.ParseCIDR(string(dcConfig.PoolCIDR))
+			if err != nil {
+				return err
+			}
+			_, dualStackPoolSubnet, err := net.ParseCIDR(string(dualStackConfig.PoolCIDR))
+			if err != nil {
+				return err
+			}
+			if (poolSubnet.IP.To4() == nil) == (dualStackPoolSubnet.IP.To4() == nil) {
+				return errors.New("the dual-stack pool CIDR must be of the other IP family than the pool CIDR")
 			}
 
-			if bits-poolPrefix > 12 {
-				return errors.New("pool prefix is too low for range allocation type")
+			// split the reservations by IP family
+			reservations, dualStackConfig.Reservations = splitReservations(dcConfig.Reservations, poolSubnet)
+			if err := validateDatacenterPool(dualStackConfig); err != nil {
+				return fmt.Errorf("invalid dual-stack configuration: %w", err)
 			}
+		}
+
+		dcConfig.Reservations = reservations
+		if err := validateDatacenterPool(dcConfig); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateDatacenterPool(dcConfig kubermaticv1.IPAMPoolDatacenterSettings) error {
+	_, poolSubnet, err := net.ParseCIDR(string(dcConfig.PoolCIDR))
+	if err != nil {
+		return err
+	}
+	poolPrefix, bits := poolSubnet.Mask.Size()
+
+	switch dcConfig.Type {
+	case kubermaticv1.IPAMPoolAllocationTypeRange:
+		if dcConfig.AllocationRange <= 0 {
+			return errors.New("allocation range should be greater than zero")
+		}
 
-			if dcConfig.AllocationRange > numberOfPoolSubnetIPs {
-				return errors.New("allocation range cannot be greater than the pool subnet possible number of IP addresses")
+		numberOfPoolSubnetIPsFloat64 := math.Pow(2, float64(bits-poolPrefix))
+		numberOfPoolSubnetIPs := int(numberOfPoolSubnetIPsFloat64)
+		if float64(numberOfPoolSubnetIPs) != numberOfPoolSubnetIPsFloat64 {
+			return errors.New("the pool is too big to be processed")
+		}
+
+		if bits-poolPrefix > 12 {
+			return errors.New("pool prefix is too low for range allocation type")
+		}
+
+		if dcConfig.AllocationRange > numberOfPoolSubnetIPs {
+			return errors.New("allocation range cannot be greater than the pool subnet possible number of IP addresses")
+		}
+
+		// exclusions and reservations must be part of the pool CIDR, which also limits their size
+		// to the size of the pool
+		for _, rangeToExclude := range dcConfig.ExcludeRanges {
+			if err := validateRange(rangeToExclude); err != nil {
+				return err
+			}
+			if !rangeInSubnet(rangeToExclude, poolSubnet) {
+				return fmt.Errorf("range to exclude \"%s\" is not part of the pool CIDR", rangeToExclude)
 			}
+		}
 
-			for _, rangeToExclude := range dcConfig.ExcludeRanges {
-				if err := validateRange(rangeToExclude); err != nil {
-					return err
-				}
+		for _, reservation := range dcConfig.Reservations {
+			if err := validateRange(reservation.Address); err != nil {
+				return fmt.Errorf("invalid address for reservation \"%s\": %w", reservation.Name, err)
 			}
-		case kubermaticv1.IPAMPoolAllocationTypePrefix:
-			if dcConfig.AllocationPrefix < poolPrefix {
-				return errors.New("allocation prefix cannot be smaller than the pool subnet mask size")
+			if !rangeInSubnet(reservation.Address, poolSubnet) {
+				return fmt.Errorf("reservation \"%s\" is not part of the pool CIDR", reservation.Name)
 			}
-			if dcConfig.AllocationPrefix > bits {
-				return errors.New("invalid allocation prefix for IP version")
+		}
+	case kubermaticv1.IPAMPoolAllocationTypePrefix:
+		if dcConfig.AllocationPrefix < poolPrefix {
+			return errors.New("allocation prefix cannot be smaller than the pool subnet mask size")
+		}
+		if dcConfig.AllocationPrefix > bits {
+			return errors.New("invalid allocation prefix for IP version")
+		}
+
+		for _, subnetCIDRToExclude := range dcConfig.ExcludePrefixes {
+			_, subnet, err := net.ParseCIDR(string(subnetCIDRToExclude))
+			if err != nil {
+				return fmt.Errorf("invalid CIDR for subnet to exclude: %w", err)
 			}
+			subnetPrefix, _ := subnet.Mask.Size()
+			if dcConfig.AllocationPrefix != subnetPrefix {
+				return fmt.Errorf("invalid length for subnet to exclude \"%s\": must be the same as the pool allocation prefix (%d)", subnetCIDRToExclude, subnetPrefix)
+			}
+		}
 
-			for _, subnetCIDRToExclude := range dcConfig.ExcludePrefixes {
-				_, subnet, err := net.ParseCIDR(string(subnetCIDRToExclude))
-				if err != nil {
-					return fmt.Errorf("invalid CIDR for subnet to exclude: %w", err)
-				}
-				subnetPrefix, _ := subnet.Mask.Size()
-				if dcConfig.AllocationPrefix != subnetPrefix {
-					return fmt.Errorf("invalid length for subnet to exclude \"%s\": must be the same as the pool allocation prefix (%d)", subnetCIDRToExclude, subnetPrefix)
-				}
+		for _, reservation := range dcConfig.Reservations {
+			subnetIP, subnet, err := net.ParseCIDR(reservation.Address)
+			if err != nil {
+				return fmt.Errorf("invalid CIDR for reservation \"%s\": %w", reservation.Name, err)
+			}
+			subnetPrefix, _ := subnet.Mask.Size()
+			if dcConfig.AllocationPrefix != subnetPrefix {
+				return fmt.Errorf("invalid length for reservation \"%s\": must be the same as the pool allocation prefix (%d)", reservation.Name, dcConfig.AllocationPrefix)
+			}
+			if !poolSubnet.Contains(subnetIP) {
+				return fmt.Errorf("reservation \"%s\" is not part of the pool CIDR", reservation.Name)
 			}
 		}
 	}
@@ -170,6 +283,36 @@ func (v *validator) validate(ctx context.Context, obj runtime.Object) error {
 	return nil
 }
 
+func validateReservationNames(reservations []kubermaticv1.IPAMPoolReservation) error {
+	names := map[string]struct{}{}
+
+	for _, reservation := range reservations {
+		if reservation.Name == "" {
+			return errors.New("reservation name cannot be empty")
+		}
+		// addons like metallb create objects named after the reservation
+		if errs := validation.IsDNS1123Label(reservation.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid reservation name \"%s\": %s", reservation.Name, strings.Join(errs, ", "))
+		}
+		if _, exists := names[reservation.Name]; exists {
+			return fmt.Errorf("duplicate reservation name \"%s\"", reservation.Name)
+		}
+		names[reservation.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// rangeInSubnet returns true if the first and the last IP of a valid range are part of the subnet.
+func rangeInSubnet(r string, subnet *net.IPNet) bool {
+	for _, ip := range strings.Split(r, "-") {
+		if !subnet.Contains(net.ParseIP(ip)) {
+			return false
+		}
+	}
+	return true
+}
+
 func validateRange(r string) error {
 	splittedRange := strings.Split(r, "-")
 	if len(splittedRange) != 1 && len(splittedRange) != 2 {
@@ -238,15 +381,26 @@ func (v *validator) checkExclusionsNotAllocated(ctx context.Context, exclusions
 
 		errExclusionConflict := fmt.Errorf("failed to add exclusion: there is an conflicted allocation in IPAM pool \"%s\" and datacenter \"%s\"", ipamPoolName, dc)
 
+		// exclusions of both IP families of dual-stack pools are checked against both allocations,
+		// as addresses of different IP families never conflict
+		allocatedAddresses := ipamAllocation.Spec.Addresses
+		allocatedCIDRs := []string{string(ipamAllocation.Spec.CIDR)}
+		if ipamAllocation.Spec.DualStack != nil {
+			allocatedAddresses = append(allocatedAddresses, ipamAllocation.Spec.DualStack.Addresses...)
+			allocatedCIDRs = append(allocatedCIDRs, string(ipamAllocation.Spec.DualStack.CIDR))
+		}
+
 		switch allocationType {
 		case kubermaticv1.IPAMPoolAllocationTypeRange:
-			if addressRangesConflict(ipamAllocation.Spec.Addresses, exclusions) {
+			if addressRangesConflict(allocatedAddresses, exclusions) {
 				return errExclusionConflict
 			}
 		case kubermaticv1.IPAMPoolAllocationTypePrefix:
 			for _, exclusion := range exclusions {
-				if string(ipamAllocation.Spec.CIDR) == exclusion {
-					return errExclusionConflict
+				for _, allocatedCIDR := range allocatedCIDRs {
+					if normalizeSubnetCIDR(allocatedCIDR) == normalizeSubnetCIDR(exclusion) {
+						return errExclusionConflict
+					}
 				}
 			}
 		}
@@ -579,6 +579,68 @@ func TestValidator(t *testing.T) {
 			},
 			expectedError: fmt.Errorf("invalid range order for \"%s\"", "192.168.1.10-192.168.1.9"),
 		},
+		{
+			name: "exclude range: outside of the pool CIDR",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:            "range",
+							PoolCIDR:        "192.168.1.0/28",
+							AllocationRange: 8,
+							ExcludeRanges:   []string{"192.168.1.10-192.168.1.20"},
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("range to exclude \"%s\" is not part of the pool CIDR", "192.168.1.10-192.168.1.20"),
+		},
+		{
+			name: "exclude range: huge IPv6 range",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:            "range",
+							PoolCIDR:        "fd00::/120",
+							AllocationRange: 8,
+							ExcludeRanges:   []string{"fd00::-fdff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"},
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("range to exclude \"%s\" is not part of the pool CIDR", "fd00::-fdff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+		},
+		{
+			name: "reservation: range ends outside of the pool CIDR",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:            "range",
+							PoolCIDR:        "192.168.1.0/28",
+							AllocationRange: 8,
+							Reservations: []kubermaticv1.IPAMPoolReservation{
+								{Name: "vips", Address: "192.168.1.14-192.168.1.17"},
+							},
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("reservation \"%s\" is not part of the pool CIDR", "vips"),
+		},
 		{
 			name: "exclude prefix: invalid CIDR",
 			op:   admissionv1.Create,
@@ -1007,6 +1069,314 @@ func TestValidator(t *testing.T) {
 			},
 			expectedError: fmt.Errorf("failed to add exclusion: there is an conflicted allocation in IPAM pool \"%s\" and datacenter \"%s\"", "test-pool", "dc"),
 		},
+		{
+			name: "negative project quota",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:             "prefix",
+							PoolCIDR:         "192.168.1.0/28",
+							AllocationPrefix: 29,
+						},
+					},
+					ProjectQuotas: map[string]int{"project-1": -1},
+				},
+			},
+			expectedError: fmt.Errorf("quota for project \"%s\" cannot be negative", "project-1"),
+		},
+		{
+			name: "reservation: duplicate name",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:            "range",
+							PoolCIDR:        "192.168.1.0/28",
+							AllocationRange: 8,
+							Reservations: []kubermaticv1.IPAMPoolReservation{
+								{Name: "vip", Address: "192.168.1.1"},
+								{Name: "vip", Address: "192.168.1.2"},
+							},
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("duplicate reservation name \"%s\"", "vip"),
+		},
+		{
+			name: "reservation: invalid name",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:            "range",
+							PoolCIDR:        "192.168.1.0/28",
+							AllocationRange: 8,
+							Reservations: []kubermaticv1.IPAMPoolReservation{
+								{Name: "Ingress_VIP", Address: "192.168.1.1"},
+							},
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("invalid reservation name \"%s\": %s", "Ingress_VIP", "a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')"),
+		},
+		{
+			name: "reservation: invalid length for subnet",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:             "prefix",
+							PoolCIDR:         "192.168.1.0/28",
+							AllocationPrefix: 29,
+							Reservations: []kubermaticv1.IPAMPoolReservation{
+								{Name: "gateway", Address: "192.168.1.8/30"},
+							},
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("invalid length for reservation \"%s\": must be the same as the pool allocation prefix (%d)", "gateway", 29),
+		},
+		{
+			name: "added range reservation: conflict with allocation",
+			op:   admissionv1.Update,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:            "range",
+							PoolCIDR:        "192.168.1.0/28",
+							AllocationRange: 4,
+							Reservations: []kubermaticv1.IPAMPoolReservation{
+								{Name: "vip", Address: "192.168.1.2"},
+							},
+						},
+					},
+				},
+			},
+			oldIPAMPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:            "range",
+							PoolCIDR:        "192.168.1.0/28",
+							AllocationRange: 4,
+						},
+					},
+				},
+			},
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.IPAMAllocation{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-pool",
+						Namespace: "cluster-test",
+					},
+					Spec: kubermaticv1.IPAMAllocationSpec{
+						Type:      "range",
+						DC:        "dc",
+						Addresses: []string{"192.168.1.0-192.168.1.3"},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("failed to add exclusion: there is an conflicted allocation in IPAM pool \"%s\" and datacenter \"%s\"", "test-pool", "dc"),
+		},
+		{
+			name: "dual-stack: same IP family",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:            "range",
+							PoolCIDR:        "192.168.1.0/28",
+							AllocationRange: 8,
+							DualStack: &kubermaticv1.IPAMPoolDualStackSettings{
+								PoolCIDR:        "192.168.2.0/28",
+								AllocationRange: 8,
+							},
+						},
+					},
+				},
+			},
+			expectedError: errors.New("the dual-stack pool CIDR must be of the other IP family than the pool CIDR"),
+		},
+		{
+			name: "dual-stack: allowed range creation with reservations of both IP families",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:            "range",
+							PoolCIDR:        "192.168.1.0/28",
+							AllocationRange: 8,
+							DualStack: &kubermaticv1.IPAMPoolDualStackSettings{
+								PoolCIDR:        "fd00::/120",
+								AllocationRange: 8,
+								ExcludeRanges:   []string{"fd00::1-fd00::a"},
+							},
+							Reservations: []kubermaticv1.IPAMPoolReservation{
+								{Name: "ipv4-vip", Address: "192.168.1.1"},
+								{Name: "ipv6-vip", Address: "fd00::ff"},
+							},
+						},
+					},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "dual-stack: reservation outside of both pool CIDRs",
+			op:   admissionv1.Create,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:             "prefix",
+							PoolCIDR:         "192.168.0.0/16",
+							AllocationPrefix: 24,
+							DualStack: &kubermaticv1.IPAMPoolDualStackSettings{
+								PoolCIDR:         "fd00::/56",
+								AllocationPrefix: 64,
+							},
+							Reservations: []kubermaticv1.IPAMPoolReservation{
+								{Name: "storage", Address: "fd01::/64"},
+							},
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("invalid dual-stack configuration: %w", fmt.Errorf("reservation \"%s\" is not part of the pool CIDR", "storage")),
+		},
+		{
+			name: "dual-stack: not allowed to remove the dual-stack configuration",
+			op:   admissionv1.Update,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:             "prefix",
+							PoolCIDR:         "192.168.0.0/16",
+							AllocationPrefix: 24,
+						},
+					},
+				},
+			},
+			oldIPAMPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ipam-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:             "prefix",
+							PoolCIDR:         "192.168.0.0/16",
+							AllocationPrefix: 24,
+							DualStack: &kubermaticv1.IPAMPoolDualStackSettings{
+								PoolCIDR:         "fd00::/56",
+								AllocationPrefix: 64,
+							},
+						},
+					},
+				},
+			},
+			expectedError: errors.New("it's not allowed to remove the dual-stack configuration for a datacenter"),
+		},
+		{
+			name: "dual-stack: added prefix exclusion conflicts with allocation",
+			op:   admissionv1.Update,
+			ipamPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:             "prefix",
+							PoolCIDR:         "192.168.0.0/16",
+							AllocationPrefix: 24,
+							DualStack: &kubermaticv1.IPAMPoolDualStackSettings{
+								PoolCIDR:         "fd00::/56",
+								AllocationPrefix: 64,
+								ExcludePrefixes:  []kubermaticv1.SubnetCIDR{"FD00:0:0:1::/64"},
+							},
+						},
+					},
+				},
+			},
+			oldIPAMPool: &kubermaticv1.IPAMPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-pool",
+				},
+				Spec: kubermaticv1.IPAMPoolSpec{
+					Datacenters: map[string]kubermaticv1.IPAMPoolDatacenterSettings{
+						"dc": {
+							Type:             "prefix",
+							PoolCIDR:         "192.168.0.0/16",
+							AllocationPrefix: 24,
+							DualStack: &kubermaticv1.IPAMPoolDualStackSettings{
+								PoolCIDR:         "fd00::/56",
+								AllocationPrefix: 64,
+							},
+						},
+					},
+				},
+			},
+			objects: []ctrlruntimeclient.Object{
+				&kubermaticv1.IPAMAllocation{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-pool",
+						Namespace: "cluster-test",
+					},
+					Spec: kubermaticv1.IPAMAllocationSpec{
+						Type: "prefix",
+						DC:   "dc",
+						CIDR: "192.168.0.0/24",
+						DualStack: &kubermaticv1.IPAMAllocationDualStack{
+							CIDR: "fd00:0:0:1::/64",
+						},
+					},
+				},
+			},
+			expectedError: fmt.Errorf("failed to add exclusion: there is an conflicted allocation in IPAM pool \"%s\" and datacenter \"%s\"", "test-pool", "dc"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// machineDeploymentValidator for validating MachineDeployment CRD.
+type machineDeploymentValidator struct {
+	log             *zap.SugaredLogger
+	seedClient      ctrlruntimeclient.Client
+	subjectSelector labels.Selector
+}
+
+// NewMachineDeploymentValidator returns a new MachineDeployment validator.
+func NewMachineDeploymentValidator(seedClient ctrlruntimeclient.Client, log *zap.SugaredLogger, projectID string) (*machineDeploymentValidator, error) {
+	subjectSelector, err := resourceQuotaSubjectSelector(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &machineDeploymentValidator{
+		log:             log,
+		seedClient:      seedClient,
+		subjectSelector: subjectSelector,
+	}, nil
+}
+
+var _ admission.CustomValidator = &machineDeploymentValidator{}
+
+func (v *machineDeploymentValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	md, ok := obj.(*clusterv1alpha1.MachineDeployment)
+	if !ok {
+		return errors.New("object is not a MachineDeployment")
+	}
+
+	log := v.log.With("machinedeployment", md.Name)
+	log.Debug("validating create")
+
+	quota, err := getResourceQuota(ctx, v.seedClient, v.subjectSelector)
+	if err != nil {
+		return err
+	}
+	if quota != nil {
+		return validateMachineDeploymentQuota(log, quota)
+	}
+	return nil
+}
+
+// ValidateUpdate does not check anything, as updates never change the number of MachineDeployments.
+func (v *machineDeploymentValidator) ValidateUpdate(_ context.Context, _, _ runtime.Object) error {
+	return nil
+}
+
+func (v *machineDeploymentValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
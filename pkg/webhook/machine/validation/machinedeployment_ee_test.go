@@ -0,0 +1,124 @@
+//go:build ee
+
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(kubermaticv1.AddToScheme(testScheme))
+}
+
+func genProjectQuota(projectID string, quota *int64, used *int64) *kubermaticv1.ResourceQuota {
+	return &kubermaticv1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "project-" + projectID,
+			Labels: map[string]string{
+				kubermaticv1.ResourceQuotaSubjectNameLabelKey: projectID,
+				kubermaticv1.ResourceQuotaSubjectKindLabelKey: kubermaticv1.ProjectSubjectKind,
+			},
+		},
+		Spec: kubermaticv1.ResourceQuotaSpec{
+			Subject: kubermaticv1.Subject{
+				Name: projectID,
+				Kind: kubermaticv1.ProjectSubjectKind,
+			},
+			Quota: kubermaticv1.ResourceDetails{MachineDeployments: quota},
+		},
+		Status: kubermaticv1.ResourceQuotaStatus{
+			GlobalUsage: kubermaticv1.ResourceDetails{MachineDeployments: used},
+		},
+	}
+}
+
+func TestMachineDeploymentValidator(t *testing.T) {
+	testCases := []struct {
+		name        string
+		objects     []ctrlruntimeclient.Object
+		wantAllowed bool
+	}{
+		{
+			name:        "project without resource quota",
+			wantAllowed: true,
+		},
+		{
+			name:        "resource quota without machine deployment quota",
+			objects:     []ctrlruntimeclient.Object{genProjectQuota("abcd1234", nil, pointer.Int64(10))},
+			wantAllowed: true,
+		},
+		{
+			name:        "machine deployment quota not reached",
+			objects:     []ctrlruntimeclient.Object{genProjectQuota("abcd1234", pointer.Int64(3), pointer.Int64(2))},
+			wantAllowed: true,
+		},
+		{
+			name:        "machine deployment quota reached",
+			objects:     []ctrlruntimeclient.Object{genProjectQuota("abcd1234", pointer.Int64(3), pointer.Int64(3))},
+			wantAllowed: false,
+		},
+		{
+			name:        "machine deployment quota of other project reached",
+			objects:     []ctrlruntimeclient.Object{genProjectQuota("wxyz0987", pointer.Int64(3), pointer.Int64(3))},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			seedClient := ctrlruntimefakeclient.NewClientBuilder().WithScheme(testScheme).WithObjects(tc.objects...).Build()
+
+			v, err := NewMachineDeploymentValidator(seedClient, kubermaticlog.Logger, "abcd1234")
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+
+			md := &clusterv1alpha1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "md",
+					Namespace: metav1.NamespaceSystem,
+				},
+			}
+
+			err = v.ValidateCreate(context.Background(), md)
+			if allowed := err == nil; allowed != tc.wantAllowed {
+				t.Errorf("Expected allowed=%v, but got error %v", tc.wantAllowed, err)
+			}
+
+			// updates never change the number of MachineDeployments
+			if err := v.ValidateUpdate(context.Background(), md, md); err != nil {
+				t.Errorf("Expected update to be allowed, but got error %v", err)
+			}
+		})
+	}
+}
@@ -46,15 +46,10 @@ type validator struct {
 // NewValidator returns a new Machine validator.
 func NewValidator(seedClient, userClient ctrlruntimeclient.Client, log *zap.SugaredLogger, caBundle *certificates.CABundle,
 	projectID string) (*validator, error) {
-	subjectNameReq, err := labels.NewRequirement(kubermaticv1.ResourceQuotaSubjectNameLabelKey, selection.Equals, []string{projectID})
+	subjectSelector, err := resourceQuotaSubjectSelector(projectID)
 	if err != nil {
-		return nil, fmt.Errorf("error creating resource quota subject name requirement: %w", err)
+		return nil, err
 	}
-	subjectKindReq, err := labels.NewRequirement(kubermaticv1.ResourceQuotaSubjectKindLabelKey, selection.Equals, []string{kubermaticv1.ProjectSubjectKind})
-	if err != nil {
-		return nil, fmt.Errorf("error creating resource quota subject kind requirement: %w", err)
-	}
-	subjectSelector := labels.NewSelector().Add(*subjectNameReq, *subjectKindReq)
 
 	return &validator{
 		log:             log,
@@ -65,6 +60,20 @@ func NewValidator(seedClient, userClient ctrlruntimeclient.Client, log *zap.Suga
 	}, nil
 }
 
+// resourceQuotaSubjectSelector returns a selector for the ResourceQuota of the given project.
+func resourceQuotaSubjectSelector(projectID string) (labels.Selector, error) {
+	subjectNameReq, err := labels.NewRequirement(kubermaticv1.ResourceQuotaSubjectNameLabelKey, selection.Equals, []string{projectID})
+	if err != nil {
+		return nil, fmt.Errorf("error creating resource quota subject name requirement: %w", err)
+	}
+	subjectKindReq, err := labels.NewRequirement(kubermaticv1.ResourceQuotaSubjectKindLabelKey, selection.Equals, []string{kubermaticv1.ProjectSubjectKind})
+	if err != nil {
+		return nil, fmt.Errorf("error creating resource quota subject kind requirement: %w", err)
+	}
+
+	return labels.NewSelector().Add(*subjectNameReq, *subjectKindReq), nil
+}
+
 var _ admission.CustomValidator = &validator{}
 
 func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
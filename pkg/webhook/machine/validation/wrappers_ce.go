@@ -36,6 +36,10 @@ func validateQuota(_ context.Context, _ *zap.SugaredLogger, _ ctrlruntimeclient.
 	return nil
 }
 
+func validateMachineDeploymentQuota(_ *zap.SugaredLogger, _ *kubermaticv1.ResourceQuota) error {
+	return nil
+}
+
 // Resource Quotas are an EE feature
 func getResourceQuota(_ context.Context, _ ctrlruntimeclient.Client, _ labels.Selector) (*kubermaticv1.ResourceQuota, error) {
 	return nil, nil
@@ -38,6 +38,10 @@ func validateQuota(ctx context.Context, log *zap.SugaredLogger, userClient ctrlr
 	return eemachinevalidation.ValidateQuota(ctx, log, userClient, machine, caBundle, resourceQuota)
 }
 
+func validateMachineDeploymentQuota(log *zap.SugaredLogger, resourceQuota *kubermaticv1.ResourceQuota) error {
+	return eemachinevalidation.ValidateMachineDeploymentQuota(log, resourceQuota)
+}
+
 func getResourceQuota(ctx context.Context, seedClient ctrlruntimeclient.Client, subjectSelector labels.Selector) (*kubermaticv1.ResourceQuota, error) {
 	quotaList := &kubermaticv1.ResourceQuotaList{}
 	if err := seedClient.List(ctx, quotaList, &ctrlruntimeclient.ListOptions{
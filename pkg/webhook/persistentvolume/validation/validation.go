@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// validator for validating PersistentVolumes in user clusters against the persistent volume quota.
+type validator struct {
+	log         *zap.SugaredLogger
+	seedClient  ctrlruntimeclient.Reader
+	userClient  ctrlruntimeclient.Reader
+	clusterName string
+}
+
+// NewValidator returns a new PersistentVolume validator.
+func NewValidator(seedClient, userClient ctrlruntimeclient.Reader, log *zap.SugaredLogger, clusterName string) *validator {
+	return &validator{
+		log:         log,
+		seedClient:  seedClient,
+		userClient:  userClient,
+		clusterName: clusterName,
+	}
+}
+
+var _ admission.CustomValidator = &validator{}
+
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	pv, ok := obj.(*corev1.PersistentVolume)
+	if !ok {
+		return errors.New("object is not a PersistentVolume")
+	}
+
+	log := v.log.With("persistentvolume", pv.Name)
+	log.Debug("validating create")
+
+	return validatePersistentVolumeQuota(ctx, log, v.seedClient, v.userClient, v.clusterName)
+}
+
+// ValidateUpdate does not check anything, as updates never change the number of PersistentVolumes.
+func (v *validator) ValidateUpdate(_ context.Context, _, _ runtime.Object) error {
+	return nil
+}
+
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
@@ -0,0 +1,132 @@
+//go:build ee
+
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(kubermaticv1.AddToScheme(testScheme))
+}
+
+func genSeedObjects(quota, globalUsage, clusterUsage *int64) []ctrlruntimeclient.Object {
+	return []ctrlruntimeclient.Object{
+		&kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "testcluster",
+				Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: "abcd1234"},
+			},
+			Status: kubermaticv1.ClusterStatus{
+				ResourceUsage: &kubermaticv1.ResourceDetails{PersistentVolumes: clusterUsage},
+			},
+		},
+		&kubermaticv1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "project-abcd1234",
+				Labels: map[string]string{
+					kubermaticv1.ResourceQuotaSubjectNameLabelKey: "abcd1234",
+					kubermaticv1.ResourceQuotaSubjectKindLabelKey: kubermaticv1.ProjectSubjectKind,
+				},
+			},
+			Spec: kubermaticv1.ResourceQuotaSpec{
+				Subject: kubermaticv1.Subject{
+					Name: "abcd1234",
+					Kind: kubermaticv1.ProjectSubjectKind,
+				},
+				Quota: kubermaticv1.ResourceDetails{PersistentVolumes: quota},
+			},
+			Status: kubermaticv1.ResourceQuotaStatus{
+				GlobalUsage: kubermaticv1.ResourceDetails{PersistentVolumes: globalUsage},
+			},
+		},
+	}
+}
+
+func genPersistentVolume(name string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+func TestValidateCreate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		seedObjects []ctrlruntimeclient.Object
+		userObjects []ctrlruntimeclient.Object
+		wantAllowed bool
+	}{
+		{
+			name:        "project without persistent volume quota",
+			seedObjects: genSeedObjects(nil, pointer.Int64(10), pointer.Int64(10)),
+			wantAllowed: true,
+		},
+		{
+			name:        "persistent volume quota not reached",
+			seedObjects: genSeedObjects(pointer.Int64(2), pointer.Int64(1), pointer.Int64(1)),
+			userObjects: []ctrlruntimeclient.Object{genPersistentVolume("pv1")},
+			wantAllowed: true,
+		},
+		{
+			name:        "persistent volume quota reached",
+			seedObjects: genSeedObjects(pointer.Int64(2), pointer.Int64(2), pointer.Int64(1)),
+			userObjects: []ctrlruntimeclient.Object{genPersistentVolume("pv1")},
+			wantAllowed: false,
+		},
+		{
+			name:        "persistent volume quota reached by volumes that are not in the usage yet",
+			seedObjects: genSeedObjects(pointer.Int64(2), pointer.Int64(0), pointer.Int64(0)),
+			userObjects: []ctrlruntimeclient.Object{genPersistentVolume("pv1"), genPersistentVolume("pv2")},
+			wantAllowed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewValidator(
+				ctrlruntimefakeclient.NewClientBuilder().WithScheme(testScheme).WithObjects(tc.seedObjects...).Build(),
+				ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.userObjects...).Build(),
+				kubermaticlog.Logger,
+				"testcluster",
+			)
+
+			err := v.ValidateCreate(context.Background(), genPersistentVolume("test"))
+			if allowed := err == nil; allowed != tc.wantAllowed {
+				t.Errorf("Expected allowed=%v, but got error %v", tc.wantAllowed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// validator for validating Services in user clusters against the load balancer quota.
+type validator struct {
+	log         *zap.SugaredLogger
+	seedClient  ctrlruntimeclient.Reader
+	userClient  ctrlruntimeclient.Reader
+	clusterName string
+}
+
+// NewValidator returns a new Service validator.
+func NewValidator(seedClient, userClient ctrlruntimeclient.Reader, log *zap.SugaredLogger, clusterName string) *validator {
+	return &validator{
+		log:         log,
+		seedClient:  seedClient,
+		userClient:  userClient,
+		clusterName: clusterName,
+	}
+}
+
+var _ admission.CustomValidator = &validator{}
+
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	service, ok := obj.(*corev1.Service)
+	if !ok {
+		return errors.New("object is not a Service")
+	}
+
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+
+	log := v.log.With("service", ctrlruntimeclient.ObjectKeyFromObject(service))
+	log.Debug("validating create")
+
+	return validateLoadBalancerQuota(ctx, log, v.seedClient, v.userClient, v.clusterName)
+}
+
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldService, ok := oldObj.(*corev1.Service)
+	if !ok {
+		return errors.New("old object is not a Service")
+	}
+
+	newService, ok := newObj.(*corev1.Service)
+	if !ok {
+		return errors.New("new object is not a Service")
+	}
+
+	// only changing the type to LoadBalancer creates a new load balancer
+	if newService.Spec.Type != corev1.ServiceTypeLoadBalancer || oldService.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+
+	log := v.log.With("service", ctrlruntimeclient.ObjectKeyFromObject(newService))
+	log.Debug("validating update")
+
+	return validateLoadBalancerQuota(ctx, log, v.seedClient, v.userClient, v.clusterName)
+}
+
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build ee
+
+/*
+Copyright 2023 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/ee/validation/resourcequota"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func validateLoadBalancerQuota(ctx context.Context, log *zap.SugaredLogger, seedClient, userClient ctrlruntimeclient.Reader, clusterName string) error {
+	services := &corev1.ServiceList{}
+	if err := userClient.List(ctx, services); err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	var loadBalancers int64
+	for _, service := range services.Items {
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			loadBalancers++
+		}
+	}
+
+	if err := resourcequota.ValidateUserClusterCount(ctx, seedClient, clusterName, resourcequota.LoadBalancers, loadBalancers); err != nil {
+		log.Debugw("requested load balancer would exceed current quota", zap.Error(err))
+		return err
+	}
+
+	return nil
+}